@@ -21,31 +21,36 @@ package appconfig
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"strings"
 
 	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/contents"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
+const gistRemotePrefix = "gist:"
+
 // RemoteRefParser attempts to parse a RemoteRef from bytes. The parser should
 // return nil with a nil error if b does not encode a RemoteRef and nil with a
 // non-nil error if b encodes an invalid RemoteRef.
 type RemoteRefParser func(path string, b []byte) (*RemoteRef, error)
 
-// RemoteRef identifies a configuration file in a different repository.
+// RemoteRef identifies a configuration file in a different repository or in
+// a gist.
 type RemoteRef struct {
-	// The repository in "owner/name" format. Required.
+	// The repository in "owner/name" format or the gist file in
+	// "gist:<id>/<file>" format. Required.
 	Remote string `yaml:"remote" json:"remote"`
 
 	// The path to the config file in the repository. If empty, use the first
-	// path configured in the loader.
+	// path configured in the loader. Ignored for gists.
 	Path string `yaml:"path" json:"path"`
 
 	// The reference (branch, tag, or SHA) to read in the repository. If empty,
-	// use the default branch of the repository.
+	// use the default branch of the repository. For gists, the SHA of a
+	// revision; if empty, use the latest revision.
 	Ref string `yaml:"ref" json:"ref"`
 }
 
@@ -57,6 +62,21 @@ func (r RemoteRef) SplitRemote() (owner, repo string, err error) {
 	return r.Remote[:slash], r.Remote[slash+1:], nil
 }
 
+// IsGist returns true if the remote is a file in a gist.
+func (r RemoteRef) IsGist() bool {
+	return strings.HasPrefix(r.Remote, gistRemotePrefix)
+}
+
+// SplitGist returns the gist ID and file name of a gist remote.
+func (r RemoteRef) SplitGist() (id, file string, err error) {
+	gist, ok := strings.CutPrefix(r.Remote, gistRemotePrefix)
+	slash := strings.IndexByte(gist, '/')
+	if !ok || slash <= 0 || slash >= len(gist)-1 {
+		return "", "", errors.Errorf("invalid gist remote value: %s", r.Remote)
+	}
+	return gist[:slash], gist[slash+1:], nil
+}
+
 // Config contains unparsed configuration data and metadata about where it was found.
 type Config struct {
 	Content []byte
@@ -66,6 +86,10 @@ type Config struct {
 	Source   string
 	Path     string
 	IsRemote bool
+
+	// Version is the version of the content before it was migrated. It is
+	// only set by loaders configured with WithMigrations.
+	Version int
 }
 
 // IsUndefined returns true if the Config's content is empty and there is no
@@ -79,8 +103,16 @@ type Loader struct {
 	paths []string
 
 	parser       RemoteRefParser
+	gists        bool
+	gistOwners   []string
 	defaultRepo  string
 	defaultPaths []string
+	resolver     Resolver
+	decrypter    Decrypter
+	listMerge    ListMergeMode
+
+	migrations     map[int]MigrationFunc
+	currentVersion int
 }
 
 // NewLoader creates a Loader that loads configuration from paths.
@@ -113,6 +145,69 @@ func NewLoader(paths []string, opts ...Option) *Loader {
 // If error is non-nil, the Source and Path fields of the returned Config tell
 // which file LoadConfig was processing when it encountered the error.
 func (ld *Loader) LoadConfig(ctx context.Context, client *github.Client, owner, repo, ref string) (Config, error) {
+	c, err := ld.loadConfig(ctx, client, owner, repo, ref)
+	if err != nil {
+		return c, err
+	}
+	return ld.processConfig(ctx, owner, repo, c)
+}
+
+// processConfig interpolates, decrypts, and migrates the content of a config
+// loaded for the repository owner/repo
+func (ld *Loader) processConfig(ctx context.Context, owner, repo string, c Config) (Config, error) {
+	if len(c.Content) == 0 {
+		return c, nil
+	}
+
+	if ld.resolver != nil {
+		content, err := interpolate(ctx, c.Content, ld.resolver)
+		if err != nil {
+			return c, err
+		}
+		c.Content = content
+	}
+
+	if ld.decrypter != nil {
+		content, err := ld.decrypter(ctx, owner, repo, c)
+		if err != nil {
+			return c, errors.Wrap(err, "failed to decrypt configuration")
+		}
+		c.Content = content
+	}
+
+	if ld.migrations != nil {
+		content, version, err := ld.migrate(ctx, c.Content)
+		c.Version = version
+		if err != nil {
+			return c, err
+		}
+		c.Content = content
+	}
+
+	return c, nil
+}
+
+func (ld *Loader) loadConfig(ctx context.Context, client *github.Client, owner, repo, ref string) (Config, error) {
+	c, err := ld.loadRepositoryConfig(ctx, client, owner, repo, ref)
+	if err != nil || !c.IsUndefined() {
+		return c, err
+	}
+
+	// if the repository defined no configuration and org defaults are enabled,
+	// try falling back to the defaults
+	if ld.hasOwnerDefault() {
+		return ld.loadDefaultConfig(ctx, client, owner)
+	}
+
+	// couldn't find configuration anyhere, so return an empty/undefined one
+	return Config{}, nil
+}
+
+func (ld *Loader) hasOwnerDefault() bool {
+	return ld.defaultRepo != "" && len(ld.defaultPaths) > 0
+}
+
+func (ld *Loader) loadRepositoryConfig(ctx context.Context, client *github.Client, owner, repo, ref string) (Config, error) {
 	logger := zerolog.Ctx(ctx)
 
 	c := Config{
@@ -123,7 +218,7 @@ func (ld *Loader) LoadConfig(ctx context.Context, client *github.Client, owner,
 		c.Path = p
 
 		logger.Debug().Msgf("Trying configuration at %s in %s", c.Path, c.Source)
-		content, exists, err := getFileContents(ctx, client, owner, repo, ref, p)
+		content, exists, err := contents.GetFile(ctx, client, owner, repo, ref, p)
 		if err != nil {
 			return c, err
 		}
@@ -149,17 +244,15 @@ func (ld *Loader) LoadConfig(ctx context.Context, client *github.Client, owner,
 		return c, nil
 	}
 
-	// if the repository defined no configuration and org defaults are enabled,
-	// try falling back to the defaults
-	if ld.defaultRepo != "" && len(ld.defaultPaths) > 0 {
-		return ld.loadDefaultConfig(ctx, client, owner)
-	}
-
-	// couldn't find configuration anyhere, so return an empty/undefined one
+	// the repository defined no configuration
 	return Config{}, nil
 }
 
 func (ld *Loader) loadRemoteConfig(ctx context.Context, client *github.Client, remote RemoteRef, c Config) (Config, error) {
+	if remote.IsGist() {
+		return ld.loadGistConfig(ctx, client, remote, c)
+	}
+
 	logger := zerolog.Ctx(ctx)
 	notFoundErr := fmt.Errorf("invalid remote reference: file does not exist")
 
@@ -196,7 +289,7 @@ func (ld *Loader) loadRemoteConfig(ctx context.Context, client *github.Client, r
 	c.Source = fmt.Sprintf("%s@%s", c.Source, ref)
 
 	logger.Debug().Msgf("Trying remote configuration at %s in %s", c.Path, c.Source)
-	content, exists, err := getFileContents(ctx, client, owner, repo, ref, c.Path)
+	content, exists, err := contents.GetFile(ctx, client, owner, repo, ref, c.Path)
 	if err != nil {
 		return c, err
 	}
@@ -212,6 +305,71 @@ func (ld *Loader) loadRemoteConfig(ctx context.Context, client *github.Client, r
 	return c, nil
 }
 
+func (ld *Loader) loadGistConfig(ctx context.Context, client *github.Client, remote RemoteRef, c Config) (Config, error) {
+	logger := zerolog.Ctx(ctx)
+	notFoundErr := fmt.Errorf("invalid remote reference: gist file does not exist")
+
+	if !ld.gists {
+		return c, errors.New("invalid remote reference: gist remotes are not enabled")
+	}
+
+	id, file, err := remote.SplitGist()
+	if err != nil {
+		return c, err
+	}
+
+	// After this point, all errors will be about the gist, not the local
+	// file containing the reference.
+	c.Source = gistRemotePrefix + id
+	c.Path = file
+	c.IsRemote = true
+
+	var gist *github.Gist
+	if remote.Ref != "" {
+		c.Source = fmt.Sprintf("%s@%s", c.Source, remote.Ref)
+		gist, _, err = client.Gists.GetRevision(ctx, id, remote.Ref)
+	} else {
+		gist, _, err = client.Gists.Get(ctx, id)
+	}
+	if err != nil {
+		if isNotFound(err) {
+			return c, notFoundErr
+		}
+		return c, errors.Wrap(err, "failed to get remote gist")
+	}
+	if !ld.isAllowedGistOwner(gist.GetOwner().GetLogin()) {
+		return c, errors.Errorf("invalid remote reference: gist owner %q is not allowed", gist.GetOwner().GetLogin())
+	}
+
+	logger.Debug().Msgf("Trying remote configuration at %s in %s", c.Path, c.Source)
+	f, ok := gist.Files[github.GistFilename(file)]
+	if !ok {
+		return c, notFoundErr
+	}
+
+	// the API truncates the content of files larger than 1MB, which is too
+	// large for configuration anyway
+	content := f.GetContent()
+	if f.GetSize() > len(content) {
+		return c, errors.New("invalid remote reference: gist file is too large")
+	}
+
+	c.Content = []byte(content)
+	return c, nil
+}
+
+func (ld *Loader) isAllowedGistOwner(owner string) bool {
+	if len(ld.gistOwners) == 0 {
+		return true
+	}
+	for _, o := range ld.gistOwners {
+		if owner != "" && strings.EqualFold(o, owner) {
+			return true
+		}
+	}
+	return false
+}
+
 func (ld *Loader) loadDefaultConfig(ctx context.Context, client *github.Client, owner string) (Config, error) {
 	logger := zerolog.Ctx(ctx)
 
@@ -234,7 +392,7 @@ func (ld *Loader) loadDefaultConfig(ctx context.Context, client *github.Client,
 		c.Path = p
 
 		logger.Debug().Msgf("Trying default configuration at %s in %s", c.Path, c.Source)
-		content, exists, err := getFileContents(ctx, client, owner, r.GetName(), ref, p)
+		content, exists, err := contents.GetFile(ctx, client, owner, r.GetName(), ref, p)
 		if err != nil {
 			return c, err
 		}
@@ -264,75 +422,9 @@ func (ld *Loader) loadDefaultConfig(ctx context.Context, client *github.Client,
 	return Config{}, nil
 }
 
-// getFileContents returns the content of the file at path on ref in owner/repo
-// if it exists. Returns an empty slice and false if the file does not exist.
-func getFileContents(ctx context.Context, client *github.Client, owner, repo, ref, path string) ([]byte, bool, error) {
-	file, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{
-		Ref: ref,
-	})
-	if err != nil {
-		switch {
-		case isNotFound(err):
-			return nil, false, nil
-		case isTooLargeError(err):
-			b, err := getLargeFileContents(ctx, client, owner, repo, ref, path)
-			return b, true, err
-		}
-		return nil, false, errors.Wrap(err, "failed to read file")
-	}
-
-	// file will be nil if the path exists but is a directory
-	if file == nil {
-		return nil, false, nil
-	}
-
-	content, err := file.GetContent()
-	if err != nil {
-		return nil, true, errors.Wrap(err, "failed to decode file content")
-	}
-
-	return []byte(content), true, nil
-}
-
-// getLargeFileContents is similar to getFileContents, but works for files up
-// to 100MB. Unlike getFileContents, it returns an error if the file does not
-// exist.
-func getLargeFileContents(ctx context.Context, client *github.Client, owner, repo, ref, path string) ([]byte, error) {
-	body, res, err := client.Repositories.DownloadContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{
-		Ref: ref,
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read file")
-	}
-	defer func() {
-		_ = body.Close()
-	}()
-
-	if res.StatusCode != http.StatusOK {
-		return nil, errors.Errorf("failed to read file: unexpected status code %d", res.StatusCode)
-	}
-
-	b, err := ioutil.ReadAll(body)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read file")
-	}
-	return b, nil
-}
-
 func isNotFound(err error) bool {
 	if rerr, ok := err.(*github.ErrorResponse); ok {
 		return rerr.Response.StatusCode == http.StatusNotFound
 	}
 	return false
 }
-
-func isTooLargeError(err error) bool {
-	if rerr, ok := err.(*github.ErrorResponse); ok {
-		for _, err := range rerr.Errors {
-			if err.Code == "too_large" {
-				return true
-			}
-		}
-	}
-	return false
-}
@@ -84,6 +84,45 @@ func TestLoadConfig(t *testing.T) {
 				IsRemote: true,
 			},
 		},
+		"gistReference": {
+			Paths:   []string{".github/test-app.yml"},
+			Options: []Option{WithGistRemotes()},
+			Repo:    "gist-ref",
+			Expected: Config{
+				Content:  []byte("message: hello\n"),
+				Source:   "gist:abc123",
+				Path:     "test-app.yml",
+				IsRemote: true,
+			},
+		},
+		"gistRevisionReference": {
+			Paths:   []string{".github/test-app.yml"},
+			Options: []Option{WithGistRemotes("other", "Test")},
+			Repo:    "gist-revision-ref",
+			Expected: Config{
+				Content:  []byte("message: hello\n"),
+				Source:   "gist:abc123@0a1b2c",
+				Path:     "test-app.yml",
+				IsRemote: true,
+			},
+		},
+		"gistReferenceMissingFile": {
+			Paths:   []string{".github/test-app.yml"},
+			Options: []Option{WithGistRemotes()},
+			Repo:    "gist-missing-file-ref",
+			Error:   true,
+		},
+		"gistReferenceDisabled": {
+			Paths: []string{".github/test-app.yml"},
+			Repo:  "gist-ref",
+			Error: true,
+		},
+		"gistReferenceOwnerNotAllowed": {
+			Paths:   []string{".github/test-app.yml"},
+			Options: []Option{WithGistRemotes("other")},
+			Repo:    "gist-ref",
+			Error:   true,
+		},
 		"defaultConfig": {
 			Paths: []string{".github/test-app.yml"},
 			Repo:  "default-config",
@@ -157,10 +196,20 @@ func makeTestClient() *github.Client {
 		"/repos/remote/config/contents/config/test-app.yml":                  "config-contents.yml",
 		"/repos/remote/config": "remote-config.yml",
 
+		"/repos/test/gist-ref/contents/.github/test-app.yml":              "gist-ref-contents.yml",
+		"/repos/test/gist-revision-ref/contents/.github/test-app.yml":     "gist-revision-ref-contents.yml",
+		"/repos/test/gist-missing-file-ref/contents/.github/test-app.yml": "gist-missing-file-ref-contents.yml",
+		"/gists/abc123":        "gist.yml",
+		"/gists/abc123/0a1b2c": "gist.yml",
+
 		"/repos/test/default-config/contents/.github/test-app.yml": "404.yml",
 		"/repos/test/.github":                       "dot-github.yml",
 		"/repos/test/.github/contents/test-app.yml": "dot-github-contents.yml",
 
+		"/repos/test/merge-config/contents/.github/merge-app.yml":   "merge-config-contents.yml",
+		"/repos/test/default-config/contents/.github/merge-app.yml": "404.yml",
+		"/repos/test/.github/contents/merge-app.yml":                "dot-github-merge-contents.yml",
+
 		"/repos/test/default-config-remote-ref/contents/.github-remote/test-app.yml": "404.yml",
 		"/repos/test/.github-remote":               "remote-config.yml",
 		"/repos/test/config/contents/test-app.yml": "remote-ref-contents.yml",
@@ -0,0 +1,80 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// Decrypter decrypts the content of a loaded configuration file for the
+// repository owner/repo. It receives the loaded Config, so it can use the
+// source or path to select keys, and returns the decrypted content. Use it
+// to integrate tools that encrypt entire files, like sops.
+type Decrypter func(ctx context.Context, owner, repo string, c Config) ([]byte, error)
+
+// DecryptFunc decrypts a single sealed value in the configuration c loaded
+// for the repository owner/repo. Implementations should bind ciphertext to
+// the repository, for example by passing the repository name as additional
+// authenticated data or as a KMS encryption context, so that a value sealed
+// for one repository cannot be copied into another repository's
+// configuration and decrypted there.
+type DecryptFunc func(ctx context.Context, owner, repo string, c Config, ciphertext []byte) ([]byte, error)
+
+var sealedValuePattern = regexp.MustCompile(`ENC\[([A-Za-z0-9+/=]+)\]`)
+
+// SealedValueDecrypter returns a Decrypter that replaces sealed values in
+// content with their plaintext. A sealed value is base64-encoded ciphertext
+// wrapped in "ENC[" and "]", like "ENC[AQICAHh...]". Each value is decrypted
+// with decrypt, which usually calls a key management service.
+//
+// Plaintext is inserted without modification, so values must decrypt to
+// text that is valid where the sealed value appears. In YAML, quote sealed
+// values and avoid quotes and newlines in the plaintext.
+func SealedValueDecrypter(decrypt DecryptFunc) Decrypter {
+	return func(ctx context.Context, owner, repo string, c Config) ([]byte, error) {
+		matches := sealedValuePattern.FindAllSubmatchIndex(c.Content, -1)
+		if len(matches) == 0 {
+			return c.Content, nil
+		}
+
+		var out bytes.Buffer
+		out.Grow(len(c.Content))
+
+		last := 0
+		for _, m := range matches {
+			ciphertext, err := base64.StdEncoding.DecodeString(string(c.Content[m[2]:m[3]]))
+			if err != nil {
+				return nil, errors.Wrap(err, "invalid sealed value")
+			}
+
+			plaintext, err := decrypt(ctx, owner, repo, c, ciphertext)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to decrypt sealed value")
+			}
+
+			out.Write(c.Content[last:m[0]])
+			out.Write(plaintext)
+			last = m[1]
+		}
+		out.Write(c.Content[last:])
+
+		return out.Bytes(), nil
+	}
+}
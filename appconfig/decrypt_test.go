@@ -0,0 +1,101 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestSealedValueDecrypter(t *testing.T) {
+	// the test "cipher" reverses the plaintext and fails for "bad" or for
+	// other repositories
+	decrypt := func(ctx context.Context, owner, repo string, c Config, ciphertext []byte) ([]byte, error) {
+		if string(ciphertext) == "bad" {
+			return nil, errors.New("decryption failed")
+		}
+		if owner+"/"+repo != "octo/app" || c.Path != ".github/app.yml" {
+			return nil, errors.New("value sealed for a different repository")
+		}
+		out := make([]byte, len(ciphertext))
+		for i, b := range ciphertext {
+			out[len(out)-1-i] = b
+		}
+		return out, nil
+	}
+	seal := func(s string) string {
+		return "ENC[" + base64.StdEncoding.EncodeToString([]byte(s)) + "]"
+	}
+
+	tests := map[string]struct {
+		Repo     string
+		Content  string
+		Expected string
+		Error    bool
+	}{
+		"noSealedValues": {
+			Repo:     "app",
+			Content:  "message: hello\n",
+			Expected: "message: hello\n",
+		},
+		"sealedValues": {
+			Repo:     "app",
+			Content:  "token: \"" + seal("terces") + "\"\nkey: " + seal("yek") + "\n",
+			Expected: "token: \"secret\"\nkey: key\n",
+		},
+		"invalidEncoding": {
+			Repo:     "app",
+			Content:  "token: ENC[!!!]\n",
+			Expected: "token: ENC[!!!]\n",
+		},
+		"invalidBase64": {
+			Repo:    "app",
+			Content: "token: ENC[abc]\n",
+			Error:   true,
+		},
+		"otherRepository": {
+			Repo:    "other",
+			Content: "token: " + seal("terces") + "\n",
+			Error:   true,
+		},
+		"decryptionError": {
+			Repo:    "app",
+			Content: "token: " + seal("bad") + "\n",
+			Error:   true,
+		},
+	}
+
+	decrypter := SealedValueDecrypter(decrypt)
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := decrypter(context.Background(), "octo", test.Repo, Config{Content: []byte(test.Content), Path: ".github/app.yml"})
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(out, []byte(test.Expected)) {
+				t.Errorf("incorrect content\nexpected: %q\n  actual: %q", test.Expected, string(out))
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultDiscoveryInterval is the default time between requests when
+	// discovering configuration by listing directories.
+	DefaultDiscoveryInterval = 100 * time.Millisecond
+
+	// DefaultDiscoverySearchInterval is the default time between requests
+	// when discovering configuration with code search, which allows fewer
+	// requests per minute than other APIs.
+	DefaultDiscoverySearchInterval = 6 * time.Second
+)
+
+// DiscoveredConfig is a repository that contains configuration.
+type DiscoveredConfig struct {
+	Repository *github.Repository
+
+	// Path is the first path of the loader that exists in the repository.
+	Path string
+}
+
+// DiscoveryOption configures how a Loader discovers configuration.
+type DiscoveryOption func(*discovery)
+
+// WithDiscoverySearch discovers configuration with code search instead of
+// listing directories in each repository. Search uses a few requests for the
+// whole installation instead of one or more per repository, but the search
+// index may be out of date and does not include some repositories, like
+// forks.
+func WithDiscoverySearch() DiscoveryOption {
+	return func(d *discovery) {
+		d.search = true
+	}
+}
+
+// WithDiscoveryInterval sets the time between requests. The default is
+// DefaultDiscoveryInterval, or DefaultDiscoverySearchInterval when using
+// code search. A negative interval disables pacing.
+func WithDiscoveryInterval(interval time.Duration) DiscoveryOption {
+	return func(d *discovery) {
+		d.interval = &interval
+	}
+}
+
+type discovery struct {
+	search   bool
+	interval *time.Duration
+}
+
+// DiscoverRepositories returns the repositories of an installation that
+// contain configuration at one of the loader's paths on their default branch,
+// in the order the installation lists them. The client must be an
+// installation client. Use it to find repositories for dashboards or to limit
+// a backfill to repositories that use the application.
+//
+// Repositories that only use the owner's default configuration are not
+// included. Files are not loaded, so a file with a remote reference counts as
+// configuration even if the reference is invalid.
+func (ld *Loader) DiscoverRepositories(ctx context.Context, client *github.Client, opts ...DiscoveryOption) ([]DiscoveredConfig, error) {
+	var d discovery
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	interval := DefaultDiscoveryInterval
+	if d.search {
+		interval = DefaultDiscoverySearchInterval
+	}
+	if d.interval != nil {
+		interval = *d.interval
+	}
+	pace := newPacer(interval)
+
+	var repos []*github.Repository
+	listOpts := &github.ListOptions{PerPage: 100}
+	for {
+		if err := pace(ctx); err != nil {
+			return nil, err
+		}
+		res, resp, err := client.Apps.ListRepos(ctx, listOpts)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list installation repositories")
+		}
+		repos = append(repos, res.Repositories...)
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+
+	var paths map[int64]string
+	var err error
+	if d.search {
+		paths, err = ld.searchConfigPaths(ctx, client, repos, pace)
+	} else {
+		paths, err = ld.listConfigPaths(ctx, client, repos, pace)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var discovered []DiscoveredConfig
+	for _, r := range repos {
+		if p, ok := paths[r.GetID()]; ok {
+			discovered = append(discovered, DiscoveredConfig{Repository: r, Path: p})
+		}
+	}
+	return discovered, nil
+}
+
+// listConfigPaths lists the directories containing the loader's paths in
+// each repository and returns the first path found for each repository ID
+func (ld *Loader) listConfigPaths(ctx context.Context, client *github.Client, repos []*github.Repository, pace func(context.Context) error) (map[int64]string, error) {
+	var dirs []string
+	for _, p := range ld.paths {
+		dir := configDir(p)
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	paths := make(map[int64]string)
+	for _, r := range repos {
+		owner, name := r.GetOwner().GetLogin(), r.GetName()
+
+		files := make(map[string]bool)
+		for _, dir := range dirs {
+			if err := pace(ctx); err != nil {
+				return nil, err
+			}
+			_, entries, _, err := client.Repositories.GetContents(ctx, owner, name, dir, nil)
+			if err != nil {
+				if isNotFound(err) {
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to list %q in %s/%s", dir, owner, name)
+			}
+			for _, e := range entries {
+				if e.GetType() == "file" {
+					files[e.GetPath()] = true
+				}
+			}
+		}
+
+		for _, p := range ld.paths {
+			if files[p] {
+				zerolog.Ctx(ctx).Debug().Msgf("Discovered configuration at %s in %s/%s", p, owner, name)
+				paths[r.GetID()] = p
+				break
+			}
+		}
+	}
+	return paths, nil
+}
+
+// searchConfigPaths searches for the loader's paths in the repositories of
+// each owner and returns the first path found for each repository ID
+func (ld *Loader) searchConfigPaths(ctx context.Context, client *github.Client, repos []*github.Repository, pace func(context.Context) error) (map[int64]string, error) {
+	var owners []string
+	for _, r := range repos {
+		if owner := r.GetOwner().GetLogin(); !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+
+	// the index of the first path found in each repository
+	found := make(map[int64]int)
+	for _, owner := range owners {
+		for i, p := range ld.paths {
+			query := fmt.Sprintf("filename:%s user:%s", path.Base(p), owner)
+			if dir := configDir(p); dir != "" {
+				query += fmt.Sprintf(" path:%s", dir)
+			}
+
+			opts := &github.SearchOptions{ListOptions: github.ListOptions{PerPage: 100}}
+			for {
+				if err := pace(ctx); err != nil {
+					return nil, err
+				}
+				res, resp, err := client.Search.Code(ctx, query, opts)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to search for %s in repositories of %s", p, owner)
+				}
+				for _, result := range res.CodeResults {
+					if result.GetPath() != p {
+						continue
+					}
+					id := result.GetRepository().GetID()
+					if j, ok := found[id]; !ok || i < j {
+						found[id] = i
+					}
+				}
+				if resp.NextPage == 0 {
+					break
+				}
+				opts.Page = resp.NextPage
+			}
+		}
+	}
+
+	paths := make(map[int64]string, len(found))
+	for id, i := range found {
+		paths[id] = ld.paths[i]
+	}
+	return paths, nil
+}
+
+// newPacer returns a function that waits for interval on every call except
+// the first
+func newPacer(interval time.Duration) func(context.Context) error {
+	first := true
+	return func(ctx context.Context) error {
+		if first || interval <= 0 {
+			first = false
+			return ctx.Err()
+		}
+		timer := githubapp.GetClock(ctx).NewTimer(interval)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C():
+			return nil
+		}
+	}
+}
+
+func configDir(p string) string {
+	if dir := path.Dir(p); dir != "." {
+		return dir
+	}
+	return ""
+}
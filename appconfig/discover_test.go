@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+type muxTransport struct {
+	mux *http.ServeMux
+}
+
+func (t muxTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	w := httptest.NewRecorder()
+	t.mux.ServeHTTP(w, r)
+	return w.Result(), nil
+}
+
+func TestDiscoverRepositories(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /installation/repositories", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"total_count": 3, "repositories": [
+			{"id": 1, "name": "configured", "owner": {"login": "test"}},
+			{"id": 2, "name": "unconfigured", "owner": {"login": "test"}},
+			{"id": 3, "name": "fallback", "owner": {"login": "test"}}
+		]}`)
+	})
+	mux.HandleFunc("GET /repos/test/configured/contents/.github", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[{"type": "file", "path": ".github/test-app.yml"}, {"type": "file", "path": ".github/CODEOWNERS"}]`)
+	})
+	mux.HandleFunc("GET /repos/test/unconfigured/contents/.github", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[{"type": "dir", "path": ".github/test-app.yml"}]`)
+	})
+	mux.HandleFunc("GET /repos/test/fallback/contents/.github", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /repos/test/fallback/contents/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[{"type": "file", "path": "test-app.yml"}]`)
+	})
+	mux.HandleFunc("GET /repos/test/{repo}/contents/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[]`)
+	})
+	mux.HandleFunc("GET /search/code", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("q") {
+		case "filename:test-app.yml user:test path:.github":
+			_, _ = io.WriteString(w, `{"total_count": 2, "items": [
+				{"path": ".github/test-app.yml", "repository": {"id": 1}},
+				{"path": "docs/.github/test-app.yml", "repository": {"id": 2}}
+			]}`)
+		case "filename:test-app.yml user:test":
+			_, _ = io.WriteString(w, `{"total_count": 2, "items": [
+				{"path": "test-app.yml", "repository": {"id": 3}},
+				{"path": "test-app.yml", "repository": {"id": 1}}
+			]}`)
+		default:
+			t.Errorf("unexpected query: %s", r.URL.Query().Get("q"))
+		}
+	})
+
+	client := github.NewClient(&http.Client{Transport: muxTransport{mux: mux}})
+	ld := NewLoader([]string{".github/test-app.yml", "test-app.yml"})
+
+	tests := map[string]struct {
+		Options []DiscoveryOption
+	}{
+		"listing": {
+			Options: []DiscoveryOption{WithDiscoveryInterval(-1)},
+		},
+		"search": {
+			Options: []DiscoveryOption{WithDiscoverySearch(), WithDiscoveryInterval(-1)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			discovered, err := ld.DiscoverRepositories(context.Background(), client, test.Options...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var actual []string
+			for _, d := range discovered {
+				actual = append(actual, d.Repository.GetName()+":"+d.Path)
+			}
+			if fmt.Sprint(actual) != "[configured:.github/test-app.yml fallback:test-app.yml]" {
+				t.Errorf("incorrect discovered repositories: %v", actual)
+			}
+		})
+	}
+}
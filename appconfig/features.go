@@ -0,0 +1,208 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/palantir/go-githubapp/githubapp"
+	ttlcache "github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DefaultFeatureCacheTTL = time.Minute
+)
+
+// FeatureParser extracts feature flags from a configuration file. It returns
+// a map from feature names to their enabled state. Features missing from the
+// map use the gate's default state.
+type FeatureParser func(c Config) (map[string]bool, error)
+
+// YAMLFeatureParser parses feature flags from the "features" key of a
+// YAML-encoded configuration file:
+//
+//	features:
+//	  auto-merge: true
+//	  labeler: false
+func YAMLFeatureParser(c Config) (map[string]bool, error) {
+	var config struct {
+		Features map[string]bool `yaml:"features"`
+	}
+	if err := yaml.Unmarshal(c.Content, &config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse features in %s", c.Path)
+	}
+	return config.Features, nil
+}
+
+// FeatureGateOption configures properties of a feature gate.
+type FeatureGateOption func(*FeatureGate)
+
+// WithFeatureParser sets the parser for feature flags. The default parser is
+// YAMLFeatureParser.
+func WithFeatureParser(parser FeatureParser) FeatureGateOption {
+	return func(g *FeatureGate) {
+		if parser != nil {
+			g.parser = parser
+		}
+	}
+}
+
+// WithFeatureCacheTTL sets how long the gate caches the feature flags of a
+// repository. The default is DefaultFeatureCacheTTL.
+func WithFeatureCacheTTL(ttl time.Duration) FeatureGateOption {
+	return func(g *FeatureGate) {
+		if ttl > 0 {
+			g.ttl = ttl
+		}
+	}
+}
+
+// WithFeatureDefault sets the state of features that a repository does not
+// configure. By default, unconfigured features are disabled.
+func WithFeatureDefault(enabled bool) FeatureGateOption {
+	return func(g *FeatureGate) {
+		g.enabled = enabled
+	}
+}
+
+// FeatureGate decides if features are enabled for repositories based on
+// configuration loaded from the repositories. This supports gradual rollout
+// of new application features.
+type FeatureGate struct {
+	cc      githubapp.ClientCreator
+	loader  *Loader
+	parser  FeatureParser
+	ttl     time.Duration
+	enabled bool
+
+	cache *ttlcache.Cache
+}
+
+// NewFeatureGate creates a FeatureGate that uses loader to read configuration
+// from the default branch of repositories with installation clients from cc.
+func NewFeatureGate(cc githubapp.ClientCreator, loader *Loader, opts ...FeatureGateOption) *FeatureGate {
+	g := &FeatureGate{
+		cc:     cc,
+		loader: loader,
+		parser: YAMLFeatureParser,
+		ttl:    DefaultFeatureCacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.cache = ttlcache.New(g.ttl, 2*g.ttl)
+	return g
+}
+
+// Enabled returns true if feature is enabled for the repository owner/repo.
+func (g *FeatureGate) Enabled(ctx context.Context, installationID int64, owner, repo, feature string) (bool, error) {
+	features, err := g.features(ctx, installationID, owner, repo)
+	if err != nil {
+		return false, err
+	}
+	if enabled, ok := features[feature]; ok {
+		return enabled, nil
+	}
+	return g.enabled, nil
+}
+
+// InvalidateRepository removes the cached feature flags for the repository
+// owner/repo, so the next check loads the current configuration. It
+// implements githubapp.RepositoryCacheInvalidator.
+func (g *FeatureGate) InvalidateRepository(owner, repo string) {
+	g.cache.Delete(fmt.Sprintf("%s/%s", owner, repo))
+}
+
+func (g *FeatureGate) features(ctx context.Context, installationID int64, owner, repo string) (map[string]bool, error) {
+	key := fmt.Sprintf("%s/%s", owner, repo)
+	if v, ok := g.cache.Get(key); ok {
+		return v.(map[string]bool), nil
+	}
+
+	client, err := g.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := g.loader.LoadConfig(ctx, client, owner, repo, "")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load configuration for %s", key)
+	}
+
+	var features map[string]bool
+	if !c.IsUndefined() {
+		if features, err = g.parser(c); err != nil {
+			return nil, err
+		}
+	}
+
+	g.cache.Set(key, features, ttlcache.DefaultExpiration)
+	return features, nil
+}
+
+// Wrap returns an EventHandler that only calls h if feature is enabled for
+// the repository of the event. Events that are not associated with a
+// repository are always passed to h.
+func (g *FeatureGate) Wrap(feature string, h githubapp.EventHandler) githubapp.EventHandler {
+	return &gatedHandler{
+		EventHandler: h,
+		gate:         g,
+		feature:      feature,
+	}
+}
+
+type gatedHandler struct {
+	githubapp.EventHandler
+	gate    *FeatureGate
+	feature string
+}
+
+func (h *gatedHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event struct {
+		Installation struct {
+			ID int64 `json:"id"`
+		} `json:"installation"`
+		Repository struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errors.Wrapf(err, "failed to parse %s event payload", eventType)
+	}
+
+	owner, repo := event.Repository.Owner.Login, event.Repository.Name
+	if owner != "" && repo != "" {
+		enabled, err := h.gate.Enabled(ctx, event.Installation.ID, owner, repo, h.feature)
+		if err != nil {
+			return err
+		}
+		if !enabled {
+			zerolog.Ctx(ctx).Debug().Msgf("Skipping event: feature %q is disabled for %s/%s", h.feature, owner, repo)
+			return nil
+		}
+	}
+	return h.EventHandler.Handle(ctx, eventType, deliveryID, payload)
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+)
+
+type testClientCreator struct {
+	githubapp.ClientCreator
+	client *github.Client
+}
+
+func (cc testClientCreator) NewInstallationClient(int64) (*github.Client, error) {
+	return cc.client, nil
+}
+
+type countingHandler struct {
+	count int
+}
+
+func (h *countingHandler) Handles() []string { return []string{"pull_request"} }
+
+func (h *countingHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	h.count++
+	return nil
+}
+
+func TestFeatureGate(t *testing.T) {
+	ctx := context.Background()
+
+	rp := &ResponsePlayer{}
+	rule := rp.AddRule(ExactPathMatcher("/repos/test/features/contents/.github/test-app.yml"), filepath.Join("testdata", "features-contents.yml"))
+	rp.AddRule(ExactPathMatcher("/repos/test/no-features/contents/.github/test-app.yml"), filepath.Join("testdata", "404.yml"))
+	rp.AddRule(ExactPathMatcher("/repos/test/.github"), filepath.Join("testdata", "404.yml"))
+
+	cc := testClientCreator{client: github.NewClient(&http.Client{Transport: rp})}
+	gate := NewFeatureGate(cc, NewLoader([]string{".github/test-app.yml"}))
+
+	tests := map[string]struct {
+		Repo     string
+		Feature  string
+		Expected bool
+	}{
+		"enabled": {
+			Repo:     "features",
+			Feature:  "labeler",
+			Expected: true,
+		},
+		"disabled": {
+			Repo:     "features",
+			Feature:  "merger",
+			Expected: false,
+		},
+		"unconfiguredFeature": {
+			Repo:     "features",
+			Feature:  "other",
+			Expected: false,
+		},
+		"unconfiguredRepository": {
+			Repo:     "no-features",
+			Feature:  "labeler",
+			Expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			enabled, err := gate.Enabled(ctx, 1, "test", test.Repo, test.Feature)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if enabled != test.Expected {
+				t.Errorf("incorrect result: expected %t, actual %t", test.Expected, enabled)
+			}
+		})
+	}
+
+	if rule.Count != 1 {
+		t.Errorf("expected configuration to be cached, but loaded it %d times", rule.Count)
+	}
+
+	h := &countingHandler{}
+	for _, feature := range []string{"labeler", "merger"} {
+		payload := []byte(`{"installation":{"id":1},"repository":{"name":"features","owner":{"login":"test"}}}`)
+		if err := gate.Wrap(feature, h).Handle(ctx, "pull_request", "", payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if h.count != 1 {
+		t.Errorf("incorrect handler call count: expected 1, actual %d", h.count)
+	}
+
+	gate.InvalidateRepository("test", "features")
+	if _, err := gate.Enabled(ctx, 1, "test", "features", "labeler"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rule.Count != 2 {
+		t.Errorf("expected configuration to be reloaded after invalidation, but loaded it %d times", rule.Count)
+	}
+}
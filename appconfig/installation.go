@@ -0,0 +1,108 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"strings"
+
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+)
+
+// InstallationLoaderOption configures properties of an InstallationLoader.
+type InstallationLoaderOption func(*InstallationLoader)
+
+// WithInstallationsService sets the service used to find the installation
+// of repositories. Use a caching service, like one from
+// githubapp.NewCachingInstallationsService, to avoid a request for each
+// load. By default, the loader creates an uncached service with an
+// application client for each load that needs one.
+func WithInstallationsService(installations githubapp.InstallationsService) InstallationLoaderOption {
+	return func(l *InstallationLoader) {
+		l.installations = installations
+	}
+}
+
+// InstallationLoader loads configuration for repositories by name, finding
+// the installation of each repository and creating an installation client
+// for it. It combines the steps that handlers and jobs otherwise repeat
+// before every call to Loader.LoadConfig.
+type InstallationLoader struct {
+	cc            githubapp.ClientCreator
+	loader        *Loader
+	installations githubapp.InstallationsService
+}
+
+// NewInstallationLoader creates an InstallationLoader that loads
+// configuration with loader using clients from cc.
+func NewInstallationLoader(cc githubapp.ClientCreator, loader *Loader, opts ...InstallationLoaderOption) *InstallationLoader {
+	l := &InstallationLoader{
+		cc:     cc,
+		loader: loader,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// LoadInstallationConfig loads configuration for the repository owner/repo
+// at ref, as described by Loader.LoadConfig. If the context contains a
+// delivery for the same repository, the installation from the delivery is
+// used; otherwise, the installation is found with the installations service.
+// If the app is not installed on the repository, the error wraps a
+// githubapp.InstallationNotFound error.
+func (l *InstallationLoader) LoadInstallationConfig(ctx context.Context, owner, repo, ref string) (Config, error) {
+	installationID, err := l.installationID(ctx, owner, repo)
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to find installation for %s/%s", owner, repo)
+	}
+
+	client, err := l.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to create client for installation %d", installationID)
+	}
+
+	c, err := l.loader.LoadConfig(ctx, client, owner, repo, ref)
+	if err != nil {
+		return c, errors.Wrapf(err, "failed to load configuration for %s/%s", owner, repo)
+	}
+	return c, nil
+}
+
+func (l *InstallationLoader) installationID(ctx context.Context, owner, repo string) (int64, error) {
+	if d, ok := githubapp.GetDelivery(ctx); ok {
+		if p, err := githubapp.ParseCommonPayload(d.Payload); err == nil && p.InstallationID() > 0 &&
+			strings.EqualFold(p.RepositoryOwner(), owner) && strings.EqualFold(p.RepositoryName(), repo) {
+			return p.InstallationID(), nil
+		}
+	}
+
+	installations := l.installations
+	if installations == nil {
+		appClient, err := l.cc.NewAppClient()
+		if err != nil {
+			return 0, err
+		}
+		installations = githubapp.NewInstallationsService(appClient)
+	}
+
+	installation, err := installations.GetByRepository(ctx, owner, repo)
+	if err != nil {
+		return 0, err
+	}
+	return installation.ID, nil
+}
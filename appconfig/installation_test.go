@@ -0,0 +1,120 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+)
+
+type installationClientCreator struct {
+	githubapp.ClientCreator
+	client *github.Client
+
+	installationIDs []int64
+}
+
+func (cc *installationClientCreator) NewAppClient() (*github.Client, error) {
+	return cc.client, nil
+}
+
+func (cc *installationClientCreator) NewInstallationClient(id int64) (*github.Client, error) {
+	cc.installationIDs = append(cc.installationIDs, id)
+	return cc.client, nil
+}
+
+func TestLoadInstallationConfig(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/test/{repo}/installation", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("repo") != "configured" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, `{"id": 7, "account": {"login": "test"}}`)
+	})
+	mux.HandleFunc("GET /repos/test/configured/contents/.github/test-app.yml", func(w http.ResponseWriter, r *http.Request) {
+		content := base64.StdEncoding.EncodeToString([]byte("ref: " + r.URL.Query().Get("ref")))
+		_, _ = fmt.Fprintf(w, `{"type": "file", "encoding": "base64", "content": %q}`, content)
+	})
+	mux.HandleFunc("GET /repos/test/.github", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	tests := map[string]struct {
+		Repo    string
+		Payload string
+
+		InstallationID int64
+		NotFound       bool
+	}{
+		"lookup": {
+			Repo:           "configured",
+			InstallationID: 7,
+		},
+		"delivery": {
+			Repo:           "configured",
+			Payload:        `{"installation": {"id": 42}, "repository": {"name": "configured", "owner": {"login": "Test"}}}`,
+			InstallationID: 42,
+		},
+		"otherRepositoryDelivery": {
+			Repo:           "configured",
+			Payload:        `{"installation": {"id": 42}, "repository": {"name": "other", "owner": {"login": "test"}}}`,
+			InstallationID: 7,
+		},
+		"notInstalled": {
+			Repo:     "missing",
+			NotFound: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cc := &installationClientCreator{client: github.NewClient(&http.Client{Transport: muxTransport{mux: mux}})}
+			l := NewInstallationLoader(cc, NewLoader([]string{".github/test-app.yml"}))
+
+			ctx := context.Background()
+			if test.Payload != "" {
+				ctx = githubapp.WithDelivery(ctx, githubapp.Delivery{EventType: "push", Payload: []byte(test.Payload)})
+			}
+
+			c, err := l.LoadInstallationConfig(ctx, "test", test.Repo, "main")
+			if test.NotFound {
+				var notFound githubapp.InstallationNotFound
+				if !errors.As(err, &notFound) {
+					t.Fatalf("expected InstallationNotFound, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(c.Content) != "ref: main" {
+				t.Errorf("incorrect content: %q", c.Content)
+			}
+			if len(cc.installationIDs) != 1 || cc.installationIDs[0] != test.InstallationID {
+				t.Errorf("incorrect installation clients: %v", cc.installationIDs)
+			}
+		})
+	}
+}
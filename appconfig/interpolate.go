@@ -0,0 +1,106 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver returns the value of a reference in configuration content. The
+// name is the text between "${" and "}", with surrounding whitespace
+// removed. Resolvers return an error if the reference is not defined.
+type Resolver func(ctx context.Context, name string) (string, error)
+
+// EnvResolver returns a Resolver that resolves references to the given
+// environment variables. References to other names are errors, which
+// prevents repository configuration from reading arbitrary environment
+// variables of the application.
+func EnvResolver(names ...string) Resolver {
+	allowed := make(map[string]bool, len(names))
+	for _, name := range names {
+		allowed[name] = true
+	}
+
+	return func(ctx context.Context, name string) (string, error) {
+		if !allowed[name] {
+			return "", errors.Errorf("reference to %q is not allowed", name)
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.Errorf("environment variable %q is not set", name)
+		}
+		return value, nil
+	}
+}
+
+// MapResolver returns a Resolver that resolves references to the keys of
+// values.
+func MapResolver(values map[string]string) Resolver {
+	return func(ctx context.Context, name string) (string, error) {
+		value, ok := values[name]
+		if !ok {
+			return "", errors.Errorf("reference to %q is not defined", name)
+		}
+		return value, nil
+	}
+}
+
+func interpolate(ctx context.Context, content []byte, resolver Resolver) ([]byte, error) {
+	if !bytes.Contains(content, []byte("${")) {
+		return content, nil
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(content))
+
+	for {
+		i := bytes.Index(content, []byte("${"))
+		if i < 0 {
+			out.Write(content)
+			return out.Bytes(), nil
+		}
+
+		// "$${" is an escaped, literal "${"
+		if i > 0 && content[i-1] == '$' {
+			out.Write(content[:i-1])
+			out.WriteString("${")
+			content = content[i+2:]
+			continue
+		}
+
+		end := bytes.IndexByte(content[i:], '}')
+		if end < 0 {
+			return nil, errors.New("invalid interpolation: missing closing brace")
+		}
+
+		name := string(bytes.TrimSpace(content[i+2 : i+end]))
+		if name == "" {
+			return nil, errors.New("invalid interpolation: empty reference")
+		}
+
+		value, err := resolver(ctx, name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to interpolate configuration")
+		}
+
+		out.Write(content[:i])
+		out.WriteString(value)
+		content = content[i+end+1:]
+	}
+}
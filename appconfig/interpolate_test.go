@@ -0,0 +1,94 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	resolver := MapResolver(map[string]string{
+		"TOKEN":  "secret-value",
+		"REGION": "us-east-1",
+	})
+
+	tests := map[string]struct {
+		Content  string
+		Expected string
+		Error    bool
+	}{
+		"noReferences": {
+			Content:  "message: hello\n",
+			Expected: "message: hello\n",
+		},
+		"references": {
+			Content:  "token: ${TOKEN}\nregion: ${ REGION }\n",
+			Expected: "token: secret-value\nregion: us-east-1\n",
+		},
+		"escaped": {
+			Content:  "literal: $${TOKEN}\nprice: $5\n",
+			Expected: "literal: ${TOKEN}\nprice: $5\n",
+		},
+		"undefined": {
+			Content: "token: ${MISSING}\n",
+			Error:   true,
+		},
+		"unclosed": {
+			Content: "token: ${TOKEN\n",
+			Error:   true,
+		},
+		"empty": {
+			Content: "token: ${}\n",
+			Error:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out, err := interpolate(context.Background(), []byte(test.Content), resolver)
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(out) != test.Expected {
+				t.Errorf("incorrect content\nexpected: %q\n  actual: %q", test.Expected, string(out))
+			}
+		})
+	}
+}
+
+func TestEnvResolver(t *testing.T) {
+	t.Setenv("APPCONFIG_TEST_ALLOWED", "allowed")
+	t.Setenv("APPCONFIG_TEST_PRIVATE", "private")
+
+	resolver := EnvResolver("APPCONFIG_TEST_ALLOWED", "APPCONFIG_TEST_UNSET")
+	ctx := context.Background()
+
+	if v, err := resolver(ctx, "APPCONFIG_TEST_ALLOWED"); err != nil || v != "allowed" {
+		t.Errorf("incorrect value for allowed variable: %q, %v", v, err)
+	}
+	if _, err := resolver(ctx, "APPCONFIG_TEST_PRIVATE"); err == nil {
+		t.Error("expected error for variable that is not allowed, but got nil")
+	}
+	if _, err := resolver(ctx, "APPCONFIG_TEST_UNSET"); err == nil {
+		t.Error("expected error for unset variable, but got nil")
+	}
+}
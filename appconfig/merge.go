@@ -0,0 +1,211 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ListMergeMode determines how LoadMergedConfig merges lists that appear in
+// both the owner default and the repository configuration.
+type ListMergeMode int
+
+const (
+	// ListReplace uses the repository list and ignores the default list.
+	ListReplace ListMergeMode = iota
+
+	// ListAppend appends the repository list to the default list.
+	ListAppend
+
+	// ListUnion appends the items of the repository list that are not in the
+	// default list to the default list.
+	ListUnion
+)
+
+// Provenance identifies the configuration that defined a value in a
+// MergedConfig.
+type Provenance string
+
+const (
+	ProvenanceDefault    Provenance = "default"
+	ProvenanceRepository Provenance = "repository"
+	ProvenanceMerged     Provenance = "merged"
+)
+
+// MergedConfig is the result of merging repository configuration with the
+// owner default.
+type MergedConfig struct {
+	// Content is the merged configuration, encoded as YAML.
+	Content []byte
+
+	// Default and Repository are the configurations that were merged. Either
+	// may be undefined.
+	Default    Config
+	Repository Config
+
+	// Provenance maps each top-level key in Content to the configuration that
+	// defined its value. Keys defined in both configurations have
+	// ProvenanceMerged if the values were combined and ProvenanceRepository
+	// if the repository value replaced the default value.
+	Provenance map[string]Provenance
+}
+
+// IsUndefined returns true if neither the owner default nor the repository
+// defined configuration.
+func (c MergedConfig) IsUndefined() bool {
+	return c.Default.IsUndefined() && c.Repository.IsUndefined()
+}
+
+// LoadMergedConfig loads both the owner default and the configuration for
+// the repository owner/repo and merges them. Unlike LoadConfig, which only
+// uses the default if the repository has no configuration, repositories can
+// override individual values in the default. Both configurations must be
+// YAML (or JSON) maps.
+//
+// Maps are merged recursively, with repository values taking priority. Lists
+// are merged according to the loader's ListMergeMode. All other repository
+// values, including null, replace the default value. If neither
+// configuration exists, it returns an undefined MergedConfig and a nil
+// error.
+//
+// If error is non-nil, the last defined Config in the result tells which file
+// LoadMergedConfig was processing when it encountered the error.
+func (ld *Loader) LoadMergedConfig(ctx context.Context, client *github.Client, owner, repo, ref string) (MergedConfig, error) {
+	var mc MergedConfig
+
+	c, err := ld.loadRepositoryConfig(ctx, client, owner, repo, ref)
+	if err == nil {
+		c, err = ld.processConfig(ctx, owner, repo, c)
+	}
+	mc.Repository = c
+	if err != nil {
+		return mc, err
+	}
+
+	if ld.hasOwnerDefault() {
+		c, err := ld.loadDefaultConfig(ctx, client, owner)
+		if err == nil {
+			c, err = ld.processConfig(ctx, owner, repo, c)
+		}
+		mc.Default = c
+		if err != nil {
+			return mc, err
+		}
+	}
+
+	if mc.IsUndefined() {
+		return mc, nil
+	}
+
+	def, err := parseMergeable(mc.Default)
+	if err != nil {
+		return mc, err
+	}
+	local, err := parseMergeable(mc.Repository)
+	if err != nil {
+		return mc, err
+	}
+
+	mc.Provenance = make(map[string]Provenance)
+	for k := range def {
+		mc.Provenance[fmt.Sprint(k)] = ProvenanceDefault
+	}
+	for k, v := range local {
+		p := ProvenanceRepository
+		if dv, ok := def[k]; ok && isMergeable(dv, v, ld.listMerge) {
+			p = ProvenanceMerged
+		}
+		mc.Provenance[fmt.Sprint(k)] = p
+	}
+
+	merged := mergeMaps(def, local, ld.listMerge)
+	if len(merged) > 0 {
+		content, err := yaml.Marshal(merged)
+		if err != nil {
+			return mc, errors.Wrap(err, "failed to marshal merged configuration")
+		}
+		mc.Content = content
+	}
+	return mc, nil
+}
+
+func parseMergeable(c Config) (map[interface{}]interface{}, error) {
+	var m map[interface{}]interface{}
+	if err := yaml.Unmarshal(c.Content, &m); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s in %s as a map", c.Path, c.Source)
+	}
+	return m, nil
+}
+
+func isMergeable(dst, src interface{}, mode ListMergeMode) bool {
+	switch dst.(type) {
+	case map[interface{}]interface{}:
+		_, ok := src.(map[interface{}]interface{})
+		return ok
+	case []interface{}:
+		_, ok := src.([]interface{})
+		return ok && mode != ListReplace
+	}
+	return false
+}
+
+// mergeMaps returns a new map containing the values in src merged into the
+// values in dst. It does not modify either argument.
+func mergeMaps(dst, src map[interface{}]interface{}, mode ListMergeMode) map[interface{}]interface{} {
+	out := make(map[interface{}]interface{}, len(dst)+len(src))
+	for k, v := range dst {
+		out[k] = v
+	}
+	for k, v := range src {
+		if dv, ok := out[k]; ok && isMergeable(dv, v, mode) {
+			out[k] = mergeValues(dv, v, mode)
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func mergeValues(dst, src interface{}, mode ListMergeMode) interface{} {
+	switch dv := dst.(type) {
+	case map[interface{}]interface{}:
+		return mergeMaps(dv, src.(map[interface{}]interface{}), mode)
+	case []interface{}:
+		out := append([]interface{}{}, dv...)
+		for _, item := range src.([]interface{}) {
+			if mode == ListUnion && containsValue(dv, item) {
+				continue
+			}
+			out = append(out, item)
+		}
+		return out
+	}
+	return src
+}
+
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestLoadMergedConfig(t *testing.T) {
+	tests := map[string]struct {
+		Paths      []string
+		Options    []Option
+		Repo       string
+		Expected   string
+		Provenance map[string]Provenance
+	}{
+		"replaceLists": {
+			Paths: []string{".github/merge-app.yml"},
+			Repo:  "merge-config",
+			Expected: `
+checks: {lint: false, test: true}
+labels: [b, c]
+message: override
+owners: [admin]
+`,
+			Provenance: map[string]Provenance{
+				"checks":  ProvenanceMerged,
+				"labels":  ProvenanceRepository,
+				"message": ProvenanceRepository,
+				"owners":  ProvenanceDefault,
+			},
+		},
+		"appendLists": {
+			Paths:   []string{".github/merge-app.yml"},
+			Options: []Option{WithListMerge(ListAppend)},
+			Repo:    "merge-config",
+			Expected: `
+checks: {lint: false, test: true}
+labels: [a, b, b, c]
+message: override
+owners: [admin]
+`,
+			Provenance: map[string]Provenance{
+				"checks":  ProvenanceMerged,
+				"labels":  ProvenanceMerged,
+				"message": ProvenanceRepository,
+				"owners":  ProvenanceDefault,
+			},
+		},
+		"unionLists": {
+			Paths:   []string{".github/merge-app.yml"},
+			Options: []Option{WithListMerge(ListUnion)},
+			Repo:    "merge-config",
+			Expected: `
+checks: {lint: false, test: true}
+labels: [a, b, c]
+message: override
+owners: [admin]
+`,
+			Provenance: map[string]Provenance{
+				"checks":  ProvenanceMerged,
+				"labels":  ProvenanceMerged,
+				"message": ProvenanceRepository,
+				"owners":  ProvenanceDefault,
+			},
+		},
+		"defaultOnly": {
+			Paths: []string{".github/merge-app.yml"},
+			Repo:  "default-config",
+			Expected: `
+checks: {lint: true, test: true}
+labels: [a, b]
+message: hello
+owners: [admin]
+`,
+			Provenance: map[string]Provenance{
+				"checks":  ProvenanceDefault,
+				"labels":  ProvenanceDefault,
+				"message": ProvenanceDefault,
+				"owners":  ProvenanceDefault,
+			},
+		},
+		"repositoryOnly": {
+			Paths:   []string{".github/merge-app.yml"},
+			Options: []Option{WithOwnerDefault("", nil)},
+			Repo:    "merge-config",
+			Expected: `
+checks: {lint: false}
+labels: [b, c]
+message: override
+`,
+			Provenance: map[string]Provenance{
+				"checks":  ProvenanceRepository,
+				"labels":  ProvenanceRepository,
+				"message": ProvenanceRepository,
+			},
+		},
+	}
+
+	ctx := context.Background()
+	client := makeTestClient()
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ld := NewLoader(test.Paths, test.Options...)
+
+			mc, err := ld.LoadMergedConfig(ctx, client, TestOwner, test.Repo, TestRef)
+			if err != nil {
+				t.Fatalf("unexpected error loading config: %v", err)
+			}
+
+			var expected, actual interface{}
+			if err := yaml.Unmarshal([]byte(test.Expected), &expected); err != nil {
+				t.Fatalf("invalid expected content: %v", err)
+			}
+			if err := yaml.Unmarshal(mc.Content, &actual); err != nil {
+				t.Fatalf("invalid merged content: %v", err)
+			}
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("incorrect content\nexpected: %s\n  actual: %s", test.Expected, mc.Content)
+			}
+			if !reflect.DeepEqual(test.Provenance, mc.Provenance) {
+				t.Errorf("incorrect provenance\nexpected: %v\n  actual: %v", test.Provenance, mc.Provenance)
+			}
+		})
+	}
+}
+
+func TestLoadMergedConfigUndefined(t *testing.T) {
+	ld := NewLoader([]string{".github/merge-app.yml"}, WithOwnerDefault("", nil))
+
+	mc, err := ld.LoadMergedConfig(context.Background(), makeTestClient(), TestOwner, "default-config", TestRef)
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+	if !mc.IsUndefined() {
+		t.Errorf("expected undefined config, but got %+v", mc)
+	}
+}
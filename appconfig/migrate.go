@@ -0,0 +1,104 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// UnversionedConfigVersion is the version of configuration that does not
+// have a "version" field, which is usually the format used before the
+// application added versions.
+const UnversionedConfigVersion = 1
+
+// MigrationFunc upgrades configuration content by one version. It receives
+// content in the format of the version it is registered for and returns
+// content in the format of the next version.
+type MigrationFunc func(ctx context.Context, content []byte) ([]byte, error)
+
+// VersionError is returned by a loader with migrations when configuration
+// has a version the loader cannot upgrade to the current version.
+type VersionError struct {
+	Version int
+	Current int
+}
+
+func (e VersionError) Error() string {
+	if e.Version > e.Current {
+		return fmt.Sprintf("configuration version %d is newer than the supported version %d", e.Version, e.Current)
+	}
+	return fmt.Sprintf("no migration from configuration version %d", e.Version)
+}
+
+// WithMigrations enables versioned configuration. Configuration declares its
+// format with a top-level "version" field, which is UnversionedConfigVersion
+// if it is missing. Before returning configuration, the loader applies the
+// migration registered for each version, in order, until the content is in
+// the current format, so applications can change their configuration format
+// without breaking repositories that use older versions. The original version
+// is available in Config.Version.
+//
+// Migrations are keyed by the version they upgrade from. The loader returns
+// a VersionError if content has a version newer than current or if a
+// migration is missing. Migrations run after interpolation and decryption,
+// and LoadMergedConfig migrates each configuration before merging them.
+func WithMigrations(current int, migrations map[int]MigrationFunc) Option {
+	return func(ld *Loader) {
+		if migrations == nil {
+			migrations = make(map[int]MigrationFunc)
+		}
+		ld.migrations = migrations
+		ld.currentVersion = current
+	}
+}
+
+// migrate upgrades content to the current version and returns the original
+// version of the content
+func (ld *Loader) migrate(ctx context.Context, content []byte) ([]byte, int, error) {
+	var versioned struct {
+		Version *int `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(content, &versioned); err != nil {
+		return nil, 0, errors.Wrap(err, "failed to parse configuration version")
+	}
+
+	version := UnversionedConfigVersion
+	if versioned.Version != nil {
+		version = *versioned.Version
+	}
+
+	original := version
+	for version < ld.currentVersion {
+		migrate, ok := ld.migrations[version]
+		if !ok || migrate == nil {
+			return nil, original, VersionError{Version: version, Current: ld.currentVersion}
+		}
+
+		upgraded, err := migrate(ctx, content)
+		if err != nil {
+			return nil, original, errors.Wrapf(err, "failed to migrate configuration from version %d", version)
+		}
+		content = upgraded
+		version++
+	}
+	if version > ld.currentVersion {
+		return nil, original, VersionError{Version: version, Current: ld.currentVersion}
+	}
+	return content, original, nil
+}
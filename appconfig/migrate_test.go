@@ -0,0 +1,103 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	// version 1 uses "reviewers", version 2 renames it to "approvers", and
+	// version 3 renames it to "owners"
+	migrations := map[int]MigrationFunc{
+		1: func(ctx context.Context, content []byte) ([]byte, error) {
+			content = bytes.Replace(content, []byte("reviewers:"), []byte("approvers:"), 1)
+			content = bytes.Replace(content, []byte("version: 1\n"), nil, 1)
+			return append([]byte("version: 2\n"), content...), nil
+		},
+		2: func(ctx context.Context, content []byte) ([]byte, error) {
+			content = bytes.Replace(content, []byte("approvers:"), []byte("owners:"), 1)
+			return bytes.Replace(content, []byte("version: 2"), []byte("version: 3"), 1), nil
+		},
+	}
+
+	tests := map[string]struct {
+		Content  string
+		Expected string
+		Version  int
+		Error    bool
+	}{
+		"unversioned": {
+			Content:  "reviewers: [octo]\n",
+			Expected: "version: 3\nowners: [octo]\n",
+			Version:  1,
+		},
+		"older": {
+			Content:  "version: 2\napprovers: [octo]\n",
+			Expected: "version: 3\nowners: [octo]\n",
+			Version:  2,
+		},
+		"current": {
+			Content:  "version: 3\nowners: [octo]\n",
+			Expected: "version: 3\nowners: [octo]\n",
+			Version:  3,
+		},
+		"newer": {
+			Content: "version: 4\n",
+			Version: 4,
+			Error:   true,
+		},
+		"missingMigration": {
+			Content: "version: 0\n",
+			Version: 0,
+			Error:   true,
+		},
+		"invalid": {
+			Content: "version: [",
+			Error:   true,
+		},
+	}
+
+	ld := NewLoader([]string{".github/test-app.yml"}, WithMigrations(3, migrations))
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := ld.processConfig(context.Background(), "test", "repo", Config{Content: []byte(test.Content)})
+			if c.Version != test.Version {
+				t.Errorf("incorrect version: expected %d, actual %d", test.Version, c.Version)
+			}
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(c.Content) != test.Expected {
+				t.Errorf("incorrect content:\nexpected: %q\n  actual: %q", test.Expected, c.Content)
+			}
+		})
+	}
+
+	_, err := ld.processConfig(context.Background(), "test", "repo", Config{Content: []byte("version: 5\n")})
+	var versionErr VersionError
+	if !errors.As(err, &versionErr) || versionErr.Current != 3 {
+		t.Errorf("expected version error, but got %v", err)
+	}
+}
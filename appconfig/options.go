@@ -24,6 +24,21 @@ func WithRemoteRefParser(parser RemoteRefParser) Option {
 	}
 }
 
+// WithGistRemotes allows remote references to files in gists. By default,
+// the loader rejects gist references, so remote references can only load
+// files from repositories the installation can access. If owners is not
+// empty, the loader only loads gists owned by one of these users.
+//
+// Any repository can reference any public or secret gist, and the loader
+// interpolates and decrypts content loaded from gists like other content,
+// so set owners unless gists from any user are acceptable.
+func WithGistRemotes(owners ...string) Option {
+	return func(ld *Loader) {
+		ld.gists = true
+		ld.gistOwners = owners
+	}
+}
+
 // WithOwnerDefault sets the owner repository and paths to check when a
 // repository does not define its own configuration. By default, the repository
 // name is ".github" and the paths are those passed to the loader with the
@@ -36,6 +51,40 @@ func WithOwnerDefault(name string, paths []string) Option {
 	}
 }
 
+// WithInterpolation replaces references like "${NAME}" in loaded content with
+// values from resolver before the loader returns it. This allows
+// configuration committed to repositories to reference secrets without
+// containing them. Use "$${" to include a literal "${" in content. By
+// default, content is returned unmodified.
+//
+// Repository configuration is controlled by repository users, so the
+// resolver should only resolve values that users are allowed to reference.
+func WithInterpolation(resolver Resolver) Option {
+	return func(ld *Loader) {
+		ld.resolver = resolver
+	}
+}
+
+// WithDecrypter sets a function that decrypts loaded content before the
+// loader returns it. This allows organizations to commit encrypted
+// credentials to repository configuration that only the application can
+// decrypt. The decrypter runs after interpolation, so decrypted values are
+// never interpolated. By default, content is returned unmodified.
+func WithDecrypter(decrypter Decrypter) Option {
+	return func(ld *Loader) {
+		ld.decrypter = decrypter
+	}
+}
+
+// WithListMerge sets how LoadMergedConfig merges lists that appear in both
+// the repository configuration and the owner default. The default is
+// ListReplace.
+func WithListMerge(mode ListMergeMode) Option {
+	return func(ld *Loader) {
+		ld.listMerge = mode
+	}
+}
+
 /*
 
 Not sure this is valuable yet, but leaving this option function as a starting
@@ -0,0 +1,320 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultWatchInterval = 5 * time.Minute
+)
+
+// WatchFunc is called with the configuration of a watched repository. The
+// context is the context of the operation that loaded the configuration.
+// Calls for the same repository never overlap, so functions must not call
+// Watch or Refresh for that repository.
+type WatchFunc func(ctx context.Context, c Config)
+
+// WatcherOption configures properties of a Watcher.
+type WatcherOption func(*Watcher)
+
+// WithWatchInterval sets how often Run reloads the configuration of watched
+// repositories. The default is DefaultWatchInterval.
+func WithWatchInterval(interval time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if interval > 0 {
+			w.interval = interval
+		}
+	}
+}
+
+// Watcher notifies long-running workers when the configuration of a
+// repository changes, so they do not need to load configuration for every
+// event. It loads configuration from the default branch of repositories
+// with installation clients from a ClientCreator.
+//
+// The watcher is also an EventHandler for "push" events. Register it with
+// the other handlers of an application to reload configuration when a push
+// changes the default branch of a watched repository, the organization
+// default repository, or the target of a remote reference. Run reloads all
+// watched configuration periodically to catch any changes missed by the
+// handler. Functions are only called when the loaded configuration changes.
+type Watcher struct {
+	cc       githubapp.ClientCreator
+	loader   *Loader
+	interval time.Duration
+
+	mu     sync.Mutex
+	repos  map[string]*watchedRepo
+	nextID int
+}
+
+type watchedRepo struct {
+	installationID int64
+	owner          string
+	name           string
+
+	// loadMu serializes loads so that functions see updates in order
+	loadMu sync.Mutex
+	loaded bool
+	config Config
+
+	// funcs is guarded by Watcher.mu
+	funcs map[int]WatchFunc
+}
+
+// NewWatcher creates a Watcher that uses loader to read configuration with
+// installation clients from cc.
+func NewWatcher(cc githubapp.ClientCreator, loader *Loader, opts ...WatcherOption) *Watcher {
+	w := &Watcher{
+		cc:       cc,
+		loader:   loader,
+		interval: DefaultWatchInterval,
+		repos:    make(map[string]*watchedRepo),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Watch registers fn to receive the configuration of the repository
+// owner/repo. It calls fn with the current configuration before returning
+// and then calls it again each time the configuration changes. It returns a
+// function that stops calling fn. If the configuration cannot be loaded,
+// Watch returns an error and does not register fn.
+func (w *Watcher) Watch(ctx context.Context, installationID int64, owner, repo string, fn WatchFunc) (func(), error) {
+	key := fmt.Sprintf("%s/%s", owner, repo)
+
+	for {
+		r := w.getOrCreate(key, installationID, owner, repo)
+
+		r.loadMu.Lock()
+		if !r.loaded {
+			c, err := w.load(ctx, r)
+			if err != nil {
+				w.removeIfUnused(key, r)
+				r.loadMu.Unlock()
+				return nil, errors.Wrapf(err, "failed to load configuration for %s", key)
+			}
+			r.config = c
+			r.loaded = true
+		}
+
+		w.mu.Lock()
+		if w.repos[key] != r {
+			// the repository stopped being watched while this call was waiting
+			w.mu.Unlock()
+			r.loadMu.Unlock()
+			continue
+		}
+		id := w.nextID
+		w.nextID++
+		r.funcs[id] = fn
+		w.mu.Unlock()
+
+		fn(ctx, r.config)
+		r.loadMu.Unlock()
+
+		return func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+
+			delete(r.funcs, id)
+			if len(r.funcs) == 0 && w.repos[key] == r {
+				delete(w.repos, key)
+			}
+		}, nil
+	}
+}
+
+func (w *Watcher) getOrCreate(key string, installationID int64, owner, repo string) *watchedRepo {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	r, ok := w.repos[key]
+	if !ok {
+		r = &watchedRepo{
+			installationID: installationID,
+			owner:          owner,
+			name:           repo,
+			funcs:          make(map[int]WatchFunc),
+		}
+		w.repos[key] = r
+	}
+	return r
+}
+
+func (w *Watcher) removeIfUnused(key string, r *watchedRepo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(r.funcs) == 0 && w.repos[key] == r {
+		delete(w.repos, key)
+	}
+}
+
+// Run reloads the configuration of all watched repositories every interval
+// until the context is canceled. Failed loads are logged.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		for _, r := range w.watched(nil) {
+			if err := w.refresh(ctx, r); err != nil {
+				zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to refresh watched configuration")
+			}
+		}
+	}
+}
+
+// Refresh reloads the configuration of the repository owner/repo, if it is
+// watched, and calls the registered functions if it changed.
+func (w *Watcher) Refresh(ctx context.Context, owner, repo string) error {
+	w.mu.Lock()
+	r, ok := w.repos[fmt.Sprintf("%s/%s", owner, repo)]
+	w.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return w.refresh(ctx, r)
+}
+
+func (w *Watcher) Handles() []string {
+	return githubapp.Handles(githubapp.EventPush)
+}
+
+func (w *Watcher) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event github.PushEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return errors.Wrap(err, "failed to parse push event payload")
+	}
+
+	owner := event.GetRepo().GetOwner().GetLogin()
+	name := event.GetRepo().GetName()
+	branch, ok := strings.CutPrefix(event.GetRef(), "refs/heads/")
+	if !ok {
+		return nil
+	}
+	isDefault := branch == event.GetRepo().GetDefaultBranch()
+	source := fmt.Sprintf("%s/%s@%s", owner, name, branch)
+
+	affected := w.watched(func(r *watchedRepo, c Config) bool {
+		switch {
+		case r.owner == owner && r.name == name:
+			return isDefault
+		case c.Source == source:
+			return true
+		case c.IsUndefined():
+			return r.owner == owner && name == w.loader.defaultRepo && isDefault
+		}
+		return false
+	})
+
+	for _, r := range affected {
+		zerolog.Ctx(ctx).Debug().Msgf("Reloading configuration for %s/%s after push to %s", r.owner, r.name, source)
+		if err := w.refresh(ctx, r); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to refresh watched configuration")
+		}
+	}
+	return nil
+}
+
+// watched returns the watched repositories that match filter. A nil filter
+// matches all repositories.
+func (w *Watcher) watched(filter func(r *watchedRepo, c Config) bool) []*watchedRepo {
+	w.mu.Lock()
+	repos := make([]*watchedRepo, 0, len(w.repos))
+	for _, r := range w.repos {
+		repos = append(repos, r)
+	}
+	w.mu.Unlock()
+
+	if filter == nil {
+		return repos
+	}
+
+	var matched []*watchedRepo
+	for _, r := range repos {
+		r.loadMu.Lock()
+		c := r.config
+		r.loadMu.Unlock()
+
+		if filter(r, c) {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+func (w *Watcher) refresh(ctx context.Context, r *watchedRepo) error {
+	r.loadMu.Lock()
+	defer r.loadMu.Unlock()
+
+	c, err := w.load(ctx, r)
+	if err != nil {
+		return errors.Wrapf(err, "failed to reload configuration for %s/%s", r.owner, r.name)
+	}
+	if sameConfig(c, r.config) {
+		return nil
+	}
+	r.config = c
+
+	w.mu.Lock()
+	funcs := make([]WatchFunc, 0, len(r.funcs))
+	for _, fn := range r.funcs {
+		funcs = append(funcs, fn)
+	}
+	w.mu.Unlock()
+
+	for _, fn := range funcs {
+		fn(ctx, c)
+	}
+	return nil
+}
+
+func (w *Watcher) load(ctx context.Context, r *watchedRepo) (Config, error) {
+	client, err := w.cc.NewInstallationClient(r.installationID)
+	if err != nil {
+		return Config{}, err
+	}
+	return w.loader.LoadConfig(ctx, client, r.owner, r.name, "")
+}
+
+func sameConfig(a, b Config) bool {
+	return a.Source == b.Source && a.Path == b.Path && a.IsRemote == b.IsRemote && bytes.Equal(a.Content, b.Content)
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// fileServer serves repository files from a map of paths to content
+type fileServer struct {
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func (s *fileServer) set(path, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[path] = content
+}
+
+func (s *fileServer) RoundTrip(r *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	content, ok := s.files[r.URL.Path]
+	s.mu.Unlock()
+
+	res := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte("{}"))),
+		Request:    r,
+	}
+	if ok {
+		body, _ := json.Marshal(map[string]string{
+			"type":     "file",
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+		res.StatusCode = http.StatusOK
+		res.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return res, nil
+}
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+
+	const path = "/repos/test/watched/contents/.github/test-app.yml"
+	files := &fileServer{files: map[string]string{path: "message: hello\n"}}
+	cc := testClientCreator{client: github.NewClient(&http.Client{Transport: files})}
+
+	w := NewWatcher(cc, NewLoader([]string{".github/test-app.yml"}))
+
+	var updates []string
+	stop, err := w.Watch(ctx, 1, "test", "watched", func(ctx context.Context, c Config) {
+		updates = append(updates, string(c.Content))
+	})
+	if err != nil {
+		t.Fatalf("unexpected error watching config: %v", err)
+	}
+
+	push := func(repo, ref string) {
+		payload := []byte(`{
+			"ref": "` + ref + `",
+			"repository": {"name": "` + repo + `", "owner": {"login": "test"}, "default_branch": "develop"}
+		}`)
+		if err := w.Handle(ctx, "push", "delivery", payload); err != nil {
+			t.Fatalf("unexpected error handling push: %v", err)
+		}
+	}
+
+	files.set(path, "message: updated\n")
+	push("watched", "refs/heads/feature")
+	push("other", "refs/heads/develop")
+	push("watched", "refs/heads/develop")
+	push("watched", "refs/heads/develop")
+
+	files.set(path, "message: refreshed\n")
+	if err := w.Refresh(ctx, "test", "watched"); err != nil {
+		t.Fatalf("unexpected error refreshing config: %v", err)
+	}
+
+	stop()
+	files.set(path, "message: stopped\n")
+	push("watched", "refs/heads/develop")
+
+	expected := []string{"message: hello\n", "message: updated\n", "message: refreshed\n"}
+	if len(updates) != len(expected) {
+		t.Fatalf("incorrect number of updates: expected %d, actual %d: %q", len(expected), len(updates), updates)
+	}
+	for i := range expected {
+		if updates[i] != expected[i] {
+			t.Errorf("incorrect update %d: expected %q, actual %q", i, expected[i], updates[i])
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command git-credential-githubapp is a git credential helper that provides
+// installation tokens for a GitHub app. Configure git to use it with:
+//
+//	git config credential.helper "githubapp --config /path/to/config.yml"
+//	git config credential.useHttpPath true
+//
+// The configuration file contains a YAML-encoded githubapp.Config. Values may
+// also be set with the environment variables read by Config.SetValuesFromEnv.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/palantir/go-githubapp/cmd/internal/cmdconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to a YAML file containing the app configuration")
+	envPrefix := flag.String("env-prefix", "", "prefix for environment variables that override configuration")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: git-credential-githubapp [--config path] [--env-prefix prefix] <get|store|erase>")
+		os.Exit(2)
+	}
+
+	if err := run(*configPath, *envPrefix, flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "git-credential-githubapp: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(configPath, envPrefix, operation string) error {
+	c, err := cmdconfig.Read(configPath, envPrefix)
+	if err != nil {
+		return err
+	}
+
+	host, err := cmdconfig.Host(c)
+	if err != nil {
+		return err
+	}
+
+	helper := githubapp.NewCredentialHelper(cmdconfig.NewClientCreator(c), githubapp.WithCredentialHost(host))
+	return helper.Serve(context.Background(), operation, os.Stdin, os.Stdout)
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/cmd/internal/cmdconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+)
+
+func runJWT(ctx context.Context, c githubapp.Config, args []string) error {
+	flags := flag.NewFlagSet("jwt", flag.ExitOnError)
+	expiration := flags.Duration("expiration", githubapp.MaxAppJWTExpiration, "lifetime of the token")
+	_ = flags.Parse(args)
+
+	token, err := githubapp.NewAppJWT(c.App.IntegrationID, []byte(c.App.PrivateKey), *expiration)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(token)
+	return nil
+}
+
+func runInstallations(ctx context.Context, c githubapp.Config, args []string) error {
+	flags := flag.NewFlagSet("installations", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	client, err := cmdconfig.NewClientCreator(c).NewAppClient()
+	if err != nil {
+		return err
+	}
+
+	installs, err := githubapp.NewInstallationsService(client).ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tOWNER\tOWNER ID")
+	for _, install := range installs {
+		fmt.Fprintf(w, "%d\t%s\t%d\n", install.ID, install.Owner, install.OwnerID)
+	}
+	return w.Flush()
+}
+
+func runToken(ctx context.Context, c githubapp.Config, args []string) error {
+	flags := flag.NewFlagSet("token", flag.ExitOnError)
+	repos := flags.String("repositories", "", "comma-separated list of repository names to restrict the token to")
+	perms := flags.String("permissions", "", "comma-separated list of name=access permissions to restrict the token to")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: githubapp token [--repositories a,b] [--permissions contents=read,...] <owner>")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("an owner is required")
+	}
+
+	opts := &github.InstallationTokenOptions{}
+	if *repos != "" {
+		opts.Repositories = strings.Split(*repos, ",")
+	}
+	if *perms != "" {
+		p, err := parsePermissions(*perms)
+		if err != nil {
+			return err
+		}
+		opts.Permissions = p
+	}
+
+	client, err := cmdconfig.NewClientCreator(c).NewAppClient()
+	if err != nil {
+		return err
+	}
+
+	install, err := githubapp.NewInstallationsService(client).GetByOwner(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	token, _, err := client.Apps.CreateInstallationToken(ctx, install.ID, opts)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create token for installation %d", install.ID)
+	}
+
+	fmt.Println(token.GetToken())
+	fmt.Fprintf(os.Stderr, "installation %d; expires at %s\n", install.ID, token.GetExpiresAt().Format(time.RFC3339))
+	return nil
+}
+
+func parsePermissions(s string) (*github.InstallationPermissions, error) {
+	m := make(map[string]string)
+	for _, p := range strings.Split(s, ",") {
+		name, access, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, errors.Errorf("invalid permission %q: expected name=access", p)
+		}
+		m[strings.TrimSpace(name)] = strings.TrimSpace(access)
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var perms github.InstallationPermissions
+	if err := json.Unmarshal(b, &perms); err != nil {
+		return nil, errors.Wrap(err, "invalid permissions")
+	}
+	return &perms, nil
+}
+
+func runSelfTest(ctx context.Context, c githubapp.Config, args []string) error {
+	flags := flag.NewFlagSet("selftest", flag.ExitOnError)
+	_ = flags.Parse(args)
+
+	report := githubapp.SelfTest(ctx, c)
+	if _, err := report.WriteTo(os.Stdout); err != nil {
+		return err
+	}
+	return report.Err()
+}
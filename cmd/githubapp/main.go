@@ -0,0 +1,118 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command githubapp is a tool for debugging GitHub app authentication. It reads
+// the same configuration as applications built with this library and can
+// print application JWTs, list installations, and create installation tokens.
+// It can also relay webhook deliveries to an application running locally and
+// run a self-test that diagnoses common configuration problems.
+//
+// Usage:
+//
+//	githubapp [--config path] [--env-prefix prefix] <command> [arguments]
+//
+// The configuration file contains a YAML-encoded githubapp.Config. Values may
+// also be set with the environment variables read by Config.SetValuesFromEnv.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/palantir/go-githubapp/cmd/internal/cmdconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+)
+
+type command struct {
+	Usage string
+	Run   func(ctx context.Context, c githubapp.Config, args []string) error
+}
+
+var commands = map[string]command{
+	"jwt": {
+		Usage: "print a signed application JWT",
+		Run:   runJWT,
+	},
+	"installations": {
+		Usage: "list installations of the application",
+		Run:   runInstallations,
+	},
+	"token": {
+		Usage: "create an installation token for an owner",
+		Run:   runToken,
+	},
+	"relay": {
+		Usage: "send new webhook deliveries to a local endpoint",
+		Run:   runRelay,
+	},
+	"selftest": {
+		Usage: "check the configuration and authentication of the application",
+		Run:   runSelfTest,
+	},
+}
+
+func main() {
+	flags := flag.NewFlagSet("githubapp", flag.ExitOnError)
+	configPath := flags.String("config", "", "path to a YAML file containing the app configuration")
+	envPrefix := flags.String("env-prefix", "", "prefix for environment variables that override configuration")
+	flags.Usage = func() { usage(flags) }
+	_ = flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	name := flags.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "githubapp: unknown command %q\n", name)
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	c, err := cmdconfig.Read(*configPath, *envPrefix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "githubapp: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := cmd.Run(context.Background(), c, flags.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "githubapp %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+func usage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintln(out, "usage: githubapp [--config path] [--env-prefix prefix] <command> [arguments]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-16s %s\n", name, commands[name].Usage)
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "flags:")
+	flags.PrintDefaults()
+}
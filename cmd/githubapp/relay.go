@@ -0,0 +1,62 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/palantir/go-githubapp/cmd/internal/cmdconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+func runRelay(ctx context.Context, c githubapp.Config, args []string) error {
+	flags := flag.NewFlagSet("relay", flag.ExitOnError)
+	interval := flags.Duration("interval", githubapp.DefaultRelayInterval, "how often to poll for new deliveries")
+	secret := flags.String("secret", c.App.WebhookSecret, "secret used to sign relayed deliveries")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: githubapp relay [--interval 5s] [--secret secret] <target-url>")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("a target URL is required")
+	}
+
+	client, err := cmdconfig.NewClientCreator(c).NewAppClient()
+	if err != nil {
+		return err
+	}
+
+	logger := zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
+	ctx = logger.WithContext(ctx)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	logger.Info().Msgf("Relaying new deliveries to %s", flags.Arg(0))
+	relay := githubapp.NewRelay(client, flags.Arg(0), *secret, githubapp.WithRelayInterval(*interval))
+	if err := relay.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cmdconfig loads application configuration for the commands in this
+// repository.
+package cmdconfig
+
+import (
+	"net/url"
+	"os"
+
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Read loads a YAML-encoded githubapp.Config from path, if path is not empty,
+// and then applies values from the environment. URLs that are not set use the
+// values for github.com.
+func Read(path, envPrefix string) (githubapp.Config, error) {
+	var c githubapp.Config
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return c, errors.Wrapf(err, "failed reading config file: %s", path)
+		}
+		if err := yaml.Unmarshal(b, &c); err != nil {
+			return c, errors.Wrap(err, "failed parsing config file")
+		}
+	}
+	c.SetValuesFromEnv(envPrefix)
+
+	if c.WebURL == "" {
+		c.WebURL = "https://github.com"
+	}
+	if c.V3APIURL == "" {
+		c.V3APIURL = "https://api.github.com/"
+	}
+	if c.V4APIURL == "" {
+		c.V4APIURL = "https://api.github.com/graphql"
+	}
+	return c, nil
+}
+
+// Host returns the host name of the web URL in c.
+func Host(c githubapp.Config) (string, error) {
+	u, err := url.Parse(c.WebURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid web URL: %q", c.WebURL)
+	}
+	return u.Host, nil
+}
+
+// NewClientCreator returns a ClientCreator for the app in c.
+func NewClientCreator(c githubapp.Config, opts ...githubapp.ClientOption) githubapp.ClientCreator {
+	return githubapp.NewClientCreatorFromConfig(c, opts...)
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// GetFile returns the content of the file at path on ref in
+// owner/repo. It returns an empty slice and false if the file does not exist
+// or if the path is a directory.
+//
+// Files larger than the 1MB limit of the contents API are downloaded
+// separately, so this works for files up to 100MB.
+func GetFile(ctx context.Context, client *github.Client, owner, repo, ref, path string) ([]byte, bool, error) {
+	file, _, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{
+		Ref: ref,
+	})
+	if err != nil {
+		switch {
+		case isNotFound(err):
+			return nil, false, nil
+		case isTooLargeError(err):
+			b, err := getLargeFile(ctx, client, owner, repo, ref, path)
+			return b, true, err
+		}
+		return nil, false, errors.Wrap(err, "failed to read file")
+	}
+
+	// file will be nil if the path exists but is a directory
+	if file == nil {
+		return nil, false, nil
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, true, errors.Wrap(err, "failed to decode file content")
+	}
+
+	return []byte(content), true, nil
+}
+
+// getLargeFile is similar to GetFile, but works for files up to 100MB.
+// Unlike GetFile, it returns an error if the file does not exist.
+func getLargeFile(ctx context.Context, client *github.Client, owner, repo, ref, path string) ([]byte, error) {
+	body, res, err := client.Repositories.DownloadContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{
+		Ref: ref,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file")
+	}
+	defer closeBody(body)
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to read file: unexpected status code %d", res.StatusCode)
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read file")
+	}
+	return b, nil
+}
+
+// ListDirectory returns the entries of the directory at path on ref in
+// owner/repo. It returns nil and false if the directory does not exist or if
+// the path is a file. Entries do not include file content.
+func ListDirectory(ctx context.Context, client *github.Client, owner, repo, ref, path string) ([]*github.RepositoryContent, bool, error) {
+	_, dir, _, err := client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{
+		Ref: ref,
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrap(err, "failed to list directory")
+	}
+
+	// dir will be nil if the path exists but is a file
+	if dir == nil {
+		return nil, false, nil
+	}
+	return dir, true, nil
+}
+
+// OpenBlob returns a reader for the raw content of the blob with the given
+// SHA in owner/repo. Unlike GetFile, the content is not buffered in
+// memory, so this is appropriate for large files. Callers must close the
+// reader.
+func OpenBlob(ctx context.Context, client *github.Client, owner, repo, sha string) (io.ReadCloser, error) {
+	u := fmt.Sprintf("repos/%s/%s/git/blobs/%s", owner, repo, sha)
+	req, err := client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create blob request")
+	}
+	req.Header.Set("Accept", "application/vnd.github.raw")
+
+	res, err := client.BareDo(ctx, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read blob %s", sha)
+	}
+	return res.Body, nil
+}
+
+func isTooLargeError(err error) bool {
+	if rerr, ok := err.(*github.ErrorResponse); ok {
+		for _, err := range rerr.Errors {
+			if err.Code == "too_large" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isNotFound(err error) bool {
+	rerr, ok := err.(*github.ErrorResponse)
+	return ok && rerr.Response.StatusCode == http.StatusNotFound
+}
+
+func closeBody(b io.ReadCloser) {
+	_ = b.Close() // per http.Transport impl, ignoring close errors is fine
+}
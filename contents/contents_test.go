@@ -0,0 +1,130 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contents
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func newContentsTestClient(t *testing.T) *github.Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/test/repo/contents/dir", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[{"type":"file","name":"a.txt","path":"dir/a.txt"},{"type":"dir","name":"b","path":"dir/b"}]`)
+	})
+	mux.HandleFunc("GET /repos/test/repo/contents/file.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"type":"file","name":"file.txt","path":"file.txt","encoding":"base64","content":"aGVsbG8="}`)
+	})
+	mux.HandleFunc("GET /repos/test/repo/git/blobs/abc123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "application/vnd.github.raw" {
+			http.Error(w, "bad accept header", http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, "raw content")
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return client
+}
+
+func TestGetFile(t *testing.T) {
+	ctx := context.Background()
+	client := newContentsTestClient(t)
+
+	tests := map[string]struct {
+		Path    string
+		Content string
+		Exists  bool
+	}{
+		"file": {
+			Path:    "file.txt",
+			Content: "hello",
+			Exists:  true,
+		},
+		"directory": {
+			Path: "dir",
+		},
+		"missing": {
+			Path: "missing.txt",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, exists, err := GetFile(ctx, client, "test", "repo", "main", test.Path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if exists != test.Exists {
+				t.Errorf("incorrect exists value: expected %t, actual %t", test.Exists, exists)
+			}
+			if string(b) != test.Content {
+				t.Errorf("incorrect content: expected %q, actual %q", test.Content, string(b))
+			}
+		})
+	}
+}
+
+func TestListDirectory(t *testing.T) {
+	ctx := context.Background()
+	client := newContentsTestClient(t)
+
+	entries, exists, err := ListDirectory(ctx, client, "test", "repo", "main", "dir")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !exists || len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d (exists=%t)", len(entries), exists)
+	}
+	if entries[0].GetPath() != "dir/a.txt" || entries[1].GetType() != "dir" {
+		t.Errorf("incorrect entries: %v", entries)
+	}
+
+	_, exists, err = ListDirectory(ctx, client, "test", "repo", "main", "file.txt")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exists {
+		t.Error("expected file path to not exist as a directory")
+	}
+}
+
+func TestOpenBlob(t *testing.T) {
+	client := newContentsTestClient(t)
+
+	r, err := OpenBlob(context.Background(), client, "test", "repo", "abc123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer closeBody(r)
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error reading blob: %v", err)
+	}
+	if string(b) != "raw content" {
+		t.Errorf("incorrect blob content: %q", string(b))
+	}
+}
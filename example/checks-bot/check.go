@@ -0,0 +1,120 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/appconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const CommitMessageCheckName = "commit-message"
+
+// RepositoryConfig is the content of .github/checks-bot.yml in a repository
+// or in the .github repository of its owner.
+type RepositoryConfig struct {
+	CommitMessage CommitMessageConfig `yaml:"commit_message"`
+}
+
+type CommitMessageConfig struct {
+	Disabled          bool     `yaml:"disabled"`
+	MaxSubjectLength  int      `yaml:"max_subject_length"`
+	ForbiddenPrefixes []string `yaml:"forbidden_prefixes"`
+}
+
+// CommitMessageCheck checks the message of the head commit of each check
+// suite against the rules configured for the repository.
+type CommitMessageCheck struct {
+	Loader *appconfig.Loader
+}
+
+func (c *CommitMessageCheck) Run(ctx context.Context, client *github.Client, repo *github.Repository, run *github.CheckRun) (githubapp.CheckResult, error) {
+	owner, name, sha := repo.GetOwner().GetLogin(), repo.GetName(), run.GetHeadSHA()
+
+	config, err := c.loadConfig(ctx, client, owner, name, sha)
+	if err != nil {
+		return githubapp.CheckResult{}, err
+	}
+	if config.Disabled {
+		return githubapp.CheckResult{
+			Conclusion: githubapp.CheckConclusionSkipped,
+			Output: &github.CheckRunOutput{
+				Title:   github.String("Check disabled"),
+				Summary: github.String("The commit-message check is disabled in .github/checks-bot.yml."),
+			},
+		}, nil
+	}
+
+	commit, _, err := client.Git.GetCommit(ctx, owner, name, sha)
+	if err != nil {
+		return githubapp.CheckResult{}, errors.Wrapf(err, "failed to get commit %s", sha)
+	}
+
+	subject, _, _ := strings.Cut(commit.GetMessage(), "\n")
+
+	var problems []string
+	if len(subject) > config.MaxSubjectLength {
+		problems = append(problems, fmt.Sprintf("- The subject is %d characters long; the limit is %d.", len(subject), config.MaxSubjectLength))
+	}
+	for _, prefix := range config.ForbiddenPrefixes {
+		if strings.HasPrefix(subject, prefix) {
+			problems = append(problems, fmt.Sprintf("- The subject starts with %q.", prefix))
+		}
+	}
+
+	if len(problems) == 0 {
+		return githubapp.CheckResult{
+			Output: &github.CheckRunOutput{
+				Title:   github.String("Commit message is valid"),
+				Summary: github.String(fmt.Sprintf("The message of %s follows the rules for this repository.", sha)),
+			},
+		}, nil
+	}
+	return githubapp.CheckResult{
+		Conclusion: githubapp.CheckConclusionFailure,
+		Output: &github.CheckRunOutput{
+			Title:   github.String(fmt.Sprintf("Found %d problems with the commit message", len(problems))),
+			Summary: github.String(strings.Join(problems, "\n")),
+		},
+	}, nil
+}
+
+func (c *CommitMessageCheck) loadConfig(ctx context.Context, client *github.Client, owner, repo, ref string) (CommitMessageConfig, error) {
+	config := RepositoryConfig{
+		CommitMessage: CommitMessageConfig{
+			MaxSubjectLength:  72,
+			ForbiddenPrefixes: []string{"WIP", "fixup!", "squash!"},
+		},
+	}
+
+	ac, err := c.Loader.LoadConfig(ctx, client, owner, repo, ref)
+	if err != nil {
+		return config.CommitMessage, err
+	}
+	if ac.IsUndefined() {
+		return config.CommitMessage, nil
+	}
+
+	if err := yaml.UnmarshalStrict(ac.Content, &config); err != nil {
+		return config.CommitMessage, errors.Wrapf(err, "failed to parse %s in %s", ac.Path, ac.Source)
+	}
+	return config.CommitMessage, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+type Config struct {
+	Server HTTPConfig       `yaml:"server"`
+	Github githubapp.Config `yaml:"github"`
+}
+
+type HTTPConfig struct {
+	Address string `yaml:"address"`
+	Port    int    `yaml:"port"`
+
+	QueueSize int `yaml:"queue_size"`
+	Workers   int `yaml:"workers"`
+}
+
+func ReadConfig(path string) (*Config, error) {
+	var c Config
+
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed reading server config file: %s", path)
+	}
+
+	if err := yaml.UnmarshalStrict(bytes, &c); err != nil {
+		return nil, errors.Wrap(err, "failed parsing configuration file")
+	}
+
+	if c.Server.QueueSize <= 0 {
+		c.Server.QueueSize = 100
+	}
+	if c.Server.Workers <= 0 {
+		c.Server.Workers = 4
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command checks-bot is an example app that reports checks on every commit.
+// It creates a "commit-message" check run for each check suite GitHub
+// requests, runs the check again when a user clicks "Re-run", and reads
+// per-repository settings from .github/checks-bot.yml.
+//
+// To run the app, update example/checks-bot/config.yml with appropriate
+// secrets, subscribe the app to "check_suite" and "check_run" events with
+// read and write access to checks and read access to contents, and run:
+//
+//	./godelw run checks-bot
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/palantir/go-githubapp/appconfig"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	config, err := ReadConfig("example/checks-bot/config.yml")
+	if err != nil {
+		panic(err)
+	}
+
+	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
+	zerolog.DefaultContextLogger = &logger
+
+	metricsRegistry := metrics.DefaultRegistry
+
+	cc, err := githubapp.NewDefaultCachingClientCreator(
+		config.Github,
+		githubapp.WithClientUserAgent("checks-bot/1.0.0"),
+		githubapp.WithClientTimeout(10*time.Second),
+		githubapp.WithClientMiddleware(
+			githubapp.ClientMetrics(metricsRegistry),
+		),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	checks := &CommitMessageCheck{
+		Loader: appconfig.NewLoader(
+			[]string{".github/checks-bot.yml"},
+			appconfig.WithOwnerDefault(".github", []string{"checks-bot.yml"}),
+		),
+	}
+
+	// Check runs take longer than GitHub waits for a webhook response, so
+	// handle events on a queue. Limit the queue space used by check_run
+	// events so that re-runs of single checks cannot delay new check suites.
+	scheduler := githubapp.QueueAsyncScheduler(
+		config.Server.QueueSize, config.Server.Workers,
+		githubapp.WithSchedulingMetrics(metricsRegistry),
+		githubapp.WithEventTypeQueueLimits(map[string]int{
+			githubapp.EventCheckRun: config.Server.QueueSize / 4,
+		}),
+	)
+
+	handler := githubapp.NewCheckRunHandler(cc, []string{CommitMessageCheckName}, checks.Run)
+
+	webhookHandler := githubapp.NewDefaultEventDispatcherWithOptions(config.Github, []githubapp.EventHandler{handler},
+		githubapp.WithScheduler(scheduler),
+	)
+
+	http.Handle(githubapp.DefaultWebhookRoute, webhookHandler)
+
+	addr := fmt.Sprintf("%s:%d", config.Server.Address, config.Server.Port)
+	logger.Info().Msgf("Starting server on %s...", addr)
+	err = http.ListenAndServe(addr, nil)
+	if err != nil {
+		panic(err)
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/google/go-github/v66/github"
 	"github.com/palantir/go-githubapp/githubapp"
@@ -33,7 +32,7 @@ type PRCommentHandler struct {
 }
 
 func (h *PRCommentHandler) Handles() []string {
-	return []string{"issue_comment"}
+	return githubapp.Handles(githubapp.EventIssueComment)
 }
 
 func (h *PRCommentHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
@@ -54,7 +53,7 @@ func (h *PRCommentHandler) Handle(ctx context.Context, eventType, deliveryID str
 	ctx, logger := githubapp.PreparePRContext(ctx, installationID, repo, event.GetIssue().GetNumber())
 
 	logger.Debug().Msgf("Event action is %s", event.GetAction())
-	if event.GetAction() != "created" {
+	if event.GetAction() != githubapp.ActionCreated {
 		return nil
 	}
 
@@ -68,7 +67,7 @@ func (h *PRCommentHandler) Handle(ctx context.Context, eventType, deliveryID str
 	author := event.GetComment().GetUser().GetLogin()
 	body := event.GetComment().GetBody()
 
-	if strings.HasSuffix(author, "[bot]") {
+	if githubapp.IsBot(event.GetComment().GetUser()) {
 		logger.Debug().Msg("Issue comment was created by a bot")
 		return nil
 	}
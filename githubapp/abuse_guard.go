@@ -0,0 +1,230 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultAbuseGuardLimit  = 10
+	DefaultAbuseGuardWindow = time.Minute
+)
+
+// AbuseGuardKeyFunc returns the key that an abuse guard counts events by. The
+// guard does not limit events for which the function returns the empty
+// string.
+type AbuseGuardKeyFunc func(eventType string, p *CommonPayload) string
+
+// SenderRepositoryKey is the default AbuseGuardKeyFunc. It counts events by
+// the sender and repository of the event, so one user triggering many events
+// in one repository does not affect other users or repositories.
+func SenderRepositoryKey(eventType string, p *CommonPayload) string {
+	if p.SenderLogin() == "" || p.Repository == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s", p.Repository.FullName, p.SenderLogin())
+}
+
+// AbuseGuardOption configures properties of an abuse guard.
+type AbuseGuardOption func(*abuseGuard)
+
+// WithAbuseGuardLimit sets the number of events with the same key that the
+// guard allows in each window. The defaults are DefaultAbuseGuardLimit and
+// DefaultAbuseGuardWindow.
+func WithAbuseGuardLimit(limit int, window time.Duration) AbuseGuardOption {
+	return func(g *abuseGuard) {
+		if limit > 0 {
+			g.limit = limit
+		}
+		if window > 0 {
+			g.window = window
+		}
+	}
+}
+
+// WithAbuseGuardKey sets the function that the guard uses to count events.
+// The default is SenderRepositoryKey.
+func WithAbuseGuardKey(fn AbuseGuardKeyFunc) AbuseGuardOption {
+	return func(g *abuseGuard) {
+		if fn != nil {
+			g.key = fn
+		}
+	}
+}
+
+// WithAbuseGuardReaction makes the guard add a reaction with the given
+// content, like ReactionConfused, to the comment, issue, or pull request
+// that first exceeds the limit in each window, so the sender knows the
+// application is ignoring them. The guard uses installation clients from cc.
+// Failures to add the reaction are logged and do not affect the guard.
+func WithAbuseGuardReaction(cc ClientCreator, content string) AbuseGuardOption {
+	return func(g *abuseGuard) {
+		g.cc = cc
+		g.reaction = content
+	}
+}
+
+// NewAbuseGuard returns an EventHandler that limits how often events of the
+// given types are processed for each key, by default each sender in each
+// repository. When a key exceeds the limit, the guard returns StopProcessing
+// until the window that started with the first event for the key ends. Use
+// it as the first handler in a HandlerChain to protect application handlers
+// from users who spam comments or commands:
+//
+//	githubapp.HandlerChain(githubapp.NewAbuseGuard([]string{"issue_comment"}), commands)
+//
+// Counts are kept in memory, so each instance of an application enforces the
+// limit separately.
+func NewAbuseGuard(eventTypes []string, opts ...AbuseGuardOption) EventHandler {
+	g := &abuseGuard{
+		eventTypes: eventTypes,
+		limit:      DefaultAbuseGuardLimit,
+		window:     DefaultAbuseGuardWindow,
+		key:        SenderRepositoryKey,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	g.counts = make(map[string]*abuseGuardCount)
+	return g
+}
+
+type abuseGuard struct {
+	eventTypes []string
+	limit      int
+	window     time.Duration
+	key        AbuseGuardKeyFunc
+
+	cc       ClientCreator
+	reaction string
+
+	mu     sync.Mutex
+	counts map[string]*abuseGuardCount
+	pruned time.Time
+}
+
+type abuseGuardCount struct {
+	start time.Time
+	n     int
+}
+
+func (g *abuseGuard) Handles() []string {
+	return g.eventTypes
+}
+
+func (g *abuseGuard) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	p, err := ParseCommonPayload(payload)
+	if err != nil {
+		return err
+	}
+
+	key := g.key(eventType, p)
+	if key == "" {
+		return nil
+	}
+
+	n := g.count(key, GetClock(ctx).Now())
+	if n <= g.limit {
+		return nil
+	}
+
+	if n == g.limit+1 {
+		zerolog.Ctx(ctx).Info().Msgf("Ignoring events for %q: more than %d events in %s", key, g.limit, g.window)
+		if g.reaction != "" {
+			if err := g.react(ctx, p, eventType, payload); err != nil {
+				zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to add abuse guard reaction")
+			}
+		}
+	}
+	return StopProcessing{Reason: fmt.Sprintf("rate limit exceeded for %q", key)}
+}
+
+// count increments and returns the number of events for key in the window
+// that contains now. A window starts with the first event for a key after
+// the previous window ended.
+func (g *abuseGuard) count(key string, now time.Time) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// remove expired windows at most once per window so keys that are never
+	// seen again do not accumulate
+	if now.Sub(g.pruned) >= g.window {
+		for k, c := range g.counts {
+			if now.Sub(c.start) >= g.window {
+				delete(g.counts, k)
+			}
+		}
+		g.pruned = now
+	}
+
+	c, ok := g.counts[key]
+	if !ok || now.Sub(c.start) >= g.window {
+		c = &abuseGuardCount{start: now}
+		g.counts[key] = c
+	}
+	c.n++
+	return c.n
+}
+
+func (g *abuseGuard) react(ctx context.Context, p *CommonPayload, eventType string, payload []byte) error {
+	var event struct {
+		Comment *struct {
+			ID int64 `json:"id"`
+		} `json:"comment"`
+		Issue *struct {
+			Number int `json:"number"`
+		} `json:"issue"`
+		PullRequest *struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrap(err, "failed to parse payload")
+	}
+
+	owner, repo := p.RepositoryOwner(), p.RepositoryName()
+
+	var target ReactionTarget
+	switch {
+	case eventType == "issue_comment" && event.Comment != nil:
+		target = IssueCommentReactionTarget(owner, repo, event.Comment.ID)
+	case eventType == "pull_request_review_comment" && event.Comment != nil:
+		target = PullRequestCommentReactionTarget(owner, repo, event.Comment.ID)
+	case eventType == "commit_comment" && event.Comment != nil:
+		target = CommitCommentReactionTarget(owner, repo, event.Comment.ID)
+	case event.Issue != nil:
+		target = IssueReactionTarget(owner, repo, event.Issue.Number)
+	case event.PullRequest != nil:
+		target = IssueReactionTarget(owner, repo, event.PullRequest.Number)
+	default:
+		return nil
+	}
+
+	client, err := g.cc.NewInstallationClient(p.InstallationID())
+	if err != nil {
+		return err
+	}
+	_, err = AddReaction(ctx, client, target, g.reaction)
+	return err
+}
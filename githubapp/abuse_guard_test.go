@@ -0,0 +1,107 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func abuseGuardPayload(sender string, commentID int64) []byte {
+	return []byte(fmt.Sprintf(`{
+		"action": "created",
+		"installation": {"id": 1},
+		"repository": {"name": "repo", "full_name": "owner/repo", "owner": {"login": "owner"}},
+		"sender": {"login": %q},
+		"comment": {"id": %d}
+	}`, sender, commentID))
+}
+
+func TestAbuseGuard(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("limitsEachKey", func(t *testing.T) {
+		g := NewAbuseGuard([]string{"issue_comment"}, WithAbuseGuardLimit(2, time.Minute))
+
+		for i := 0; i < 2; i++ {
+			if err := g.Handle(ctx, "issue_comment", "id", abuseGuardPayload("spammer", 1)); err != nil {
+				t.Fatalf("unexpected error for event %d: %v", i, err)
+			}
+		}
+
+		err := g.Handle(ctx, "issue_comment", "id", abuseGuardPayload("spammer", 1))
+		var stop StopProcessing
+		if !errors.As(err, &stop) {
+			t.Fatalf("expected StopProcessing, but got %v", err)
+		}
+
+		if err := g.Handle(ctx, "issue_comment", "id", abuseGuardPayload("someone-else", 1)); err != nil {
+			t.Errorf("unexpected error for different sender: %v", err)
+		}
+	})
+
+	t.Run("windowExpires", func(t *testing.T) {
+		clock := newTestClock()
+		ctx := WithClock(ctx, clock)
+		g := NewAbuseGuard([]string{"issue_comment"}, WithAbuseGuardLimit(1, time.Minute))
+
+		_ = g.Handle(ctx, "issue_comment", "id", abuseGuardPayload("spammer", 1))
+		if err := g.Handle(ctx, "issue_comment", "id", abuseGuardPayload("spammer", 1)); err == nil {
+			t.Fatal("expected error, but got nil")
+		}
+
+		clock.Advance(time.Minute)
+		if err := g.Handle(ctx, "issue_comment", "id", abuseGuardPayload("spammer", 1)); err != nil {
+			t.Errorf("unexpected error after window expired: %v", err)
+		}
+	})
+
+	t.Run("emptyKeyIsNotLimited", func(t *testing.T) {
+		g := NewAbuseGuard([]string{"issue_comment"}, WithAbuseGuardLimit(1, time.Minute))
+		for i := 0; i < 3; i++ {
+			if err := g.Handle(ctx, "issue_comment", "id", []byte(`{"action": "created"}`)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+
+	t.Run("reactsOncePerWindow", func(t *testing.T) {
+		var reactions []string
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /repos/owner/repo/issues/comments/{id}/reactions", func(w http.ResponseWriter, r *http.Request) {
+			reactions = append(reactions, r.PathValue("id"))
+			_, _ = io.WriteString(w, `{"id": 1, "content": "confused"}`)
+		})
+		cc := newStaticClientCreator(t, mux)
+
+		g := NewAbuseGuard([]string{"issue_comment"},
+			WithAbuseGuardLimit(1, time.Minute),
+			WithAbuseGuardReaction(cc, ReactionConfused),
+		)
+		for i := int64(1); i <= 3; i++ {
+			_ = g.Handle(ctx, "issue_comment", "id", abuseGuardPayload("spammer", i))
+		}
+
+		if len(reactions) != 1 || reactions[0] != "2" {
+			t.Errorf("incorrect reactions: expected [2], actual %v", reactions)
+		}
+	})
+}
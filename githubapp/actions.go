@@ -0,0 +1,123 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxLogRedirects is the number of redirects followed when requesting the
+	// download URL for workflow logs
+	maxLogRedirects = 3
+
+	// defaultJobLogsTimeout is the timeout for downloading workflow logs when
+	// the caller does not provide an HTTP client
+	defaultJobLogsTimeout = 5 * time.Minute
+)
+
+// DispatchWorkflow triggers a workflow_dispatch event for the workflow
+// defined in workflowFile (like "build.yml") on ref in owner/repo. The
+// workflow must have a workflow_dispatch trigger that declares all inputs.
+func DispatchWorkflow(ctx context.Context, client *github.Client, owner, repo, workflowFile, ref string, inputs map[string]interface{}) error {
+	_, err := client.Actions.CreateWorkflowDispatchEventByFileName(ctx, owner, repo, workflowFile, github.CreateWorkflowDispatchEventRequest{
+		Ref:    ref,
+		Inputs: inputs,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to dispatch workflow %s on %s", workflowFile, ref)
+	}
+	return nil
+}
+
+// RerunFailedJobs re-runs the failed jobs and their dependents in a workflow
+// run.
+func RerunFailedJobs(ctx context.Context, client *github.Client, owner, repo string, runID int64) error {
+	if _, err := client.Actions.RerunFailedJobsByID(ctx, owner, repo, runID); err != nil {
+		return errors.Wrapf(err, "failed to re-run failed jobs for workflow run %d", runID)
+	}
+	return nil
+}
+
+// OpenJobLogs returns a reader for the plain text logs of a workflow job.
+// GitHub serves logs from a temporary download URL that includes its own
+// authorization, so the logs are downloaded with httpClient instead of the
+// client, which must not send GitHub credentials to the download host. Use
+// a client with the transport, middleware, and timeout the application uses
+// for other requests. If httpClient is nil, OpenJobLogs uses a client with
+// the default transport and a 5 minute timeout. Callers must close the
+// reader.
+func OpenJobLogs(ctx context.Context, client *github.Client, httpClient *http.Client, owner, repo string, jobID int64) (io.ReadCloser, error) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultJobLogsTimeout}
+	}
+
+	u, _, err := client.Actions.GetWorkflowJobLogs(ctx, owner, repo, jobID, maxLogRedirects)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get log URL for job %d", jobID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create log request")
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download logs for job %d", jobID)
+	}
+	if res.StatusCode != http.StatusOK {
+		closeBody(res.Body)
+		return nil, errors.Errorf("failed to download logs for job %d: unexpected status code %d", jobID, res.StatusCode)
+	}
+	return res.Body, nil
+}
+
+// GetWorkflowRunCheckSuiteID returns the ID of the check suite for a
+// workflow run. For workflow_run events, the ID is also available directly
+// from the payload.
+func GetWorkflowRunCheckSuiteID(ctx context.Context, client *github.Client, owner, repo string, runID int64) (int64, error) {
+	run, _, err := client.Actions.GetWorkflowRunByID(ctx, owner, repo, runID)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get workflow run %d", runID)
+	}
+	return run.GetCheckSuiteID(), nil
+}
+
+// GetWorkflowJobCheckSuiteID returns the ID of the check suite for the
+// workflow run that contains the job in a workflow_job event.
+func GetWorkflowJobCheckSuiteID(ctx context.Context, client *github.Client, event *github.WorkflowJobEvent) (int64, error) {
+	repo := event.GetRepo()
+	return GetWorkflowRunCheckSuiteID(ctx, client, repo.GetOwner().GetLogin(), repo.GetName(), event.GetWorkflowJob().GetRunID())
+}
+
+// WorkflowJobCheckRunID returns the ID of the check run for a workflow job,
+// parsed from the job's check run URL. It returns 0 if the job has no check
+// run URL.
+func WorkflowJobCheckRunID(job *github.WorkflowJob) int64 {
+	id, err := strconv.ParseInt(path.Base(job.GetCheckRunURL()), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
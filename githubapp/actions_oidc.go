@@ -0,0 +1,490 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultActionsOIDCIssuer is the issuer of OIDC tokens for GitHub
+	// Actions workflows on github.com. On GitHub Enterprise Server, the
+	// issuer is "https://HOSTNAME/_services/token".
+	DefaultActionsOIDCIssuer = "https://token.actions.githubusercontent.com"
+
+	// DefaultActionsOIDCKeyTTL is the default time an ActionsOIDCVerifier
+	// caches the issuer's signing keys.
+	DefaultActionsOIDCKeyTTL = time.Hour
+
+	// actionsOIDCRefreshInterval limits how often the verifier fetches keys
+	// when tokens reference an unknown key ID
+	actionsOIDCRefreshInterval = time.Minute
+
+	// actionsOIDCClockSkew allows for clock drift between GitHub and the
+	// application when checking token times
+	actionsOIDCClockSkew = time.Minute
+)
+
+// ActionsOIDCClaims are the claims of an OIDC token issued to a GitHub
+// Actions workflow. See the GitHub documentation on security hardening with
+// OpenID Connect for the meaning of each claim.
+type ActionsOIDCClaims struct {
+	jwt.RegisteredClaims
+
+	Repository        string `json:"repository"`
+	RepositoryID      string `json:"repository_id"`
+	RepositoryOwner   string `json:"repository_owner"`
+	RepositoryOwnerID string `json:"repository_owner_id"`
+	Ref               string `json:"ref"`
+	RefType           string `json:"ref_type"`
+	SHA               string `json:"sha"`
+	Environment       string `json:"environment"`
+	EventName         string `json:"event_name"`
+	Actor             string `json:"actor"`
+	Workflow          string `json:"workflow"`
+	WorkflowRef       string `json:"workflow_ref"`
+	JobWorkflowRef    string `json:"job_workflow_ref"`
+	RunID             string `json:"run_id"`
+	RunAttempt        string `json:"run_attempt"`
+}
+
+// ActionsOIDCOption configures properties of an ActionsOIDCVerifier.
+type ActionsOIDCOption func(*ActionsOIDCVerifier)
+
+// WithActionsOIDCIssuer sets the issuer of accepted tokens. The default is
+// DefaultActionsOIDCIssuer. The verifier loads signing keys from the OpenID
+// configuration of the issuer.
+func WithActionsOIDCIssuer(issuer string) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		v.issuer = strings.TrimSuffix(issuer, "/")
+	}
+}
+
+// WithActionsOIDCHTTPClient sets the client used to load signing keys. The
+// default is http.DefaultClient.
+func WithActionsOIDCHTTPClient(client *http.Client) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		if client != nil {
+			v.client = client
+		}
+	}
+}
+
+// WithActionsOIDCKeyTTL sets how long the verifier caches signing keys. The
+// default is DefaultActionsOIDCKeyTTL. Keys are also reloaded when a token
+// uses an unknown key, at most once a minute.
+func WithActionsOIDCKeyTTL(ttl time.Duration) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		v.keyTTL = ttl
+	}
+}
+
+// WithActionsOIDCRepositories only accepts tokens from workflows in the
+// repositories, in "owner/name" format. Names are compared without regard
+// to case. Because a name can refer to a different repository after the
+// original is renamed or deleted, prefer WithActionsOIDCRepositoryIDs.
+func WithActionsOIDCRepositories(repositories ...string) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		v.repositories = append(v.repositories, repositories...)
+	}
+}
+
+// WithActionsOIDCRepositoryIDs only accepts tokens from workflows in the
+// repositories with the IDs, compared with the repository_id claim.
+func WithActionsOIDCRepositoryIDs(ids ...int64) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		for _, id := range ids {
+			v.repositoryIDs = append(v.repositoryIDs, strconv.FormatInt(id, 10))
+		}
+	}
+}
+
+// WithActionsOIDCOwnerIDs only accepts tokens from workflows in repositories
+// owned by the users or organizations with the IDs, compared with the
+// repository_owner_id claim.
+func WithActionsOIDCOwnerIDs(ids ...int64) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		for _, id := range ids {
+			v.ownerIDs = append(v.ownerIDs, strconv.FormatInt(id, 10))
+		}
+	}
+}
+
+// WithActionsOIDCRefs only accepts tokens from workflows that run for a
+// matching ref, like "refs/heads/main" or "refs/tags/*". In patterns, "*"
+// matches any sequence of characters, including "/".
+func WithActionsOIDCRefs(patterns ...string) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		v.refs = append(v.refs, patterns...)
+	}
+}
+
+// WithActionsOIDCWorkflows only accepts tokens from jobs defined by a
+// matching workflow, compared with the job_workflow_ref claim, like
+// "octo-org/deploy/.github/workflows/deploy.yml@refs/heads/main". For
+// reusable workflows, the claim names the reusable workflow, not the caller.
+// In patterns, "*" matches any sequence of characters, including "/".
+func WithActionsOIDCWorkflows(patterns ...string) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		v.workflows = append(v.workflows, patterns...)
+	}
+}
+
+// WithActionsOIDCClaimCheck adds a function that checks the claims of tokens
+// after all other checks pass, like requiring an environment. If it returns
+// an error, the token is rejected.
+func WithActionsOIDCClaimCheck(check func(*ActionsOIDCClaims) error) ActionsOIDCOption {
+	return func(v *ActionsOIDCVerifier) {
+		if check != nil {
+			v.checks = append(v.checks, check)
+		}
+	}
+}
+
+// ActionsOIDCVerifier verifies OIDC tokens issued to GitHub Actions
+// workflows, so services built with this package can authenticate requests
+// from workflows as well as webhooks. It checks the signature, issuer,
+// audience, and lifetime of tokens and the repository, owner, ref, and
+// workflow restrictions.
+//
+// Any workflow on GitHub can request a token with any audience, so the
+// audience only protects against tokens meant for other services. The
+// repository or owner restrictions decide which workflows are trusted.
+//
+// An ActionsOIDCVerifier is safe for concurrent use.
+type ActionsOIDCVerifier struct {
+	audience string
+	issuer   string
+	client   *http.Client
+	keyTTL   time.Duration
+
+	repositories  []string
+	repositoryIDs []string
+	ownerIDs      []string
+	refs          []string
+	workflows     []string
+	checks        []func(*ActionsOIDCClaims) error
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	loadedAt  time.Time
+	refreshAt time.Time
+	loading   *oidcKeyLoad
+}
+
+// oidcKeyLoad is an in-progress load of signing keys, shared by requests
+// that need keys at the same time.
+type oidcKeyLoad struct {
+	done chan struct{}
+	err  error
+}
+
+// NewActionsOIDCVerifier creates a verifier that accepts tokens with the
+// audience, which workflows set when requesting tokens, for example with the
+// audience input of actions/github-script's core.getIDToken. Use an audience
+// specific to the service, not the default audience of the token.
+//
+// The audience does not limit which workflows can obtain tokens, so
+// NewActionsOIDCVerifier returns an error if the audience is empty or if
+// none of WithActionsOIDCRepositories, WithActionsOIDCRepositoryIDs, or
+// WithActionsOIDCOwnerIDs is set.
+func NewActionsOIDCVerifier(audience string, opts ...ActionsOIDCOption) (*ActionsOIDCVerifier, error) {
+	if audience == "" {
+		return nil, errors.New("OIDC verifier requires an audience")
+	}
+
+	v := &ActionsOIDCVerifier{
+		audience: audience,
+		issuer:   DefaultActionsOIDCIssuer,
+		client:   http.DefaultClient,
+		keyTTL:   DefaultActionsOIDCKeyTTL,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	if len(v.repositories) == 0 && len(v.repositoryIDs) == 0 && len(v.ownerIDs) == 0 {
+		return nil, errors.New("OIDC verifier requires a repository or owner restriction")
+	}
+	return v, nil
+}
+
+// Verify parses and verifies a token and returns its claims. Times are
+// checked with the clock from the context.
+func (v *ActionsOIDCVerifier) Verify(ctx context.Context, token string) (*ActionsOIDCClaims, error) {
+	var claims ActionsOIDCClaims
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256"}), jwt.WithoutClaimsValidation())
+	if _, err := parser.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.key(ctx, kid)
+	}); err != nil {
+		return nil, errors.Wrap(err, "invalid token")
+	}
+
+	if err := v.checkClaims(GetClock(ctx).Now(), &claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (v *ActionsOIDCVerifier) checkClaims(now time.Time, c *ActionsOIDCClaims) error {
+	switch {
+	case !c.VerifyIssuer(v.issuer, true):
+		return errors.Errorf("token has incorrect issuer %q", c.Issuer)
+	case !c.VerifyAudience(v.audience, true):
+		return errors.Errorf("token has incorrect audience %q", c.Audience)
+	case !c.VerifyExpiresAt(now.Add(-actionsOIDCClockSkew), true):
+		return errors.New("token is expired")
+	case !c.VerifyNotBefore(now.Add(actionsOIDCClockSkew), false):
+		return errors.New("token is not valid yet")
+	}
+
+	if len(v.repositories) > 0 && !containsFold(v.repositories, c.Repository) {
+		return errors.Errorf("repository %q is not allowed", c.Repository)
+	}
+	if len(v.repositoryIDs) > 0 && !slices.Contains(v.repositoryIDs, c.RepositoryID) {
+		return errors.Errorf("repository ID %q is not allowed", c.RepositoryID)
+	}
+	if len(v.ownerIDs) > 0 && !slices.Contains(v.ownerIDs, c.RepositoryOwnerID) {
+		return errors.Errorf("repository owner ID %q is not allowed", c.RepositoryOwnerID)
+	}
+	if len(v.refs) > 0 && !matchesAny(v.refs, c.Ref) {
+		return errors.Errorf("ref %q is not allowed", c.Ref)
+	}
+	if len(v.workflows) > 0 && !matchesAny(v.workflows, c.JobWorkflowRef) {
+		return errors.Errorf("workflow %q is not allowed", c.JobWorkflowRef)
+	}
+
+	for _, check := range v.checks {
+		if err := check(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if matchWildcard(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWildcard returns true if value matches pattern, where "*" in the
+// pattern matches any sequence of characters.
+func matchWildcard(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	first, last := parts[0], parts[len(parts)-1]
+	if !strings.HasPrefix(value, first) || !strings.HasSuffix(value[len(first):], last) {
+		return false
+	}
+
+	value = value[len(first) : len(value)-len(last)]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return true
+}
+
+// key returns the signing key with the ID, loading keys from the issuer if
+// they are stale or if the ID is unknown. Requests that need keys while a
+// load is in progress wait for it instead of starting another one.
+func (v *ActionsOIDCVerifier) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	now := GetClock(ctx).Now()
+
+	v.mu.Lock()
+	key, ok := v.keys[kid]
+	fresh := !v.loadedAt.IsZero() && now.Sub(v.loadedAt) <= v.keyTTL
+	switch {
+	case ok && fresh:
+		v.mu.Unlock()
+		return key, nil
+	case fresh && now.Before(v.refreshAt):
+		v.mu.Unlock()
+		return nil, errors.Errorf("unknown signing key %q", kid)
+	}
+
+	load := v.loading
+	if load == nil {
+		load = &oidcKeyLoad{done: make(chan struct{})}
+		v.loading = load
+		v.mu.Unlock()
+
+		v.load(ctx, now, load)
+	} else {
+		v.mu.Unlock()
+
+		select {
+		case <-load.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.keys[kid]; ok {
+		if load.err != nil {
+			// keep using known keys if the issuer is temporarily unavailable
+			zerolog.Ctx(ctx).Warn().Err(load.err).Msg("Failed to refresh OIDC signing keys")
+		}
+		return key, nil
+	}
+	if load.err != nil {
+		return nil, load.err
+	}
+	return nil, errors.Errorf("unknown signing key %q", kid)
+}
+
+// load fetches keys from the issuer without holding the lock and then stores
+// them and completes the load.
+func (v *ActionsOIDCVerifier) load(ctx context.Context, now time.Time, load *oidcKeyLoad) {
+	keys, err := v.loadKeys(ctx)
+
+	v.mu.Lock()
+	if err == nil {
+		v.keys = keys
+		v.loadedAt = now
+		v.refreshAt = now.Add(actionsOIDCRefreshInterval)
+	}
+	load.err = err
+	v.loading = nil
+	v.mu.Unlock()
+
+	close(load.done)
+}
+
+func (v *ActionsOIDCVerifier) loadKeys(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var config struct {
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := v.getJSON(ctx, v.issuer+"/.well-known/openid-configuration", &config); err != nil {
+		return nil, errors.Wrap(err, "failed to load OpenID configuration")
+	}
+	if config.JWKSURI == "" {
+		return nil, errors.New("OpenID configuration does not contain a JWKS URI")
+	}
+
+	var jwks struct {
+		Keys []struct {
+			KeyType string `json:"kty"`
+			KeyID   string `json:"kid"`
+			N       string `json:"n"`
+			E       string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(ctx, config.JWKSURI, &jwks); err != nil {
+		return nil, errors.Wrap(err, "failed to load signing keys")
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.KeyType != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid modulus for key %q", k.KeyID)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid exponent for key %q", k.KeyID)
+		}
+		keys[k.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func (v *ActionsOIDCVerifier) getJSON(ctx context.Context, url string, value interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %d from %s", res.StatusCode, url)
+	}
+	return json.NewDecoder(res.Body).Decode(value)
+}
+
+type actionsOIDCClaimsKey struct{}
+
+// GetActionsOIDCClaims returns the claims stored in the context by the
+// middleware of an ActionsOIDCVerifier, or nil if the context does not
+// contain claims.
+func GetActionsOIDCClaims(ctx context.Context) *ActionsOIDCClaims {
+	c, _ := ctx.Value(actionsOIDCClaimsKey{}).(*ActionsOIDCClaims)
+	return c
+}
+
+// Middleware returns an http.Handler that verifies the bearer token in the
+// Authorization header of each request before calling next. Requests without
+// a valid token receive a 401 response. Handlers read the claims of the token
+// with GetActionsOIDCClaims.
+func (v *ActionsOIDCVerifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "Missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		claims, err := v.Verify(ctx, token)
+		if err != nil {
+			zerolog.Ctx(ctx).Info().Err(err).Msg("Rejected request with invalid OIDC token")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, actionsOIDCClaimsKey{}, claims)))
+	})
+}
@@ -0,0 +1,309 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newTestOIDCIssuer(t *testing.T, key *rsa.PrivateKey) (*httptest.Server, *int) {
+	var loads int
+
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"jwks_uri": srv.URL + "/.well-known/jwks"})
+	})
+	mux.HandleFunc("GET /.well-known/jwks", func(w http.ResponseWriter, r *http.Request) {
+		loads++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	return srv, &loads
+}
+
+func TestActionsOIDCVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	srv, loads := newTestOIDCIssuer(t, key)
+
+	clock := newTestClock()
+	ctx := WithClock(context.Background(), clock)
+
+	sign := func(kid string, modify func(*ActionsOIDCClaims)) string {
+		claims := ActionsOIDCClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    srv.URL,
+				Audience:  jwt.ClaimStrings{"deploy-service"},
+				IssuedAt:  jwt.NewNumericDate(clock.Now()),
+				ExpiresAt: jwt.NewNumericDate(clock.Now().Add(5 * time.Minute)),
+			},
+			Repository:        "octo/app",
+			RepositoryID:      "42",
+			RepositoryOwnerID: "7",
+			Ref:               "refs/heads/main",
+			JobWorkflowRef:    "octo/app/.github/workflows/deploy.yml@refs/heads/main",
+		}
+		if modify != nil {
+			modify(&claims)
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return s
+	}
+
+	v, err := NewActionsOIDCVerifier("deploy-service",
+		WithActionsOIDCIssuer(srv.URL),
+		WithActionsOIDCHTTPClient(srv.Client()),
+		WithActionsOIDCRepositories("Octo/App"),
+		WithActionsOIDCRepositoryIDs(42),
+		WithActionsOIDCOwnerIDs(7),
+		WithActionsOIDCRefs("refs/heads/*"),
+		WithActionsOIDCWorkflows("octo/app/.github/workflows/deploy.yml@*"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating verifier: %v", err)
+	}
+
+	tests := map[string]struct {
+		Token string
+		Err   bool
+	}{
+		"valid": {
+			Token: sign("key-1", nil),
+		},
+		"wrongAudience": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.Audience = jwt.ClaimStrings{"other"} }),
+			Err:   true,
+		},
+		"wrongIssuer": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.Issuer = DefaultActionsOIDCIssuer }),
+			Err:   true,
+		},
+		"expired": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.ExpiresAt = jwt.NewNumericDate(clock.Now().Add(-time.Hour)) }),
+			Err:   true,
+		},
+		"wrongRepository": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.Repository = "octo/other" }),
+			Err:   true,
+		},
+		"wrongRepositoryID": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.RepositoryID = "43" }),
+			Err:   true,
+		},
+		"wrongOwnerID": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.RepositoryOwnerID = "8" }),
+			Err:   true,
+		},
+		"wrongRef": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.Ref = "refs/tags/v1" }),
+			Err:   true,
+		},
+		"nestedBranch": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.Ref = "refs/heads/feature/oidc" }),
+		},
+		"wrongWorkflow": {
+			Token: sign("key-1", func(c *ActionsOIDCClaims) { c.JobWorkflowRef = "octo/app/.github/workflows/test.yml@refs/heads/main" }),
+			Err:   true,
+		},
+		"unknownKey": {
+			Token: sign("key-2", nil),
+			Err:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			claims, err := v.Verify(ctx, test.Token)
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claims.Repository != "octo/app" {
+				t.Errorf("incorrect repository: %s", claims.Repository)
+			}
+		})
+	}
+
+	if *loads != 1 {
+		t.Errorf("expected keys to load once, but loaded %d times", *loads)
+	}
+
+	// unknown key IDs reload keys after the refresh interval
+	clock.Advance(2 * time.Minute)
+	if _, err := v.Verify(ctx, sign("key-2", nil)); err == nil {
+		t.Error("expected error for unknown key, but got nil")
+	}
+	if *loads != 2 {
+		t.Errorf("expected keys to load twice, but loaded %d times", *loads)
+	}
+
+	t.Run("middleware", func(t *testing.T) {
+		h := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if GetActionsOIDCClaims(r.Context()) == nil {
+				t.Error("expected claims in request context")
+			}
+		}))
+
+		for token, status := range map[string]int{
+			"":                 http.StatusUnauthorized,
+			"invalid":          http.StatusUnauthorized,
+			sign("key-1", nil): http.StatusOK,
+		} {
+			req := httptest.NewRequest(http.MethodPost, "/deploy", nil).WithContext(ctx)
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != status {
+				t.Errorf("incorrect status: expected %d, actual %d", status, w.Code)
+			}
+		}
+	})
+}
+
+func TestNewActionsOIDCVerifierRequiresRestrictions(t *testing.T) {
+	tests := map[string]struct {
+		Audience string
+		Opts     []ActionsOIDCOption
+		Err      bool
+	}{
+		"noAudience": {
+			Opts: []ActionsOIDCOption{WithActionsOIDCOwnerIDs(7)},
+			Err:  true,
+		},
+		"noRestriction": {
+			Audience: "deploy-service",
+			Opts:     []ActionsOIDCOption{WithActionsOIDCRefs("refs/heads/main")},
+			Err:      true,
+		},
+		"repository": {
+			Audience: "deploy-service",
+			Opts:     []ActionsOIDCOption{WithActionsOIDCRepositories("octo/app")},
+		},
+		"repositoryID": {
+			Audience: "deploy-service",
+			Opts:     []ActionsOIDCOption{WithActionsOIDCRepositoryIDs(42)},
+		},
+		"ownerID": {
+			Audience: "deploy-service",
+			Opts:     []ActionsOIDCOption{WithActionsOIDCOwnerIDs(7)},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewActionsOIDCVerifier(test.Audience, test.Opts...)
+			if test.Err && err == nil {
+				t.Fatal("expected error, but got nil")
+			}
+			if !test.Err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestActionsOIDCVerifierConcurrentLoad(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	srv, loads := newTestOIDCIssuer(t, key)
+
+	release := make(chan struct{})
+	client := srv.Client()
+	base := client.Transport
+	client.Transport = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		<-release
+		return base.RoundTrip(r)
+	})
+
+	v, err := NewActionsOIDCVerifier("deploy-service",
+		WithActionsOIDCIssuer(srv.URL),
+		WithActionsOIDCHTTPClient(client),
+		WithActionsOIDCOwnerIDs(7),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error creating verifier: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := v.key(context.Background(), "key-1"); err != nil {
+				t.Errorf("unexpected error loading key: %v", err)
+			}
+		}()
+	}
+
+	// requests that start while keys are loading do not block on the lock
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for {
+		v.mu.Lock()
+		loading := v.loading != nil
+		v.mu.Unlock()
+		if loading {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := v.key(ctx, "key-1"); err == nil {
+		t.Error("expected error for canceled context, but got nil")
+	}
+
+	close(release)
+	wg.Wait()
+
+	if *loads != 1 {
+		t.Errorf("expected keys to load once, but loaded %d times", *loads)
+	}
+}
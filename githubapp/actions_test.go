@@ -0,0 +1,95 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestOpenJobLogs(t *testing.T) {
+	logs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("log download sent credentials: %q", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Test-Client") != "true" {
+			t.Error("log download did not use the provided HTTP client")
+		}
+		_, _ = io.WriteString(w, "step 1\nstep 2\n")
+	}))
+	defer logs.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/actions/jobs/7/logs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, logs.URL+"/job-7.txt", http.StatusFound)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	httpClient := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.Header.Set("X-Test-Client", "true")
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+
+	r, err := OpenJobLogs(context.Background(), cc.client, httpClient, "octo", "repo", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error reading logs: %v", err)
+	}
+	if string(b) != "step 1\nstep 2\n" {
+		t.Errorf("incorrect logs: %q", b)
+	}
+}
+
+func TestWorkflowJobCheckRunID(t *testing.T) {
+	tests := map[string]struct {
+		URL string
+		ID  int64
+	}{
+		"valid": {
+			URL: "https://api.github.com/repos/test/repo/check-runs/399444496",
+			ID:  399444496,
+		},
+		"missing": {
+			URL: "",
+			ID:  0,
+		},
+		"invalid": {
+			URL: "https://api.github.com/repos/test/repo/check-runs/",
+			ID:  0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			job := &github.WorkflowJob{}
+			if test.URL != "" {
+				job.CheckRunURL = &test.URL
+			}
+			if id := WorkflowJobCheckRunID(job); id != test.ID {
+				t.Errorf("incorrect check run ID: expected %d, actual %d", test.ID, id)
+			}
+		})
+	}
+}
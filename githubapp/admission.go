@@ -0,0 +1,99 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"strings"
+)
+
+// AdmissionPolicy restricts the owners, repositories, and senders whose
+// events an app processes. Owners are organization or user logins and
+// repositories are full names, like "octo-org/repo". Matching ignores case.
+//
+// An event is admitted if it matches the allow lists and no deny list. When
+// AllowOwners or AllowRepositories is set, the event's owner must be in
+// AllowOwners or its repository must be in AllowRepositories. When
+// AllowSenders is set, the sender must also be in AllowSenders. Events that
+// do not identify the required owner, repository, or sender, like
+// installation events for user accounts, are rejected by the allow lists.
+type AdmissionPolicy struct {
+	AllowOwners       []string `yaml:"allow_owners" json:"allowOwners"`
+	AllowRepositories []string `yaml:"allow_repositories" json:"allowRepositories"`
+	AllowSenders      []string `yaml:"allow_senders" json:"allowSenders"`
+
+	DenyOwners       []string `yaml:"deny_owners" json:"denyOwners"`
+	DenyRepositories []string `yaml:"deny_repositories" json:"denyRepositories"`
+	DenySenders      []string `yaml:"deny_senders" json:"denySenders"`
+}
+
+// IsZero returns true if the policy admits all events.
+func (p AdmissionPolicy) IsZero() bool {
+	return len(p.AllowOwners) == 0 && len(p.AllowRepositories) == 0 && len(p.AllowSenders) == 0 &&
+		len(p.DenyOwners) == 0 && len(p.DenyRepositories) == 0 && len(p.DenySenders) == 0
+}
+
+// Admits returns true if the policy admits an event with the envelope
+// returned by PeekEnvelope. The owner of the event is the owner of its
+// repository or, for events without a repository, its organization.
+func (p AdmissionPolicy) Admits(e Envelope) bool {
+	repo := e.RepositoryFullName
+	owner := e.OrganizationLogin
+	if i := strings.IndexByte(repo, '/'); i > 0 {
+		owner = repo[:i]
+	}
+
+	if containsFold(p.DenyOwners, owner) || containsFold(p.DenyRepositories, repo) || containsFold(p.DenySenders, e.SenderLogin) {
+		return false
+	}
+	if len(p.AllowOwners) > 0 || len(p.AllowRepositories) > 0 {
+		if !containsFold(p.AllowOwners, owner) && !containsFold(p.AllowRepositories, repo) {
+			return false
+		}
+	}
+	if len(p.AllowSenders) > 0 && !containsFold(p.AllowSenders, e.SenderLogin) {
+		return false
+	}
+	return true
+}
+
+// WithAdmissionPolicy sets a policy that the dispatcher checks for every
+// valid event before it archives the delivery, checks event filters, or
+// schedules a handler, so no handler runs and no installation token is
+// created for rejected events. The dispatcher responds to rejected events as
+// if no handler exists for them. Deployments that serve a fixed set of
+// tenants can load the policy from Config.Admission. Replays are checked
+// like other deliveries. Events with payloads that the dispatcher cannot read
+// for the policy are rejected as invalid.
+func WithAdmissionPolicy(policy AdmissionPolicy) DispatcherOption {
+	return func(d *eventDispatcher) {
+		if policy.IsZero() {
+			d.admission = nil
+			return
+		}
+		d.admission = &policy
+	}
+}
+
+func containsFold(values []string, v string) bool {
+	if v == "" {
+		return false
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdmissionPolicy(t *testing.T) {
+	policy := AdmissionPolicy{
+		AllowOwners:       []string{"Octo-Org"},
+		AllowRepositories: []string{"partner/shared"},
+		DenyRepositories:  []string{"octo-org/secret"},
+		DenySenders:       []string{"mallory"},
+	}
+
+	tests := map[string]struct {
+		Envelope Envelope
+		Admitted bool
+	}{
+		"allowedOwner": {
+			Envelope: Envelope{RepositoryFullName: "octo-org/repo", SenderLogin: "octocat"},
+			Admitted: true,
+		},
+		"allowedOrganization": {
+			Envelope: Envelope{OrganizationLogin: "octo-org"},
+			Admitted: true,
+		},
+		"allowedRepository": {
+			Envelope: Envelope{RepositoryFullName: "partner/shared", OrganizationLogin: "partner"},
+			Admitted: true,
+		},
+		"otherRepository": {
+			Envelope: Envelope{RepositoryFullName: "partner/private", OrganizationLogin: "partner"},
+			Admitted: false,
+		},
+		"deniedRepository": {
+			Envelope: Envelope{RepositoryFullName: "octo-org/secret"},
+			Admitted: false,
+		},
+		"deniedSender": {
+			Envelope: Envelope{RepositoryFullName: "octo-org/repo", SenderLogin: "Mallory"},
+			Admitted: false,
+		},
+		"noOwner": {
+			Envelope: Envelope{InstallationID: 1},
+			Admitted: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if admitted := policy.Admits(test.Envelope); admitted != test.Admitted {
+				t.Errorf("incorrect result: expected %t, actual %t", test.Admitted, admitted)
+			}
+		})
+	}
+
+	if !(AdmissionPolicy{DenySenders: []string{"mallory"}}).Admits(Envelope{InstallationID: 1}) {
+		t.Error("expected policy without allow lists to admit events without an owner")
+	}
+}
+
+func TestDispatcherAdmissionPolicy(t *testing.T) {
+	h := &TestEventHandler{Types: []string{"push"}}
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithAdmissionPolicy(AdmissionPolicy{
+		AllowOwners: []string{"octo-org"},
+	}))
+
+	// rejected events get the response for events without a handler
+	// and events the policy cannot check are rejected as invalid
+	expected := map[string]int{
+		`{"repository": {"full_name": "octo-org/repo"}}`: http.StatusOK,
+		`{"repository": {"full_name": "other/repo"}}`:    http.StatusAccepted,
+		`{"repository": "octo-org/repo"}`:                http.StatusBadRequest,
+	}
+	for payload, status := range expected {
+		req, err := NewDeliveryRequest(context.Background(), DefaultWebhookRoute, testHookSecret, Delivery{
+			EventType:  "push",
+			DeliveryID: "admission",
+			Payload:    []byte(payload),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating request: %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		d.ServeHTTP(w, req)
+		if w.Code != status {
+			t.Errorf("incorrect status for %s: expected %d, actual %d", payload, status, w.Code)
+		}
+	}
+
+	if h.Count != 1 {
+		t.Errorf("expected 1 handled event, but got %d", h.Count)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	requestIDHeader = "X-GitHub-Request-Id"
+)
+
+// APIError is an error response from the GitHub API. It is returned by
+// ClassifyError for error responses that do not have a more specific type.
+type APIError struct {
+	StatusCode int
+	Message    string
+
+	// RequestID is the value of the X-GitHub-Request-Id header, which GitHub
+	// support uses to find requests.
+	RequestID string
+
+	// DocumentationURL is the link to the GitHub documentation for the error,
+	// if the response included one.
+	DocumentationURL string
+
+	// Cause is the original error returned by the client.
+	Cause error
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID == "" {
+		return e.Cause.Error()
+	}
+	return fmt.Sprintf("%v [request ID: %s]", e.Cause, e.RequestID)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Cause
+}
+
+func (e *APIError) apiError() *APIError {
+	return e
+}
+
+// NotFoundError is returned by ClassifyError for 404 responses. GitHub also
+// returns 404 for private resources that the client cannot access.
+type NotFoundError struct {
+	APIError
+}
+
+// ForbiddenError is returned by ClassifyError for 403 responses that are not
+// caused by rate limits.
+type ForbiddenError struct {
+	APIError
+}
+
+// SecondaryRateLimitError is returned by ClassifyError when GitHub rejects a
+// request because of a secondary rate limit, also called an abuse rate limit.
+// Use RetryDelay to get the time to wait before trying again.
+type SecondaryRateLimitError struct {
+	APIError
+
+	// RetryAfter is how long GitHub asked the client to wait before trying
+	// again. It is zero if GitHub did not provide a value.
+	RetryAfter time.Duration
+}
+
+// UnprocessableError is returned by ClassifyError for 422 responses, which
+// GitHub uses for validation failures.
+type UnprocessableError struct {
+	APIError
+
+	// Errors contains details about the fields that failed validation.
+	Errors []github.Error
+}
+
+// InstallationDeletedError is returned by ClassifyError when an installation
+// client cannot create a token because the installation no longer exists.
+// This happens when an app is uninstalled while events for the installation
+// are still queued or being handled.
+type InstallationDeletedError struct {
+	APIError
+
+	InstallationID int64
+}
+
+// ClassifyError converts errors returned by a go-github client into one of
+// the error types in this package, so callers can use errors.As to handle
+// specific failures:
+//
+//	var notFound *githubapp.NotFoundError
+//	if errors.As(githubapp.ClassifyError(err), &notFound) {
+//		...
+//	}
+//
+// It returns *NotFoundError, *ForbiddenError, *SecondaryRateLimitError,
+// *UnprocessableError, or *InstallationDeletedError for the matching failures
+// and *APIError for all other error responses. 429 responses are secondary
+// rate limits, even though go-github does not report them as
+// *github.AbuseRateLimitError. Other errors, including
+// primary rate limit errors, which go-github reports as
+// *github.RateLimitError, are returned unmodified. The returned errors wrap
+// the original error, so existing checks for go-github error types continue
+// to work.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var classified interface{ apiError() *APIError }
+	if errors.As(err, &classified) {
+		return err
+	}
+
+	var tokenErr *ghinstallation.HTTPError
+	if errors.As(err, &tokenErr) && tokenErr.Response != nil && tokenErr.Response.StatusCode == http.StatusNotFound {
+		return &InstallationDeletedError{
+			APIError:       newAPIError(err, tokenErr.Response, tokenErr.Message),
+			InstallationID: tokenErr.InstallationID,
+		}
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		apiErr := newAPIError(err, abuseErr.Response, abuseErr.Message)
+		apiErr.DocumentationURL = responseDocumentationURL(abuseErr.Response)
+		return &SecondaryRateLimitError{
+			APIError:   apiErr,
+			RetryAfter: abuseErr.GetRetryAfter(),
+		}
+	}
+
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return err
+	}
+
+	var resErr *github.ErrorResponse
+	if !errors.As(err, &resErr) {
+		return err
+	}
+
+	apiErr := newAPIError(err, resErr.Response, resErr.Message)
+	apiErr.DocumentationURL = resErr.DocumentationURL
+
+	// go-github only detects secondary rate limits in 403 responses, but
+	// GitHub also uses 429 responses for them
+	if apiErr.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimitURL(apiErr.DocumentationURL) {
+		return &SecondaryRateLimitError{
+			APIError:   apiErr,
+			RetryAfter: parseRetryAfter(resErr.Response),
+		}
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusNotFound:
+		return &NotFoundError{APIError: apiErr}
+	case http.StatusForbidden:
+		return &ForbiddenError{APIError: apiErr}
+	case http.StatusUnprocessableEntity:
+		return &UnprocessableError{APIError: apiErr, Errors: resErr.Errors}
+	}
+	return &apiErr
+}
+
+func newAPIError(cause error, res *http.Response, message string) APIError {
+	e := APIError{
+		Message: message,
+		Cause:   cause,
+	}
+	if res != nil {
+		e.StatusCode = res.StatusCode
+		e.RequestID = res.Header.Get(requestIDHeader)
+	}
+	return e
+}
+
+// responseDocumentationURL returns the documentation URL from the body of an
+// error response. go-github restores the body after reading it, so it is
+// read again here and restored for other callers.
+func responseDocumentationURL(res *http.Response) string {
+	if res == nil || res.Body == nil {
+		return ""
+	}
+
+	b, err := io.ReadAll(res.Body)
+	res.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		DocumentationURL string `json:"documentation_url"`
+	}
+	_ = json.Unmarshal(b, &body)
+	return body.DocumentationURL
+}
+
+func isSecondaryRateLimitURL(url string) bool {
+	return strings.HasSuffix(url, "#abuse-rate-limits") || strings.HasSuffix(url, "secondary-rate-limits")
+}
+
+// parseRetryAfter returns the duration in the Retry-After header of a
+// response or zero if the header is missing or invalid
+func parseRetryAfter(res *http.Response) time.Duration {
+	if res == nil {
+		return 0
+	}
+	secs, err := strconv.ParseInt(res.Header.Get("Retry-After"), 10, 64)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// RetryDelay returns how long to wait before retrying a request that failed
+// with err because of a primary or secondary rate limit, and false if err is
+// not caused by a rate limit. For secondary limits, it is the delay GitHub
+// asked for, or one minute if GitHub did not provide one. For primary limits,
+// it is the time until the limit resets on the clock of the context.
+//
+// Handlers and schedulers can use it to delay work instead of matching error
+// messages.
+func RetryDelay(ctx context.Context, err error) (time.Duration, bool) {
+	delay, ok := rateLimitDelay(ClassifyError(err), GetClock(ctx).Now())
+	return max(delay, 0), ok
+}
+
+// ErrorRequestID returns the GitHub request ID of the response that caused
+// err, or the empty string if err is not an error response from GitHub.
+func ErrorRequestID(err error) string {
+	var classified interface{ apiError() *APIError }
+	if errors.As(ClassifyError(err), &classified) {
+		return classified.apiError().RequestID
+	}
+	return ""
+}
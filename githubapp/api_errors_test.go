@@ -0,0 +1,272 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestClassifyError(t *testing.T) {
+	newResponse := func(status int) *http.Response {
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"X-Github-Request-Id": {"ABCD:1234"}},
+			Request:    httptest.NewRequest(http.MethodGet, "https://api.github.com/repos/octo/repo", nil),
+		}
+	}
+
+	retryAfter := 30 * time.Second
+
+	tests := map[string]struct {
+		Err   error
+		Check func(t *testing.T, err error)
+	}{
+		"nil": {
+			Check: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected nil, but got %v", err)
+				}
+			},
+		},
+		"notFound": {
+			Err: errors.Wrap(&github.ErrorResponse{Response: newResponse(404), Message: "Not Found"}, "failed to get repository"),
+			Check: func(t *testing.T, err error) {
+				var target *NotFoundError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected NotFoundError, but got %T", err)
+				}
+				if target.RequestID != "ABCD:1234" {
+					t.Errorf("incorrect request ID: %q", target.RequestID)
+				}
+				if !strings.Contains(err.Error(), "ABCD:1234") {
+					t.Errorf("error message does not contain the request ID: %q", err.Error())
+				}
+			},
+		},
+		"forbidden": {
+			Err: &github.ErrorResponse{Response: newResponse(403), Message: "Resource not accessible by integration"},
+			Check: func(t *testing.T, err error) {
+				var target *ForbiddenError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected ForbiddenError, but got %T", err)
+				}
+				if target.Message != "Resource not accessible by integration" {
+					t.Errorf("incorrect message: %q", target.Message)
+				}
+			},
+		},
+		"secondaryRateLimit": {
+			Err: &github.AbuseRateLimitError{Response: newResponse(403), Message: "secondary rate limit", RetryAfter: &retryAfter},
+			Check: func(t *testing.T, err error) {
+				var target *SecondaryRateLimitError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected SecondaryRateLimitError, but got %T", err)
+				}
+				if target.RetryAfter != retryAfter {
+					t.Errorf("incorrect retry after: %s", target.RetryAfter)
+				}
+			},
+		},
+		"secondaryRateLimitDocumentation": {
+			Err: func() error {
+				res := newResponse(403)
+				res.Body = io.NopCloser(strings.NewReader(`{"message": "secondary rate limit", "documentation_url": "https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`))
+				return &github.AbuseRateLimitError{Response: res, Message: "secondary rate limit"}
+			}(),
+			Check: func(t *testing.T, err error) {
+				var target *SecondaryRateLimitError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected SecondaryRateLimitError, but got %T", err)
+				}
+				if !strings.HasSuffix(target.DocumentationURL, "#about-secondary-rate-limits") {
+					t.Errorf("incorrect documentation URL: %q", target.DocumentationURL)
+				}
+			},
+		},
+		"tooManyRequests": {
+			Err: func() error {
+				res := newResponse(429)
+				res.Header.Set("Retry-After", "45")
+				return &github.ErrorResponse{Response: res, Message: "too many requests", DocumentationURL: "https://docs.github.com/rest"}
+			}(),
+			Check: func(t *testing.T, err error) {
+				var target *SecondaryRateLimitError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected SecondaryRateLimitError, but got %T", err)
+				}
+				if target.RetryAfter != 45*time.Second {
+					t.Errorf("incorrect retry after: %s", target.RetryAfter)
+				}
+				if target.DocumentationURL != "https://docs.github.com/rest" {
+					t.Errorf("incorrect documentation URL: %q", target.DocumentationURL)
+				}
+			},
+		},
+		"unprocessable": {
+			Err: &github.ErrorResponse{
+				Response: newResponse(422),
+				Message:  "Validation Failed",
+				Errors:   []github.Error{{Resource: "Label", Field: "name", Code: "already_exists"}},
+			},
+			Check: func(t *testing.T, err error) {
+				var target *UnprocessableError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected UnprocessableError, but got %T", err)
+				}
+				if len(target.Errors) != 1 || target.Errors[0].Field != "name" {
+					t.Errorf("incorrect field errors: %+v", target.Errors)
+				}
+			},
+		},
+		"installationDeleted": {
+			Err: &url.Error{Op: "Get", URL: "https://api.github.com/repos/octo/repo", Err: &ghinstallation.HTTPError{
+				Message:        "received non 2xx response status \"404 Not Found\"",
+				InstallationID: 42,
+				Response:       newResponse(404),
+			}},
+			Check: func(t *testing.T, err error) {
+				var target *InstallationDeletedError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected InstallationDeletedError, but got %T", err)
+				}
+				if target.InstallationID != 42 {
+					t.Errorf("incorrect installation ID: %d", target.InstallationID)
+				}
+			},
+		},
+		"otherStatus": {
+			Err: &github.ErrorResponse{Response: newResponse(502), Message: "Bad Gateway"},
+			Check: func(t *testing.T, err error) {
+				var target *APIError
+				if !errors.As(err, &target) {
+					t.Fatalf("expected APIError, but got %T", err)
+				}
+				if target.StatusCode != 502 {
+					t.Errorf("incorrect status code: %d", target.StatusCode)
+				}
+			},
+		},
+		"preservesOriginal": {
+			Err: &github.ErrorResponse{Response: newResponse(404), Message: "Not Found"},
+			Check: func(t *testing.T, err error) {
+				var target *github.ErrorResponse
+				if !errors.As(err, &target) {
+					t.Errorf("expected classified error to wrap the original error")
+				}
+				if ClassifyError(err) != err {
+					t.Errorf("expected classified errors to be returned unmodified")
+				}
+			},
+		},
+		"primaryRateLimit": {
+			Err: &github.RateLimitError{Response: newResponse(403), Message: "API rate limit exceeded"},
+			Check: func(t *testing.T, err error) {
+				var target *github.RateLimitError
+				if !errors.As(err, &target) {
+					t.Errorf("expected RateLimitError, but got %T", err)
+				}
+			},
+		},
+		"other": {
+			Err: errors.New("connection reset"),
+			Check: func(t *testing.T, err error) {
+				var target *APIError
+				if errors.As(err, &target) {
+					t.Errorf("expected non-API error to be unmodified, but got %T", err)
+				}
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.Check(t, ClassifyError(test.Err))
+		})
+	}
+}
+
+func TestErrorRequestID(t *testing.T) {
+	res := &http.Response{
+		StatusCode: 404,
+		Header:     http.Header{"X-Github-Request-Id": {"ABCD:1234"}},
+		Request:    httptest.NewRequest(http.MethodGet, "https://api.github.com/repos/octo/repo", nil),
+	}
+
+	err := errors.Wrap(&github.ErrorResponse{Response: res, Message: "Not Found"}, "failed to get repository")
+	if id := ErrorRequestID(err); id != "ABCD:1234" {
+		t.Errorf("incorrect request ID: %q", id)
+	}
+	if id := ErrorRequestID(errors.New("connection reset")); id != "" {
+		t.Errorf("expected empty request ID, but got %q", id)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	clock := newTestClock()
+	ctx := WithClock(context.Background(), clock)
+
+	retryAfter := 30 * time.Second
+	res := &http.Response{StatusCode: 403, Header: http.Header{}}
+
+	tests := map[string]struct {
+		Err      error
+		Expected time.Duration
+		OK       bool
+	}{
+		"secondary": {
+			Err:      errors.Wrap(&github.AbuseRateLimitError{Response: res, RetryAfter: &retryAfter}, "failed to create comment"),
+			Expected: retryAfter,
+			OK:       true,
+		},
+		"secondaryWithoutRetryAfter": {
+			Err:      &github.AbuseRateLimitError{Response: res},
+			Expected: time.Minute,
+			OK:       true,
+		},
+		"primary": {
+			Err:      &github.RateLimitError{Response: res, Rate: github.Rate{Reset: github.Timestamp{Time: clock.Now().Add(10 * time.Minute)}}},
+			Expected: 10 * time.Minute,
+			OK:       true,
+		},
+		"primaryReset": {
+			Err:      &github.RateLimitError{Response: res, Rate: github.Rate{Reset: github.Timestamp{Time: clock.Now().Add(-time.Minute)}}},
+			Expected: 0,
+			OK:       true,
+		},
+		"other": {
+			Err: &github.ErrorResponse{Response: res, Message: "Forbidden"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			delay, ok := RetryDelay(ctx, test.Err)
+			if ok != test.OK || delay != test.Expected {
+				t.Errorf("incorrect result: expected (%s, %t), actual (%s, %t)", test.Expected, test.OK, delay, ok)
+			}
+		})
+	}
+}
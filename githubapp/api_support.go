@@ -0,0 +1,150 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// APIFeatureMergeQueue is the merge queue API and the merge_group event.
+	APIFeatureMergeQueue = "merge_queue"
+
+	// APIFeatureRulesets is the repository and organization rulesets API.
+	APIFeatureRulesets = "rulesets"
+
+	// APIFeatureCustomProperties is the repository custom properties API.
+	APIFeatureCustomProperties = "custom_properties"
+)
+
+// APIFeatureVersions maps API features to the first GitHub Enterprise Server
+// version, in "major.minor" format, that supports them. Applications may add
+// entries for other features before handling events. github.com supports
+// all features.
+var APIFeatureVersions = map[string]string{
+	APIFeatureMergeQueue:       "3.12",
+	APIFeatureRulesets:         "3.11",
+	APIFeatureCustomProperties: "3.13",
+}
+
+const enterpriseVersionHeader = "X-GitHub-Enterprise-Version"
+
+type serverVersionKey struct{}
+
+// WithServerVersion returns a context that records the GitHub Enterprise
+// Server version used by SupportsAPI, like "3.12.4". An empty version means
+// github.com. Use it in background jobs, where there is no delivery to
+// detect the version from; DetectServerVersion finds the version with a
+// client.
+func WithServerVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, serverVersionKey{}, version)
+}
+
+// GetServerVersion returns the GitHub Enterprise Server version for the
+// context. It uses the version set by WithServerVersion or, if that is not
+// set, the version in the headers of the delivery stored in the context. It
+// returns an empty string and true for github.com and false if the version
+// is unknown.
+func GetServerVersion(ctx context.Context) (string, bool) {
+	if version, ok := ctx.Value(serverVersionKey{}).(string); ok {
+		return version, true
+	}
+	if d, ok := GetDelivery(ctx); ok && d.Header != nil {
+		return d.Header.Get(enterpriseVersionHeader), true
+	}
+	return "", false
+}
+
+// SupportsAPI returns true if the GitHub server for the context supports an
+// API feature, like APIFeatureMergeQueue. Handlers shared between github.com
+// and GitHub Enterprise Server can use it to skip features on older
+// Enterprise Server versions instead of failing with 404 errors.
+//
+// The server version comes from GetServerVersion. SupportsAPI returns true if
+// the version is unknown or cannot be parsed and for features that are not in
+// APIFeatureVersions, so handlers only degrade when the server is known to
+// be too old.
+func SupportsAPI(ctx context.Context, feature string) bool {
+	version, ok := GetServerVersion(ctx)
+	if !ok || version == "" {
+		return true
+	}
+
+	minVersion, ok := APIFeatureVersions[feature]
+	if !ok {
+		return true
+	}
+
+	major, minor, ok := parseServerVersion(version)
+	if !ok {
+		return true
+	}
+	minMajor, minMinor, ok := parseServerVersion(minVersion)
+	if !ok {
+		return true
+	}
+	return major > minMajor || (major == minMajor && minor >= minMinor)
+}
+
+// DetectServerVersion returns the GitHub Enterprise Server version of the
+// server used by client, or an empty string for github.com. The result
+// does not change while the server runs, so callers should cache it.
+func DetectServerVersion(ctx context.Context, client *github.Client) (string, error) {
+	req, err := client.NewRequest("GET", "meta", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	res, err := client.Do(ctx, req, &meta)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get server metadata")
+	}
+
+	if version := res.Header.Get(enterpriseVersionHeader); version != "" {
+		return version, nil
+	}
+	return meta.InstalledVersion, nil
+}
+
+// parseServerVersion returns the major and minor components of a version,
+// ignoring any prefix like "enterprise-server@" and any patch or
+// pre-release components
+func parseServerVersion(version string) (int, int, bool) {
+	if i := strings.LastIndexByte(version, '@'); i >= 0 {
+		version = version[i+1:]
+	}
+
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(strings.SplitN(parts[1], "-", 2)[0])
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
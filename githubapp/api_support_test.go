@@ -0,0 +1,108 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestSupportsAPI(t *testing.T) {
+	delivery := func(version string) context.Context {
+		h := http.Header{"X-Github-Event": []string{"merge_group"}}
+		if version != "" {
+			h.Set("X-GitHub-Enterprise-Version", version)
+		}
+		return WithDelivery(context.Background(), Delivery{EventType: "merge_group", Header: h})
+	}
+
+	tests := map[string]struct {
+		Ctx      context.Context
+		Feature  string
+		Expected bool
+	}{
+		"unknownServer": {
+			Ctx:      context.Background(),
+			Feature:  APIFeatureMergeQueue,
+			Expected: true,
+		},
+		"dotcom": {
+			Ctx:      delivery(""),
+			Feature:  APIFeatureMergeQueue,
+			Expected: true,
+		},
+		"newerServer": {
+			Ctx:      delivery("3.14.2"),
+			Feature:  APIFeatureMergeQueue,
+			Expected: true,
+		},
+		"sameMinor": {
+			Ctx:      delivery("3.12.0"),
+			Feature:  APIFeatureMergeQueue,
+			Expected: true,
+		},
+		"olderServer": {
+			Ctx:      delivery("3.9.7"),
+			Feature:  APIFeatureMergeQueue,
+			Expected: false,
+		},
+		"unknownFeature": {
+			Ctx:      delivery("3.9.7"),
+			Feature:  "other",
+			Expected: true,
+		},
+		"invalidVersion": {
+			Ctx:      delivery("unknown"),
+			Feature:  APIFeatureMergeQueue,
+			Expected: true,
+		},
+		"explicitVersion": {
+			Ctx:      WithServerVersion(delivery("3.14.0"), "enterprise-server@3.10"),
+			Feature:  APIFeatureRulesets,
+			Expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := SupportsAPI(test.Ctx, test.Feature); actual != test.Expected {
+				t.Errorf("incorrect result: expected %t, actual %t", test.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestDetectServerVersion(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /meta", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"installed_version": "3.12.1"}`)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	version, err := DetectServerVersion(context.Background(), cc.client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != "3.12.1" {
+		t.Errorf("incorrect version: %q", version)
+	}
+
+	ctx := DefaultContextDeriver(WithServerVersion(context.Background(), version))
+	if SupportsAPI(ctx, APIFeatureCustomProperties) {
+		t.Error("expected derived context to keep the server version")
+	}
+}
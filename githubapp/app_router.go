@@ -0,0 +1,90 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// AppRouterOption configures properties of an app router.
+type AppRouterOption func(*appRouter)
+
+// WithUnknownAppHandler sets the handler for deliveries that do not match
+// any app. By default, the router responds with 400 Bad Request.
+func WithUnknownAppHandler(h http.Handler) AppRouterOption {
+	return func(r *appRouter) {
+		if h != nil {
+			r.unknown = h
+		}
+	}
+}
+
+type appRouter struct {
+	apps    map[int64]http.Handler
+	unknown http.Handler
+}
+
+// NewAppRouter returns an http.Handler that routes webhook deliveries for
+// multiple GitHub Apps that share a single endpoint. Each delivery is sent to
+// the handler for the app that it targets, which is usually an event
+// dispatcher configured with the app's secret and handlers. Handlers in each
+// dispatcher should use a ClientCreator for the same app.
+//
+// The router identifies the app using the X-GitHub-Hook-Installation-Target-ID
+// header, which GitHub sets on every delivery to an app. Most payloads do not
+// contain the app ID, so deliveries without the header, or with a target
+// that is not an app, go to the unknown app handler.
+func NewAppRouter(apps map[int64]http.Handler, opts ...AppRouterOption) http.Handler {
+	r := &appRouter{
+		apps: apps,
+		unknown: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Unknown GitHub App", http.StatusBadRequest)
+		}),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func (ar *appRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	appID, ok := findAppID(r)
+	if !ok {
+		zerolog.Ctx(r.Context()).Warn().Msg("Received webhook without an app target")
+		ar.unknown.ServeHTTP(w, r)
+		return
+	}
+
+	h, ok := ar.apps[appID]
+	if !ok {
+		zerolog.Ctx(r.Context()).Warn().Int64("github_app_id", appID).Msg("Received webhook for unknown app")
+		ar.unknown.ServeHTTP(w, r)
+		return
+	}
+	h.ServeHTTP(w, r)
+}
+
+func findAppID(r *http.Request) (int64, bool) {
+	if r.Header.Get("X-GitHub-Hook-Installation-Target-Type") != "integration" {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(r.Header.Get("X-GitHub-Hook-Installation-Target-ID"), 10, 64)
+	return id, err == nil
+}
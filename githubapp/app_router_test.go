@@ -0,0 +1,100 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAppRouter(t *testing.T) {
+	appHandler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			_, _ = io.WriteString(w, name+":"+string(body))
+		})
+	}
+
+	router := NewAppRouter(map[int64]http.Handler{
+		1: appHandler("one"),
+		2: appHandler("two"),
+	})
+
+	tests := map[string]struct {
+		TargetType string
+		TargetID   string
+		Body       string
+
+		ResponseCode int
+		ResponseBody string
+	}{
+		"header": {
+			TargetType:   "integration",
+			TargetID:     "2",
+			Body:         `{}`,
+			ResponseCode: 200,
+			ResponseBody: "two:{}",
+		},
+		"missingHeader": {
+			Body:         `{"installation":{"id":10,"app_id":1}}`,
+			ResponseCode: 400,
+			ResponseBody: "Unknown GitHub App\n",
+		},
+		"nonAppTarget": {
+			TargetType:   "repository",
+			TargetID:     "2",
+			Body:         `{"installation":{"app_id":1}}`,
+			ResponseCode: 400,
+			ResponseBody: "Unknown GitHub App\n",
+		},
+		"invalidTargetID": {
+			TargetType:   "integration",
+			TargetID:     "two",
+			Body:         `{}`,
+			ResponseCode: 400,
+			ResponseBody: "Unknown GitHub App\n",
+		},
+		"unknownApp": {
+			TargetType:   "integration",
+			TargetID:     "3",
+			Body:         `{}`,
+			ResponseCode: 400,
+			ResponseBody: "Unknown GitHub App\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/github/hook", strings.NewReader(test.Body))
+			if test.TargetType != "" {
+				req.Header.Set("X-GitHub-Hook-Installation-Target-Type", test.TargetType)
+				req.Header.Set("X-GitHub-Hook-Installation-Target-ID", test.TargetID)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != test.ResponseCode {
+				t.Errorf("incorrect response code: expected %d, actual %d", test.ResponseCode, w.Code)
+			}
+			if w.Body.String() != test.ResponseBody {
+				t.Errorf("incorrect response body: expected %q, actual %q", test.ResponseBody, w.Body.String())
+			}
+		})
+	}
+}
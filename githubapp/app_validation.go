@@ -0,0 +1,185 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// AppRequirements are the permissions and events an application needs to
+// function correctly.
+type AppRequirements struct {
+	// Permissions maps permission names, like "pull_requests" or "checks", to
+	// the minimum required access: "read", "write", or "admin".
+	Permissions map[string]string
+
+	// Events are the webhook events the application must subscribe to.
+	Events []string
+}
+
+// RequiredEvents returns the events handled by a set of handlers, for use in
+// AppRequirements.
+func RequiredEvents(handlers ...EventHandler) []string {
+	seen := make(map[string]bool)
+	var events []string
+	for _, h := range handlers {
+		for _, event := range h.Handles() {
+			if !seen[event] {
+				seen[event] = true
+				events = append(events, event)
+			}
+		}
+	}
+	sort.Strings(events)
+	return events
+}
+
+// AppRequirementsError describes the requirements that an application does
+// not satisfy.
+type AppRequirementsError struct {
+	// MissingPermissions maps permission names to the required access for
+	// permissions that are not granted or are granted with less access.
+	MissingPermissions map[string]string
+
+	// MissingEvents are the required events that are not subscribed.
+	MissingEvents []string
+}
+
+func (e AppRequirementsError) Error() string {
+	var problems []string
+	if len(e.MissingPermissions) > 0 {
+		problems = append(problems, "missing permissions: "+formatPermissions(e.MissingPermissions))
+	}
+	if len(e.MissingEvents) > 0 {
+		problems = append(problems, "missing events: "+strings.Join(e.MissingEvents, ", "))
+	}
+	return "app does not meet requirements: " + strings.Join(problems, "; ")
+}
+
+// formatPermissions formats permissions as "name:access" pairs sorted by
+// name.
+func formatPermissions(perms map[string]string) string {
+	names := make([]string, 0, len(perms))
+	for name := range perms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s:%s", name, perms[name]))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// missingPermissions returns the required permissions that granted does not
+// grant with at least the required access. Permissions with an unknown
+// required access, like a misspelled one, are always missing.
+func missingPermissions(granted, required map[string]string) map[string]string {
+	var missing map[string]string
+	for name, access := range required {
+		rank, ok := appPermissionRanks[access]
+		if !ok || appPermissionRanks[granted[name]] < rank {
+			if missing == nil {
+				missing = make(map[string]string)
+			}
+			missing[name] = access
+		}
+	}
+	return missing
+}
+
+// GitHub delivers these events to all applications regardless of their
+// subscriptions, so they never appear in the list of subscribed events.
+var implicitAppEvents = map[string]bool{
+	"github_app_authorization":  true,
+	"installation":              true,
+	"installation_repositories": true,
+	"ping":                      true,
+}
+
+var appPermissionRanks = map[string]int{
+	"read":  1,
+	"write": 2,
+	"admin": 3,
+}
+
+// ValidateApp fetches the application's configuration from GitHub and checks
+// that it grants the required permissions and subscribes to the required
+// events. If any requirement is not met, it returns an AppRequirementsError.
+// Call ValidateApp at startup to either exit or log loudly, since missing
+// permissions otherwise only appear as 403 responses from API calls.
+func ValidateApp(ctx context.Context, cc ClientCreator, req AppRequirements) error {
+	client, err := cc.NewAppClient()
+	if err != nil {
+		return err
+	}
+
+	app, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		return errors.Wrap(err, "failed to get app")
+	}
+	return checkAppRequirements(app, req)
+}
+
+func checkAppRequirements(app *github.App, req AppRequirements) error {
+	granted, err := permissionsMap(app.Permissions)
+	if err != nil {
+		return err
+	}
+
+	appErr := AppRequirementsError{
+		MissingPermissions: missingPermissions(granted, req.Permissions),
+	}
+
+	subscribed := make(map[string]bool)
+	for _, event := range app.Events {
+		subscribed[event] = true
+	}
+	for _, event := range req.Events {
+		if !subscribed[event] && !implicitAppEvents[event] {
+			appErr.MissingEvents = append(appErr.MissingEvents, event)
+		}
+	}
+	sort.Strings(appErr.MissingEvents)
+
+	if len(appErr.MissingPermissions) > 0 || len(appErr.MissingEvents) > 0 {
+		return appErr
+	}
+	return nil
+}
+
+func permissionsMap(perms *github.InstallationPermissions) (map[string]string, error) {
+	m := make(map[string]string)
+	if perms == nil {
+		return m, nil
+	}
+
+	b, err := json.Marshal(perms)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal permissions")
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal permissions")
+	}
+	return m, nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestCheckAppRequirements(t *testing.T) {
+	app := &github.App{
+		Permissions: &github.InstallationPermissions{
+			Contents:     github.String("read"),
+			PullRequests: github.String("write"),
+			Checks:       github.String("write"),
+		},
+		Events: []string{"pull_request", "check_run"},
+	}
+
+	tests := map[string]struct {
+		Requirements AppRequirements
+		Expected     *AppRequirementsError
+	}{
+		"satisfied": {
+			Requirements: AppRequirements{
+				Permissions: map[string]string{"contents": "read", "pull_requests": "read", "checks": "write"},
+				Events:      []string{"pull_request", "installation", "ping"},
+			},
+		},
+		"insufficientAccess": {
+			Requirements: AppRequirements{
+				Permissions: map[string]string{"contents": "write"},
+			},
+			Expected: &AppRequirementsError{
+				MissingPermissions: map[string]string{"contents": "write"},
+			},
+		},
+		"missingPermissionAndEvents": {
+			Requirements: AppRequirements{
+				Permissions: map[string]string{"issues": "read"},
+				Events:      []string{"push", "check_run", "issue_comment"},
+			},
+			Expected: &AppRequirementsError{
+				MissingPermissions: map[string]string{"issues": "read"},
+				MissingEvents:      []string{"issue_comment", "push"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkAppRequirements(app, test.Requirements)
+			if test.Expected == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			appErr, ok := err.(AppRequirementsError)
+			if !ok {
+				t.Fatalf("expected AppRequirementsError, got %T: %v", err, err)
+			}
+			if !reflect.DeepEqual(appErr, *test.Expected) {
+				t.Errorf("incorrect error:\nexpected: %+v\n  actual: %+v", *test.Expected, appErr)
+			}
+		})
+	}
+}
+
+func TestRequiredEvents(t *testing.T) {
+	events := RequiredEvents(
+		&TestEventHandler{Types: []string{"pull_request", "issue_comment"}},
+		&TestEventHandler{Types: []string{"pull_request", "check_run"}},
+	)
+
+	expected := []string{"check_run", "issue_comment", "pull_request"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("incorrect events: expected %v, actual %v", expected, events)
+	}
+}
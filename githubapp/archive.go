@@ -0,0 +1,175 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BlobSink stores archived webhook deliveries. Implementations can write to
+// local files, object storage like S3, or any other blob store. Keys are
+// slash-separated paths.
+type BlobSink interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// ArchivedDelivery is the format of deliveries written to a BlobSink.
+type ArchivedDelivery struct {
+	EventType  string          `json:"event_type"`
+	DeliveryID string          `json:"delivery_id"`
+	ReceivedAt time.Time       `json:"received_at"`
+	Header     http.Header     `json:"header"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Delivery returns the archived delivery in a form that can be sent to a
+// webhook endpoint with NewDeliveryRequest.
+func (a ArchivedDelivery) Delivery() Delivery {
+	return Delivery{
+		EventType:  a.EventType,
+		DeliveryID: a.DeliveryID,
+		Payload:    a.Payload,
+		Header:     DeliveryHeader(a.Header),
+	}
+}
+
+// Key returns the key used to store the delivery, which groups deliveries by
+// the day they were received. The delivery ID comes from a request header
+// that is not covered by the payload signature, so characters other than
+// ASCII letters, digits, and hyphens are percent-encoded.
+func (a ArchivedDelivery) Key() string {
+	return a.ReceivedAt.UTC().Format("2006/01/02") + "/" + escapeKeySegment(a.DeliveryID) + ".json"
+}
+
+// escapeKeySegment percent-encodes all bytes of s except ASCII letters,
+// digits, and hyphens, so the result is a single path segment that cannot
+// refer to a parent directory.
+func escapeKeySegment(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// WithDeliveryArchive sets a sink that receives a copy of every delivery
+// after the dispatcher validates it and before the dispatcher schedules it
+// for handling. Archival failures are logged and do not prevent handling.
+func WithDeliveryArchive(sink BlobSink) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.archive = sink
+	}
+}
+
+func archiveDelivery(ctx context.Context, sink BlobSink, r *http.Request, eventType, deliveryID string, payload []byte) error {
+	a := ArchivedDelivery{
+		EventType:  eventType,
+		DeliveryID: deliveryID,
+		ReceivedAt: GetClock(ctx).Now().UTC(),
+		Header:     r.Header.Clone(),
+		Payload:    payload,
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal delivery")
+	}
+	if err := sink.Put(ctx, a.Key(), b); err != nil {
+		return errors.Wrapf(err, "failed to archive delivery %s", deliveryID)
+	}
+	return nil
+}
+
+type directoryBlobSink struct {
+	dir string
+}
+
+// NewDirectoryBlobSink returns a BlobSink that writes blobs as files in dir,
+// creating subdirectories as needed.
+func NewDirectoryBlobSink(dir string) BlobSink {
+	return &directoryBlobSink{dir: dir}
+}
+
+// path returns the file for key. It returns an error if the key is absolute,
+// contains ".." segments, or otherwise resolves to a file outside the
+// directory.
+func (s *directoryBlobSink) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || filepath.IsAbs(filepath.FromSlash(key)) {
+		return "", errors.Errorf("invalid blob key %q", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." || strings.Contains(segment, `\`) {
+			return "", errors.Errorf("invalid blob key %q", key)
+		}
+	}
+
+	dir := filepath.Clean(s.dir)
+	path := filepath.Join(dir, filepath.FromSlash(key))
+	if rel, err := filepath.Rel(dir, path); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", errors.Errorf("blob key %q is outside of %s", key, s.dir)
+	}
+	return path, nil
+}
+
+func (s *directoryBlobSink) Put(ctx context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "failed to create directory")
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write %s", path)
+	}
+	return nil
+}
+
+func (s *directoryBlobSink) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+	return data, nil
+}
+
+func (s *directoryBlobSink) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to delete %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeliveryArchive(t *testing.T) {
+	dir := t.TempDir()
+
+	d := NewEventDispatcher(nil, testHookSecret, WithDeliveryArchive(NewDirectoryBlobSink(dir)))
+
+	for _, signed := range []bool{true, false} {
+		w := httptest.NewRecorder()
+		d.ServeHTTP(w, newHookRequest("pull_request", "archived", signed))
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*", "*", "*", "archived.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 archived delivery, found %d", len(matches))
+	}
+
+	b, err := os.ReadFile(matches[0])
+	if err != nil {
+		t.Fatalf("unexpected error reading archive: %v", err)
+	}
+
+	var a ArchivedDelivery
+	if err := json.Unmarshal(b, &a); err != nil {
+		t.Fatalf("unexpected error parsing archive: %v", err)
+	}
+
+	if a.EventType != "pull_request" || a.DeliveryID != "archived" {
+		t.Errorf("incorrect delivery metadata: %+v", a)
+	}
+	if string(a.Payload) != `{"type":"pull_request"}` {
+		t.Errorf("incorrect payload: %s", a.Payload)
+	}
+	if a.Header.Get("X-Hub-Signature") == "" {
+		t.Errorf("archived delivery is missing headers: %v", a.Header)
+	}
+	if a.Key() != filepath.ToSlash(matches[0][len(dir)+1:]) {
+		t.Errorf("incorrect key: %s", a.Key())
+	}
+}
+
+func TestArchivedDeliveryKey(t *testing.T) {
+	receivedAt := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		ID  string
+		Key string
+	}{
+		"uuid":      {ID: "72d3162e-cc78-11e3-81ab-4c9367dc0958", Key: "2024/03/09/72d3162e-cc78-11e3-81ab-4c9367dc0958.json"},
+		"traversal": {ID: "../../../etc/passwd", Key: "2024/03/09/%2E%2E%2F%2E%2E%2F%2E%2E%2Fetc%2Fpasswd.json"},
+		"dots":      {ID: "..", Key: "2024/03/09/%2E%2E.json"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			key := ArchivedDelivery{DeliveryID: test.ID, ReceivedAt: receivedAt}.Key()
+			if key != test.Key {
+				t.Errorf("expected key %q, got %q", test.Key, key)
+			}
+		})
+	}
+}
+
+func TestDirectoryBlobSinkRejectsEscapingKeys(t *testing.T) {
+	dir := t.TempDir()
+	sink := &directoryBlobSink{dir: filepath.Join(dir, "archive")}
+
+	for _, key := range []string{"", "/etc/passwd", "../outside.json", "2024/../../outside.json", "."} {
+		t.Run(key, func(t *testing.T) {
+			if err := sink.Put(context.Background(), key, []byte("{}")); err == nil {
+				t.Errorf("expected error putting key %q", key)
+			}
+			if _, err := sink.Get(context.Background(), key); err == nil {
+				t.Errorf("expected error getting key %q", key)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "outside.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no file outside of the archive directory, got %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultBackfillInterval is the default time between synthesized
+	// events sent to handlers by a Backfill.
+	DefaultBackfillInterval = 100 * time.Millisecond
+
+	// DefaultBackfillMinRemaining is the default number of core API requests
+	// a Backfill leaves for other work before it waits for the rate limit to
+	// reset.
+	DefaultBackfillMinRemaining = 500
+)
+
+type backfillKey struct{}
+
+// IsBackfill returns true if the context is for an event synthesized by a
+// Backfill instead of delivered by GitHub. Handlers can use it to skip side
+// effects that only make sense for new activity, like notifying users.
+func IsBackfill(ctx context.Context) bool {
+	backfill, _ := ctx.Value(backfillKey{}).(bool)
+	return backfill
+}
+
+// BackfillEmitFunc sends a synthesized event to the handler for its type.
+// It returns an error if the context is canceled; handler errors are
+// reported to the backfill's error callback instead.
+type BackfillEmitFunc func(eventType string, payload interface{}) error
+
+// BackfillSource synthesizes events for a repository from its current state,
+// like an event for each open pull request. It calls emit for each event. The
+// client is an installation client for the repository.
+type BackfillSource func(ctx context.Context, client *github.Client, installationID int64, repo *github.Repository, emit BackfillEmitFunc) error
+
+// BackfillOpenPullRequests returns a source that synthesizes a
+// "pull_request" event with the given action, usually "opened", for each open
+// pull request in a repository.
+func BackfillOpenPullRequests(action string) BackfillSource {
+	return func(ctx context.Context, client *github.Client, installationID int64, repo *github.Repository, emit BackfillEmitFunc) error {
+		owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+
+		opts := &github.PullRequestListOptions{
+			State:       "open",
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		for {
+			prs, res, err := client.PullRequests.List(ctx, owner, name, opts)
+			if err != nil {
+				return errors.Wrapf(err, "failed to list open pull requests in %s/%s", owner, name)
+			}
+			for _, pr := range prs {
+				if err := emit("pull_request", &github.PullRequestEvent{
+					Action:       github.String(action),
+					Number:       pr.Number,
+					PullRequest:  pr,
+					Repo:         repo,
+					Installation: &github.Installation{ID: github.Int64(installationID)},
+				}); err != nil {
+					return err
+				}
+			}
+			if res.NextPage == 0 {
+				return nil
+			}
+			opts.Page = res.NextPage
+		}
+	}
+}
+
+// BackfillOpenIssues returns a source that synthesizes an "issues" event with
+// the given action, usually "opened", for each open issue in a repository.
+// Pull requests, which the issues API also returns, are skipped.
+func BackfillOpenIssues(action string) BackfillSource {
+	return func(ctx context.Context, client *github.Client, installationID int64, repo *github.Repository, emit BackfillEmitFunc) error {
+		owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+
+		opts := &github.IssueListByRepoOptions{
+			State:       "open",
+			ListOptions: github.ListOptions{PerPage: 100},
+		}
+		for {
+			issues, res, err := client.Issues.ListByRepo(ctx, owner, name, opts)
+			if err != nil {
+				return errors.Wrapf(err, "failed to list open issues in %s/%s", owner, name)
+			}
+			for _, issue := range issues {
+				if issue.IsPullRequest() {
+					continue
+				}
+				if err := emit("issues", &github.IssuesEvent{
+					Action:       github.String(action),
+					Issue:        issue,
+					Repo:         repo,
+					Installation: &github.Installation{ID: github.Int64(installationID)},
+				}); err != nil {
+					return err
+				}
+			}
+			if res.NextPage == 0 {
+				return nil
+			}
+			opts.Page = res.NextPage
+		}
+	}
+}
+
+// BackfillResult counts the events a Backfill synthesized.
+type BackfillResult struct {
+	// Events is the number of events sent to handlers.
+	Events int
+
+	// Errors is the number of events for which the handler failed.
+	Errors int
+}
+
+// BackfillErrorCallback is called when a handler fails for a synthesized
+// event. If the handler panics, err is a HandlerPanicError.
+type BackfillErrorCallback func(ctx context.Context, d Delivery, err error)
+
+// DefaultBackfillErrorCallback logs errors.
+func DefaultBackfillErrorCallback(ctx context.Context, d Delivery, err error) {
+	zerolog.Ctx(ctx).Error().Err(err).Msg("Unexpected error handling backfilled event")
+}
+
+// BackfillOption configures properties of a Backfill.
+type BackfillOption func(*Backfill)
+
+// WithBackfillInterval sets the time between synthesized events. The default
+// is DefaultBackfillInterval. A negative interval disables pacing.
+func WithBackfillInterval(interval time.Duration) BackfillOption {
+	return func(b *Backfill) {
+		b.interval = interval
+	}
+}
+
+// WithBackfillMinRemaining sets how many core API requests a backfill leaves
+// for other work. When the rate limit reported by GitHub drops below this
+// number, the backfill waits for the limit to reset before sending the next
+// event. The default is DefaultBackfillMinRemaining.
+func WithBackfillMinRemaining(minRemaining int) BackfillOption {
+	return func(b *Backfill) {
+		b.minRemaining = minRemaining
+	}
+}
+
+// WithBackfillRepositoryFilter sets a function that selects the repositories
+// to backfill. By default, all repositories of the installation are
+// backfilled except archived repositories.
+func WithBackfillRepositoryFilter(filter func(*github.Repository) bool) BackfillOption {
+	return func(b *Backfill) {
+		if filter != nil {
+			b.filter = filter
+		}
+	}
+}
+
+// WithBackfillErrorCallback sets the function called when a handler fails.
+// The default is DefaultBackfillErrorCallback.
+func WithBackfillErrorCallback(onError BackfillErrorCallback) BackfillOption {
+	return func(b *Backfill) {
+		if onError != nil {
+			b.onError = onError
+		}
+	}
+}
+
+// Backfill sends events synthesized from the current state of repositories
+// to event handlers, as if GitHub delivered them. Use it to onboard
+// repositories that were created or installed before the app existed, like
+// checking every open pull request when a new check is introduced.
+//
+// Events are handled synchronously and one at a time, with a pause between
+// events and a pause when the installation's rate limit runs low, so a
+// backfill does not starve webhook handling. Handlers see a context for
+// which IsBackfill returns true.
+type Backfill struct {
+	cc       ClientCreator
+	handlers map[string]EventHandler
+	sources  []BackfillSource
+
+	interval     time.Duration
+	minRemaining int
+	filter       func(*github.Repository) bool
+	onError      BackfillErrorCallback
+}
+
+// NewBackfill creates a Backfill that synthesizes events with sources and
+// sends them to the handler for each event type. Events without a handler
+// are ignored.
+func NewBackfill(cc ClientCreator, handlers []EventHandler, sources []BackfillSource, opts ...BackfillOption) *Backfill {
+	b := &Backfill{
+		cc:           cc,
+		handlers:     make(map[string]EventHandler),
+		sources:      sources,
+		interval:     DefaultBackfillInterval,
+		minRemaining: DefaultBackfillMinRemaining,
+		filter:       func(r *github.Repository) bool { return !r.GetArchived() },
+		onError:      DefaultBackfillErrorCallback,
+	}
+	for _, h := range handlers {
+		for _, eventType := range h.Handles() {
+			b.handlers[eventType] = h
+		}
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// RunInstallation backfills all selected repositories of an installation.
+// It returns an error if it cannot list repositories, if a source fails, or
+// if the context is canceled, along with the events sent so far.
+func (b *Backfill) RunInstallation(ctx context.Context, installationID int64) (BackfillResult, error) {
+	var result BackfillResult
+
+	client, err := b.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return result, err
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		repos, res, err := client.Apps.ListRepos(ctx, opts)
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to list repositories of installation %d", installationID)
+		}
+		for _, repo := range repos.Repositories {
+			if !b.filter(repo) {
+				continue
+			}
+			if err := b.runRepository(ctx, client, installationID, repo, &result); err != nil {
+				return result, err
+			}
+		}
+		if res.NextPage == 0 {
+			return result, nil
+		}
+		opts.Page = res.NextPage
+	}
+}
+
+// RunRepository backfills a single repository of an installation, ignoring
+// the repository filter.
+func (b *Backfill) RunRepository(ctx context.Context, installationID int64, owner, repo string) (BackfillResult, error) {
+	var result BackfillResult
+
+	client, err := b.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return result, err
+	}
+
+	r, _, err := client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return result, errors.Wrapf(err, "failed to get repository %s/%s", owner, repo)
+	}
+
+	err = b.runRepository(ctx, client, installationID, r, &result)
+	return result, err
+}
+
+func (b *Backfill) runRepository(ctx context.Context, client *github.Client, installationID int64, repo *github.Repository, result *BackfillResult) error {
+	ctx, logger := PrepareRepoContext(ctx, installationID, repo)
+	ctx = InitializeRateLimits(context.WithValue(ctx, backfillKey{}, true))
+
+	logger.Info().Msg("Backfilling repository")
+
+	emit := func(eventType string, payload interface{}) error {
+		return b.emit(ctx, repo, eventType, payload, result)
+	}
+	for _, source := range b.sources {
+		if err := source(ctx, client, installationID, repo, emit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Backfill) emit(ctx context.Context, repo *github.Repository, eventType string, payload interface{}, result *BackfillResult) error {
+	handler, ok := b.handlers[eventType]
+	if !ok {
+		return nil
+	}
+
+	if err := b.wait(ctx, result.Events > 0); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s event", eventType)
+	}
+
+	d := Dispatch{
+		Handler:    handler,
+		EventType:  eventType,
+		DeliveryID: fmt.Sprintf("backfill-%d-%d", repo.GetID(), result.Events+1),
+		Payload:    raw,
+	}
+
+	result.Events++
+	if err := executeRecover(ctx, d); err != nil {
+		result.Errors++
+		b.onError(ctx, Delivery{EventType: d.EventType, DeliveryID: d.DeliveryID, Payload: d.Payload}, err)
+	}
+	return nil
+}
+
+func executeRecover(ctx context.Context, d Dispatch) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredPanic(ctx, r)
+		}
+	}()
+	return d.Execute(ctx)
+}
+
+// wait pauses before an event to pace the backfill and to keep requests in
+// reserve when the rate limit is low.
+func (b *Backfill) wait(ctx context.Context, pace bool) error {
+	if pace && b.interval > 0 {
+		if err := sleepContext(ctx, b.interval); err != nil {
+			return err
+		}
+	}
+
+	core, ok := RateLimits(ctx)[RateLimitResourceCore]
+	if ok && core.Limit > 0 && core.Remaining < b.minRemaining {
+		delay := core.Reset.Sub(GetClock(ctx).Now())
+		if delay > 0 {
+			zerolog.Ctx(ctx).Info().Msgf("Pausing backfill for %s until the rate limit resets", delay.Round(time.Second))
+			return sleepContext(ctx, delay)
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestBackfill(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /installation/repositories", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"total_count": 2, "repositories": [
+			{"id": 1, "name": "repo", "owner": {"login": "octo"}},
+			{"id": 2, "name": "old", "owner": {"login": "octo"}, "archived": true}
+		]}`)
+	})
+	mux.HandleFunc("GET /repos/octo/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "open" {
+			t.Errorf("incorrect state: %s", r.URL.Query().Get("state"))
+		}
+		_, _ = io.WriteString(w, `[{"number": 1}, {"number": 2}, {"number": 3}]`)
+	})
+	mux.HandleFunc("GET /repos/octo/repo/issues", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `[{"number": 4}, {"number": 1, "pull_request": {"url": "pr"}}]`)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	var handled []string
+	h := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			if !IsBackfill(ctx) {
+				t.Error("expected backfill flag in handler context")
+			}
+
+			var event github.PullRequestEvent
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return err
+			}
+			if event.GetInstallation().GetID() != 42 || event.GetAction() != "opened" {
+				t.Errorf("incorrect event: %s", payload)
+			}
+			handled = append(handled, fmt.Sprintf("%s#%d", event.GetRepo().GetName(), event.GetNumber()))
+			if event.GetNumber() == 2 {
+				return errors.New("handler failed")
+			}
+			return nil
+		},
+	}
+
+	var failed []string
+	b := NewBackfill(cc, []EventHandler{h},
+		[]BackfillSource{BackfillOpenPullRequests("opened"), BackfillOpenIssues("opened")},
+		WithBackfillInterval(time.Second),
+		WithBackfillErrorCallback(func(ctx context.Context, d Delivery, err error) {
+			failed = append(failed, d.DeliveryID)
+		}),
+	)
+
+	clock := newTestClock()
+	result, err := b.RunInstallation(WithClock(context.Background(), clock), 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Events != 3 || result.Errors != 1 {
+		t.Errorf("incorrect result: %+v", result)
+	}
+	if fmt.Sprint(handled) != "[repo#1 repo#2 repo#3]" {
+		t.Errorf("incorrect handled events: %v", handled)
+	}
+	if fmt.Sprint(failed) != "[backfill-1-2]" {
+		t.Errorf("incorrect failed deliveries: %v", failed)
+	}
+	if len(clock.slept) != 2 {
+		t.Errorf("expected 2 pauses between events, but got %v", clock.slept)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const botSuffix = "[bot]"
+
+// IsBot returns true if user is a bot account, like the bot user of a
+// GitHub App or a legacy machine account that GitHub identifies as a bot.
+func IsBot(user *github.User) bool {
+	if user == nil {
+		return false
+	}
+	return user.GetType() == "Bot" || strings.HasSuffix(user.GetLogin(), botSuffix)
+}
+
+// IsOwnApp returns true if user is the bot user of the application with the
+// given slug.
+func IsOwnApp(user *github.User, slug string) bool {
+	return slug != "" && strings.EqualFold(user.GetLogin(), BotLogin(slug))
+}
+
+// BotLogin returns the login of the bot user of the application with the
+// given slug.
+func BotLogin(slug string) string {
+	return slug + botSuffix
+}
+
+// AppIdentity looks up and caches the identity of the application, so
+// handlers can recognize the application's bot user in events without
+// configuring the slug separately. It is safe for concurrent use.
+type AppIdentity struct {
+	cc ClientCreator
+
+	mu  sync.Mutex
+	app *github.App
+}
+
+// NewAppIdentity creates an AppIdentity that uses an application client
+// from cc to look up the application.
+func NewAppIdentity(cc ClientCreator) *AppIdentity {
+	return &AppIdentity{cc: cc}
+}
+
+// App returns the application, loading it on the first call. If loading
+// fails, the next call tries again.
+func (id *AppIdentity) App(ctx context.Context) (*github.App, error) {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+
+	if id.app != nil {
+		return id.app, nil
+	}
+
+	client, err := id.cc.NewAppClient()
+	if err != nil {
+		return nil, err
+	}
+
+	app, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get app")
+	}
+
+	id.app = app
+	return app, nil
+}
+
+// Slug returns the slug of the application.
+func (id *AppIdentity) Slug(ctx context.Context) (string, error) {
+	app, err := id.App(ctx)
+	if err != nil {
+		return "", err
+	}
+	return app.GetSlug(), nil
+}
+
+// IsOwnApp returns true if user is the bot user of the application.
+func (id *AppIdentity) IsOwnApp(ctx context.Context, user *github.User) (bool, error) {
+	if !IsBot(user) {
+		return false, nil
+	}
+
+	slug, err := id.Slug(ctx)
+	if err != nil {
+		return false, err
+	}
+	return IsOwnApp(user, slug), nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestIsBot(t *testing.T) {
+	tests := map[string]struct {
+		User     *github.User
+		Expected bool
+	}{
+		"nil":     {nil, false},
+		"user":    {&github.User{Login: github.String("mhaypenny"), Type: github.String("User")}, false},
+		"appBot":  {&github.User{Login: github.String("my-app[bot]"), Type: github.String("Bot")}, true},
+		"noType":  {&github.User{Login: github.String("my-app[bot]")}, true},
+		"botType": {&github.User{Login: github.String("dependabot"), Type: github.String("Bot")}, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := IsBot(test.User); actual != test.Expected {
+				t.Errorf("incorrect result: expected %t, actual %t", test.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestAppIdentity(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"id": 1, "slug": "my-app"}`))
+	})
+	id := NewAppIdentity(newStaticClientCreator(t, mux))
+
+	tests := map[string]struct {
+		Login    string
+		Expected bool
+	}{
+		"ownApp":   {"my-app[bot]", true},
+		"caseDiff": {"My-App[bot]", true},
+		"otherApp": {"other-app[bot]", false},
+		"user":     {"my-app", false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			own, err := id.IsOwnApp(ctx, &github.User{Login: github.String(test.Login)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if own != test.Expected {
+				t.Errorf("incorrect result: expected %t, actual %t", test.Expected, own)
+			}
+		})
+	}
+
+	if calls != 1 {
+		t.Errorf("expected app to be cached, but loaded it %d times", calls)
+	}
+}
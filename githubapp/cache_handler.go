@@ -0,0 +1,238 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// RepositoryCacheInvalidator is implemented by caches that store values for
+// repositories, like appconfig.FeatureGate.
+type RepositoryCacheInvalidator interface {
+	// InvalidateRepository removes cached values for the repository
+	// owner/repo.
+	InvalidateRepository(owner, repo string)
+}
+
+// UserCacheInvalidator is implemented by caches that store values for users
+// who authorized the application, like OAuth tokens.
+type UserCacheInvalidator interface {
+	// InvalidateUser removes cached values for a user.
+	InvalidateUser(login string)
+}
+
+// CacheHandlerOption configures the caches maintained by a cache handler.
+type CacheHandlerOption func(*cacheHandler)
+
+// WithCachedClients invalidates cached installation clients when an
+// installation changes. Pass the ClientCreator returned by
+// NewCachingClientCreator or NewDefaultCachingClientCreator. Creators that
+// do not implement ClientInvalidator are ignored.
+func WithCachedClients(cc ClientCreator) CacheHandlerOption {
+	return func(h *cacheHandler) {
+		if inv, ok := cc.(ClientInvalidator); ok {
+			h.clients = inv
+		}
+	}
+}
+
+// WithCachedInstallations invalidates cached installations when an
+// installation, its repositories, or a repository changes. Pass the service
+// returned by NewCachingInstallationsService. Services that do not implement
+// InstallationsInvalidator are ignored.
+func WithCachedInstallations(s InstallationsService) CacheHandlerOption {
+	return func(h *cacheHandler) {
+		if inv, ok := s.(InstallationsInvalidator); ok {
+			h.installations = inv
+		}
+	}
+}
+
+// WithRepositoryCaches invalidates values in each cache when a repository
+// is added to or removed from an installation, renamed, transferred, or
+// deleted.
+func WithRepositoryCaches(caches ...RepositoryCacheInvalidator) CacheHandlerOption {
+	return func(h *cacheHandler) {
+		for _, c := range caches {
+			if c != nil {
+				h.repositories = append(h.repositories, c)
+			}
+		}
+	}
+}
+
+// WithUserCaches invalidates values in each cache when a user revokes their
+// authorization of the application.
+func WithUserCaches(caches ...UserCacheInvalidator) CacheHandlerOption {
+	return func(h *cacheHandler) {
+		for _, c := range caches {
+			if c != nil {
+				h.users = append(h.users, c)
+			}
+		}
+	}
+}
+
+type cacheHandler struct {
+	clients       ClientInvalidator
+	installations InstallationsInvalidator
+	repositories  []RepositoryCacheInvalidator
+	users         []UserCacheInvalidator
+}
+
+// NewCacheHandler returns an EventHandler that keeps the configured caches
+// coherent with changes on GitHub. It handles "installation",
+// "installation_repositories", "github_app_authorization", and "repository"
+// events. Register it with the other handlers of an application, for
+// example by using NewFanOutHandler if the application also handles these
+// events.
+func NewCacheHandler(opts ...CacheHandlerOption) EventHandler {
+	h := &cacheHandler{}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *cacheHandler) Handles() []string {
+	return Handles(EventInstallation, EventInstallationRepositories, EventGitHubAppAuthorization, EventRepository)
+}
+
+func (h *cacheHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	switch eventType {
+	case "installation":
+		var event github.InstallationEvent
+		if err := unmarshalPayload(ctx, payload, &event); err != nil {
+			return errors.Wrap(err, "failed to parse installation event payload")
+		}
+		h.handleInstallation(ctx, &event)
+
+	case "installation_repositories":
+		var event github.InstallationRepositoriesEvent
+		if err := unmarshalPayload(ctx, payload, &event); err != nil {
+			return errors.Wrap(err, "failed to parse installation repositories event payload")
+		}
+		h.handleInstallationRepositories(ctx, &event)
+
+	case "github_app_authorization":
+		var event github.GitHubAppAuthorizationEvent
+		if err := unmarshalPayload(ctx, payload, &event); err != nil {
+			return errors.Wrap(err, "failed to parse app authorization event payload")
+		}
+		h.handleAuthorization(ctx, &event)
+
+	case "repository":
+		var event github.RepositoryEvent
+		if err := unmarshalPayload(ctx, payload, &event); err != nil {
+			return errors.Wrap(err, "failed to parse repository event payload")
+		}
+		h.handleRepository(ctx, &event)
+	}
+	return nil
+}
+
+func (h *cacheHandler) handleInstallation(ctx context.Context, event *github.InstallationEvent) {
+	installationID := event.GetInstallation().GetID()
+
+	zerolog.Ctx(ctx).Debug().Msgf("Invalidating caches for installation %d after %q action", installationID, event.GetAction())
+
+	h.invalidateClients(installationID)
+	if h.installations != nil {
+		h.installations.InvalidateInstallation(installationID)
+		h.installations.InvalidateOwner(event.GetInstallation().GetAccount().GetLogin())
+	}
+	for _, r := range event.Repositories {
+		h.invalidateRepository(r.GetFullName())
+	}
+}
+
+func (h *cacheHandler) handleInstallationRepositories(ctx context.Context, event *github.InstallationRepositoriesEvent) {
+	installationID := event.GetInstallation().GetID()
+
+	zerolog.Ctx(ctx).Debug().Msgf("Invalidating caches for installation %d after %q action", installationID, event.GetAction())
+
+	// tokens are scoped to the repositories available when they were created
+	h.invalidateClients(installationID)
+	for _, r := range event.RepositoriesAdded {
+		h.invalidateRepository(r.GetFullName())
+	}
+	for _, r := range event.RepositoriesRemoved {
+		h.invalidateRepository(r.GetFullName())
+	}
+}
+
+func (h *cacheHandler) handleAuthorization(ctx context.Context, event *github.GitHubAppAuthorizationEvent) {
+	if event.GetAction() != "revoked" {
+		return
+	}
+
+	login := event.GetSender().GetLogin()
+	zerolog.Ctx(ctx).Debug().Msgf("Invalidating caches for user %s after authorization was revoked", login)
+
+	for _, c := range h.users {
+		c.InvalidateUser(login)
+	}
+}
+
+func (h *cacheHandler) handleRepository(ctx context.Context, event *github.RepositoryEvent) {
+	repo := event.GetRepo()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+
+	switch event.GetAction() {
+	case "renamed":
+		if from := event.GetChanges().GetRepo().GetName().GetFrom(); from != "" {
+			h.invalidateRepository(owner + "/" + from)
+		}
+	case "transferred":
+		from := event.GetChanges().GetOwner().GetOwnerInfo()
+		if login := from.GetOrg().GetLogin(); login != "" {
+			h.invalidateRepository(login + "/" + name)
+		}
+		if login := from.GetUser().GetLogin(); login != "" {
+			h.invalidateRepository(login + "/" + name)
+		}
+	case "deleted":
+	default:
+		return
+	}
+
+	zerolog.Ctx(ctx).Debug().Msgf("Invalidating caches for repository %s/%s after %q action", owner, name, event.GetAction())
+	h.invalidateRepository(owner + "/" + name)
+}
+
+func (h *cacheHandler) invalidateClients(installationID int64) {
+	if h.clients != nil && installationID > 0 {
+		h.clients.Invalidate(installationID)
+	}
+}
+
+func (h *cacheHandler) invalidateRepository(fullName string) {
+	owner, name, ok := strings.Cut(fullName, "/")
+	if !ok || owner == "" || name == "" {
+		return
+	}
+
+	if h.installations != nil {
+		h.installations.InvalidateRepository(owner, name)
+	}
+	for _, c := range h.repositories {
+		c.InvalidateRepository(owner, name)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+type recordingInvalidator struct {
+	clients []int64
+	repos   []string
+	users   []string
+}
+
+func (r *recordingInvalidator) Invalidate(installationID int64) {
+	r.clients = append(r.clients, installationID)
+}
+
+func (r *recordingInvalidator) InvalidateRepository(owner, repo string) {
+	r.repos = append(r.repos, owner+"/"+repo)
+}
+
+func (r *recordingInvalidator) InvalidateUser(login string) {
+	r.users = append(r.users, login)
+}
+
+type staticInstallationsService struct {
+	InstallationsService
+}
+
+func (s staticInstallationsService) GetByOwner(ctx context.Context, owner string) (Installation, error) {
+	return Installation{ID: 1, Owner: owner}, nil
+}
+
+func (s staticInstallationsService) GetByRepository(ctx context.Context, owner, name string) (Installation, error) {
+	return Installation{ID: 1, Owner: owner}, nil
+}
+
+func TestCacheHandler(t *testing.T) {
+	tests := map[string]struct {
+		Type    string
+		Payload string
+		Clients []int64
+		Repos   []string
+		Users   []string
+	}{
+		"installationDeleted": {
+			Type:    "installation",
+			Payload: `{"action": "deleted", "installation": {"id": 1, "account": {"login": "octo"}}, "repositories": [{"full_name": "octo/a"}, {"full_name": "octo/b"}]}`,
+			Clients: []int64{1},
+			Repos:   []string{"octo/a", "octo/b"},
+		},
+		"repositoriesChanged": {
+			Type:    "installation_repositories",
+			Payload: `{"action": "removed", "installation": {"id": 2}, "repositories_added": [{"full_name": "octo/a"}], "repositories_removed": [{"full_name": "octo/b"}]}`,
+			Clients: []int64{2},
+			Repos:   []string{"octo/a", "octo/b"},
+		},
+		"authorizationRevoked": {
+			Type:    "github_app_authorization",
+			Payload: `{"action": "revoked", "sender": {"login": "mhaypenny"}}`,
+			Users:   []string{"mhaypenny"},
+		},
+		"repositoryRenamed": {
+			Type:    "repository",
+			Payload: `{"action": "renamed", "changes": {"repository": {"name": {"from": "old"}}}, "repository": {"name": "new", "owner": {"login": "octo"}}}`,
+			Repos:   []string{"octo/new", "octo/old"},
+		},
+		"repositoryTransferred": {
+			Type:    "repository",
+			Payload: `{"action": "transferred", "changes": {"owner": {"from": {"organization": {"login": "old-org"}}}}, "repository": {"name": "repo", "owner": {"login": "octo"}}}`,
+			Repos:   []string{"octo/repo", "old-org/repo"},
+		},
+		"repositoryEdited": {
+			Type:    "repository",
+			Payload: `{"action": "edited", "repository": {"name": "repo", "owner": {"login": "octo"}}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := &recordingInvalidator{}
+			cc := struct {
+				ClientCreator
+				ClientInvalidator
+			}{nil, rec}
+
+			h := NewCacheHandler(
+				WithCachedClients(cc),
+				WithRepositoryCaches(rec),
+				WithUserCaches(rec),
+			)
+			if err := h.Handle(context.Background(), test.Type, "", []byte(test.Payload)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			sort.Strings(rec.repos)
+			if !reflect.DeepEqual(test.Clients, rec.clients) {
+				t.Errorf("incorrect client invalidations: expected %v, actual %v", test.Clients, rec.clients)
+			}
+			if !reflect.DeepEqual(test.Repos, rec.repos) {
+				t.Errorf("incorrect repository invalidations: expected %v, actual %v", test.Repos, rec.repos)
+			}
+			if !reflect.DeepEqual(test.Users, rec.users) {
+				t.Errorf("incorrect user invalidations: expected %v, actual %v", test.Users, rec.users)
+			}
+		})
+	}
+}
+
+func TestCacheHandlerInstallations(t *testing.T) {
+	ctx := context.Background()
+
+	s := NewCachingInstallationsService(staticInstallationsService{}, time.Hour, time.Hour)
+	_, _ = s.GetByOwner(ctx, "octo")
+	_, _ = s.GetByRepository(ctx, "octo", "a")
+	_, _ = s.GetByRepository(ctx, "other", "b")
+
+	cache := s.(*cachingInstallationsService).cache
+	if n := cache.ItemCount(); n != 3 {
+		t.Fatalf("expected 3 cached installations, but got %d", n)
+	}
+
+	h := NewCacheHandler(WithCachedInstallations(s))
+
+	payload := `{"action": "removed", "installation": {"id": 2}, "repositories_removed": [{"full_name": "other/b"}]}`
+	if err := h.Handle(ctx, "installation_repositories", "", []byte(payload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cache.Get("other/b"); ok {
+		t.Error("expected removed repository to be invalidated")
+	}
+
+	payload = `{"action": "deleted", "installation": {"id": 1, "account": {"login": "octo"}}}`
+	if err := h.Handle(ctx, "installation", "", []byte(payload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := cache.ItemCount(); n != 0 {
+		t.Errorf("expected all installations to be invalidated, but %d remain", n)
+	}
+}
@@ -15,7 +15,11 @@
 package githubapp
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v66/github"
 	lru "github.com/hashicorp/golang-lru"
@@ -26,39 +30,152 @@ import (
 
 const (
 	DefaultCachingClientCapacity = 64
+	MaxCachingClientShards       = 32
 )
 
 // NewDefaultCachingClientCreator returns a ClientCreator using values from the
 // configuration or other defaults.
 func NewDefaultCachingClientCreator(c Config, opts ...ClientOption) (ClientCreator, error) {
-	delegate := NewClientCreator(
-		c.V3APIURL,
-		c.V4APIURL,
-		c.App.IntegrationID,
-		[]byte(c.App.PrivateKey),
-		opts...,
-	)
-	return NewCachingClientCreator(delegate, DefaultCachingClientCapacity)
+	return NewCachingClientCreator(NewClientCreatorFromConfig(c, opts...), DefaultCachingClientCapacity)
+}
+
+// ClientInvalidator is implemented by ClientCreators that cache installation
+// clients. Use a type assertion to access it from a ClientCreator.
+type ClientInvalidator interface {
+	// Invalidate removes all cached clients for an installation. Callers
+	// should invalidate clients when an installation changes, for example
+	// when the application is reinstalled or its permissions change.
+	Invalidate(installationID int64)
+}
+
+// CachingClientOption configures properties of a caching client creator.
+type CachingClientOption func(*cachingClientCreator)
+
+// WithCachingShards sets the number of independent LRU caches used to store
+// clients. Sharding reduces lock contention when many installations create
+// clients concurrently. The capacity is divided evenly between shards. By
+// default, the creator uses one shard for every DefaultCachingClientCapacity
+// clients of capacity, up to MaxCachingClientShards.
+func WithCachingShards(shards int) CachingClientOption {
+	return func(c *cachingClientCreator) {
+		if shards > 0 {
+			c.shards = make([]*lru.Cache, shards)
+		}
+	}
+}
+
+// WithCachingTTL sets the maximum age of cached clients. Older clients are
+// replaced with new clients, which picks up changes to the delegate's
+// configuration. By default, clients do not expire. Creating clients does not
+// take a context, so the age is measured with SystemClock.
+func WithCachingTTL(ttl time.Duration) CachingClientOption {
+	return func(c *cachingClientCreator) {
+		c.ttl = ttl
+	}
 }
 
 // NewCachingClientCreator returns a ClientCreator that creates a GitHub client for installations of the app specified
 // by the provided arguments. It uses an LRU cache of the provided capacity to store clients created for installations
-// and returns cached clients when a cache hit exists.
-func NewCachingClientCreator(delegate ClientCreator, capacity int) (ClientCreator, error) {
-	cache, err := lru.New(capacity)
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to create cache")
+// and returns cached clients when a cache hit exists. The returned ClientCreator implements ClientInvalidator,
+// ClientWarmer, and RepositoryClientCreator.
+func NewCachingClientCreator(delegate ClientCreator, capacity int, opts ...CachingClientOption) (ClientCreator, error) {
+	c := &cachingClientCreator{
+		delegate: delegate,
+		clock:    SystemClock,
+		warm:     make(map[int64]*github.Client),
 	}
 
-	return &cachingClientCreator{
-		cachedClients: cache,
-		delegate:      delegate,
-	}, nil
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.shards == nil {
+		n := capacity / DefaultCachingClientCapacity
+		switch {
+		case n < 1:
+			n = 1
+		case n > MaxCachingClientShards:
+			n = MaxCachingClientShards
+		}
+		c.shards = make([]*lru.Cache, n)
+	}
+
+	shardCapacity := capacity / len(c.shards)
+	if capacity > 0 && shardCapacity < 1 {
+		shardCapacity = 1
+	}
+	for i := range c.shards {
+		cache, err := lru.New(shardCapacity)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create cache")
+		}
+		c.shards[i] = cache
+	}
+
+	return c, nil
 }
 
 type cachingClientCreator struct {
-	cachedClients *lru.Cache
-	delegate      ClientCreator
+	shards   []*lru.Cache
+	ttl      time.Duration
+	clock    Clock // only replaced in tests
+	delegate ClientCreator
+
+	warmIDs []int64
+	warmMu  sync.RWMutex
+	warm    map[int64]*github.Client
+}
+
+type cachedClient struct {
+	client  interface{}
+	created time.Time
+}
+
+func (c *cachingClientCreator) shard(installationID int64) *lru.Cache {
+	i := installationID % int64(len(c.shards))
+	if i < 0 {
+		i = -i
+	}
+	return c.shards[i]
+}
+
+func (c *cachingClientCreator) get(apiVersion string, installationID int64) (interface{}, bool) {
+	shard := c.shard(installationID)
+	key := c.toCacheKey(apiVersion, installationID)
+
+	val, ok := shard.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	cached := val.(cachedClient)
+	if c.ttl > 0 && c.clock.Now().Sub(cached.created) > c.ttl {
+		shard.Remove(key)
+		return nil, false
+	}
+	return cached.client, true
+}
+
+func (c *cachingClientCreator) add(apiVersion string, installationID int64, client interface{}) {
+	c.shard(installationID).Add(c.toCacheKey(apiVersion, installationID), cachedClient{
+		client:  client,
+		created: c.clock.Now(),
+	})
+}
+
+func (c *cachingClientCreator) Invalidate(installationID int64) {
+	shard := c.shard(installationID)
+	shard.Remove(c.toCacheKey("v3", installationID))
+	shard.Remove(c.toCacheKey("v4", installationID))
+
+	// repository clients have a key for each repository and permissions
+	suffix := fmt.Sprintf(":%d", installationID)
+	for _, key := range shard.Keys() {
+		if k, ok := key.(string); ok && strings.HasPrefix(k, "repo:") && strings.HasSuffix(k, suffix) {
+			shard.Remove(key)
+		}
+	}
+	c.invalidateWarm(installationID)
 }
 
 func (c *cachingClientCreator) NewAppClient() (*github.Client, error) {
@@ -72,10 +189,12 @@ func (c *cachingClientCreator) NewAppV4Client() (*githubv4.Client, error) {
 }
 
 func (c *cachingClientCreator) NewInstallationClient(installationID int64) (*github.Client, error) {
+	if client, ok := c.getWarm(installationID); ok {
+		return client, nil
+	}
+
 	// if client is in cache, return it
-	key := c.toCacheKey("v3", installationID)
-	val, ok := c.cachedClients.Get(key)
-	if ok {
+	if val, ok := c.get("v3", installationID); ok {
 		if client, ok := val.(*github.Client); ok {
 			return client, nil
 		}
@@ -86,15 +205,13 @@ func (c *cachingClientCreator) NewInstallationClient(installationID int64) (*git
 	if err != nil {
 		return nil, err
 	}
-	c.cachedClients.Add(key, client)
+	c.add("v3", installationID, client)
 	return client, nil
 }
 
 func (c *cachingClientCreator) NewInstallationV4Client(installationID int64) (*githubv4.Client, error) {
 	// if client is in cache, return it
-	key := c.toCacheKey("v4", installationID)
-	val, ok := c.cachedClients.Get(key)
-	if ok {
+	if val, ok := c.get("v4", installationID); ok {
 		if client, ok := val.(*githubv4.Client); ok {
 			return client, nil
 		}
@@ -105,7 +222,31 @@ func (c *cachingClientCreator) NewInstallationV4Client(installationID int64) (*g
 	if err != nil {
 		return nil, err
 	}
-	c.cachedClients.Add(key, client)
+	c.add("v4", installationID, client)
+	return client, nil
+}
+
+func (c *cachingClientCreator) NewRepositoryClient(ctx context.Context, installationID int64, repo string, perms *github.InstallationPermissions) (*github.Client, error) {
+	key, err := repositoryCacheKey(repo, perms)
+	if err != nil {
+		return nil, err
+	}
+
+	// if client is in cache, return it
+	if val, ok := c.get(key, installationID); ok {
+		if client, ok := val.(*github.Client); ok {
+			return client, nil
+		}
+	}
+
+	// otherwise, create and return; the token source outlives the context
+	// of the first caller, so it must not be canceled with it
+	ts := NewRepositoryTokenSource(context.WithoutCancel(ctx), c.delegate, installationID, repo, perms)
+	client, err := c.delegate.NewTokenSourceClient(ts)
+	if err != nil {
+		return nil, err
+	}
+	c.add(key, installationID, client)
 	return client, nil
 }
 
@@ -0,0 +1,93 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+type countingClientCreator struct {
+	ClientCreator
+	count int
+}
+
+func (c *countingClientCreator) NewInstallationClient(installationID int64) (*github.Client, error) {
+	c.count++
+	return github.NewClient(nil), nil
+}
+
+func TestCachingClientCreator(t *testing.T) {
+	t.Run("cachesClients", func(t *testing.T) {
+		delegate := &countingClientCreator{}
+		cc, err := NewCachingClientCreator(delegate, 256, WithCachingShards(4))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, id := range []int64{1, 2, 3, 4, 5, 1, 2, 3, 4, 5} {
+			if _, err := cc.NewInstallationClient(id); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if delegate.count != 5 {
+			t.Errorf("expected 5 created clients, but got %d", delegate.count)
+		}
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		delegate := &countingClientCreator{}
+		cc, err := NewCachingClientCreator(delegate, DefaultCachingClientCapacity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		first, _ := cc.NewInstallationClient(1)
+		cc.(ClientInvalidator).Invalidate(1)
+		second, _ := cc.NewInstallationClient(1)
+
+		if first == second {
+			t.Error("expected a new client after invalidation")
+		}
+		if delegate.count != 2 {
+			t.Errorf("expected 2 created clients, but got %d", delegate.count)
+		}
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		delegate := &countingClientCreator{}
+		clock := newTestClock()
+		cc, err := NewCachingClientCreator(delegate, DefaultCachingClientCapacity, WithCachingTTL(time.Minute))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		cc.(*cachingClientCreator).clock = clock
+
+		_, _ = cc.NewInstallationClient(1)
+		clock.Advance(time.Minute)
+		_, _ = cc.NewInstallationClient(1)
+		if delegate.count != 1 {
+			t.Errorf("expected 1 created client before the TTL, but got %d", delegate.count)
+		}
+
+		clock.Advance(time.Second)
+		_, _ = cc.NewInstallationClient(1)
+		if delegate.count != 2 {
+			t.Errorf("expected 2 created clients, but got %d", delegate.count)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// StopProcessing is returned by handlers in a HandlerChain to prevent later
+// handlers in the chain from running for the current delivery. It is not a
+// failure: the chain logs the reason and returns nil.
+type StopProcessing struct {
+	Reason string
+}
+
+func (s StopProcessing) Error() string {
+	if s.Reason == "" {
+		return "stop processing"
+	}
+	return fmt.Sprintf("stop processing: %s", s.Reason)
+}
+
+// HandlerChain returns an EventHandler that sends each event to the handlers
+// in handlers that handle the event type, in order, until one of them returns
+// an error. Use it to put guard handlers, like spam filters or permission
+// checks, in front of the handlers that implement features.
+//
+// If a handler returns StopProcessing, possibly wrapped, the chain skips the
+// remaining handlers and returns nil. If a handler returns any other error,
+// the chain skips the remaining handlers and returns the error.
+func HandlerChain(handlers ...EventHandler) EventHandler {
+	h := &chainHandler{
+		handlerMap: make(map[string][]int),
+		handlers:   handlers,
+	}
+	for i, handler := range handlers {
+		for _, event := range handler.Handles() {
+			if !slices.Contains(h.handlerMap[event], i) {
+				h.handlerMap[event] = append(h.handlerMap[event], i)
+			}
+		}
+	}
+	return h
+}
+
+type chainHandler struct {
+	handlerMap map[string][]int
+	handlers   []EventHandler
+}
+
+func (h *chainHandler) Handles() []string {
+	events := make([]string, 0, len(h.handlerMap))
+	for event := range h.handlerMap {
+		events = append(events, event)
+	}
+	return events
+}
+
+func (h *chainHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	for _, i := range h.handlerMap[eventType] {
+		err := h.handlers[i].Handle(ctx, eventType, deliveryID, payload)
+		if err == nil {
+			continue
+		}
+
+		var stop StopProcessing
+		if errors.As(err, &stop) {
+			zerolog.Ctx(ctx).Debug().Msgf("Handler %d (%T) stopped processing: %s", i, h.handlers[i], stop.Reason)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestHandlerChain(t *testing.T) {
+	errGuard := errors.New("guard failed")
+
+	tests := map[string]struct {
+		GuardErr error
+		Event    string
+
+		Err          error
+		GuardCount   int
+		FeatureCount int
+	}{
+		"runsAllHandlers": {
+			Event:        "issue_comment",
+			GuardCount:   1,
+			FeatureCount: 1,
+		},
+		"stopProcessing": {
+			GuardErr:   StopProcessing{Reason: "sender is blocked"},
+			Event:      "issue_comment",
+			GuardCount: 1,
+		},
+		"wrappedStopProcessing": {
+			GuardErr:   errors.Wrap(StopProcessing{}, "guard"),
+			Event:      "issue_comment",
+			GuardCount: 1,
+		},
+		"errorStopsChain": {
+			GuardErr:   errGuard,
+			Event:      "issue_comment",
+			Err:        errGuard,
+			GuardCount: 1,
+		},
+		"skipsHandlersForOtherEvents": {
+			GuardErr:     StopProcessing{},
+			Event:        "pull_request",
+			FeatureCount: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			guard := &TestEventHandler{
+				Types: []string{"issue_comment"},
+				Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+					return test.GuardErr
+				},
+			}
+			feature := &TestEventHandler{Types: []string{"issue_comment", "pull_request"}}
+
+			err := HandlerChain(guard, feature).Handle(context.Background(), test.Event, "id", []byte(`{}`))
+			if !errors.Is(err, test.Err) {
+				t.Errorf("incorrect error: expected %v, actual %v", test.Err, err)
+			}
+			if guard.Count != test.GuardCount {
+				t.Errorf("incorrect guard call count: expected %d, actual %d", test.GuardCount, guard.Count)
+			}
+			if feature.Count != test.FeatureCount {
+				t.Errorf("incorrect feature call count: expected %d, actual %d", test.FeatureCount, feature.Count)
+			}
+		})
+	}
+
+	t.Run("handles", func(t *testing.T) {
+		h := HandlerChain(
+			&TestEventHandler{Types: []string{"issue_comment"}},
+			&TestEventHandler{Types: []string{"issue_comment", "pull_request"}},
+		)
+
+		events := h.Handles()
+		sort.Strings(events)
+		if strings.Join(events, ",") != "issue_comment,pull_request" {
+			t.Errorf("incorrect events: %v", events)
+		}
+	})
+}
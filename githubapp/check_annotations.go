@@ -0,0 +1,228 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxAnnotationsPerRequest is the largest number of annotations GitHub
+	// accepts in one request to create or update a check run.
+	MaxAnnotationsPerRequest = 50
+
+	// DefaultAnnotationInterval is the default time between requests made by
+	// UploadCheckRunAnnotations. GitHub recommends waiting at least a second
+	// between requests that create content to avoid secondary rate limits.
+	DefaultAnnotationInterval = time.Second
+)
+
+const (
+	maxAnnotationTitleSize      = 255
+	maxAnnotationMessageSize    = 64 << 10
+	maxAnnotationRawDetailsSize = 64 << 10
+)
+
+// AnnotationProgress describes the progress of UploadCheckRunAnnotations.
+type AnnotationProgress struct {
+	// Uploaded is the number of annotations uploaded so far.
+	Uploaded int
+
+	// Total is the number of annotations that will be uploaded, after
+	// applying MaxAnnotations.
+	Total int
+}
+
+// AnnotationUploadOptions configures UploadCheckRunAnnotations.
+type AnnotationUploadOptions struct {
+	// Title and Summary are the output of the check run. GitHub requires
+	// them in every request that adds annotations.
+	Title   string
+	Summary string
+
+	// MaxAnnotations is the largest number of annotations to upload. If it
+	// is zero, all annotations are uploaded.
+	MaxAnnotations int
+
+	// Interval is the time between requests. If it is zero, requests are
+	// made every DefaultAnnotationInterval. If it is negative, requests are
+	// not paced.
+	Interval time.Duration
+
+	// MaxRateLimitWait is the longest time to wait for a rate limit to reset
+	// before retrying a request. If a request fails because of a rate limit
+	// and GitHub asks the client to wait longer, if this is zero, or if the
+	// RetryBudget of the context is exhausted, the upload stops and returns
+	// the error.
+	MaxRateLimitWait time.Duration
+
+	// Progress, if set, is called after each successful request.
+	Progress func(AnnotationProgress)
+}
+
+// AnnotationUploadResult describes the result of UploadCheckRunAnnotations.
+type AnnotationUploadResult struct {
+	// Uploaded is the number of annotations added to the check run.
+	Uploaded int
+
+	// Truncated is the number of annotations that were not uploaded because
+	// of MaxAnnotations or because GitHub rejected more annotations for the
+	// check run.
+	Truncated int
+}
+
+// UploadCheckRunAnnotations adds annotations to an existing check run. It
+// sends them in batches of MaxAnnotationsPerRequest, waits between requests
+// to avoid secondary rate limits, and shortens text fields that are longer
+// than GitHub allows. Annotations are not modified and nil annotations are
+// skipped.
+//
+// If there are more annotations than MaxAnnotations or GitHub rejects a batch
+// because the check run has too many annotations, the remaining annotations
+// are counted in the result as truncated and the summary of the check run is
+// updated to say how many annotations are missing. Other errors stop the
+// upload and are returned with the result so far.
+func UploadCheckRunAnnotations(ctx context.Context, client *github.Client, owner, repo string, checkRunID int64, annotations []*github.CheckRunAnnotation, opts AnnotationUploadOptions) (AnnotationUploadResult, error) {
+	var result AnnotationUploadResult
+
+	annotations = slices.DeleteFunc(slices.Clone(annotations), func(a *github.CheckRunAnnotation) bool {
+		return a == nil
+	})
+	if opts.MaxAnnotations > 0 && len(annotations) > opts.MaxAnnotations {
+		result.Truncated = len(annotations) - opts.MaxAnnotations
+		annotations = annotations[:opts.MaxAnnotations]
+	}
+
+	interval := opts.Interval
+	if interval == 0 {
+		interval = DefaultAnnotationInterval
+	}
+
+	var reachedLimit bool
+	total := len(annotations)
+	for start := 0; start < total; start += MaxAnnotationsPerRequest {
+		if start > 0 && interval > 0 {
+			if err := sleepContext(ctx, interval); err != nil {
+				return result, err
+			}
+		}
+
+		batch := annotations[start:min(start+MaxAnnotationsPerRequest, total)]
+		err := updateCheckRunOutput(ctx, client, owner, repo, checkRunID, opts, &github.CheckRunOutput{
+			Title:       github.String(opts.Title),
+			Summary:     github.String(opts.Summary),
+			Annotations: shortenAnnotations(batch),
+		})
+		if isAnnotationLimitError(err) {
+			result.Truncated += total - start
+			reachedLimit = true
+			break
+		}
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to upload annotations to check run %d", checkRunID)
+		}
+
+		result.Uploaded += len(batch)
+		if opts.Progress != nil {
+			opts.Progress(AnnotationProgress{Uploaded: result.Uploaded, Total: total})
+		}
+	}
+
+	if result.Truncated > 0 {
+		reason := "the check run reached the annotation limit"
+		if !reachedLimit {
+			reason = fmt.Sprintf("at most %d annotations are reported", opts.MaxAnnotations)
+		}
+		summary := fmt.Sprintf("%s\n\n_%d annotations were not reported because %s._", opts.Summary, result.Truncated, reason)
+		err := updateCheckRunOutput(ctx, client, owner, repo, checkRunID, opts, &github.CheckRunOutput{
+			Title:   github.String(opts.Title),
+			Summary: github.String(strings.TrimSpace(summary)),
+		})
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to report truncated annotations for check run %d", checkRunID)
+		}
+	}
+
+	return result, nil
+}
+
+func updateCheckRunOutput(ctx context.Context, client *github.Client, owner, repo string, checkRunID int64, opts AnnotationUploadOptions, output *github.CheckRunOutput) error {
+	for {
+		_, _, err := client.Checks.UpdateCheckRun(ctx, owner, repo, checkRunID, github.UpdateCheckRunOptions{
+			Output: output,
+		})
+		if err == nil {
+			return nil
+		}
+
+		err = ClassifyError(err)
+		delay, ok := rateLimitDelay(err, GetClock(ctx).Now())
+		if !ok || delay > opts.MaxRateLimitWait || !GetRetryBudget(ctx).Acquire() {
+			return err
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// isAnnotationLimitError returns true if GitHub rejected annotations because
+// the check run has too many. GitHub reports this as a validation failure of
+// the annotations field.
+func isAnnotationLimitError(err error) bool {
+	var unprocessable *UnprocessableError
+	if !errors.As(err, &unprocessable) {
+		return false
+	}
+	for _, e := range unprocessable.Errors {
+		if e.Field == "annotations" && (e.Code == "too_many" || strings.Contains(e.Message, "limit")) {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(unprocessable.Message), "annotation limit")
+}
+
+// shortenAnnotations returns copies of annotations with text fields
+// shortened to the maximum sizes allowed by GitHub.
+func shortenAnnotations(annotations []*github.CheckRunAnnotation) []*github.CheckRunAnnotation {
+	shortened := make([]*github.CheckRunAnnotation, len(annotations))
+	for i, a := range annotations {
+		c := *a
+		c.Title = shortenString(c.Title, maxAnnotationTitleSize)
+		c.Message = shortenString(c.Message, maxAnnotationMessageSize)
+		c.RawDetails = shortenString(c.RawDetails, maxAnnotationRawDetailsSize)
+		shortened[i] = &c
+	}
+	return shortened
+}
+
+func shortenString(s *string, size int) *string {
+	if s == nil || len(*s) <= size {
+		return s
+	}
+
+	const ellipsis = "..."
+	short := (*s)[:size-len(ellipsis)]
+	// avoid splitting multi-byte characters
+	short = strings.ToValidUTF8(short, "")
+	return github.String(short + ellipsis)
+}
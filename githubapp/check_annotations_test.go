@@ -0,0 +1,166 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestUploadCheckRunAnnotations(t *testing.T) {
+	newAnnotations := func(n int) []*github.CheckRunAnnotation {
+		annotations := make([]*github.CheckRunAnnotation, n)
+		for i := range annotations {
+			annotations[i] = &github.CheckRunAnnotation{
+				Path:            github.String("main.go"),
+				StartLine:       github.Int(i + 1),
+				EndLine:         github.Int(i + 1),
+				AnnotationLevel: github.String("warning"),
+				Message:         github.String(fmt.Sprintf("problem %d", i)),
+			}
+		}
+		return annotations
+	}
+
+	tests := map[string]struct {
+		Annotations    int
+		NilAnnotations int
+		MaxAnnotations int
+		GitHubLimit    int
+
+		Batches   []int
+		Uploaded  int
+		Truncated int
+	}{
+		"singleBatch": {
+			Annotations: 10,
+			Batches:     []int{10},
+			Uploaded:    10,
+		},
+		"nilAnnotations": {
+			Annotations:    10,
+			NilAnnotations: 3,
+			Batches:        []int{10},
+			Uploaded:       10,
+		},
+		"multipleBatches": {
+			Annotations: 120,
+			Batches:     []int{50, 50, 20},
+			Uploaded:    120,
+		},
+		"maxAnnotations": {
+			Annotations:    120,
+			MaxAnnotations: 60,
+			Batches:        []int{50, 10, 0},
+			Uploaded:       60,
+			Truncated:      60,
+		},
+		"githubLimit": {
+			Annotations: 150,
+			GitHubLimit: 100,
+			Batches:     []int{50, 50, 0},
+			Uploaded:    100,
+			Truncated:   50,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var batches []int
+			var summary string
+			var stored int
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("PATCH /repos/octo/repo/check-runs/1", func(w http.ResponseWriter, r *http.Request) {
+				var opts github.UpdateCheckRunOptions
+				_ = json.NewDecoder(r.Body).Decode(&opts)
+
+				n := len(opts.Output.Annotations)
+				if test.GitHubLimit > 0 && stored+n > test.GitHubLimit {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					_, _ = io.WriteString(w, `{"message": "Validation Failed", "errors": [{"resource": "CheckRun", "field": "annotations", "code": "too_many"}]}`)
+					return
+				}
+				stored += n
+				batches = append(batches, n)
+				summary = opts.Output.GetSummary()
+				_, _ = io.WriteString(w, `{"id": 1}`)
+			})
+			cc := newStaticClientCreator(t, mux)
+
+			annotations := newAnnotations(test.Annotations)
+			for i := 0; i < test.NilAnnotations; i++ {
+				annotations = slices.Insert(annotations, i*2, nil)
+			}
+
+			var progress []AnnotationProgress
+			result, err := UploadCheckRunAnnotations(context.Background(), cc.client, "octo", "repo", 1, annotations, AnnotationUploadOptions{
+				Title:          "Lint",
+				Summary:        "Found problems",
+				MaxAnnotations: test.MaxAnnotations,
+				Interval:       -1,
+				Progress: func(p AnnotationProgress) {
+					progress = append(progress, p)
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fmt.Sprint(batches) != fmt.Sprint(test.Batches) {
+				t.Errorf("incorrect batches: expected %v, actual %v", test.Batches, batches)
+			}
+			if result.Uploaded != test.Uploaded || result.Truncated != test.Truncated {
+				t.Errorf("incorrect result: %+v", result)
+			}
+			if len(progress) == 0 || progress[len(progress)-1].Uploaded != test.Uploaded {
+				t.Errorf("incorrect progress: %+v", progress)
+			}
+			if truncated := strings.Contains(summary, "were not reported"); truncated != (test.Truncated > 0) {
+				t.Errorf("incorrect truncation report in summary: %q", summary)
+			}
+			if limit := strings.Contains(summary, "reached the annotation limit"); limit != (test.GitHubLimit > 0) {
+				t.Errorf("incorrect truncation reason in summary: %q", summary)
+			}
+		})
+	}
+}
+
+func TestShortenAnnotations(t *testing.T) {
+	long := strings.Repeat("é", maxAnnotationTitleSize)
+	original := &github.CheckRunAnnotation{Title: github.String(long), Message: github.String("short")}
+
+	shortened := shortenAnnotations([]*github.CheckRunAnnotation{original})[0]
+	if len(shortened.GetTitle()) > maxAnnotationTitleSize {
+		t.Errorf("title was not shortened: %d bytes", len(shortened.GetTitle()))
+	}
+	if !strings.HasSuffix(shortened.GetTitle(), "é...") {
+		t.Errorf("title was not shortened on a character boundary: %q", shortened.GetTitle()[len(shortened.GetTitle())-8:])
+	}
+	if shortened.GetMessage() != "short" {
+		t.Errorf("message was modified: %q", shortened.GetMessage())
+	}
+	if original.GetTitle() != long {
+		t.Error("original annotation was modified")
+	}
+}
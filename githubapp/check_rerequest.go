@@ -0,0 +1,151 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// PreviousCheckRuns returns the latest check run with each name that the app
+// with appID created for a commit. Use it to find the runs to recreate when a
+// user asks GitHub to run the checks for a commit again.
+func PreviousCheckRuns(ctx context.Context, client *github.Client, owner, repo, headSHA string, appID int64) ([]*github.CheckRun, error) {
+	var runs []*github.CheckRun
+
+	opts := &github.ListCheckRunsOptions{
+		AppID:       github.Int64(appID),
+		Filter:      github.String("latest"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		res, resp, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, headSHA, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list check runs for %s in %s/%s", headSHA, owner, repo)
+		}
+		for _, run := range res.CheckRuns {
+			// GitHub ignores the app filter for some tokens, so check again
+			if run.GetApp().GetID() == appID {
+				runs = append(runs, run)
+			}
+		}
+		if resp.NextPage == 0 {
+			return runs, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// RecreateCheckRuns creates a new queued check run for a commit for each of
+// the previous runs. The new runs keep the name, external ID, and details URL
+// of the previous runs, so the app can find the work to repeat from the
+// external ID. It returns the new runs in the same order as the previous runs
+// and stops at the first error, returning the runs created so far.
+func RecreateCheckRuns(ctx context.Context, client *github.Client, owner, repo, headSHA string, previous []*github.CheckRun) ([]*github.CheckRun, error) {
+	runs := make([]*github.CheckRun, 0, len(previous))
+	for _, p := range previous {
+		run, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, github.CreateCheckRunOptions{
+			Name:       p.GetName(),
+			HeadSHA:    headSHA,
+			ExternalID: p.ExternalID,
+			DetailsURL: p.DetailsURL,
+			Status:     github.String("queued"),
+		})
+		if err != nil {
+			return runs, errors.Wrapf(err, "failed to recreate check run %q for %s in %s/%s", p.GetName(), headSHA, owner, repo)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// CheckRerequestFunc is called with the check runs that were recreated
+// because a user asked to run them again. The runs are queued; the function
+// should start the work identified by their external IDs and update them
+// as it progresses.
+type CheckRerequestFunc func(ctx context.Context, client *github.Client, repo *github.Repository, runs []*github.CheckRun) error
+
+type checkRerequestHandler struct {
+	cc ClientCreator
+	fn CheckRerequestFunc
+}
+
+// NewCheckRerequestHandler returns an EventHandler for "check_suite" and
+// "check_run" events with the "rerequested" action. For a check suite, it
+// recreates the latest runs the app created for the head commit of the suite.
+// For a check run, it recreates only that run. It then calls fn with the new
+// runs and an installation client. Other actions are ignored.
+func NewCheckRerequestHandler(cc ClientCreator, fn CheckRerequestFunc) EventHandler {
+	return &checkRerequestHandler{cc: cc, fn: fn}
+}
+
+func (h *checkRerequestHandler) Handles() []string {
+	return Handles(EventCheckSuite, EventCheckRun)
+}
+
+func (h *checkRerequestHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event struct {
+		Action       string               `json:"action"`
+		CheckSuite   *github.CheckSuite   `json:"check_suite"`
+		CheckRun     *github.CheckRun     `json:"check_run"`
+		Repo         *github.Repository   `json:"repository"`
+		Installation *github.Installation `json:"installation"`
+	}
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrapf(err, "failed to parse %s event payload", eventType)
+	}
+	if event.Action != "rerequested" {
+		return nil
+	}
+
+	installationID := event.Installation.GetID()
+	ctx, logger := PrepareRepoContext(ctx, installationID, event.Repo)
+
+	client, err := h.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return err
+	}
+
+	owner, repo := event.Repo.GetOwner().GetLogin(), event.Repo.GetName()
+
+	var headSHA string
+	var previous []*github.CheckRun
+	switch eventType {
+	case "check_suite":
+		headSHA = event.CheckSuite.GetHeadSHA()
+		previous, err = PreviousCheckRuns(ctx, client, owner, repo, headSHA, event.CheckSuite.GetApp().GetID())
+		if err != nil {
+			return err
+		}
+	case "check_run":
+		headSHA = event.CheckRun.GetHeadSHA()
+		previous = []*github.CheckRun{event.CheckRun}
+	}
+
+	if len(previous) == 0 {
+		logger.Debug().Msgf("No previous check runs to recreate for %s", headSHA)
+		return nil
+	}
+
+	runs, err := RecreateCheckRuns(ctx, client, owner, repo, headSHA, previous)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug().Msgf("Recreated %d check runs for %s", len(runs), headSHA)
+	return h.fn(ctx, client, event.Repo, runs)
+}
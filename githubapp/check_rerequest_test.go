@@ -0,0 +1,100 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestCheckRerequestHandler(t *testing.T) {
+	tests := map[string]struct {
+		EventType string
+		Payload   string
+		Created   []string
+	}{
+		"checkSuite": {
+			EventType: "check_suite",
+			Payload:   `{"action": "rerequested", "check_suite": {"head_sha": "abc", "app": {"id": 7}}}`,
+			Created:   []string{"build:ext-build", "lint:ext-lint"},
+		},
+		"checkRun": {
+			EventType: "check_run",
+			Payload:   `{"action": "rerequested", "check_run": {"name": "lint", "head_sha": "abc", "external_id": "ext-lint"}}`,
+			Created:   []string{"lint:ext-lint"},
+		},
+		"otherAction": {
+			EventType: "check_suite",
+			Payload:   `{"action": "completed", "check_suite": {"head_sha": "abc", "app": {"id": 7}}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created []string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/octo/repo/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("app_id") != "7" || r.URL.Query().Get("filter") != "latest" {
+					t.Errorf("incorrect query: %s", r.URL.RawQuery)
+				}
+				_, _ = io.WriteString(w, `{"total_count": 3, "check_runs": [
+					{"name": "build", "external_id": "ext-build", "app": {"id": 7}},
+					{"name": "lint", "external_id": "ext-lint", "app": {"id": 7}},
+					{"name": "other", "external_id": "ext-other", "app": {"id": 8}}
+				]}`)
+			})
+			mux.HandleFunc("POST /repos/octo/repo/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				var opts github.CreateCheckRunOptions
+				_ = json.NewDecoder(r.Body).Decode(&opts)
+				if opts.HeadSHA != "abc" || opts.GetStatus() != "queued" {
+					t.Errorf("incorrect check run options: %+v", opts)
+				}
+				created = append(created, opts.Name+":"+opts.GetExternalID())
+				_, _ = fmt.Fprintf(w, `{"id": %d, "name": %q, "external_id": %q}`, len(created), opts.Name, opts.GetExternalID())
+			})
+			cc := newStaticClientCreator(t, mux)
+
+			var recreated []*github.CheckRun
+			h := NewCheckRerequestHandler(cc, func(ctx context.Context, client *github.Client, repo *github.Repository, runs []*github.CheckRun) error {
+				recreated = runs
+				return nil
+			})
+
+			var payload map[string]interface{}
+			_ = json.Unmarshal([]byte(test.Payload), &payload)
+			payload["repository"] = map[string]interface{}{"name": "repo", "owner": map[string]interface{}{"login": "octo"}}
+			payload["installation"] = map[string]interface{}{"id": 1}
+			b, _ := json.Marshal(payload)
+
+			if err := h.Handle(context.Background(), test.EventType, "id", b); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if fmt.Sprint(created) != fmt.Sprint(test.Created) {
+				t.Errorf("incorrect created runs: expected %v, actual %v", test.Created, created)
+			}
+			if len(recreated) != len(test.Created) {
+				t.Errorf("expected %d runs passed to function, but got %d", len(test.Created), len(recreated))
+			}
+		})
+	}
+}
@@ -0,0 +1,346 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// Statuses of check runs.
+const (
+	CheckStatusQueued     = "queued"
+	CheckStatusInProgress = "in_progress"
+	CheckStatusCompleted  = "completed"
+)
+
+// Conclusions of completed check runs.
+const (
+	CheckConclusionActionRequired = "action_required"
+	CheckConclusionCancelled      = "cancelled"
+	CheckConclusionFailure        = "failure"
+	CheckConclusionNeutral        = "neutral"
+	CheckConclusionSkipped        = "skipped"
+	CheckConclusionStale          = "stale"
+	CheckConclusionSuccess        = "success"
+	CheckConclusionTimedOut       = "timed_out"
+)
+
+// States of commit statuses.
+const (
+	CommitStateError   = "error"
+	CommitStateFailure = "failure"
+	CommitStatePending = "pending"
+	CommitStateSuccess = "success"
+)
+
+// conclusionSeverity orders conclusions from least to most severe, following
+// how GitHub summarizes the runs of a check suite.
+var conclusionSeverity = map[string]int{
+	CheckConclusionSkipped:        1,
+	CheckConclusionSuccess:        2,
+	CheckConclusionNeutral:        3,
+	CheckConclusionStale:          4,
+	CheckConclusionCancelled:      5,
+	CheckConclusionTimedOut:       6,
+	CheckConclusionFailure:        7,
+	CheckConclusionActionRequired: 8,
+}
+
+// WorstCheckConclusion returns the most severe of the conclusions, like the
+// conclusion GitHub shows for a check suite. Use it to combine the results of
+// several steps into the conclusion of one run. Empty conclusions are ignored
+// and unknown conclusions are treated as failures. It returns an empty string
+// if there are no conclusions.
+func WorstCheckConclusion(conclusions ...string) string {
+	var worst string
+	for _, c := range conclusions {
+		if c == "" {
+			continue
+		}
+		if _, ok := conclusionSeverity[c]; !ok {
+			c = CheckConclusionFailure
+		}
+		if conclusionSeverity[c] > conclusionSeverity[worst] {
+			worst = c
+		}
+	}
+	return worst
+}
+
+// CheckConclusionForError returns the conclusion of a check run for the error
+// returned by the work it reports. A nil error is a success, a canceled
+// context is cancelled, an expired context deadline is timed_out, and any
+// other error is a failure.
+func CheckConclusionForError(err error) string {
+	switch {
+	case err == nil:
+		return CheckConclusionSuccess
+	case errors.Is(err, context.Canceled):
+		return CheckConclusionCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CheckConclusionTimedOut
+	default:
+		return CheckConclusionFailure
+	}
+}
+
+// CommitStateForCheckRun returns the commit status state that matches a check
+// run with status and conclusion, for apps that also report commit statuses,
+// like to satisfy branch protection rules that require a status. Runs that
+// are not completed are pending; neutral and skipped runs are successful, as
+// they are for required checks.
+func CommitStateForCheckRun(status, conclusion string) string {
+	if status != CheckStatusCompleted {
+		return CommitStatePending
+	}
+	switch conclusion {
+	case CheckConclusionSuccess, CheckConclusionNeutral, CheckConclusionSkipped:
+		return CommitStateSuccess
+	case CheckConclusionFailure, CheckConclusionActionRequired:
+		return CommitStateFailure
+	default:
+		return CommitStateError
+	}
+}
+
+// CheckRunForCommitState returns the status and conclusion of a check run that
+// matches a commit status state, for apps that mirror commit statuses from an
+// external system as check runs. The conclusion is empty if the status is not
+// completed.
+func CheckRunForCommitState(state string) (status, conclusion string) {
+	switch state {
+	case CommitStateSuccess:
+		return CheckStatusCompleted, CheckConclusionSuccess
+	case CommitStateFailure:
+		return CheckStatusCompleted, CheckConclusionFailure
+	case CommitStateError:
+		return CheckStatusCompleted, CheckConclusionCancelled
+	default:
+		return CheckStatusQueued, ""
+	}
+}
+
+// CheckResult is the outcome of the work reported by a check run.
+type CheckResult struct {
+	// Conclusion is the conclusion of the run. If empty, the conclusion is
+	// CheckConclusionSuccess.
+	Conclusion string
+
+	// Output is the title, summary, and details of the run. It is optional.
+	Output *github.CheckRunOutput
+
+	// Actions are buttons shown on the run that send "check_run" events with
+	// the "requested_action" action. It is optional.
+	Actions []*github.CheckRunAction
+}
+
+// StartCheckRun marks a queued check run as in progress.
+func StartCheckRun(ctx context.Context, client *github.Client, owner, repo string, run *github.CheckRun) (*github.CheckRun, error) {
+	updated, _, err := client.Checks.UpdateCheckRun(ctx, owner, repo, run.GetID(), github.UpdateCheckRunOptions{
+		Name:   run.GetName(),
+		Status: github.String(CheckStatusInProgress),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to start check run %q in %s/%s", run.GetName(), owner, repo)
+	}
+	return updated, nil
+}
+
+// CompleteCheckRun completes a check run with the conclusion, output, and
+// actions of result. The completion time comes from the clock in the
+// context.
+func CompleteCheckRun(ctx context.Context, client *github.Client, owner, repo string, run *github.CheckRun, result CheckResult) (*github.CheckRun, error) {
+	conclusion := result.Conclusion
+	if conclusion == "" {
+		conclusion = CheckConclusionSuccess
+	}
+
+	updated, _, err := client.Checks.UpdateCheckRun(ctx, owner, repo, run.GetID(), github.UpdateCheckRunOptions{
+		Name:        run.GetName(),
+		Status:      github.String(CheckStatusCompleted),
+		Conclusion:  github.String(conclusion),
+		CompletedAt: &github.Timestamp{Time: GetClock(ctx).Now()},
+		Output:      result.Output,
+		Actions:     result.Actions,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to complete check run %q in %s/%s", run.GetName(), owner, repo)
+	}
+	return updated, nil
+}
+
+// CheckRunFunc performs the work reported by a check run. The run is in
+// progress when the function is called. The function identifies the work
+// from the name and head SHA of the run. Runs created by NewCheckRunHandler
+// use the ID of the delivery that requested them as their external ID, so
+// the external ID identifies the request, not the work.
+//
+// If the function returns an error, the run completes with the conclusion
+// from CheckConclusionForError and a generic summary, so return a result
+// with a failure conclusion instead for failures that users should act on.
+type CheckRunFunc func(ctx context.Context, client *github.Client, repo *github.Repository, run *github.CheckRun) (CheckResult, error)
+
+// CheckRunHandlerOption configures a handler created by NewCheckRunHandler.
+type CheckRunHandlerOption func(*checkRunHandler)
+
+// WithCheckRunErrorSummary uses the message of errors returned by the
+// CheckRunFunc as the summary of the failed run. By default, the summary
+// does not include the error, which is only logged, because anyone who can
+// read the repository can read the summary and error messages can contain
+// internal details, like hosts and URLs.
+func WithCheckRunErrorSummary() CheckRunHandlerOption {
+	return func(h *checkRunHandler) {
+		h.errorSummary = true
+	}
+}
+
+type checkRunHandler struct {
+	cc           ClientCreator
+	names        []string
+	fn           CheckRunFunc
+	errorSummary bool
+	rerequest    *checkRerequestHandler
+}
+
+// NewCheckRunHandler returns an EventHandler that runs checks for commits.
+// When GitHub requests a check suite for a new commit, it creates a check
+// run for each of names, using CreateCheckRunOnce so redeliveries do not
+// create duplicate runs. When a user asks to run a check suite or check run
+// again, it recreates the previous runs like NewCheckRerequestHandler. For
+// each new run, it marks the run in progress, calls fn, and completes the run
+// with the result.
+//
+// Runs are processed one at a time. The handler returns the first error from
+// fn or from GitHub after processing all runs, so use an asynchronous
+// scheduler for long checks.
+func NewCheckRunHandler(cc ClientCreator, names []string, fn CheckRunFunc, opts ...CheckRunHandlerOption) EventHandler {
+	h := &checkRunHandler{
+		cc:    cc,
+		names: names,
+		fn:    fn,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.rerequest = &checkRerequestHandler{cc: cc, fn: h.runAll}
+	return h
+}
+
+func (h *checkRunHandler) Handles() []string {
+	return Handles(EventCheckSuite, EventCheckRun)
+}
+
+func (h *checkRunHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event struct {
+		Action       string               `json:"action"`
+		CheckSuite   *github.CheckSuite   `json:"check_suite"`
+		Repo         *github.Repository   `json:"repository"`
+		Installation *github.Installation `json:"installation"`
+	}
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrapf(err, "failed to parse %s event payload", eventType)
+	}
+
+	switch {
+	case event.Action == ActionRerequested:
+		return h.rerequest.Handle(ctx, eventType, deliveryID, payload)
+	case eventType != EventCheckSuite || event.Action != ActionRequested:
+		return nil
+	}
+
+	installationID := event.Installation.GetID()
+	ctx, _ = PrepareRepoContext(ctx, installationID, event.Repo)
+
+	client, err := h.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return err
+	}
+
+	owner, repo := event.Repo.GetOwner().GetLogin(), event.Repo.GetName()
+	headSHA := event.CheckSuite.GetHeadSHA()
+
+	runs := make([]*github.CheckRun, 0, len(h.names))
+	for _, name := range h.names {
+		run, _, err := CreateCheckRunOnce(ctx, client, owner, repo, github.CreateCheckRunOptions{
+			Name:    name,
+			HeadSHA: headSHA,
+			Status:  github.String(CheckStatusQueued),
+		})
+		if err != nil {
+			return err
+		}
+		if run.GetStatus() == CheckStatusCompleted {
+			// a previous attempt at this delivery already finished the run
+			continue
+		}
+		runs = append(runs, run)
+	}
+
+	return h.runAll(ctx, client, event.Repo, runs)
+}
+
+func (h *checkRunHandler) runAll(ctx context.Context, client *github.Client, repo *github.Repository, runs []*github.CheckRun) error {
+	var firstErr error
+	for _, run := range runs {
+		if err := h.run(ctx, client, repo, run); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (h *checkRunHandler) run(ctx context.Context, client *github.Client, repo *github.Repository, run *github.CheckRun) error {
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	logger := zerolog.Ctx(ctx).With().Str("github_check_run_name", run.GetName()).Int64("github_check_run_id", run.GetID()).Logger()
+
+	started, err := StartCheckRun(ctx, client, owner, name, run)
+	if err != nil {
+		return err
+	}
+
+	result, fnErr := h.fn(ctx, client, repo, started)
+	if fnErr != nil {
+		logger.Error().Err(fnErr).Msg("Check run failed")
+
+		summary := "The check could not run because of an internal error."
+		if h.errorSummary {
+			summary = fnErr.Error()
+		}
+		result = CheckResult{
+			Conclusion: CheckConclusionForError(fnErr),
+			Output: &github.CheckRunOutput{
+				Title:   github.String(fmt.Sprintf("%s failed", run.GetName())),
+				Summary: github.String(summary),
+			},
+		}
+	}
+
+	// complete the run even if the context was canceled so it does not stay
+	// in progress forever
+	if _, err := CompleteCheckRun(context.WithoutCancel(ctx), client, owner, name, started, result); err != nil {
+		return err
+	}
+	logger.Debug().Msg("Completed check run")
+
+	if fnErr != nil {
+		return errors.Wrapf(fnErr, "check run %q failed", run.GetName())
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestWorstCheckConclusion(t *testing.T) {
+	tests := map[string]struct {
+		Conclusions []string
+		Worst       string
+	}{
+		"empty": {},
+		"success": {
+			Conclusions: []string{"skipped", "success", ""},
+			Worst:       "success",
+		},
+		"failure": {
+			Conclusions: []string{"success", "failure", "neutral", "cancelled"},
+			Worst:       "failure",
+		},
+		"actionRequired": {
+			Conclusions: []string{"action_required", "failure"},
+			Worst:       "action_required",
+		},
+		"unknown": {
+			Conclusions: []string{"success", "exploded"},
+			Worst:       "failure",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if worst := WorstCheckConclusion(test.Conclusions...); worst != test.Worst {
+				t.Errorf("incorrect conclusion: expected %q, actual %q", test.Worst, worst)
+			}
+		})
+	}
+}
+
+func TestCheckConclusionForError(t *testing.T) {
+	tests := map[string]struct {
+		Err        error
+		Conclusion string
+	}{
+		"nil":      {Err: nil, Conclusion: "success"},
+		"canceled": {Err: errors.Wrap(context.Canceled, "build"), Conclusion: "cancelled"},
+		"deadline": {Err: errors.Wrap(context.DeadlineExceeded, "build"), Conclusion: "timed_out"},
+		"other":    {Err: errors.New("build failed"), Conclusion: "failure"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if c := CheckConclusionForError(test.Err); c != test.Conclusion {
+				t.Errorf("incorrect conclusion: expected %q, actual %q", test.Conclusion, c)
+			}
+		})
+	}
+}
+
+func TestCommitStateMapping(t *testing.T) {
+	tests := map[string]struct {
+		Status     string
+		Conclusion string
+		State      string
+	}{
+		"queued":    {Status: "queued", State: "pending"},
+		"running":   {Status: "in_progress", State: "pending"},
+		"success":   {Status: "completed", Conclusion: "success", State: "success"},
+		"skipped":   {Status: "completed", Conclusion: "skipped", State: "success"},
+		"failure":   {Status: "completed", Conclusion: "failure", State: "failure"},
+		"cancelled": {Status: "completed", Conclusion: "cancelled", State: "error"},
+		"timedOut":  {Status: "completed", Conclusion: "timed_out", State: "error"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if state := CommitStateForCheckRun(test.Status, test.Conclusion); state != test.State {
+				t.Errorf("incorrect state: expected %q, actual %q", test.State, state)
+			}
+		})
+	}
+
+	for _, state := range []string{"pending", "success", "failure", "error"} {
+		status, conclusion := CheckRunForCommitState(state)
+		if roundTrip := CommitStateForCheckRun(status, conclusion); roundTrip != state {
+			t.Errorf("state %q did not round trip: %s/%s is %q", state, status, conclusion, roundTrip)
+		}
+	}
+}
+
+func TestCheckRunHandler(t *testing.T) {
+	tests := map[string]struct {
+		EventType string
+		Payload   string
+		Options   []CheckRunHandlerOption
+		Existing  string
+		Updates   []string
+		Summary   string
+		Err       bool
+	}{
+		"requested": {
+			EventType: "check_suite",
+			Payload:   `{"action": "requested", "check_suite": {"head_sha": "abc"}}`,
+			Updates: []string{
+				"1:build:in_progress:", "1:build:completed:success",
+				"2:lint:in_progress:", "2:lint:completed:failure",
+			},
+			Summary: "The check could not run because of an internal error.",
+			Err:     true,
+		},
+		"requestedWithErrorSummary": {
+			EventType: "check_suite",
+			Payload:   `{"action": "requested", "check_suite": {"head_sha": "abc"}}`,
+			Options:   []CheckRunHandlerOption{WithCheckRunErrorSummary()},
+			Updates: []string{
+				"1:build:in_progress:", "1:build:completed:success",
+				"2:lint:in_progress:", "2:lint:completed:failure",
+			},
+			Summary: "linter crashed",
+			Err:     true,
+		},
+		"redelivered": {
+			EventType: "check_suite",
+			Payload:   `{"action": "requested", "check_suite": {"head_sha": "abc"}}`,
+			Existing:  `{"id": 9, "name": "build", "external_id": "delivery", "status": "completed"}`,
+			Updates: []string{
+				"1:lint:in_progress:", "1:lint:completed:failure",
+			},
+			Err: true,
+		},
+		"rerequested": {
+			EventType: "check_run",
+			Payload:   `{"action": "rerequested", "check_run": {"name": "build", "head_sha": "abc", "external_id": "ext-build"}}`,
+			Updates: []string{
+				"1:build:in_progress:", "1:build:completed:success",
+			},
+		},
+		"completed": {
+			EventType: "check_suite",
+			Payload:   `{"action": "completed", "check_suite": {"head_sha": "abc"}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var created int
+			var updates []string
+			var summary string
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/octo/repo/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("check_name") == "build" && test.Existing != "" {
+					_, _ = fmt.Fprintf(w, `{"total_count": 1, "check_runs": [%s]}`, test.Existing)
+					return
+				}
+				_, _ = io.WriteString(w, `{"total_count": 0, "check_runs": []}`)
+			})
+			mux.HandleFunc("POST /repos/octo/repo/check-runs", func(w http.ResponseWriter, r *http.Request) {
+				var opts github.CreateCheckRunOptions
+				_ = json.NewDecoder(r.Body).Decode(&opts)
+
+				mu.Lock()
+				created++
+				id := created
+				mu.Unlock()
+
+				_, _ = fmt.Fprintf(w, `{"id": %d, "name": %q, "status": "queued"}`, id, opts.Name)
+			})
+			mux.HandleFunc("PATCH /repos/octo/repo/check-runs/{id}", func(w http.ResponseWriter, r *http.Request) {
+				var opts github.UpdateCheckRunOptions
+				_ = json.NewDecoder(r.Body).Decode(&opts)
+				if opts.GetStatus() == "completed" && opts.CompletedAt == nil {
+					t.Errorf("completed check run without completion time: %+v", opts)
+				}
+
+				mu.Lock()
+				updates = append(updates, fmt.Sprintf("%s:%s:%s:%s", r.PathValue("id"), opts.Name, opts.GetStatus(), opts.GetConclusion()))
+				if opts.GetConclusion() == "failure" {
+					summary = opts.Output.GetSummary()
+				}
+				mu.Unlock()
+
+				_, _ = fmt.Fprintf(w, `{"id": %s, "name": %q, "status": %q}`, r.PathValue("id"), opts.Name, opts.GetStatus())
+			})
+			cc := newStaticClientCreator(t, mux)
+
+			h := NewCheckRunHandler(cc, []string{"build", "lint"}, func(ctx context.Context, client *github.Client, repo *github.Repository, run *github.CheckRun) (CheckResult, error) {
+				if run.GetStatus() != "in_progress" {
+					t.Errorf("expected run in progress, but got %q", run.GetStatus())
+				}
+				if run.GetName() == "lint" {
+					return CheckResult{}, errors.New("linter crashed")
+				}
+				return CheckResult{Output: &github.CheckRunOutput{Title: github.String("Build passed"), Summary: github.String("ok")}}, nil
+			}, test.Options...)
+
+			payload := fmt.Sprintf(`{"repository": {"name": "repo", "owner": {"login": "octo"}}, "installation": {"id": 1}, %s`, test.Payload[1:])
+			ctx := WithDelivery(context.Background(), Delivery{EventType: test.EventType, DeliveryID: "delivery"})
+
+			err := h.Handle(ctx, test.EventType, "delivery", []byte(payload))
+			if test.Err != (err != nil) {
+				t.Fatalf("incorrect error: %v", err)
+			}
+			if fmt.Sprint(updates) != fmt.Sprint(test.Updates) {
+				t.Errorf("incorrect updates:\nexpected %v\n  actual %v", test.Updates, updates)
+			}
+			if test.Summary != "" && summary != test.Summary {
+				t.Errorf("incorrect failure summary: expected %q, actual %q", test.Summary, summary)
+			}
+		})
+	}
+}
@@ -16,17 +16,22 @@ package githubapp
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/go-github/v66/github"
 	"github.com/gregjones/httpcache"
 	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
 	"github.com/shurcooL/githubv4"
 	"golang.org/x/oauth2"
 )
@@ -93,6 +98,12 @@ type ClientCreator interface {
 	NewTokenV4Client(token string) (*githubv4.Client, error)
 }
 
+const (
+	// DefaultMaxIdleConnsPerHost is the number of idle connections to each
+	// host kept by the default transport of created clients.
+	DefaultMaxIdleConnsPerHost = 32
+)
+
 var (
 	maxAgeRegex = regexp.MustCompile(`max-age=\d+`)
 )
@@ -109,6 +120,8 @@ func NewClientCreator(v3BaseURL, v4BaseURL string, integrationID int64, privKeyB
 		v4BaseURL:     v4BaseURL,
 		integrationID: integrationID,
 		privKeyBytes:  privKeyBytes,
+		jwtReuse:      DefaultAppJWTReuse,
+		clock:         SystemClock,
 	}
 
 	for _, opt := range opts {
@@ -119,23 +132,53 @@ func NewClientCreator(v3BaseURL, v4BaseURL string, integrationID int64, privKeyB
 		cc.v3BaseURL += "/"
 	}
 
+	if cc.uploadURL == "" {
+		cc.uploadURL = defaultUploadURL(cc.v3BaseURL)
+	}
+	if cc.uploadURL != "" && !strings.HasSuffix(cc.uploadURL, "/") {
+		cc.uploadURL += "/"
+	}
+
 	// graphql URL should not end in trailing slash
 	cc.v4BaseURL = strings.TrimSuffix(cc.v4BaseURL, "/")
 
+	// share a single transport between all clients to reuse connections
+	if cc.transport == nil {
+		cc.transport = newDefaultTransport(cc.maxIdleConnsPerHost, !cc.disableHTTP2)
+	}
+
+	if cc.identityLogger != nil {
+		go cc.logIdentity(*cc.identityLogger)
+	}
+
 	return cc
 }
 
 type clientCreator struct {
 	v3BaseURL      string
 	v4BaseURL      string
+	uploadURL      string
 	integrationID  int64
 	privKeyBytes   []byte
 	userAgent      string
 	middleware     []ClientMiddleware
+	installMW      func(installationID int64) []ClientMiddleware
 	cacheFunc      func() httpcache.Cache
 	alwaysValidate bool
 	timeout        time.Duration
 	transport      http.RoundTripper
+	tokenMetrics   metrics.Registry
+	jwtReuse       time.Duration
+	onCreate       ClientCreationCallback
+	clock          Clock
+	identityLogger *zerolog.Logger
+
+	signerOnce sync.Once
+	signer     ghinstallation.Signer
+	signerErr  error
+
+	maxIdleConnsPerHost int
+	disableHTTP2        bool
 }
 
 var _ ClientCreator = &clientCreator{}
@@ -179,43 +222,153 @@ func WithClientMiddleware(middleware ...ClientMiddleware) ClientOption {
 	}
 }
 
+// WithInstallationMiddleware adds middleware to installation clients based on
+// the installation ID, like stricter throttling for specific accounts or
+// extra logging for an installation under investigation. The function is
+// called each time an installation client is created and may return nil. The
+// middleware is applied after the middleware from WithClientMiddleware.
+//
+// Caching client creators reuse clients, so the function is only called the
+// first time a client is created for an installation, until the client is
+// evicted or invalidated.
+func WithInstallationMiddleware(fn func(installationID int64) []ClientMiddleware) ClientOption {
+	return func(c *clientCreator) {
+		c.installMW = fn
+	}
+}
+
+// WithTokenMetrics enables metrics about application JWTs and installation
+// tokens created by clients, like the number of tokens minted and the latency
+// of minting them. Use these metrics to detect token minting storms or
+// authentication problems separately from other requests.
+func WithTokenMetrics(registry metrics.Registry) ClientOption {
+	return func(c *clientCreator) {
+		c.tokenMetrics = registry
+	}
+}
+
+// WithAppJWTReuse sets how long clients reuse an application JWT before
+// signing a new one. All clients from the creator share the JWT, which avoids
+// an RSA signature for each request authenticated as the application,
+// including requests for installation tokens. The default is
+// DefaultAppJWTReuse. The duration is capped so that a reused JWT is always
+// at least one minute from expiring. A duration of zero or less disables
+// reuse.
+func WithAppJWTReuse(reuse time.Duration) ClientOption {
+	return func(c *clientCreator) {
+		c.jwtReuse = reuse
+	}
+}
+
+// WithClientClock sets the clock used to decide when clients sign a new
+// application JWT with WithAppJWTReuse. Signing a JWT does not receive the
+// context of the request, so this is the only clock that is not read from
+// the context with GetClock. The default is SystemClock.
+func WithClientClock(clock Clock) ClientOption {
+	return func(c *clientCreator) {
+		if clock != nil {
+			c.clock = clock
+		}
+	}
+}
+
+// ClientKind identifies the type of authentication used by a client.
+type ClientKind string
+
+const (
+	ClientKindApp          ClientKind = "app"
+	ClientKindInstallation ClientKind = "installation"
+	ClientKindToken        ClientKind = "token"
+)
+
+// ClientCreationCallback is called after a ClientCreator creates a v3 (REST)
+// client. The installation ID is 0 for application and token clients.
+type ClientCreationCallback func(kind ClientKind, installationID int64, client *github.Client)
+
+// WithClientCreationCallback sets a function that is called each time the
+// creator creates a new v3 (REST) client. Applications can use it to attach
+// bookkeeping, warm caches, or register per-client metrics. The callback is
+// not called for v4 (GraphQL) clients or for clients returned from the cache
+// of a caching client creator.
+func WithClientCreationCallback(fn ClientCreationCallback) ClientOption {
+	return func(c *clientCreator) {
+		c.onCreate = fn
+	}
+}
+
 // WithTransport sets the http.RoundTripper used to make requests. Clients can
 // provide an http.Transport instance to modify TLS, proxy, or timeout options.
-// By default, clients use http.DefaultTransport.
+// By default, clients share a transport based on http.DefaultTransport that
+// keeps more idle connections to each host and always attempts HTTP/2.
+//
+// The transport is beneath all middleware in created clients. Requests pass
+// through client middleware, caching, and authentication before reaching it,
+// so it sees every request sent to GitHub, including requests that create
+// installation tokens, with final headers.
 func WithTransport(transport http.RoundTripper) ClientOption {
 	return func(c *clientCreator) {
 		c.transport = transport
 	}
 }
 
+// WithClientUploadURL sets the base URL for uploads, like release assets, in
+// created clients. By default, the upload URL is derived from the v3 base URL
+// for both github.com and GitHub Enterprise Server.
+func WithClientUploadURL(uploadURL string) ClientOption {
+	return func(c *clientCreator) {
+		c.uploadURL = uploadURL
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections the
+// default transport keeps for each host. The default is
+// DefaultMaxIdleConnsPerHost. This has no effect if WithTransport is set.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *clientCreator) {
+		if n > 0 {
+			c.maxIdleConnsPerHost = n
+		}
+	}
+}
+
+// WithHTTP2 sets whether the default transport uses HTTP/2 when the server
+// supports it. HTTP/2 is enabled by default. This has no effect if
+// WithTransport is set.
+func WithHTTP2(enabled bool) ClientOption {
+	return func(c *clientCreator) {
+		c.disableHTTP2 = !enabled
+	}
+}
+
 func (c *clientCreator) NewAppClient() (*github.Client, error) {
 	base := c.newHTTPClient()
-	installation, transportError := newAppInstallation(c.integrationID, c.privKeyBytes, c.v3BaseURL)
+	installation, transportError := c.newAppInstallation()
 
 	middleware := []ClientMiddleware{installation}
 	if c.cacheFunc != nil {
-		middleware = append(middleware, cache(c.cacheFunc), cacheControl(c.alwaysValidate))
+		middleware = append(middleware, readYourWrites(), cache(c.cacheFunc), cacheControl(c.alwaysValidate))
 	}
 
-	client, err := c.newClient(base, middleware, "application", 0)
+	client, err := c.newClient(base, middleware, "application", 0, nil)
 	if err != nil {
 		return nil, err
 	}
 	if *transportError != nil {
 		return nil, *transportError
 	}
+	c.created(ClientKindApp, 0, client)
 	return client, nil
 }
 
 func (c *clientCreator) NewAppV4Client() (*githubv4.Client, error) {
 	base := c.newHTTPClient()
-	installation, transportError := newAppInstallation(c.integrationID, c.privKeyBytes, c.v3BaseURL)
+	installation, transportError := c.newAppInstallation()
 
 	// The v4 API primarily uses POST requests (except for introspection queries)
 	// which we cannot cache, so don't add the cache middleware
 	middleware := []ClientMiddleware{installation}
 
-	client, err := c.newV4Client(base, middleware, "application")
+	client, err := c.newV4Client(base, middleware, "application", 0)
 	if err != nil {
 		return nil, err
 	}
@@ -227,37 +380,38 @@ func (c *clientCreator) NewAppV4Client() (*githubv4.Client, error) {
 
 func (c *clientCreator) NewInstallationClient(installationID int64) (*github.Client, error) {
 	base := c.newHTTPClient()
-	installation, transportError := newInstallation(c.integrationID, installationID, c.privKeyBytes, c.v3BaseURL)
+	installation, result := c.newInstallation(installationID)
 
 	middleware := []ClientMiddleware{installation}
 	if c.cacheFunc != nil {
-		middleware = append(middleware, cache(c.cacheFunc), cacheControl(c.alwaysValidate))
+		middleware = append(middleware, readYourWrites(), cache(c.cacheFunc), cacheControl(c.alwaysValidate))
 	}
 
-	client, err := c.newClient(base, middleware, fmt.Sprintf("installation: %d", installationID), installationID)
+	client, err := c.newClient(base, middleware, fmt.Sprintf("installation: %d", installationID), installationID, result)
 	if err != nil {
 		return nil, err
 	}
-	if *transportError != nil {
-		return nil, *transportError
+	if result.err != nil {
+		return nil, result.err
 	}
+	c.created(ClientKindInstallation, installationID, client)
 	return client, nil
 }
 
 func (c *clientCreator) NewInstallationV4Client(installationID int64) (*githubv4.Client, error) {
 	base := c.newHTTPClient()
-	installation, transportError := newInstallation(c.integrationID, installationID, c.privKeyBytes, c.v3BaseURL)
+	installation, result := c.newInstallation(installationID)
 
 	// The v4 API primarily uses POST requests (except for introspection queries)
 	// which we cannot cache, so don't construct the middleware
 	middleware := []ClientMiddleware{installation}
 
-	client, err := c.newV4Client(base, middleware, fmt.Sprintf("installation: %d", installationID))
+	client, err := c.newV4Client(base, middleware, fmt.Sprintf("installation: %d", installationID), installationID)
 	if err != nil {
 		return nil, err
 	}
-	if *transportError != nil {
-		return nil, *transportError
+	if result.err != nil {
+		return nil, result.err
 	}
 	return client, nil
 }
@@ -272,10 +426,15 @@ func (c *clientCreator) NewTokenSourceClient(ts oauth2.TokenSource) (*github.Cli
 
 	middleware := []ClientMiddleware{}
 	if c.cacheFunc != nil {
-		middleware = append(middleware, cache(c.cacheFunc), cacheControl(c.alwaysValidate))
+		middleware = append(middleware, readYourWrites(), cache(c.cacheFunc), cacheControl(c.alwaysValidate))
 	}
 
-	return c.newClient(tc, middleware, "oauth token", 0)
+	client, err := c.newClient(tc, middleware, "oauth token", 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.created(ClientKindToken, 0, client)
+	return client, nil
 }
 
 func (c *clientCreator) NewTokenV4Client(token string) (*githubv4.Client, error) {
@@ -287,7 +446,13 @@ func (c *clientCreator) NewTokenSourceV4Client(ts oauth2.TokenSource) (*githubv4
 	tc := oauth2.NewClient(context.Background(), ts)
 	// The v4 API primarily uses POST requests (except for introspection queries)
 	// which we cannot cache, so don't construct the middleware
-	return c.newV4Client(tc, nil, "oauth token")
+	return c.newV4Client(tc, nil, "oauth token", 0)
+}
+
+func (c *clientCreator) created(kind ClientKind, installationID int64, client *github.Client) {
+	if c.onCreate != nil {
+		c.onCreate(kind, installationID, client)
+	}
 }
 
 func (c *clientCreator) newHTTPClient() *http.Client {
@@ -304,12 +469,39 @@ func (c *clientCreator) newHTTPClient() *http.Client {
 	}
 }
 
-func (c *clientCreator) newClient(base *http.Client, middleware []ClientMiddleware, details string, installID int64) (*github.Client, error) {
+// newDefaultTransport returns a copy of http.DefaultTransport tuned for
+// applications that make many concurrent requests to a single GitHub host.
+// The standard transport only keeps two idle connections per host, which
+// causes connection churn under webhook load.
+func newDefaultTransport(maxIdleConnsPerHost int, http2 bool) *http.Transport {
+	if maxIdleConnsPerHost <= 0 {
+		maxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	}
+
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	if t.MaxIdleConns < maxIdleConnsPerHost {
+		t.MaxIdleConns = maxIdleConnsPerHost
+	}
+	t.ForceAttemptHTTP2 = http2
+	if !http2 {
+		// a non-nil, empty map disables automatic HTTP/2 upgrades
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+	}
+	return t
+}
+
+func (c *clientCreator) newClient(base *http.Client, middleware []ClientMiddleware, details string, installID int64, installation *installationResult) (*github.Client, error) {
 	applyMiddleware(base, [][]ClientMiddleware{
-		{setInstallationID(installID)},
+		{setInstallationID(installID), recordRateLimits()},
 		c.middleware,
+		c.installationMiddleware(installID),
 		middleware,
 	})
+	if installation != nil {
+		// expose the installation token to TokenExpiry and RefreshToken
+		base.Transport = &installationTokenTransport{next: base.Transport, installation: installation}
+	}
 
 	baseURL, err := url.Parse(c.v3BaseURL)
 	if err != nil {
@@ -320,13 +512,22 @@ func (c *clientCreator) newClient(base *http.Client, middleware []ClientMiddlewa
 	client.BaseURL = baseURL
 	client.UserAgent = makeUserAgent(c.userAgent, details)
 
+	if c.uploadURL != "" {
+		uploadURL, err := url.Parse(c.uploadURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse upload URL: %q", c.uploadURL)
+		}
+		client.UploadURL = uploadURL
+	}
+
 	return client, nil
 }
 
-func (c *clientCreator) newV4Client(base *http.Client, middleware []ClientMiddleware, details string) (*githubv4.Client, error) {
+func (c *clientCreator) newV4Client(base *http.Client, middleware []ClientMiddleware, details string, installID int64) (*githubv4.Client, error) {
 	applyMiddleware(base, [][]ClientMiddleware{
-		{setUserAgentHeader(makeUserAgent(c.userAgent, details))},
+		{setInstallationID(installID), recordRateLimits(), setUserAgentHeader(makeUserAgent(c.userAgent, details))},
 		c.middleware,
+		c.installationMiddleware(installID),
 		middleware,
 	})
 
@@ -339,6 +540,36 @@ func (c *clientCreator) newV4Client(base *http.Client, middleware []ClientMiddle
 	return client, nil
 }
 
+// defaultUploadURL returns the upload URL for the server with the given v3 API
+// URL. It returns an empty string if the URL is not a standard github.com or
+// GitHub Enterprise Server API URL.
+func defaultUploadURL(v3BaseURL string) string {
+	u, err := url.Parse(v3BaseURL)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case u.Host == "api.github.com":
+		u.Host = "uploads.github.com"
+		u.Path = "/"
+	case strings.HasSuffix(u.Path, "/api/v3/"):
+		u.Path = strings.TrimSuffix(u.Path, "/api/v3/") + "/api/uploads/"
+	default:
+		return ""
+	}
+	return u.String()
+}
+
+// installationMiddleware returns the middleware set by
+// WithInstallationMiddleware for an installation, or nil if there is none.
+func (c *clientCreator) installationMiddleware(installID int64) []ClientMiddleware {
+	if c.installMW == nil || installID <= 0 {
+		return nil
+	}
+	return c.installMW(installID)
+}
+
 // applyMiddleware behaves as if it concatenates all middleware slices in the
 // order given and then composes the middleware so that the first element is
 // the outermost function and the last element is the innermost function.
@@ -350,34 +581,91 @@ func applyMiddleware(base *http.Client, middleware [][]ClientMiddleware) {
 	}
 }
 
-func newAppInstallation(integrationID int64, privKeyBytes []byte, v3BaseURL string) (ClientMiddleware, *error) {
+func (c *clientCreator) newAppInstallation() (ClientMiddleware, *error) {
 	var transportError error
 	installation := func(next http.RoundTripper) http.RoundTripper {
-		itr, err := ghinstallation.NewAppsTransport(next, integrationID, privKeyBytes)
+		atr, err := c.newAppsTransport(next)
 		if err != nil {
 			transportError = err
 			return next
 		}
-		// leaving the v3 URL since this is used to refresh the token, not make queries
-		itr.BaseURL = strings.TrimSuffix(v3BaseURL, "/")
-		return itr
+		return atr
 	}
 	return installation, &transportError
 }
 
-func newInstallation(integrationID, installationID int64, privKeyBytes []byte, v3BaseURL string) (ClientMiddleware, *error) {
-	var transportError error
+// installationResult records the transport created by installation
+// middleware, or the error that prevented creating it.
+type installationResult struct {
+	transport *refreshingTransport
+	err       error
+}
+
+func (c *clientCreator) newInstallation(installationID int64) (ClientMiddleware, *installationResult) {
+	var result installationResult
 	installation := func(next http.RoundTripper) http.RoundTripper {
-		itr, err := ghinstallation.New(next, integrationID, installationID, privKeyBytes)
+		newTransport := func() (*ghinstallation.Transport, error) {
+			atr, err := c.newAppsTransport(next)
+			if err != nil {
+				return nil, err
+			}
+			itr := ghinstallation.NewFromAppsTransport(atr, installationID)
+			// leaving the v3 URL since this is used to refresh the token, not make queries
+			itr.BaseURL = atr.BaseURL
+			return itr, nil
+		}
+
+		itr, err := newTransport()
 		if err != nil {
-			transportError = err
+			result.err = err
 			return next
 		}
-		// leaving the v3 URL since this is used to refresh the token, not make queries
-		itr.BaseURL = strings.TrimSuffix(v3BaseURL, "/")
-		return itr
+
+		rt := newRefreshingTransport(itr, newTransport)
+		rt.registry = c.tokenMetrics
+		result.transport = rt
+		return rt
 	}
-	return installation, &transportError
+	return installation, &result
+}
+
+// jwtSigner returns the signer shared by all clients from the creator.
+func (c *clientCreator) jwtSigner() (ghinstallation.Signer, error) {
+	c.signerOnce.Do(func() {
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(c.privKeyBytes)
+		if err != nil {
+			c.signerErr = errors.Wrap(err, "could not parse private key")
+			return
+		}
+
+		var signer ghinstallation.Signer = ghinstallation.NewRSASigner(jwt.SigningMethodRS256, key)
+		if c.tokenMetrics != nil {
+			signer = &metricsSigner{signer: signer, registry: c.tokenMetrics}
+		}
+		if c.jwtReuse > 0 {
+			signer = newReusingSigner(signer, c.integrationID, c.jwtReuse, c.clock)
+		}
+		c.signer = signer
+	})
+	return c.signer, c.signerErr
+}
+
+func (c *clientCreator) newAppsTransport(next http.RoundTripper) (*ghinstallation.AppsTransport, error) {
+	signer, err := c.jwtSigner()
+	if err != nil {
+		return nil, err
+	}
+	if c.tokenMetrics != nil {
+		next = tokenMetrics(c.tokenMetrics)(next)
+	}
+
+	atr, err := ghinstallation.NewAppsTransportWithOptions(next, c.integrationID, ghinstallation.WithSigner(signer))
+	if err != nil {
+		return nil, err
+	}
+	// leaving the v3 URL since this is used to refresh the token, not make queries
+	atr.BaseURL = strings.TrimSuffix(c.v3BaseURL, "/")
+	return atr, nil
 }
 
 func cache(cacheFunc func() httpcache.Cache) ClientMiddleware {
@@ -0,0 +1,313 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestDefaultTransport(t *testing.T) {
+	tests := map[string]struct {
+		Options []ClientOption
+
+		MaxIdleConnsPerHost int
+		HTTP2               bool
+	}{
+		"defaults": {
+			MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+			HTTP2:               true,
+		},
+		"overrides": {
+			Options:             []ClientOption{WithMaxIdleConnsPerHost(200), WithHTTP2(false)},
+			MaxIdleConnsPerHost: 200,
+			HTTP2:               false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cc := NewClientCreator("https://api.github.com/", "https://api.github.com/graphql", 1, nil, test.Options...).(*clientCreator)
+
+			transport, ok := cc.transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("incorrect transport type: %T", cc.transport)
+			}
+			if transport.MaxIdleConnsPerHost != test.MaxIdleConnsPerHost {
+				t.Errorf("incorrect MaxIdleConnsPerHost: expected %d, actual %d", test.MaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+			}
+			if transport.MaxIdleConns < transport.MaxIdleConnsPerHost {
+				t.Errorf("MaxIdleConns (%d) is less than MaxIdleConnsPerHost", transport.MaxIdleConns)
+			}
+			if transport.ForceAttemptHTTP2 != test.HTTP2 {
+				t.Errorf("incorrect ForceAttemptHTTP2: expected %t, actual %t", test.HTTP2, transport.ForceAttemptHTTP2)
+			}
+			if transport == http.DefaultTransport {
+				t.Error("transport must not modify http.DefaultTransport")
+			}
+		})
+	}
+
+	custom := &http.Transport{}
+	cc := NewClientCreator("https://api.github.com/", "https://api.github.com/graphql", 1, nil, WithTransport(custom), WithMaxIdleConnsPerHost(200)).(*clientCreator)
+	if cc.transport != custom || custom.MaxIdleConnsPerHost != 0 {
+		t.Error("custom transport was replaced or modified")
+	}
+}
+
+func TestClientTransport(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-1", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /repos/octo/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	base := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		requests = append(requests, fmt.Sprintf("%s %s auth=%t marked=%s", r.Method, r.URL.Path, r.Header.Get("Authorization") != "", r.Header.Get("X-Middleware")))
+		mu.Unlock()
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	middleware := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Middleware", "true")
+			return next.RoundTrip(r)
+		})
+	}
+
+	cc := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t),
+		WithTransport(base),
+		WithClientMiddleware(middleware),
+	)
+
+	client, err := cc.NewInstallationClient(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	if _, _, err := client.Repositories.Get(context.Background(), "octo", "repo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"POST /app/installations/1/access_tokens auth=true marked=",
+		"GET /repos/octo/repo auth=true marked=true",
+	}
+	if !reflect.DeepEqual(expected, requests) {
+		t.Errorf("incorrect requests to base transport\nexpected: %q\n  actual: %q", expected, requests)
+	}
+}
+
+func TestClientUploadURL(t *testing.T) {
+	tests := map[string]struct {
+		V3BaseURL string
+		Options   []ClientOption
+		UploadURL string
+	}{
+		"github": {
+			V3BaseURL: "https://api.github.com/",
+			UploadURL: "https://uploads.github.com/",
+		},
+		"enterprise": {
+			V3BaseURL: "https://github.company.domain/api/v3",
+			UploadURL: "https://github.company.domain/api/uploads/",
+		},
+		"explicit": {
+			V3BaseURL: "https://github.company.domain/api/v3/",
+			Options:   []ClientOption{WithClientUploadURL("https://uploads.company.domain")},
+			UploadURL: "https://uploads.company.domain/",
+		},
+		"unknown": {
+			V3BaseURL: "http://localhost:8080/",
+			UploadURL: "https://uploads.github.com/",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cc := NewClientCreator(test.V3BaseURL, "", 1, nil, test.Options...)
+
+			client, err := cc.NewTokenClient("token")
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+			if client.UploadURL.String() != test.UploadURL {
+				t.Errorf("incorrect upload URL: expected %q, actual %q", test.UploadURL, client.UploadURL.String())
+			}
+		})
+	}
+}
+
+func TestInstallationMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	marked := make(map[string]string)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/{id}/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-%s", "expires_at": %q}`, r.PathValue("id"), time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /repos/octo/{repo}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		marked[r.PathValue("repo")] = r.Header.Get("X-Middleware")
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	middleware := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("X-Middleware", "true")
+			return next.RoundTrip(r)
+		})
+	}
+
+	cc := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t),
+		WithInstallationMiddleware(func(installationID int64) []ClientMiddleware {
+			if installationID == 2 {
+				return []ClientMiddleware{middleware}
+			}
+			return nil
+		}),
+	)
+
+	for _, id := range []int64{1, 2} {
+		client, err := cc.NewInstallationClient(id)
+		if err != nil {
+			t.Fatalf("unexpected error creating client: %v", err)
+		}
+		if _, _, err := client.Repositories.Get(context.Background(), "octo", fmt.Sprintf("repo-%d", id)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string]string{"repo-1": "", "repo-2": "true"}
+	if !reflect.DeepEqual(expected, marked) {
+		t.Errorf("incorrect middleware headers\nexpected: %q\n  actual: %q", expected, marked)
+	}
+}
+
+func TestAppJWTReuse(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	tests := map[string]struct {
+		Options []ClientOption
+		Advance time.Duration
+		Signed  int64
+	}{
+		"default": {
+			Signed: 1,
+		},
+		"disabled": {
+			Options: []ClientOption{WithAppJWTReuse(0)},
+			Signed:  3,
+		},
+		"expired": {
+			Advance: DefaultAppJWTReuse,
+			Signed:  3,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clock := newTestClock()
+			registry := metrics.NewRegistry()
+			opts := append([]ClientOption{WithTokenMetrics(registry), WithClientClock(clock)}, test.Options...)
+			cc := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t), opts...)
+
+			for i := 0; i < 3; i++ {
+				// create a new client for each request to check that the JWT is shared
+				client, err := cc.NewAppClient()
+				if err != nil {
+					t.Fatalf("unexpected error creating client: %v", err)
+				}
+				if _, _, err := client.Apps.Get(context.Background(), ""); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				clock.Advance(test.Advance)
+			}
+
+			if signed := metrics.GetOrRegisterCounter(MetricsKeyAppJWTSigned, registry).Count(); signed != test.Signed {
+				t.Errorf("incorrect number of signed JWTs: expected %d, actual %d", test.Signed, signed)
+			}
+		})
+	}
+}
+
+func TestClientCreationCallback(t *testing.T) {
+	var created []string
+	callback := func(kind ClientKind, installationID int64, client *github.Client) {
+		if client == nil {
+			t.Errorf("callback received nil client for %s", kind)
+		}
+		created = append(created, fmt.Sprintf("%s:%d", kind, installationID))
+	}
+
+	var c Config
+	c.V3APIURL = "https://api.github.com/"
+	c.App.IntegrationID = 1
+	c.App.PrivateKey = string(newTestPrivateKey(t))
+
+	cc, err := NewDefaultCachingClientCreator(c, WithClientCreationCallback(callback))
+	if err != nil {
+		t.Fatalf("unexpected error creating client creator: %v", err)
+	}
+
+	for _, create := range []func() (*github.Client, error){
+		cc.NewAppClient,
+		func() (*github.Client, error) { return cc.NewInstallationClient(12) },
+		func() (*github.Client, error) { return cc.NewInstallationClient(12) },
+		func() (*github.Client, error) { return cc.NewTokenClient("token") },
+	} {
+		if _, err := create(); err != nil {
+			t.Fatalf("unexpected error creating client: %v", err)
+		}
+	}
+
+	expected := []string{"app:0", "installation:12", "token:0"}
+	if !reflect.DeepEqual(expected, created) {
+		t.Errorf("incorrect created clients\nexpected: %q\n  actual: %q", expected, created)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultWarmInterval is the default time between checks of the clients
+	// of warm installations.
+	DefaultWarmInterval = 5 * time.Minute
+
+	// WarmTokenMargin is how long before expiring a warm installation's token
+	// is replaced. It is longer than DefaultWarmInterval, so handlers never
+	// wait for a new token.
+	WarmTokenMargin = 15 * time.Minute
+)
+
+// ClientWarmer is implemented by caching client creators configured with
+// WithCachingWarmInstallations. Use a type assertion to access it from a
+// ClientCreator.
+type ClientWarmer interface {
+	// Warm creates the REST client and installation token for each warm
+	// installation that does not have them and replaces tokens that expire
+	// within WarmTokenMargin. It tries all installations and returns the
+	// first error.
+	Warm(ctx context.Context) error
+
+	// KeepWarm calls Warm every interval until the context is canceled.
+	// Errors are logged with the logger in the context. If interval is not
+	// positive, it uses DefaultWarmInterval.
+	KeepWarm(ctx context.Context, interval time.Duration)
+}
+
+// WithCachingWarmInstallations keeps REST clients for high-traffic
+// installations ready, so the first webhook for these installations after a
+// deploy does not wait to create a client and token. Warm clients are not
+// evicted from the cache or expired by WithCachingTTL, but are removed by
+// Invalidate and created again by the next call to Warm.
+//
+// The creator implements ClientWarmer. Call Warm at startup and run KeepWarm
+// in the background to keep tokens fresh. Only clients created by
+// NewClientCreator have tokens that can be refreshed in advance.
+func WithCachingWarmInstallations(installationIDs ...int64) CachingClientOption {
+	return func(c *cachingClientCreator) {
+		c.warmIDs = installationIDs
+	}
+}
+
+func (c *cachingClientCreator) getWarm(installationID int64) (*github.Client, bool) {
+	c.warmMu.RLock()
+	defer c.warmMu.RUnlock()
+	client, ok := c.warm[installationID]
+	return client, ok
+}
+
+func (c *cachingClientCreator) invalidateWarm(installationID int64) {
+	c.warmMu.Lock()
+	defer c.warmMu.Unlock()
+	delete(c.warm, installationID)
+}
+
+func (c *cachingClientCreator) Warm(ctx context.Context) error {
+	var firstErr error
+	for _, id := range c.warmIDs {
+		if err := c.warmInstallation(ctx, id); err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Int64(LogKeyInstallationID, id).Msg("Failed to warm installation client")
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return firstErr
+}
+
+func (c *cachingClientCreator) warmInstallation(ctx context.Context, installationID int64) error {
+	client, ok := c.getWarm(installationID)
+	if !ok {
+		var err error
+		if client, err = c.delegate.NewInstallationClient(installationID); err != nil {
+			return err
+		}
+
+		c.warmMu.Lock()
+		c.warm[installationID] = client
+		c.warmMu.Unlock()
+	}
+
+	if _, err := installationTransport(client); err != nil {
+		// clients from other creators manage tokens in their own way
+		return nil
+	}
+
+	expiry, err := TokenExpiry(ctx, client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create token for installation %d", installationID)
+	}
+	if expiry.Sub(GetClock(ctx).Now()) < WarmTokenMargin {
+		if _, err := RefreshToken(ctx, client); err != nil {
+			return errors.Wrapf(err, "failed to refresh token for installation %d", installationID)
+		}
+	}
+	return nil
+}
+
+func (c *cachingClientCreator) KeepWarm(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultWarmInterval
+	}
+	for {
+		_ = c.Warm(ctx)
+
+		if err := sleepContext(ctx, interval); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCachingClientWarming(t *testing.T) {
+	ctx := context.Background()
+
+	var tokens int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+
+		// the first token expires too soon to keep
+		expiresAt := time.Now().Add(time.Hour)
+		if tokens == 1 {
+			expiresAt = time.Now().Add(WarmTokenMargin / 2)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, tokens, expiresAt.Format(time.RFC3339))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	delegate := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t))
+	cc, err := NewCachingClientCreator(delegate, 1, WithCachingWarmInstallations(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	warmer := cc.(ClientWarmer)
+	if err := warmer.Warm(ctx); err != nil {
+		t.Fatalf("unexpected error warming clients: %v", err)
+	}
+	if tokens != 2 {
+		t.Errorf("expected 2 tokens after warming, but got %d", tokens)
+	}
+
+	if err := warmer.Warm(ctx); err != nil {
+		t.Fatalf("unexpected error warming clients: %v", err)
+	}
+	if tokens != 2 {
+		t.Errorf("expected warm token to be reused, but got %d tokens", tokens)
+	}
+
+	first, _ := cc.NewInstallationClient(1)
+
+	// fill the cache so an unpinned client would be evicted
+	_, _ = cc.NewInstallationClient(2)
+	_, _ = cc.NewInstallationClient(3)
+
+	if second, _ := cc.NewInstallationClient(1); first != second {
+		t.Error("expected the warm client to be returned")
+	}
+
+	cc.(ClientInvalidator).Invalidate(1)
+	if third, _ := cc.NewInstallationClient(1); first == third {
+		t.Error("expected a new client after invalidation")
+	}
+}
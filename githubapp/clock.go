@@ -0,0 +1,111 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"time"
+)
+
+// Clock provides the current time and waits for durations to elapse. Code
+// that expires or refreshes values, waits, or retries reads the Clock from
+// its context with GetClock, so tests can simulate the passage of time
+// instead of sleeping by setting a clock with WithClock. The githubapptest
+// package provides a Clock that tests control manually.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer returns a timer that sends the current time on its channel
+	// after d elapses.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event created by Clock.NewTimer.
+type Timer interface {
+	// C returns the channel that receives the time when the timer fires.
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing. It returns false if the timer
+	// already fired or was stopped. Callers that stop waiting before the
+	// timer fires should call Stop to release the timer.
+	Stop() bool
+}
+
+// SystemClock is the Clock that uses real time. It is the default for all
+// types that accept a Clock.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+type clockKey struct{}
+
+// WithClock returns a context that stores c. Functions that wait, like
+// WaitFor, UploadCheckRunAnnotations, SearchThrottle.Wait, and the timeline
+// helpers, use the clock from their context for timeouts, backoff, and rate
+// limit delays, and long-lived types like schedulers, hook allowlists, jobs,
+// and consumers use the clock from the context of each call. Installation
+// clients use the clock from the context of each request to decide when
+// their token expires and to timestamp rate limits. Application JWTs are
+// signed without a context and use the clock set by WithClientClock.
+func WithClock(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, c)
+}
+
+// GetClock returns the clock stored in the context, or SystemClock if the
+// context does not contain a clock.
+func GetClock(ctx context.Context) Clock {
+	if c, ok := ctx.Value(clockKey{}).(Clock); ok && c != nil {
+		return c
+	}
+	return SystemClock
+}
+
+// sleepContext waits for d to elapse on the clock of the context or for the
+// context to be canceled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := GetClock(ctx).NewTimer(d)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
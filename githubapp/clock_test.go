@@ -0,0 +1,108 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// testClock is a minimal manual clock for tests in this package, which
+// cannot import githubapptest. Timers fire immediately and advance the
+// clock, so code that waits runs without sleeping.
+type testClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	c.slept = append(c.slept, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return firedTimer(ch)
+}
+
+type firedTimer chan time.Time
+
+func (t firedTimer) C() <-chan time.Time { return t }
+func (t firedTimer) Stop() bool          { return false }
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestGetClock(t *testing.T) {
+	if GetClock(context.Background()) != SystemClock {
+		t.Error("expected SystemClock for a context without a clock")
+	}
+
+	c := newTestClock()
+	if GetClock(WithClock(context.Background(), c)) != c {
+		t.Error("expected clock from the context")
+	}
+}
+
+func TestWaitForClock(t *testing.T) {
+	c := newTestClock()
+	ctx := WithClock(context.Background(), c)
+
+	var calls int
+	err := WaitFor(ctx, func(ctx context.Context) (bool, error) {
+		calls++
+		return calls == 4, nil
+	}, Backoff{InitialDelay: time.Second, Multiplier: 2, Jitter: -1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if len(c.slept) != len(expected) {
+		t.Fatalf("incorrect delays: expected %v, actual %v", expected, c.slept)
+	}
+	for i, d := range expected {
+		if c.slept[i] != d {
+			t.Errorf("incorrect delay %d: expected %v, actual %v", i, d, c.slept[i])
+		}
+	}
+
+	err = WaitFor(ctx, func(ctx context.Context) (bool, error) {
+		return false, nil
+	}, Backoff{Timeout: time.Hour})
+	if errors.Cause(err) != ErrWaitTimeout {
+		t.Errorf("expected timeout error, but got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultCommandPrefix is the prefix that identifies a command in a
+	// comment body.
+	DefaultCommandPrefix = "/"
+
+	// DefaultCommandReaction is the reaction added to comments containing
+	// accepted commands.
+	DefaultCommandReaction = "+1"
+)
+
+// Command is a command parsed from a line of a comment, like "/retest" or
+// "/approve reason".
+type Command struct {
+	// Name is the name of the command, without the prefix.
+	Name string
+
+	// Args are the whitespace-separated arguments that followed the name.
+	// Arguments may contain whitespace if they are enclosed in double quotes.
+	Args []string
+}
+
+// CommandHandler processes commands from issue and pull request comments.
+type CommandHandler interface {
+	// Commands returns the names of the commands this handler processes,
+	// without the prefix.
+	Commands() []string
+
+	// HandleCommand processes a single command from the comment in event. The
+	// command dispatcher guarantees that HandleCommand is only called for
+	// commands returned by Commands() and only when the comment author has
+	// the required permission.
+	HandleCommand(ctx context.Context, event *github.IssueCommentEvent, cmd Command) error
+}
+
+// CommandOption configures properties of a command dispatcher.
+type CommandOption func(*commandDispatcher)
+
+// WithCommandPrefix sets the prefix that identifies commands. The default
+// prefix is "/".
+func WithCommandPrefix(prefix string) CommandOption {
+	return func(d *commandDispatcher) {
+		if prefix != "" {
+			d.prefix = prefix
+		}
+	}
+}
+
+// WithCommandPermission sets the minimum repository permission a comment
+// author must have to run the named commands. If no names are given, the
+// permission applies to all commands that do not have a specific permission.
+// Valid permissions are "read", "triage", "write", "maintain", and "admin". By
+// default, commands do not require any permission.
+func WithCommandPermission(permission string, names ...string) CommandOption {
+	return func(d *commandDispatcher) {
+		if len(names) == 0 {
+			d.defaultPermission = permission
+			return
+		}
+		for _, name := range names {
+			d.permissions[name] = permission
+		}
+	}
+}
+
+// WithCommandReaction sets the reaction added to a comment after its commands
+// are accepted for processing. The default reaction is "+1". Set an empty
+// reaction to disable acknowledgment.
+func WithCommandReaction(reaction string) CommandOption {
+	return func(d *commandDispatcher) {
+		d.reaction = reaction
+	}
+}
+
+// WithCommandMembershipService sets the service used to look up the
+// permission of comment authors, which allows caching results across
+// comments. By default, the dispatcher queries GitHub for every comment that
+// contains a command requiring a permission.
+func WithCommandMembershipService(members MembershipService) CommandOption {
+	return func(d *commandDispatcher) {
+		d.members = members
+	}
+}
+
+type commandDispatcher struct {
+	ClientCreator
+
+	handlerMap        map[string]CommandHandler
+	prefix            string
+	reaction          string
+	defaultPermission string
+	permissions       map[string]string
+	members           MembershipService
+}
+
+// NewCommandDispatcher returns an EventHandler for "issue_comment" events that
+// parses commands from new comments and dispatches them to the appropriate
+// command handler. Comments created by bots are ignored.
+//
+// Before calling a handler, the dispatcher checks that the comment author has
+// the required repository permission, if any, and acknowledges the comment by
+// adding a reaction. If the author lacks permission for any command in the
+// comment, no commands are processed. Otherwise, commands are processed in the
+// order they appear in the comment. A failed command does not stop later
+// commands; the errors of all failed commands are returned in a *MultiError.
+func NewCommandDispatcher(cc ClientCreator, handlers []CommandHandler, opts ...CommandOption) EventHandler {
+	handlerMap := make(map[string]CommandHandler)
+
+	// Iterate in reverse so the first entries in the slice have priority
+	for i := len(handlers) - 1; i >= 0; i-- {
+		for _, name := range handlers[i].Commands() {
+			handlerMap[name] = handlers[i]
+		}
+	}
+
+	d := &commandDispatcher{
+		ClientCreator: cc,
+		handlerMap:    handlerMap,
+		prefix:        DefaultCommandPrefix,
+		reaction:      DefaultCommandReaction,
+		permissions:   make(map[string]string),
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+func (d *commandDispatcher) Handles() []string {
+	return Handles(EventIssueComment)
+}
+
+func (d *commandDispatcher) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event github.IssueCommentEvent
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrap(err, "failed to parse issue comment event payload")
+	}
+
+	if event.GetAction() != "created" {
+		return nil
+	}
+
+	author := event.GetComment().GetUser()
+	if author.GetType() == "Bot" {
+		return nil
+	}
+
+	var cmds []Command
+	for _, cmd := range ParseCommands(event.GetComment().GetBody(), d.prefix) {
+		if _, ok := d.handlerMap[cmd.Name]; ok {
+			cmds = append(cmds, cmd)
+		}
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	repo := event.GetRepo()
+	installationID := GetInstallationIDFromEvent(&event)
+	ctx, logger := PreparePRContext(ctx, installationID, repo, event.GetIssue().GetNumber())
+
+	client, err := d.NewInstallationClient(installationID)
+	if err != nil {
+		return err
+	}
+
+	owner := repo.GetOwner().GetLogin()
+	name := repo.GetName()
+
+	var role *string
+	for _, cmd := range cmds {
+		required := d.requiredPermission(cmd.Name)
+		if required == "" {
+			continue
+		}
+		if role == nil {
+			var permission string
+			if d.members != nil {
+				permission, err = d.members.GetRepositoryPermission(ctx, installationID, owner, name, author.GetLogin())
+			} else {
+				permission, err = GetPermission(ctx, client, owner, name, author.GetLogin())
+			}
+			if err != nil {
+				return err
+			}
+			role = &permission
+		}
+		if !PermissionAtLeast(*role, required) {
+			logger.Info().Msgf("Ignoring command %q from %s: requires %q permission, but user has %q", cmd.Name, author.GetLogin(), required, *role)
+			return nil
+		}
+	}
+
+	if d.reaction != "" {
+		if _, err := AddReaction(ctx, client, IssueCommentReactionTarget(owner, name, event.GetComment().GetID()), d.reaction); err != nil {
+			logger.Warn().Err(err).Msg("Failed to acknowledge command comment")
+		}
+	}
+
+	// handle all commands even if some fail and report every failure
+	var errs MultiError
+	for _, cmd := range cmds {
+		logger.Debug().Msgf("Handling command %q from %s", cmd.Name, author.GetLogin())
+		errs.add(fmt.Sprintf("command %q", cmd.Name), d.handlerMap[cmd.Name].HandleCommand(ctx, &event, cmd))
+	}
+	return errs.errorOrNil()
+}
+
+func (d *commandDispatcher) requiredPermission(name string) string {
+	if p, ok := d.permissions[name]; ok {
+		return p
+	}
+	return d.defaultPermission
+}
+
+// ParseCommands returns the commands in a comment body. A command is a line
+// that starts with prefix immediately followed by the command name. Lines in
+// fenced code blocks and quoted lines are ignored.
+func ParseCommands(body, prefix string) []Command {
+	var cmds []Command
+	inCode := false
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") || strings.HasPrefix(line, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || !strings.HasPrefix(line, prefix) {
+			continue
+		}
+
+		rest := strings.TrimPrefix(line, prefix)
+		if rest == "" || unicode.IsSpace(rune(rest[0])) {
+			continue
+		}
+
+		fields := splitCommandArgs(rest)
+		cmds = append(cmds, Command{
+			Name: fields[0],
+			Args: fields[1:],
+		})
+	}
+	return cmds
+}
+
+// splitCommandArgs splits s on whitespace, treating text in double quotes as
+// a single field. An unterminated quote extends to the end of the string.
+func splitCommandArgs(s string) []string {
+	var fields []string
+	var field strings.Builder
+	inField, inQuote := false, false
+
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			inField = true
+		case unicode.IsSpace(r) && !inQuote:
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
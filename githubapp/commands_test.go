@@ -0,0 +1,88 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCommands(t *testing.T) {
+	tests := map[string]struct {
+		Body     string
+		Prefix   string
+		Expected []Command
+	}{
+		"noCommands": {
+			Body:   "This looks good to me!",
+			Prefix: "/",
+		},
+		"singleCommand": {
+			Body:     "/retest",
+			Prefix:   "/",
+			Expected: []Command{{Name: "retest", Args: []string{}}},
+		},
+		"commandWithArgs": {
+			Body:     "Thanks!\n  /approve now please\n",
+			Prefix:   "/",
+			Expected: []Command{{Name: "approve", Args: []string{"now", "please"}}},
+		},
+		"quotedArgs": {
+			Body:     `/label add "needs review" ""`,
+			Prefix:   "/",
+			Expected: []Command{{Name: "label", Args: []string{"add", "needs review", ""}}},
+		},
+		"multipleCommands": {
+			Body:   "/retest\n/approve",
+			Prefix: "/",
+			Expected: []Command{
+				{Name: "retest", Args: []string{}},
+				{Name: "approve", Args: []string{}},
+			},
+		},
+		"ignoresCodeBlocks": {
+			Body:     "```\n/retest\n```\n/approve",
+			Prefix:   "/",
+			Expected: []Command{{Name: "approve", Args: []string{}}},
+		},
+		"ignoresQuotes": {
+			Body:   "> /retest\nNo.",
+			Prefix: "/",
+		},
+		"ignoresSpaceAfterPrefix": {
+			Body:   "/ retest",
+			Prefix: "/",
+		},
+		"customPrefix": {
+			Body:     "/retest\n!bot retest",
+			Prefix:   "!bot ",
+			Expected: []Command{{Name: "retest", Args: []string{}}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmds := ParseCommands(test.Body, test.Prefix)
+			for i := range cmds {
+				if cmds[i].Args == nil {
+					cmds[i].Args = []string{}
+				}
+			}
+			if !reflect.DeepEqual(test.Expected, cmds) {
+				t.Errorf("incorrect commands\nexpected: %+v\n  actual: %+v", test.Expected, cmds)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// PayloadAccount is a user, organization, or bot in a webhook payload.
+type PayloadAccount struct {
+	ID     int64  `json:"id"`
+	NodeID string `json:"node_id"`
+	Login  string `json:"login"`
+	Type   string `json:"type"`
+}
+
+// PayloadInstallation is the installation in a webhook payload.
+type PayloadInstallation struct {
+	ID     int64  `json:"id"`
+	NodeID string `json:"node_id"`
+}
+
+// PayloadRepository is the repository in a webhook payload.
+type PayloadRepository struct {
+	ID            int64          `json:"id"`
+	NodeID        string         `json:"node_id"`
+	Name          string         `json:"name"`
+	FullName      string         `json:"full_name"`
+	Owner         PayloadAccount `json:"owner"`
+	Private       bool           `json:"private"`
+	Archived      bool           `json:"archived"`
+	Fork          bool           `json:"fork"`
+	DefaultBranch string         `json:"default_branch"`
+	HTMLURL       string         `json:"html_url"`
+}
+
+// CommonPayload contains the fields shared by most webhook payloads. Unlike
+// the types from go-github used by ParseEvent and the context helpers, these
+// types are defined by this package and do not change when go-github
+// changes. Applications that only need these fields can use them to upgrade
+// go-github independently of this module.
+//
+// Fields that are not present in the payload are nil or empty.
+type CommonPayload struct {
+	Action       string               `json:"action"`
+	Installation *PayloadInstallation `json:"installation"`
+	Repository   *PayloadRepository   `json:"repository"`
+	Organization *PayloadAccount      `json:"organization"`
+	Sender       *PayloadAccount      `json:"sender"`
+}
+
+// ParseCommonPayload parses the common fields of a webhook payload.
+func ParseCommonPayload(payload []byte) (*CommonPayload, error) {
+	var p CommonPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, errors.Wrap(err, "failed to parse payload")
+	}
+	return &p, nil
+}
+
+// InstallationID returns the ID of the installation, or 0 if the payload has
+// no installation.
+func (p *CommonPayload) InstallationID() int64 {
+	if p == nil || p.Installation == nil {
+		return 0
+	}
+	return p.Installation.ID
+}
+
+// RepositoryOwner returns the login of the repository owner, or the empty
+// string if the payload has no repository.
+func (p *CommonPayload) RepositoryOwner() string {
+	if p == nil || p.Repository == nil {
+		return ""
+	}
+	return p.Repository.Owner.Login
+}
+
+// RepositoryName returns the name of the repository, or the empty string if
+// the payload has no repository.
+func (p *CommonPayload) RepositoryName() string {
+	if p == nil || p.Repository == nil {
+		return ""
+	}
+	return p.Repository.Name
+}
+
+// SenderLogin returns the login of the account that triggered the event, or
+// the empty string if the payload has no sender.
+func (p *CommonPayload) SenderLogin() string {
+	if p == nil || p.Sender == nil {
+		return ""
+	}
+	return p.Sender.Login
+}
+
+// PrepareContext adds information about the installation, repository, and
+// organization of the payload to the logger in a context and returns the
+// modified context and logger. It uses the same log keys as
+// PrepareRepoContext and PrepareOrgContext.
+func (p *CommonPayload) PrepareContext(ctx context.Context) (context.Context, zerolog.Logger) {
+	logctx := zerolog.Ctx(ctx).With()
+
+	logctx = attachInstallationLogKeys(logctx, p.InstallationID())
+	if p.Repository != nil {
+		logctx = logctx.
+			Str(LogKeyRepositoryOwner, p.RepositoryOwner()).
+			Str(LogKeyRepositoryName, p.RepositoryName())
+	}
+	if p.Organization != nil {
+		logctx = logctx.Str(LogKeyOrganization, p.Organization.Login)
+	}
+
+	logger := logctx.Logger()
+	return logger.WithContext(ctx), logger
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestParseCommonPayload(t *testing.T) {
+	tests := map[string]struct {
+		Payload      string
+		Action       string
+		Installation int64
+		Owner        string
+		Name         string
+		Sender       string
+		Err          bool
+	}{
+		"repository": {
+			Payload: `{
+				"action": "opened",
+				"number": 1,
+				"repository": {"id": 10, "name": "repo", "full_name": "octo/repo", "owner": {"login": "octo", "type": "Organization"}},
+				"organization": {"login": "octo", "id": 2},
+				"installation": {"id": 123, "node_id": "MDIz"},
+				"sender": {"login": "mhaypenny", "type": "User"}
+			}`,
+			Action:       "opened",
+			Installation: 123,
+			Owner:        "octo",
+			Name:         "repo",
+			Sender:       "mhaypenny",
+		},
+		"missingFields": {
+			Payload: `{"zen": "Keep it logically awesome."}`,
+		},
+		"invalid": {
+			Payload: `{"action": `,
+			Err:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseCommonPayload([]byte(test.Payload))
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if p.Action != test.Action {
+				t.Errorf("incorrect action: expected %q, actual %q", test.Action, p.Action)
+			}
+			if p.InstallationID() != test.Installation {
+				t.Errorf("incorrect installation ID: expected %d, actual %d", test.Installation, p.InstallationID())
+			}
+			if p.RepositoryOwner() != test.Owner {
+				t.Errorf("incorrect owner: expected %q, actual %q", test.Owner, p.RepositoryOwner())
+			}
+			if p.RepositoryName() != test.Name {
+				t.Errorf("incorrect name: expected %q, actual %q", test.Name, p.RepositoryName())
+			}
+			if p.SenderLogin() != test.Sender {
+				t.Errorf("incorrect sender: expected %q, actual %q", test.Sender, p.SenderLogin())
+			}
+		})
+	}
+}
+
+func TestCommonPayloadPrepareContext(t *testing.T) {
+	p, err := ParseCommonPayload([]byte(`{
+		"repository": {"name": "repo", "owner": {"login": "octo"}},
+		"installation": {"id": 123}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	logger := zerolog.New(&out)
+
+	_, logger = p.PrepareContext(logger.WithContext(context.Background()))
+	logger.Info().Msg("")
+
+	for _, field := range []string{
+		`"github_installation_id":123`,
+		`"github_repository_owner":"octo"`,
+		`"github_repository_name":"repo"`,
+	} {
+		if !strings.Contains(out.String(), field) {
+			t.Errorf("expected log output to contain %s, actual %s", field, out.String())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v66/github"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultETagStoreSize is the number of URLs remembered by a memory
+	// ETagStore if the size is not positive.
+	DefaultETagStoreSize = 1000
+)
+
+// ETagStore stores the entity tags (ETags) of responses by URL, so that
+// polling requests can ask GitHub to only return data that changed.
+// Implementations must be safe for concurrent use.
+//
+// GitHub computes ETags for the authenticated caller, so a store should only
+// be shared between clients with the same credentials, like all clients for
+// one installation.
+type ETagStore interface {
+	GetETag(url string) (string, bool)
+	SetETag(url, etag string)
+}
+
+// NewMemoryETagStore creates an ETagStore that keeps the ETags of the size
+// most recently used URLs in memory. If size is not positive, it uses
+// DefaultETagStoreSize.
+func NewMemoryETagStore(size int) ETagStore {
+	if size <= 0 {
+		size = DefaultETagStoreSize
+	}
+
+	// lru.New only returns an error for non-positive sizes
+	cache, _ := lru.New(size)
+	return &memoryETagStore{cache: cache}
+}
+
+type memoryETagStore struct {
+	cache *lru.Cache
+}
+
+func (s *memoryETagStore) GetETag(url string) (string, bool) {
+	if v, ok := s.cache.Get(url); ok {
+		return v.(string), true
+	}
+	return "", false
+}
+
+func (s *memoryETagStore) SetETag(url, etag string) {
+	s.cache.Add(url, etag)
+}
+
+// PollResult is the result of a conditional request made by PollConditional.
+type PollResult struct {
+	// NotModified is true if the resource did not change since the last
+	// request that stored an ETag. In this case, the value passed to
+	// PollConditional is not modified.
+	NotModified bool
+
+	// ETag is the current ETag of the resource.
+	ETag string
+
+	// Response is the response from GitHub.
+	Response *github.Response
+}
+
+// PollConditional sends a GET request for url, which is relative to the base
+// URL of client, and decodes the response into v. If store has an ETag for
+// the URL, the request only asks for the resource if it changed since that
+// ETag. When it did not change, GitHub responds with "304 Not Modified",
+// which does not count against the rate limit, and the result has
+// NotModified set. This makes loops that poll deliveries, installations, or
+// configuration files almost free.
+//
+// Use a client without caching (see WithClientCaching): the cache answers
+// conditional requests itself and hides unmodified responses.
+func PollConditional(ctx context.Context, client *github.Client, store ETagStore, url string, v interface{}) (PollResult, error) {
+	req, err := client.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return PollResult{}, errors.Wrapf(err, "failed to create request for %s", url)
+	}
+
+	key := req.URL.String()
+	etag, hasETag := store.GetETag(key)
+	if hasETag {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	res, err := client.Do(ctx, req, v)
+	if res != nil && res.StatusCode == http.StatusNotModified {
+		return PollResult{NotModified: true, ETag: etag, Response: res}, nil
+	}
+	if err != nil {
+		return PollResult{Response: res}, errors.Wrapf(err, "failed to get %s", url)
+	}
+
+	result := PollResult{ETag: res.Header.Get("ETag"), Response: res}
+	if result.ETag != "" {
+		store.SetETag(key, result.ETag)
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestPollConditional(t *testing.T) {
+	version := 1
+	var conditional []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/installations", func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+
+		etag := fmt.Sprintf(`"v%d"`, version)
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `[{"id": %d}]`, version)
+	})
+	cc := newStaticClientCreator(t, mux)
+	store := NewMemoryETagStore(0)
+
+	poll := func() ([]*github.Installation, PollResult) {
+		var installations []*github.Installation
+		res, err := PollConditional(context.Background(), cc.client, store, "app/installations", &installations)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return installations, res
+	}
+
+	installations, res := poll()
+	if res.NotModified || len(installations) != 1 || installations[0].GetID() != 1 {
+		t.Fatalf("incorrect first result: %+v, %v", res, installations)
+	}
+
+	installations, res = poll()
+	if !res.NotModified || installations != nil {
+		t.Errorf("expected unmodified result without data, but got %+v, %v", res, installations)
+	}
+	if res.ETag != `"v1"` {
+		t.Errorf("incorrect ETag: %s", res.ETag)
+	}
+
+	version = 2
+	installations, res = poll()
+	if res.NotModified || len(installations) != 1 || installations[0].GetID() != 2 {
+		t.Errorf("incorrect result after change: %+v, %v", res, installations)
+	}
+
+	expected := []string{"", `"v1"`, `"v1"`}
+	if fmt.Sprint(conditional) != fmt.Sprint(expected) {
+		t.Errorf("incorrect If-None-Match headers: expected %q, actual %q", expected, conditional)
+	}
+}
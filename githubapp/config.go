@@ -15,8 +15,23 @@
 package githubapp
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"os"
 	"strconv"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// RedactedValue replaces values in a redacted configuration that cannot
+	// be summarized safely, like a private key that does not parse.
+	RedactedValue = "REDACTED"
 )
 
 type Config struct {
@@ -24,16 +39,30 @@ type Config struct {
 	V3APIURL string `yaml:"v3_api_url" json:"v3ApiUrl"`
 	V4APIURL string `yaml:"v4_api_url" json:"v4ApiUrl"`
 
+	// UploadURL is the base URL for uploads, like release assets. If empty,
+	// clients derive it from V3APIURL.
+	UploadURL string `yaml:"upload_url" json:"uploadUrl"`
+
 	App struct {
 		IntegrationID int64  `yaml:"integration_id" json:"integrationId"`
 		WebhookSecret string `yaml:"webhook_secret" json:"webhookSecret"`
 		PrivateKey    string `yaml:"private_key" json:"privateKey"`
+
+		// WebhookRoute is the path that receives webhook deliveries for the
+		// app. If empty, the app uses DefaultWebhookRoute. Set different
+		// routes to serve several apps, or several versions of an app, from
+		// one server.
+		WebhookRoute string `yaml:"webhook_route" json:"webhookRoute"`
 	} `yaml:"app" json:"app"`
 
 	OAuth struct {
 		ClientID     string `yaml:"client_id" json:"clientId"`
 		ClientSecret string `yaml:"client_secret" json:"clientSecret"`
 	} `yaml:"oauth" json:"oauth"`
+
+	// Admission restricts the owners, repositories, and senders whose events
+	// the app processes. Pass it to WithAdmissionPolicy.
+	Admission AdmissionPolicy `yaml:"admission" json:"admission"`
 }
 
 // SetValuesFromEnv sets values in the configuration from coresponding
@@ -43,15 +72,108 @@ func (c *Config) SetValuesFromEnv(prefix string) {
 	setStringFromEnv("GITHUB_WEB_URL", prefix, &c.WebURL)
 	setStringFromEnv("GITHUB_V3_API_URL", prefix, &c.V3APIURL)
 	setStringFromEnv("GITHUB_V4_API_URL", prefix, &c.V4APIURL)
+	setStringFromEnv("GITHUB_UPLOAD_URL", prefix, &c.UploadURL)
 
 	setIntFromEnv("GITHUB_APP_INTEGRATION_ID", prefix, &c.App.IntegrationID)
 	setStringFromEnv("GITHUB_APP_WEBHOOK_SECRET", prefix, &c.App.WebhookSecret)
 	setStringFromEnv("GITHUB_APP_PRIVATE_KEY", prefix, &c.App.PrivateKey)
+	setStringFromEnv("GITHUB_APP_WEBHOOK_ROUTE", prefix, &c.App.WebhookRoute)
 
 	setStringFromEnv("GITHUB_OAUTH_CLIENT_ID", prefix, &c.OAuth.ClientID)
 	setStringFromEnv("GITHUB_OAUTH_CLIENT_SECRET", prefix, &c.OAuth.ClientSecret)
 }
 
+// WebhookPath returns the path that receives webhook deliveries for the app,
+// which is DefaultWebhookRoute unless the configuration sets a route.
+func (c *Config) WebhookPath() string {
+	if c.App.WebhookRoute == "" {
+		return DefaultWebhookRoute
+	}
+	return c.App.WebhookRoute
+}
+
+// Redacted returns a copy of the configuration that is safe to log or show
+// on diagnostic endpoints, so operators can confirm the effective
+// configuration without exposing credentials. The private key is replaced
+// by its fingerprint, as returned by PrivateKeyFingerprint. Secrets are
+// replaced by "hmac:" and a short HMAC-SHA256 keyed with a random value
+// chosen when the process starts, so the values identify a secret within
+// one process but cannot be used to guess it. Empty values stay empty so
+// missing configuration is still visible.
+func (c Config) Redacted() Config {
+	r := c
+
+	if c.App.PrivateKey != "" {
+		fingerprint, err := PrivateKeyFingerprint([]byte(c.App.PrivateKey))
+		if err != nil {
+			fingerprint = RedactedValue
+		}
+		r.App.PrivateKey = fingerprint
+	}
+	r.App.WebhookSecret = hashSecret(c.App.WebhookSecret)
+	r.OAuth.ClientSecret = hashSecret(c.OAuth.ClientSecret)
+
+	return r
+}
+
+// NewClientCreatorFromConfig returns a ClientCreator for the app and URLs in
+// c, without caching. The options are applied after the upload URL from c,
+// so they can override it.
+func NewClientCreatorFromConfig(c Config, opts ...ClientOption) ClientCreator {
+	if c.UploadURL != "" {
+		opts = append([]ClientOption{WithClientUploadURL(c.UploadURL)}, opts...)
+	}
+	return NewClientCreator(
+		c.V3APIURL,
+		c.V4APIURL,
+		c.App.IntegrationID,
+		[]byte(c.App.PrivateKey),
+		opts...,
+	)
+}
+
+// PrivateKeyFingerprint returns the fingerprint of a PEM-encoded RSA private
+// key: "SHA256:" and the base64-encoded SHA-256 hash of the public key. This
+// matches the fingerprint GitHub shows for the keys of an app, so it
+// identifies which key an application is using without revealing it.
+func PrivateKeyFingerprint(privateKey []byte) (string, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(privateKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse private key")
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal public key")
+	}
+
+	sum := sha256.Sum256(der)
+	return "SHA256:" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// secretHashKey keys the hashes of redacted secrets. It is random so that the
+// hashes cannot be used to test guesses of a weak secret offline.
+var secretHashKey = newSecretHashKey()
+
+func newSecretHashKey() []byte {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(errors.Wrap(err, "failed to generate secret hash key"))
+	}
+	return key
+}
+
+// hashSecret returns a short keyed hash of a secret that identifies it
+// within this process without revealing it
+func hashSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, secretHashKey)
+	mac.Write([]byte(secret))
+	return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
 func setStringFromEnv(key, prefix string, value *string) {
 	if v, ok := os.LookupEnv(prefix + key); ok {
 		*value = v
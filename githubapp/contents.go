@@ -0,0 +1,43 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/contents"
+)
+
+// GetFileContents returns the content of the file at path on ref in
+// owner/repo. It returns an empty slice and false if the file does not exist
+// or if the path is a directory. See contents.GetFile.
+func GetFileContents(ctx context.Context, client *github.Client, owner, repo, ref, path string) ([]byte, bool, error) {
+	return contents.GetFile(ctx, client, owner, repo, ref, path)
+}
+
+// ListDirectory returns the entries of the directory at path on ref in
+// owner/repo. It returns nil and false if the directory does not exist or if
+// the path is a file. See contents.ListDirectory.
+func ListDirectory(ctx context.Context, client *github.Client, owner, repo, ref, path string) ([]*github.RepositoryContent, bool, error) {
+	return contents.ListDirectory(ctx, client, owner, repo, ref, path)
+}
+
+// OpenBlob returns a reader for the raw content of the blob with the given
+// SHA in owner/repo. Callers must close the reader. See contents.OpenBlob.
+func OpenBlob(ctx context.Context, client *github.Client, owner, repo, sha string) (io.ReadCloser, error) {
+	return contents.OpenBlob(ctx, client, owner, repo, sha)
+}
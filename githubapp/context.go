@@ -28,6 +28,17 @@ const (
 	LogKeyRepositoryOwner string = "github_repository_owner"
 	LogKeyPRNum           string = "github_pr_num"
 	LogKeyInstallationID  string = "github_installation_id"
+	LogKeyOrganization    string = "github_organization"
+	LogKeyEnterprise      string = "github_enterprise"
+	LogKeyMergeGroupSHA   string = "github_merge_group_sha"
+	LogKeyDeliveryLatency string = "github_delivery_latency"
+	LogKeyReplay          string = "github_replay"
+	LogKeyAppID           string = "github_app_id"
+	LogKeyAppSlug         string = "github_app_slug"
+	LogKeyKeyFingerprint  string = "github_key_fingerprint"
+	LogKeyV3APIURL        string = "github_v3_api_url"
+	LogKeyV4APIURL        string = "github_v4_api_url"
+	LogKeyUploadURL       string = "github_upload_url"
 )
 
 // PrepareRepoContext adds information about a repository to the logger in a
@@ -55,6 +66,52 @@ func PreparePRContext(ctx context.Context, installationID int64, repo *github.Re
 	return logger.WithContext(ctx), logger
 }
 
+// PrepareMergeGroupContext adds information about a merge group to the
+// logger in a context and returns the modified context and logger. In
+// addition to the head SHA of the group, it adds the number of the pull
+// request that created the group, as returned by MergeGroupPullRequestNumber.
+func PrepareMergeGroupContext(ctx context.Context, installationID int64, repo *github.Repository, group *github.MergeGroup) (context.Context, zerolog.Logger) {
+	logctx := zerolog.Ctx(ctx).With()
+
+	logctx = attachInstallationLogKeys(logctx, installationID)
+	logctx = attachRepoLogKeys(logctx, repo)
+	logctx = attachPullRequestLogKeys(logctx, MergeGroupPullRequestNumber(group))
+	if sha := group.GetHeadSHA(); sha != "" {
+		logctx = logctx.Str(LogKeyMergeGroupSHA, sha)
+	}
+
+	logger := logctx.Logger()
+	return logger.WithContext(ctx), logger
+}
+
+// PrepareOrgContext adds information about an organization to the logger in
+// a context and returns the modified context and logger. Use it for events
+// that are not associated with a repository, like "organization" or "team"
+// events.
+func PrepareOrgContext(ctx context.Context, installationID int64, org *github.Organization) (context.Context, zerolog.Logger) {
+	logctx := zerolog.Ctx(ctx).With()
+
+	logctx = attachInstallationLogKeys(logctx, installationID)
+	logctx = attachOrgLogKeys(logctx, org)
+
+	logger := logctx.Logger()
+	return logger.WithContext(ctx), logger
+}
+
+// PrepareEnterpriseContext adds information about an enterprise to the logger
+// in a context and returns the modified context and logger. Use it for
+// enterprise events that are not associated with an organization or
+// repository.
+func PrepareEnterpriseContext(ctx context.Context, installationID int64, enterprise *github.Enterprise) (context.Context, zerolog.Logger) {
+	logctx := zerolog.Ctx(ctx).With()
+
+	logctx = attachInstallationLogKeys(logctx, installationID)
+	logctx = attachEnterpriseLogKeys(logctx, enterprise)
+
+	logger := logctx.Logger()
+	return logger.WithContext(ctx), logger
+}
+
 func attachInstallationLogKeys(logctx zerolog.Context, installID int64) zerolog.Context {
 	if installID > 0 {
 		return logctx.Int64(LogKeyInstallationID, installID)
@@ -77,3 +134,17 @@ func attachPullRequestLogKeys(logctx zerolog.Context, number int) zerolog.Contex
 	}
 	return logctx
 }
+
+func attachOrgLogKeys(logctx zerolog.Context, org *github.Organization) zerolog.Context {
+	if org != nil {
+		return logctx.Str(LogKeyOrganization, org.GetLogin())
+	}
+	return logctx
+}
+
+func attachEnterpriseLogKeys(logctx zerolog.Context, enterprise *github.Enterprise) zerolog.Context {
+	if enterprise != nil {
+		return logctx.Str(LogKeyEnterprise, enterprise.GetSlug())
+	}
+	return logctx
+}
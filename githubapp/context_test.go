@@ -84,6 +84,88 @@ func TestPreparePRContext(t *testing.T) {
 	assertField(t, "pull request number", 128, entry.Number)
 }
 
+func TestPrepareMergeGroupContext(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := zerolog.New(&out)
+	ctx := logger.WithContext(context.Background())
+
+	_, logger = PrepareMergeGroupContext(ctx, 42, &github.Repository{
+		Name: github.String("test"),
+		Owner: &github.User{
+			Login: github.String("mhaypenny"),
+		},
+	}, &github.MergeGroup{
+		HeadSHA: github.String("0a1b2c"),
+		HeadRef: github.String("refs/heads/gh-readonly-queue/main/pr-128-0a1b2c"),
+	})
+
+	logger.Info().Msg("")
+
+	var entry struct {
+		ID     int64  `json:"github_installation_id"`
+		Name   string `json:"github_repository_name"`
+		Number int    `json:"github_pr_num"`
+		SHA    string `json:"github_merge_group_sha"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %s: %v", out.String(), err)
+	}
+
+	assertField(t, "installation ID", int64(42), entry.ID)
+	assertField(t, "repository name", "test", entry.Name)
+	assertField(t, "pull request number", 128, entry.Number)
+	assertField(t, "merge group SHA", "0a1b2c", entry.SHA)
+}
+
+func TestPrepareOrgContext(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := zerolog.New(&out)
+	ctx := logger.WithContext(context.Background())
+
+	_, logger = PrepareOrgContext(ctx, 42, &github.Organization{
+		Login: github.String("octo-org"),
+	})
+
+	logger.Info().Msg("")
+
+	var entry struct {
+		ID  int64  `json:"github_installation_id"`
+		Org string `json:"github_organization"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %s: %v", out.String(), err)
+	}
+
+	assertField(t, "installation ID", int64(42), entry.ID)
+	assertField(t, "organization", "octo-org", entry.Org)
+}
+
+func TestPrepareEnterpriseContext(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := zerolog.New(&out)
+	ctx := logger.WithContext(context.Background())
+
+	_, logger = PrepareEnterpriseContext(ctx, 42, &github.Enterprise{
+		Slug: github.String("octo-corp"),
+	})
+
+	logger.Info().Msg("")
+
+	var entry struct {
+		ID         int64  `json:"github_installation_id"`
+		Enterprise string `json:"github_enterprise"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid log entry: %s: %v", out.String(), err)
+	}
+
+	assertField(t, "installation ID", int64(42), entry.ID)
+	assertField(t, "enterprise", "octo-corp", entry.Enterprise)
+}
+
 func assertField(t *testing.T, name string, expected, actual interface{}) {
 	if expected != actual {
 		t.Errorf("incorrect %s: expected %#v (%T), but was %#v (%T)", name, expected, expected, actual, actual)
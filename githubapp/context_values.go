@@ -0,0 +1,103 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+)
+
+// ContextValues contains the values that the library stores in contexts. It
+// is a snapshot: values set in the context after calling FromContext, like
+// a responder set by a later handler or rate limits from later requests, are
+// not reflected in it.
+type ContextValues struct {
+	// EventType and DeliveryID identify the delivery being processed. They
+	// are empty if the context does not contain a delivery.
+	EventType  string
+	DeliveryID string
+
+	// Payload is the payload of the delivery. It is shared by all users of
+	// the context and must not be modified.
+	Payload []byte
+
+	// Header contains the headers that describe the delivery, as returned by
+	// DeliveryHeader, or nil if they are not available. It is shared by all
+	// users of the context and must not be modified.
+	Header http.Header
+
+	// InstallationID is the installation of the client that made the request
+	// in contexts of requests sent by clients from a ClientCreator. In other
+	// contexts, it is the installation from the delivery payload. It is 0 if
+	// neither is available.
+	InstallationID int64
+
+	// Responder is the function set with SetResponder, or nil if the context
+	// was not initialized by InitializeResponder, the event is handled
+	// asynchronously, or no handler set one.
+	Responder func(http.ResponseWriter, *http.Request)
+
+	// RateLimits are the rate limits returned by RateLimits.
+	RateLimits map[string]RateLimit
+
+	// Tenant is the tenant stored with WithTenant, or nil.
+	Tenant *Tenant
+
+	// RetryBudget is the budget stored with WithRetryBudget, or nil.
+	RetryBudget *RetryBudget
+
+	// Replay is true if the delivery is a replay, as reported by IsReplay.
+	Replay bool
+}
+
+// FromContext returns the values that the library stores in ctx, so
+// middleware, handler wrappers, and callbacks can read them without
+// depending on individual accessors. Use the functions that set each value,
+// like WithDelivery, InitializeResponder, and InitializeRateLimits, to create
+// contexts for tests or custom dispatchers.
+func FromContext(ctx context.Context) ContextValues {
+	v := ContextValues{
+		Responder:   GetResponder(ctx),
+		RateLimits:  RateLimits(ctx),
+		Tenant:      GetTenant(ctx),
+		RetryBudget: GetRetryBudget(ctx),
+		Replay:      IsReplay(ctx),
+	}
+
+	if d, ok := GetDelivery(ctx); ok {
+		v.EventType = d.EventType
+		v.DeliveryID = d.DeliveryID
+		v.Payload = d.Payload
+		v.Header = d.Header
+	}
+
+	v.InstallationID = contextInstallationID(ctx)
+
+	return v
+}
+
+// contextInstallationID returns the installation of the client request in
+// ctx or, if there is none, the installation from the delivery payload.
+func contextInstallationID(ctx context.Context) int64 {
+	if id, ok := ctx.Value(installationKey).(int64); ok && id > 0 {
+		return id
+	}
+	if d, ok := GetDelivery(ctx); ok && len(d.Payload) > 0 {
+		if e, err := PeekEnvelopeContext(ctx, d.Payload); err == nil {
+			return e.InstallationID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		v := FromContext(context.Background())
+		if v.EventType != "" || v.DeliveryID != "" || v.InstallationID != 0 || v.Responder != nil || v.RateLimits != nil || v.Tenant != nil {
+			t.Errorf("expected zero values, but got %+v", v)
+		}
+	})
+
+	t.Run("handlerContext", func(t *testing.T) {
+		var v ContextValues
+		h := &TestEventHandler{
+			Types: []string{"pull_request"},
+			Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+				SetResponder(ctx, func(w http.ResponseWriter, r *http.Request) {})
+				ctx = WithTenant(ctx, &Tenant{InstallationID: 42})
+				v = FromContext(ctx)
+				return nil
+			},
+		}
+
+		ctx := WithDelivery(InitializeResponder(context.Background()), Delivery{
+			EventType:  "pull_request",
+			DeliveryID: "delivery-id",
+			Payload:    []byte(`{"action": "opened", "installation": {"id": 42}}`),
+		})
+		if err := h.Handle(ctx, "pull_request", "delivery-id", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if v.EventType != "pull_request" || v.DeliveryID != "delivery-id" {
+			t.Errorf("incorrect delivery: %q, %q", v.EventType, v.DeliveryID)
+		}
+		if v.InstallationID != 42 {
+			t.Errorf("incorrect installation ID: expected 42, actual %d", v.InstallationID)
+		}
+		if v.Responder == nil {
+			t.Error("expected responder, but got nil")
+		}
+		if v.Tenant == nil || v.Tenant.InstallationID != 42 {
+			t.Errorf("incorrect tenant: %+v", v.Tenant)
+		}
+	})
+
+	t.Run("clientRequest", func(t *testing.T) {
+		var v ContextValues
+		rt := setInstallationID(7)(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			v = FromContext(r.Context())
+			return httptest.NewRecorder().Result(), nil
+		}))
+
+		ctx := WithDelivery(context.Background(), Delivery{
+			EventType: "pull_request",
+			Payload:   []byte(`{"installation": {"id": 42}}`),
+		})
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if v.InstallationID != 7 {
+			t.Errorf("incorrect installation ID: expected 7, actual %d", v.InstallationID)
+		}
+	})
+}
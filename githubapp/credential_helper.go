@@ -0,0 +1,185 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CredentialUsername is the username git uses with installation tokens.
+	CredentialUsername = "x-access-token"
+)
+
+// CredentialHelper implements the git credential helper protocol using
+// installation tokens. It allows processes to run git commands against
+// repositories where the application is installed without embedding tokens in
+// remote URLs.
+//
+// The helper needs to know the repository for each request, so git must be
+// configured to send paths to credential helpers:
+//
+//	git config credential.useHttpPath true
+//
+// See https://git-scm.com/docs/gitcredentials for more information.
+type CredentialHelper struct {
+	cc       ClientCreator
+	host     string
+	installs InstallationsService
+	scoped   bool
+}
+
+// CredentialHelperOption configures properties of a credential helper.
+type CredentialHelperOption func(*CredentialHelper)
+
+// WithCredentialHost sets the host for which the helper provides credentials.
+// Requests for other hosts are ignored. The default host is "github.com".
+func WithCredentialHost(host string) CredentialHelperOption {
+	return func(h *CredentialHelper) {
+		if host != "" {
+			h.host = host
+		}
+	}
+}
+
+// WithCredentialInstallationsService sets the service used to find the
+// installation for a repository. By default, the helper queries GitHub for
+// every request using an application client.
+func WithCredentialInstallationsService(installs InstallationsService) CredentialHelperOption {
+	return func(h *CredentialHelper) {
+		h.installs = installs
+	}
+}
+
+// WithCredentialRepositoryScope sets whether tokens are restricted to the
+// requested repository. Tokens are restricted by default.
+func WithCredentialRepositoryScope(scoped bool) CredentialHelperOption {
+	return func(h *CredentialHelper) {
+		h.scoped = scoped
+	}
+}
+
+// NewCredentialHelper returns a CredentialHelper that creates installation
+// tokens using application clients from cc.
+func NewCredentialHelper(cc ClientCreator, opts ...CredentialHelperOption) *CredentialHelper {
+	h := &CredentialHelper{
+		cc:     cc,
+		host:   "github.com",
+		scoped: true,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Serve processes a single credential helper operation, reading attributes
+// from in and writing the response to out. Only the "get" operation produces
+// output; the "store" and "erase" operations are accepted and ignored because
+// installation tokens are always created on demand.
+//
+// If the request is for a different host or does not include a repository
+// path, Serve writes nothing so that git can try other helpers.
+func (h *CredentialHelper) Serve(ctx context.Context, operation string, in io.Reader, out io.Writer) error {
+	attrs, err := readCredentialAttributes(in)
+	if err != nil {
+		return err
+	}
+
+	if operation != "get" {
+		return nil
+	}
+	if attrs["protocol"] != "https" || attrs["host"] != h.host {
+		return nil
+	}
+
+	owner, repo, ok := splitCredentialPath(attrs["path"])
+	if !ok {
+		return nil
+	}
+
+	token, err := h.Token(ctx, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(out, "username=%s\npassword=%s\n", CredentialUsername, token.GetToken())
+	return err
+}
+
+// Token creates an installation token for the repository owner/repo.
+func (h *CredentialHelper) Token(ctx context.Context, owner, repo string) (*github.InstallationToken, error) {
+	client, err := h.cc.NewAppClient()
+	if err != nil {
+		return nil, err
+	}
+
+	installs := h.installs
+	if installs == nil {
+		installs = NewInstallationsService(client)
+	}
+
+	install, err := installs.GetByRepository(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts github.InstallationTokenOptions
+	if h.scoped {
+		opts.Repositories = []string{repo}
+	}
+
+	token, _, err := client.Apps.CreateInstallationToken(ctx, install.ID, &opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create token for installation %d", install.ID)
+	}
+	return token, nil
+}
+
+func readCredentialAttributes(in io.Reader) (map[string]string, error) {
+	attrs := make(map[string]string)
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			attrs[k] = v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read credential attributes")
+	}
+	return attrs, nil
+}
+
+func splitCredentialPath(path string) (owner, repo string, ok bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], strings.TrimSuffix(parts[1], ".git"), true
+}
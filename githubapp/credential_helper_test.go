@@ -0,0 +1,166 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestCredentialHelper(t *testing.T) {
+	var created *github.InstallationTokenOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/{owner}/{repo}/installation", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("owner") != "octo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 123, "account": {"login": "octo"}}`)
+	})
+	mux.HandleFunc("POST /app/installations/123/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		created = &github.InstallationTokenOptions{}
+		if err := json.NewDecoder(r.Body).Decode(created); err != nil {
+			t.Errorf("failed to decode token options: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"token": "ghs_token", "expires_at": "2026-01-01T01:00:00Z"}`)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	tests := map[string]struct {
+		Operation string
+		Input     string
+		Options   []CredentialHelperOption
+
+		Output       string
+		Repositories []string
+		Err          bool
+	}{
+		"get": {
+			Operation:    "get",
+			Input:        "protocol=https\nhost=github.com\npath=octo/app.git\n\n",
+			Output:       "username=x-access-token\npassword=ghs_token\n",
+			Repositories: []string{"app"},
+		},
+		"unscoped": {
+			Operation: "get",
+			Input:     "protocol=https\nhost=github.com\npath=octo/app.git\n\n",
+			Options:   []CredentialHelperOption{WithCredentialRepositoryScope(false)},
+			Output:    "username=x-access-token\npassword=ghs_token\n",
+		},
+		"customHost": {
+			Operation:    "get",
+			Input:        "protocol=https\nhost=ghe.example.com\npath=/octo/app\n",
+			Options:      []CredentialHelperOption{WithCredentialHost("ghe.example.com")},
+			Output:       "username=x-access-token\npassword=ghs_token\n",
+			Repositories: []string{"app"},
+		},
+		"otherHost": {
+			Operation: "get",
+			Input:     "protocol=https\nhost=gitlab.com\npath=octo/app.git\n\n",
+		},
+		"http": {
+			Operation: "get",
+			Input:     "protocol=http\nhost=github.com\npath=octo/app.git\n\n",
+		},
+		"missingPath": {
+			Operation: "get",
+			Input:     "protocol=https\nhost=github.com\n\n",
+		},
+		"store": {
+			Operation: "store",
+			Input:     "protocol=https\nhost=github.com\npath=octo/app.git\nusername=x-access-token\npassword=ghs_token\n\n",
+		},
+		"erase": {
+			Operation: "erase",
+			Input:     "protocol=https\nhost=github.com\npath=octo/app.git\n\n",
+		},
+		"notInstalled": {
+			Operation: "get",
+			Input:     "protocol=https\nhost=github.com\npath=other/app.git\n\n",
+			Err:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			created = nil
+
+			var out bytes.Buffer
+			h := NewCredentialHelper(cc, test.Options...)
+			err := h.Serve(context.Background(), test.Operation, strings.NewReader(test.Input), &out)
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if out.String() != test.Output {
+				t.Errorf("expected output %q, but got %q", test.Output, out.String())
+			}
+			if test.Output == "" {
+				if created != nil {
+					t.Error("expected no token to be created")
+				}
+				return
+			}
+			if created == nil {
+				t.Fatal("expected a token to be created")
+			}
+			if !reflect.DeepEqual(created.Repositories, test.Repositories) {
+				t.Errorf("expected repositories %v, but got %v", test.Repositories, created.Repositories)
+			}
+		})
+	}
+}
+
+func TestSplitCredentialPath(t *testing.T) {
+	tests := map[string]struct {
+		Path  string
+		Owner string
+		Repo  string
+		OK    bool
+	}{
+		"repo":          {Path: "octo/app", Owner: "octo", Repo: "app", OK: true},
+		"gitSuffix":     {Path: "octo/app.git", Owner: "octo", Repo: "app", OK: true},
+		"leadingSlash":  {Path: "/octo/app.git/", Owner: "octo", Repo: "app", OK: true},
+		"ownerOnly":     {Path: "octo"},
+		"empty":         {Path: ""},
+		"emptySegments": {Path: "//app"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			owner, repo, ok := splitCredentialPath(test.Path)
+			if owner != test.Owner || repo != test.Repo || ok != test.OK {
+				t.Errorf("expected (%q, %q, %t), but got (%q, %q, %t)", test.Owner, test.Repo, test.OK, owner, repo, ok)
+			}
+		})
+	}
+}
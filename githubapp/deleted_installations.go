@@ -0,0 +1,120 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultDeletedInstallationTTL = time.Hour
+)
+
+// WithDeletedInstallationDrops makes an asynchronous scheduler silently drop
+// queued dispatches for installations that were deleted. The scheduler marks
+// an installation as deleted when it executes an "installation" event with
+// the "deleted" action or when a handler for the installation returns an
+// error that ClassifyError converts to *InstallationDeletedError. That error
+// is logged at debug level instead of passed to the error callback.
+//
+// Installations stay marked for ttl, which should be longer than dispatches
+// wait in the queue. If ttl is not positive, the scheduler uses
+// DefaultDeletedInstallationTTL.
+func WithDeletedInstallationDrops(ttl time.Duration) SchedulerOption {
+	return func(s *scheduler) {
+		if ttl <= 0 {
+			ttl = DefaultDeletedInstallationTTL
+		}
+		s.deleted = &deletedInstallations{
+			ttl:     ttl,
+			expires: make(map[int64]time.Time),
+		}
+	}
+}
+
+type deletedInstallations struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	expires map[int64]time.Time
+}
+
+// skip returns true if the dispatch is for a deleted installation and should
+// not be executed.
+func (di *deletedInstallations) skip(ctx context.Context, d Dispatch, now time.Time) bool {
+	if di == nil {
+		return false
+	}
+
+	e, err := PeekEnvelopeContext(ctx, d.Payload)
+	if err != nil || e.InstallationID <= 0 {
+		return false
+	}
+	if d.EventType == "installation" {
+		if e.Action == "deleted" {
+			di.mark(e.InstallationID, now)
+		}
+		return false
+	}
+
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	expires, ok := di.expires[e.InstallationID]
+	if !ok {
+		return false
+	}
+	if now.After(expires) {
+		delete(di.expires, e.InstallationID)
+		return false
+	}
+
+	zerolog.Ctx(ctx).Debug().Msg("Dropping event for deleted installation")
+	return true
+}
+
+// handleError marks the installation as deleted if err shows that it was
+// deleted and returns true if the error should not be reported.
+func (di *deletedInstallations) handleError(ctx context.Context, err error, now time.Time) bool {
+	if di == nil || err == nil {
+		return false
+	}
+
+	var deleted *InstallationDeletedError
+	if !errors.As(ClassifyError(err), &deleted) {
+		return false
+	}
+
+	di.mark(deleted.InstallationID, now)
+	zerolog.Ctx(ctx).Debug().Err(err).Msg("Installation was deleted while handling event")
+	return true
+}
+
+func (di *deletedInstallations) mark(installationID int64, now time.Time) {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+
+	for id, expires := range di.expires {
+		if now.After(expires) {
+			delete(di.expires, id)
+		}
+	}
+	di.expires[installationID] = now.Add(di.ttl)
+}
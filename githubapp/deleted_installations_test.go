@@ -0,0 +1,115 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/pkg/errors"
+)
+
+func TestDeletedInstallationDrops(t *testing.T) {
+	deletedErr := func(id int64) error {
+		return errors.Wrap(&ghinstallation.HTTPError{
+			Message:        "received non 2xx response status \"404 Not Found\"",
+			InstallationID: id,
+			Response:       &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}},
+		}, "failed to get pull request")
+	}
+
+	tests := map[string]struct {
+		Dispatches []string
+		Handled    []string
+		Errors     int
+	}{
+		"handlerError": {
+			Dispatches: []string{"pull_request:1:deleted", "pull_request:1", "pull_request:2"},
+			Handled:    []string{"pull_request:1:deleted", "pull_request:2"},
+		},
+		"installationEvent": {
+			Dispatches: []string{"installation:1", "pull_request:1", "issues:1"},
+			Handled:    []string{"installation:1"},
+		},
+		"expired": {
+			Dispatches: []string{"installation:1", "advance", "pull_request:1"},
+			Handled:    []string{"installation:1", "pull_request:1"},
+		},
+		"otherError": {
+			Dispatches: []string{"pull_request:1:error", "pull_request:1"},
+			Handled:    []string{"pull_request:1:error", "pull_request:1"},
+			Errors:     1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var handled []string
+			errCount := 0
+
+			clock := newTestClock()
+			ctx := WithClock(context.Background(), clock)
+			s := scheduler{
+				onError: func(ctx context.Context, d Dispatch, err error) { errCount++ },
+			}
+			WithDeletedInstallationDrops(0)(&s)
+
+			for _, key := range test.Dispatches {
+				if key == "advance" {
+					clock.Advance(DefaultDeletedInstallationTTL + time.Second)
+					continue
+				}
+
+				// keys are "eventType:installationID[:result]"
+				parts := append(strings.Split(key, ":"), "")
+				eventType, result := parts[0], parts[2]
+				id, _ := strconv.ParseInt(parts[1], 10, 64)
+
+				action := "opened"
+				if eventType == "installation" {
+					action = "deleted"
+				}
+
+				s.safeExecute(ctx, Dispatch{
+					Handler: &TestEventHandler{Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+						handled = append(handled, key)
+						switch result {
+						case "deleted":
+							return deletedErr(id)
+						case "error":
+							return errors.New("handler failed")
+						}
+						return nil
+					}},
+					EventType: eventType,
+					Payload:   []byte(fmt.Sprintf(`{"action": %q, "installation": {"id": %d}}`, action, id)),
+				}, nil)
+			}
+
+			if fmt.Sprint(handled) != fmt.Sprint(test.Handled) {
+				t.Errorf("incorrect handled dispatches: expected %v, actual %v", test.Handled, handled)
+			}
+			if errCount != test.Errors {
+				t.Errorf("incorrect number of errors: expected %d, actual %d", test.Errors, errCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+type deliveryKey struct{}
+
+type contextDelivery struct {
+	Delivery
+}
+
+// WithDelivery returns a context that stores d. The event dispatcher does
+// this after validating a request, so response callbacks, event filters,
+// handler wrappers, and handlers can inspect the event without reading or
+// parsing the body again. Dispatch.Execute also stores the delivery if the
+// context does not contain it. Call WithDelivery to test code that uses
+// GetDelivery or to implement custom event dispatchers.
+func WithDelivery(ctx context.Context, d Delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, &contextDelivery{Delivery: d})
+}
+
+// GetDelivery returns the delivery stored in the context and true, or false
+// if the context does not contain a delivery. The payload is shared by all
+// users of the context and must not be modified.
+func GetDelivery(ctx context.Context) (Delivery, bool) {
+	if d, ok := ctx.Value(deliveryKey{}).(*contextDelivery); ok {
+		return d.Delivery, true
+	}
+	return Delivery{}, false
+}
+
+// GetDeliveryEvent returns the delivery stored in the context parsed with
+// ParseEventContext. Each call parses the payload again and returns a new
+// event, so callers may modify the result without affecting other users of
+// the context. It returns an error if the context does not contain a
+// delivery.
+func GetDeliveryEvent(ctx context.Context) (*Event, error) {
+	d, ok := ctx.Value(deliveryKey{}).(*contextDelivery)
+	if !ok {
+		return nil, errors.New("context does not contain a delivery")
+	}
+
+	event, err := ParseEventContext(ctx, d.EventType, d.Payload)
+	if err != nil {
+		return nil, err
+	}
+	event.DeliveryID = d.DeliveryID
+	return event, nil
+}
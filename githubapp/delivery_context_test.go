@@ -0,0 +1,128 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestDeliveryContext(t *testing.T) {
+	t.Run("dispatcher", func(t *testing.T) {
+		var filtered, handled bool
+		h := &TestEventHandler{
+			Types: []string{"issues"},
+			Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+				d, ok := GetDelivery(ctx)
+				if !ok {
+					t.Fatal("handler context does not contain a delivery")
+				}
+				if d.EventType != eventType || d.DeliveryID != deliveryID || string(d.Payload) != string(payload) {
+					t.Errorf("incorrect delivery in handler context: %+v", d)
+				}
+
+				event, err := GetDeliveryEvent(ctx)
+				if err != nil {
+					t.Fatalf("unexpected error parsing event: %v", err)
+				}
+				if _, ok := event.Payload.(*github.IssuesEvent); !ok {
+					t.Errorf("incorrect event type: %T", event.Payload)
+				}
+				if event.DeliveryID != deliveryID {
+					t.Errorf("incorrect delivery ID: %q", event.DeliveryID)
+				}
+				handled = true
+				return nil
+			},
+		}
+
+		d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithEventFilter(func(ctx context.Context, eventType string, payload []byte) bool {
+			_, filtered = GetDelivery(ctx)
+			return true
+		}))
+
+		w := httptest.NewRecorder()
+		d.ServeHTTP(w, newHookRequest("issues", "delivery-id", true))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("incorrect response code: %d", w.Code)
+		}
+		if !filtered {
+			t.Error("filter context does not contain a delivery")
+		}
+		if !handled {
+			t.Error("handler was not called")
+		}
+	})
+
+	t.Run("execute", func(t *testing.T) {
+		var delivery Delivery
+		d := Dispatch{
+			Handler: &TestEventHandler{
+				Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+					delivery, _ = GetDelivery(ctx)
+					return nil
+				},
+			},
+			EventType:  "issues",
+			DeliveryID: "delivery-id",
+			Payload:    []byte(`{}`),
+		}
+
+		if err := d.Execute(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if delivery.EventType != "issues" || delivery.DeliveryID != "delivery-id" {
+			t.Errorf("incorrect delivery in handler context: %+v", delivery)
+		}
+	})
+
+	t.Run("parsedPerCall", func(t *testing.T) {
+		ctx := WithDelivery(context.Background(), Delivery{
+			EventType: "issues",
+			Payload:   []byte(`{"action":"opened"}`),
+		})
+
+		e1, err := GetDeliveryEvent(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error parsing event: %v", err)
+		}
+		e1.Payload.(*github.IssuesEvent).Action = github.String("closed")
+
+		e2, err := GetDeliveryEvent(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error parsing event: %v", err)
+		}
+		if e1 == e2 {
+			t.Error("expected a new event from each call")
+		}
+		if action := e2.Payload.(*github.IssuesEvent).GetAction(); action != "opened" {
+			t.Errorf("incorrect action: %q", action)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, ok := GetDelivery(context.Background()); ok {
+			t.Error("expected no delivery in empty context")
+		}
+		if _, err := GetDeliveryEvent(context.Background()); err == nil {
+			t.Error("expected error for empty context, but got nil")
+		}
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"net/http"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// deliveryHeaders are the request headers that describe a webhook delivery
+var deliveryHeaders = []string{
+	"Content-Type",
+	"User-Agent",
+	"X-GitHub-Event",
+	"X-GitHub-Delivery",
+	"X-GitHub-Hook-ID",
+	"X-GitHub-Hook-Installation-Target-ID",
+	"X-GitHub-Hook-Installation-Target-Type",
+	"X-GitHub-Enterprise-Host",
+	"X-GitHub-Enterprise-Version",
+	github.SHA1SignatureHeader,
+	github.SHA256SignatureHeader,
+}
+
+// DeliveryHeader returns a copy of the headers in h that GitHub sets to
+// describe a webhook delivery, like the event type, the hook ID, and the
+// signatures. Other headers, like those added by proxies, are not copied.
+// It returns nil if h contains none of the headers.
+func DeliveryHeader(h http.Header) http.Header {
+	var out http.Header
+	for _, name := range deliveryHeaders {
+		if values := h.Values(name); len(values) > 0 {
+			if out == nil {
+				out = make(http.Header)
+			}
+			out[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+	return out
+}
+
+// SignatureAlgorithm returns the algorithm of the strongest signature in the
+// delivery's headers, "sha256" or "sha1", or an empty string if the delivery
+// has no headers or was not signed.
+func (d Delivery) SignatureAlgorithm() string {
+	switch {
+	case d.Header.Get(github.SHA256SignatureHeader) != "":
+		return "sha256"
+	case d.Header.Get(github.SHA1SignatureHeader) != "":
+		return "sha1"
+	}
+	return ""
+}
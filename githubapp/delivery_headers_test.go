@@ -0,0 +1,78 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeliveryHeader(t *testing.T) {
+	tests := map[string]struct {
+		Scheduler func() Scheduler
+	}{
+		"sync": {
+			Scheduler: DefaultScheduler,
+		},
+		"async": {
+			Scheduler: func() Scheduler { return QueueAsyncScheduler(1, 1) },
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			deliveries := make(chan Delivery, 1)
+			h := &TestEventHandler{
+				Types: []string{"ping"},
+				Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+					d, _ := GetDelivery(ctx)
+					deliveries <- d
+					return nil
+				},
+			}
+			dispatcher := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithScheduler(test.Scheduler()))
+
+			req := newHookRequest("ping", "1", true)
+			req.Header.Set("X-GitHub-Hook-ID", "123")
+			req.Header.Set("X-Forwarded-For", "10.0.0.1")
+
+			w := httptest.NewRecorder()
+			dispatcher.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("incorrect status: %d", w.Code)
+			}
+
+			var d Delivery
+			select {
+			case d = <-deliveries:
+			case <-time.After(time.Second):
+				t.Fatal("handler was not called")
+			}
+
+			if d.Header.Get("X-GitHub-Hook-ID") != "123" || d.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("missing delivery headers: %v", d.Header)
+			}
+			if d.Header.Get("X-Forwarded-For") != "" {
+				t.Errorf("unexpected proxy header: %v", d.Header)
+			}
+			if alg := d.SignatureAlgorithm(); alg != "sha1" {
+				t.Errorf("incorrect signature algorithm: %q", alg)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+)
+
+const (
+	MetricsKeyDeliveryLatency = "github.event.delivery_latency"
+)
+
+// eventTimestampFields lists the fields that contain the time of the change
+// that triggered each event type, as paths of object keys. Event types that
+// are not listed use the "updated_at" field of the object with the same name
+// as the event type, if it exists.
+var eventTimestampFields = map[string][]string{
+	"issues":                      {"issue", "updated_at"},
+	"issue_comment":               {"comment", "updated_at"},
+	"pull_request_review":         {"review", "submitted_at"},
+	"pull_request_review_comment": {"comment", "updated_at"},
+	"push":                        {"repository", "pushed_at"},
+	"status":                      {"updated_at"},
+}
+
+// EventTimestamp returns the time GitHub recorded the change that triggered
+// an event, based on timestamps in the payload, like the "updated_at" field
+// of the pull request in a "pull_request" event. It returns false if the
+// payload of the event type does not contain a usable timestamp.
+//
+// The timestamp is only an estimate of when GitHub emitted the event: some
+// actions, like re-requesting a check suite, do not update the timestamp, and
+// GitHub may record it slightly before emitting the event.
+func EventTimestamp(eventType string, payload []byte) (time.Time, bool) {
+	path, ok := eventTimestampFields[eventType]
+	if !ok {
+		path = []string{eventType, "updated_at"}
+	}
+
+	var value json.RawMessage = payload
+	for _, key := range path {
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(value, &obj); err != nil {
+			return time.Time{}, false
+		}
+		if value, ok = obj[key]; !ok {
+			return time.Time{}, false
+		}
+	}
+
+	// GitHub reports some timestamps, like "pushed_at" in push events, as
+	// Unix times instead of strings
+	var unix int64
+	if err := json.Unmarshal(value, &unix); err == nil {
+		return time.Unix(unix, 0), unix > 0
+	}
+
+	var t time.Time
+	if err := json.Unmarshal(value, &t); err != nil || t.IsZero() {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// WithDeliveryLatency makes the dispatcher measure the time between GitHub
+// recording the change that triggered an event, as reported by
+// EventTimestamp, and the dispatcher receiving the event. The latency is
+// added to the logger in the handler context and, if the registry is not
+// nil, recorded in a histogram tagged with the event type.
+//
+// This latency covers delays in GitHub and the network. With asynchronous
+// schedulers, the github.event.age metric reported by WithSchedulingMetrics
+// covers local queueing.
+func WithDeliveryLatency(r metrics.Registry) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.latency = &deliveryLatency{registry: r}
+	}
+}
+
+// WithConsumerDeliveryLatency makes the consumer measure delivery latency in
+// the same way as WithDeliveryLatency. For consumers, the latency also
+// includes the time messages spend in the relay and the event source.
+func WithConsumerDeliveryLatency(r metrics.Registry) EventConsumerOption {
+	return func(c *EventConsumer) {
+		c.latency = &deliveryLatency{registry: r}
+	}
+}
+
+type deliveryLatency struct {
+	registry metrics.Registry
+}
+
+// record measures the latency of an event and returns a context with the
+// latency added to the logger.
+func (dl *deliveryLatency) record(ctx context.Context, eventType string, payload []byte) context.Context {
+	if dl == nil {
+		return ctx
+	}
+
+	t, ok := EventTimestamp(eventType, payload)
+	if !ok {
+		return ctx
+	}
+	latency := max(time.Since(t), 0)
+
+	if dl.registry != nil {
+		key := fmt.Sprintf("%s[event:%s]", MetricsKeyDeliveryLatency, eventType)
+		metrics.GetOrRegisterHistogram(key, dl.registry, metrics.NewExpDecaySample(histogramReservoirSize, histogramAlpha)).Update(latency.Milliseconds())
+	}
+
+	logger := zerolog.Ctx(ctx).With().Dur(LogKeyDeliveryLatency, latency).Logger()
+	return logger.WithContext(ctx)
+}
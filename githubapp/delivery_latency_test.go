@@ -0,0 +1,125 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+)
+
+func TestEventTimestamp(t *testing.T) {
+	expected := time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	tests := map[string]struct {
+		EventType string
+		Payload   string
+		OK        bool
+	}{
+		"pullRequest": {
+			EventType: "pull_request",
+			Payload:   `{"action": "opened", "pull_request": {"updated_at": "2026-03-14T15:09:26Z"}}`,
+			OK:        true,
+		},
+		"issueComment": {
+			EventType: "issue_comment",
+			Payload:   `{"issue": {"updated_at": "2020-01-01T00:00:00Z"}, "comment": {"updated_at": "2026-03-14T15:09:26Z"}}`,
+			OK:        true,
+		},
+		"pullRequestReview": {
+			EventType: "pull_request_review",
+			Payload:   `{"review": {"submitted_at": "2026-03-14T15:09:26Z"}}`,
+			OK:        true,
+		},
+		"pushUnixTime": {
+			EventType: "push",
+			Payload:   fmt.Sprintf(`{"repository": {"pushed_at": %d}}`, expected.Unix()),
+			OK:        true,
+		},
+		"status": {
+			EventType: "status",
+			Payload:   `{"state": "success", "updated_at": "2026-03-14T15:09:26Z"}`,
+			OK:        true,
+		},
+		"missingField": {
+			EventType: "pull_request",
+			Payload:   `{"action": "opened", "pull_request": {"number": 1}}`,
+		},
+		"nullField": {
+			EventType: "check_run",
+			Payload:   `{"check_run": {"updated_at": null}}`,
+		},
+		"unknownEvent": {
+			EventType: "ping",
+			Payload:   `{"zen": "Keep it logically awesome."}`,
+		},
+		"invalidPayload": {
+			EventType: "issues",
+			Payload:   `{"issue": "not an object"}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts, ok := EventTimestamp(test.EventType, []byte(test.Payload))
+			if ok != test.OK {
+				t.Fatalf("incorrect ok value: expected %t, actual %t", test.OK, ok)
+			}
+			if ok && !ts.Equal(expected) {
+				t.Errorf("incorrect timestamp: expected %s, actual %s", expected, ts)
+			}
+		})
+	}
+}
+
+func TestDeliveryLatency(t *testing.T) {
+	registry := metrics.NewRegistry()
+	dl := &deliveryLatency{registry: registry}
+
+	var out bytes.Buffer
+	ctx := zerolog.New(&out).WithContext(context.Background())
+
+	updated := time.Now().Add(-5 * time.Second).UTC().Format(time.RFC3339)
+	payload := fmt.Sprintf(`{"pull_request": {"updated_at": %q}}`, updated)
+
+	ctx = dl.record(ctx, "pull_request", []byte(payload))
+	zerolog.Ctx(ctx).Info().Msg("handled")
+
+	if !bytes.Contains(out.Bytes(), []byte(LogKeyDeliveryLatency)) {
+		t.Errorf("log output does not contain delivery latency: %s", out.String())
+	}
+
+	h, ok := registry.Get(MetricsKeyDeliveryLatency + "[event:pull_request]").(metrics.Histogram)
+	if !ok {
+		t.Fatal("delivery latency histogram was not registered")
+	}
+	if h.Count() != 1 {
+		t.Errorf("incorrect histogram count: expected 1, actual %d", h.Count())
+	}
+	if ms := h.Min(); ms < 4000 {
+		t.Errorf("incorrect latency: expected at least 4000ms, actual %dms", ms)
+	}
+
+	// events without a timestamp are not measured
+	dl.record(ctx, "ping", []byte(`{}`))
+	if n := len(registry.GetAll()); n != 1 {
+		t.Errorf("incorrect number of metrics: expected 1, actual %d", n)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// WithDetachedContext detaches the contexts passed to schedulers from the
+// lifetime of webhook requests. By default, the context of a synchronous
+// handler is canceled when GitHub closes the connection, which happens if
+// the handler takes longer than GitHub's timeout for webhook deliveries. With
+// this option, handlers keep running after the connection closes and their
+// contexts are only canceled when shutdown is done, like a context canceled
+// when the server stops. If shutdown is nil, contexts are never canceled.
+//
+// Detached contexts keep all values of the request context, like the logger
+// and the delivery. Asynchronous schedulers already create new contexts, so
+// the option has no effect with them.
+func WithDetachedContext(shutdown context.Context) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.detach = &detachedContext{shutdown: shutdown}
+	}
+}
+
+type detachedContext struct {
+	shutdown context.Context
+}
+
+// context returns a context with the values of ctx that is canceled when the
+// shutdown context is done or when the returned function is called.
+func (dc *detachedContext) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if dc == nil {
+		return ctx, func() {}
+	}
+
+	logClosed := context.AfterFunc(ctx, func() {
+		zerolog.Ctx(ctx).Debug().Msg("Connection closed before the handler finished, continuing with detached context")
+	})
+
+	detached, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	stopShutdown := func() bool { return false }
+	if dc.shutdown != nil {
+		stopShutdown = context.AfterFunc(dc.shutdown, func() {
+			cancel(context.Cause(dc.shutdown))
+		})
+	}
+
+	return detached, func() {
+		logClosed()
+		stopShutdown()
+		cancel(context.Canceled)
+	}
+}
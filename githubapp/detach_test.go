@@ -0,0 +1,94 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestDetachedContext(t *testing.T) {
+	tests := map[string]struct {
+		Detach   bool
+		Shutdown bool
+
+		Err error
+	}{
+		"attached": {
+			Err: context.Canceled,
+		},
+		"detached": {
+			Detach: true,
+		},
+		"detachedShutdown": {
+			Detach:   true,
+			Shutdown: true,
+			Err:      errShutdown,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			shutdown, stop := context.WithCancelCause(context.Background())
+			defer stop(nil)
+
+			req := newHookRequest("pull_request", "1", true)
+			reqCtx, closeConn := context.WithCancel(req.Context())
+			defer closeConn()
+			req = req.WithContext(reqCtx)
+
+			var handlerErr error
+			h := &TestEventHandler{
+				Types: []string{"pull_request"},
+				Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+					// simulate GitHub closing the connection and the server stopping
+					closeConn()
+					if test.Shutdown {
+						stop(errShutdown)
+						<-ctx.Done()
+					}
+
+					handlerErr = context.Cause(ctx)
+					if _, ok := GetDelivery(ctx); !ok {
+						t.Error("expected delivery in handler context")
+					}
+					return nil
+				},
+			}
+
+			var opts []DispatcherOption
+			if test.Detach {
+				opts = append(opts, WithDetachedContext(shutdown))
+			}
+			d := NewEventDispatcher([]EventHandler{h}, testHookSecret, opts...)
+
+			w := httptest.NewRecorder()
+			d.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Errorf("incorrect status: %d", w.Code)
+			}
+			if !errors.Is(handlerErr, test.Err) {
+				t.Errorf("incorrect handler context error: expected %v, actual %v", test.Err, handlerErr)
+			}
+		})
+	}
+}
+
+var errShutdown = errors.New("shutdown")
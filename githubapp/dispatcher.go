@@ -18,8 +18,8 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 
-	"github.com/google/go-github/v66/github"
 	"github.com/pkg/errors"
 	"github.com/rcrowley/go-metrics"
 	"github.com/rs/zerolog"
@@ -106,13 +106,42 @@ type eventDispatcher struct {
 	scheduler  Scheduler
 	onError    ErrorCallback
 	onResponse ResponseCallback
+	archive    BlobSink
+	filter     EventFilter
+	admission  *AdmissionPolicy
+	allowlist  *HookAllowlist
+	pingCheck  bool
+	pingAppID  int64
+	shedding   *loadShedding
+	unhandled  unhandledEvents
+	detach     *detachedContext
+	unmarshal  JSONUnmarshalFunc
+
+	payloadValidation *payloadValidation
+	latency           *deliveryLatency
+	eventMetrics      *eventMetrics
+
+	maxPayloadSize int64
 }
 
 // NewDefaultEventDispatcher is a convenience method to create an event
 // dispatcher from configuration using the default error and response
-// callbacks.
+// callbacks. The dispatcher checks the configuration of ping events against
+// the application. Use NewDefaultEventDispatcherWithOptions to customize the
+// dispatcher.
 func NewDefaultEventDispatcher(c Config, handlers ...EventHandler) http.Handler {
-	return NewEventDispatcher(handlers, c.App.WebhookSecret)
+	return NewDefaultEventDispatcherWithOptions(c, handlers)
+}
+
+// NewDefaultEventDispatcherWithOptions is like NewDefaultEventDispatcher, but
+// accepts options that customize the dispatcher in the same way as
+// NewEventDispatcher. The dispatcher validates payloads with the configured
+// webhook secret and checks the configuration of ping events against the
+// application. If the options do not set callbacks, the dispatcher uses the
+// default error and response callbacks.
+func NewDefaultEventDispatcherWithOptions(c Config, handlers []EventHandler, opts ...DispatcherOption) http.Handler {
+	opts = append([]DispatcherOption{WithPingCheck(c.App.IntegrationID)}, opts...)
+	return NewEventDispatcher(handlers, c.App.WebhookSecret, opts...)
 }
 
 // NewEventDispatcher creates an http.Handler that dispatches GitHub webhook
@@ -122,21 +151,14 @@ func NewDefaultEventDispatcher(c Config, handlers ...EventHandler) http.Handler
 // Responses are controlled by optional error and response callbacks. If these
 // options are not provided, default callbacks are used.
 func NewEventDispatcher(handlers []EventHandler, secret string, opts ...DispatcherOption) http.Handler {
-	handlerMap := make(map[string]EventHandler)
-
-	// Iterate in reverse so the first entries in the slice have priority
-	for i := len(handlers) - 1; i >= 0; i-- {
-		for _, event := range handlers[i].Handles() {
-			handlerMap[event] = handlers[i]
-		}
-	}
-
 	d := &eventDispatcher{
-		handlerMap: handlerMap,
+		handlerMap: newHandlerMap(handlers),
 		secret:     secret,
 		scheduler:  DefaultScheduler(),
 		onError:    DefaultErrorCallback,
 		onResponse: DefaultResponseCallback,
+
+		maxPayloadSize: DefaultMaxPayloadSize,
 	}
 
 	for _, opt := range opts {
@@ -146,12 +168,31 @@ func NewEventDispatcher(handlers []EventHandler, secret string, opts ...Dispatch
 	return d
 }
 
+// newHandlerMap returns a map from event type to the handler for that type.
+// If several handlers handle the same type, the first one in the slice is
+// used.
+func newHandlerMap(handlers []EventHandler) map[string]EventHandler {
+	handlerMap := make(map[string]EventHandler)
+
+	// Iterate in reverse so the first entries in the slice have priority
+	for i := len(handlers) - 1; i >= 0; i-- {
+		for _, event := range handlers[i].Handles() {
+			handlerMap[event] = handlers[i]
+		}
+	}
+	return handlerMap
+}
+
 // ServeHTTP processes a webhook request from GitHub.
 func (d *eventDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// initialize context for SetResponder/GetResponder
+	// initialize context for SetResponder/GetResponder and RateLimits
 	ctx = InitializeResponder(ctx)
+	ctx = InitializeRateLimits(ctx)
+	if d.unmarshal != nil {
+		ctx = WithJSONUnmarshal(ctx, d.unmarshal)
+	}
 	r = r.WithContext(ctx)
 
 	eventType := r.Header.Get("X-GitHub-Event")
@@ -166,16 +207,46 @@ func (d *eventDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logger := zerolog.Ctx(ctx).With().
+	replay := IsReplay(ctx)
+
+	logCtx := zerolog.Ctx(ctx).With().
 		Str(LogKeyEventType, eventType).
-		Str(LogKeyDeliveryID, deliveryID).
-		Logger()
+		Str(LogKeyDeliveryID, deliveryID)
+	if replay {
+		logCtx = logCtx.Bool(LogKeyReplay, true)
+	}
+	logger := logCtx.Logger()
 
 	// initialize context with event logger
 	ctx = logger.WithContext(ctx)
 	r = r.WithContext(ctx)
 
-	payloadBytes, err := github.ValidatePayload(r, []byte(d.secret))
+	// replays come from operators, not GitHub, but are signed with the secret
+	if d.allowlist != nil && !replay {
+		allowed, err := d.allowlist.Allowed(r)
+		if err == nil && !allowed {
+			err = errors.New("request source is not in the GitHub hook IP ranges")
+		}
+		if err != nil {
+			d.onError(w, r, ValidationError{
+				EventType:  eventType,
+				DeliveryID: deliveryID,
+				Cause:      err,
+			})
+			return
+		}
+	}
+
+	if _, ok := d.handlerMap[eventType]; ok && d.shedding.saturated(d.scheduler) {
+		d.shedding.setRetryAfter(w)
+		d.onError(w, r, ErrCapacityExceeded)
+		return
+	}
+
+	payloadBytes, err := validatePayload(r, []byte(d.secret), d.maxPayloadSize)
+	if err == nil {
+		err = d.payloadValidation.check(ctx, eventType, payloadBytes)
+	}
 	if err != nil {
 		d.onError(w, r, ValidationError{
 			EventType:  eventType,
@@ -185,16 +256,78 @@ func (d *eventDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !replay {
+		ctx = d.latency.record(ctx, eventType, payloadBytes)
+		logger = *zerolog.Ctx(ctx)
+	}
+
 	logger.Debug().Msgf("Received webhook event")
+	d.eventMetrics.record(ctx, eventType, payloadBytes)
+
+	// store the validated delivery for callbacks, filters, and handlers
+	header := DeliveryHeader(r.Header)
+	ctx = WithDelivery(ctx, Delivery{
+		EventType:  eventType,
+		DeliveryID: deliveryID,
+		Payload:    payloadBytes,
+		Header:     header,
+	})
+	r = r.WithContext(ctx)
+
+	if d.admission != nil {
+		e, err := PeekEnvelopeContext(ctx, payloadBytes)
+		if err != nil {
+			// the policy cannot be checked, so reject the event instead of
+			// admitting it by default
+			d.onError(w, r, ValidationError{
+				EventType:  eventType,
+				DeliveryID: deliveryID,
+				Cause:      errors.Wrap(err, "failed to read payload for the admission policy"),
+			})
+			return
+		}
+		if !d.admission.Admits(e) {
+			logger.Info().Int64(LogKeyInstallationID, e.InstallationID).Msg("Ignoring event rejected by the admission policy")
+			d.onResponse(w, r, eventType, false)
+			return
+		}
+	}
+
+	if d.archive != nil && !replay {
+		if err := archiveDelivery(ctx, d.archive, r, eventType, deliveryID, payloadBytes); err != nil {
+			logger.Warn().Err(err).Msg("Failed to archive webhook delivery")
+		}
+	}
+
+	if eventType == "ping" && d.pingCheck {
+		d.checkPing(ctx, payloadBytes)
+	}
 
 	handler, ok := d.handlerMap[eventType]
+	if !ok && eventType != "ping" {
+		d.unhandled.record(ctx, eventType)
+		if d.unhandled.status != 0 {
+			w.WriteHeader(d.unhandled.status)
+			return
+		}
+	}
+	if ok && d.filter != nil {
+		ok = d.filter(ctx, eventType, payloadBytes)
+	}
 	if ok {
-		if err := d.scheduler.Schedule(ctx, Dispatch{
+		dispatchCtx, cancel := d.detach.context(ctx)
+		defer cancel()
+
+		if err := d.scheduler.Schedule(dispatchCtx, Dispatch{
 			Handler:    handler,
 			EventType:  eventType,
 			DeliveryID: deliveryID,
 			Payload:    payloadBytes,
+			Header:     header,
 		}); err != nil {
+			if errors.Is(err, ErrCapacityExceeded) {
+				d.shedding.setRetryAfter(w)
+			}
 			d.onError(w, r, err)
 			return
 		}
@@ -226,6 +359,11 @@ func MetricsErrorCallback(reg metrics.Registry) ErrorCallback {
 			http.Error(w, "No capacity available to processes this event", http.StatusServiceUnavailable)
 			return
 		}
+		if errors.Is(err, ErrSchedulerDraining) {
+			logger.Warn().Msg("Rejecting webhook event because the scheduler is draining")
+			http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
 
 		logger.Error().Err(err).Msg("Unexpected error handling webhook")
 		errorCounter(reg, r.Header.Get("X-Github-Event")).Inc(1)
@@ -253,36 +391,88 @@ func DefaultResponseCallback(w http.ResponseWriter, r *http.Request, event strin
 
 type responderKey struct{}
 
+// ErrAsyncResponder is returned by TrySetResponder when the event is handled
+// asynchronously. The dispatcher responds to GitHub before these handlers
+// run, so there is no response to customize.
+var ErrAsyncResponder = errors.New("responder: event is handled asynchronously and the response was already sent")
+
+type responderState struct {
+	mu        sync.Mutex
+	responder func(http.ResponseWriter, *http.Request)
+	async     bool
+}
+
 // InitializeResponder prepares the context to work with SetResponder and
 // GetResponder. It is used to test handlers that call SetResponder or to
 // implement custom event dispatchers that support responders.
 func InitializeResponder(ctx context.Context) context.Context {
-	var responder func(http.ResponseWriter, *http.Request)
-	return context.WithValue(ctx, responderKey{}, &responder)
+	return context.WithValue(ctx, responderKey{}, &responderState{})
+}
+
+// InitializeAsyncResponder prepares the context for handlers that run after
+// the response to GitHub was sent, like handlers run by asynchronous
+// schedulers. In these contexts, TrySetResponder returns ErrAsyncResponder
+// and SetResponder logs a warning and ignores the responder. Custom
+// ContextDeriver implementations should use this instead of
+// InitializeResponder.
+func InitializeAsyncResponder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, responderKey{}, &responderState{async: true})
 }
 
 // SetResponder sets a function that sends a response to GitHub after event
 // processing completes. The context must be initialized by InitializeResponder.
 // The event dispatcher does this automatically before calling a handler.
 //
+// If the event is handled asynchronously, the response was already sent and
+// SetResponder logs a warning instead of setting the responder. Use
+// TrySetResponder to detect this case.
+//
 // Customizing individual handler responses should be rare. Applications that
 // want to modify the standard responses should consider registering a response
 // callback before using this function.
 func SetResponder(ctx context.Context, responder func(http.ResponseWriter, *http.Request)) {
-	r, ok := ctx.Value(responderKey{}).(*func(http.ResponseWriter, *http.Request))
+	r, ok := ctx.Value(responderKey{}).(*responderState)
 	if !ok || r == nil {
 		panic("SetResponder() must be called with an initialized context, such as one from the event dispatcher")
 	}
-	*r = responder
+	if err := r.set(responder); err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Ignoring responder set by handler")
+	}
+}
+
+// TrySetResponder is like SetResponder, but returns an error instead of
+// panicking if the context is not initialized and returns ErrAsyncResponder
+// if the event is handled asynchronously. It is safe to call from multiple
+// goroutines; the last responder set is used.
+func TrySetResponder(ctx context.Context, responder func(http.ResponseWriter, *http.Request)) error {
+	r, ok := ctx.Value(responderKey{}).(*responderState)
+	if !ok || r == nil {
+		return errors.New("responder: context is not initialized")
+	}
+	return r.set(responder)
+}
+
+func (r *responderState) set(responder func(http.ResponseWriter, *http.Request)) error {
+	if r.async {
+		return ErrAsyncResponder
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responder = responder
+	return nil
 }
 
 // GetResponder returns the response function that was set by an event handler.
 // If no response function exists, it returns nil. There is usually no reason
 // to call this outside of a response callback implementation.
 func GetResponder(ctx context.Context) func(http.ResponseWriter, *http.Request) {
-	r, ok := ctx.Value(responderKey{}).(*func(http.ResponseWriter, *http.Request))
+	r, ok := ctx.Value(responderKey{}).(*responderState)
 	if !ok || r == nil {
 		return nil
 	}
-	return *r
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.responder
 }
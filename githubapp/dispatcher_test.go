@@ -139,6 +139,26 @@ func TestEventDispatcher(t *testing.T) {
 			ResponseCode: 404,
 			ResponseBody: "No handler for the issue_comment event!\n",
 		},
+		"unhandledEventStatus": {
+			Handler: TestEventHandler{
+				Types: []string{"pull_request"},
+			},
+			Options: []DispatcherOption{
+				WithUnhandledEventStatus(http.StatusNotFound),
+			},
+			Event:        "issue_comment",
+			ResponseCode: 404,
+		},
+		"unhandledEventStatusIgnoresPing": {
+			Handler: TestEventHandler{
+				Types: []string{"pull_request"},
+			},
+			Options: []DispatcherOption{
+				WithUnhandledEventStatus(http.StatusNoContent),
+			},
+			Event:        "ping",
+			ResponseCode: 200,
+		},
 		"callsHandlerResponder": {
 			Handler: TestEventHandler{
 				Types: []string{"pull_request"},
@@ -178,6 +198,53 @@ func TestEventDispatcher(t *testing.T) {
 	}
 }
 
+func TestNewDefaultEventDispatcher(t *testing.T) {
+	var c Config
+	c.App.WebhookSecret = testHookSecret
+
+	h := &TestEventHandler{Types: []string{"pull_request"}}
+	d := NewDefaultEventDispatcher(c, h)
+
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, newHookRequest("pull_request", "delivery-id", true))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("incorrect response code: expected %d, actual %d", http.StatusOK, w.Code)
+	}
+	if h.Count != 1 {
+		t.Errorf("incorrect handler call count: expected 1, actual %d", h.Count)
+	}
+}
+
+func TestNewDefaultEventDispatcherWithOptions(t *testing.T) {
+	var c Config
+	c.App.WebhookSecret = testHookSecret
+
+	h := &TestEventHandler{Types: []string{"pull_request"}}
+	d := NewDefaultEventDispatcherWithOptions(c, []EventHandler{h},
+		WithResponseCallback(func(w http.ResponseWriter, r *http.Request, event string, handled bool) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, newHookRequest("pull_request", "delivery-id", true))
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("incorrect response code: expected %d, actual %d", http.StatusNoContent, w.Code)
+	}
+	if h.Count != 1 {
+		t.Errorf("incorrect handler call count: expected 1, actual %d", h.Count)
+	}
+
+	w = httptest.NewRecorder()
+	d.ServeHTTP(w, newHookRequest("pull_request", "delivery-id", false))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("incorrect response code for unsigned request: expected %d, actual %d", http.StatusBadRequest, w.Code)
+	}
+}
+
 func TestSetAndGetResponder(t *testing.T) {
 	t.Run("setPanicsOutsideOfDispatcher", func(t *testing.T) {
 		defer func() {
@@ -189,6 +256,36 @@ func TestSetAndGetResponder(t *testing.T) {
 		ctx := context.Background()
 		SetResponder(ctx, func(w http.ResponseWriter, r *http.Request) {})
 	})
+
+	t.Run("trySetReturnsErrorOutsideOfDispatcher", func(t *testing.T) {
+		if err := TrySetResponder(context.Background(), func(w http.ResponseWriter, r *http.Request) {}); err == nil {
+			t.Error("expected TrySetResponder to return an error, but it did not")
+		}
+	})
+
+	t.Run("ignoredInAsyncContext", func(t *testing.T) {
+		ctx := DefaultContextDeriver(context.Background())
+
+		if err := TrySetResponder(ctx, func(w http.ResponseWriter, r *http.Request) {}); err != ErrAsyncResponder {
+			t.Errorf("expected ErrAsyncResponder, but got: %v", err)
+		}
+
+		SetResponder(ctx, func(w http.ResponseWriter, r *http.Request) {})
+		if GetResponder(ctx) != nil {
+			t.Error("expected responder to be ignored in async context")
+		}
+	})
+
+	t.Run("setInSyncContext", func(t *testing.T) {
+		ctx := InitializeResponder(context.Background())
+
+		if err := TrySetResponder(ctx, func(w http.ResponseWriter, r *http.Request) {}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if GetResponder(ctx) == nil {
+			t.Error("expected responder to be set")
+		}
+	})
 }
 
 func newHookRequest(eventType, id string, signed bool) *http.Request {
@@ -0,0 +1,173 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	numericSegment = regexp.MustCompile(`^\d+$`)
+	shaSegment     = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// endpointParams maps path segments to the name of the parameter in the
+// following segment
+var endpointParams = map[string]string{
+	"artifacts":     "{artifact_id}",
+	"branches":      "{branch}",
+	"check-runs":    "{check_run_id}",
+	"check-suites":  "{check_suite_id}",
+	"collaborators": "{username}",
+	"comments":      "{comment_id}",
+	"commits":       "{ref}",
+	"compare":       "{basehead}",
+	"deliveries":    "{delivery_id}",
+	"environments":  "{environment_name}",
+	"hooks":         "{hook_id}",
+	"installations": "{installation_id}",
+	"issues":        "{number}",
+	"jobs":          "{job_id}",
+	"labels":        "{name}",
+	"members":       "{username}",
+	"memberships":   "{username}",
+	"milestones":    "{number}",
+	"orgs":          "{org}",
+	"pulls":         "{number}",
+	"releases":      "{release_id}",
+	"reviews":       "{review_id}",
+	"runs":          "{run_id}",
+	"secrets":       "{secret_name}",
+	"statuses":      "{ref}",
+	"teams":         "{team_slug}",
+	"users":         "{username}",
+	"variables":     "{name}",
+	"workflows":     "{workflow_id}",
+}
+
+// endpointRestParams maps path segments to the name of a parameter that
+// includes all following segments
+var endpointRestParams = map[string]string{
+	"contents":      "{path}",
+	"matching-refs": "{ref}",
+	"readme":        "{dir}",
+	"ref":           "{ref}",
+	"refs":          "{ref}",
+	"tarball":       "{ref}",
+	"zipball":       "{ref}",
+}
+
+// endpointSegments contains the fixed segments of API paths that are not in
+// endpointParams or endpointRestParams. Other segments are replaced by
+// "{param}".
+var endpointSegments = toSet([]string{
+	"access_tokens", "actions", "alerts", "annotations", "app", "app-manifests",
+	"approve", "apps", "archive", "assets", "assignees", "attempts", "audit-log",
+	"autolinks", "billing", "blobs", "blocks", "cache", "caches", "cancel",
+	"cards", "code", "code-scanning", "codeowners", "codespaces", "collaborators",
+	"columns", "community", "config", "contributors", "conversions", "copilot",
+	"custom-properties", "dependabot", "dependency-graph",
+	"deployment-branch-policies", "deployment_protection_rules", "deployments",
+	"dismissals", "dispatches", "emails", "emojis", "enable", "errors", "feeds",
+	"followers", "following", "force-cancel", "forks", "generate-notes", "gists",
+	"git", "gitignore", "graphql", "hook", "installation", "invitations", "keys",
+	"languages", "latest", "licenses", "lock", "logs", "markdown",
+	"marketplace_listing", "merge", "merge-upstream", "merges", "meta",
+	"notifications", "octocat", "organizations", "outside_collaborators", "pages",
+	"pending_deployments", "permission", "permissions", "pings", "preferences",
+	"projects", "properties", "public-key", "public_members", "rate_limit",
+	"registration-token", "releases", "remove-token", "rename", "replies", "repos",
+	"repositories", "required_pull_request_reviews", "required_signatures",
+	"required_status_checks", "rerequest", "rerun", "rerun-failed-jobs",
+	"restrictions", "rules", "rulesets", "runners", "sbom", "search",
+	"secret-scanning", "settings", "snapshots", "stargazers", "starred", "status",
+	"sub_issues", "subscribers", "subscription", "suspended", "tags", "templates",
+	"tests", "timing", "topics", "traffic", "trees", "usage", "user", "values",
+	"versions", "vulnerability-alerts", "watchers", "zen",
+})
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+// EndpointTemplate returns the route template for a GitHub API path by
+// replacing parameter values with names, like "/repos/{owner}/{repo}/issues/{number}"
+// for "/repos/palantir/go-githubapp/issues/1". The "/api/v3" prefix used by
+// GitHub Enterprise Server is removed. Templates are useful as low-cardinality
+// labels for metrics.
+//
+// The conversion is based on common API patterns and is not exact for all
+// endpoints, but every numeric ID and commit SHA is replaced, and segments
+// that are not known parts of API paths are replaced by "{param}", so the
+// number of distinct templates stays small and templates never contain
+// names from request paths.
+func EndpointTemplate(path string) string {
+	path = strings.TrimPrefix(path, "/api/v3")
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return "/"
+	}
+
+	for i := 0; i < len(segments); i++ {
+		s := segments[i]
+		switch {
+		case s == "repos" && i+2 < len(segments):
+			segments[i+1] = "{owner}"
+			segments[i+2] = "{repo}"
+			i += 2
+
+		case endpointRestParams[s] != "" && i+1 < len(segments):
+			segments = append(segments[:i+1], endpointRestParams[s])
+			i = len(segments)
+
+		case endpointParams[s] != "" && i+1 < len(segments):
+			if next := segments[i+1]; !isEndpointKeyword(next) {
+				segments[i+1] = endpointParams[s]
+				i++
+			}
+
+		case numericSegment.MatchString(s):
+			segments[i] = "{id}"
+
+		case shaSegment.MatchString(s):
+			segments[i] = "{sha}"
+
+		case !isEndpointSegment(s):
+			segments[i] = "{param}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// isEndpointSegment returns true if s is a fixed segment of API paths.
+func isEndpointSegment(s string) bool {
+	return endpointSegments[s] || endpointParams[s] != "" || endpointRestParams[s] != "" || isEndpointKeyword(s)
+}
+
+// isEndpointKeyword returns true for segments that are part of an endpoint
+// path instead of a parameter value, like "comments" in "/issues/comments".
+func isEndpointKeyword(s string) bool {
+	switch s {
+	case "comments", "events", "reactions", "timeline", "files", "requested_reviewers", "protection":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"testing"
+)
+
+func TestEndpointTemplate(t *testing.T) {
+	tests := map[string]string{
+		"/":                                      "/",
+		"/app":                                   "/app",
+		"/app/installations/123/access_tokens":   "/app/installations/{installation_id}/access_tokens",
+		"/repos/palantir/go-githubapp":           "/repos/{owner}/{repo}",
+		"/repos/palantir/go-githubapp/issues/12": "/repos/{owner}/{repo}/issues/{number}",
+		"/repos/palantir/go-githubapp/pulls/12/files":                                     "/repos/{owner}/{repo}/pulls/{number}/files",
+		"/repos/palantir/go-githubapp/issues/comments/99/reactions":                       "/repos/{owner}/{repo}/issues/comments/{comment_id}/reactions",
+		"/repos/palantir/go-githubapp/contents/docs/README.md":                            "/repos/{owner}/{repo}/contents/{path}",
+		"/repos/palantir/go-githubapp/git/refs/heads/develop":                             "/repos/{owner}/{repo}/git/refs/{ref}",
+		"/repos/palantir/go-githubapp/git/blobs/0123456789abcdef0123456789abcdef01234567": "/repos/{owner}/{repo}/git/blobs/{sha}",
+		"/repos/palantir/go-githubapp/commits/develop/check-runs":                         "/repos/{owner}/{repo}/commits/{ref}/check-runs",
+		"/orgs/palantir/members/alice":                                                    "/orgs/{org}/members/{username}",
+		"/api/v3/repos/palantir/go-githubapp/labels/bug":                                  "/repos/{owner}/{repo}/labels/{name}",
+		"/search/issues":        "/search/issues",
+		"/user/repos":           "/user/repos",
+		"/unknown/123/ok":       "/{param}/{id}/{param}",
+		"/repos/a/b/pulls/x/y":  "/repos/{owner}/{repo}/pulls/{number}/{param}",
+		"/gists/secret-gist-id": "/gists/{param}",
+	}
+
+	for path, expected := range tests {
+		t.Run(path, func(t *testing.T) {
+			if actual := EndpointTemplate(path); actual != expected {
+				t.Errorf("incorrect template: expected %q, actual %q", expected, actual)
+			}
+		})
+	}
+}
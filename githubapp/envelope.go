@@ -0,0 +1,356 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Envelope contains the fields common to most webhook payloads.
+type Envelope struct {
+	Action             string
+	InstallationID     int64
+	InstallationAppID  int64
+	RepositoryFullName string
+	OrganizationLogin  string
+	EnterpriseSlug     string
+	SenderLogin        string
+}
+
+// PeekEnvelope extracts the action, installation, repository name,
+// organization, enterprise, and sender from a webhook payload without
+// unmarshaling the rest of the payload. It scans the payload in a single
+// pass and does not allocate for skipped values, so it is much cheaper than
+// parsing the full event for large payloads, like "push" events with many
+// commits. It is intended for filtering and routing decisions and only
+// validates the parts of the payload that it reads. Missing fields are left
+// empty.
+func PeekEnvelope(payload []byte) (Envelope, error) {
+	var e Envelope
+
+	s := envelopeScanner{data: payload}
+	err := s.object(func(key []byte) (bool, error) {
+		switch string(key) {
+		case "action":
+			v, err := s.string()
+			e.Action = v
+			return true, err
+		case "installation":
+			return true, s.object(func(key []byte) (bool, error) {
+				var err error
+				switch string(key) {
+				case "id":
+					e.InstallationID, err = s.int()
+				case "app_id":
+					e.InstallationAppID, err = s.int()
+				default:
+					return false, nil
+				}
+				return true, err
+			})
+		case "repository":
+			return true, s.object(func(key []byte) (bool, error) {
+				if string(key) != "full_name" {
+					return false, nil
+				}
+				v, err := s.string()
+				e.RepositoryFullName = v
+				return true, err
+			})
+		case "organization":
+			return true, s.object(func(key []byte) (bool, error) {
+				if string(key) != "login" {
+					return false, nil
+				}
+				v, err := s.string()
+				e.OrganizationLogin = v
+				return true, err
+			})
+		case "enterprise":
+			return true, s.object(func(key []byte) (bool, error) {
+				if string(key) != "slug" {
+					return false, nil
+				}
+				v, err := s.string()
+				e.EnterpriseSlug = v
+				return true, err
+			})
+		case "sender":
+			return true, s.object(func(key []byte) (bool, error) {
+				if string(key) != "login" {
+					return false, nil
+				}
+				v, err := s.string()
+				e.SenderLogin = v
+				return true, err
+			})
+		}
+		return false, nil
+	})
+	if err != nil {
+		return e, errors.Wrap(err, "invalid payload")
+	}
+	return e, nil
+}
+
+// PeekEnvelopeContext is like PeekEnvelope, but if the context contains a
+// function set by WithJSONUnmarshal, it decodes the fields with that
+// function instead of scanning the payload.
+func PeekEnvelopeContext(ctx context.Context, payload []byte) (Envelope, error) {
+	return peekEnvelope(contextJSONUnmarshal(ctx), payload)
+}
+
+// envelopeFields are the fields of a payload read by PeekEnvelope
+type envelopeFields struct {
+	Action       string `json:"action"`
+	Installation struct {
+		ID    int64 `json:"id"`
+		AppID int64 `json:"app_id"`
+	} `json:"installation"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Organization struct {
+		Login string `json:"login"`
+	} `json:"organization"`
+	Enterprise struct {
+		Slug string `json:"slug"`
+	} `json:"enterprise"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// peekEnvelope decodes the envelope with unmarshal, or with PeekEnvelope if
+// unmarshal is nil.
+func peekEnvelope(unmarshal JSONUnmarshalFunc, payload []byte) (Envelope, error) {
+	if unmarshal == nil {
+		return PeekEnvelope(payload)
+	}
+
+	var f envelopeFields
+	if err := unmarshal(payload, &f); err != nil {
+		return Envelope{}, errors.Wrap(err, "invalid payload")
+	}
+	return Envelope{
+		Action:             f.Action,
+		InstallationID:     f.Installation.ID,
+		InstallationAppID:  f.Installation.AppID,
+		RepositoryFullName: f.Repository.FullName,
+		OrganizationLogin:  f.Organization.Login,
+		EnterpriseSlug:     f.Enterprise.Slug,
+		SenderLogin:        f.Sender.Login,
+	}, nil
+}
+
+type envelopeScanner struct {
+	data []byte
+	pos  int
+}
+
+func (s *envelopeScanner) skipSpace() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *envelopeScanner) peek() byte {
+	s.skipSpace()
+	if s.pos < len(s.data) {
+		return s.data[s.pos]
+	}
+	return 0
+}
+
+func (s *envelopeScanner) expect(c byte) error {
+	if s.peek() != c {
+		return s.errorf("expected %q", c)
+	}
+	s.pos++
+	return nil
+}
+
+func (s *envelopeScanner) errorf(format string, args ...interface{}) error {
+	return errors.Errorf("offset %d: "+format, append([]interface{}{s.pos}, args...)...)
+}
+
+// null consumes a null literal if it is the next value.
+func (s *envelopeScanner) null() bool {
+	if s.peek() == 'n' && bytes.HasPrefix(s.data[s.pos:], []byte("null")) {
+		s.pos += len("null")
+		return true
+	}
+	return false
+}
+
+// object calls fn with the key of each field in the next object. If fn
+// returns false, the field's value is skipped. A null value is treated as an
+// empty object.
+func (s *envelopeScanner) object(fn func(key []byte) (bool, error)) error {
+	if s.null() {
+		return nil
+	}
+	if err := s.expect('{'); err != nil {
+		return err
+	}
+	if s.peek() == '}' {
+		s.pos++
+		return nil
+	}
+
+	for {
+		key, err := s.rawString()
+		if err != nil {
+			return err
+		}
+		if err := s.expect(':'); err != nil {
+			return err
+		}
+
+		read, err := fn(key)
+		if err != nil {
+			return err
+		}
+		if !read {
+			if err := s.skip(); err != nil {
+				return err
+			}
+		}
+
+		switch s.peek() {
+		case ',':
+			s.pos++
+		case '}':
+			s.pos++
+			return nil
+		default:
+			return s.errorf("expected ',' or '}'")
+		}
+	}
+}
+
+// rawString returns the contents of the next string without processing
+// escape sequences.
+func (s *envelopeScanner) rawString() ([]byte, error) {
+	if err := s.expect('"'); err != nil {
+		return nil, err
+	}
+	start := s.pos
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case '\\':
+			s.pos += 2
+		case '"':
+			s.pos++
+			return s.data[start : s.pos-1], nil
+		default:
+			s.pos++
+		}
+	}
+	return nil, s.errorf("unterminated string")
+}
+
+// string returns the value of the next string or null.
+func (s *envelopeScanner) string() (string, error) {
+	if s.null() {
+		return "", nil
+	}
+	start := s.pos
+	raw, err := s.rawString()
+	if err != nil {
+		return "", err
+	}
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return string(raw), nil
+	}
+
+	var v string
+	if err := json.Unmarshal(s.data[start:s.pos], &v); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// int returns the value of the next integer or null.
+func (s *envelopeScanner) int() (int64, error) {
+	if s.null() {
+		return 0, nil
+	}
+	start := s.pos
+	s.skipLiteral()
+	v, err := strconv.ParseInt(string(s.data[start:s.pos]), 10, 64)
+	if err != nil {
+		return 0, s.errorf("expected integer")
+	}
+	return v, nil
+}
+
+func (s *envelopeScanner) skipLiteral() {
+	for s.pos < len(s.data) {
+		switch s.data[s.pos] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return
+		}
+		s.pos++
+	}
+}
+
+// skip advances past the next value.
+func (s *envelopeScanner) skip() error {
+	depth := 0
+	for {
+		switch s.peek() {
+		case 0:
+			return s.errorf("unexpected end of payload")
+		case '"':
+			if _, err := s.rawString(); err != nil {
+				return err
+			}
+		case '{', '[':
+			depth++
+			s.pos++
+		case '}', ']':
+			depth--
+			s.pos++
+		case ',', ':':
+			if depth == 0 {
+				return s.errorf("unexpected %q", s.data[s.pos])
+			}
+			s.pos++
+		default:
+			start := s.pos
+			s.skipLiteral()
+			if s.pos == start {
+				return s.errorf("unexpected %q", s.data[s.pos])
+			}
+		}
+		if depth <= 0 {
+			if depth < 0 {
+				return s.errorf("unbalanced delimiters")
+			}
+			return nil
+		}
+	}
+}
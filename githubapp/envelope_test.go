@@ -0,0 +1,123 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPeekEnvelope(t *testing.T) {
+	tests := map[string]struct {
+		Payload  string
+		Expected Envelope
+		Err      bool
+	}{
+		"allFields": {
+			Payload: `{
+				"action": "opened",
+				"pull_request": {"number": 1, "labels": [{"name": "a"}], "draft": false},
+				"repository": {"id": 10, "owner": {"login": "octo"}, "full_name": "octo/repo"},
+				"installation": {"id": 123, "app_id": 7}
+			}`,
+			Expected: Envelope{
+				Action:             "opened",
+				InstallationID:     123,
+				InstallationAppID:  7,
+				RepositoryFullName: "octo/repo",
+			},
+		},
+		"orgFields": {
+			Payload: `{
+				"action": "created",
+				"team": {"id": 3, "name": "reviewers"},
+				"organization": {"login": "octo-org", "id": 2},
+				"enterprise": {"slug": "octo-corp", "id": 1},
+				"installation": {"id": 123},
+				"sender": {"login": "octocat", "type": "User"}
+			}`,
+			Expected: Envelope{
+				Action:            "created",
+				InstallationID:    123,
+				OrganizationLogin: "octo-org",
+				EnterpriseSlug:    "octo-corp",
+				SenderLogin:       "octocat",
+			},
+		},
+		"missingFields": {
+			Payload: `{"ref": "refs/heads/main", "commits": [], "installation": {"id": 5}}`,
+			Expected: Envelope{
+				InstallationID: 5,
+			},
+		},
+		"nullFields": {
+			Payload:  `{"action": null, "repository": null, "installation": null}`,
+			Expected: Envelope{},
+		},
+		"notObject": {
+			Payload: `[1, 2, 3]`,
+			Err:     true,
+		},
+		"invalidInstallation": {
+			Payload: `{"installation": {"id": "abc"}}`,
+			Err:     true,
+		},
+		"escapedStrings": {
+			Payload: `{"head_commit": {"message": "fix \"quotes\" and }"}, "action": "a\u0062c"}`,
+			Expected: Envelope{
+				Action: "abc",
+			},
+		},
+		"truncated": {
+			Payload: `{"action": "opened", "repository": {"full_name": "octo/`,
+			Err:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := PeekEnvelope([]byte(test.Payload))
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e != test.Expected {
+				t.Errorf("incorrect envelope\nexpected: %+v\n  actual: %+v", test.Expected, e)
+			}
+		})
+	}
+}
+
+func TestPeekEnvelopeAllocations(t *testing.T) {
+	commits := strings.Repeat(`{"id": "abc123", "message": "update \"docs\"", "added": ["a.txt", "b.txt"]},`, 1000)
+	payload := []byte(`{"ref": "refs/heads/main", "commits": [` + strings.TrimSuffix(commits, ",") + `],
+		"repository": {"full_name": "octo/repo"}, "installation": {"id": 123}}`)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, err := PeekEnvelope(payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	// allow for the closures and the extracted strings
+	if allocs > 10 {
+		t.Errorf("expected a constant number of allocations, but got %.0f", allocs)
+	}
+}
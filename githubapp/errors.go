@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strings"
 
 	"github.com/rcrowley/go-metrics"
 )
@@ -92,6 +93,66 @@ func (e HandlerPanicError) Error() string {
 	return fmt.Sprintf("panic: %v", v)
 }
 
+// HandlerError is the error from one of several handlers that processed the
+// same event.
+type HandlerError struct {
+	// Handler identifies the handler that failed.
+	Handler string
+	Err     error
+}
+
+func (e HandlerError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Handler, e.Err)
+}
+
+func (e HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError collects the errors from all handlers that failed while
+// processing an event. Use errors.As or errors.Is to check for specific
+// errors from any handler.
+type MultiError struct {
+	Errors []HandlerError
+}
+
+func (e *MultiError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d handlers failed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors from each handler.
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// add records a failure if err is not nil.
+func (e *MultiError) add(handler string, err error) {
+	if err != nil {
+		e.Errors = append(e.Errors, HandlerError{Handler: handler, Err: err})
+	}
+}
+
+// errorOrNil returns the MultiError if it contains any errors or nil
+// otherwise.
+func (e *MultiError) errorOrNil() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
 func getStack(skip int) []runtime.Frame {
 	rpc := make([]uintptr, HandlerRecoverStackDepth)
 
@@ -0,0 +1,364 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultEventSourceRetryInterval = time.Second
+	DefaultEventSourceMaxAttempts   = 10
+)
+
+// DefaultEventSourceBackoff is the default backoff for messages whose handler
+// fails. The Timeout field is not used.
+var DefaultEventSourceBackoff = Backoff{
+	InitialDelay: time.Second,
+	MaxDelay:     5 * time.Minute,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// SourceMessage is a webhook delivery received from an EventSource.
+type SourceMessage struct {
+	// ID identifies the message in the source, like an SQS receipt handle or
+	// a Kafka offset. It is only used by the source and may be empty.
+	ID string
+
+	// Header contains the headers of the original webhook request. It must
+	// include the X-GitHub-Event and X-GitHub-Delivery headers and should
+	// include the signature headers, so consumers can verify that the
+	// payload was sent by GitHub.
+	Header http.Header
+
+	// Payload is the unmodified body of the original webhook request.
+	Payload []byte
+
+	// Attempt is the number of times the source delivered the message,
+	// including this delivery, like the receive count of an SQS message or
+	// the delivery attempt of a Pub/Sub message. Sources that do not count
+	// deliveries leave it 0, and the consumer counts failed deliveries of
+	// each delivery ID in memory instead.
+	Attempt int
+}
+
+// EventType returns the event type of the delivery.
+func (m SourceMessage) EventType() string {
+	return m.Header.Get("X-GitHub-Event")
+}
+
+// DeliveryID returns the GitHub delivery ID of the delivery.
+func (m SourceMessage) DeliveryID() string {
+	return m.Header.Get("X-GitHub-Delivery")
+}
+
+// EventSource receives webhook deliveries from a queue or message bus, like
+// SQS, Pub/Sub, or Kafka. In these architectures, a small relay receives
+// webhooks from GitHub and publishes them, preserving the original headers,
+// and applications consume them with an EventConsumer.
+type EventSource interface {
+	// Receive returns the next messages from the source. It should block
+	// until at least one message is available, a source-specific timeout
+	// expires, or the context is canceled. It may return no messages.
+	Receive(ctx context.Context) ([]SourceMessage, error)
+
+	// Ack removes a message from the source after it is processed.
+	Ack(ctx context.Context, m SourceMessage) error
+
+	// Nack returns a message to the source so that it is delivered again
+	// after delay, for example by changing the visibility timeout of an SQS
+	// message or the ack deadline of a Pub/Sub message.
+	Nack(ctx context.Context, m SourceMessage, delay time.Duration) error
+}
+
+// SourceDeadLetterFunc receives messages that failed on their last allowed
+// attempt, with the error from that attempt. The message is acknowledged
+// after the function returns.
+type SourceDeadLetterFunc func(ctx context.Context, m SourceMessage, err error)
+
+// DefaultSourceDeadLetter logs messages that exhausted their attempts.
+func DefaultSourceDeadLetter(ctx context.Context, m SourceMessage, err error) {
+	zerolog.Ctx(ctx).Error().Err(err).Int("attempts", m.Attempt).Msg("Dropping event source message after its final attempt")
+}
+
+// EventConsumerOption configures properties of an event consumer.
+type EventConsumerOption func(*EventConsumer)
+
+// WithConsumerScheduler sets the scheduler used to execute handlers. If not
+// set, the consumer uses DefaultScheduler.
+func WithConsumerScheduler(s Scheduler) EventConsumerOption {
+	return func(c *EventConsumer) {
+		if s != nil {
+			c.scheduler = s
+		}
+	}
+}
+
+// WithConsumerErrorCallback sets the callback for messages that fail
+// validation or scheduling. If not set, the consumer uses
+// DefaultAsyncErrorCallback.
+func WithConsumerErrorCallback(onError AsyncErrorCallback) EventConsumerOption {
+	return func(c *EventConsumer) {
+		if onError != nil {
+			c.onError = onError
+		}
+	}
+}
+
+// WithConsumerRetryInterval sets how long a consumer waits after the source
+// returns an error before receiving again. The default is
+// DefaultEventSourceRetryInterval.
+func WithConsumerRetryInterval(interval time.Duration) EventConsumerOption {
+	return func(c *EventConsumer) {
+		if interval > 0 {
+			c.retryInterval = interval
+		}
+	}
+}
+
+// WithConsumerBackoff sets the delays before messages whose handler fails
+// are delivered again, based on the number of attempts. The default is
+// DefaultEventSourceBackoff.
+func WithConsumerBackoff(backoff Backoff) EventConsumerOption {
+	return func(c *EventConsumer) {
+		c.backoff = backoff.withDefaults()
+	}
+}
+
+// WithConsumerMaxAttempts sets the number of times a consumer tries to
+// handle a message before passing it to the dead letter function. The
+// default is DefaultEventSourceMaxAttempts.
+func WithConsumerMaxAttempts(attempts int) EventConsumerOption {
+	return func(c *EventConsumer) {
+		if attempts > 0 {
+			c.maxAttempts = attempts
+		}
+	}
+}
+
+// WithConsumerDeadLetter sets the function that receives messages that fail
+// on their last attempt, like one that publishes them to a dead letter
+// queue. The default is DefaultSourceDeadLetter.
+func WithConsumerDeadLetter(deadLetter SourceDeadLetterFunc) EventConsumerOption {
+	return func(c *EventConsumer) {
+		if deadLetter != nil {
+			c.deadLetter = deadLetter
+		}
+	}
+}
+
+// EventConsumer reads deliveries from an EventSource, validates their
+// signatures, and dispatches them to event handlers with a Scheduler, in the
+// same way that the event dispatcher handles webhook requests.
+//
+// Messages are acknowledged when the scheduler accepts them, which is when
+// the event dispatcher would respond to GitHub. With the default scheduler,
+// this means messages are acknowledged after the handler succeeds and
+// returned to the source after a backoff if the handler fails or panics.
+// With asynchronous schedulers, messages are acknowledged before the handler
+// runs and are only returned to the source if the scheduler is at capacity.
+// Messages that fail on their last attempt are passed to the dead letter
+// function and acknowledged. Messages with invalid signatures and messages
+// for event types without a handler are acknowledged and dropped.
+type EventConsumer struct {
+	source        EventSource
+	handlerMap    map[string]EventHandler
+	secret        string
+	scheduler     Scheduler
+	onError       AsyncErrorCallback
+	retryInterval time.Duration
+	backoff       Backoff
+	maxAttempts   int
+	deadLetter    SourceDeadLetterFunc
+
+	// failures counts failed deliveries for sources that do not set Attempt
+	mu       sync.Mutex
+	failures map[string]int
+
+	payloadValidation *payloadValidation
+	latency           *deliveryLatency
+}
+
+// NewEventConsumer creates a consumer that dispatches messages from source to
+// handlers. The handlers are selected in the same way as NewEventDispatcher.
+// If secret is not empty, the consumer rejects messages that are not signed
+// with it.
+func NewEventConsumer(source EventSource, handlers []EventHandler, secret string, opts ...EventConsumerOption) *EventConsumer {
+	c := &EventConsumer{
+		source:        source,
+		handlerMap:    newHandlerMap(handlers),
+		secret:        secret,
+		scheduler:     DefaultScheduler(),
+		onError:       DefaultAsyncErrorCallback,
+		retryInterval: DefaultEventSourceRetryInterval,
+		backoff:       DefaultEventSourceBackoff,
+		maxAttempts:   DefaultEventSourceMaxAttempts,
+		deadLetter:    DefaultSourceDeadLetter,
+		failures:      make(map[string]int),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Run processes messages until the context is canceled. Errors from the
+// source are logged and do not stop the consumer.
+func (c *EventConsumer) Run(ctx context.Context) error {
+	for {
+		if _, err := c.ProcessBatch(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to process event source messages")
+
+			if err := sleepContext(ctx, c.retryInterval); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// ProcessBatch receives and processes a single batch of messages. It returns
+// the number of messages received. Errors acknowledging or returning one
+// message do not stop the consumer from processing the others; ProcessBatch
+// returns all of them.
+func (c *EventConsumer) ProcessBatch(ctx context.Context) (int, error) {
+	messages, err := c.source.Receive(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to receive messages")
+	}
+
+	var errs []error
+	for _, m := range messages {
+		if err := c.process(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(messages), stderrors.Join(errs...)
+}
+
+func (c *EventConsumer) process(ctx context.Context, m SourceMessage) error {
+	eventType := m.EventType()
+	deliveryID := m.DeliveryID()
+
+	logger := zerolog.Ctx(ctx).With().
+		Str(LogKeyEventType, eventType).
+		Str(LogKeyDeliveryID, deliveryID).
+		Logger()
+	hctx := logger.WithContext(InitializeRateLimits(InitializeAsyncResponder(ctx)))
+
+	d := Dispatch{
+		EventType:  eventType,
+		DeliveryID: deliveryID,
+		Payload:    m.Payload,
+		Header:     DeliveryHeader(m.Header),
+	}
+
+	if err := c.validate(hctx, m); err != nil {
+		c.onError(hctx, d, ValidationError{
+			EventType:  eventType,
+			DeliveryID: deliveryID,
+			Cause:      err,
+		})
+		return c.ack(ctx, m)
+	}
+
+	handler, ok := c.handlerMap[eventType]
+	if !ok {
+		return c.ack(ctx, m)
+	}
+
+	hctx = c.latency.record(hctx, eventType, m.Payload)
+
+	d.Handler = handler
+	hctx = WithDelivery(hctx, Delivery{
+		EventType:  eventType,
+		DeliveryID: deliveryID,
+		Payload:    m.Payload,
+		Header:     d.Header,
+	})
+	if err := c.schedule(hctx, d); err != nil {
+		c.onError(hctx, d, err)
+
+		attempt := c.attempt(m)
+		if attempt < c.maxAttempts {
+			delay := c.backoff.delay(attempt)
+			return errors.Wrapf(c.source.Nack(ctx, m, delay), "failed to nack message for delivery %s", deliveryID)
+		}
+		dead := m
+		dead.Attempt = attempt
+		c.deadLetter(hctx, dead, err)
+	}
+	return c.ack(ctx, m)
+}
+
+// attempt returns the attempt number of a message that failed. If the
+// source does not count deliveries, it counts failures by delivery ID.
+func (c *EventConsumer) attempt(m SourceMessage) int {
+	if m.Attempt > 0 {
+		return m.Attempt
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures[m.DeliveryID()]++
+	return c.failures[m.DeliveryID()]
+}
+
+func (c *EventConsumer) validate(ctx context.Context, m SourceMessage) error {
+	if m.EventType() == "" {
+		return errors.New("missing event type")
+	}
+	if c.secret != "" {
+		if err := VerifySignature(c.secret, m.Payload, m.Header); err != nil {
+			return err
+		}
+	}
+	return c.payloadValidation.check(ctx, m.EventType(), m.Payload)
+}
+
+func (c *EventConsumer) schedule(ctx context.Context, d Dispatch) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredPanic(ctx, r)
+		}
+	}()
+	return c.scheduler.Schedule(ctx, d)
+}
+
+func (c *EventConsumer) ack(ctx context.Context, m SourceMessage) error {
+	if m.Attempt == 0 {
+		c.mu.Lock()
+		delete(c.failures, m.DeliveryID())
+		c.mu.Unlock()
+	}
+	return errors.Wrapf(c.source.Ack(ctx, m), "failed to ack message for delivery %s", m.DeliveryID())
+}
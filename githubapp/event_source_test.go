@@ -0,0 +1,214 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testEventSource struct {
+	messages []SourceMessage
+	acked    []string
+	nacked   []string
+	delays   []time.Duration
+	ackErr   error
+}
+
+func (s *testEventSource) Receive(ctx context.Context) ([]SourceMessage, error) {
+	messages := s.messages
+	s.messages = nil
+	return messages, nil
+}
+
+func (s *testEventSource) Ack(ctx context.Context, m SourceMessage) error {
+	if s.ackErr != nil {
+		return s.ackErr
+	}
+	s.acked = append(s.acked, m.ID)
+	return nil
+}
+
+func (s *testEventSource) Nack(ctx context.Context, m SourceMessage, delay time.Duration) error {
+	s.nacked = append(s.nacked, m.ID)
+	s.delays = append(s.delays, delay)
+	return nil
+}
+
+func newSourceMessage(id, eventType string, payload []byte, signature string) SourceMessage {
+	header := http.Header{}
+	header.Set("X-GitHub-Event", eventType)
+	header.Set("X-GitHub-Delivery", id)
+	if signature != "" {
+		header.Set("X-Hub-Signature-256", signature)
+	}
+	return SourceMessage{ID: id, Header: header, Payload: payload}
+}
+
+func TestEventConsumer(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+	signature := SignPayload(testHookSecret, payload)
+
+	source := &testEventSource{messages: []SourceMessage{
+		newSourceMessage("ok", "pull_request", payload, signature),
+		newSourceMessage("failed", "pull_request", payload, signature),
+		newSourceMessage("panic", "pull_request", payload, signature),
+		newSourceMessage("unsigned", "pull_request", payload, ""),
+		newSourceMessage("tampered", "pull_request", []byte(`{"action":"closed"}`), signature),
+		newSourceMessage("unhandled", "issues", payload, signature),
+	}}
+
+	handler := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			switch deliveryID {
+			case "failed":
+				return errors.New("handler failure")
+			case "panic":
+				panic("handler panic")
+			}
+			return nil
+		},
+	}
+
+	var errs []error
+	c := NewEventConsumer(source, []EventHandler{handler}, testHookSecret, WithConsumerErrorCallback(func(ctx context.Context, d Dispatch, err error) {
+		errs = append(errs, err)
+	}))
+
+	n, err := c.ProcessBatch(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 6 {
+		t.Errorf("incorrect number of messages: %d", n)
+	}
+
+	if handler.Count != 3 {
+		t.Errorf("incorrect number of handler calls: %d", handler.Count)
+	}
+	if expected := []string{"ok", "unsigned", "tampered", "unhandled"}; !reflect.DeepEqual(expected, source.acked) {
+		t.Errorf("incorrect acked messages\nexpected: %v\n  actual: %v", expected, source.acked)
+	}
+	if expected := []string{"failed", "panic"}; !reflect.DeepEqual(expected, source.nacked) {
+		t.Errorf("incorrect nacked messages\nexpected: %v\n  actual: %v", expected, source.nacked)
+	}
+
+	if len(errs) != 4 {
+		t.Fatalf("incorrect number of errors: %d", len(errs))
+	}
+	if _, ok := errs[1].(HandlerPanicError); !ok {
+		t.Errorf("expected HandlerPanicError, but got %T", errs[1])
+	}
+	for _, err := range errs[2:] {
+		if _, ok := err.(ValidationError); !ok {
+			t.Errorf("expected ValidationError, but got %T", err)
+		}
+	}
+}
+
+func TestEventConsumerRetries(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+	signature := SignPayload(testHookSecret, payload)
+
+	handler := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			return errors.New("handler failure")
+		},
+	}
+	backoff := WithConsumerBackoff(Backoff{InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 2, Jitter: -1})
+
+	t.Run("sourceAttempts", func(t *testing.T) {
+		var dead []SourceMessage
+		source := &testEventSource{}
+		c := NewEventConsumer(source, []EventHandler{handler}, testHookSecret, backoff, WithConsumerMaxAttempts(3),
+			WithConsumerDeadLetter(func(ctx context.Context, m SourceMessage, err error) {
+				dead = append(dead, m)
+			}),
+			WithConsumerErrorCallback(func(ctx context.Context, d Dispatch, err error) {}),
+		)
+
+		for attempt := 1; attempt <= 3; attempt++ {
+			m := newSourceMessage("poison", "pull_request", payload, signature)
+			m.Attempt = attempt
+			source.messages = []SourceMessage{m}
+			if _, err := c.ProcessBatch(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if expected := []time.Duration{time.Second, 2 * time.Second}; !reflect.DeepEqual(expected, source.delays) {
+			t.Errorf("incorrect nack delays\nexpected: %v\n  actual: %v", expected, source.delays)
+		}
+		if expected := []string{"poison"}; !reflect.DeepEqual(expected, source.acked) {
+			t.Errorf("expected message to be acked after its final attempt, but got %v", source.acked)
+		}
+		if len(dead) != 1 || dead[0].Attempt != 3 {
+			t.Errorf("expected message to be dead-lettered on attempt 3, but got %v", dead)
+		}
+	})
+
+	t.Run("countedAttempts", func(t *testing.T) {
+		var dead []SourceMessage
+		source := &testEventSource{}
+		c := NewEventConsumer(source, []EventHandler{handler}, testHookSecret, backoff, WithConsumerMaxAttempts(2),
+			WithConsumerDeadLetter(func(ctx context.Context, m SourceMessage, err error) {
+				dead = append(dead, m)
+			}),
+			WithConsumerErrorCallback(func(ctx context.Context, d Dispatch, err error) {}),
+		)
+
+		for i := 0; i < 2; i++ {
+			source.messages = []SourceMessage{newSourceMessage("poison", "pull_request", payload, signature)}
+			if _, err := c.ProcessBatch(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if len(source.nacked) != 1 || len(dead) != 1 || dead[0].Attempt != 2 {
+			t.Errorf("expected one nack and one dead letter, but got %v and %v", source.nacked, dead)
+		}
+		if len(c.failures) != 0 {
+			t.Errorf("expected failure count to be removed after ack, but got %v", c.failures)
+		}
+	})
+
+	t.Run("ackErrors", func(t *testing.T) {
+		source := &testEventSource{
+			messages: []SourceMessage{
+				newSourceMessage("unhandled-1", "issues", payload, signature),
+				newSourceMessage("unhandled-2", "issues", payload, signature),
+			},
+			ackErr: errors.New("source unavailable"),
+		}
+		c := NewEventConsumer(source, []EventHandler{handler}, testHookSecret)
+
+		n, err := c.ProcessBatch(context.Background())
+		if n != 2 {
+			t.Errorf("incorrect number of messages: %d", n)
+		}
+		if err == nil {
+			t.Fatal("expected error, but got nil")
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); !ok || len(joined.Unwrap()) != 2 {
+			t.Errorf("expected errors for both messages, but got %v", err)
+		}
+	})
+}
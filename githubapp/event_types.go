@@ -0,0 +1,257 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// Webhook event types, as sent in the X-GitHub-Event header.
+const (
+	EventBranchProtectionConfiguration = "branch_protection_configuration"
+	EventBranchProtectionRule          = "branch_protection_rule"
+	EventCheckRun                      = "check_run"
+	EventCheckSuite                    = "check_suite"
+	EventCodeScanningAlert             = "code_scanning_alert"
+	EventCommitComment                 = "commit_comment"
+	EventCreate                        = "create"
+	EventCustomProperty                = "custom_property"
+	EventCustomPropertyValues          = "custom_property_values"
+	EventDelete                        = "delete"
+	EventDependabotAlert               = "dependabot_alert"
+	EventDeployKey                     = "deploy_key"
+	EventDeployment                    = "deployment"
+	EventDeploymentProtectionRule      = "deployment_protection_rule"
+	EventDeploymentReview              = "deployment_review"
+	EventDeploymentStatus              = "deployment_status"
+	EventDiscussion                    = "discussion"
+	EventDiscussionComment             = "discussion_comment"
+	EventFork                          = "fork"
+	EventGitHubAppAuthorization        = "github_app_authorization"
+	EventGollum                        = "gollum"
+	EventInstallation                  = "installation"
+	EventInstallationRepositories      = "installation_repositories"
+	EventInstallationTarget            = "installation_target"
+	EventIssueComment                  = "issue_comment"
+	EventIssues                        = "issues"
+	EventLabel                         = "label"
+	EventMarketplacePurchase           = "marketplace_purchase"
+	EventMember                        = "member"
+	EventMembership                    = "membership"
+	EventMergeGroup                    = "merge_group"
+	EventMeta                          = "meta"
+	EventMilestone                     = "milestone"
+	EventOrgBlock                      = "org_block"
+	EventOrganization                  = "organization"
+	EventPackage                       = "package"
+	EventPageBuild                     = "page_build"
+	EventPersonalAccessTokenRequest    = "personal_access_token_request"
+	EventPing                          = "ping"
+	EventProject                       = "project"
+	EventProjectCard                   = "project_card"
+	EventProjectColumn                 = "project_column"
+	EventProjectsV2                    = "projects_v2"
+	EventProjectsV2Item                = "projects_v2_item"
+	EventProjectsV2StatusUpdate        = "projects_v2_status_update"
+	EventPublic                        = "public"
+	EventPullRequest                   = "pull_request"
+	EventPullRequestReview             = "pull_request_review"
+	EventPullRequestReviewComment      = "pull_request_review_comment"
+	EventPullRequestReviewThread       = "pull_request_review_thread"
+	EventPush                          = "push"
+	EventRegistryPackage               = "registry_package"
+	EventRelease                       = "release"
+	EventRepository                    = "repository"
+	EventRepositoryAdvisory            = "repository_advisory"
+	EventRepositoryDispatch            = "repository_dispatch"
+	EventRepositoryImport              = "repository_import"
+	EventRepositoryRuleset             = "repository_ruleset"
+	EventRepositoryVulnerabilityAlert  = "repository_vulnerability_alert"
+	EventSecretScanningAlert           = "secret_scanning_alert"
+	EventSecretScanningAlertLocation   = "secret_scanning_alert_location"
+	EventSecretScanningScan            = "secret_scanning_scan"
+	EventSecurityAdvisory              = "security_advisory"
+	EventSecurityAndAnalysis           = "security_and_analysis"
+	EventSponsorship                   = "sponsorship"
+	EventStar                          = "star"
+	EventStatus                        = "status"
+	EventSubIssues                     = "sub_issues"
+	EventTeam                          = "team"
+	EventTeamAdd                       = "team_add"
+	EventWatch                         = "watch"
+	EventWorkflowDispatch              = "workflow_dispatch"
+	EventWorkflowJob                   = "workflow_job"
+	EventWorkflowRun                   = "workflow_run"
+)
+
+// Common webhook event actions. Not every action applies to every event
+// type; see the GitHub documentation for the actions of each event.
+const (
+	ActionAdded                  = "added"
+	ActionArchived               = "archived"
+	ActionAssigned               = "assigned"
+	ActionAutoMergeDisabled      = "auto_merge_disabled"
+	ActionAutoMergeEnabled       = "auto_merge_enabled"
+	ActionChecksRequested        = "checks_requested"
+	ActionClosed                 = "closed"
+	ActionCompleted              = "completed"
+	ActionConvertedToDraft       = "converted_to_draft"
+	ActionCreated                = "created"
+	ActionDeleted                = "deleted"
+	ActionDemilestoned           = "demilestoned"
+	ActionDequeued               = "dequeued"
+	ActionDestroyed              = "destroyed"
+	ActionDismissed              = "dismissed"
+	ActionEdited                 = "edited"
+	ActionEnqueued               = "enqueued"
+	ActionInProgress             = "in_progress"
+	ActionLabeled                = "labeled"
+	ActionLocked                 = "locked"
+	ActionMilestoned             = "milestoned"
+	ActionNewPermissionsAccepted = "new_permissions_accepted"
+	ActionOpened                 = "opened"
+	ActionPinned                 = "pinned"
+	ActionPrereleased            = "prereleased"
+	ActionPublished              = "published"
+	ActionQueued                 = "queued"
+	ActionReadyForReview         = "ready_for_review"
+	ActionReleased               = "released"
+	ActionRemoved                = "removed"
+	ActionRenamed                = "renamed"
+	ActionReopened               = "reopened"
+	ActionRequested              = "requested"
+	ActionRequestedAction        = "requested_action"
+	ActionRerequested            = "rerequested"
+	ActionResolved               = "resolved"
+	ActionReviewRequestRemoved   = "review_request_removed"
+	ActionReviewRequested        = "review_requested"
+	ActionSubmitted              = "submitted"
+	ActionSuspend                = "suspend"
+	ActionSynchronize            = "synchronize"
+	ActionTransferred            = "transferred"
+	ActionUnarchived             = "unarchived"
+	ActionUnassigned             = "unassigned"
+	ActionUnlabeled              = "unlabeled"
+	ActionUnlocked               = "unlocked"
+	ActionUnpinned               = "unpinned"
+	ActionUnresolved             = "unresolved"
+	ActionUnsuspend              = "unsuspend"
+)
+
+var knownEventTypes = map[string]bool{
+	EventBranchProtectionConfiguration: true,
+	EventBranchProtectionRule:          true,
+	EventCheckRun:                      true,
+	EventCheckSuite:                    true,
+	EventCodeScanningAlert:             true,
+	EventCommitComment:                 true,
+	EventCreate:                        true,
+	EventCustomProperty:                true,
+	EventCustomPropertyValues:          true,
+	EventDelete:                        true,
+	EventDependabotAlert:               true,
+	EventDeployKey:                     true,
+	EventDeployment:                    true,
+	EventDeploymentProtectionRule:      true,
+	EventDeploymentReview:              true,
+	EventDeploymentStatus:              true,
+	EventDiscussion:                    true,
+	EventDiscussionComment:             true,
+	EventFork:                          true,
+	EventGitHubAppAuthorization:        true,
+	EventGollum:                        true,
+	EventInstallation:                  true,
+	EventInstallationRepositories:      true,
+	EventInstallationTarget:            true,
+	EventIssueComment:                  true,
+	EventIssues:                        true,
+	EventLabel:                         true,
+	EventMarketplacePurchase:           true,
+	EventMember:                        true,
+	EventMembership:                    true,
+	EventMergeGroup:                    true,
+	EventMeta:                          true,
+	EventMilestone:                     true,
+	EventOrgBlock:                      true,
+	EventOrganization:                  true,
+	EventPackage:                       true,
+	EventPageBuild:                     true,
+	EventPersonalAccessTokenRequest:    true,
+	EventPing:                          true,
+	EventProject:                       true,
+	EventProjectCard:                   true,
+	EventProjectColumn:                 true,
+	EventProjectsV2:                    true,
+	EventProjectsV2Item:                true,
+	EventProjectsV2StatusUpdate:        true,
+	EventPublic:                        true,
+	EventPullRequest:                   true,
+	EventPullRequestReview:             true,
+	EventPullRequestReviewComment:      true,
+	EventPullRequestReviewThread:       true,
+	EventPush:                          true,
+	EventRegistryPackage:               true,
+	EventRelease:                       true,
+	EventRepository:                    true,
+	EventRepositoryAdvisory:            true,
+	EventRepositoryDispatch:            true,
+	EventRepositoryImport:              true,
+	EventRepositoryRuleset:             true,
+	EventRepositoryVulnerabilityAlert:  true,
+	EventSecretScanningAlert:           true,
+	EventSecretScanningAlertLocation:   true,
+	EventSecretScanningScan:            true,
+	EventSecurityAdvisory:              true,
+	EventSecurityAndAnalysis:           true,
+	EventSponsorship:                   true,
+	EventStar:                          true,
+	EventStatus:                        true,
+	EventSubIssues:                     true,
+	EventTeam:                          true,
+	EventTeamAdd:                       true,
+	EventWatch:                         true,
+	EventWorkflowDispatch:              true,
+	EventWorkflowJob:                   true,
+	EventWorkflowRun:                   true,
+}
+
+// Handles returns a list of event types for the Handles method of an
+// EventHandler. It panics if a type is not a known event type, so typos in
+// handler definitions fail when the handler is created instead of silently
+// never matching a delivery. Dispatchers match event types exactly, so the
+// wildcard "*" used in webhook configurations is also rejected:
+//
+//	func (h *PRHandler) Handles() []string {
+//		return githubapp.Handles(githubapp.EventPullRequest, githubapp.EventPullRequestReview)
+//	}
+//
+// Handlers for event types that neither this package nor go-github know yet
+// can return a string slice instead.
+func Handles(eventTypes ...string) []string {
+	for _, t := range eventTypes {
+		if !IsKnownEventType(t) {
+			panic(fmt.Sprintf("githubapp: unknown event type %q", t))
+		}
+	}
+	return eventTypes
+}
+
+// IsKnownEventType returns true if eventType is one of the event type
+// constants defined by this package or an event type that go-github parses.
+func IsKnownEventType(eventType string) bool {
+	return knownEventTypes[eventType] || github.EventForType(eventType) != nil
+}
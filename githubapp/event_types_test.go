@@ -0,0 +1,68 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestHandles(t *testing.T) {
+	types := Handles(EventPullRequest, EventPullRequestReview)
+	if fmt.Sprint(types) != "[pull_request pull_request_review]" {
+		t.Errorf("incorrect event types: %v", types)
+	}
+
+	// new constants and types that only go-github knows
+	if types := Handles(EventSubIssues, EventProjectsV2StatusUpdate, EventSecretScanningScan, "user"); len(types) != 4 {
+		t.Errorf("incorrect event types: %v", types)
+	}
+
+	// dispatchers never match the wildcard
+	for _, invalid := range []string{"pull_requests", "*"} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected panic for event type %q", invalid)
+				}
+			}()
+			Handles(EventPullRequest, invalid)
+		}()
+	}
+}
+
+func TestKnownEventTypes(t *testing.T) {
+	// events that GitHub sends but go-github does not parse yet
+	unparsed := map[string]bool{
+		EventBranchProtectionConfiguration: true,
+		EventCustomProperty:                true,
+		EventCustomPropertyValues:          true,
+		EventProjectsV2StatusUpdate:        true,
+		EventRegistryPackage:               true,
+		EventRepositoryAdvisory:            true,
+		EventRepositoryRuleset:             true,
+		EventSecretScanningAlertLocation:   true,
+		EventSecretScanningScan:            true,
+		EventSubIssues:                     true,
+	}
+
+	for eventType := range knownEventTypes {
+		if github.EventForType(eventType) == nil && !unparsed[eventType] {
+			t.Errorf("event type %q is not known to go-github", eventType)
+		}
+	}
+}
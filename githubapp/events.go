@@ -0,0 +1,208 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// Event is a parsed webhook event.
+type Event struct {
+	Type       string
+	DeliveryID string
+
+	// Payload is the concrete go-github type for the event, like
+	// *github.PullRequestEvent. It always uses the version of go-github
+	// required by this module, so applications should use the same version
+	// in type assertions.
+	Payload interface{}
+
+	// InstallationID is the ID of the installation that received the event,
+	// or 0 if the event is not associated with an installation.
+	InstallationID int64
+
+	// Repository is the repository of the event, if any.
+	Repository *github.Repository
+
+	// Organization is the organization of the event, if any. It is set for
+	// organization-level events, like "team" or "organization" events, as
+	// well as most repository events in organizations.
+	Organization *github.Organization
+
+	// Enterprise is the enterprise of the event, if any.
+	Enterprise *github.Enterprise
+
+	// PullRequestNumber is the number of the pull request of the event, if
+	// any. It is set for pull request events and for issue comments on pull
+	// requests. For merge_group events, it is the number returned by
+	// MergeGroupPullRequestNumber.
+	PullRequestNumber int
+}
+
+type repositorySource interface {
+	GetRepo() *github.Repository
+}
+
+// go-github is inconsistent in naming the organization accessor
+type orgSource interface {
+	GetOrg() *github.Organization
+}
+
+type organizationSource interface {
+	GetOrganization() *github.Organization
+}
+
+type enterpriseSource interface {
+	GetEnterprise() *github.Enterprise
+}
+
+type pullRequestSource interface {
+	GetPullRequest() *github.PullRequest
+}
+
+// ParseEvent parses a webhook payload of the given type into the matching
+// go-github event type and extracts common metadata. It returns an error if
+// the event type is unknown or the payload is invalid.
+func ParseEvent(eventType string, payload []byte) (*Event, error) {
+	return parseEvent(nil, eventType, payload)
+}
+
+// ParseEventContext is like ParseEvent, but decodes the payload with the
+// function returned by GetJSONUnmarshal.
+func ParseEventContext(ctx context.Context, eventType string, payload []byte) (*Event, error) {
+	return parseEvent(contextJSONUnmarshal(ctx), eventType, payload)
+}
+
+// parseEvent decodes the payload with unmarshal, or with json.Unmarshal if
+// unmarshal is nil.
+func parseEvent(unmarshal JSONUnmarshalFunc, eventType string, payload []byte) (*Event, error) {
+	parsed := github.EventForType(eventType)
+	if parsed == nil {
+		return nil, errors.Errorf("failed to parse %s event payload: unknown event type", eventType)
+	}
+	decode := unmarshal
+	if decode == nil {
+		decode = json.Unmarshal
+	}
+	if err := decode(payload, parsed); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s event payload", eventType)
+	}
+
+	e := &Event{
+		Type:    eventType,
+		Payload: parsed,
+	}
+	if src, ok := parsed.(InstallationSource); ok {
+		e.InstallationID = GetInstallationIDFromEvent(src)
+	} else if env, err := peekEnvelope(unmarshal, payload); err == nil {
+		// some event types do not expose the installation in go-github
+		e.InstallationID = env.InstallationID
+	}
+	if src, ok := parsed.(repositorySource); ok {
+		e.Repository = src.GetRepo()
+	}
+	switch src := parsed.(type) {
+	case orgSource:
+		e.Organization = src.GetOrg()
+	case organizationSource:
+		e.Organization = src.GetOrganization()
+	}
+	if src, ok := parsed.(enterpriseSource); ok {
+		e.Enterprise = src.GetEnterprise()
+	}
+
+	switch src := parsed.(type) {
+	case *github.IssueCommentEvent:
+		if src.GetIssue().IsPullRequest() {
+			e.PullRequestNumber = src.GetIssue().GetNumber()
+		}
+	case *github.PushEvent:
+		// push events use a different repository type
+		if repo := src.GetRepo(); repo != nil {
+			e.Repository = &github.Repository{
+				ID:       repo.ID,
+				Name:     repo.Name,
+				FullName: repo.FullName,
+				Owner:    repo.Owner,
+			}
+		}
+	case *github.MergeGroupEvent:
+		e.PullRequestNumber = MergeGroupPullRequestNumber(src.GetMergeGroup())
+	case pullRequestSource:
+		e.PullRequestNumber = src.GetPullRequest().GetNumber()
+	}
+
+	return e, nil
+}
+
+// PrepareContext adds information about the event's installation,
+// repository, and pull request to the logger in a context and returns the
+// modified context and logger. For events without a repository, it adds
+// information about the event's organization and enterprise instead.
+func (e *Event) PrepareContext(ctx context.Context) (context.Context, zerolog.Logger) {
+	if event, ok := e.Payload.(*github.MergeGroupEvent); ok {
+		return PrepareMergeGroupContext(ctx, e.InstallationID, e.Repository, event.GetMergeGroup())
+	}
+	if e.Repository != nil {
+		return PreparePRContext(ctx, e.InstallationID, e.Repository, e.PullRequestNumber)
+	}
+
+	logctx := zerolog.Ctx(ctx).With()
+
+	logctx = attachInstallationLogKeys(logctx, e.InstallationID)
+	logctx = attachOrgLogKeys(logctx, e.Organization)
+	logctx = attachEnterpriseLogKeys(logctx, e.Enterprise)
+
+	logger := logctx.Logger()
+	return logger.WithContext(ctx), logger
+}
+
+// ParsedEventHandlerFunc handles a parsed webhook event.
+type ParsedEventHandlerFunc func(ctx context.Context, event *Event) error
+
+type parsedEventHandler struct {
+	eventTypes []string
+	handle     ParsedEventHandlerFunc
+}
+
+// NewParsedEventHandler returns an EventHandler for the given event types
+// that parses payloads with ParseEventContext and prepares the context with
+// Event.PrepareContext before calling handle.
+func NewParsedEventHandler(handle ParsedEventHandlerFunc, eventTypes ...string) EventHandler {
+	return &parsedEventHandler{
+		eventTypes: eventTypes,
+		handle:     handle,
+	}
+}
+
+func (h *parsedEventHandler) Handles() []string {
+	return h.eventTypes
+}
+
+func (h *parsedEventHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	event, err := ParseEventContext(ctx, eventType, payload)
+	if err != nil {
+		return err
+	}
+	event.DeliveryID = deliveryID
+
+	ctx, _ = event.PrepareContext(ctx)
+	return h.handle(ctx, event)
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestParseEvent(t *testing.T) {
+	tests := map[string]struct {
+		Type    string
+		Payload string
+
+		InstallationID int64
+		RepoName       string
+		OrgLogin       string
+		EnterpriseSlug string
+		PRNumber       int
+		Err            bool
+	}{
+		"pullRequest": {
+			Type:           "pull_request",
+			Payload:        `{"action":"opened","number":7,"pull_request":{"number":7},"repository":{"name":"repo"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			RepoName:       "repo",
+			PRNumber:       7,
+		},
+		"issueCommentOnPullRequest": {
+			Type:           "issue_comment",
+			Payload:        `{"action":"created","issue":{"number":3,"pull_request":{"url":"https://example.com"}},"repository":{"name":"repo"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			RepoName:       "repo",
+			PRNumber:       3,
+		},
+		"issueCommentOnIssue": {
+			Type:           "issue_comment",
+			Payload:        `{"action":"created","issue":{"number":3},"repository":{"name":"repo"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			RepoName:       "repo",
+		},
+		"push": {
+			Type:     "push",
+			Payload:  `{"ref":"refs/heads/main","repository":{"name":"repo"}}`,
+			RepoName: "repo",
+		},
+		"team": {
+			Type:           "team",
+			Payload:        `{"action":"created","team":{"name":"reviewers"},"organization":{"login":"octo-org"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			OrgLogin:       "octo-org",
+		},
+		"organization": {
+			Type:           "organization",
+			Payload:        `{"action":"member_added","organization":{"login":"octo-org"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			OrgLogin:       "octo-org",
+		},
+		"securityAndAnalysis": {
+			Type:           "security_and_analysis",
+			Payload:        `{"organization":{"login":"octo-org"},"enterprise":{"slug":"octo-corp"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			OrgLogin:       "octo-org",
+			EnterpriseSlug: "octo-corp",
+		},
+		"repositoryImport": {
+			Type:           "repository_import",
+			Payload:        `{"status":"success","repository":{"name":"repo"},"organization":{"login":"octo-org"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			RepoName:       "repo",
+			OrgLogin:       "octo-org",
+		},
+		"mergeGroup": {
+			Type:           "merge_group",
+			Payload:        `{"action":"checks_requested","merge_group":{"head_ref":"refs/heads/gh-readonly-queue/main/pr-42-0a1b2c"},"repository":{"name":"repo"},"installation":{"id":12}}`,
+			InstallationID: 12,
+			RepoName:       "repo",
+			PRNumber:       42,
+		},
+		"unknownType": {
+			Type:    "not_an_event",
+			Payload: `{}`,
+			Err:     true,
+		},
+		"invalidPayload": {
+			Type:    "push",
+			Payload: `{`,
+			Err:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			event, err := ParseEvent(test.Type, []byte(test.Payload))
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if event.InstallationID != test.InstallationID {
+				t.Errorf("incorrect installation ID: expected %d, actual %d", test.InstallationID, event.InstallationID)
+			}
+			if name := event.Repository.GetName(); name != test.RepoName {
+				t.Errorf("incorrect repository: expected %q, actual %q", test.RepoName, name)
+			}
+			if login := event.Organization.GetLogin(); login != test.OrgLogin {
+				t.Errorf("incorrect organization: expected %q, actual %q", test.OrgLogin, login)
+			}
+			if slug := event.Enterprise.GetSlug(); slug != test.EnterpriseSlug {
+				t.Errorf("incorrect enterprise: expected %q, actual %q", test.EnterpriseSlug, slug)
+			}
+			if event.PullRequestNumber != test.PRNumber {
+				t.Errorf("incorrect pull request number: expected %d, actual %d", test.PRNumber, event.PullRequestNumber)
+			}
+		})
+	}
+}
+
+func TestParseEventPayloadType(t *testing.T) {
+	event, err := ParseEvent("pull_request", []byte(`{"action":"closed"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := event.Payload.(*github.PullRequestEvent); !ok {
+		t.Errorf("incorrect payload type: %T", event.Payload)
+	}
+}
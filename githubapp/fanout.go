@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// NewFanOutHandler returns an EventHandler that sends each event to every
+// handler in handlers that handles the event type, in order. The event
+// dispatcher only calls one handler for each event type, so use this to
+// register several independent handlers for the same events.
+//
+// All handlers run even if some return errors. If any handlers fail, Handle
+// returns a *MultiError that identifies each failed handler by its position
+// and type.
+func NewFanOutHandler(handlers ...EventHandler) EventHandler {
+	h := &fanOutHandler{
+		handlerMap: make(map[string][]int),
+		handlers:   handlers,
+	}
+	for i, handler := range handlers {
+		for _, event := range handler.Handles() {
+			if !slices.Contains(h.handlerMap[event], i) {
+				h.handlerMap[event] = append(h.handlerMap[event], i)
+			}
+		}
+	}
+	return h
+}
+
+type fanOutHandler struct {
+	handlerMap map[string][]int
+	handlers   []EventHandler
+}
+
+func (h *fanOutHandler) Handles() []string {
+	events := make([]string, 0, len(h.handlerMap))
+	for event := range h.handlerMap {
+		events = append(events, event)
+	}
+	return events
+}
+
+func (h *fanOutHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var errs MultiError
+	for _, i := range h.handlerMap[eventType] {
+		handler := h.handlers[i]
+		errs.add(fmt.Sprintf("handler %d (%T)", i, handler), handler.Handle(ctx, eventType, deliveryID, payload))
+	}
+	return errs.errorOrNil()
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFanOutHandler(t *testing.T) {
+	errFirst := errors.New("first failed")
+	errThird := errors.New("third failed")
+
+	newHandler := func(err error, types ...string) *TestEventHandler {
+		return &TestEventHandler{
+			Types: types,
+			Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+				return err
+			},
+		}
+	}
+
+	first := newHandler(errFirst, "push", "pull_request")
+	second := newHandler(nil, "push")
+	third := newHandler(errThird, "push")
+	h := NewFanOutHandler(first, second, third)
+
+	events := h.Handles()
+	sort.Strings(events)
+	if strings.Join(events, ",") != "pull_request,push" {
+		t.Errorf("incorrect events: %v", events)
+	}
+
+	t.Run("collectsErrors", func(t *testing.T) {
+		err := h.Handle(context.Background(), "push", "id", []byte(`{}`))
+
+		var merr *MultiError
+		if !errors.As(err, &merr) {
+			t.Fatalf("expected MultiError, but got %T: %v", err, err)
+		}
+		if len(merr.Errors) != 2 {
+			t.Fatalf("expected 2 errors, but got %d", len(merr.Errors))
+		}
+		if merr.Errors[0].Handler != "handler 0 (*githubapp.TestEventHandler)" {
+			t.Errorf("incorrect handler attribution: %q", merr.Errors[0].Handler)
+		}
+		if !errors.Is(err, errFirst) || !errors.Is(err, errThird) {
+			t.Errorf("expected error to wrap all handler errors: %v", err)
+		}
+		if !strings.HasPrefix(err.Error(), "2 handlers failed: ") {
+			t.Errorf("incorrect error message: %q", err.Error())
+		}
+		if second.Count != 1 || third.Count != 1 {
+			t.Errorf("expected all handlers to run, but got counts %d and %d", second.Count, third.Count)
+		}
+	})
+
+	t.Run("singleHandler", func(t *testing.T) {
+		err := h.Handle(context.Background(), "pull_request", "id", []byte(`{}`))
+		if !errors.Is(err, errFirst) {
+			t.Errorf("expected error to wrap handler error: %v", err)
+		}
+	})
+
+	t.Run("unhandledEvent", func(t *testing.T) {
+		if err := h.Handle(context.Background(), "issues", "id", []byte(`{}`)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault is a failure injected into a request by FaultInjection. It returns
+// the response or error for the request, and may call next to send the
+// request to GitHub, like to add latency to a real response.
+type Fault func(r *http.Request, next http.RoundTripper) (*http.Response, error)
+
+// FaultStatus returns a fault that responds with status and a GitHub-style
+// error body, like http.StatusBadGateway for a failing proxy.
+func FaultStatus(status int) Fault {
+	return func(r *http.Request, next http.RoundTripper) (*http.Response, error) {
+		return faultResponse(r, status, nil, http.StatusText(status)), nil
+	}
+}
+
+// FaultSecondaryRateLimit returns a fault that responds like GitHub does when
+// a secondary rate limit is exceeded: a 429 response with a Retry-After
+// header. Requests that receive it return a SecondaryRateLimitError from
+// ClassifyError.
+func FaultSecondaryRateLimit(retryAfter time.Duration) Fault {
+	return func(r *http.Request, next http.RoundTripper) (*http.Response, error) {
+		secs := int64((retryAfter + time.Second - 1) / time.Second)
+		header := http.Header{"Retry-After": []string{strconv.FormatInt(secs, 10)}}
+		return faultResponse(r, http.StatusTooManyRequests, header, "You have exceeded a secondary rate limit"), nil
+	}
+}
+
+// FaultTokenExpired returns a fault that responds like GitHub does when an
+// installation token expired or was revoked. To test token refreshes, inject
+// this fault beneath authentication with WithTransport and do not match
+// requests that create tokens.
+func FaultTokenExpired() Fault {
+	return func(r *http.Request, next http.RoundTripper) (*http.Response, error) {
+		return faultResponse(r, http.StatusUnauthorized, nil, "Bad credentials"), nil
+	}
+}
+
+// FaultLatency returns a fault that waits for d and then sends the request
+// to GitHub. The wait uses the clock in the request context and ends early
+// if the context is canceled.
+func FaultLatency(d time.Duration) Fault {
+	return func(r *http.Request, next http.RoundTripper) (*http.Response, error) {
+		if err := sleepContext(r.Context(), d); err != nil {
+			return nil, err
+		}
+		return next.RoundTrip(r)
+	}
+}
+
+// FaultError returns a fault that fails requests with err without a
+// response, like a connection reset.
+func FaultError(err error) Fault {
+	return func(r *http.Request, next http.RoundTripper) (*http.Response, error) {
+		discardRequestBody(r)
+		return nil, err
+	}
+}
+
+// discardRequestBody closes the body of a request that is not sent, as
+// required of all RoundTrippers.
+func discardRequestBody(r *http.Request) {
+	if r.Body != nil {
+		closeBody(r.Body)
+	}
+}
+
+func faultResponse(r *http.Request, status int, header http.Header, message string) *http.Response {
+	discardRequestBody(r)
+
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+
+	body := fmt.Sprintf(`{"message": %q}`, message)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
+
+// FaultRule selects the requests that receive a fault.
+type FaultRule struct {
+	// Method is the HTTP method of matching requests. If empty, the rule
+	// matches all methods.
+	Method string
+
+	// Endpoint is the route template of matching request paths, as returned
+	// by EndpointTemplate, like "/repos/{owner}/{repo}/pulls/{number}". If
+	// empty, the rule matches all paths.
+	Endpoint string
+
+	// Probability is the chance that a matching request receives the fault,
+	// between 0 and 1. If zero, every matching request receives the fault.
+	Probability float64
+
+	// Limit is the maximum number of requests that receive the fault. If
+	// zero, there is no limit. Use a limit of 1 to fail only the first
+	// attempt of a request and test that it is retried.
+	Limit int
+
+	// Fault is the failure to inject.
+	Fault Fault
+}
+
+func (rule FaultRule) matches(r *http.Request) bool {
+	if rule.Method != "" && rule.Method != r.Method {
+		return false
+	}
+	if rule.Endpoint != "" && rule.Endpoint != EndpointTemplate(r.URL.Path) {
+		return false
+	}
+	return true
+}
+
+// FaultInjection creates client middleware that injects failures into
+// requests that match rules, for chaos tests of retries and backoff in
+// handlers without real GitHub failures. For each request, the first rule
+// that matches, has not reached its limit, and is selected by its
+// probability applies its fault. Other requests are sent to GitHub as
+// usual.
+//
+// The middleware is meant for tests and staging environments; do not
+// configure it in production.
+func FaultInjection(rules ...FaultRule) ClientMiddleware {
+	var mu sync.Mutex
+	injected := make([]int, len(rules))
+
+	selectFault := func(r *http.Request) Fault {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for i, rule := range rules {
+			if rule.Fault == nil || !rule.matches(r) {
+				continue
+			}
+			if rule.Limit > 0 && injected[i] >= rule.Limit {
+				continue
+			}
+			if rule.Probability > 0 && rand.Float64() >= rule.Probability {
+				continue
+			}
+			injected[i]++
+			return rule.Fault
+		}
+		return nil
+	}
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if fault := selectFault(r); fault != nil {
+				return fault(r, next)
+			}
+			return next.RoundTrip(r)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestFaultInjection(t *testing.T) {
+	var sent int
+	var base http.RoundTripper = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		sent++
+		res := httptest.NewRecorder()
+		res.WriteHeader(http.StatusOK)
+		return res.Result(), nil
+	})
+
+	rt := FaultInjection(
+		FaultRule{Method: http.MethodGet, Endpoint: "/repos/{owner}/{repo}/pulls/{number}", Limit: 1, Fault: FaultSecondaryRateLimit(1500 * time.Millisecond)},
+		FaultRule{Method: http.MethodPost, Limit: 2, Fault: FaultStatus(http.StatusBadGateway)},
+		FaultRule{Endpoint: "/user", Fault: FaultTokenExpired()},
+		FaultRule{Endpoint: "/rate_limit", Fault: FaultError(errors.New("connection reset"))},
+		FaultRule{Endpoint: "/zen", Fault: FaultLatency(time.Minute)},
+	)(base)
+
+	clock := newTestClock()
+	ctx := WithClock(context.Background(), clock)
+
+	tests := []struct {
+		Method string
+		Path   string
+		Status int
+		Err    bool
+	}{
+		{Method: http.MethodGet, Path: "/repos/octo/repo/pulls/1", Status: http.StatusTooManyRequests},
+		{Method: http.MethodGet, Path: "/repos/octo/repo/pulls/1", Status: http.StatusOK},
+		{Method: http.MethodPost, Path: "/repos/octo/repo/issues", Status: http.StatusBadGateway},
+		{Method: http.MethodPost, Path: "/repos/octo/repo/issues", Status: http.StatusBadGateway},
+		{Method: http.MethodPost, Path: "/repos/octo/repo/issues", Status: http.StatusOK},
+		{Method: http.MethodGet, Path: "/user", Status: http.StatusUnauthorized},
+		{Method: http.MethodGet, Path: "/rate_limit", Err: true},
+		{Method: http.MethodGet, Path: "/zen", Status: http.StatusOK},
+	}
+
+	var last *http.Response
+	for _, test := range tests {
+		req, _ := http.NewRequestWithContext(ctx, test.Method, "https://api.github.com"+test.Path, nil)
+		res, err := rt.RoundTrip(req)
+		if test.Err {
+			if err == nil {
+				t.Errorf("%s %s: expected error, but got nil", test.Method, test.Path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s %s: unexpected error: %v", test.Method, test.Path, err)
+		}
+		if res.StatusCode != test.Status {
+			t.Errorf("%s %s: incorrect status: expected %d, actual %d", test.Method, test.Path, test.Status, res.StatusCode)
+		}
+		if last == nil {
+			last = res
+		}
+	}
+
+	if sent != 3 {
+		t.Errorf("expected 3 requests sent to the base transport, but got %d", sent)
+	}
+	if len(clock.slept) != 1 || clock.slept[0] != time.Minute {
+		t.Errorf("expected 1 minute of injected latency, but got %v", clock.slept)
+	}
+
+	err := ClassifyError(github.CheckResponse(last))
+	var rateErr *SecondaryRateLimitError
+	if !errors.As(err, &rateErr) || rateErr.RetryAfter != 2*time.Second {
+		t.Errorf("expected secondary rate limit error with retry after 2s, but got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/base64"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// Git file modes for use in FileChange.
+const (
+	FileModeRegular    = "100644"
+	FileModeExecutable = "100755"
+	FileModeSymlink    = "120000"
+)
+
+// FileChange is a change to a single file in a commit.
+type FileChange struct {
+	// Path is the path of the file relative to the repository root.
+	Path string
+
+	// Content is the new content of the file.
+	Content []byte
+
+	// Mode is the file mode. If empty, it defaults to FileModeRegular.
+	Mode string
+
+	// Delete removes the file instead of setting its content.
+	Delete bool
+}
+
+// CommitOptions are optional properties of a commit.
+type CommitOptions struct {
+	// Author sets the author of the commit. If nil, GitHub attributes the
+	// commit to the authenticated app or user and signs it, so it shows as
+	// verified.
+	Author *github.CommitAuthor
+
+	// Force allows updating the branch even if the new commit is not a
+	// descendant of the current commit. It is only needed if the branch
+	// changes while the commit is created.
+	Force bool
+}
+
+// CreateBranch creates the branch in owner/repo pointing to the commit sha.
+func CreateBranch(ctx context.Context, client *github.Client, owner, repo, branch, sha string) (*github.Reference, error) {
+	ref, _, err := client.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: &sha},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create branch %q in %s/%s", branch, owner, repo)
+	}
+	return ref, nil
+}
+
+// GetBranchSHA returns the SHA of the commit at the head of the branch.
+func GetBranchSHA(ctx context.Context, client *github.Client, owner, repo, branch string) (string, error) {
+	ref, _, err := client.Git.GetRef(ctx, owner, repo, "heads/"+branch)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get branch %q in %s/%s", branch, owner, repo)
+	}
+	return ref.GetObject().GetSHA(), nil
+}
+
+// CommitChanges creates a commit with the changes on top of the head of the
+// branch and updates the branch to point to the new commit. It uses the Git
+// Data API, so applications can modify repositories without cloning them.
+func CommitChanges(ctx context.Context, client *github.Client, owner, repo, branch, message string, changes []FileChange, opts CommitOptions) (*github.Commit, error) {
+	parentSHA, err := GetBranchSHA(ctx, client, owner, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, _, err := client.Git.GetCommit(ctx, owner, repo, parentSHA)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get commit %s in %s/%s", parentSHA, owner, repo)
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(changes))
+	for _, change := range changes {
+		entry, err := createTreeEntry(ctx, client, owner, repo, change)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	tree, _, err := client.Git.CreateTree(ctx, owner, repo, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create tree in %s/%s", owner, repo)
+	}
+
+	commit, _, err := client.Git.CreateCommit(ctx, owner, repo, &github.Commit{
+		Message: &message,
+		Tree:    &github.Tree{SHA: tree.SHA},
+		Parents: []*github.Commit{{SHA: &parentSHA}},
+		Author:  opts.Author,
+	}, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create commit in %s/%s", owner, repo)
+	}
+
+	_, _, err = client.Git.UpdateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: commit.SHA},
+	}, opts.Force)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to update branch %q in %s/%s", branch, owner, repo)
+	}
+	return commit, nil
+}
+
+func createTreeEntry(ctx context.Context, client *github.Client, owner, repo string, change FileChange) (*github.TreeEntry, error) {
+	mode := change.Mode
+	if mode == "" {
+		mode = FileModeRegular
+	}
+
+	entry := &github.TreeEntry{
+		Path: github.String(change.Path),
+		Mode: &mode,
+		Type: github.String("blob"),
+	}
+	if change.Delete {
+		// an entry without a SHA or content deletes the file
+		return entry, nil
+	}
+
+	// text files can be included in the tree directly, but binary files
+	// must be uploaded as base64-encoded blobs first
+	if utf8.Valid(change.Content) {
+		entry.Content = github.String(string(change.Content))
+		return entry, nil
+	}
+
+	blob, _, err := client.Git.CreateBlob(ctx, owner, repo, &github.Blob{
+		Content:  github.String(base64.StdEncoding.EncodeToString(change.Content)),
+		Encoding: github.String("base64"),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create blob for %s in %s/%s", change.Path, owner, repo)
+	}
+	entry.SHA = blob.SHA
+	return entry, nil
+}
+
+// ProposedChanges describes changes to propose in a new pull request.
+type ProposedChanges struct {
+	// Base is the branch the pull request merges into.
+	Base string
+
+	// Head is the name of the new branch that contains the changes.
+	Head string
+
+	// Title and Body are the title and description of the pull request.
+	Title string
+	Body  string
+
+	// Message is the commit message. If empty, the title is used.
+	Message string
+
+	Changes []FileChange
+	Commit  CommitOptions
+}
+
+// ProposeChanges creates a branch from the base branch, commits the changes
+// to it, and opens a pull request. This is useful for bots that fix
+// configuration or update dependencies.
+func ProposeChanges(ctx context.Context, client *github.Client, owner, repo string, p ProposedChanges) (*github.PullRequest, error) {
+	baseSHA, err := GetBranchSHA(ctx, client, owner, repo, p.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := CreateBranch(ctx, client, owner, repo, p.Head, baseSHA); err != nil {
+		return nil, err
+	}
+
+	message := p.Message
+	if message == "" {
+		message = p.Title
+	}
+	if _, err := CommitChanges(ctx, client, owner, repo, p.Head, message, p.Changes, p.Commit); err != nil {
+		return nil, err
+	}
+
+	pr, _, err := client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: &p.Title,
+		Body:  &p.Body,
+		Head:  &p.Head,
+		Base:  &p.Base,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create pull request in %s/%s", owner, repo)
+	}
+	return pr, nil
+}
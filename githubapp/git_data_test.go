@@ -0,0 +1,129 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestProposeChanges(t *testing.T) {
+	refs := map[string]string{"main": "base-sha"}
+	var tree, commit, pull map[string]interface{}
+	var blobs int
+
+	decode := func(r *http.Request) map[string]interface{} {
+		var v map[string]interface{}
+		b, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &v); err != nil {
+			t.Errorf("invalid request body: %s: %v", b, err)
+		}
+		return v
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/git/ref/heads/{branch}", func(w http.ResponseWriter, r *http.Request) {
+		sha, ok := refs[r.PathValue("branch")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"ref": "refs/heads/%s", "object": {"sha": %q}}`, r.PathValue("branch"), sha)
+	})
+	mux.HandleFunc("POST /repos/octo/repo/git/refs", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		refs["fix"] = body["sha"].(string)
+		_, _ = w.Write([]byte(`{"ref": "refs/heads/fix"}`))
+	})
+	mux.HandleFunc("PATCH /repos/octo/repo/git/refs/heads/fix", func(w http.ResponseWriter, r *http.Request) {
+		body := decode(r)
+		refs["fix"] = body["sha"].(string)
+		_, _ = w.Write([]byte(`{"ref": "refs/heads/fix"}`))
+	})
+	mux.HandleFunc("GET /repos/octo/repo/git/commits/base-sha", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"sha": "base-sha", "tree": {"sha": "base-tree"}}`))
+	})
+	mux.HandleFunc("POST /repos/octo/repo/git/blobs", func(w http.ResponseWriter, r *http.Request) {
+		blobs++
+		_, _ = w.Write([]byte(`{"sha": "blob-sha"}`))
+	})
+	mux.HandleFunc("POST /repos/octo/repo/git/trees", func(w http.ResponseWriter, r *http.Request) {
+		tree = decode(r)
+		_, _ = w.Write([]byte(`{"sha": "new-tree"}`))
+	})
+	mux.HandleFunc("POST /repos/octo/repo/git/commits", func(w http.ResponseWriter, r *http.Request) {
+		commit = decode(r)
+		_, _ = w.Write([]byte(`{"sha": "new-commit"}`))
+	})
+	mux.HandleFunc("POST /repos/octo/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		pull = decode(r)
+		_, _ = w.Write([]byte(`{"number": 12}`))
+	})
+	client := newStaticClientCreator(t, mux).client
+
+	pr, err := ProposeChanges(context.Background(), client, "octo", "repo", ProposedChanges{
+		Base:  "main",
+		Head:  "fix",
+		Title: "Fix configuration",
+		Changes: []FileChange{
+			{Path: "config.yml", Content: []byte("enabled: true\n")},
+			{Path: "image.bin", Content: []byte{0xff, 0xfe, 0x00}},
+			{Path: "old.yml", Delete: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pr.GetNumber() != 12 {
+		t.Errorf("incorrect pull request number: %d", pr.GetNumber())
+	}
+	if refs["fix"] != "new-commit" {
+		t.Errorf("incorrect branch head: %s", refs["fix"])
+	}
+	if blobs != 1 {
+		t.Errorf("expected 1 blob for the binary file, but created %d", blobs)
+	}
+
+	if tree["base_tree"] != "base-tree" {
+		t.Errorf("incorrect base tree: %v", tree["base_tree"])
+	}
+	entries := tree["tree"].([]interface{})
+	if len(entries) != 3 {
+		t.Fatalf("incorrect number of tree entries: %d", len(entries))
+	}
+	if e := entries[0].(map[string]interface{}); e["content"] != "enabled: true\n" || e["mode"] != FileModeRegular {
+		t.Errorf("incorrect text entry: %v", e)
+	}
+	if e := entries[1].(map[string]interface{}); e["sha"] != "blob-sha" {
+		t.Errorf("incorrect binary entry: %v", e)
+	}
+	if e := entries[2].(map[string]interface{}); e["path"] != "old.yml" {
+		t.Errorf("incorrect delete entry: %v", e)
+	} else if sha, ok := e["sha"]; !ok || sha != nil {
+		t.Errorf("expected delete entry to have a null sha, but got %v", e)
+	}
+
+	if commit["message"] != "Fix configuration" || commit["tree"] != "new-tree" {
+		t.Errorf("incorrect commit: %v", commit)
+	}
+	if pull["head"] != "fix" || pull["base"] != "main" {
+		t.Errorf("incorrect pull request: %v", pull)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultHealthCheckInterval = time.Minute
+
+	// DefaultMaxClockSkew is the largest difference between the local clock
+	// and GitHub's clock that an AppHealthChecker accepts. Application JWTs
+	// tolerate this much skew before GitHub rejects them.
+	DefaultMaxClockSkew = appJWTClockSkew
+)
+
+// AppHealth is the result of checking the application's credentials.
+type AppHealth struct {
+	// Healthy is true if GitHub accepted the credentials and the clock skew
+	// is within the limit.
+	Healthy bool `json:"healthy"`
+
+	// Reachable is true if GitHub responded to the request.
+	Reachable bool `json:"reachable"`
+
+	// Authenticated is true if GitHub accepted the application JWT, which
+	// means the private key and application ID are valid.
+	Authenticated bool `json:"authenticated"`
+
+	// ClockSkew is the difference between GitHub's clock and the local
+	// clock, as reported by the Date header of the response. It is positive
+	// if the local clock is behind. The header has a resolution of one
+	// second.
+	ClockSkew time.Duration `json:"clock_skew"`
+
+	// Error describes why the check failed, if it failed.
+	Error string `json:"error,omitempty"`
+
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// AppHealthOption configures properties of an AppHealthChecker.
+type AppHealthOption func(*AppHealthChecker)
+
+// WithHealthCheckInterval sets how often Run checks the credentials. The
+// default is DefaultHealthCheckInterval.
+func WithHealthCheckInterval(interval time.Duration) AppHealthOption {
+	return func(c *AppHealthChecker) {
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// WithMaxClockSkew sets the largest clock skew the checker considers
+// healthy. The default is DefaultMaxClockSkew.
+func WithMaxClockSkew(skew time.Duration) AppHealthOption {
+	return func(c *AppHealthChecker) {
+		if skew > 0 {
+			c.maxSkew = skew
+		}
+	}
+}
+
+// AppHealthChecker verifies that the application can authenticate with
+// GitHub by requesting the application with an application JWT. Running the
+// check periodically catches expired or rotated private keys and clock drift
+// before they cause webhook handlers to fail.
+//
+// The checker is also an http.Handler that reports the result of the last
+// check as JSON. It responds with status 200 if the application is healthy
+// and 503 otherwise, including before the first check, so it can be used as
+// a readiness probe.
+type AppHealthChecker struct {
+	cc       ClientCreator
+	interval time.Duration
+	maxSkew  time.Duration
+
+	mu   sync.RWMutex
+	last AppHealth
+}
+
+// NewAppHealthChecker creates a checker that uses application clients from cc.
+func NewAppHealthChecker(cc ClientCreator, opts ...AppHealthOption) *AppHealthChecker {
+	c := &AppHealthChecker{
+		cc:       cc,
+		interval: DefaultHealthCheckInterval,
+		maxSkew:  DefaultMaxClockSkew,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Run checks the credentials immediately and then every interval until the
+// context is canceled. Failed checks are logged.
+func (c *AppHealthChecker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if h := c.Check(ctx); !h.Healthy {
+			zerolog.Ctx(ctx).Error().Str("error", h.Error).Msg("GitHub app health check failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks the credentials, records the result, and returns it.
+func (c *AppHealthChecker) Check(ctx context.Context) AppHealth {
+	h := c.check(ctx)
+
+	c.mu.Lock()
+	c.last = h
+	c.mu.Unlock()
+
+	return h
+}
+
+func (c *AppHealthChecker) check(ctx context.Context) AppHealth {
+	h := AppHealth{CheckedAt: GetClock(ctx).Now().UTC()}
+
+	client, err := c.cc.NewAppClient()
+	if err != nil {
+		h.Error = errors.Wrap(err, "failed to create app client").Error()
+		return h
+	}
+
+	_, res, err := client.Apps.Get(ctx, "")
+	if res != nil {
+		h.Reachable = true
+		if date, derr := http.ParseTime(res.Header.Get("Date")); derr == nil {
+			h.ClockSkew = date.Sub(h.CheckedAt).Truncate(time.Second)
+		}
+	}
+
+	var resErr *github.ErrorResponse
+	switch {
+	case err == nil:
+		h.Authenticated = true
+	case errors.As(err, &resErr) && resErr.Response.StatusCode == http.StatusUnauthorized:
+		h.Error = "GitHub rejected the app credentials: " + resErr.Message
+		return h
+	default:
+		h.Error = errors.Wrap(err, "failed to get app").Error()
+		return h
+	}
+
+	if skew := h.ClockSkew.Abs(); skew > c.maxSkew {
+		h.Error = "clock skew of " + h.ClockSkew.String() + " exceeds the maximum of " + c.maxSkew.String()
+		return h
+	}
+
+	h.Healthy = true
+	return h
+}
+
+// Health returns the result of the last check. If there were no checks, it
+// returns an unhealthy result with a zero CheckedAt time.
+func (c *AppHealthChecker) Health() AppHealth {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.last.CheckedAt.IsZero() {
+		return AppHealth{Error: "not checked"}
+	}
+	return c.last
+}
+
+// ServeHTTP responds with the result of the last check.
+func (c *AppHealthChecker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := c.Health()
+
+	w.Header().Set("Content-Type", "application/json")
+	if h.Healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(h)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAppHealthChecker(t *testing.T) {
+	tests := map[string]struct {
+		Status int
+		Offset time.Duration
+
+		Healthy       bool
+		Authenticated bool
+	}{
+		"healthy": {
+			Status:        http.StatusOK,
+			Healthy:       true,
+			Authenticated: true,
+		},
+		"invalidKey": {
+			Status: http.StatusUnauthorized,
+		},
+		"clockSkew": {
+			Status:        http.StatusOK,
+			Offset:        5 * time.Minute,
+			Authenticated: true,
+		},
+		"serverError": {
+			Status: http.StatusBadGateway,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /app", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Date", time.Now().Add(test.Offset).UTC().Format(http.TimeFormat))
+				w.WriteHeader(test.Status)
+				if test.Status == http.StatusOK {
+					_, _ = w.Write([]byte(`{"id": 1, "slug": "test-app"}`))
+				} else {
+					_, _ = w.Write([]byte(`{"message": "failure"}`))
+				}
+			})
+
+			c := NewAppHealthChecker(newStaticClientCreator(t, mux))
+			if h := c.Health(); h.Healthy {
+				t.Fatalf("expected unhealthy status before the first check")
+			}
+
+			h := c.Check(context.Background())
+			if h.Healthy != test.Healthy || h.Authenticated != test.Authenticated || !h.Reachable {
+				t.Errorf("incorrect health: %+v", h)
+			}
+			if !test.Healthy && h.Error == "" {
+				t.Errorf("expected error message for unhealthy result")
+			}
+			if test.Offset > 0 && h.ClockSkew < test.Offset-5*time.Second {
+				t.Errorf("incorrect clock skew: %s", h.ClockSkew)
+			}
+
+			w := httptest.NewRecorder()
+			c.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			expected := http.StatusServiceUnavailable
+			if test.Healthy {
+				expected = http.StatusOK
+			}
+			if w.Code != expected {
+				t.Errorf("incorrect status code: expected %d, actual %d", expected, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultHookAllowlistRefreshInterval = time.Hour
+)
+
+// HookAllowlist checks that webhook requests come from the IP ranges that
+// GitHub publishes for hooks in the /meta API. This protects against spoofed
+// requests even if the webhook secret leaks. The ranges are cached and
+// refreshed in the background when requests are checked, using the clock from
+// the request context to decide when the ranges are stale.
+type HookAllowlist struct {
+	client   *github.Client
+	interval time.Duration
+	sourceIP func(*http.Request) net.IP
+
+	mu         sync.Mutex
+	nets       []*net.IPNet
+	refreshed  time.Time
+	refreshing *hookAllowlistRefresh
+}
+
+// hookAllowlistRefresh is an in-progress refresh of the hook IP ranges,
+// shared by requests that need ranges at the same time.
+type hookAllowlistRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// HookAllowlistOption configures properties of a hook allowlist.
+type HookAllowlistOption func(*HookAllowlist)
+
+// WithHookAllowlistRefreshInterval sets how often the allowlist loads the
+// hook IP ranges from GitHub. The default is
+// DefaultHookAllowlistRefreshInterval.
+func WithHookAllowlistRefreshInterval(interval time.Duration) HookAllowlistOption {
+	return func(a *HookAllowlist) {
+		if interval > 0 {
+			a.interval = interval
+		}
+	}
+}
+
+// WithHookAllowlistSourceIP sets the function that returns the source IP of
+// a request. By default, the allowlist uses the address of the remote end of
+// the connection. Applications behind a proxy or load balancer must set a
+// function that reads the address from a header set by a trusted proxy.
+func WithHookAllowlistSourceIP(sourceIP func(*http.Request) net.IP) HookAllowlistOption {
+	return func(a *HookAllowlist) {
+		if sourceIP != nil {
+			a.sourceIP = sourceIP
+		}
+	}
+}
+
+// NewHookAllowlist creates a HookAllowlist that loads IP ranges with client.
+// The /meta API does not require authentication, so the client may be an
+// application client or an unauthenticated client for the GitHub server.
+func NewHookAllowlist(client *github.Client, opts ...HookAllowlistOption) *HookAllowlist {
+	a := &HookAllowlist{
+		client:   client,
+		interval: DefaultHookAllowlistRefreshInterval,
+		sourceIP: remoteAddrIP,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// WithHookAllowlist sets an allowlist that the dispatcher checks before
+// validating each webhook request. Requests from other sources fail
+// validation.
+func WithHookAllowlist(allowlist *HookAllowlist) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.allowlist = allowlist
+	}
+}
+
+// Refresh loads the current hook IP ranges from GitHub.
+func (a *HookAllowlist) Refresh(ctx context.Context) error {
+	meta, _, err := a.client.Meta.Get(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get GitHub meta information")
+	}
+
+	nets := make([]*net.IPNet, 0, len(meta.Hooks))
+	for _, cidr := range meta.Hooks {
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid hook IP range: %q", cidr)
+		}
+		nets = append(nets, ipnet)
+	}
+	if len(nets) == 0 {
+		return errors.New("GitHub meta information contains no hook IP ranges")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nets = nets
+	a.refreshed = GetClock(ctx).Now()
+	return nil
+}
+
+// Allowed returns true if the request comes from a GitHub hook IP range. If
+// the cached ranges are older than the refresh interval, it uses them while
+// one request refreshes them in the background; if the refresh fails, it
+// keeps using the previous ranges until the next interval. Allowed only waits
+// for GitHub if no ranges were loaded yet and returns an error if that load
+// fails. Concurrent requests share a single load.
+func (a *HookAllowlist) Allowed(r *http.Request) (bool, error) {
+	nets, err := a.currentNets(r.Context())
+	if err != nil {
+		return false, err
+	}
+
+	ip := a.sourceIP(r)
+	if ip == nil {
+		return false, nil
+	}
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *HookAllowlist) currentNets(ctx context.Context) ([]*net.IPNet, error) {
+	a.mu.Lock()
+	nets := a.nets
+	if nets != nil && GetClock(ctx).Now().Sub(a.refreshed) <= a.interval {
+		a.mu.Unlock()
+		return nets, nil
+	}
+
+	refresh := a.refreshing
+	start := refresh == nil
+	if start {
+		refresh = &hookAllowlistRefresh{done: make(chan struct{})}
+		a.refreshing = refresh
+	}
+	a.mu.Unlock()
+
+	if nets != nil {
+		if start {
+			// the refresh must outlive the request that started it
+			go a.refresh(context.WithoutCancel(ctx), refresh)
+		}
+		return nets, nil
+	}
+
+	if start {
+		a.refresh(ctx, refresh)
+	} else {
+		select {
+		case <-refresh.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if refresh.err != nil {
+		return nil, refresh.err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.nets, nil
+}
+
+// refresh loads the ranges and then completes the shared refresh. If loading
+// fails and previous ranges exist, it keeps them until the next interval.
+func (a *HookAllowlist) refresh(ctx context.Context, refresh *hookAllowlistRefresh) {
+	err := a.Refresh(ctx)
+
+	a.mu.Lock()
+	if err != nil && a.nets != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to refresh hook IP ranges, using previous ranges")
+
+		// avoid retrying on every request while GitHub is unavailable
+		a.refreshed = GetClock(ctx).Now()
+	}
+	refresh.err = err
+	a.refreshing = nil
+	a.mu.Unlock()
+
+	close(refresh.done)
+}
+
+func remoteAddrIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func newMetaClient(t *testing.T, fail *atomic.Bool, calls *atomic.Int32) *github.Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if fail.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"hooks": ["192.30.252.0/22", "2a0a:a440::/29"]}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return client
+}
+
+func TestHookAllowlist(t *testing.T) {
+	newRequest := func(remoteAddr string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/api/github/hook", nil)
+		r.RemoteAddr = remoteAddr
+		return r
+	}
+
+	t.Run("checksSource", func(t *testing.T) {
+		var fail atomic.Bool
+		var calls atomic.Int32
+		a := NewHookAllowlist(newMetaClient(t, &fail, &calls))
+
+		tests := map[string]bool{
+			"192.30.252.10:1234":     true,
+			"[2a0a:a440::1]:1234":    true,
+			"10.0.0.1:1234":          false,
+			"invalid":                false,
+			"192.30.255.255:1234":    true,
+			"192.30.256.1:1234":      false,
+			"[2001:db8::1]:1234":     false,
+			"192.30.251.255:1234":    false,
+			"192.30.252.0:65535":     true,
+			"[::ffff:192.30.252.1]:": true,
+		}
+		for addr, expected := range tests {
+			allowed, err := a.Allowed(newRequest(addr))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if allowed != expected {
+				t.Errorf("incorrect result for %q: expected %t, actual %t", addr, expected, allowed)
+			}
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected ranges to be loaded once, but got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("sourceIP", func(t *testing.T) {
+		var fail atomic.Bool
+		var calls atomic.Int32
+		a := NewHookAllowlist(newMetaClient(t, &fail, &calls), WithHookAllowlistSourceIP(func(r *http.Request) net.IP {
+			return net.ParseIP(r.Header.Get("X-Real-IP"))
+		}))
+
+		r := newRequest("10.0.0.1:1234")
+		r.Header.Set("X-Real-IP", "192.30.252.1")
+		if allowed, err := a.Allowed(r); err != nil || !allowed {
+			t.Errorf("expected request to be allowed, but got %t, %v", allowed, err)
+		}
+	})
+
+	t.Run("refreshFailure", func(t *testing.T) {
+		var fail atomic.Bool
+		var calls atomic.Int32
+		clock := newTestClock()
+		a := NewHookAllowlist(newMetaClient(t, &fail, &calls), WithHookAllowlistRefreshInterval(time.Minute))
+		newClockRequest := func(addr string) *http.Request {
+			r := newRequest(addr)
+			return r.WithContext(WithClock(r.Context(), clock))
+		}
+
+		fail.Store(true)
+		if _, err := a.Allowed(newClockRequest("192.30.252.1:1234")); err == nil {
+			t.Fatal("expected error when ranges were never loaded")
+		}
+
+		fail.Store(false)
+		if allowed, err := a.Allowed(newClockRequest("192.30.252.1:1234")); err != nil || !allowed {
+			t.Fatalf("expected request to be allowed, but got %t, %v", allowed, err)
+		}
+
+		fail.Store(true)
+		clock.Advance(2 * time.Minute)
+		before := calls.Load()
+		if allowed, err := a.Allowed(newClockRequest("192.30.252.1:1234")); err != nil || !allowed {
+			t.Errorf("expected previous ranges to be used, but got %t, %v", allowed, err)
+		}
+		waitForRefresh(t, a)
+		if calls.Load() != before+1 {
+			t.Errorf("expected ranges to be refreshed once after the interval, but got %d calls", calls.Load()-before)
+		}
+
+		if allowed, err := a.Allowed(newClockRequest("192.30.252.1:1234")); err != nil || !allowed {
+			t.Errorf("expected previous ranges to be used, but got %t, %v", allowed, err)
+		}
+		if calls.Load() != before+1 {
+			t.Error("expected failed refresh not to be retried before the next interval")
+		}
+	})
+
+	t.Run("concurrentLoad", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			<-release
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"hooks": ["192.30.252.0/22"]}`)
+		}))
+		t.Cleanup(srv.Close)
+
+		client := github.NewClient(nil)
+		client.BaseURL, _ = url.Parse(srv.URL + "/")
+		a := NewHookAllowlist(client)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if allowed, err := a.Allowed(newRequest("192.30.252.1:1234")); err != nil || !allowed {
+					errs <- fmt.Errorf("expected request to be allowed, but got %t, %v", allowed, err)
+				}
+			}()
+		}
+
+		// wait for the first load to start before releasing it
+		for calls.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			t.Error(err)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("expected concurrent requests to share one load, but got %d calls", calls.Load())
+		}
+	})
+
+	t.Run("dispatcher", func(t *testing.T) {
+		var fail atomic.Bool
+		var calls atomic.Int32
+		a := NewHookAllowlist(newMetaClient(t, &fail, &calls))
+
+		h := &TestEventHandler{Types: []string{"pull_request"}}
+		d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithHookAllowlist(a))
+
+		req := newHookRequest("pull_request", "id", true)
+		req.RemoteAddr = "10.0.0.1:1234"
+
+		w := httptest.NewRecorder()
+		d.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, but got %d", w.Code)
+		}
+		if h.Count != 0 {
+			t.Errorf("expected handler not to be called")
+		}
+	})
+}
+
+// waitForRefresh waits for a background refresh of the allowlist to finish.
+func waitForRefresh(t *testing.T, a *HookAllowlist) {
+	a.mu.Lock()
+	refresh := a.refreshing
+	a.mu.Unlock()
+
+	if refresh != nil {
+		select {
+		case <-refresh.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for allowlist refresh")
+		}
+	}
+}
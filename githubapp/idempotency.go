@@ -0,0 +1,132 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// DeliveryMarker returns the hidden marker that CreateIssueCommentOnce adds
+// to comments created for a delivery. The marker is an HTML comment, so it
+// does not appear in rendered Markdown.
+func DeliveryMarker(deliveryID string) string {
+	return fmt.Sprintf("<!-- githubapp-delivery-id: %s -->", deliveryID)
+}
+
+// deliveryID returns the ID of the delivery in the context or an error if
+// the context does not contain a delivery
+func deliveryID(ctx context.Context) (string, error) {
+	d, ok := GetDelivery(ctx)
+	if !ok || d.DeliveryID == "" {
+		return "", errors.New("context does not contain a delivery")
+	}
+	return d.DeliveryID, nil
+}
+
+// CreateIssueCommentOnce creates a comment on an issue or pull request for
+// the delivery in the context, unless a comment for the delivery already
+// exists. The comment contains a hidden marker with the delivery ID, which
+// does not change when GitHub redelivers an event or when a handler retries,
+// so handlers can create comments without posting duplicates. It returns the
+// new or existing comment and true if it created the comment.
+//
+// Each delivery has at most one comment per issue. It returns an error if
+// the context does not contain a delivery, as set by the event dispatcher
+// and Dispatch.Execute.
+func CreateIssueCommentOnce(ctx context.Context, client *github.Client, owner, repo string, number int, body string) (*github.IssueComment, bool, error) {
+	id, err := deliveryID(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	marker := DeliveryMarker(id)
+
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		comments, res, err := client.Issues.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to list comments on %s/%s#%d", owner, repo, number)
+		}
+		for _, c := range comments {
+			if strings.Contains(c.GetBody(), marker) {
+				return c, false, nil
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		opts.Page = res.NextPage
+	}
+
+	comment, _, err := client.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{
+		Body: github.String(body + "\n\n" + marker),
+	})
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to create comment on %s/%s#%d", owner, repo, number)
+	}
+	return comment, true, nil
+}
+
+// CreateCheckRunOnce creates a check run for the delivery in the context,
+// unless a run with the same name for the same commit and delivery already
+// exists. If opts does not set an external ID, the run uses the delivery ID,
+// which does not change when GitHub redelivers an event or when a handler
+// retries. Otherwise, the existing external ID identifies the run. It returns
+// the new or existing run and true if it created the run.
+//
+// It returns an error if the context does not contain a delivery, as set by
+// the event dispatcher and Dispatch.Execute.
+func CreateCheckRunOnce(ctx context.Context, client *github.Client, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, bool, error) {
+	if opts.ExternalID == nil {
+		id, err := deliveryID(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		opts.ExternalID = github.String(id)
+	}
+
+	listOpts := &github.ListCheckRunsOptions{
+		CheckName:   github.String(opts.Name),
+		Filter:      github.String("all"),
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		res, resp, err := client.Checks.ListCheckRunsForRef(ctx, owner, repo, opts.HeadSHA, listOpts)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "failed to list check runs for %s in %s/%s", opts.HeadSHA, owner, repo)
+		}
+		for _, run := range res.CheckRuns {
+			if run.GetName() == opts.Name && run.GetExternalID() == *opts.ExternalID {
+				return run, false, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		listOpts.Page = resp.NextPage
+	}
+
+	run, _, err := client.Checks.CreateCheckRun(ctx, owner, repo, opts)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to create check run %q for %s in %s/%s", opts.Name, opts.HeadSHA, owner, repo)
+	}
+	return run, true, nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestCreateIssueCommentOnce(t *testing.T) {
+	var comments []*github.IssueComment
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(comments)
+	})
+	mux.HandleFunc("POST /repos/octo/repo/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		var c github.IssueComment
+		if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+			t.Fatalf("invalid comment: %v", err)
+		}
+		c.ID = github.Int64(int64(len(comments) + 1))
+		comments = append(comments, &c)
+		_ = json.NewEncoder(w).Encode(c)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	if _, _, err := CreateIssueCommentOnce(context.Background(), cc.client, "octo", "repo", 1, "hello"); err == nil {
+		t.Error("expected error for context without a delivery, but got nil")
+	}
+
+	ctx := WithDelivery(context.Background(), Delivery{EventType: "issues", DeliveryID: "delivery-1"})
+	for i, expected := range []bool{true, false} {
+		c, created, err := CreateIssueCommentOnce(ctx, cc.client, "octo", "repo", 1, "hello")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created != expected {
+			t.Errorf("call %d: incorrect created flag: expected %t, actual %t", i, expected, created)
+		}
+		if c.GetID() != 1 || !strings.HasPrefix(c.GetBody(), "hello\n\n"+DeliveryMarker("delivery-1")) {
+			t.Errorf("call %d: incorrect comment: %d %q", i, c.GetID(), c.GetBody())
+		}
+	}
+
+	ctx = WithDelivery(context.Background(), Delivery{EventType: "issues", DeliveryID: "delivery-2"})
+	if _, created, err := CreateIssueCommentOnce(ctx, cc.client, "octo", "repo", 1, "hello"); err != nil || !created {
+		t.Errorf("expected new comment for a different delivery: created=%t, err=%v", created, err)
+	}
+}
+
+func TestCreateCheckRunOnce(t *testing.T) {
+	var runs []*github.CheckRun
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/commits/abc/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("check_name") != "lint" {
+			t.Errorf("incorrect check name filter: %s", r.URL.Query().Get("check_name"))
+		}
+		_ = json.NewEncoder(w).Encode(github.ListCheckRunsResults{Total: github.Int(len(runs)), CheckRuns: runs})
+	})
+	mux.HandleFunc("POST /repos/octo/repo/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		var opts github.CreateCheckRunOptions
+		if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+			t.Fatalf("invalid check run: %v", err)
+		}
+		run := &github.CheckRun{
+			ID:         github.Int64(int64(len(runs) + 1)),
+			Name:       github.String(opts.Name),
+			HeadSHA:    github.String(opts.HeadSHA),
+			ExternalID: opts.ExternalID,
+		}
+		runs = append(runs, run)
+		_ = json.NewEncoder(w).Encode(run)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	ctx := WithDelivery(context.Background(), Delivery{EventType: "check_suite", DeliveryID: "delivery-1"})
+	opts := github.CreateCheckRunOptions{Name: "lint", HeadSHA: "abc"}
+
+	tests := []struct {
+		Ctx      context.Context
+		Opts     github.CreateCheckRunOptions
+		Created  bool
+		Expected int64
+	}{
+		{Ctx: ctx, Opts: opts, Created: true, Expected: 1},
+		{Ctx: ctx, Opts: opts, Created: false, Expected: 1},
+		{Ctx: WithDelivery(context.Background(), Delivery{DeliveryID: "delivery-2"}), Opts: opts, Created: true, Expected: 2},
+		{Ctx: context.Background(), Opts: github.CreateCheckRunOptions{Name: "lint", HeadSHA: "abc", ExternalID: github.String("delivery-1")}, Created: false, Expected: 1},
+	}
+
+	for i, test := range tests {
+		run, created, err := CreateCheckRunOnce(test.Ctx, cc.client, "octo", "repo", test.Opts)
+		if err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+		if created != test.Created || run.GetID() != test.Expected {
+			t.Errorf("call %d: incorrect result: created=%t, id=%d", i, created, run.GetID())
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// identityLookupTimeout limits the request that looks up the app slug
+	// for the identity log
+	identityLookupTimeout = 10 * time.Second
+)
+
+// WithIdentityLog logs the identity of the application once when the
+// creator is created: the app ID, the fingerprint of the private key, as
+// returned by PrivateKeyFingerprint, and the API URLs. This answers which
+// app and key a running process uses without exposing the key.
+//
+// The creator also looks up the app slug with an application client, so the
+// entry is written in the background after the lookup completes. If the
+// lookup fails, the entry is a warning that includes the error, which often
+// points to an invalid key or app ID.
+func WithIdentityLog(logger zerolog.Logger) ClientOption {
+	return func(c *clientCreator) {
+		c.identityLogger = &logger
+	}
+}
+
+func (c *clientCreator) logIdentity(logger zerolog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), identityLookupTimeout)
+	defer cancel()
+
+	var slug string
+	client, err := c.NewAppClient()
+	if err == nil {
+		app, _, getErr := client.Apps.Get(ctx, "")
+		if getErr != nil {
+			err = errors.Wrap(getErr, "failed to get app")
+		}
+		slug = app.GetSlug()
+	}
+
+	ev := logger.Info()
+	if err != nil {
+		ev = logger.Warn().Err(err)
+	}
+
+	fingerprint, fpErr := PrivateKeyFingerprint(c.privKeyBytes)
+	if fpErr != nil {
+		fingerprint = RedactedValue
+	}
+
+	ev.Int64(LogKeyAppID, c.integrationID).
+		Str(LogKeyAppSlug, slug).
+		Str(LogKeyKeyFingerprint, fingerprint).
+		Str(LogKeyV3APIURL, c.v3BaseURL).
+		Str(LogKeyV4APIURL, c.v4BaseURL).
+		Str(LogKeyUploadURL, c.uploadURL).
+		Msg("Using GitHub app")
+}
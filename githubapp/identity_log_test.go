@@ -0,0 +1,98 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type chanWriter chan []byte
+
+func (w chanWriter) Write(b []byte) (int, error) {
+	w <- append([]byte(nil), b...)
+	return len(b), nil
+}
+
+func TestWithIdentityLog(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"id": 4, "slug": "octo-app"}`)
+	})
+	mux.HandleFunc("GET /broken/app", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	key := newTestPrivateKey(t)
+	fingerprint, err := PrivateKeyFingerprint(key)
+	if err != nil {
+		t.Fatalf("unexpected error computing fingerprint: %v", err)
+	}
+
+	tests := map[string]struct {
+		BaseURL string
+
+		Level string
+		Slug  string
+	}{
+		"found": {
+			BaseURL: srv.URL,
+			Level:   "info",
+			Slug:    "octo-app",
+		},
+		"rejected": {
+			BaseURL: srv.URL + "/broken",
+			Level:   "warn",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := make(chanWriter, 1)
+			_ = NewClientCreator(test.BaseURL, srv.URL+"/graphql", 4, key, WithIdentityLog(zerolog.New(out)))
+
+			var entry map[string]interface{}
+			select {
+			case b := <-out:
+				if err := json.Unmarshal(b, &entry); err != nil {
+					t.Fatalf("failed to parse log entry: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("identity was not logged")
+			}
+
+			if entry["level"] != test.Level {
+				t.Errorf("incorrect level: %v", entry["level"])
+			}
+			if entry[LogKeyAppID] != float64(4) || entry[LogKeyAppSlug] != test.Slug {
+				t.Errorf("incorrect app identity: %v", entry)
+			}
+			if entry[LogKeyKeyFingerprint] != fingerprint {
+				t.Errorf("incorrect fingerprint: %v", entry[LogKeyKeyFingerprint])
+			}
+			if entry[LogKeyV3APIURL] != test.BaseURL+"/" || entry[LogKeyV4APIURL] != srv.URL+"/graphql" {
+				t.Errorf("incorrect API URLs: %v", entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultInstallStateTTL = time.Hour
+
+	// InstallStateCookie is the name of the cookie that binds states created
+	// by InstallStateSigner.SignRequest to the user's browser.
+	InstallStateCookie = "githubapp-install-state"
+)
+
+// InstallationURLOptions are optional parameters for an installation URL.
+type InstallationURLOptions struct {
+	// State is returned unmodified in the "state" parameter of the request to
+	// the application's setup URL.
+	State string
+
+	// TargetID is the ID of the user or organization account to select for
+	// the installation.
+	TargetID int64
+}
+
+// InstallationURL returns the URL where users install the application with
+// the given slug. The webURL is the base URL of the GitHub server, like
+// "https://github.com" or the URL of a GitHub Enterprise Server instance. If
+// empty, it uses github.com.
+func InstallationURL(webURL, slug string, opts InstallationURLOptions) (string, error) {
+	if webURL == "" {
+		webURL = "https://github.com"
+	}
+
+	u, err := url.Parse(webURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse web URL: %q", webURL)
+	}
+
+	// GitHub Enterprise Server uses a different path prefix for apps
+	prefix := "github-apps"
+	if u.Host == "github.com" {
+		prefix = "apps"
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + prefix + "/" + url.PathEscape(slug) + "/installations/new"
+	q := url.Values{}
+	if opts.TargetID > 0 {
+		u.Path += "/permissions"
+		q.Set("target_id", strconv.FormatInt(opts.TargetID, 10))
+	}
+	if opts.State != "" {
+		q.Set("state", opts.State)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// InstallStateSigner creates and verifies signed state values for
+// installation URLs. A state carries an application-defined value, like the
+// ID of the tenant that started the installation, and expires after a fixed
+// time. Because the state is signed, the setup URL handler can trust the
+// value even though it passes through the user's browser.
+//
+// States created by SignRequest are also bound to the browser that started
+// the installation with a cookie, so an attacker cannot send another user to
+// the setup URL with a state the attacker obtained. Use SignRequest for
+// installations started in a browser and Sign only when the state cannot be
+// bound to a browser.
+//
+// InstallStateSigner implements SetupStateVerifier.
+type InstallStateSigner struct {
+	secret []byte
+	ttl    time.Duration
+	clock  Clock
+}
+
+// InstallStateOption configures properties of an InstallStateSigner.
+type InstallStateOption func(*InstallStateSigner)
+
+// WithInstallStateClock sets the clock used to set and check the expiration
+// of states. The default is SystemClock.
+func WithInstallStateClock(clock Clock) InstallStateOption {
+	return func(s *InstallStateSigner) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
+}
+
+// NewInstallStateSigner creates a signer that uses secret for signatures and
+// creates states that expire after ttl. If ttl is not positive, states
+// expire after DefaultInstallStateTTL. It panics if secret is empty, because
+// anyone could forge states signed with an empty secret.
+func NewInstallStateSigner(secret string, ttl time.Duration, opts ...InstallStateOption) *InstallStateSigner {
+	if secret == "" {
+		panic("NewInstallStateSigner: secret must not be empty")
+	}
+	if ttl <= 0 {
+		ttl = DefaultInstallStateTTL
+	}
+
+	s := &InstallStateSigner{
+		secret: []byte(secret),
+		ttl:    ttl,
+		clock:  SystemClock,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Sign returns a state that contains value. The state is not bound to a
+// browser; prefer SignRequest for installations started in a browser.
+func (s *InstallStateSigner) Sign(value string) string {
+	return s.sign(value, "")
+}
+
+// SignRequest returns a state that contains value and sets a cookie on w
+// that binds the state to the browser that receives the response. Call it
+// in the handler that redirects the user to the installation URL.
+func (s *InstallStateSigner) SignRequest(w http.ResponseWriter, value string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate state nonce")
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     InstallStateCookie,
+		Value:    nonce,
+		Path:     "/",
+		MaxAge:   int(s.ttl.Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		// the setup URL is reached by a top-level redirect from GitHub
+		SameSite: http.SameSiteLaxMode,
+	})
+	return s.sign(value, nonce), nil
+}
+
+func (s *InstallStateSigner) sign(value, nonce string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(value)) + "." + strconv.FormatInt(s.clock.Now().Add(s.ttl).Unix(), 10)
+	if nonce != "" {
+		payload += "." + nonce
+	}
+	return payload + "." + s.signature(payload)
+}
+
+// Value returns the value of a state created by Sign or SignRequest. It
+// returns ErrInvalidSetupState if the state is malformed, has an invalid
+// signature, or is expired. It does not check that the state is bound to the
+// current browser; use VerifyState for that.
+func (s *InstallStateSigner) Value(state string) (string, error) {
+	value, _, err := s.parse(state)
+	return value, err
+}
+
+// parse returns the value and the nonce of a state. The nonce is empty for
+// states created by Sign.
+func (s *InstallStateSigner) parse(state string) (string, string, error) {
+	i := strings.LastIndexByte(state, '.')
+	if i < 0 {
+		return "", "", ErrInvalidSetupState
+	}
+	payload, sig := state[:i], state[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.signature(payload))) {
+		return "", "", ErrInvalidSetupState
+	}
+
+	parts := strings.Split(payload, ".")
+	if len(parts) != 2 && len(parts) != 3 {
+		return "", "", ErrInvalidSetupState
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || s.clock.Now().Unix() > expiry {
+		return "", "", ErrInvalidSetupState
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", ErrInvalidSetupState
+	}
+
+	var nonce string
+	if len(parts) == 3 {
+		nonce = parts[2]
+	}
+	return string(value), nonce, nil
+}
+
+// VerifyState returns true if state has a valid signature and is not
+// expired. If the state was created by SignRequest, the request must also
+// have the cookie set when the state was created.
+func (s *InstallStateSigner) VerifyState(r *http.Request, state string) (bool, error) {
+	_, nonce, err := s.parse(state)
+	if err != nil {
+		return false, nil
+	}
+	if nonce == "" {
+		return true, nil
+	}
+	if r == nil {
+		return false, nil
+	}
+
+	cookie, err := r.Cookie(InstallStateCookie)
+	if err != nil {
+		return false, nil
+	}
+	return hmac.Equal([]byte(cookie.Value), []byte(nonce)), nil
+}
+
+func (s *InstallStateSigner) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
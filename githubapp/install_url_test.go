@@ -0,0 +1,136 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestInstallationURL(t *testing.T) {
+	tests := map[string]struct {
+		WebURL   string
+		Options  InstallationURLOptions
+		Expected string
+	}{
+		"github": {
+			Expected: "https://github.com/apps/my-app/installations/new",
+		},
+		"enterprise": {
+			WebURL:   "https://github.company.domain/",
+			Expected: "https://github.company.domain/github-apps/my-app/installations/new",
+		},
+		"stateAndTarget": {
+			WebURL:   "https://github.com",
+			Options:  InstallationURLOptions{State: "a b", TargetID: 42},
+			Expected: "https://github.com/apps/my-app/installations/new/permissions?state=a+b&target_id=42",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, err := InstallationURL(test.WebURL, "my-app", test.Options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if u != test.Expected {
+				t.Errorf("incorrect URL\nexpected: %s\n  actual: %s", test.Expected, u)
+			}
+		})
+	}
+}
+
+func TestInstallStateSigner(t *testing.T) {
+	s := NewInstallStateSigner("secret", time.Hour)
+
+	state := s.Sign("tenant-1")
+	value, err := s.Value(state)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value != "tenant-1" {
+		t.Errorf("incorrect value: %q", value)
+	}
+
+	if ok, _ := s.VerifyState(nil, state); !ok {
+		t.Error("expected state to be valid")
+	}
+
+	invalid := map[string]string{
+		"otherSecret": NewInstallStateSigner("other", time.Hour).Sign("tenant-1"),
+		"modified":    "dGVuYW50LTI" + state[len("dGVuYW50LTE"):],
+		"malformed":   "not-a-state",
+		"empty":       "",
+	}
+
+	clock := newTestClock()
+	expiring := NewInstallStateSigner("secret", time.Hour, WithInstallStateClock(clock))
+	invalid["expired"] = expiring.Sign("tenant-1")
+	if _, err := expiring.Value(invalid["expired"]); err != nil {
+		t.Fatalf("unexpected error before expiration: %v", err)
+	}
+	clock.Advance(time.Hour + time.Second)
+
+	for name, state := range invalid {
+		if _, err := expiring.Value(state); !errors.Is(err, ErrInvalidSetupState) {
+			t.Errorf("%s: expected ErrInvalidSetupState, but got %v", name, err)
+		}
+	}
+}
+
+func TestInstallStateSignerEmptySecret(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for empty secret")
+		}
+	}()
+	NewInstallStateSigner("", time.Hour)
+}
+
+func TestInstallStateSignerRequest(t *testing.T) {
+	s := NewInstallStateSigner("secret", time.Hour)
+
+	w := httptest.NewRecorder()
+	state, err := s.SignRequest(w, "tenant-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, err := s.Value(state); err != nil || value != "tenant-1" {
+		t.Errorf("incorrect value: %q, %v", value, err)
+	}
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != InstallStateCookie {
+		t.Fatalf("incorrect cookies: %v", cookies)
+	}
+
+	r := httptest.NewRequest("GET", "/setup", nil)
+	r.AddCookie(cookies[0])
+	if ok, _ := s.VerifyState(r, state); !ok {
+		t.Error("expected state to be valid with the cookie")
+	}
+
+	if ok, _ := s.VerifyState(httptest.NewRequest("GET", "/setup", nil), state); ok {
+		t.Error("expected state to be invalid without the cookie")
+	}
+
+	other, _ := s.SignRequest(httptest.NewRecorder(), "tenant-1")
+	if ok, _ := s.VerifyState(r, other); ok {
+		t.Error("expected state to be invalid with the cookie of another state")
+	}
+}
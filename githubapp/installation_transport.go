@@ -0,0 +1,130 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxUnauthorizedBodySize is the largest 401 response body read to check
+	// for a revoked token.
+	maxUnauthorizedBodySize = 64 << 10
+)
+
+// refreshingTransport authenticates requests as an installation. It replaces
+// the installation token when the clock from the request context reaches the
+// token's refresh time, so tests can expire tokens with a fake clock. If
+// GitHub rejects the installation token, which happens when the token is
+// revoked before it expires, the transport discards the token and retries the
+// request once with a new token if the request's RetryBudget allows it.
+type refreshingTransport struct {
+	newTransport func() (*ghinstallation.Transport, error)
+	registry     metrics.Registry
+
+	mu  sync.Mutex
+	itr *ghinstallation.Transport
+}
+
+func newRefreshingTransport(itr *ghinstallation.Transport, newTransport func() (*ghinstallation.Transport, error)) *refreshingTransport {
+	return &refreshingTransport{
+		newTransport: newTransport,
+		itr:          itr,
+	}
+}
+
+func (t *refreshingTransport) current() *ghinstallation.Transport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.itr
+}
+
+// invalidate replaces the transport if it is still the transport that failed.
+// Concurrent requests that fail with the same token only replace it once.
+func (t *refreshingTransport) invalidate(failed *ghinstallation.Transport) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.itr != failed {
+		return nil
+	}
+
+	itr, err := t.newTransport()
+	if err != nil {
+		return err
+	}
+	t.itr = itr
+	return nil
+}
+
+func (t *refreshingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	now := GetClock(r.Context()).Now()
+
+	itr := t.current()
+	if _, refreshAt, err := itr.Expiry(); err == nil && !now.Before(refreshAt) {
+		if err := t.invalidate(itr); err != nil {
+			return nil, err
+		}
+		itr = t.current()
+	}
+	recordTokenUse(t.registry, itr, now)
+
+	res, err := itr.RoundTrip(r)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	// only retry requests with bodies that can be sent again
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return res, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxUnauthorizedBodySize))
+	closeBody(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !bytes.Contains(body, []byte("Bad credentials")) {
+		return res, nil
+	}
+
+	logger := zerolog.Ctx(r.Context())
+	if !GetRetryBudget(r.Context()).Acquire() {
+		logger.Debug().Msg("Installation token was rejected, but the retry budget is exhausted")
+		return res, nil
+	}
+	if err := t.invalidate(itr); err != nil {
+		logger.Warn().Err(err).Msg("Failed to replace rejected installation token")
+		return res, nil
+	}
+	logger.Debug().Msg("Installation token was rejected, retrying request with a new token")
+
+	retry := r.Clone(r.Context())
+	if r.GetBody != nil {
+		if retry.Body, err = r.GetBody(); err != nil {
+			return res, nil
+		}
+	}
+	return t.current().RoundTrip(retry)
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func newTestPrivateKey(t *testing.T) []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func TestInstallationTokenRetry(t *testing.T) {
+	tests := map[string]struct {
+		Revoked []string
+		Budget  *RetryBudget
+
+		Err      bool
+		Tokens   int
+		Requests int
+	}{
+		"validToken": {
+			Tokens:   1,
+			Requests: 1,
+		},
+		"revokedToken": {
+			Revoked:  []string{"token-1"},
+			Tokens:   2,
+			Requests: 2,
+		},
+		"exhaustedBudget": {
+			Revoked:  []string{"token-1"},
+			Budget:   NewRetryBudget(0),
+			Err:      true,
+			Tokens:   1,
+			Requests: 1,
+		},
+		"retriesOnce": {
+			Revoked:  []string{"token-1", "token-2"},
+			Err:      true,
+			Tokens:   2,
+			Requests: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var tokens int
+			var bodies []string
+
+			revoked := make(map[string]bool)
+			for _, token := range test.Revoked {
+				revoked["token "+token] = true
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				tokens++
+				token := fmt.Sprintf("token-%d", tokens)
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprintf(w, `{"token": %q, "expires_at": %q}`, token, time.Now().Add(time.Hour).Format(time.RFC3339))
+			})
+			mux.HandleFunc("POST /repos/octo/repo/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+
+				mu.Lock()
+				bodies = append(bodies, string(b))
+				mu.Unlock()
+
+				w.Header().Set("Content-Type", "application/json")
+				if revoked[r.Header.Get("Authorization")] {
+					w.WriteHeader(http.StatusUnauthorized)
+					fmt.Fprint(w, `{"message": "Bad credentials"}`)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"id": 1}`)
+			})
+
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			cc := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t))
+			client, err := cc.NewInstallationClient(1)
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+
+			ctx := context.Background()
+			if test.Budget != nil {
+				ctx = WithRetryBudget(ctx, test.Budget)
+			}
+
+			_, _, err = client.Issues.CreateComment(ctx, "octo", "repo", 1, &github.IssueComment{
+				Body: github.String("hello"),
+			})
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tokens != test.Tokens {
+				t.Errorf("expected %d tokens to be created, but got %d", test.Tokens, tokens)
+			}
+			if len(bodies) != test.Requests {
+				t.Fatalf("expected %d requests, but got %d", test.Requests, len(bodies))
+			}
+			for _, b := range bodies {
+				if b != bodies[0] {
+					t.Errorf("expected retries to send the same body, but got %q", bodies)
+				}
+			}
+		})
+	}
+}
+
+func TestInstallationTokenExpiry(t *testing.T) {
+	var tokens int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, tokens, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /repos/octo/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cc := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t))
+	client, err := cc.NewInstallationClient(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	clock := newTestClock()
+	clock.now = time.Now()
+	ctx := WithClock(context.Background(), clock)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Repositories.Get(ctx, "octo", "repo"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if tokens != 1 {
+		t.Fatalf("expected token to be reused before it expires, but got %d tokens", tokens)
+	}
+
+	clock.Advance(time.Hour)
+	if _, _, err := client.Repositories.Get(ctx, "octo", "repo"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tokens != 2 {
+		t.Errorf("expected a new token after the clock passed the expiry, but got %d tokens", tokens)
+	}
+}
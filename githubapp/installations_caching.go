@@ -22,10 +22,25 @@ import (
 	ttlcache "github.com/patrickmn/go-cache"
 )
 
+// InstallationsInvalidator is implemented by InstallationsServices that cache
+// installations. Use a type assertion to access it from an
+// InstallationsService.
+type InstallationsInvalidator interface {
+	RepositoryCacheInvalidator
+
+	// InvalidateOwner removes the cached installation for an owner.
+	InvalidateOwner(owner string)
+
+	// InvalidateInstallation removes all cached owners and repositories that
+	// map to an installation.
+	InvalidateInstallation(installationID int64)
+}
+
 // NewCachingInstallationsService returns an InstallationsService that always queries GitHub. It should be created with
 // a client that authenticates as the target.
 // It uses a time based cache of the provided expiry/cleanup time to store app installation info for repositories
-// or owners and returns the cached installation info when a cache hit exists.
+// or owners and returns the cached installation info when a cache hit exists. The returned service implements
+// InstallationsInvalidator.
 func NewCachingInstallationsService(delegate InstallationsService, expiry, cleanup time.Duration) InstallationsService {
 	return &cachingInstallationsService{
 		cache:    ttlcache.New(expiry, cleanup),
@@ -76,3 +91,19 @@ func (c *cachingInstallationsService) GetByRepository(ctx context.Context, owner
 	c.cache.Set(key, install, ttlcache.DefaultExpiration)
 	return install, nil
 }
+
+func (c *cachingInstallationsService) InvalidateOwner(owner string) {
+	c.cache.Delete(owner)
+}
+
+func (c *cachingInstallationsService) InvalidateRepository(owner, name string) {
+	c.cache.Delete(fmt.Sprintf("%s/%s", owner, name))
+}
+
+func (c *cachingInstallationsService) InvalidateInstallation(installationID int64) {
+	for key, item := range c.cache.Items() {
+		if install, ok := item.Object.(Installation); ok && install.ID == installationID {
+			c.cache.Delete(key)
+		}
+	}
+}
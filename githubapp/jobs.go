@@ -0,0 +1,293 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+)
+
+const (
+	MetricsKeyJobRuns     = "github.job.runs"
+	MetricsKeyJobErrors   = "github.job.errors"
+	MetricsKeyJobSkipped  = "github.job.skipped"
+	MetricsKeyJobDuration = "github.job.duration"
+)
+
+const (
+	LogKeyJob string = "github_job"
+)
+
+const (
+	DefaultJobConcurrency = 4
+)
+
+// JobFunc is the work a Job performs for a single installation. The client
+// is an installation client for the installation.
+type JobFunc func(ctx context.Context, installation Installation, client *github.Client) error
+
+// JobErrorCallback is called when a JobFunc returns an error or panics for
+// an installation. If the function panics, err is a HandlerPanicError.
+type JobErrorCallback func(ctx context.Context, installation Installation, err error)
+
+// DefaultJobErrorCallback logs errors.
+func DefaultJobErrorCallback(ctx context.Context, installation Installation, err error) {
+	zerolog.Ctx(ctx).Error().Err(err).Msg("Unexpected error running job")
+}
+
+// JobOption configures properties of a Job.
+type JobOption func(*Job)
+
+// WithJobConcurrency sets the maximum number of installations a job
+// processes at the same time. The default is DefaultJobConcurrency.
+func WithJobConcurrency(n int) JobOption {
+	return func(j *Job) {
+		if n > 0 {
+			j.concurrency = n
+		}
+	}
+}
+
+// WithJobFilter sets a function that selects the installations a job runs
+// for. By default, a job runs for all installations of the app.
+func WithJobFilter(filter func(Installation) bool) JobOption {
+	return func(j *Job) {
+		j.filter = filter
+	}
+}
+
+// WithJobInstallations sets the service a job uses to list installations.
+// By default, the job creates an InstallationsService with an app client
+// each time it runs.
+func WithJobInstallations(installations InstallationsService) JobOption {
+	return func(j *Job) {
+		j.installations = installations
+	}
+}
+
+// WithJobRateLimits makes a job skip installations with fewer than
+// minRemaining requests left in their core rate limit, as recorded by the
+// tracker. Skipped installations are processed again in the next run, after
+// their rate limit resets. The tracker's middleware must be registered with
+// the job's ClientCreator.
+func WithJobRateLimits(tracker *RateLimitTracker, minRemaining int) JobOption {
+	return func(j *Job) {
+		j.rateLimits = tracker
+		j.minRemaining = minRemaining
+	}
+}
+
+// WithJobErrorCallback sets the function called when the job fails for an
+// installation. The default is DefaultJobErrorCallback.
+func WithJobErrorCallback(onError JobErrorCallback) JobOption {
+	return func(j *Job) {
+		if onError != nil {
+			j.onError = onError
+		}
+	}
+}
+
+// WithJobMetrics enables metrics reporting for a job. Metric names include
+// the name of the job.
+func WithJobMetrics(r metrics.Registry) JobOption {
+	return func(j *Job) {
+		j.registry = r
+	}
+}
+
+// Job runs a function periodically for every installation of an app, in
+// addition to the webhooks an app handles. This is useful for periodic
+// sweeps, like reminding reviewers of stale pull requests or auditing
+// repository configuration.
+type Job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+	cc       ClientCreator
+
+	concurrency   int
+	filter        func(Installation) bool
+	installations InstallationsService
+	rateLimits    *RateLimitTracker
+	minRemaining  int
+	onError       JobErrorCallback
+	registry      metrics.Registry
+}
+
+// NewJob creates a Job with the given name that calls fn for each
+// installation every interval. The name identifies the job in logs and
+// metrics. The job uses cc to create clients; use a caching ClientCreator to
+// reuse clients between runs.
+func NewJob(name string, interval time.Duration, cc ClientCreator, fn JobFunc, opts ...JobOption) *Job {
+	j := &Job{
+		name:        name,
+		interval:    interval,
+		fn:          fn,
+		cc:          cc,
+		concurrency: DefaultJobConcurrency,
+		onError:     DefaultJobErrorCallback,
+	}
+
+	for _, opt := range opts {
+		opt(j)
+	}
+
+	return j
+}
+
+// Start runs the job immediately and then every interval until ctx is
+// canceled. It blocks until ctx is canceled and returns the context error.
+// Runs never overlap: if a run takes longer than the interval, the next run
+// starts when it finishes. Start measures the interval with the clock from
+// ctx. If the interval of the job is not positive, Start returns an error
+// without running the job.
+func (j *Job) Start(ctx context.Context) error {
+	if j.interval <= 0 {
+		return errors.Errorf("job %q must have a positive interval, got %v", j.name, j.interval)
+	}
+
+	clock := GetClock(ctx)
+	for {
+		start := clock.Now()
+		if err := j.Run(ctx); err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Str(LogKeyJob, j.name).Msg("Failed to run job")
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := sleepContext(ctx, start.Add(j.interval).Sub(clock.Now())); err != nil {
+			return err
+		}
+	}
+}
+
+// Run runs the job once for all selected installations and waits for it to
+// finish. It only returns an error if it cannot list installations; failures
+// for individual installations are reported to the job's error callback.
+func (j *Job) Run(ctx context.Context) error {
+	start := GetClock(ctx).Now()
+
+	installations, err := j.listInstallations(ctx)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, j.concurrency)
+
+	for _, installation := range installations {
+		if j.filter != nil && !j.filter(installation) {
+			continue
+		}
+		if j.rateLimited(ctx, installation) {
+			zerolog.Ctx(ctx).Debug().
+				Str(LogKeyJob, j.name).
+				Int64(LogKeyInstallationID, installation.ID).
+				Msg("Skipping installation with insufficient rate limit")
+			j.counter(MetricsKeyJobSkipped).Inc(1)
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(installation Installation) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			j.runInstallation(ctx, installation)
+		}(installation)
+	}
+
+	wg.Wait()
+
+	if j.registry != nil {
+		metrics.GetOrRegisterTimer(j.metricsKey(MetricsKeyJobDuration), j.registry).Update(GetClock(ctx).Now().Sub(start))
+	}
+	return nil
+}
+
+func (j *Job) listInstallations(ctx context.Context) ([]Installation, error) {
+	installations := j.installations
+	if installations == nil {
+		client, err := j.cc.NewAppClient()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create app client")
+		}
+		installations = NewInstallationsService(client)
+	}
+	return installations.ListAll(ctx)
+}
+
+func (j *Job) rateLimited(ctx context.Context, installation Installation) bool {
+	if j.rateLimits == nil {
+		return false
+	}
+
+	limit, ok := j.rateLimits.Get(installation.ID)[RateLimitResourceCore]
+	return ok && limit.Remaining < j.minRemaining && GetClock(ctx).Now().Before(limit.Reset)
+}
+
+func (j *Job) runInstallation(ctx context.Context, installation Installation) {
+	logger := zerolog.Ctx(ctx).With().
+		Str(LogKeyJob, j.name).
+		Int64(LogKeyInstallationID, installation.ID).
+		Logger()
+	ctx = logger.WithContext(InitializeRateLimits(ctx))
+
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredPanic(ctx, r)
+		}
+		if err != nil {
+			j.counter(MetricsKeyJobErrors).Inc(1)
+			j.onError(ctx, installation, err)
+		}
+	}()
+
+	j.counter(MetricsKeyJobRuns).Inc(1)
+
+	client, err := j.cc.NewInstallationClient(installation.ID)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to create client for installation %d", installation.ID)
+		return
+	}
+	err = j.fn(ctx, installation, client)
+}
+
+func (j *Job) counter(key string) metrics.Counter {
+	if j.registry == nil {
+		return metrics.NilCounter{}
+	}
+	return metrics.GetOrRegisterCounter(j.metricsKey(key), j.registry)
+}
+
+func (j *Job) metricsKey(key string) string {
+	return fmt.Sprintf("%s[job:%s]", key, j.name)
+}
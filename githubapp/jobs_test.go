@@ -0,0 +1,163 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+)
+
+type listInstallationsService struct {
+	InstallationsService
+	installations []Installation
+}
+
+func (s listInstallationsService) ListAll(ctx context.Context) ([]Installation, error) {
+	return s.installations, nil
+}
+
+func TestJobRun(t *testing.T) {
+	installations := listInstallationsService{installations: []Installation{
+		{ID: 1, Owner: "alpha"},
+		{ID: 2, Owner: "beta"},
+		{ID: 3, Owner: "gamma"},
+		{ID: 4, Owner: "delta"},
+		{ID: 5, Owner: "skipped"},
+	}}
+
+	tracker := NewRateLimitTracker()
+	tracker.update(4, http.Header{
+		"X-Ratelimit-Limit":     []string{"5000"},
+		"X-Ratelimit-Remaining": []string{"10"},
+		"X-Ratelimit-Reset":     []string{strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+	}, time.Now())
+
+	var mu sync.Mutex
+	var visited []string
+	var failed []int64
+	var active, maxActive int64
+
+	registry := metrics.NewRegistry()
+	job := NewJob("sweep", time.Hour, staticClientCreator{client: github.NewClient(nil)},
+		func(ctx context.Context, installation Installation, client *github.Client) error {
+			n := atomic.AddInt64(&active, 1)
+			defer atomic.AddInt64(&active, -1)
+			for {
+				m := atomic.LoadInt64(&maxActive)
+				if n <= m || atomic.CompareAndSwapInt64(&maxActive, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			visited = append(visited, installation.Owner)
+			mu.Unlock()
+
+			switch installation.ID {
+			case 2:
+				return errors.New("failed")
+			case 3:
+				panic("panicked")
+			}
+			return nil
+		},
+		WithJobInstallations(installations),
+		WithJobConcurrency(2),
+		WithJobFilter(func(i Installation) bool { return i.Owner != "skipped" }),
+		WithJobRateLimits(tracker, 100),
+		WithJobMetrics(registry),
+		WithJobErrorCallback(func(ctx context.Context, installation Installation, err error) {
+			mu.Lock()
+			failed = append(failed, installation.ID)
+			mu.Unlock()
+		}),
+	)
+
+	if err := job.Run(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sort.Strings(visited)
+	if !reflect.DeepEqual([]string{"alpha", "beta", "gamma"}, visited) {
+		t.Errorf("incorrect installations visited: %v", visited)
+	}
+
+	sort.Slice(failed, func(i, j int) bool { return failed[i] < failed[j] })
+	if !reflect.DeepEqual([]int64{2, 3}, failed) {
+		t.Errorf("incorrect installations failed: %v", failed)
+	}
+
+	if maxActive > 2 {
+		t.Errorf("exceeded concurrency limit: %d active", maxActive)
+	}
+
+	assertCounter := func(key string, expected int64) {
+		c, ok := registry.Get(key + "[job:sweep]").(metrics.Counter)
+		if !ok {
+			t.Errorf("missing metric %s", key)
+			return
+		}
+		if c.Count() != expected {
+			t.Errorf("incorrect value for %s: expected %d, actual %d", key, expected, c.Count())
+		}
+	}
+	assertCounter(MetricsKeyJobRuns, 3)
+	assertCounter(MetricsKeyJobErrors, 2)
+	assertCounter(MetricsKeyJobSkipped, 1)
+}
+
+func TestJobStart(t *testing.T) {
+	installations := listInstallationsService{installations: []Installation{{ID: 1}}}
+
+	var runs int64
+	job := NewJob("tick", 5*time.Millisecond, staticClientCreator{client: github.NewClient(nil)},
+		func(ctx context.Context, installation Installation, client *github.Client) error {
+			atomic.AddInt64(&runs, 1)
+			return nil
+		},
+		WithJobInstallations(installations),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := job.Start(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, but got %v", err)
+	}
+	if n := atomic.LoadInt64(&runs); n < 2 {
+		t.Errorf("expected multiple runs, but ran %d times", n)
+	}
+
+	atomic.StoreInt64(&runs, 0)
+	job.interval = 0
+	if err := job.Start(context.Background()); err == nil || errors.Is(err, context.Canceled) {
+		t.Fatalf("expected error for non-positive interval, but got %v", err)
+	}
+	if n := atomic.LoadInt64(&runs); n != 0 {
+		t.Errorf("expected no runs with a non-positive interval, but ran %d times", n)
+	}
+}
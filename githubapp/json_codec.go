@@ -0,0 +1,68 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// JSONUnmarshalFunc decodes JSON data into v. It has the same signature as
+// json.Unmarshal, so faster implementations with compatible behavior, like
+// the Unmarshal functions of sonic or go-json, can be used directly.
+type JSONUnmarshalFunc func(data []byte, v interface{}) error
+
+type jsonUnmarshalKey struct{}
+
+// WithJSONUnmarshal returns a context in which the library decodes webhook
+// payloads with unmarshal. This affects ParseEventContext,
+// PeekEnvelopeContext, GetDeliveryEvent, handlers from
+// NewParsedEventHandler, and the event handlers provided by this package. Use it with the context passed to consumers, like
+// EventConsumer, that do not use an event dispatcher.
+func WithJSONUnmarshal(ctx context.Context, unmarshal JSONUnmarshalFunc) context.Context {
+	return context.WithValue(ctx, jsonUnmarshalKey{}, unmarshal)
+}
+
+// GetJSONUnmarshal returns the function set by WithJSONUnmarshal, or
+// json.Unmarshal if the context does not contain one.
+func GetJSONUnmarshal(ctx context.Context) JSONUnmarshalFunc {
+	if unmarshal := contextJSONUnmarshal(ctx); unmarshal != nil {
+		return unmarshal
+	}
+	return json.Unmarshal
+}
+
+// contextJSONUnmarshal returns the function set by WithJSONUnmarshal, or nil
+func contextJSONUnmarshal(ctx context.Context) JSONUnmarshalFunc {
+	unmarshal, _ := ctx.Value(jsonUnmarshalKey{}).(JSONUnmarshalFunc)
+	return unmarshal
+}
+
+// WithJSONUnmarshalFunc sets the function used to decode payloads for the
+// event dispatcher and its handlers, as if each handler's context was
+// created with WithJSONUnmarshal. Decoding large payloads dominates CPU
+// usage for applications that receive many events, so replacing the
+// standard library decoder can reduce the cost of each event.
+// DefaultContextDeriver copies the function for asynchronous schedulers.
+func WithJSONUnmarshalFunc(unmarshal JSONUnmarshalFunc) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.unmarshal = unmarshal
+	}
+}
+
+// unmarshalPayload decodes a payload with the function from the context
+func unmarshalPayload(ctx context.Context, payload []byte, v interface{}) error {
+	return GetJSONUnmarshal(ctx)(payload, v)
+}
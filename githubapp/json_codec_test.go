@@ -0,0 +1,96 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestWithJSONUnmarshalFunc(t *testing.T) {
+	var decoded int
+	unmarshal := func(data []byte, v interface{}) error {
+		decoded++
+		return json.Unmarshal(data, v)
+	}
+
+	var event *Event
+	h := NewParsedEventHandler(func(ctx context.Context, e *Event) error {
+		event = e
+
+		// the deriver for async schedulers keeps the decoder
+		if _, err := ParseEventContext(DefaultContextDeriver(ctx), e.Type, []byte(`{}`)); err != nil {
+			t.Errorf("unexpected error parsing with derived context: %v", err)
+		}
+		return nil
+	}, "pull_request")
+
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithJSONUnmarshalFunc(unmarshal))
+
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, newHookRequest("pull_request", "1", true))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("incorrect status: %d", w.Code)
+	}
+	if _, ok := event.Payload.(*github.PullRequestEvent); !ok {
+		t.Errorf("incorrect payload type: %T", event.Payload)
+	}
+	// the envelope, the event, and the event parsed with the derived context
+	if decoded != 3 {
+		t.Errorf("expected 3 calls to the decoder, but got %d", decoded)
+	}
+}
+
+func TestPeekEnvelopeContext(t *testing.T) {
+	payload := []byte(`{"action": "opened", "installation": {"id": 123, "app_id": 1}, "repository": {"full_name": "octo/repo"}, "sender": null}`)
+
+	expected, err := PeekEnvelope(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded int
+	ctx := WithJSONUnmarshal(context.Background(), func(data []byte, v interface{}) error {
+		decoded++
+		return json.Unmarshal(data, v)
+	})
+	e, err := PeekEnvelopeContext(ctx, payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded != 1 {
+		t.Errorf("expected 1 call to the decoder, but got %d", decoded)
+	}
+	if e != expected {
+		t.Errorf("incorrect envelope\nexpected: %+v\n  actual: %+v", expected, e)
+	}
+
+	if _, err := PeekEnvelopeContext(ctx, []byte(`{"action": 1}`)); err == nil {
+		t.Error("expected error for invalid payload, but got nil")
+	}
+}
+
+func TestGetJSONUnmarshal(t *testing.T) {
+	var v map[string]int
+	if err := GetJSONUnmarshal(context.Background())([]byte(`{"a": 1}`), &v); err != nil || v["a"] != 1 {
+		t.Errorf("default decoder failed: %v, %v", v, err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxAppJWTExpiration is the maximum lifetime GitHub accepts for an
+	// application JWT.
+	MaxAppJWTExpiration = 10 * time.Minute
+
+	// appJWTClockSkew is subtracted from the issue time of JWTs to allow for
+	// clock drift between the application and GitHub
+	appJWTClockSkew = 30 * time.Second
+
+	// DefaultAppJWTReuse is how long clients reuse an application JWT before
+	// signing a new one.
+	DefaultAppJWTReuse = 5 * time.Minute
+
+	// maxAppJWTReuse leaves at least a minute before a reused JWT expires
+	maxAppJWTReuse = MaxAppJWTExpiration - appJWTClockSkew - time.Minute
+)
+
+// NewAppJWT creates a signed JWT that authenticates as the application with
+// the given integration ID. The key bytes must be a PEM-encoded PKCS1 or PKCS8
+// private key for the application. The expiration is capped at
+// MaxAppJWTExpiration.
+//
+// Most users should use an application client from a ClientCreator instead of
+// calling this function. It is useful for debugging or for making requests
+// with other HTTP clients.
+func NewAppJWT(integrationID int64, privKeyBytes []byte, expiration time.Duration) (string, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(privKeyBytes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse private key")
+	}
+
+	if expiration <= 0 || expiration > MaxAppJWTExpiration {
+		expiration = MaxAppJWTExpiration
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, newAppJWTClaims(integrationID, time.Now(), expiration)).SignedString(key)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign JWT")
+	}
+	return token, nil
+}
+
+func newAppJWTClaims(integrationID int64, now time.Time, expiration time.Duration) *jwt.RegisteredClaims {
+	// GitHub rejects timestamps that are not integers, so truncate them
+	iss := now.Add(-appJWTClockSkew).Truncate(time.Second)
+	exp := iss.Add(expiration)
+
+	return &jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(iss),
+		ExpiresAt: jwt.NewNumericDate(exp),
+		Issuer:    strconv.FormatInt(integrationID, 10),
+	}
+}
+
+// reusingSigner signs application JWTs with the maximum expiration and
+// returns the same JWT until the reuse duration elapses. The claims passed to
+// Sign are ignored.
+type reusingSigner struct {
+	signer        ghinstallation.Signer
+	integrationID int64
+	reuse         time.Duration
+	clock         Clock
+
+	mu      sync.Mutex
+	token   string
+	renewAt time.Time
+}
+
+func newReusingSigner(signer ghinstallation.Signer, integrationID int64, reuse time.Duration, clock Clock) *reusingSigner {
+	return &reusingSigner{
+		signer:        signer,
+		integrationID: integrationID,
+		reuse:         min(reuse, maxAppJWTReuse),
+		clock:         clock,
+	}
+}
+
+func (s *reusingSigner) Sign(_ jwt.Claims) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if s.token != "" && now.Before(s.renewAt) {
+		return s.token, nil
+	}
+
+	token, err := s.signer.Sign(newAppJWTClaims(s.integrationID, now, MaxAppJWTExpiration))
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token
+	s.renewAt = now.Add(s.reuse)
+	return token, nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestNewAppJWT(t *testing.T) {
+	keyBytes := newTestPrivateKey(t)
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(keyBytes)
+	if err != nil {
+		t.Fatalf("failed to parse key: %v", err)
+	}
+
+	tests := map[string]struct {
+		Expiration time.Duration
+		Lifetime   time.Duration
+	}{
+		"custom":  {Expiration: 2 * time.Minute, Lifetime: 2 * time.Minute},
+		"default": {Expiration: 0, Lifetime: MaxAppJWTExpiration},
+		"capped":  {Expiration: time.Hour, Lifetime: MaxAppJWTExpiration},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			before := time.Now().Truncate(time.Second)
+			signed, err := NewAppJWT(42, keyBytes, test.Expiration)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			after := time.Now()
+
+			var claims jwt.RegisteredClaims
+			token, err := jwt.ParseWithClaims(signed, &claims, func(token *jwt.Token) (interface{}, error) {
+				return &key.PublicKey, nil
+			}, jwt.WithoutClaimsValidation())
+			if err != nil {
+				t.Fatalf("failed to verify JWT: %v", err)
+			}
+			if token.Method != jwt.SigningMethodRS256 {
+				t.Errorf("expected RS256 signature, but got %s", token.Method.Alg())
+			}
+
+			if claims.Issuer != "42" {
+				t.Errorf("expected issuer 42, but got %q", claims.Issuer)
+			}
+			iat := claims.IssuedAt.Time
+			if iat.Before(before.Add(-appJWTClockSkew)) || iat.After(after.Add(-appJWTClockSkew)) {
+				t.Errorf("expected issue time %s before now, but got %s", appJWTClockSkew, iat)
+			}
+			if iat.Nanosecond() != 0 {
+				t.Errorf("expected issue time to be truncated to seconds, but got %s", iat)
+			}
+			if lifetime := claims.ExpiresAt.Sub(iat); lifetime != test.Lifetime {
+				t.Errorf("expected lifetime %s, but got %s", test.Lifetime, lifetime)
+			}
+		})
+	}
+
+	t.Run("otherKey", func(t *testing.T) {
+		signed, err := NewAppJWT(42, keyBytes, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		other, err := jwt.ParseRSAPrivateKeyFromPEM(newTestPrivateKey(t))
+		if err != nil {
+			t.Fatalf("failed to parse key: %v", err)
+		}
+		if _, err := jwt.Parse(signed, func(token *jwt.Token) (interface{}, error) {
+			return &other.PublicKey, nil
+		}); err == nil {
+			t.Error("expected verification with a different key to fail")
+		}
+	})
+
+	t.Run("invalidKey", func(t *testing.T) {
+		if _, err := NewAppJWT(42, []byte("not a key"), 0); err == nil {
+			t.Error("expected error for invalid key, but got nil")
+		}
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// Label is a repository label.
+type Label struct {
+	Name string
+
+	// Color is the hex color code of the label, without the leading "#".
+	Color string
+
+	Description string
+}
+
+// EnsureLabels creates the labels that do not exist in the repository
+// owner/repo. Label names are case-insensitive. Existing labels are not
+// modified, so users can customize the color and description of labels
+// created by the application.
+func EnsureLabels(ctx context.Context, client *github.Client, owner, repo string, labels ...Label) error {
+	existing, err := listLabelNames(ctx, client, owner, repo)
+	if err != nil {
+		return err
+	}
+
+	for _, label := range labels {
+		if existing[strings.ToLower(label.Name)] {
+			continue
+		}
+
+		newLabel := &github.Label{Name: &label.Name}
+		if label.Color != "" {
+			color := strings.TrimPrefix(label.Color, "#")
+			newLabel.Color = &color
+		}
+		if label.Description != "" {
+			newLabel.Description = &label.Description
+		}
+
+		if _, _, err := client.Issues.CreateLabel(ctx, owner, repo, newLabel); err != nil && !isAlreadyExists(err) {
+			return errors.Wrapf(err, "failed to create label %q in %s/%s", label.Name, owner, repo)
+		}
+		existing[strings.ToLower(label.Name)] = true
+	}
+	return nil
+}
+
+func listLabelNames(ctx context.Context, client *github.Client, owner, repo string) (map[string]bool, error) {
+	names := make(map[string]bool)
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		labels, res, err := client.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list labels in %s/%s", owner, repo)
+		}
+		for _, label := range labels {
+			names[strings.ToLower(label.GetName())] = true
+		}
+		if res.NextPage == 0 {
+			return names, nil
+		}
+		opts.Page = res.NextPage
+	}
+}
+
+// AddLabels adds labels to an issue or pull request. Labels that are already
+// present are unchanged and labels that do not exist in the repository are
+// created with default colors. Use EnsureLabels first to control how labels
+// are created.
+func AddLabels(ctx context.Context, client *github.Client, owner, repo string, number int, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	if _, _, err := client.Issues.AddLabelsToIssue(ctx, owner, repo, number, names); err != nil {
+		return errors.Wrapf(err, "failed to add labels to %s/%s#%d", owner, repo, number)
+	}
+	return nil
+}
+
+// RemoveLabels removes labels from an issue or pull request. Labels that are
+// not present are ignored.
+func RemoveLabels(ctx context.Context, client *github.Client, owner, repo string, number int, names ...string) error {
+	for _, name := range names {
+		if _, err := client.Issues.RemoveLabelForIssue(ctx, owner, repo, number, name); err != nil && !isNotFound(err) {
+			return errors.Wrapf(err, "failed to remove label %q from %s/%s#%d", name, owner, repo, number)
+		}
+	}
+	return nil
+}
+
+func isAlreadyExists(err error) bool {
+	var unprocessable *UnprocessableError
+	if !errors.As(ClassifyError(err), &unprocessable) {
+		return false
+	}
+	for _, e := range unprocessable.Errors {
+		if e.Code == "already_exists" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestEnsureLabels(t *testing.T) {
+	var created []github.Label
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/labels", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"name": "Bug", "color": "ff0000"}]`))
+	})
+	mux.HandleFunc("POST /repos/octo/repo/labels", func(w http.ResponseWriter, r *http.Request) {
+		var label github.Label
+		_ = json.NewDecoder(r.Body).Decode(&label)
+
+		// simulate another process creating the label concurrently
+		if label.GetName() == "race" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message": "Validation Failed", "errors": [{"resource": "Label", "code": "already_exists", "field": "name"}]}`))
+			return
+		}
+
+		created = append(created, label)
+		_ = json.NewEncoder(w).Encode(label)
+	})
+	client := newStaticClientCreator(t, mux).client
+
+	err := EnsureLabels(context.Background(), client, "octo", "repo",
+		Label{Name: "bug", Color: "00ff00"},
+		Label{Name: "triage", Color: "#cccccc", Description: "Needs triage"},
+		Label{Name: "race"},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []github.Label{
+		{Name: github.String("triage"), Color: github.String("cccccc"), Description: github.String("Needs triage")},
+	}
+	if !reflect.DeepEqual(expected, created) {
+		t.Errorf("incorrect labels created\nexpected: %v\n  actual: %v", expected, created)
+	}
+}
+
+func TestAddRemoveLabels(t *testing.T) {
+	labels := map[string]bool{"bug": true}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /repos/octo/repo/issues/1/labels", func(w http.ResponseWriter, r *http.Request) {
+		var names []string
+		_ = json.NewDecoder(r.Body).Decode(&names)
+		for _, name := range names {
+			labels[name] = true
+		}
+		_, _ = w.Write([]byte(`[]`))
+	})
+	mux.HandleFunc("DELETE /repos/octo/repo/issues/1/labels/{name}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if !labels[name] {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Label does not exist"}`))
+			return
+		}
+		delete(labels, name)
+		_, _ = w.Write([]byte(`[]`))
+	})
+	client := newStaticClientCreator(t, mux).client
+
+	ctx := context.Background()
+	if err := AddLabels(ctx, client, "octo", "repo", 1, "bug", "triage"); err != nil {
+		t.Fatalf("unexpected error adding labels: %v", err)
+	}
+	if err := RemoveLabels(ctx, client, "octo", "repo", 1, "bug", "missing"); err != nil {
+		t.Fatalf("unexpected error removing labels: %v", err)
+	}
+
+	var names []string
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !reflect.DeepEqual([]string{"triage"}, names) {
+		t.Errorf("incorrect labels: %v", names)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLoadSheddingRetryAfter = time.Minute
+)
+
+// WithLoadShedding makes the dispatcher reject events when its scheduler is
+// saturated, instead of accepting events the scheduler will drop. When the
+// saturation reported by the scheduler's Stats method is at least
+// maxSaturation, events with a handler are passed to the error callback with
+// ErrCapacityExceeded before the payload is read. The default error callback
+// responds with 503 Service Unavailable.
+//
+// Responses for rejected events, and for events the scheduler drops, include
+// a Retry-After header with the given duration, rounded up to the nearest
+// second. If retryAfter is not positive, the dispatcher uses
+// DefaultLoadSheddingRetryAfter.
+//
+// Load shedding only applies to schedulers that implement StatsScheduler,
+// like the scheduler returned by QueueAsyncScheduler.
+func WithLoadShedding(maxSaturation float64, retryAfter time.Duration) DispatcherOption {
+	return func(d *eventDispatcher) {
+		if retryAfter <= 0 {
+			retryAfter = DefaultLoadSheddingRetryAfter
+		}
+		d.shedding = &loadShedding{
+			maxSaturation: maxSaturation,
+			retryAfter:    retryAfter,
+		}
+	}
+}
+
+type loadShedding struct {
+	maxSaturation float64
+	retryAfter    time.Duration
+}
+
+// saturated returns true if the scheduler is too busy to accept new events
+func (ls *loadShedding) saturated(s Scheduler) bool {
+	if ls == nil {
+		return false
+	}
+	if ss, ok := s.(StatsScheduler); ok {
+		return ss.Stats().Saturation() >= ls.maxSaturation
+	}
+	return false
+}
+
+func (ls *loadShedding) setRetryAfter(w http.ResponseWriter) {
+	if ls == nil {
+		return
+	}
+	secs := int64((ls.retryAfter + time.Second - 1) / time.Second)
+	w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+}
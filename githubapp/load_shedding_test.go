@@ -0,0 +1,131 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type statsScheduler struct {
+	stats SchedulerStats
+	err   error
+}
+
+func (s *statsScheduler) Schedule(ctx context.Context, d Dispatch) error {
+	if s.err != nil {
+		return s.err
+	}
+	return d.Execute(ctx)
+}
+
+func (s *statsScheduler) Stats() SchedulerStats {
+	return s.stats
+}
+
+func TestLoadShedding(t *testing.T) {
+	tests := map[string]struct {
+		Stats SchedulerStats
+		Err   error
+		Event string
+
+		ResponseCode int
+		RetryAfter   string
+		CallCount    int
+	}{
+		"available": {
+			Stats:        SchedulerStats{QueueLength: 2, QueueCapacity: 10, ActiveWorkers: 2, Workers: 2},
+			Event:        "pull_request",
+			ResponseCode: 200,
+			CallCount:    1,
+		},
+		"saturated": {
+			Stats:        SchedulerStats{QueueLength: 9, QueueCapacity: 10, ActiveWorkers: 2, Workers: 2},
+			Event:        "pull_request",
+			ResponseCode: 503,
+			RetryAfter:   "30",
+		},
+		"saturatedUnhandledEvent": {
+			Stats:        SchedulerStats{QueueLength: 10, QueueCapacity: 10, ActiveWorkers: 2, Workers: 2},
+			Event:        "issues",
+			ResponseCode: 202,
+		},
+		"scheduleCapacityExceeded": {
+			Err:          ErrCapacityExceeded,
+			Event:        "pull_request",
+			ResponseCode: 503,
+			RetryAfter:   "30",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &TestEventHandler{Types: []string{"pull_request"}}
+			s := &statsScheduler{stats: test.Stats, err: test.Err}
+
+			d := NewEventDispatcher([]EventHandler{h}, testHookSecret,
+				WithScheduler(s),
+				WithLoadShedding(0.9, 29500*time.Millisecond),
+			)
+
+			w := httptest.NewRecorder()
+			d.ServeHTTP(w, newHookRequest(test.Event, "delivery-id", true))
+
+			if w.Code != test.ResponseCode {
+				t.Errorf("incorrect response code: expected %d, actual %d", test.ResponseCode, w.Code)
+			}
+			if retryAfter := w.Header().Get("Retry-After"); retryAfter != test.RetryAfter {
+				t.Errorf("incorrect Retry-After header: expected %q, actual %q", test.RetryAfter, retryAfter)
+			}
+			if h.Count != test.CallCount {
+				t.Errorf("incorrect handler call count: expected %d, actual %d", test.CallCount, h.Count)
+			}
+		})
+	}
+}
+
+func TestSchedulerStatsSaturation(t *testing.T) {
+	tests := map[string]struct {
+		Stats    SchedulerStats
+		Expected float64
+	}{
+		"idle": {
+			Stats:    SchedulerStats{QueueCapacity: 8, Workers: 2},
+			Expected: 0,
+		},
+		"partial": {
+			Stats:    SchedulerStats{QueueLength: 3, QueueCapacity: 8, ActiveWorkers: 2, Workers: 2},
+			Expected: 0.5,
+		},
+		"unbufferedQueue": {
+			Stats:    SchedulerStats{ActiveWorkers: 4, Workers: 4},
+			Expected: 1,
+		},
+		"noCapacity": {
+			Stats:    SchedulerStats{},
+			Expected: 0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := test.Stats.Saturation(); actual != test.Expected {
+				t.Errorf("incorrect saturation: expected %v, actual %v", test.Expected, actual)
+			}
+		})
+	}
+}
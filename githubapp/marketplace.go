@@ -0,0 +1,125 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	LogKeyMarketplaceAccount = "github_marketplace_account"
+	LogKeyMarketplacePlanID  = "github_marketplace_plan_id"
+)
+
+const (
+	MarketplaceActionPurchased              = "purchased"
+	MarketplaceActionCancelled              = "cancelled"
+	MarketplaceActionChanged                = "changed"
+	MarketplaceActionPendingChange          = "pending_change"
+	MarketplaceActionPendingChangeCancelled = "pending_change_cancelled"
+)
+
+// MarketplacePurchase summarizes a marketplace_purchase event.
+type MarketplacePurchase struct {
+	Action        string
+	EffectiveDate time.Time
+
+	AccountID    int64
+	AccountLogin string
+	AccountType  string
+
+	// Plan is the plan after the change. For cancellations, it is the
+	// cancelled plan.
+	Plan         *github.MarketplacePlan
+	BillingCycle string
+	UnitCount    int
+	OnFreeTrial  bool
+
+	// PreviousPlan and PreviousUnitCount are set for changes to an existing
+	// purchase.
+	PreviousPlan      *github.MarketplacePlan
+	PreviousUnitCount int
+}
+
+// ParseMarketplacePurchase parses the payload of a marketplace_purchase
+// event.
+func ParseMarketplacePurchase(payload []byte) (*MarketplacePurchase, *github.MarketplacePurchaseEvent, error) {
+	var event github.MarketplacePurchaseEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse marketplace purchase event payload")
+	}
+
+	purchase := event.GetMarketplacePurchase()
+	account := purchase.GetAccount()
+
+	p := &MarketplacePurchase{
+		Action:        event.GetAction(),
+		EffectiveDate: event.GetEffectiveDate().Time,
+		AccountID:     account.GetID(),
+		AccountLogin:  account.GetLogin(),
+		AccountType:   account.GetType(),
+		Plan:          purchase.GetPlan(),
+		BillingCycle:  purchase.GetBillingCycle(),
+		UnitCount:     purchase.GetUnitCount(),
+		OnFreeTrial:   purchase.GetOnFreeTrial(),
+	}
+	if prev := event.GetPreviousMarketplacePurchase(); prev != nil {
+		p.PreviousPlan = prev.GetPlan()
+		p.PreviousUnitCount = prev.GetUnitCount()
+	}
+	return p, &event, nil
+}
+
+// PrepareMarketplaceContext adds information about a marketplace purchase to
+// the logger in a context and returns the modified context and logger.
+func PrepareMarketplaceContext(ctx context.Context, p *MarketplacePurchase) (context.Context, zerolog.Logger) {
+	logctx := zerolog.Ctx(ctx).With()
+
+	if p != nil {
+		logctx = logctx.Str(LogKeyMarketplaceAccount, p.AccountLogin)
+		if p.Plan != nil {
+			logctx = logctx.Int64(LogKeyMarketplacePlanID, p.Plan.GetID())
+		}
+	}
+
+	logger := logctx.Logger()
+	return logger.WithContext(ctx), logger
+}
+
+// GetMarketplacePlanAccount returns the marketplace plan of an account,
+// including any pending change. The client must authenticate as the
+// application. If stubbed is true, it uses the stubbed endpoints, which
+// return test data for apps that are not yet listed. It returns false if
+// the account has no plan for the application.
+func GetMarketplacePlanAccount(ctx context.Context, appClient *github.Client, accountID int64, stubbed bool) (*github.MarketplacePlanAccount, bool, error) {
+	// copy the service to avoid modifying the shared client
+	marketplace := *appClient.Marketplace
+	marketplace.Stubbed = stubbed
+
+	account, _, err := marketplace.GetPlanAccountForAccount(ctx, accountID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, errors.Wrapf(err, "failed to get marketplace plan for account %d", accountID)
+	}
+	return account, true, nil
+}
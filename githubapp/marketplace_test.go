@@ -0,0 +1,103 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestParseMarketplacePurchase(t *testing.T) {
+	payload := []byte(`{
+		"action": "changed",
+		"effective_date": "2026-10-01T00:00:00Z",
+		"marketplace_purchase": {
+			"account": {"id": 18404719, "login": "octo-org", "type": "Organization"},
+			"billing_cycle": "monthly",
+			"unit_count": 10,
+			"on_free_trial": false,
+			"plan": {"id": 435, "name": "Pro"}
+		},
+		"previous_marketplace_purchase": {
+			"unit_count": 5,
+			"plan": {"id": 434, "name": "Basic"}
+		}
+	}`)
+
+	p, _, err := ParseMarketplacePurchase(payload)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p.Action != MarketplaceActionChanged {
+		t.Errorf("incorrect action: %q", p.Action)
+	}
+	if p.AccountID != 18404719 || p.AccountLogin != "octo-org" || p.AccountType != "Organization" {
+		t.Errorf("incorrect account: %d %q %q", p.AccountID, p.AccountLogin, p.AccountType)
+	}
+	if p.Plan.GetName() != "Pro" || p.BillingCycle != "monthly" || p.UnitCount != 10 {
+		t.Errorf("incorrect plan: %q %q %d", p.Plan.GetName(), p.BillingCycle, p.UnitCount)
+	}
+	if p.PreviousPlan.GetName() != "Basic" || p.PreviousUnitCount != 5 {
+		t.Errorf("incorrect previous plan: %q %d", p.PreviousPlan.GetName(), p.PreviousUnitCount)
+	}
+	if p.EffectiveDate.Month() != 10 {
+		t.Errorf("incorrect effective date: %s", p.EffectiveDate)
+	}
+
+	var out bytes.Buffer
+	ctx := zerolog.New(&out).WithContext(context.Background())
+	_, logger := PrepareMarketplaceContext(ctx, p)
+	logger.Info().Msg("")
+
+	assertLogFields(t, out.Bytes(), map[string]interface{}{
+		LogKeyMarketplaceAccount: "octo-org",
+		LogKeyMarketplacePlanID:  float64(435),
+	})
+}
+
+func TestGetMarketplacePlanAccount(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /marketplace_listing/stubbed/accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1, "login": "octo", "marketplace_purchase": {"plan": {"id": 435}}}`)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	account, ok, err := GetMarketplacePlanAccount(context.Background(), cc.client, 1, true)
+	if err != nil || !ok {
+		t.Fatalf("expected account, but got %t, %v", ok, err)
+	}
+	if account.GetMarketplacePurchase().GetPlan().GetID() != 435 {
+		t.Errorf("incorrect plan: %+v", account)
+	}
+	if cc.client.Marketplace.Stubbed {
+		t.Error("shared client was modified")
+	}
+
+	_, ok, err = GetMarketplacePlanAccount(context.Background(), cc.client, 2, true)
+	if err != nil || ok {
+		t.Errorf("expected no account, but got %t, %v", ok, err)
+	}
+}
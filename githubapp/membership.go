@@ -0,0 +1,199 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ttlcache "github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultMembershipTTL = 5 * time.Minute
+)
+
+// MembershipService answers questions about the organization membership,
+// team membership, and repository permissions of users. Implementations may
+// cache results.
+//
+// A MembershipService is also an EventHandler. When registered with an event
+// dispatcher, it invalidates cached results for an owner when GitHub reports
+// membership or permission changes in that owner.
+type MembershipService interface {
+	EventHandler
+
+	// IsOrgMember returns true if user is a member of org.
+	IsOrgMember(ctx context.Context, installationID int64, org, user string) (bool, error)
+
+	// IsTeamMember returns true if user is an active member of the team with
+	// the given slug in org.
+	IsTeamMember(ctx context.Context, installationID int64, org, team, user string) (bool, error)
+
+	// GetRepositoryPermission returns the permission level of user on
+	// owner/repo. See GetPermission for details.
+	GetRepositoryPermission(ctx context.Context, installationID int64, owner, repo, user string) (string, error)
+
+	// Invalidate removes all cached results for an owner.
+	Invalidate(owner string)
+}
+
+// MembershipOption configures properties of a membership service.
+type MembershipOption func(*membershipService)
+
+// WithMembershipTTL sets how long a membership service caches results. The
+// default is DefaultMembershipTTL.
+func WithMembershipTTL(ttl time.Duration) MembershipOption {
+	return func(s *membershipService) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+type membershipService struct {
+	cc    ClientCreator
+	ttl   time.Duration
+	cache *ttlcache.Cache
+}
+
+// NewMembershipService returns a MembershipService that queries GitHub with
+// installation clients from cc and caches results.
+func NewMembershipService(cc ClientCreator, opts ...MembershipOption) MembershipService {
+	s := &membershipService{
+		cc:  cc,
+		ttl: DefaultMembershipTTL,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.cache = ttlcache.New(s.ttl, 2*s.ttl)
+	return s
+}
+
+func (s *membershipService) IsOrgMember(ctx context.Context, installationID int64, org, user string) (bool, error) {
+	key := membershipKey(org, "org", user)
+	if v, ok := s.cache.Get(key); ok {
+		return v.(bool), nil
+	}
+
+	client, err := s.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return false, err
+	}
+
+	member, _, err := client.Organizations.IsMember(ctx, org, user)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check membership of %q in %s", user, org)
+	}
+
+	s.cache.Set(key, member, ttlcache.DefaultExpiration)
+	return member, nil
+}
+
+func (s *membershipService) IsTeamMember(ctx context.Context, installationID int64, org, team, user string) (bool, error) {
+	key := membershipKey(org, "team", team, user)
+	if v, ok := s.cache.Get(key); ok {
+		return v.(bool), nil
+	}
+
+	client, err := s.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return false, err
+	}
+
+	var member bool
+	membership, _, err := client.Teams.GetTeamMembershipBySlug(ctx, org, team, user)
+	switch {
+	case err == nil:
+		member = membership.GetState() == "active"
+	case isNotFound(err):
+		member = false
+	default:
+		return false, errors.Wrapf(err, "failed to check membership of %q in %s/%s", user, org, team)
+	}
+
+	s.cache.Set(key, member, ttlcache.DefaultExpiration)
+	return member, nil
+}
+
+func (s *membershipService) GetRepositoryPermission(ctx context.Context, installationID int64, owner, repo, user string) (string, error) {
+	key := membershipKey(owner, "repo", repo, user)
+	if v, ok := s.cache.Get(key); ok {
+		return v.(string), nil
+	}
+
+	client, err := s.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return "", err
+	}
+
+	permission, err := GetPermission(ctx, client, owner, repo, user)
+	if err != nil {
+		return "", err
+	}
+
+	s.cache.Set(key, permission, ttlcache.DefaultExpiration)
+	return permission, nil
+}
+
+func (s *membershipService) Invalidate(owner string) {
+	prefix := strings.ToLower(owner) + "|"
+	for key := range s.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			s.cache.Delete(key)
+		}
+	}
+}
+
+func (s *membershipService) Handles() []string {
+	return Handles(EventMembership, EventOrganization, EventMember, EventTeam)
+}
+
+func (s *membershipService) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event struct {
+		Organization struct {
+			Login string `json:"login"`
+		} `json:"organization"`
+		Repository struct {
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrapf(err, "failed to parse %s event payload", eventType)
+	}
+
+	owner := event.Organization.Login
+	if owner == "" {
+		owner = event.Repository.Owner.Login
+	}
+	if owner != "" {
+		zerolog.Ctx(ctx).Debug().Msgf("Invalidating cached memberships for %s", owner)
+		s.Invalidate(owner)
+	}
+	return nil
+}
+
+func membershipKey(owner string, parts ...string) string {
+	return strings.ToLower(fmt.Sprintf("%s|%s", owner, strings.Join(parts, "|")))
+}
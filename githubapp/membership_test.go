@@ -0,0 +1,126 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+// staticClientCreator returns the same client for all installations
+type staticClientCreator struct {
+	ClientCreator
+	client *github.Client
+}
+
+func (cc staticClientCreator) NewInstallationClient(int64) (*github.Client, error) {
+	return cc.client, nil
+}
+
+func (cc staticClientCreator) NewAppClient() (*github.Client, error) {
+	return cc.client, nil
+}
+
+func newStaticClientCreator(t *testing.T, h http.Handler) staticClientCreator {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+	return staticClientCreator{client: client}
+}
+
+func TestMembershipService(t *testing.T) {
+	ctx := context.Background()
+	calls := make(map[string]int)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/test/members/{user}", func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		if r.PathValue("user") == "alice" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /orgs/test/teams/devs/memberships/{user}", func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		switch r.PathValue("user") {
+		case "alice":
+			_, _ = io.WriteString(w, `{"state":"active"}`)
+		case "bob":
+			_, _ = io.WriteString(w, `{"state":"pending"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("GET /repos/test/repo/collaborators/{user}/permission", func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.Path]++
+		_, _ = io.WriteString(w, `{"permission":"write","role_name":"maintain"}`)
+	})
+
+	s := NewMembershipService(newStaticClientCreator(t, mux))
+
+	for user, expected := range map[string]bool{"alice": true, "bob": false} {
+		member, err := s.IsOrgMember(ctx, 1, "test", user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if member != expected {
+			t.Errorf("incorrect org membership for %s: expected %t, actual %t", user, expected, member)
+		}
+	}
+
+	for user, expected := range map[string]bool{"alice": true, "bob": false, "carol": false} {
+		member, err := s.IsTeamMember(ctx, 1, "test", "devs", user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if member != expected {
+			t.Errorf("incorrect team membership for %s: expected %t, actual %t", user, expected, member)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		permission, err := s.GetRepositoryPermission(ctx, 1, "test", "repo", "alice")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if permission != "maintain" {
+			t.Errorf("incorrect permission: expected %q, actual %q", "maintain", permission)
+		}
+	}
+
+	permissionPath := "/repos/test/repo/collaborators/alice/permission"
+	if calls[permissionPath] != 1 {
+		t.Errorf("expected cached permission, but made %d requests", calls[permissionPath])
+	}
+
+	if err := s.Handle(ctx, "member", "", []byte(`{"action":"added","repository":{"owner":{"login":"Test"}}}`)); err != nil {
+		t.Fatalf("unexpected error handling event: %v", err)
+	}
+	if _, err := s.GetRepositoryPermission(ctx, 1, "test", "repo", "alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls[permissionPath] != 2 {
+		t.Errorf("expected invalidated permission, but made %d requests", calls[permissionPath])
+	}
+}
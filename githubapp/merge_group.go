@@ -0,0 +1,53 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+)
+
+var mergeGroupRefPattern = regexp.MustCompile(`^gh-readonly-queue/.+/pr-(\d+)-[0-9a-f]+$`)
+
+// MergeGroupPullRequestNumber returns the number of the pull request that
+// created a merge group, or 0 if the number is unknown. A merge group may
+// contain multiple pull requests, but only the most recently added one is
+// named in the group's head ref, like
+// "refs/heads/gh-readonly-queue/main/pr-123-<sha>". Use this number for
+// logging or to find the pull request to report failures on, but use the
+// head commit of the group to determine which changes are tested.
+func MergeGroupPullRequestNumber(group *github.MergeGroup) int {
+	ref := strings.TrimPrefix(group.GetHeadRef(), "refs/heads/")
+
+	m := mergeGroupRefPattern.FindStringSubmatch(ref)
+	if m == nil {
+		return 0
+	}
+
+	number, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+// MergeGroupBaseBranch returns the name of the branch that a merge group
+// merges into.
+func MergeGroupBaseBranch(group *github.MergeGroup) string {
+	return strings.TrimPrefix(group.GetBaseRef(), "refs/heads/")
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestMergeGroupPullRequestNumber(t *testing.T) {
+	tests := map[string]struct {
+		Ref      string
+		Expected int
+	}{
+		"simple":       {Ref: "refs/heads/gh-readonly-queue/main/pr-42-a1b2c3d4", Expected: 42},
+		"nestedBranch": {Ref: "refs/heads/gh-readonly-queue/release/v1/pr-7-a1b2c3d4", Expected: 7},
+		"notQueue":     {Ref: "refs/heads/main", Expected: 0},
+		"empty":        {Ref: "", Expected: 0},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			group := &github.MergeGroup{HeadRef: github.String(test.Ref)}
+			if n := MergeGroupPullRequestNumber(group); n != test.Expected {
+				t.Errorf("incorrect number: expected %d, actual %d", test.Expected, n)
+			}
+		})
+	}
+
+	if n := MergeGroupPullRequestNumber(nil); n != 0 {
+		t.Errorf("incorrect number for nil group: %d", n)
+	}
+}
@@ -0,0 +1,315 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	ttlcache "github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultMergeRequirementsTTL = 5 * time.Minute
+)
+
+// MergeRequirements describes what is required to merge a pull request into
+// a branch. It combines the branch protection rules of the branch and the
+// repository rulesets that apply to it. When both define a requirement, the
+// stricter value is used.
+type MergeRequirements struct {
+	// Protected is true if branch protection or at least one ruleset
+	// applies to the branch.
+	Protected bool
+
+	// RequiredApprovals is the number of approving reviews required.
+	RequiredApprovals int
+
+	RequireCodeOwnerReview        bool
+	RequireLastPushApproval       bool
+	DismissStaleReviews           bool
+	RequireConversationResolution bool
+
+	// RequiredStatusChecks are the sorted names of the required status
+	// checks and check runs.
+	RequiredStatusChecks []string
+
+	// RequireUpToDate is true if the branch of a pull request must contain
+	// the latest commit of the base branch before merging.
+	RequireUpToDate bool
+
+	RequireLinearHistory bool
+	RequireSignedCommits bool
+	RequireMergeQueue    bool
+
+	// RequiredDeployments are the sorted names of the environments that
+	// must be deployed successfully before merging.
+	RequiredDeployments []string
+
+	// ProtectionUnavailable is true if the client was not allowed to read
+	// the branch protection rules, which requires administration
+	// permission. In this case, the requirements only reflect rulesets.
+	ProtectionUnavailable bool
+}
+
+// GetMergeRequirements returns the requirements to merge a pull request into
+// branch in owner/repo.
+func GetMergeRequirements(ctx context.Context, client *github.Client, owner, repo, branch string) (*MergeRequirements, error) {
+	var r MergeRequirements
+
+	protection, _, err := client.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+	switch {
+	case err == nil:
+		r.addProtection(protection)
+	case errors.Is(err, github.ErrBranchNotProtected) || isNotFound(err):
+	case isForbidden(err):
+		r.ProtectionUnavailable = true
+	default:
+		return nil, errors.Wrapf(err, "failed to get protection for branch %q in %s/%s", branch, owner, repo)
+	}
+
+	rules, _, err := client.Repositories.GetRulesForBranch(ctx, owner, repo, branch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get rules for branch %q in %s/%s", branch, owner, repo)
+	}
+	for _, rule := range rules {
+		if err := r.addRule(rule); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s rule for branch %q in %s/%s", rule.Type, branch, owner, repo)
+		}
+	}
+
+	sort.Strings(r.RequiredStatusChecks)
+	sort.Strings(r.RequiredDeployments)
+	return &r, nil
+}
+
+// clone returns a deep copy of r.
+func (r *MergeRequirements) clone() *MergeRequirements {
+	c := *r
+	c.RequiredStatusChecks = slices.Clone(r.RequiredStatusChecks)
+	c.RequiredDeployments = slices.Clone(r.RequiredDeployments)
+	return &c
+}
+
+func (r *MergeRequirements) addProtection(p *github.Protection) {
+	r.Protected = true
+
+	if checks := p.RequiredStatusChecks; checks != nil {
+		r.RequireUpToDate = r.RequireUpToDate || checks.Strict
+		if checks.Contexts != nil {
+			r.RequiredStatusChecks = appendUnique(r.RequiredStatusChecks, *checks.Contexts...)
+		}
+		if checks.Checks != nil {
+			for _, check := range *checks.Checks {
+				r.RequiredStatusChecks = appendUnique(r.RequiredStatusChecks, check.Context)
+			}
+		}
+	}
+	if reviews := p.RequiredPullRequestReviews; reviews != nil {
+		r.RequiredApprovals = max(r.RequiredApprovals, reviews.RequiredApprovingReviewCount)
+		r.RequireCodeOwnerReview = r.RequireCodeOwnerReview || reviews.RequireCodeOwnerReviews
+		r.RequireLastPushApproval = r.RequireLastPushApproval || reviews.RequireLastPushApproval
+		r.DismissStaleReviews = r.DismissStaleReviews || reviews.DismissStaleReviews
+	}
+	if p.RequireLinearHistory != nil && p.RequireLinearHistory.Enabled {
+		r.RequireLinearHistory = true
+	}
+	if p.RequiredConversationResolution != nil && p.RequiredConversationResolution.Enabled {
+		r.RequireConversationResolution = true
+	}
+	if p.RequiredSignatures.GetEnabled() {
+		r.RequireSignedCommits = true
+	}
+}
+
+func (r *MergeRequirements) addRule(rule *github.RepositoryRule) error {
+	r.Protected = true
+
+	switch rule.Type {
+	case "pull_request":
+		var params github.PullRequestRuleParameters
+		if err := unmarshalRuleParameters(rule, &params); err != nil {
+			return err
+		}
+		r.RequiredApprovals = max(r.RequiredApprovals, params.RequiredApprovingReviewCount)
+		r.RequireCodeOwnerReview = r.RequireCodeOwnerReview || params.RequireCodeOwnerReview
+		r.RequireLastPushApproval = r.RequireLastPushApproval || params.RequireLastPushApproval
+		r.DismissStaleReviews = r.DismissStaleReviews || params.DismissStaleReviewsOnPush
+		r.RequireConversationResolution = r.RequireConversationResolution || params.RequiredReviewThreadResolution
+
+	case "required_status_checks":
+		var params github.RequiredStatusChecksRuleParameters
+		if err := unmarshalRuleParameters(rule, &params); err != nil {
+			return err
+		}
+		r.RequireUpToDate = r.RequireUpToDate || params.StrictRequiredStatusChecksPolicy
+		for _, check := range params.RequiredStatusChecks {
+			r.RequiredStatusChecks = appendUnique(r.RequiredStatusChecks, check.Context)
+		}
+
+	case "required_deployments":
+		var params github.RequiredDeploymentEnvironmentsRuleParameters
+		if err := unmarshalRuleParameters(rule, &params); err != nil {
+			return err
+		}
+		r.RequiredDeployments = appendUnique(r.RequiredDeployments, params.RequiredDeploymentEnvironments...)
+
+	case "required_linear_history":
+		r.RequireLinearHistory = true
+	case "required_signatures":
+		r.RequireSignedCommits = true
+	case "merge_queue":
+		r.RequireMergeQueue = true
+	}
+	return nil
+}
+
+func unmarshalRuleParameters(rule *github.RepositoryRule, v interface{}) error {
+	if rule.Parameters == nil {
+		return nil
+	}
+	return json.Unmarshal(*rule.Parameters, v)
+}
+
+func appendUnique(values []string, add ...string) []string {
+	for _, v := range add {
+		if !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func isForbidden(err error) bool {
+	var forbidden *ForbiddenError
+	return errors.As(ClassifyError(err), &forbidden)
+}
+
+// MergeRequirementsService returns the merge requirements of branches and
+// caches the results.
+//
+// A MergeRequirementsService is also an EventHandler. When registered with an
+// event dispatcher, it invalidates cached results for a repository when
+// GitHub reports changes to its branch protection rules or rulesets.
+type MergeRequirementsService interface {
+	EventHandler
+	RepositoryCacheInvalidator
+
+	// Get returns the requirements to merge a pull request into branch in
+	// owner/repo. See GetMergeRequirements for details. Each call returns a
+	// new copy of the cached requirements, so callers may modify it.
+	Get(ctx context.Context, installationID int64, owner, repo, branch string) (*MergeRequirements, error)
+}
+
+// MergeRequirementsOption configures properties of a merge requirements
+// service.
+type MergeRequirementsOption func(*mergeRequirementsService)
+
+// WithMergeRequirementsTTL sets how long a merge requirements service caches
+// results. The default is DefaultMergeRequirementsTTL.
+func WithMergeRequirementsTTL(ttl time.Duration) MergeRequirementsOption {
+	return func(s *mergeRequirementsService) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+type mergeRequirementsService struct {
+	cc    ClientCreator
+	ttl   time.Duration
+	cache *ttlcache.Cache
+}
+
+// NewMergeRequirementsService returns a MergeRequirementsService that
+// queries GitHub with installation clients from cc and caches results.
+func NewMergeRequirementsService(cc ClientCreator, opts ...MergeRequirementsOption) MergeRequirementsService {
+	s := &mergeRequirementsService{
+		cc:  cc,
+		ttl: DefaultMergeRequirementsTTL,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.cache = ttlcache.New(s.ttl, 2*s.ttl)
+	return s
+}
+
+func (s *mergeRequirementsService) Get(ctx context.Context, installationID int64, owner, repo, branch string) (*MergeRequirements, error) {
+	key := mergeRequirementsPrefix(owner, repo) + branch
+	if v, ok := s.cache.Get(key); ok {
+		return v.(*MergeRequirements).clone(), nil
+	}
+
+	client, err := s.cc.NewInstallationClient(installationID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := GetMergeRequirements(ctx, client, owner, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Set(key, r, ttlcache.DefaultExpiration)
+	return r.clone(), nil
+}
+
+func (s *mergeRequirementsService) InvalidateRepository(owner, repo string) {
+	prefix := mergeRequirementsPrefix(owner, repo)
+	for key := range s.cache.Items() {
+		if strings.HasPrefix(key, prefix) {
+			s.cache.Delete(key)
+		}
+	}
+}
+
+func (s *mergeRequirementsService) Handles() []string {
+	return Handles(EventBranchProtectionRule, EventBranchProtectionConfiguration, EventRepositoryRuleset)
+}
+
+func (s *mergeRequirementsService) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event struct {
+		Repository struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrapf(err, "failed to parse %s event payload", eventType)
+	}
+
+	// rulesets defined by an organization or enterprise do not include a
+	// repository, but they are rare enough that callers can rely on the TTL
+	if repo := event.Repository; repo.Name != "" {
+		s.InvalidateRepository(repo.Owner.Login, repo.Name)
+	}
+	return nil
+}
+
+func mergeRequirementsPrefix(owner, repo string) string {
+	// branch names are case-sensitive, but owner and repository names are not
+	return strings.ToLower(owner+"/"+repo) + ":"
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetMergeRequirements(t *testing.T) {
+	const protection = `{
+		"required_status_checks": {"strict": false, "checks": [{"context": "build"}, {"context": "test"}]},
+		"required_pull_request_reviews": {"required_approving_review_count": 1, "require_code_owner_reviews": true},
+		"required_linear_history": {"enabled": true}
+	}`
+
+	const rules = `[
+		{"type": "deletion"},
+		{"type": "pull_request", "parameters": {"required_approving_review_count": 2, "required_review_thread_resolution": true}},
+		{"type": "required_status_checks", "parameters": {"required_status_checks": [{"context": "lint"}, {"context": "build"}], "strict_required_status_checks_policy": true}},
+		{"type": "required_deployments", "parameters": {"required_deployment_environments": ["staging"]}},
+		{"type": "merge_queue", "parameters": {"merge_method": "SQUASH"}}
+	]`
+
+	tests := map[string]struct {
+		Status   int
+		Body     string
+		Rules    string
+		Expected MergeRequirements
+	}{
+		"combined": {
+			Status: http.StatusOK,
+			Body:   protection,
+			Rules:  rules,
+			Expected: MergeRequirements{
+				Protected:                     true,
+				RequiredApprovals:             2,
+				RequireCodeOwnerReview:        true,
+				RequireConversationResolution: true,
+				RequiredStatusChecks:          []string{"build", "lint", "test"},
+				RequireUpToDate:               true,
+				RequireLinearHistory:          true,
+				RequireMergeQueue:             true,
+				RequiredDeployments:           []string{"staging"},
+			},
+		},
+		"protectionOnly": {
+			Status: http.StatusOK,
+			Body:   protection,
+			Rules:  `[]`,
+			Expected: MergeRequirements{
+				Protected:              true,
+				RequiredApprovals:      1,
+				RequireCodeOwnerReview: true,
+				RequiredStatusChecks:   []string{"build", "test"},
+				RequireLinearHistory:   true,
+			},
+		},
+		"notProtected": {
+			Status:   http.StatusNotFound,
+			Body:     `{"message": "Branch not protected"}`,
+			Rules:    `[]`,
+			Expected: MergeRequirements{},
+		},
+		"forbidden": {
+			Status: http.StatusForbidden,
+			Body:   `{"message": "Resource not accessible by integration"}`,
+			Rules:  `[{"type": "required_signatures"}]`,
+			Expected: MergeRequirements{
+				Protected:             true,
+				RequireSignedCommits:  true,
+				ProtectionUnavailable: true,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/octo/repo/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.Status)
+				_, _ = w.Write([]byte(test.Body))
+			})
+			mux.HandleFunc("GET /repos/octo/repo/rules/branches/main", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(test.Rules))
+			})
+			client := newStaticClientCreator(t, mux).client
+
+			r, err := GetMergeRequirements(context.Background(), client, "octo", "repo", "main")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.Expected, *r) {
+				t.Errorf("incorrect requirements\nexpected: %+v\n  actual: %+v", test.Expected, *r)
+			}
+		})
+	}
+}
+
+func TestMergeRequirementsService(t *testing.T) {
+	var requests int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/branches/main/protection", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"required_pull_request_reviews": {"required_approving_review_count": 1}}`))
+	})
+	mux.HandleFunc("GET /repos/octo/repo/rules/branches/main", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	})
+
+	s := NewMergeRequirementsService(newStaticClientCreator(t, mux))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		r, err := s.Get(ctx, 1, "octo", "repo", "main")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if r.RequiredApprovals != 1 || len(r.RequiredStatusChecks) != 0 {
+			t.Errorf("incorrect requirements: %+v", r)
+		}
+
+		// callers modifying the result must not change the cached value
+		r.RequiredApprovals = 5
+		r.RequiredStatusChecks = append(r.RequiredStatusChecks, "build")
+	}
+	if requests != 1 {
+		t.Errorf("expected 1 request, but made %d", requests)
+	}
+
+	payload := []byte(`{"action": "edited", "repository": {"name": "Repo", "owner": {"login": "Octo"}}}`)
+	if err := s.Handle(ctx, "branch_protection_rule", "delivery", payload); err != nil {
+		t.Fatalf("unexpected error handling event: %v", err)
+	}
+
+	if _, err := s.Get(ctx, 1, "octo", "repo", "main"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests after invalidation, but made %d", requests)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	// MetricsKeyReceivedEvents counts the valid events received by the
+	// dispatcher when WithEventMetrics is set.
+	MetricsKeyReceivedEvents = "github.event.received"
+)
+
+// InstallationTagMode selects how metrics are tagged with installations.
+type InstallationTagMode int
+
+const (
+	// InstallationTagsOff omits the installation tag.
+	InstallationTagsOff InstallationTagMode = iota
+
+	// InstallationTagsHash tags metrics with a hash of the installation ID
+	// in one of a fixed number of buckets, like "installation_hash:17". Use
+	// InstallationHash to find the bucket of a suspect installation.
+	InstallationTagsHash
+
+	// InstallationTagsBucket tags metrics with the installation ID, like
+	// "installation:123", for a fixed number of installations, in the order
+	// they are first seen. Other installations are tagged with a hash bucket
+	// as with InstallationTagsHash, so a new installation that sends many
+	// events can still be found with InstallationHash.
+	InstallationTagsBucket
+
+	// InstallationTagsFull tags metrics with the installation ID of every
+	// installation. The number of metrics grows with the number of
+	// installations.
+	InstallationTagsFull
+)
+
+// InstallationTags controls the installation tag of metrics, so operators of
+// applications with many installations can find the installation that sends
+// or triggers the most events without creating metrics for every
+// installation. A nil InstallationTags omits the tag.
+//
+// InstallationTags is safe for concurrent use.
+type InstallationTags struct {
+	mode  InstallationTagMode
+	limit int
+
+	mu   sync.Mutex
+	seen map[int64]struct{}
+}
+
+// NewInstallationTags creates InstallationTags with the mode. The limit is the
+// number of hash buckets for InstallationTagsHash and both the number of
+// installations with their own tag and the number of hash buckets for other
+// installations for InstallationTagsBucket. It is ignored by other modes and
+// must be positive for these modes.
+func NewInstallationTags(mode InstallationTagMode, limit int) *InstallationTags {
+	if (mode == InstallationTagsHash || mode == InstallationTagsBucket) && limit < 1 {
+		panic("NewInstallationTags: limit must be positive")
+	}
+	return &InstallationTags{
+		mode:  mode,
+		limit: limit,
+		seen:  make(map[int64]struct{}),
+	}
+}
+
+// Tag returns the installation tag for an installation, like
+// "installation:123", or an empty string if the tag is omitted. Metrics for
+// events and requests without an installation use the ID 0.
+func (t *InstallationTags) Tag(installationID int64) string {
+	if t == nil {
+		return ""
+	}
+
+	switch t.mode {
+	case InstallationTagsHash:
+		return t.hashTag(installationID)
+	case InstallationTagsBucket:
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		if _, ok := t.seen[installationID]; !ok {
+			if len(t.seen) >= t.limit {
+				return t.hashTag(installationID)
+			}
+			t.seen[installationID] = struct{}{}
+		}
+		return "installation:" + strconv.FormatInt(installationID, 10)
+	case InstallationTagsFull:
+		return "installation:" + strconv.FormatInt(installationID, 10)
+	}
+	return ""
+}
+
+func (t *InstallationTags) hashTag(installationID int64) string {
+	return fmt.Sprintf("installation_hash:%d", InstallationHash(installationID, t.limit))
+}
+
+// InstallationHash returns the bucket of an installation in metrics tagged
+// with InstallationTagsHash and the given number of buckets.
+func InstallationHash(installationID int64, buckets int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(strconv.FormatInt(installationID, 10)))
+	return int(h.Sum32() % uint32(buckets))
+}
+
+// metricTags formats tags as a metric name suffix, like "[event:push]",
+// omitting empty tags.
+func metricTags(tags ...string) string {
+	var s string
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if s != "" {
+			s += ","
+		}
+		s += tag
+	}
+	if s == "" {
+		return ""
+	}
+	return "[" + s + "]"
+}
+
+// WithEventMetrics counts the valid events received by the dispatcher in the
+// registry, tagged with the event type and with the installation as selected
+// by tags. Use it to find the installations or event types responsible for a
+// sudden increase in events. If tags is nil, events are only tagged with the
+// event type.
+func WithEventMetrics(r metrics.Registry, tags *InstallationTags) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.eventMetrics = &eventMetrics{registry: r, tags: tags}
+	}
+}
+
+type eventMetrics struct {
+	registry metrics.Registry
+	tags     *InstallationTags
+}
+
+func (m *eventMetrics) record(ctx context.Context, eventType string, payload []byte) {
+	if m == nil || m.registry == nil {
+		return
+	}
+
+	key := MetricsKeyReceivedEvents + eventMetricTags(ctx, m.tags, eventType, payload)
+	metrics.GetOrRegisterCounter(key, m.registry).Inc(1)
+}
+
+// eventMetricTags returns the tags for a metric about an event.
+func eventMetricTags(ctx context.Context, tags *InstallationTags, eventType string, payload []byte) string {
+	var installation string
+	if tags != nil {
+		e, _ := PeekEnvelopeContext(ctx, payload)
+		installation = tags.Tag(e.InstallationID)
+	}
+	return metricTags("event:"+eventType, installation)
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestInstallationTags(t *testing.T) {
+	tests := map[string]struct {
+		Tags     *InstallationTags
+		Expected []string
+	}{
+		"nil": {
+			Expected: []string{"", "", ""},
+		},
+		"off": {
+			Tags:     NewInstallationTags(InstallationTagsOff, 0),
+			Expected: []string{"", "", ""},
+		},
+		"hash": {
+			Tags: NewInstallationTags(InstallationTagsHash, 8),
+			Expected: []string{
+				fmt.Sprintf("installation_hash:%d", InstallationHash(1, 8)),
+				fmt.Sprintf("installation_hash:%d", InstallationHash(2, 8)),
+				fmt.Sprintf("installation_hash:%d", InstallationHash(1, 8)),
+			},
+		},
+		"bucket": {
+			Tags:     NewInstallationTags(InstallationTagsBucket, 1),
+			Expected: []string{"installation:1", fmt.Sprintf("installation_hash:%d", InstallationHash(2, 1)), "installation:1"},
+		},
+		"full": {
+			Tags:     NewInstallationTags(InstallationTagsFull, 0),
+			Expected: []string{"installation:1", "installation:2", "installation:1"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i, id := range []int64{1, 2, 1} {
+				if tag := test.Tags.Tag(id); tag != test.Expected[i] {
+					t.Errorf("incorrect tag for installation %d: expected %q, actual %q", id, test.Expected[i], tag)
+				}
+			}
+		})
+	}
+
+	if h := InstallationHash(12345, 16); h < 0 || h >= 16 {
+		t.Errorf("hash out of range: %d", h)
+	}
+}
+
+func TestEventMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	h := &TestEventHandler{Types: []string{"push"}}
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret,
+		WithEventMetrics(registry, NewInstallationTags(InstallationTagsBucket, 2)),
+	)
+
+	for _, id := range []int64{1, 1, 2, 3} {
+		req, err := NewDeliveryRequest(context.Background(), DefaultWebhookRoute, testHookSecret, Delivery{
+			EventType:  "push",
+			DeliveryID: "delivery-id",
+			Payload:    []byte(fmt.Sprintf(`{"installation": {"id": %d}}`, id)),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating request: %v", err)
+		}
+		d.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// installations after the limit are tagged with their hash bucket
+	overflow := fmt.Sprintf("github.event.received[event:push,installation_hash:%d]", InstallationHash(3, 2))
+	expected := map[string]int64{
+		"github.event.received[event:push,installation:1]": 2,
+		"github.event.received[event:push,installation:2]": 1,
+		overflow: 1,
+	}
+	for name, count := range expected {
+		counter, ok := registry.Get(name).(metrics.Counter)
+		if !ok {
+			t.Errorf("missing metric %q", name)
+			continue
+		}
+		if counter.Count() != count {
+			t.Errorf("incorrect count for %q: expected %d, actual %d", name, count, counter.Count())
+		}
+	}
+}
+
+func TestSchedulingInstallationTags(t *testing.T) {
+	registry := metrics.NewRegistry()
+	s := BoundedAsyncScheduler(1, OverflowReject,
+		WithSchedulingMetrics(registry),
+		WithSchedulingInstallationTags(NewInstallationTags(InstallationTagsFull, 0)),
+	)
+
+	h := &AsyncHandler{Block: make(chan struct{}), Called: make(chan bool, 2)}
+	defer close(h.Block)
+
+	d := Dispatch{Handler: h, EventType: "push", Payload: []byte(`{"installation": {"id": 7}}`)}
+	if err := s.Schedule(context.Background(), d); err != nil {
+		t.Fatalf("unexpected error scheduling dispatch: %v", err)
+	}
+	if err := s.Schedule(context.Background(), d); err != ErrCapacityExceeded {
+		t.Fatalf("expected ErrCapacityExceeded, but got: %v", err)
+	}
+
+	for name, count := range map[string]int64{
+		"github.event.dropped":                            1,
+		"github.event.dropped[event:push,installation:7]": 1,
+	} {
+		counter, ok := registry.Get(name).(metrics.Counter)
+		if !ok || counter.Count() != count {
+			t.Errorf("incorrect metric %q: %v", name, registry.Get(name))
+		}
+	}
+}
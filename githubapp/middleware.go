@@ -15,9 +15,11 @@
 package githubapp
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gregjones/httpcache"
 	"github.com/rcrowley/go-metrics"
@@ -34,11 +36,95 @@ const (
 
 	MetricsKeyRateLimit          = "github.rate.limit"
 	MetricsKeyRateLimitRemaining = "github.rate.remaining"
+
+	MetricsKeyEndpointRequests = "github.endpoint.requests"
+	MetricsKeyEndpointLatency  = "github.endpoint.latency"
 )
 
+// ClientMetricsOption configures properties of client metrics.
+type ClientMetricsOption func(*clientMetricsOptions)
+
+type clientMetricsOptions struct {
+	endpoints        bool
+	installationTags *InstallationTags
+	observer         ClientRequestObserver
+}
+
+// WithEndpointMetrics enables per-endpoint request counters and latency
+// timers. Metrics are tagged with the request method and the route template
+// of the path returned by EndpointTemplate, like
+// "github.endpoint.requests[endpoint:GET /repos/{owner}/{repo}/pulls/{number}]",
+// which keeps the number of distinct metrics small.
+func WithEndpointMetrics() ClientMetricsOption {
+	return func(opts *clientMetricsOptions) {
+		opts.endpoints = true
+	}
+}
+
+// WithRateLimitInstallationTags sets how rate limit metrics are tagged with
+// installations. By default, they are tagged with the ID of every
+// installation, as with InstallationTagsFull. Rate limits are gauges, so
+// installations that share a tag report the value of the most recent
+// request.
+func WithRateLimitInstallationTags(tags *InstallationTags) ClientMetricsOption {
+	return func(opts *clientMetricsOptions) {
+		opts.installationTags = tags
+	}
+}
+
+// ClientRequestObservation describes a request made by a client for a
+// ClientRequestObserver.
+type ClientRequestObservation struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// Endpoint is the route template of the request path, as returned by
+	// EndpointTemplate.
+	Endpoint string
+
+	// StatusCode is the status of the response, or 0 if the request failed
+	// without a response.
+	StatusCode int
+
+	// InstallationID is the ID of the installation that made the request, or
+	// 0 for application and unauthenticated requests.
+	InstallationID int64
+
+	// Elapsed is the duration of the request.
+	Elapsed time.Duration
+
+	// Err is the error returned by the transport, if any.
+	Err error
+}
+
+// ClientRequestObserver is called after each request made by a client. The
+// context is the context of the request, which usually contains the trace
+// span of the handler that made it.
+//
+// This allows recording request latency in metric systems that support
+// exemplars, like Prometheus histograms, with the ID of the current trace as
+// the exemplar, without adding a dependency to this package.
+type ClientRequestObserver func(ctx context.Context, o ClientRequestObservation)
+
+// WithClientRequestObserver sets a function that is called after each
+// request, in addition to recording the standard metrics. The observer is
+// called synchronously, so it should not block.
+func WithClientRequestObserver(observer ClientRequestObserver) ClientMetricsOption {
+	return func(opts *clientMetricsOptions) {
+		opts.observer = observer
+	}
+}
+
 // ClientMetrics creates client middleware that records metrics about all
 // requests. It also defines the metrics in the provided registry.
-func ClientMetrics(registry metrics.Registry) ClientMiddleware {
+func ClientMetrics(registry metrics.Registry, opts ...ClientMetricsOption) ClientMiddleware {
+	options := clientMetricsOptions{
+		installationTags: NewInstallationTags(InstallationTagsFull, 0),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	for _, key := range []string{
 		MetricsKeyRequests,
 		MetricsKeyRequests2xx,
@@ -59,7 +145,29 @@ func ClientMetrics(registry metrics.Registry) ClientMiddleware {
 				installationID = 0
 			}
 
+			start := time.Now()
 			res, err := next.RoundTrip(r)
+			elapsed := time.Since(start)
+
+			if options.observer != nil {
+				o := ClientRequestObservation{
+					Method:         r.Method,
+					Endpoint:       EndpointTemplate(r.URL.Path),
+					InstallationID: installationID,
+					Elapsed:        elapsed,
+					Err:            err,
+				}
+				if res != nil {
+					o.StatusCode = res.StatusCode
+				}
+				options.observer(r.Context(), o)
+			}
+
+			if options.endpoints {
+				endpoint := fmt.Sprintf("[endpoint:%s %s]", r.Method, EndpointTemplate(r.URL.Path))
+				metrics.GetOrRegisterCounter(MetricsKeyEndpointRequests+endpoint, registry).Inc(1)
+				metrics.GetOrRegisterTimer(MetricsKeyEndpointLatency+endpoint, registry).Update(elapsed)
+			}
 
 			if res != nil {
 				registry.Get(MetricsKeyRequests).(metrics.Counter).Inc(1)
@@ -71,8 +179,9 @@ func ClientMetrics(registry metrics.Registry) ClientMiddleware {
 					registry.Get(MetricsKeyRequestsCached).(metrics.Counter).Inc(1)
 				}
 
-				limitMetric := fmt.Sprintf("%s[installation:%d]", MetricsKeyRateLimit, installationID)
-				remainingMetric := fmt.Sprintf("%s[installation:%d]", MetricsKeyRateLimitRemaining, installationID)
+				tags := rateLimitTags(options.installationTags.Tag(installationID), res.Header)
+				limitMetric := MetricsKeyRateLimit + tags
+				remainingMetric := MetricsKeyRateLimitRemaining + tags
 
 				// Headers from https://developer.github.com/v3/#rate-limiting
 				updateRegistryForHeader(res.Header, "X-RateLimit-Limit", metrics.GetOrRegisterGauge(limitMetric, registry))
@@ -84,6 +193,17 @@ func ClientMetrics(registry metrics.Registry) ClientMiddleware {
 	}
 }
 
+// rateLimitTags returns the metric tags for the rate limit resource used by a
+// response. Resources have independent quotas, so limits for resources other
+// than "core", like "search" or "graphql", include a resource tag.
+func rateLimitTags(installationTag string, headers http.Header) string {
+	resource := headers.Get("X-RateLimit-Resource")
+	if resource == "" || resource == RateLimitResourceCore {
+		return metricTags(installationTag)
+	}
+	return metricTags(installationTag, "resource:"+resource)
+}
+
 func updateRegistryForHeader(headers http.Header, header string, metric metrics.Gauge) {
 	headerString := headers.Get(header)
 	if headerString != "" {
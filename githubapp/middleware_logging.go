@@ -19,6 +19,9 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gregjones/httpcache"
@@ -29,6 +32,11 @@ import (
 // information at the given level. If the request fails without creating a
 // response, it is logged with a status code of -1. The middleware uses a
 // logger from the request context.
+//
+// Requests made by installation clients include the installation ID and
+// requests to repository endpoints include the repository owner and name,
+// using the same keys as handler logs. Use the OmitClientLogFields option if
+// the context logger already includes these fields.
 func ClientLogging(lvl zerolog.Level, opts ...ClientLoggingOption) ClientMiddleware {
 	var options clientLoggingOptions
 	for _, opt := range opts {
@@ -37,27 +45,42 @@ func ClientLogging(lvl zerolog.Level, opts ...ClientLoggingOption) ClientMiddlew
 
 	return func(next http.RoundTripper) http.RoundTripper {
 		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			// skip all work, including body capture, if the event is disabled
+			evt := zerolog.Ctx(r.Context()).WithLevel(lvl)
+			if !evt.Enabled() {
+				return next.RoundTrip(r)
+			}
+
 			var err error
 			var reqBody, resBody []byte
 
+			var reqTruncated bool
 			if requestMatches(r, options.RequestBodyPatterns) {
-				if r, reqBody, err = mirrorRequestBody(r); err != nil {
+				var release func()
+				if r, reqBody, reqTruncated, release, err = mirrorRequestBody(r, options.MaxBodyBytes); err != nil {
+					evt.Discard()
 					return nil, err
 				}
+				defer release()
 			}
 
 			start := time.Now()
 			res, err := next.RoundTrip(r)
 			elapsed := time.Now().Sub(start)
 
-			evt := zerolog.Ctx(r.Context()).
-				WithLevel(lvl).
-				Str("method", r.Method).
+			evt.Str("method", r.Method).
 				Str("path", r.URL.String()).
 				Dur("elapsed", elapsed)
 
+			if !options.OmitFields {
+				addClientLogFields(evt, r)
+			}
+
 			if reqBody != nil {
 				evt.Bytes("request_body", reqBody)
+				if reqTruncated {
+					evt.Bool("request_body_truncated", true)
+				}
 			}
 
 			if res != nil {
@@ -65,15 +88,28 @@ func ClientLogging(lvl zerolog.Level, opts ...ClientLoggingOption) ClientMiddlew
 				evt.Bool("cached", cached).
 					Int("status", res.StatusCode)
 
+				if remaining, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining")); err == nil {
+					resource := res.Header.Get("X-RateLimit-Resource")
+					if resource == "" {
+						resource = RateLimitResourceCore
+					}
+					evt.Str("rate_limit_resource", resource).Int("rate_limit_remaining", remaining)
+				}
+
 				size := res.ContentLength
 				if requestMatches(r, options.ResponseBodyPatterns) {
-					if res, resBody, err = mirrorResponseBody(res); err != nil {
+					var resTruncated bool
+					if res, resBody, resTruncated, err = mirrorResponseBody(res, options.MaxBodyBytes); err != nil {
+						evt.Discard()
 						return res, err
 					}
-					if size < 0 {
+					if size < 0 && !resTruncated {
 						size = int64(len(resBody))
 					}
 					evt.Int64("size", size).Bytes("response_body", resBody)
+					if resTruncated {
+						evt.Bool("response_body_truncated", true)
+					}
 				} else {
 					evt.Int64("size", size)
 				}
@@ -95,6 +131,8 @@ type ClientLoggingOption func(*clientLoggingOptions)
 type clientLoggingOptions struct {
 	RequestBodyPatterns  []*regexp.Regexp
 	ResponseBodyPatterns []*regexp.Regexp
+	MaxBodyBytes         int64
+	OmitFields           bool
 }
 
 // LogRequestBody enables request body logging for requests to paths matching
@@ -117,41 +155,195 @@ func LogResponseBody(patterns ...string) ClientLoggingOption {
 	}
 }
 
-func mirrorRequestBody(r *http.Request) (*http.Request, []byte, error) {
+// LogBodyMaxBytes limits the size of request and response bodies logged by
+// LogRequestBody and LogResponseBody. Bodies larger than n bytes are
+// truncated and the log includes a "request_body_truncated" or
+// "response_body_truncated" field. The remainder of a truncated body is not
+// buffered. By default, bodies are logged in full.
+func LogBodyMaxBytes(n int64) ClientLoggingOption {
+	return func(opts *clientLoggingOptions) {
+		opts.MaxBodyBytes = n
+	}
+}
+
+// OmitClientLogFields disables the installation and repository fields that
+// ClientLogging adds to request logs. Use it when the context logger already
+// includes these fields, like the loggers prepared by PrepareRepoContext, to
+// avoid logging duplicate keys.
+func OmitClientLogFields() ClientLoggingOption {
+	return func(opts *clientLoggingOptions) {
+		opts.OmitFields = true
+	}
+}
+
+func addClientLogFields(evt *zerolog.Event, r *http.Request) {
+	if installationID, ok := r.Context().Value(installationKey).(int64); ok && installationID > 0 {
+		evt.Int64(LogKeyInstallationID, installationID)
+	}
+	if owner, repo, ok := repositoryFromPath(r.URL.Path); ok {
+		evt.Str(LogKeyRepositoryOwner, owner).Str(LogKeyRepositoryName, repo)
+	}
+}
+
+// repositoryFromPath returns the owner and name of the repository in a path
+// like "/repos/{owner}/{repo}/...", which may have a prefix like "/api/v3"
+func repositoryFromPath(path string) (owner, repo string, ok bool) {
+	_, rest, found := strings.Cut(path, "/repos/")
+	if !found {
+		return "", "", false
+	}
+
+	owner, rest, _ = strings.Cut(rest, "/")
+	repo, _, _ = strings.Cut(rest, "/")
+	if owner == "" || repo == "" {
+		return "", "", false
+	}
+	return owner, repo, true
+}
+
+const (
+	// maxPooledBufferSize is the capacity above which body buffers are not
+	// returned to the pool, so that rare large bodies do not pin memory
+	maxPooledBufferSize = 64 * 1024
+)
+
+var bodyBufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getBodyBuffer() *bytes.Buffer {
+	return bodyBufferPool.Get().(*bytes.Buffer)
+}
+
+func putBodyBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	buf.Reset()
+	bodyBufferPool.Put(buf)
+}
+
+// pooledBody is a response body backed by a pooled buffer. The buffer returns
+// to the pool when the body is closed.
+type pooledBody struct {
+	*bytes.Reader
+	buf *bytes.Buffer
+}
+
+func (b *pooledBody) Close() error {
+	if b.buf != nil {
+		putBodyBuffer(b.buf)
+		b.buf = nil
+		b.Reader = bytes.NewReader(nil)
+	}
+	return nil
+}
+
+// readBodyPrefix reads up to limit+1 bytes from r into buf, so the caller can
+// tell if r has more than limit bytes. If limit is not positive, it reads all
+// of r. It returns the bytes to log and true if those bytes are truncated.
+func readBodyPrefix(buf *bytes.Buffer, r io.Reader, limit int64) ([]byte, bool, error) {
+	if limit <= 0 {
+		_, err := buf.ReadFrom(r)
+		return buf.Bytes(), false, err
+	}
+
+	n, err := buf.ReadFrom(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if n > limit {
+		return buf.Bytes()[:limit], true, nil
+	}
+	return buf.Bytes(), false, nil
+}
+
+// prefixedBody is a body that was partially read to capture a prefix. It
+// reads the captured prefix and then the remaining data.
+type prefixedBody struct {
+	io.Reader
+	prefix io.Closer
+	rest   io.Closer
+}
+
+func (b *prefixedBody) Close() error {
+	_ = b.prefix.Close()
+	return b.rest.Close()
+}
+
+// mirrorRequestBody returns a copy of at most limit bytes of the request body,
+// a flag that is true if the copy is truncated, and a function that releases
+// the copy after it is no longer used. It returns a modified request if
+// reading the body consumes it.
+func mirrorRequestBody(r *http.Request, limit int64) (*http.Request, []byte, bool, func(), error) {
+	noop := func() {}
+
 	switch {
 	case r.Body == nil || r.Body == http.NoBody:
-		return r, []byte{}, nil
+		return r, []byte{}, false, noop, nil
 
 	case r.GetBody != nil:
 		br, err := r.GetBody()
 		if err != nil {
-			return r, nil, err
+			return r, nil, false, noop, err
 		}
-		body, err := io.ReadAll(br)
+		buf := getBodyBuffer()
+		logged, truncated, err := readBodyPrefix(buf, br, limit)
 		closeBody(br)
-		return r, body, err
+		if err != nil {
+			putBodyBuffer(buf)
+			return r, nil, false, noop, err
+		}
+		return r, logged, truncated, func() { putBodyBuffer(buf) }, nil
 
 	default:
-		body, err := io.ReadAll(r.Body)
-		closeBody(r.Body)
+		// the request keeps the body, so it cannot use a pooled buffer
+		var buf bytes.Buffer
+		logged, truncated, err := readBodyPrefix(&buf, r.Body, limit)
 		if err != nil {
-			return r, nil, err
+			closeBody(r.Body)
+			return r, nil, false, noop, err
 		}
+
 		rCopy := r.Clone(r.Context())
-		rCopy.Body = io.NopCloser(bytes.NewReader(body))
-		return rCopy, body, nil
+		if truncated {
+			rCopy.Body = &prefixedBody{
+				Reader: io.MultiReader(bytes.NewReader(buf.Bytes()), r.Body),
+				prefix: io.NopCloser(nil),
+				rest:   r.Body,
+			}
+		} else {
+			closeBody(r.Body)
+			rCopy.Body = io.NopCloser(bytes.NewReader(buf.Bytes()))
+		}
+		return rCopy, logged, truncated, noop, nil
 	}
 }
 
-func mirrorResponseBody(res *http.Response) (*http.Response, []byte, error) {
-	body, err := io.ReadAll(res.Body)
-	closeBody(res.Body)
+// mirrorResponseBody returns a copy of at most limit bytes of the response
+// body and a flag that is true if the copy is truncated. The copy is valid
+// until the modified response body is closed.
+func mirrorResponseBody(res *http.Response, limit int64) (*http.Response, []byte, bool, error) {
+	buf := getBodyBuffer()
+	logged, truncated, err := readBodyPrefix(buf, res.Body, limit)
 	if err != nil {
-		return res, nil, err
+		closeBody(res.Body)
+		putBodyBuffer(buf)
+		return res, nil, false, err
 	}
 
-	res.Body = io.NopCloser(bytes.NewReader(body))
-	return res, body, nil
+	body := &pooledBody{Reader: bytes.NewReader(buf.Bytes()), buf: buf}
+	if truncated {
+		res.Body = &prefixedBody{
+			Reader: io.MultiReader(body, res.Body),
+			prefix: body,
+			rest:   res.Body,
+		}
+	} else {
+		closeBody(res.Body)
+		res.Body = body
+	}
+	return res, logged, truncated, nil
 }
 
 func compileRegexps(pats []string) []*regexp.Regexp {
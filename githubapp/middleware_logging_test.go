@@ -48,6 +48,31 @@ func TestClientLogging(t *testing.T) {
 		})
 	})
 
+	t.Run("rateLimitResource", func(t *testing.T) {
+		req, out := newLoggingRequest("GET", "https://test.domain/search/issues", nil)
+		rt := http.RoundTripper(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			res := httptest.NewRecorder()
+			res.Header().Set("X-RateLimit-Remaining", "12")
+			res.Header().Set("X-RateLimit-Resource", "search")
+			res.WriteHeader(200)
+			return res.Result(), nil
+		}))
+
+		logMiddleware := ClientLogging(zerolog.InfoLevel)
+		rt = logMiddleware(rt)
+
+		_, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+
+		assertLogFields(t, out.Bytes(), map[string]interface{}{
+			"status":               float64(200),
+			"rate_limit_resource":  "search",
+			"rate_limit_remaining": float64(12),
+		})
+	})
+
 	t.Run("requestBodyNoMatch", func(t *testing.T) {
 		req, out := newLoggingRequest("GET", "https://test.domain/path", []byte("The request"))
 		rt := newStaticRoundTripper(200, []byte("The response"))
@@ -123,8 +148,187 @@ func TestClientLogging(t *testing.T) {
 			"response_body": missingField,
 		})
 	})
+
+	t.Run("responseBodyReadable", func(t *testing.T) {
+		req, _ := newLoggingRequest("GET", "https://test.domain/path", nil)
+		rt := newStaticRoundTripper(200, []byte("The response"))
+
+		logMiddleware := ClientLogging(zerolog.InfoLevel, LogResponseBody(".*"))
+		rt = logMiddleware(rt)
+
+		res, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+		defer closeBody(res.Body)
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading response: %v", err)
+		}
+		if string(body) != "The response" {
+			t.Errorf("incorrect response body: %q", body)
+		}
+	})
+
+	t.Run("requestBodyTruncated", func(t *testing.T) {
+		req, out := newLoggingRequest("POST", "https://test.domain/path", []byte("The request"))
+		req.GetBody = nil
+
+		var received []byte
+		var rt http.RoundTripper = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			received, _ = io.ReadAll(r.Body)
+			return newStaticRoundTripper(200, nil).RoundTrip(r)
+		})
+
+		logMiddleware := ClientLogging(zerolog.InfoLevel, LogRequestBody(".*"), LogBodyMaxBytes(3))
+		rt = logMiddleware(rt)
+
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+		if string(received) != "The request" {
+			t.Errorf("incorrect body sent to transport: %q", received)
+		}
+
+		assertLogFields(t, out.Bytes(), map[string]interface{}{
+			"request_body":           "The",
+			"request_body_truncated": true,
+		})
+	})
+
+	t.Run("responseBodyTruncated", func(t *testing.T) {
+		req, out := newLoggingRequest("GET", "https://test.domain/path", nil)
+		rt := newStaticRoundTripper(200, []byte("The response"))
+
+		logMiddleware := ClientLogging(zerolog.InfoLevel, LogResponseBody(".*"), LogBodyMaxBytes(3))
+		rt = logMiddleware(rt)
+
+		res, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+		defer closeBody(res.Body)
+
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			t.Fatalf("unexpected error reading response: %v", err)
+		}
+		if string(body) != "The response" {
+			t.Errorf("incorrect response body: %q", body)
+		}
+
+		assertLogFields(t, out.Bytes(), map[string]interface{}{
+			"response_body":           "The",
+			"response_body_truncated": true,
+		})
+	})
+
+	t.Run("responseBodyUnderLimit", func(t *testing.T) {
+		req, out := newLoggingRequest("GET", "https://test.domain/path", nil)
+		rt := newStaticRoundTripper(200, []byte("The response"))
+
+		logMiddleware := ClientLogging(zerolog.InfoLevel, LogResponseBody(".*"), LogBodyMaxBytes(100))
+		rt = logMiddleware(rt)
+
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+
+		assertLogFields(t, out.Bytes(), map[string]interface{}{
+			"response_body":           "The response",
+			"response_body_truncated": missingField,
+		})
+	})
+
+	t.Run("installationAndRepositoryFields", func(t *testing.T) {
+		req, out := newLoggingRequest("GET", "https://test.domain/api/v3/repos/octo/app/pulls/1", nil)
+		rt := ClientLogging(zerolog.InfoLevel)(newStaticRoundTripper(200, nil))
+		rt = setInstallationID(42)(rt)
+
+		_, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+
+		assertLogFields(t, out.Bytes(), map[string]interface{}{
+			LogKeyInstallationID:  float64(42),
+			LogKeyRepositoryOwner: "octo",
+			LogKeyRepositoryName:  "app",
+		})
+	})
+
+	t.Run("appClientFields", func(t *testing.T) {
+		req, out := newLoggingRequest("GET", "https://test.domain/app/installations", nil)
+		rt := ClientLogging(zerolog.InfoLevel)(newStaticRoundTripper(200, nil))
+		rt = setInstallationID(0)(rt)
+
+		_, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+
+		assertLogFields(t, out.Bytes(), map[string]interface{}{
+			LogKeyInstallationID:  missingField,
+			LogKeyRepositoryOwner: missingField,
+			LogKeyRepositoryName:  missingField,
+		})
+	})
+
+	t.Run("omitFields", func(t *testing.T) {
+		req, out := newLoggingRequest("GET", "https://test.domain/repos/octo/app", nil)
+		rt := ClientLogging(zerolog.InfoLevel, OmitClientLogFields())(newStaticRoundTripper(200, nil))
+		rt = setInstallationID(42)(rt)
+
+		_, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+
+		assertLogFields(t, out.Bytes(), map[string]interface{}{
+			LogKeyInstallationID:  missingField,
+			LogKeyRepositoryOwner: missingField,
+			LogKeyRepositoryName:  missingField,
+		})
+	})
+
+	t.Run("disabledLevelSkipsCapture", func(t *testing.T) {
+		req, out := newLoggingRequest("GET", "https://test.domain/path", nil)
+		req = req.WithContext(zerolog.New(out).Level(zerolog.WarnLevel).WithContext(req.Context()))
+
+		body := &trackingBody{Reader: bytes.NewReader([]byte("The response"))}
+		var rt http.RoundTripper = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: 200, Body: body}, nil
+		})
+
+		logMiddleware := ClientLogging(zerolog.InfoLevel, LogResponseBody(".*"))
+		rt = logMiddleware(rt)
+
+		res, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+		if res.Body != body || body.read {
+			t.Error("response body was captured for a disabled log level")
+		}
+		if out.Len() > 0 {
+			t.Errorf("unexpected log output: %s", out.String())
+		}
+	})
+}
+
+type trackingBody struct {
+	*bytes.Reader
+	read bool
 }
 
+func (b *trackingBody) Read(p []byte) (int, error) {
+	b.read = true
+	return b.Reader.Read(p)
+}
+
+func (b *trackingBody) Close() error { return nil }
+
 func newLoggingRequest(method, url string, body []byte) (*http.Request, *bytes.Buffer) {
 	var out bytes.Buffer
 	logger := zerolog.New(&out)
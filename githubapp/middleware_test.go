@@ -0,0 +1,134 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestClientMetricsRateLimitResources(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	remaining := map[string]string{"": "4000", "search": "25", "graphql": "4500"}
+	var rt http.RoundTripper = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		resource := r.URL.Query().Get("resource")
+
+		res := httptest.NewRecorder()
+		res.Header().Set("X-RateLimit-Limit", "5000")
+		res.Header().Set("X-RateLimit-Remaining", remaining[resource])
+		if resource != "" {
+			res.Header().Set("X-RateLimit-Resource", resource)
+		}
+		res.WriteHeader(http.StatusOK)
+		return res.Result(), nil
+	})
+	rt = setInstallationID(42)(ClientMetrics(registry)(rt))
+
+	for _, resource := range []string{"", "search", "graphql"} {
+		req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.github.com/?resource="+resource, nil)
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := map[string]int64{
+		"github.rate.remaining[installation:42]":                  4000,
+		"github.rate.remaining[installation:42,resource:search]":  25,
+		"github.rate.remaining[installation:42,resource:graphql]": 4500,
+	}
+	for name, value := range expected {
+		gauge, ok := registry.Get(name).(metrics.Gauge)
+		if !ok {
+			t.Errorf("missing metric %q", name)
+			continue
+		}
+		if gauge.Value() != value {
+			t.Errorf("incorrect value for %q: expected %d, actual %d", name, value, gauge.Value())
+		}
+	}
+}
+
+func TestClientMetricsRateLimitInstallationTags(t *testing.T) {
+	registry := metrics.NewRegistry()
+
+	var rt http.RoundTripper = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		res := httptest.NewRecorder()
+		res.Header().Set("X-RateLimit-Limit", "5000")
+		res.Header().Set("X-RateLimit-Remaining", "4000")
+		res.WriteHeader(http.StatusOK)
+		return res.Result(), nil
+	})
+	rt = setInstallationID(42)(ClientMetrics(registry, WithRateLimitInstallationTags(nil))(rt))
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "https://api.github.com/", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gauge, ok := registry.Get("github.rate.remaining").(metrics.Gauge); !ok || gauge.Value() != 4000 {
+		t.Errorf("expected untagged rate limit metric, but got %v", registry.Get("github.rate.remaining"))
+	}
+}
+
+func TestClientMetricsRequestObserver(t *testing.T) {
+	type traceKey struct{}
+
+	var observed []ClientRequestObservation
+	var traces []string
+	observer := func(ctx context.Context, o ClientRequestObservation) {
+		trace, _ := ctx.Value(traceKey{}).(string)
+		traces = append(traces, trace)
+		observed = append(observed, o)
+	}
+
+	var rt http.RoundTripper = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodPost {
+			return nil, errors.New("connection reset")
+		}
+		res := httptest.NewRecorder()
+		res.WriteHeader(http.StatusNotFound)
+		return res.Result(), nil
+	})
+	rt = setInstallationID(42)(ClientMetrics(metrics.NewRegistry(), WithClientRequestObserver(observer))(rt))
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "trace-1")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/repos/octo/repo/pulls/12", nil)
+	if _, err := rt.RoundTrip(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req, _ = http.NewRequestWithContext(ctx, http.MethodPost, "https://api.github.com/repos/octo/repo/issues/12/comments", nil)
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+
+	if len(observed) != 2 {
+		t.Fatalf("expected 2 observations, but got %d", len(observed))
+	}
+	if o := observed[0]; o.Method != "GET" || o.Endpoint != "/repos/{owner}/{repo}/pulls/{number}" || o.StatusCode != 404 || o.InstallationID != 42 || o.Err != nil {
+		t.Errorf("incorrect observation: %+v", o)
+	}
+	if o := observed[1]; o.Method != "POST" || o.StatusCode != 0 || o.Err == nil {
+		t.Errorf("incorrect observation: %+v", o)
+	}
+	if traces[0] != "trace-1" || traces[1] != "trace-1" {
+		t.Errorf("observer did not receive request context: %v", traces)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	MetricsKeyTransportDNS     = "github.transport.dns"
+	MetricsKeyTransportConnect = "github.transport.connect"
+	MetricsKeyTransportTLS     = "github.transport.tls"
+	MetricsKeyTransportTTFB    = "github.transport.ttfb"
+
+	MetricsKeyTransportConnNew    = "github.transport.conn.new"
+	MetricsKeyTransportConnReused = "github.transport.conn.reused"
+)
+
+// ClientTransportMetrics creates client middleware that records connection
+// metrics for requests using net/http/httptrace: the duration of DNS
+// lookups, TCP connections, and TLS handshakes, the time GitHub takes to
+// start responding, and whether requests use new or reused connections.
+// Comparing these metrics with total request latency shows whether slow
+// requests are caused by GitHub or by local connection churn.
+//
+// Responses served from a client cache do not use a connection and are not
+// recorded.
+func ClientTransportMetrics(registry metrics.Registry) ClientMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			t := &transportTrace{registry: registry}
+			r = r.WithContext(httptrace.WithClientTrace(r.Context(), t.clientTrace()))
+			return next.RoundTrip(r)
+		})
+	}
+}
+
+// transportTrace records the metrics for a single request. Hooks may be
+// called concurrently, for instance when dialing multiple addresses.
+type transportTrace struct {
+	registry metrics.Registry
+
+	mu           sync.Mutex
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+}
+
+func (t *transportTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.start(&t.dnsStart)
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			if info.Err == nil {
+				t.done(&t.dnsStart, MetricsKeyTransportDNS)
+			}
+		},
+		ConnectStart: func(network, addr string) {
+			t.start(&t.connectStart)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				t.done(&t.connectStart, MetricsKeyTransportConnect)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.start(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.done(&t.tlsStart, MetricsKeyTransportTLS)
+			}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				metrics.GetOrRegisterCounter(MetricsKeyTransportConnReused, t.registry).Inc(1)
+			} else {
+				metrics.GetOrRegisterCounter(MetricsKeyTransportConnNew, t.registry).Inc(1)
+			}
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				t.start(&t.wroteRequest)
+			}
+		},
+		GotFirstResponseByte: func() {
+			t.done(&t.wroteRequest, MetricsKeyTransportTTFB)
+		},
+	}
+}
+
+// start records the start of a phase, keeping the earliest start if the
+// phase starts more than once.
+func (t *transportTrace) start(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+// done records the duration of a phase the first time it completes.
+func (t *transportTrace) done(at *time.Time, key string) {
+	t.mu.Lock()
+	start := *at
+	*at = time.Time{}
+	t.mu.Unlock()
+
+	if !start.IsZero() {
+		metrics.GetOrRegisterTimer(key, t.registry).UpdateSince(start)
+	}
+}
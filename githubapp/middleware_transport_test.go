@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestClientTransportMetrics(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	registry := metrics.NewRegistry()
+	client := &http.Client{
+		Transport: ClientTransportMetrics(registry)(srv.Client().Transport),
+	}
+
+	for i := 0; i < 2; i++ {
+		res, err := client.Get(srv.URL + "/repos/octo/repo")
+		if err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	}
+
+	counters := map[string]int64{
+		MetricsKeyTransportConnNew:    1,
+		MetricsKeyTransportConnReused: 1,
+	}
+	for key, expected := range counters {
+		if count := metrics.GetOrRegisterCounter(key, registry).Count(); count != expected {
+			t.Errorf("incorrect value for %s: expected %d, actual %d", key, expected, count)
+		}
+	}
+
+	timers := map[string]int64{
+		MetricsKeyTransportConnect: 1,
+		MetricsKeyTransportTLS:     1,
+		MetricsKeyTransportTTFB:    2,
+	}
+	for key, expected := range timers {
+		if count := metrics.GetOrRegisterTimer(key, registry).Count(); count != expected {
+			t.Errorf("incorrect count for %s: expected %d, actual %d", key, expected, count)
+		}
+	}
+}
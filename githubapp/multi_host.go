@@ -0,0 +1,150 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// HostSelector returns the name of the host that serves an installation, as
+// used in the map passed to NewMultiHostClientCreator.
+type HostSelector func(installationID int64) (string, error)
+
+// StaticHostSelector returns a HostSelector that looks up installations in
+// hosts. Installations that are not in the map use defaultHost.
+func StaticHostSelector(hosts map[int64]string, defaultHost string) HostSelector {
+	return func(installationID int64) (string, error) {
+		if host, ok := hosts[installationID]; ok {
+			return host, nil
+		}
+		return defaultHost, nil
+	}
+}
+
+// MultiHostClientCreator is a ClientCreator that serves installations from
+// several GitHub hosts, like a GitHub Enterprise Server instance and
+// github.com, for one logical application.
+type MultiHostClientCreator interface {
+	ClientCreator
+
+	// Host returns the ClientCreator for the named host and true, or false
+	// if the host does not exist.
+	Host(name string) (ClientCreator, bool)
+}
+
+type multiHostClientCreator struct {
+	hosts       map[string]ClientCreator
+	defaultHost string
+	selector    HostSelector
+}
+
+// NewMultiHostClientCreator returns a ClientCreator that delegates to one of
+// several creators, keyed by host name. Each creator uses the base URLs and
+// app credentials for its host. Use it for organizations that are migrating
+// between GitHub Enterprise Server and github.com and run one deployment of
+// an application for both hosts.
+//
+// Installation clients use the creator for the host returned by selector.
+// App and token clients, which are not associated with an installation, use
+// the creator for defaultHost; call Host to create them for other hosts.
+//
+// Installation IDs are only unique within a host. If the same ID may exist on
+// several hosts, the selector cannot tell them apart and callers should use
+// Host to get the creator for the host that sent each event.
+func NewMultiHostClientCreator(hosts map[string]ClientCreator, defaultHost string, selector HostSelector) (MultiHostClientCreator, error) {
+	if _, ok := hosts[defaultHost]; !ok {
+		return nil, errors.Errorf("default host %q does not exist", defaultHost)
+	}
+	if selector == nil {
+		selector = StaticHostSelector(nil, defaultHost)
+	}
+	return &multiHostClientCreator{
+		hosts:       hosts,
+		defaultHost: defaultHost,
+		selector:    selector,
+	}, nil
+}
+
+func (c *multiHostClientCreator) Host(name string) (ClientCreator, bool) {
+	cc, ok := c.hosts[name]
+	return cc, ok
+}
+
+func (c *multiHostClientCreator) forInstallation(installationID int64) (ClientCreator, error) {
+	name, err := c.selector(installationID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to select host for installation %d", installationID)
+	}
+	cc, ok := c.hosts[name]
+	if !ok {
+		return nil, errors.Errorf("host %q for installation %d does not exist", name, installationID)
+	}
+	return cc, nil
+}
+
+// Invalidate removes cached clients for an installation if the creator for
+// its host implements ClientInvalidator.
+func (c *multiHostClientCreator) Invalidate(installationID int64) {
+	cc, err := c.forInstallation(installationID)
+	if err != nil {
+		return
+	}
+	if inv, ok := cc.(ClientInvalidator); ok {
+		inv.Invalidate(installationID)
+	}
+}
+
+func (c *multiHostClientCreator) NewAppClient() (*github.Client, error) {
+	return c.hosts[c.defaultHost].NewAppClient()
+}
+
+func (c *multiHostClientCreator) NewAppV4Client() (*githubv4.Client, error) {
+	return c.hosts[c.defaultHost].NewAppV4Client()
+}
+
+func (c *multiHostClientCreator) NewInstallationClient(installationID int64) (*github.Client, error) {
+	cc, err := c.forInstallation(installationID)
+	if err != nil {
+		return nil, err
+	}
+	return cc.NewInstallationClient(installationID)
+}
+
+func (c *multiHostClientCreator) NewInstallationV4Client(installationID int64) (*githubv4.Client, error) {
+	cc, err := c.forInstallation(installationID)
+	if err != nil {
+		return nil, err
+	}
+	return cc.NewInstallationV4Client(installationID)
+}
+
+func (c *multiHostClientCreator) NewTokenSourceClient(ts oauth2.TokenSource) (*github.Client, error) {
+	return c.hosts[c.defaultHost].NewTokenSourceClient(ts)
+}
+
+func (c *multiHostClientCreator) NewTokenSourceV4Client(ts oauth2.TokenSource) (*githubv4.Client, error) {
+	return c.hosts[c.defaultHost].NewTokenSourceV4Client(ts)
+}
+
+func (c *multiHostClientCreator) NewTokenClient(token string) (*github.Client, error) {
+	return c.hosts[c.defaultHost].NewTokenClient(token)
+}
+
+func (c *multiHostClientCreator) NewTokenV4Client(token string) (*githubv4.Client, error) {
+	return c.hosts[c.defaultHost].NewTokenV4Client(token)
+}
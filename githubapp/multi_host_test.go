@@ -0,0 +1,94 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestMultiHostClientCreator(t *testing.T) {
+	ghes := &countingClientCreator{}
+	dotcom := &countingClientCreator{}
+	hosts := map[string]ClientCreator{
+		"ghes":   ghes,
+		"dotcom": dotcom,
+	}
+
+	cc, err := NewMultiHostClientCreator(hosts, "ghes", StaticHostSelector(map[int64]string{
+		2: "dotcom",
+		3: "missing",
+	}, "ghes"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := cc.NewInstallationClient(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := cc.NewInstallationClient(2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ghes.count != 1 || dotcom.count != 1 {
+		t.Errorf("incorrect client counts: ghes=%d, dotcom=%d", ghes.count, dotcom.count)
+	}
+
+	if _, err := cc.NewInstallationClient(3); err == nil {
+		t.Error("expected error for unknown host, but got nil")
+	}
+
+	if host, ok := cc.Host("dotcom"); !ok || host != dotcom {
+		t.Errorf("incorrect host: %v, %t", host, ok)
+	}
+
+	t.Run("selectorError", func(t *testing.T) {
+		cc, err := NewMultiHostClientCreator(hosts, "ghes", func(int64) (string, error) {
+			return "", errors.New("lookup failed")
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := cc.NewInstallationClient(1); err == nil {
+			t.Error("expected error, but got nil")
+		}
+	})
+
+	t.Run("invalidDefaultHost", func(t *testing.T) {
+		if _, err := NewMultiHostClientCreator(hosts, "missing", nil); err == nil {
+			t.Error("expected error, but got nil")
+		}
+	})
+
+	t.Run("invalidatesCachedClients", func(t *testing.T) {
+		delegate := &countingClientCreator{}
+		caching, err := NewCachingClientCreator(delegate, DefaultCachingClientCapacity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		cc, err := NewMultiHostClientCreator(map[string]ClientCreator{"dotcom": caching}, "dotcom", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		first, _ := cc.NewInstallationClient(1)
+		cc.(ClientInvalidator).Invalidate(1)
+		second, _ := cc.NewInstallationClient(1)
+		if first == second || delegate.count != 2 {
+			t.Errorf("expected a new client after invalidation, but got %d clients", delegate.count)
+		}
+	})
+}
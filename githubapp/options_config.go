@@ -0,0 +1,326 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gregjones/httpcache"
+	"github.com/pkg/errors"
+)
+
+// Duration is a time.Duration that configuration files represent as a
+// string, like "30s" or "5m", in both YAML and JSON. Numbers are rejected
+// instead of read as nanoseconds.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return errors.Wrapf(err, "invalid duration %q", b)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return errors.Errorf("invalid duration %v: durations must be strings, like \"30s\"", v)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Errorf("invalid duration %s: durations must be strings, like \"30s\"", b)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// ClientCreatorOptions configures a client creator with values that can be
+// loaded from configuration files. Zero values use the same defaults as the
+// corresponding ClientOption. Options that need code, like middleware, are
+// set with the Options field.
+type ClientCreatorOptions struct {
+	// UserAgent is the base user agent of clients, as with
+	// WithClientUserAgent.
+	UserAgent string `yaml:"user_agent" json:"userAgent"`
+
+	// Timeout is the timeout of each request, as with WithClientTimeout.
+	Timeout Duration `yaml:"timeout" json:"timeout"`
+
+	// MaxIdleConnsPerHost is the number of idle connections kept for each
+	// host, as with WithMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host" json:"maxIdleConnsPerHost"`
+
+	// AppJWTReuse is how long clients reuse an application JWT, as with
+	// WithAppJWTReuse. If zero, clients use DefaultAppJWTReuse.
+	AppJWTReuse Duration `yaml:"app_jwt_reuse" json:"appJwtReuse"`
+
+	// HTTPCache enables an in-memory cache of responses for each client, as
+	// with WithClientCaching. AlwaysValidate makes the cache validate all
+	// saved responses with GitHub before returning them.
+	HTTPCache      bool `yaml:"http_cache" json:"httpCache"`
+	AlwaysValidate bool `yaml:"always_validate" json:"alwaysValidate"`
+
+	// ClientCacheCapacity is the number of installation clients the creator
+	// caches, as with NewCachingClientCreator. If zero, the creator caches
+	// DefaultCachingClientCapacity clients. If negative, the creator does
+	// not cache clients.
+	ClientCacheCapacity int `yaml:"client_cache_capacity" json:"clientCacheCapacity"`
+
+	// ClientCacheTTL is the maximum age of cached clients, as with
+	// WithCachingTTL. If zero, clients do not expire.
+	ClientCacheTTL Duration `yaml:"client_cache_ttl" json:"clientCacheTtl"`
+
+	// Options are applied after the options set by other fields.
+	Options []ClientOption `yaml:"-" json:"-"`
+}
+
+// NewClientCreatorFromOptions returns a ClientCreator for the app in c
+// configured by opts. It is equivalent to NewDefaultCachingClientCreator
+// with the ClientOption for each field of opts, for applications that load
+// client settings from configuration files.
+func NewClientCreatorFromOptions(c Config, opts ClientCreatorOptions) (ClientCreator, error) {
+	var clientOpts []ClientOption
+	if opts.UserAgent != "" {
+		clientOpts = append(clientOpts, WithClientUserAgent(opts.UserAgent))
+	}
+	if opts.Timeout > 0 {
+		clientOpts = append(clientOpts, WithClientTimeout(time.Duration(opts.Timeout)))
+	}
+	if opts.MaxIdleConnsPerHost > 0 {
+		clientOpts = append(clientOpts, WithMaxIdleConnsPerHost(opts.MaxIdleConnsPerHost))
+	}
+	if opts.AppJWTReuse != 0 {
+		clientOpts = append(clientOpts, WithAppJWTReuse(time.Duration(opts.AppJWTReuse)))
+	}
+	if opts.HTTPCache {
+		clientOpts = append(clientOpts, WithClientCaching(opts.AlwaysValidate, func() httpcache.Cache { return httpcache.NewMemoryCache() }))
+	}
+	clientOpts = append(clientOpts, opts.Options...)
+
+	delegate := NewClientCreatorFromConfig(c, clientOpts...)
+
+	capacity := opts.ClientCacheCapacity
+	switch {
+	case capacity < 0:
+		return delegate, nil
+	case capacity == 0:
+		capacity = DefaultCachingClientCapacity
+	}
+
+	var cachingOpts []CachingClientOption
+	if opts.ClientCacheTTL > 0 {
+		cachingOpts = append(cachingOpts, WithCachingTTL(time.Duration(opts.ClientCacheTTL)))
+	}
+	return NewCachingClientCreator(delegate, capacity, cachingOpts...)
+}
+
+// Types of schedulers created by NewSchedulerFromOptions.
+const (
+	SchedulerTypeDefault = "default"
+	SchedulerTypeAsync   = "async"
+	SchedulerTypeBounded = "bounded"
+	SchedulerTypeQueue   = "queue"
+)
+
+// SchedulerOptions configures a scheduler with values that can be loaded
+// from configuration files. Options that need code, like error callbacks and
+// metrics, are set with the Options field.
+type SchedulerOptions struct {
+	// Type is the type of scheduler. The default is SchedulerTypeDefault,
+	// which executes handlers synchronously, as with DefaultScheduler.
+	Type string `yaml:"type" json:"type"`
+
+	// QueueSize and Workers configure a SchedulerTypeQueue scheduler, as with
+	// QueueAsyncScheduler.
+	QueueSize int `yaml:"queue_size" json:"queueSize"`
+	Workers   int `yaml:"workers" json:"workers"`
+
+	// MaxConcurrent and Overflow configure a SchedulerTypeBounded scheduler,
+	// as with BoundedAsyncScheduler. The default overflow policy is
+	// OverflowReject.
+	MaxConcurrent int            `yaml:"max_concurrent" json:"maxConcurrent"`
+	Overflow      OverflowPolicy `yaml:"overflow" json:"overflow"`
+
+	// EventTypeQueueLimits limits the queued dispatches of each event type,
+	// as with WithEventTypeQueueLimits.
+	EventTypeQueueLimits map[string]int `yaml:"event_type_queue_limits" json:"eventTypeQueueLimits"`
+
+	// InstallationQuota and InstallationQuotaOverrides limit the share of the
+	// scheduler used by each installation, as with WithInstallationQuotas.
+	InstallationQuota          InstallationQuota           `yaml:"installation_quota" json:"installationQuota"`
+	InstallationQuotaOverrides map[int64]InstallationQuota `yaml:"installation_quota_overrides" json:"installationQuotaOverrides"`
+
+	// DeletedInstallationTTL enables WithDeletedInstallationDrops with this
+	// TTL if it is positive.
+	DeletedInstallationTTL Duration `yaml:"deleted_installation_ttl" json:"deletedInstallationTtl"`
+
+	// Options are applied after the options set by other fields.
+	Options []SchedulerOption `yaml:"-" json:"-"`
+}
+
+// NewSchedulerFromOptions returns the scheduler configured by opts. Unlike
+// the scheduler constructors, it returns an error instead of panicking if
+// the options are invalid. It also returns an error if opts sets fields that
+// the type of scheduler ignores, like Workers for a bounded scheduler or
+// Options for the default scheduler.
+func NewSchedulerFromOptions(opts SchedulerOptions) (Scheduler, error) {
+	if ignored := opts.ignoredFields(); len(ignored) > 0 {
+		typ := opts.Type
+		if typ == "" {
+			typ = SchedulerTypeDefault
+		}
+		return nil, errors.Errorf("%s scheduler does not use %s", typ, strings.Join(ignored, ", "))
+	}
+
+	var schedOpts []SchedulerOption
+	if len(opts.EventTypeQueueLimits) > 0 {
+		schedOpts = append(schedOpts, WithEventTypeQueueLimits(opts.EventTypeQueueLimits))
+	}
+	if opts.InstallationQuota != (InstallationQuota{}) || len(opts.InstallationQuotaOverrides) > 0 {
+		schedOpts = append(schedOpts, WithInstallationQuotas(opts.InstallationQuota, opts.InstallationQuotaOverrides))
+	}
+	if opts.DeletedInstallationTTL > 0 {
+		schedOpts = append(schedOpts, WithDeletedInstallationDrops(time.Duration(opts.DeletedInstallationTTL)))
+	}
+	schedOpts = append(schedOpts, opts.Options...)
+
+	switch opts.Type {
+	case "", SchedulerTypeDefault:
+		return DefaultScheduler(), nil
+	case SchedulerTypeAsync:
+		return AsyncScheduler(schedOpts...), nil
+	case SchedulerTypeBounded:
+		if opts.MaxConcurrent < 1 {
+			return nil, errors.Errorf("bounded scheduler requires a positive max_concurrent, got %d", opts.MaxConcurrent)
+		}
+		return BoundedAsyncScheduler(opts.MaxConcurrent, opts.Overflow, schedOpts...), nil
+	case SchedulerTypeQueue:
+		if opts.QueueSize < 0 {
+			return nil, errors.Errorf("queue scheduler requires a non-negative queue_size, got %d", opts.QueueSize)
+		}
+		if opts.Workers < 1 {
+			return nil, errors.Errorf("queue scheduler requires a positive number of workers, got %d", opts.Workers)
+		}
+		return QueueAsyncScheduler(opts.QueueSize, opts.Workers, schedOpts...), nil
+	default:
+		return nil, errors.Errorf("unknown scheduler type %q", opts.Type)
+	}
+}
+
+// ignoredFields returns the names of the fields set in opts that schedulers
+// of the configured type ignore
+func (opts SchedulerOptions) ignoredFields() []string {
+	switch opts.Type {
+	case "", SchedulerTypeDefault, SchedulerTypeAsync, SchedulerTypeBounded, SchedulerTypeQueue:
+	default:
+		// NewSchedulerFromOptions rejects unknown types
+		return nil
+	}
+
+	queue := opts.Type == SchedulerTypeQueue
+	bounded := opts.Type == SchedulerTypeBounded
+	async := queue || bounded || opts.Type == SchedulerTypeAsync
+
+	var ignored []string
+	check := func(name string, set, used bool) {
+		if set && !used {
+			ignored = append(ignored, name)
+		}
+	}
+	check("queue_size", opts.QueueSize != 0, queue)
+	check("workers", opts.Workers != 0, queue)
+	check("max_concurrent", opts.MaxConcurrent != 0, bounded)
+	check("overflow", opts.Overflow != OverflowReject, bounded)
+	check("event_type_queue_limits", len(opts.EventTypeQueueLimits) > 0, queue)
+	check("installation_quota", opts.InstallationQuota != (InstallationQuota{}), queue)
+	check("installation_quota_overrides", len(opts.InstallationQuotaOverrides) > 0, queue)
+	check("deleted_installation_ttl", opts.DeletedInstallationTTL != 0, async)
+	check("options", len(opts.Options) > 0, async)
+	return ignored
+}
+
+// DispatcherOptions configures an event dispatcher with values that can be
+// loaded from configuration files. Options that need code, like callbacks,
+// are set with the Options field.
+type DispatcherOptions struct {
+	// Scheduler configures the scheduler of the dispatcher.
+	Scheduler SchedulerOptions `yaml:"scheduler" json:"scheduler"`
+
+	// MaxPayloadSize is the largest payload in bytes the dispatcher accepts,
+	// as with WithMaxPayloadSize.
+	MaxPayloadSize int64 `yaml:"max_payload_size" json:"maxPayloadSize"`
+
+	// UnhandledEventStatus is the status of responses to events without a
+	// handler, as with WithUnhandledEventStatus.
+	UnhandledEventStatus int `yaml:"unhandled_event_status" json:"unhandledEventStatus"`
+
+	// LoadShedding rejects events when the scheduler is saturated, as with
+	// WithLoadShedding. It is disabled if MaxSaturation is zero.
+	LoadShedding struct {
+		MaxSaturation float64  `yaml:"max_saturation" json:"maxSaturation"`
+		RetryAfter    Duration `yaml:"retry_after" json:"retryAfter"`
+	} `yaml:"load_shedding" json:"loadShedding"`
+
+	// Options are applied after the options set by other fields.
+	Options []DispatcherOption `yaml:"-" json:"-"`
+}
+
+// NewEventDispatcherFromOptions returns an event dispatcher for the app in c
+// configured by opts. Like NewDefaultEventDispatcherWithOptions, it validates payloads
+// with the webhook secret and checks ping events against the app. It also
+// applies the admission policy in c, if any. It returns an error if the
+// scheduler options are invalid.
+func NewEventDispatcherFromOptions(c Config, handlers []EventHandler, opts DispatcherOptions) (http.Handler, error) {
+	scheduler, err := NewSchedulerFromOptions(opts.Scheduler)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatcherOpts := []DispatcherOption{WithScheduler(scheduler)}
+	if !c.Admission.IsZero() {
+		dispatcherOpts = append(dispatcherOpts, WithAdmissionPolicy(c.Admission))
+	}
+	if opts.MaxPayloadSize > 0 {
+		dispatcherOpts = append(dispatcherOpts, WithMaxPayloadSize(opts.MaxPayloadSize))
+	}
+	if opts.UnhandledEventStatus != 0 {
+		dispatcherOpts = append(dispatcherOpts, WithUnhandledEventStatus(opts.UnhandledEventStatus))
+	}
+	if opts.LoadShedding.MaxSaturation > 0 {
+		dispatcherOpts = append(dispatcherOpts, WithLoadShedding(opts.LoadShedding.MaxSaturation, time.Duration(opts.LoadShedding.RetryAfter)))
+	}
+	dispatcherOpts = append(dispatcherOpts, opts.Options...)
+
+	return NewDefaultEventDispatcherWithOptions(c, handlers, dispatcherOpts...), nil
+}
@@ -0,0 +1,242 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestOptionsFromConfigFiles(t *testing.T) {
+	type appConfig struct {
+		Clients    ClientCreatorOptions `yaml:"clients" json:"clients"`
+		Dispatcher DispatcherOptions    `yaml:"dispatcher" json:"dispatcher"`
+	}
+
+	yamlConfig := `
+clients:
+  user_agent: my-app/1.0.0
+  timeout: 10s
+  client_cache_ttl: 1h
+dispatcher:
+  unhandled_event_status: 204
+  load_shedding:
+    max_saturation: 0.9
+    retry_after: 30s
+  scheduler:
+    type: bounded
+    max_concurrent: 20
+    overflow: run_sync
+    deleted_installation_ttl: 1h
+`
+	jsonConfig := `{
+		"clients": {"userAgent": "my-app/1.0.0", "timeout": "10s", "clientCacheTtl": "1h"},
+		"dispatcher": {
+			"unhandledEventStatus": 204,
+			"loadShedding": {"maxSaturation": 0.9, "retryAfter": "30s"},
+			"scheduler": {"type": "bounded", "maxConcurrent": 20, "overflow": "run_sync", "deletedInstallationTtl": "1h"}
+		}
+	}`
+
+	tests := map[string]func(*appConfig) error{
+		"yaml": func(c *appConfig) error { return yaml.UnmarshalStrict([]byte(yamlConfig), c) },
+		"json": func(c *appConfig) error { return json.Unmarshal([]byte(jsonConfig), c) },
+	}
+
+	for name, decode := range tests {
+		t.Run(name, func(t *testing.T) {
+			var c appConfig
+			if err := decode(&c); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c.Clients.UserAgent != "my-app/1.0.0" || time.Duration(c.Clients.Timeout) != 10*time.Second || time.Duration(c.Clients.ClientCacheTTL) != time.Hour {
+				t.Errorf("incorrect client options: %+v", c.Clients)
+			}
+
+			d := c.Dispatcher
+			if d.UnhandledEventStatus != 204 || d.LoadShedding.MaxSaturation != 0.9 || time.Duration(d.LoadShedding.RetryAfter) != 30*time.Second {
+				t.Errorf("incorrect dispatcher options: %+v", d)
+			}
+			if s := d.Scheduler; s.Type != SchedulerTypeBounded || s.MaxConcurrent != 20 || s.Overflow != OverflowRunSync || time.Duration(s.DeletedInstallationTTL) != time.Hour {
+				t.Errorf("incorrect scheduler options: %+v", s)
+			}
+		})
+	}
+
+	var d Duration
+	if err := yaml.Unmarshal([]byte(`ten seconds`), &d); err == nil {
+		t.Error("expected error for invalid duration, but got nil")
+	}
+	if err := yaml.Unmarshal([]byte(`30`), &d); err == nil {
+		t.Errorf("expected error for YAML duration without units, but got %v", time.Duration(d))
+	}
+	if err := json.Unmarshal([]byte(`30`), &d); err == nil {
+		t.Errorf("expected error for JSON number duration, but got %v", time.Duration(d))
+	}
+	var p OverflowPolicy
+	if err := json.Unmarshal([]byte(`"drop"`), &p); err == nil {
+		t.Error("expected error for unknown overflow policy, but got nil")
+	}
+}
+
+func TestNewSchedulerFromOptions(t *testing.T) {
+	tests := map[string]struct {
+		Options SchedulerOptions
+		Type    Scheduler
+		Err     bool
+	}{
+		"default": {
+			Type: &defaultScheduler{},
+		},
+		"async": {
+			Options: SchedulerOptions{Type: "async"},
+			Type:    &asyncScheduler{},
+		},
+		"bounded": {
+			Options: SchedulerOptions{Type: "bounded", MaxConcurrent: 4, Overflow: OverflowWait},
+			Type:    &boundedScheduler{},
+		},
+		"queue": {
+			Options: SchedulerOptions{Type: "queue", QueueSize: 10, Workers: 2, EventTypeQueueLimits: map[string]int{"status": 5}},
+			Type:    &queueScheduler{},
+		},
+		"queueWithoutWorkers": {
+			Options: SchedulerOptions{Type: "queue", QueueSize: 10},
+			Err:     true,
+		},
+		"boundedWithoutLimit": {
+			Options: SchedulerOptions{Type: "bounded"},
+			Err:     true,
+		},
+		"unknown": {
+			Options: SchedulerOptions{Type: "priority"},
+			Err:     true,
+		},
+		"defaultWithOptions": {
+			Options: SchedulerOptions{Options: []SchedulerOption{WithAsyncErrorCallback(DefaultAsyncErrorCallback)}},
+			Err:     true,
+		},
+		"defaultWithDeletedInstallationTTL": {
+			Options: SchedulerOptions{Type: "default", DeletedInstallationTTL: Duration(time.Hour)},
+			Err:     true,
+		},
+		"asyncWithWorkers": {
+			Options: SchedulerOptions{Type: "async", Workers: 2},
+			Err:     true,
+		},
+		"boundedWithQueueSize": {
+			Options: SchedulerOptions{Type: "bounded", MaxConcurrent: 4, QueueSize: 10},
+			Err:     true,
+		},
+		"boundedWithQuota": {
+			Options: SchedulerOptions{Type: "bounded", MaxConcurrent: 4, InstallationQuota: InstallationQuota{MaxQueued: 5}},
+			Err:     true,
+		},
+		"boundedWithEventTypeLimits": {
+			Options: SchedulerOptions{Type: "bounded", MaxConcurrent: 4, EventTypeQueueLimits: map[string]int{"status": 5}},
+			Err:     true,
+		},
+		"queueWithMaxConcurrent": {
+			Options: SchedulerOptions{Type: "queue", Workers: 2, MaxConcurrent: 4},
+			Err:     true,
+		},
+		"queueWithOverflow": {
+			Options: SchedulerOptions{Type: "queue", Workers: 2, Overflow: OverflowWait},
+			Err:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewSchedulerFromOptions(test.Options)
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := fmt.Sprintf("%T", s), fmt.Sprintf("%T", test.Type); got != want {
+				t.Errorf("incorrect scheduler type: expected %s, actual %s", want, got)
+			}
+		})
+	}
+
+	s, _ := NewSchedulerFromOptions(SchedulerOptions{Type: "queue", Workers: 1, EventTypeQueueLimits: map[string]int{"status": 5}})
+	if l := s.(*queueScheduler).eventLimits; l == nil || l.limits["status"] != 5 {
+		t.Errorf("event type limits were not applied: %+v", l)
+	}
+}
+
+func TestNewEventDispatcherFromOptions(t *testing.T) {
+	var c Config
+	c.App.WebhookSecret = testHookSecret
+
+	var opts DispatcherOptions
+	opts.UnhandledEventStatus = http.StatusNoContent
+
+	d, err := NewEventDispatcherFromOptions(c, []EventHandler{&TestEventHandler{
+		Types: []string{"issues"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			return nil
+		},
+	}}, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, newHookRequest("push", "unhandled", true))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("incorrect status for unhandled event: expected %d, actual %d", http.StatusNoContent, w.Code)
+	}
+
+	opts.Scheduler.Type = "queue"
+	if _, err := NewEventDispatcherFromOptions(c, nil, opts); err == nil {
+		t.Error("expected error for invalid scheduler options, but got nil")
+	}
+}
+
+func TestNewClientCreatorFromOptions(t *testing.T) {
+	var c Config
+	c.V3APIURL = "https://api.github.com/"
+	c.V4APIURL = "https://api.github.com/graphql"
+
+	cc, err := NewClientCreatorFromOptions(c, ClientCreatorOptions{ClientCacheTTL: Duration(time.Hour)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cc.(ClientInvalidator); !ok {
+		t.Errorf("expected caching client creator, but got %T", cc)
+	}
+
+	cc, err = NewClientCreatorFromOptions(c, ClientCreatorOptions{ClientCacheCapacity: -1, UserAgent: "my-app"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cc.(ClientInvalidator); ok {
+		t.Errorf("expected client creator without caching, but got %T", cc)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+// OrganizationPermissionsError is returned when the installation of the app
+// in an organization does not grant the permissions needed for an operation.
+// Organization owners must approve new permissions for each installation, so
+// an installation may grant less than the app requests.
+type OrganizationPermissionsError struct {
+	Organization   string
+	InstallationID int64
+
+	// MissingPermissions maps permission names to the required access for
+	// permissions that are not granted or are granted with less access.
+	MissingPermissions map[string]string
+}
+
+func (e OrganizationPermissionsError) Error() string {
+	return fmt.Sprintf("installation %d in organization %q is missing permissions: %s", e.InstallationID, e.Organization, formatPermissions(e.MissingPermissions))
+}
+
+// OrganizationInstallation returns the installation of the app in the
+// organization with login org and checks that it grants perms, which map
+// permission names, like "organization_administration" or
+// "organization_user_blocking", to the minimum access: "read", "write", or
+// "admin". It returns InstallationNotFound if the app is not installed in the
+// organization and OrganizationPermissionsError if permissions are missing.
+// Suspended installations and installations for users are also errors.
+func OrganizationInstallation(ctx context.Context, cc ClientCreator, org string, perms map[string]string) (Installation, error) {
+	client, err := cc.NewAppClient()
+	if err != nil {
+		return Installation{}, err
+	}
+
+	installation, _, err := client.Apps.FindOrganizationInstallation(ctx, org)
+	if err != nil {
+		if isNotFound(err) {
+			return Installation{}, InstallationNotFound(org)
+		}
+		return Installation{}, errors.Wrapf(err, "failed to get installation for organization %q", org)
+	}
+
+	if installation.GetTargetType() != "Organization" {
+		return Installation{}, errors.Errorf("installation %d for %q is not an organization installation", installation.GetID(), org)
+	}
+	if installation.SuspendedAt != nil {
+		return Installation{}, errors.Errorf("installation %d for organization %q is suspended", installation.GetID(), org)
+	}
+
+	granted, err := permissionsMap(installation.Permissions)
+	if err != nil {
+		return Installation{}, err
+	}
+	if missing := missingPermissions(granted, perms); len(missing) > 0 {
+		return Installation{}, OrganizationPermissionsError{
+			Organization:       org,
+			InstallationID:     installation.GetID(),
+			MissingPermissions: missing,
+		}
+	}
+
+	return toInstallation(installation), nil
+}
+
+// NewOrganizationClient returns an installation client for the organization
+// with login org, for organization-level APIs that do not have a repository
+// in their path, like organization settings, SCIM, or the audit log on
+// GitHub Enterprise Server. It finds and validates the installation with
+// OrganizationInstallation before creating the client, so missing
+// permissions are reported before the first request instead of as 403
+// responses.
+func NewOrganizationClient(ctx context.Context, cc ClientCreator, org string, perms map[string]string) (*github.Client, error) {
+	installation, err := OrganizationInstallation(ctx, cc, org, perms)
+	if err != nil {
+		return nil, err
+	}
+	return cc.NewInstallationClient(installation.ID)
+}
+
+// NewOrganizationV4Client is like NewOrganizationClient, but returns a client
+// for the v4 (GraphQL) API.
+func NewOrganizationV4Client(ctx context.Context, cc ClientCreator, org string, perms map[string]string) (*githubv4.Client, error) {
+	installation, err := OrganizationInstallation(ctx, cc, org, perms)
+	if err != nil {
+		return nil, err
+	}
+	return cc.NewInstallationV4Client(installation.ID)
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewOrganizationClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/{org}/installation", func(w http.ResponseWriter, r *http.Request) {
+		switch r.PathValue("org") {
+		case "octo":
+			_, _ = io.WriteString(w, `{"id": 1, "target_type": "Organization", "account": {"login": "octo"}, "permissions": {"organization_administration": "read", "members": "write"}}`)
+		case "suspended":
+			_, _ = io.WriteString(w, `{"id": 2, "target_type": "Organization", "suspended_at": "2026-01-01T00:00:00Z"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tests := map[string]struct {
+		Org   string
+		Perms map[string]string
+
+		Missing  map[string]string
+		NotFound bool
+		Err      bool
+	}{
+		"granted": {
+			Org:   "octo",
+			Perms: map[string]string{"organization_administration": "read", "members": "read"},
+		},
+		"missing": {
+			Org:     "octo",
+			Perms:   map[string]string{"organization_administration": "write", "organization_user_blocking": "read"},
+			Missing: map[string]string{"organization_administration": "write", "organization_user_blocking": "read"},
+			Err:     true,
+		},
+		"unknownAccess": {
+			Org:     "octo",
+			Perms:   map[string]string{"members": "writ"},
+			Missing: map[string]string{"members": "writ"},
+			Err:     true,
+		},
+		"notInstalled": {
+			Org:      "other",
+			NotFound: true,
+			Err:      true,
+		},
+		"suspended": {
+			Org: "suspended",
+			Err: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cc := newStaticClientCreator(t, mux)
+
+			client, err := NewOrganizationClient(context.Background(), cc, test.Org, test.Perms)
+			if !test.Err {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if client == nil {
+					t.Fatal("expected client, but got nil")
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error, but got nil")
+			}
+
+			var permErr OrganizationPermissionsError
+			if test.Missing != nil {
+				if !errors.As(err, &permErr) {
+					t.Fatalf("expected OrganizationPermissionsError, but got %T: %v", err, err)
+				}
+				if formatPermissions(permErr.MissingPermissions) != formatPermissions(test.Missing) {
+					t.Errorf("incorrect missing permissions: %v", permErr.MissingPermissions)
+				}
+			}
+
+			var notFound InstallationNotFound
+			if errors.As(err, &notFound) != test.NotFound {
+				t.Errorf("incorrect not found error: %v", err)
+			}
+		})
+	}
+}
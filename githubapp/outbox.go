@@ -0,0 +1,401 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultOutboxInterval    = time.Second
+	DefaultOutboxBatchSize   = 10
+	DefaultOutboxLease       = 5 * time.Minute
+	DefaultOutboxMaxAttempts = 10
+)
+
+// DefaultOutboxBackoff is the default backoff for records that fail. The
+// Timeout field is not used.
+var DefaultOutboxBackoff = Backoff{
+	InitialDelay: time.Second,
+	MaxDelay:     5 * time.Minute,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// OutboxRecord is a webhook delivery persisted in an OutboxStore.
+type OutboxRecord struct {
+	EventType  string
+	DeliveryID string
+	Payload    []byte
+	CreatedAt  time.Time
+
+	// Attempts is the number of times a consumer claimed the record.
+	Attempts int
+}
+
+// OutboxStore durably stores deliveries until they are handled. Records are
+// identified by delivery ID; GitHub reuses the ID when redelivering an event,
+// so stores should ignore saves for IDs they already contain.
+type OutboxStore interface {
+	// Save persists a record. It must not return until the record is
+	// committed, because the dispatcher acknowledges the delivery to GitHub
+	// after Save returns.
+	Save(ctx context.Context, r OutboxRecord) error
+
+	// Claim returns up to limit records that are ready for handling, in the
+	// order they were saved, and prevents other consumers from claiming them
+	// until they are completed or released or until the lease expires. The
+	// lease makes records available again if a consumer stops without
+	// completing or releasing them. Implementations should increment the
+	// Attempts field of claimed records.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxRecord, error)
+
+	// Complete removes a record after it is handled.
+	Complete(ctx context.Context, deliveryID string) error
+
+	// Release makes a claimed record available to consumers again at
+	// retryAt, after handling fails or if the consumer stops before handling
+	// the record.
+	Release(ctx context.Context, deliveryID string, retryAt time.Time) error
+}
+
+// OutboxDeadLetterFunc receives records that failed on their last allowed
+// attempt, with the error from that attempt. The record is removed from the
+// store after the function returns.
+type OutboxDeadLetterFunc func(ctx context.Context, r OutboxRecord, err error)
+
+// DefaultOutboxDeadLetter logs records that exhausted their attempts.
+func DefaultOutboxDeadLetter(ctx context.Context, r OutboxRecord, err error) {
+	zerolog.Ctx(ctx).Error().Err(err).Int("attempts", r.Attempts).Msg("Dropping outbox record after its final attempt")
+}
+
+// OutboxScheduler returns a scheduler that saves dispatches to store instead
+// of executing them. Use an OutboxConsumer to execute the saved dispatches.
+// Because the dispatcher waits for Schedule to return, GitHub only receives a
+// successful response after the delivery is committed to the store.
+func OutboxScheduler(store OutboxStore) Scheduler {
+	return &outboxScheduler{store: store}
+}
+
+type outboxScheduler struct {
+	store OutboxStore
+}
+
+func (s *outboxScheduler) Schedule(ctx context.Context, d Dispatch) error {
+	err := s.store.Save(ctx, OutboxRecord{
+		EventType:  d.EventType,
+		DeliveryID: d.DeliveryID,
+		Payload:    d.Payload,
+		CreatedAt:  GetClock(ctx).Now().UTC(),
+	})
+	return errors.Wrap(err, "failed to save delivery to outbox")
+}
+
+// OutboxConsumerOption configures properties of an outbox consumer.
+type OutboxConsumerOption func(*OutboxConsumer)
+
+// WithOutboxInterval sets how often a consumer checks for new records when
+// the store is empty. The default is DefaultOutboxInterval.
+func WithOutboxInterval(interval time.Duration) OutboxConsumerOption {
+	return func(c *OutboxConsumer) {
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// WithOutboxBatchSize sets the maximum number of records a consumer claims at
+// once. The default is DefaultOutboxBatchSize.
+func WithOutboxBatchSize(size int) OutboxConsumerOption {
+	return func(c *OutboxConsumer) {
+		if size > 0 {
+			c.batchSize = size
+		}
+	}
+}
+
+// WithOutboxLease sets how long records stay claimed by a consumer before
+// other consumers can claim them. It should be longer than the time to handle
+// a batch. The default is DefaultOutboxLease.
+func WithOutboxLease(lease time.Duration) OutboxConsumerOption {
+	return func(c *OutboxConsumer) {
+		if lease > 0 {
+			c.lease = lease
+		}
+	}
+}
+
+// WithOutboxBackoff sets the delays before records that fail are handled
+// again, based on the number of attempts. The default is
+// DefaultOutboxBackoff.
+func WithOutboxBackoff(backoff Backoff) OutboxConsumerOption {
+	return func(c *OutboxConsumer) {
+		c.backoff = backoff.withDefaults()
+	}
+}
+
+// WithOutboxMaxAttempts sets the number of times a consumer tries to handle
+// a record before passing it to the dead letter function. The default is
+// DefaultOutboxMaxAttempts.
+func WithOutboxMaxAttempts(attempts int) OutboxConsumerOption {
+	return func(c *OutboxConsumer) {
+		if attempts > 0 {
+			c.maxAttempts = attempts
+		}
+	}
+}
+
+// WithOutboxDeadLetter sets the function that receives records that fail on
+// their last attempt, like one that saves them to another table for later
+// inspection. The default is DefaultOutboxDeadLetter.
+func WithOutboxDeadLetter(deadLetter OutboxDeadLetterFunc) OutboxConsumerOption {
+	return func(c *OutboxConsumer) {
+		if deadLetter != nil {
+			c.deadLetter = deadLetter
+		}
+	}
+}
+
+// WithOutboxErrorCallback sets the callback for errors from handlers. If not
+// set, the consumer uses DefaultAsyncErrorCallback.
+func WithOutboxErrorCallback(onError AsyncErrorCallback) OutboxConsumerOption {
+	return func(c *OutboxConsumer) {
+		if onError != nil {
+			c.onError = onError
+		}
+	}
+}
+
+// OutboxConsumer executes handlers for records in an OutboxStore. Records are
+// completed if their handler succeeds or if no handler exists for the event
+// type, and released for another attempt after a backoff if the handler
+// fails or panics. Records that fail on their last attempt are passed to the
+// dead letter function and completed. Handlers should be idempotent, as a
+// record may be handled more than once.
+type OutboxConsumer struct {
+	store       OutboxStore
+	handlerMap  map[string]EventHandler
+	interval    time.Duration
+	batchSize   int
+	lease       time.Duration
+	backoff     Backoff
+	maxAttempts int
+	deadLetter  OutboxDeadLetterFunc
+	onError     AsyncErrorCallback
+}
+
+// NewOutboxConsumer creates a consumer that executes handlers for records in
+// store. The handlers are selected in the same way as NewEventDispatcher.
+func NewOutboxConsumer(store OutboxStore, handlers []EventHandler, opts ...OutboxConsumerOption) *OutboxConsumer {
+	c := &OutboxConsumer{
+		store:       store,
+		handlerMap:  newHandlerMap(handlers),
+		interval:    DefaultOutboxInterval,
+		batchSize:   DefaultOutboxBatchSize,
+		lease:       DefaultOutboxLease,
+		backoff:     DefaultOutboxBackoff,
+		maxAttempts: DefaultOutboxMaxAttempts,
+		deadLetter:  DefaultOutboxDeadLetter,
+		onError:     DefaultAsyncErrorCallback,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Run processes records until the context is canceled. Errors from the store
+// are logged and do not stop the consumer.
+func (c *OutboxConsumer) Run(ctx context.Context) error {
+	for {
+		n, err := c.ProcessBatch(ctx)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().Err(err).Msg("Failed to process outbox records")
+		}
+
+		// continue immediately if the batch was full, there may be more records
+		if err == nil && n == c.batchSize {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		if err := sleepContext(ctx, c.interval); err != nil {
+			return err
+		}
+	}
+}
+
+// ProcessBatch claims and handles a single batch of records. It returns the
+// number of records claimed. Errors from the store for one record do not
+// stop the consumer from handling the others; ProcessBatch returns all of
+// them. If the context is canceled during the batch, the remaining records
+// are released without handling them.
+func (c *OutboxConsumer) ProcessBatch(ctx context.Context) (int, error) {
+	records, err := c.store.Claim(ctx, c.batchSize, c.lease)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to claim outbox records")
+	}
+
+	var errs []error
+	for i, r := range records {
+		if ctx.Err() != nil {
+			errs = append(errs, c.releaseAll(ctx, records[i:])...)
+			break
+		}
+		if err := c.process(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(records), stderrors.Join(errs...)
+}
+
+// releaseAll releases records that the consumer claimed but did not handle.
+// It uses a context that is not canceled, as ctx usually is.
+func (c *OutboxConsumer) releaseAll(ctx context.Context, records []OutboxRecord) []error {
+	ctx = context.WithoutCancel(ctx)
+	now := GetClock(ctx).Now()
+
+	var errs []error
+	for _, r := range records {
+		if err := c.store.Release(ctx, r.DeliveryID, now); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to release outbox record %s", r.DeliveryID))
+		}
+	}
+	return errs
+}
+
+func (c *OutboxConsumer) process(ctx context.Context, r OutboxRecord) error {
+	logger := zerolog.Ctx(ctx).With().
+		Str(LogKeyEventType, r.EventType).
+		Str(LogKeyDeliveryID, r.DeliveryID).
+		Logger()
+	hctx := logger.WithContext(InitializeRateLimits(InitializeAsyncResponder(ctx)))
+
+	handler, ok := c.handlerMap[r.EventType]
+	if ok {
+		d := Dispatch{
+			Handler:    handler,
+			EventType:  r.EventType,
+			DeliveryID: r.DeliveryID,
+			Payload:    r.Payload,
+		}
+		if err := c.execute(hctx, d); err != nil {
+			c.onError(hctx, d, err)
+			if r.Attempts < c.maxAttempts {
+				retryAt := GetClock(ctx).Now().Add(c.backoff.delay(r.Attempts))
+				return errors.Wrapf(c.store.Release(ctx, r.DeliveryID, retryAt), "failed to release outbox record %s", r.DeliveryID)
+			}
+			c.deadLetter(hctx, r, err)
+		}
+	}
+	return errors.Wrapf(c.store.Complete(ctx, r.DeliveryID), "failed to complete outbox record %s", r.DeliveryID)
+}
+
+func (c *OutboxConsumer) execute(ctx context.Context, d Dispatch) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredPanic(ctx, r)
+		}
+	}()
+	return d.Execute(ctx)
+}
+
+// NewMemoryOutboxStore returns an OutboxStore that keeps records in memory.
+// It does not provide durability and is intended for tests and development.
+func NewMemoryOutboxStore() OutboxStore {
+	return &memoryOutboxStore{
+		records: make(map[string]*memoryOutboxRecord),
+	}
+}
+
+type memoryOutboxRecord struct {
+	OutboxRecord
+	seq          int64
+	claimedUntil time.Time
+	availableAt  time.Time
+}
+
+type memoryOutboxStore struct {
+	mu      sync.Mutex
+	seq     int64
+	records map[string]*memoryOutboxRecord
+}
+
+func (s *memoryOutboxStore) Save(ctx context.Context, r OutboxRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[r.DeliveryID]; !ok {
+		s.seq++
+		s.records[r.DeliveryID] = &memoryOutboxRecord{OutboxRecord: r, seq: s.seq}
+	}
+	return nil
+}
+
+func (s *memoryOutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := GetClock(ctx).Now()
+
+	var pending []*memoryOutboxRecord
+	for _, r := range s.records {
+		if !now.Before(r.claimedUntil) && !now.Before(r.availableAt) {
+			pending = append(pending, r)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].seq < pending[j].seq })
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	records := make([]OutboxRecord, 0, len(pending))
+	for _, r := range pending {
+		r.claimedUntil = now.Add(lease)
+		r.Attempts++
+		records = append(records, r.OutboxRecord)
+	}
+	return records, nil
+}
+
+func (s *memoryOutboxStore) Complete(ctx context.Context, deliveryID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, deliveryID)
+	return nil
+}
+
+func (s *memoryOutboxStore) Release(ctx context.Context, deliveryID string, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[deliveryID]; ok {
+		r.claimedUntil = time.Time{}
+		r.availableAt = retryAt
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOutbox(t *testing.T) {
+	clock := newTestClock()
+	ctx := WithClock(context.Background(), clock)
+	store := NewMemoryOutboxStore()
+
+	fail := true
+	handler := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			if deliveryID == "flaky" && fail {
+				fail = false
+				return errors.New("handler failure")
+			}
+			return nil
+		},
+	}
+
+	d := NewEventDispatcher([]EventHandler{handler}, testHookSecret, WithScheduler(OutboxScheduler(store)))
+	for _, id := range []string{"first", "flaky", "first"} {
+		w := httptest.NewRecorder()
+		d.ServeHTTP(w, newHookRequest("pull_request", id, true))
+		if w.Code != 200 {
+			t.Fatalf("incorrect response code: %d", w.Code)
+		}
+	}
+	if handler.Count != 0 {
+		t.Fatalf("handler was called before consuming the outbox")
+	}
+
+	var errs []error
+	c := NewOutboxConsumer(store, []EventHandler{handler}, WithOutboxErrorCallback(func(ctx context.Context, d Dispatch, err error) {
+		errs = append(errs, err)
+	}))
+
+	n, err := c.ProcessBatch(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 records in first batch, got %d", n)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected 1 handler error, got %d", len(errs))
+	}
+
+	if records, err := store.Claim(ctx, 10, time.Minute); err != nil || len(records) != 0 {
+		t.Fatalf("expected failed record to wait for its backoff: %+v, %v", records, err)
+	}
+
+	clock.Advance(DefaultOutboxBackoff.MaxDelay)
+	records, err := store.Claim(ctx, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 || records[0].DeliveryID != "flaky" || records[0].Attempts != 2 {
+		t.Fatalf("expected only the failed record to remain: %+v", records)
+	}
+	if err := store.Release(ctx, "flaky", clock.Now()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n, err := c.ProcessBatch(ctx); err != nil || n != 1 {
+		t.Fatalf("expected 1 record in second batch, got %d: %v", n, err)
+	}
+	if n, err := c.ProcessBatch(ctx); err != nil || n != 0 {
+		t.Fatalf("expected empty outbox, got %d: %v", n, err)
+	}
+	if handler.Count != 3 {
+		t.Errorf("incorrect handler call count: expected 3, actual %d", handler.Count)
+	}
+}
+
+func TestOutboxLease(t *testing.T) {
+	clock := newTestClock()
+	ctx := WithClock(context.Background(), clock)
+
+	store := NewMemoryOutboxStore()
+	if err := store.Save(ctx, OutboxRecord{EventType: "pull_request", DeliveryID: "abandoned"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if records, _ := store.Claim(ctx, 10, time.Minute); len(records) != 1 {
+		t.Fatalf("expected to claim 1 record, got %d", len(records))
+	}
+	if records, _ := store.Claim(ctx, 10, time.Minute); len(records) != 0 {
+		t.Fatalf("expected claimed record to be unavailable, got %d", len(records))
+	}
+
+	clock.Advance(time.Minute)
+	records, _ := store.Claim(ctx, 10, time.Minute)
+	if len(records) != 1 || records[0].Attempts != 2 {
+		t.Fatalf("expected record to be available after the lease expired: %+v", records)
+	}
+}
+
+func TestOutboxDeadLetter(t *testing.T) {
+	clock := newTestClock()
+	ctx := WithClock(context.Background(), clock)
+
+	handler := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			return errors.New("handler failure")
+		},
+	}
+
+	var dead []OutboxRecord
+	store := NewMemoryOutboxStore()
+	c := NewOutboxConsumer(store, []EventHandler{handler},
+		WithOutboxMaxAttempts(3),
+		WithOutboxErrorCallback(func(ctx context.Context, d Dispatch, err error) {}),
+		WithOutboxDeadLetter(func(ctx context.Context, r OutboxRecord, err error) {
+			dead = append(dead, r)
+		}),
+	)
+
+	if err := store.Save(ctx, OutboxRecord{EventType: "pull_request", DeliveryID: "failing"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if n, err := c.ProcessBatch(ctx); err != nil || n != 1 {
+			t.Fatalf("attempt %d: expected 1 record, got %d: %v", i+1, n, err)
+		}
+		clock.Advance(DefaultOutboxBackoff.MaxDelay * 2)
+	}
+
+	if handler.Count != 3 {
+		t.Errorf("incorrect handler call count: expected 3, actual %d", handler.Count)
+	}
+	if len(dead) != 1 || dead[0].DeliveryID != "failing" || dead[0].Attempts != 3 {
+		t.Errorf("expected record in dead letters after 3 attempts: %+v", dead)
+	}
+	if n, err := c.ProcessBatch(ctx); err != nil || n != 0 {
+		t.Errorf("expected empty outbox, got %d: %v", n, err)
+	}
+}
+
+func TestOutboxProcessBatchErrors(t *testing.T) {
+	ctx := context.Background()
+
+	handler := &TestEventHandler{Types: []string{"pull_request"}}
+	store := &failingOutboxStore{
+		OutboxStore: NewMemoryOutboxStore(),
+		failures:    map[string]bool{"first": true},
+	}
+	for _, id := range []string{"first", "second", "third"} {
+		if err := store.Save(ctx, OutboxRecord{EventType: "pull_request", DeliveryID: id}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	c := NewOutboxConsumer(store, []EventHandler{handler})
+	n, err := c.ProcessBatch(ctx)
+	if n != 3 || err == nil {
+		t.Fatalf("expected 3 records and an error, got %d: %v", n, err)
+	}
+	if handler.Count != 3 {
+		t.Errorf("expected all records to be handled after a store error, but handled %d", handler.Count)
+	}
+}
+
+func TestOutboxReleasesOnCancel(t *testing.T) {
+	clock := newTestClock()
+	ctx, cancel := context.WithCancel(WithClock(context.Background(), clock))
+
+	handler := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			cancel()
+			return nil
+		},
+	}
+
+	store := NewMemoryOutboxStore()
+	for _, id := range []string{"first", "second", "third"} {
+		if err := store.Save(ctx, OutboxRecord{EventType: "pull_request", DeliveryID: id}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	c := NewOutboxConsumer(store, []EventHandler{handler})
+	if n, err := c.ProcessBatch(ctx); n != 3 || err != nil {
+		t.Fatalf("expected 3 records, got %d: %v", n, err)
+	}
+	if handler.Count != 1 {
+		t.Errorf("expected 1 record to be handled before cancellation, got %d", handler.Count)
+	}
+
+	records, err := store.Claim(WithClock(context.Background(), clock), 10, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("expected unhandled records to be released immediately, got %+v", records)
+	}
+}
+
+type failingOutboxStore struct {
+	OutboxStore
+	failures map[string]bool
+}
+
+func (s *failingOutboxStore) Complete(ctx context.Context, deliveryID string) error {
+	if s.failures[deliveryID] {
+		return errors.New("store failure")
+	}
+	return s.OutboxStore.Complete(ctx, deliveryID)
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// PanicPolicy controls what happens when the library recovers a panic from a
+// handler it runs outside of a webhook request, like handlers run by
+// asynchronous schedulers, consumers, jobs, and backfills.
+type PanicPolicy int
+
+const (
+	// PanicPolicyRecover converts the panic to a HandlerPanicError and
+	// passes it to the component's error callback, which usually logs it.
+	// The process keeps running. This is the default.
+	PanicPolicyRecover PanicPolicy = iota
+
+	// PanicPolicyCrash logs the panic and then panics again with the
+	// HandlerPanicError, which crashes the process. Use it if a panic may
+	// leave shared state corrupted and a restart is safer than continuing.
+	PanicPolicyCrash
+
+	// PanicPolicyReport passes the HandlerPanicError to HandlerPanicReporter
+	// and then handles it like PanicPolicyRecover. Use it to send all
+	// panics to an error reporting service from one place.
+	PanicPolicyReport
+)
+
+// PanicReporter receives recovered handler panics when HandlerPanicPolicy
+// is PanicPolicyReport.
+type PanicReporter func(ctx context.Context, err HandlerPanicError)
+
+var (
+	// HandlerPanicPolicy is the policy for recovered handler panics. Set it
+	// before creating schedulers or starting consumers; it is not safe to
+	// change while handlers run.
+	HandlerPanicPolicy = PanicPolicyRecover
+
+	// HandlerPanicReporter receives panics when HandlerPanicPolicy is
+	// PanicPolicyReport.
+	HandlerPanicReporter PanicReporter
+)
+
+// recoveredPanic creates a HandlerPanicError for a value returned by
+// recover() and applies HandlerPanicPolicy. It must be called directly by
+// the deferred function that recovered the panic.
+func recoveredPanic(ctx context.Context, r interface{}) HandlerPanicError {
+	err := HandlerPanicError{
+		value: r,
+		stack: getStack(2),
+	}
+
+	switch HandlerPanicPolicy {
+	case PanicPolicyCrash:
+		zerolog.Ctx(ctx).Error().Str("stack", fmt.Sprintf("%+v", err)).Msg("Handler panicked, crashing because of the panic policy")
+		panic(err)
+	case PanicPolicyReport:
+		if HandlerPanicReporter != nil {
+			HandlerPanicReporter(ctx, err)
+		}
+	}
+	return err
+}
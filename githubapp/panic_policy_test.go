@@ -0,0 +1,98 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestHandlerPanicPolicy(t *testing.T) {
+	defer func(policy PanicPolicy, reporter PanicReporter) {
+		HandlerPanicPolicy = policy
+		HandlerPanicReporter = reporter
+	}(HandlerPanicPolicy, HandlerPanicReporter)
+
+	tests := map[string]struct {
+		Policy PanicPolicy
+
+		Reported bool
+		Crashed  bool
+	}{
+		"recover": {
+			Policy: PanicPolicyRecover,
+		},
+		"crash": {
+			Policy:  PanicPolicyCrash,
+			Crashed: true,
+		},
+		"report": {
+			Policy:   PanicPolicyReport,
+			Reported: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var reported []HandlerPanicError
+			HandlerPanicPolicy = test.Policy
+			HandlerPanicReporter = func(ctx context.Context, err HandlerPanicError) {
+				reported = append(reported, err)
+			}
+
+			errc := make(chan error, 1)
+			s := AsyncScheduler(WithAsyncErrorCallback(func(ctx context.Context, d Dispatch, err error) {
+				errc <- err
+			}))
+
+			var crash interface{}
+			func() {
+				defer func() { crash = recover() }()
+
+				// run the handler in the current goroutine so the test can
+				// recover the panic that would otherwise crash the process
+				s.(*asyncScheduler).safeExecute(context.Background(), Dispatch{Handler: panicHandler{}}, nil)
+			}()
+
+			if test.Crashed {
+				if _, ok := crash.(HandlerPanicError); !ok {
+					t.Fatalf("expected panic with HandlerPanicError, but got %v", crash)
+				}
+				return
+			}
+			if crash != nil {
+				t.Fatalf("unexpected panic: %v", crash)
+			}
+
+			var panicErr HandlerPanicError
+			if err := <-errc; !errors.As(err, &panicErr) || panicErr.Value() != "boom" {
+				t.Errorf("expected HandlerPanicError in error callback, but got %v", err)
+			}
+			if (len(reported) == 1) != test.Reported {
+				t.Errorf("incorrect reported panics: %v", reported)
+			}
+		})
+	}
+}
+
+type panicHandler struct{}
+
+func (panicHandler) Handles() []string { return []string{"ping"} }
+
+func (panicHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	panic("boom")
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxPayloadSize is the largest webhook payload accepted by an
+	// event dispatcher. GitHub does not send payloads larger than 25 MB.
+	DefaultMaxPayloadSize int64 = 25 << 20
+)
+
+// WithMaxPayloadSize sets the largest webhook payload, in bytes, that a
+// dispatcher accepts. Larger payloads fail validation. The default is
+// DefaultMaxPayloadSize.
+func WithMaxPayloadSize(size int64) DispatcherOption {
+	return func(d *eventDispatcher) {
+		if size > 0 {
+			d.maxPayloadSize = size
+		}
+	}
+}
+
+// validatePayload reads the body of a webhook request and validates its
+// signature. Unlike github.ValidatePayload, JSON payloads are hashed while
+// they are read, the buffer is sized once from the content length, and
+// requests larger than maxSize are rejected before reading the body.
+func validatePayload(r *http.Request, secret []byte, maxSize int64) ([]byte, error) {
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid content type")
+	}
+
+	if r.ContentLength > maxSize {
+		return nil, errors.Errorf("payload size %d exceeds the maximum of %d bytes", r.ContentLength, maxSize)
+	}
+
+	if contentType == "application/x-www-form-urlencoded" {
+		r.Body = http.MaxBytesReader(nil, r.Body, maxSize)
+		return github.ValidatePayload(r, secret)
+	}
+	if contentType != "application/json" {
+		return nil, errors.Errorf("webhook request has unsupported Content-Type %q", contentType)
+	}
+
+	var mac hash.Hash
+	var expected []byte
+	if len(secret) > 0 {
+		signature := r.Header.Get(github.SHA256SignatureHeader)
+		if signature == "" {
+			signature = r.Header.Get(github.SHA1SignatureHeader)
+		}
+		if mac, expected, err = parseSignature(signature, secret); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if r.ContentLength > 0 {
+		buf.Grow(int(r.ContentLength))
+	}
+
+	var w io.Writer = &buf
+	if mac != nil {
+		w = io.MultiWriter(&buf, mac)
+	}
+	if _, err := io.Copy(w, io.LimitReader(r.Body, maxSize+1)); err != nil {
+		return nil, errors.Wrap(err, "failed to read payload")
+	}
+	if int64(buf.Len()) > maxSize {
+		return nil, errors.Errorf("payload exceeds the maximum of %d bytes", maxSize)
+	}
+
+	if mac != nil && !hmac.Equal(expected, mac.Sum(nil)) {
+		return nil, errors.New("payload signature check failed")
+	}
+	return buf.Bytes(), nil
+}
+
+func parseSignature(signature string, secret []byte) (hash.Hash, []byte, error) {
+	if signature == "" {
+		return nil, nil, errors.New("missing signature")
+	}
+
+	alg, value, ok := strings.Cut(signature, "=")
+	if !ok {
+		return nil, nil, errors.Errorf("invalid signature format: %q", signature)
+	}
+
+	var h func() hash.Hash
+	switch alg {
+	case "sha256":
+		h = sha256.New
+	case "sha1":
+		h = sha1.New
+	default:
+		return nil, nil, errors.Errorf("unknown signature algorithm: %q", alg)
+	}
+
+	expected, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "invalid signature value")
+	}
+	return hmac.New(h, secret), expected, nil
+}
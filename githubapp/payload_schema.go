@@ -0,0 +1,344 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+//go:embed schemas/*.json
+var bundledSchemaFiles embed.FS
+
+var (
+	bundledSchemasOnce sync.Once
+	bundledSchemas     map[string]*PayloadSchema
+)
+
+// PayloadSchema is a JSON schema for the payload of a webhook event. It
+// supports the "type", "properties", "required", "items", and "enum"
+// keywords, which are enough to describe the structure handlers depend on.
+// Other keywords are ignored.
+type PayloadSchema struct {
+	root *schemaNode
+}
+
+type schemaNode struct {
+	Type       schemaTypes            `json:"type"`
+	Properties map[string]*schemaNode `json:"properties"`
+	Required   []string               `json:"required"`
+	Items      *schemaNode            `json:"items"`
+	Enum       []interface{}          `json:"enum"`
+}
+
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+
+	var multi []string
+	if err := json.Unmarshal(b, &multi); err != nil {
+		return errors.New("type must be a string or a list of strings")
+	}
+	*t = multi
+	return nil
+}
+
+// ParsePayloadSchema parses a JSON schema.
+func ParsePayloadSchema(b []byte) (*PayloadSchema, error) {
+	var root schemaNode
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&root); err != nil {
+		return nil, errors.Wrap(err, "failed to parse payload schema")
+	}
+	return &PayloadSchema{root: &root}, nil
+}
+
+// PayloadSchemaError is returned when a payload does not match the schema
+// for its event type.
+type PayloadSchemaError struct {
+	EventType string
+
+	// Path identifies the invalid value, like "$.pull_request.head.sha".
+	Path    string
+	Message string
+}
+
+func (e *PayloadSchemaError) Error() string {
+	return fmt.Sprintf("%s payload does not match schema: %s: %s", e.EventType, e.Path, e.Message)
+}
+
+// PayloadValidator checks webhook payloads against JSON schemas for their
+// event types. By default, it uses schemas bundled with this package for
+// common events. The bundled schemas only describe the fields that GitHub
+// always includes and that handlers commonly rely on, so they reject
+// truncated or mangled payloads without rejecting payloads that have new or
+// unusual fields.
+type PayloadValidator struct {
+	schemas map[string]*PayloadSchema
+}
+
+// PayloadValidatorOption configures properties of a PayloadValidator.
+type PayloadValidatorOption func(*PayloadValidator)
+
+// WithPayloadSchema sets the schema for an event type, replacing the bundled
+// schema, if any.
+func WithPayloadSchema(eventType string, schema *PayloadSchema) PayloadValidatorOption {
+	return func(v *PayloadValidator) {
+		if schema != nil {
+			v.schemas[eventType] = schema
+		}
+	}
+}
+
+// WithoutBundledPayloadSchemas removes the bundled schemas from the
+// validator, so it only checks event types with schemas set by
+// WithPayloadSchema. It must be the first option.
+func WithoutBundledPayloadSchemas() PayloadValidatorOption {
+	return func(v *PayloadValidator) {
+		v.schemas = make(map[string]*PayloadSchema)
+	}
+}
+
+// NewPayloadValidator creates a PayloadValidator with the bundled schemas and
+// the schemas set by options.
+func NewPayloadValidator(opts ...PayloadValidatorOption) *PayloadValidator {
+	v := &PayloadValidator{
+		schemas: make(map[string]*PayloadSchema),
+	}
+	for eventType, schema := range loadBundledSchemas() {
+		v.schemas[eventType] = schema
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// EventTypes returns the event types that have schemas.
+func (v *PayloadValidator) EventTypes() []string {
+	types := make([]string, 0, len(v.schemas))
+	for eventType := range v.schemas {
+		types = append(types, eventType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Validate checks that payload matches the schema for eventType. It returns
+// a *PayloadSchemaError if the payload does not match and nil if it matches
+// or if there is no schema for the event type.
+func (v *PayloadValidator) Validate(eventType string, payload []byte) error {
+	schema, ok := v.schemas[eventType]
+	if !ok {
+		return nil
+	}
+
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return &PayloadSchemaError{EventType: eventType, Path: "$", Message: err.Error()}
+	}
+
+	if path, msg := schema.root.validate("$", value); msg != "" {
+		return &PayloadSchemaError{EventType: eventType, Path: path, Message: msg}
+	}
+	return nil
+}
+
+// validate returns the path and a description of the first value that does
+// not match the schema, or an empty description if the value matches.
+func (n *schemaNode) validate(path string, value interface{}) (string, string) {
+	if n == nil {
+		return "", ""
+	}
+
+	if len(n.Type) > 0 {
+		actual := jsonType(value)
+		matched := false
+		for _, t := range n.Type {
+			if t == actual || (t == "number" && actual == "integer") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return path, fmt.Sprintf("expected %s, but got %s", strings.Join(n.Type, " or "), actual)
+		}
+	}
+
+	if len(n.Enum) > 0 {
+		matched := false
+		for _, e := range n.Enum {
+			if reflect.DeepEqual(e, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return path, fmt.Sprintf("value %v is not allowed", value)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range n.Required {
+			if _, ok := v[name]; !ok {
+				return path, fmt.Sprintf("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(n.Properties))
+		for name := range n.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if pv, ok := v[name]; ok {
+				if p, msg := n.Properties[name].validate(path+"."+name, pv); msg != "" {
+					return p, msg
+				}
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if p, msg := n.Items.validate(fmt.Sprintf("%s[%d]", path, i), item); msg != "" {
+				return p, msg
+			}
+		}
+	}
+	return "", ""
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func loadBundledSchemas() map[string]*PayloadSchema {
+	bundledSchemasOnce.Do(func() {
+		entries, err := bundledSchemaFiles.ReadDir("schemas")
+		if err != nil {
+			panic(err)
+		}
+
+		bundledSchemas = make(map[string]*PayloadSchema, len(entries))
+		for _, e := range entries {
+			b, err := bundledSchemaFiles.ReadFile("schemas/" + e.Name())
+			if err != nil {
+				panic(err)
+			}
+			schema, err := ParsePayloadSchema(b)
+			if err != nil {
+				panic(fmt.Sprintf("githubapp: invalid bundled schema %s: %v", e.Name(), err))
+			}
+			bundledSchemas[strings.TrimSuffix(e.Name(), ".json")] = schema
+		}
+	})
+	return bundledSchemas
+}
+
+// PayloadValidationMode determines what happens to payloads that do not
+// match their schema.
+type PayloadValidationMode int
+
+const (
+	// RejectInvalidPayloads passes payloads that do not match their schema
+	// to the error callback as a ValidationError instead of a handler.
+	RejectInvalidPayloads PayloadValidationMode = iota
+
+	// FlagInvalidPayloads logs a warning for payloads that do not match their
+	// schema and then handles them normally.
+	FlagInvalidPayloads
+)
+
+// WithPayloadValidator makes the dispatcher check the payloads of events
+// after verifying their signatures. This catches payloads that were
+// truncated or modified by relays and proxies before they reach handlers.
+func WithPayloadValidator(v *PayloadValidator, mode PayloadValidationMode) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.payloadValidation = newPayloadValidation(v, mode)
+	}
+}
+
+// WithConsumerPayloadValidator makes the consumer check the payloads of
+// messages after verifying their signatures. Rejected messages are passed to
+// the error callback and acknowledged.
+func WithConsumerPayloadValidator(v *PayloadValidator, mode PayloadValidationMode) EventConsumerOption {
+	return func(c *EventConsumer) {
+		c.payloadValidation = newPayloadValidation(v, mode)
+	}
+}
+
+type payloadValidation struct {
+	validator *PayloadValidator
+	mode      PayloadValidationMode
+}
+
+func newPayloadValidation(v *PayloadValidator, mode PayloadValidationMode) *payloadValidation {
+	if v == nil {
+		return nil
+	}
+	return &payloadValidation{validator: v, mode: mode}
+}
+
+// check returns an error if the payload does not match its schema and the
+// validation rejects invalid payloads.
+func (pv *payloadValidation) check(ctx context.Context, eventType string, payload []byte) error {
+	if pv == nil {
+		return nil
+	}
+
+	err := pv.validator.Validate(eventType, payload)
+	if err == nil || pv.mode == RejectInvalidPayloads {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Warn().Err(err).Msg("Webhook payload does not match schema")
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestPayloadValidator(t *testing.T) {
+	custom, err := ParsePayloadSchema([]byte(`{
+		"type": "object",
+		"required": ["action", "widget"],
+		"properties": {
+			"action": {"type": "string", "enum": ["created", "deleted"]},
+			"widget": {
+				"type": "object",
+				"required": ["id"],
+				"properties": {
+					"id": {"type": "integer"},
+					"size": {"type": ["number", "null"]},
+					"tags": {"type": "array", "items": {"type": "string"}}
+				}
+			}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	v := NewPayloadValidator(WithPayloadSchema("widget", custom))
+
+	tests := map[string]struct {
+		EventType string
+		Payload   string
+		Path      string
+	}{
+		"valid": {
+			EventType: "widget",
+			Payload:   `{"action": "created", "widget": {"id": 1, "size": 1.5, "tags": ["a", "b"], "extra": true}}`,
+		},
+		"validNull": {
+			EventType: "widget",
+			Payload:   `{"action": "created", "widget": {"id": 1, "size": null}}`,
+		},
+		"unknownEventType": {
+			EventType: "gadget",
+			Payload:   `not json`,
+		},
+		"malformed": {
+			EventType: "widget",
+			Payload:   `{"action": "created", "widget": {"id"`,
+			Path:      "$",
+		},
+		"missingRequired": {
+			EventType: "widget",
+			Payload:   `{"action": "created"}`,
+			Path:      "$",
+		},
+		"wrongType": {
+			EventType: "widget",
+			Payload:   `{"action": "created", "widget": {"id": 1.5}}`,
+			Path:      "$.widget.id",
+		},
+		"wrongItemType": {
+			EventType: "widget",
+			Payload:   `{"action": "created", "widget": {"id": 1, "tags": ["a", 2]}}`,
+			Path:      "$.widget.tags[1]",
+		},
+		"notInEnum": {
+			EventType: "widget",
+			Payload:   `{"action": "edited", "widget": {"id": 1}}`,
+			Path:      "$.action",
+		},
+		"bundled": {
+			EventType: "pull_request",
+			Payload:   `{"action": "opened", "number": "1"}`,
+			Path:      "$",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := v.Validate(test.EventType, []byte(test.Payload))
+			if test.Path == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var schemaErr *PayloadSchemaError
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("expected PayloadSchemaError, but got %v", err)
+			}
+			if schemaErr.Path != test.Path {
+				t.Errorf("incorrect path: expected %q, actual %q (%v)", test.Path, schemaErr.Path, err)
+			}
+		})
+	}
+}
+
+func TestPayloadValidatorWithoutBundledSchemas(t *testing.T) {
+	v := NewPayloadValidator(WithoutBundledPayloadSchemas())
+	if types := v.EventTypes(); len(types) != 0 {
+		t.Errorf("expected no event types, but got %v", types)
+	}
+	if err := v.Validate("pull_request", []byte(`{}`)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDispatcherPayloadValidation(t *testing.T) {
+	tests := map[string]struct {
+		Mode         PayloadValidationMode
+		ResponseCode int
+		CallCount    int
+	}{
+		"reject": {
+			Mode:         RejectInvalidPayloads,
+			ResponseCode: 400,
+		},
+		"flag": {
+			Mode:         FlagInvalidPayloads,
+			ResponseCode: 200,
+			CallCount:    1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := &TestEventHandler{Types: []string{"pull_request"}}
+			d := NewEventDispatcher([]EventHandler{h}, testHookSecret,
+				WithPayloadValidator(NewPayloadValidator(), test.Mode),
+			)
+
+			w := httptest.NewRecorder()
+			d.ServeHTTP(w, newHookRequest("pull_request", "delivery-id", true))
+
+			if w.Code != test.ResponseCode {
+				t.Errorf("incorrect response code: expected %d, actual %d", test.ResponseCode, w.Code)
+			}
+			if h.Count != test.CallCount {
+				t.Errorf("incorrect handler call count: expected %d, actual %d", test.CallCount, h.Count)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidatePayload(t *testing.T) {
+	payload := `{"action":"opened"}`
+
+	newRequest := func(contentType, body, signature string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/api/github/hook", strings.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+		if signature != "" {
+			r.Header.Set("X-Hub-Signature-256", signature)
+		}
+		return r
+	}
+
+	tests := map[string]struct {
+		Request *http.Request
+		Secret  string
+		MaxSize int64
+		Err     bool
+	}{
+		"valid": {
+			Request: newRequest("application/json", payload, SignPayload(testHookSecret, []byte(payload))),
+			Secret:  testHookSecret,
+		},
+		"validNoSecret": {
+			Request: newRequest("application/json", payload, ""),
+		},
+		"invalidSignature": {
+			Request: newRequest("application/json", payload, SignPayload("wrong", []byte(payload))),
+			Secret:  testHookSecret,
+			Err:     true,
+		},
+		"missingSignature": {
+			Request: newRequest("application/json", payload, ""),
+			Secret:  testHookSecret,
+			Err:     true,
+		},
+		"tooLarge": {
+			Request: newRequest("application/json", payload, SignPayload(testHookSecret, []byte(payload))),
+			Secret:  testHookSecret,
+			MaxSize: 8,
+			Err:     true,
+		},
+		"tooLargeUnknownLength": {
+			Request: func() *http.Request {
+				r := newRequest("application/json", "", SignPayload(testHookSecret, []byte(payload)))
+				r.Body = io.NopCloser(bytes.NewReader([]byte(payload)))
+				r.ContentLength = -1
+				return r
+			}(),
+			Secret:  testHookSecret,
+			MaxSize: 8,
+			Err:     true,
+		},
+		"form": {
+			Request: func() *http.Request {
+				body := url.Values{"payload": {payload}}.Encode()
+				return newRequest("application/x-www-form-urlencoded", body, SignPayload(testHookSecret, []byte(body)))
+			}(),
+			Secret: testHookSecret,
+		},
+		"unsupportedContentType": {
+			Request: newRequest("text/plain", payload, ""),
+			Err:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			maxSize := test.MaxSize
+			if maxSize == 0 {
+				maxSize = DefaultMaxPayloadSize
+			}
+
+			b, err := validatePayload(test.Request, []byte(test.Secret), maxSize)
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(b) != payload {
+				t.Errorf("incorrect payload: expected %q, but got %q", payload, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// Repository permission levels, from least to most access.
+const (
+	PermissionNone     = "none"
+	PermissionRead     = "read"
+	PermissionTriage   = "triage"
+	PermissionWrite    = "write"
+	PermissionMaintain = "maintain"
+	PermissionAdmin    = "admin"
+)
+
+var permissionRanks = map[string]int{
+	PermissionNone:     0,
+	PermissionRead:     1,
+	PermissionTriage:   2,
+	PermissionWrite:    3,
+	PermissionMaintain: 4,
+	PermissionAdmin:    5,
+}
+
+// PermissionAtLeast returns true if the actual permission level grants at
+// least the access of the required level. Unknown actual levels grant no
+// access and unknown required levels, like misspelled ones, are never met.
+func PermissionAtLeast(actual, required string) bool {
+	requiredRank, ok := permissionRanks[required]
+	if !ok {
+		return false
+	}
+	return permissionRanks[actual] >= requiredRank
+}
+
+// GetPermission returns the permission level of user on owner/repo. It
+// returns one of the Permission constants.
+//
+// The level is the user's role if GitHub reports a standard role. For custom
+// roles and for older GitHub Enterprise Server versions that do not report
+// roles, it falls back to the base permission, which is one of "none",
+// "read", "write", or "admin".
+func GetPermission(ctx context.Context, client *github.Client, owner, repo, user string) (string, error) {
+	level, _, err := client.Repositories.GetPermissionLevel(ctx, owner, repo, user)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get permission level for %q on %s/%s", user, owner, repo)
+	}
+
+	if _, ok := permissionRanks[level.GetRoleName()]; ok {
+		return level.GetRoleName(), nil
+	}
+	if _, ok := permissionRanks[level.GetPermission()]; ok {
+		return level.GetPermission(), nil
+	}
+	return PermissionNone, nil
+}
+
+// HasPermission returns true if user has at least the required permission
+// level on owner/repo. Use MembershipService.GetRepositoryPermission with
+// PermissionAtLeast to cache the result across calls.
+func HasPermission(ctx context.Context, client *github.Client, owner, repo, user, levelAtLeast string) (bool, error) {
+	permission, err := GetPermission(ctx, client, owner, repo, user)
+	if err != nil {
+		return false, err
+	}
+	return PermissionAtLeast(permission, levelAtLeast), nil
+}
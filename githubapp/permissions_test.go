@@ -0,0 +1,101 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestHasPermission(t *testing.T) {
+	responses := map[string]string{
+		"maintainer": `{"permission":"write","role_name":"maintain"}`,
+		"custom":     `{"permission":"write","role_name":"security-reviewer"}`,
+		"enterprise": `{"permission":"admin"}`,
+		"reader":     `{"permission":"read","role_name":"read"}`,
+		"nobody":     `{"permission":"none","role_name":""}`,
+	}
+
+	cc := newStaticClientCreator(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for user, body := range responses {
+			if r.URL.Path == "/repos/test/repo/collaborators/"+user+"/permission" {
+				_, _ = io.WriteString(w, body)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	tests := map[string]struct {
+		User     string
+		Required string
+		Expected bool
+	}{
+		"roleAboveRequired": {
+			User:     "maintainer",
+			Required: PermissionWrite,
+			Expected: true,
+		},
+		"roleBelowRequired": {
+			User:     "maintainer",
+			Required: PermissionAdmin,
+			Expected: false,
+		},
+		"customRoleUsesBasePermission": {
+			User:     "custom",
+			Required: PermissionWrite,
+			Expected: true,
+		},
+		"customRoleDoesNotExceedBasePermission": {
+			User:     "custom",
+			Required: PermissionMaintain,
+			Expected: false,
+		},
+		"missingRoleUsesBasePermission": {
+			User:     "enterprise",
+			Required: PermissionAdmin,
+			Expected: true,
+		},
+		"readCannotWrite": {
+			User:     "reader",
+			Required: PermissionWrite,
+			Expected: false,
+		},
+		"noneCannotRead": {
+			User:     "nobody",
+			Required: PermissionRead,
+			Expected: false,
+		},
+		"unknownRequiredLevel": {
+			User:     "maintainer",
+			Required: "writ",
+			Expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ok, err := HasPermission(context.Background(), cc.client, "test", "repo", test.User, test.Required)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != test.Expected {
+				t.Errorf("incorrect result: expected %t, actual %t", test.Expected, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// PingMismatchError describes the differences between the webhook
+// configuration in a ping event and the configuration the application
+// expects.
+type PingMismatchError struct {
+	Problems []string
+}
+
+func (e PingMismatchError) Error() string {
+	return "webhook configuration does not match application: " + strings.Join(e.Problems, "; ")
+}
+
+type pingEvent struct {
+	Hook struct {
+		Type   string   `json:"type"`
+		AppID  int64    `json:"app_id"`
+		Events []string `json:"events"`
+		Config struct {
+			ContentType string `json:"content_type"`
+		} `json:"config"`
+	} `json:"hook"`
+}
+
+// CheckPing compares the hook configuration in the payload of a ping event to
+// the expected application ID and events. It returns a PingMismatchError if
+// the hook belongs to a different application, does not use the JSON content
+// type, or is not subscribed to all of the events. An appID of 0 skips the
+// application check.
+func CheckPing(payload []byte, appID int64, events []string) error {
+	var ping pingEvent
+	if err := json.Unmarshal(payload, &ping); err != nil {
+		return errors.Wrap(err, "failed to parse ping event payload")
+	}
+	hook := ping.Hook
+
+	var problems []string
+	if appID > 0 && hook.AppID != 0 && hook.AppID != appID {
+		problems = append(problems, fmt.Sprintf("hook belongs to app %d, expected app %d", hook.AppID, appID))
+	}
+	if ct := hook.Config.ContentType; ct != "" && ct != "json" {
+		problems = append(problems, fmt.Sprintf("hook content type is %q, expected \"json\"", ct))
+	}
+
+	subscribed := make(map[string]bool)
+	for _, event := range hook.Events {
+		subscribed[event] = true
+	}
+	if !subscribed["*"] {
+		var missing []string
+		for _, event := range events {
+			if !subscribed[event] && !implicitAppEvents[event] {
+				missing = append(missing, event)
+			}
+		}
+		sort.Strings(missing)
+		if len(missing) > 0 {
+			problems = append(problems, "hook is missing events: "+strings.Join(missing, ", "))
+		}
+	}
+
+	if len(problems) > 0 {
+		return PingMismatchError{Problems: problems}
+	}
+	return nil
+}
+
+// WithPingCheck enables configuration checks for ping events, which GitHub
+// sends when a webhook is created or when a user requests a ping from the
+// application settings. The dispatcher checks the hook with CheckPing, using
+// the events of all registered handlers. Mismatches are logged as warnings
+// and included in the response body, which GitHub shows in the list of
+// recent deliveries.
+func WithPingCheck(appID int64) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.pingCheck = true
+		d.pingAppID = appID
+	}
+}
+
+func (d *eventDispatcher) checkPing(ctx context.Context, payload []byte) {
+	events := make([]string, 0, len(d.handlerMap))
+	for event := range d.handlerMap {
+		events = append(events, event)
+	}
+
+	err := CheckPing(payload, d.pingAppID, events)
+	if err == nil {
+		zerolog.Ctx(ctx).Info().Msg("Webhook configuration matches application")
+		return
+	}
+
+	zerolog.Ctx(ctx).Warn().Err(err).Msg("Webhook configuration check failed")
+	SetResponder(ctx, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = fmt.Fprintln(w, err.Error())
+	})
+}
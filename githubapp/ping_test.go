@@ -0,0 +1,99 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestCheckPing(t *testing.T) {
+	tests := map[string]struct {
+		Payload  string
+		AppID    int64
+		Events   []string
+		Problems []string
+	}{
+		"matches": {
+			Payload: `{"hook": {"type": "App", "app_id": 1, "events": ["pull_request", "push"], "config": {"content_type": "json"}}}`,
+			AppID:   1,
+			Events:  []string{"push", "pull_request", "installation"},
+		},
+		"wildcard": {
+			Payload: `{"hook": {"type": "Repository", "events": ["*"], "config": {"content_type": "json"}}}`,
+			AppID:   1,
+			Events:  []string{"push", "pull_request"},
+		},
+		"mismatches": {
+			Payload: `{"hook": {"type": "App", "app_id": 2, "events": ["push"], "config": {"content_type": "form"}}}`,
+			AppID:   1,
+			Events:  []string{"push", "pull_request", "check_run"},
+			Problems: []string{
+				"hook belongs to app 2, expected app 1",
+				`hook content type is "form", expected "json"`,
+				"hook is missing events: check_run, pull_request",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckPing([]byte(test.Payload), test.AppID, test.Events)
+			if len(test.Problems) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			var perr PingMismatchError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected PingMismatchError, but got %v", err)
+			}
+			if !reflect.DeepEqual(test.Problems, perr.Problems) {
+				t.Errorf("incorrect problems\nexpected: %q\n  actual: %q", test.Problems, perr.Problems)
+			}
+		})
+	}
+}
+
+func TestPingCheckDispatcher(t *testing.T) {
+	payload := []byte(`{"hook": {"type": "App", "app_id": 1, "events": ["push"], "config": {"content_type": "json"}}}`)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/github/hook", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "ping")
+	req.Header.Set("X-GitHub-Delivery", "id")
+	req.Header.Set("X-Hub-Signature-256", SignPayload(testHookSecret, payload))
+
+	h := &TestEventHandler{Types: []string{"push", "pull_request"}}
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithPingCheck(1))
+
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, but got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "hook is missing events: pull_request") {
+		t.Errorf("response does not describe mismatch: %q", w.Body.String())
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Attribute names set on messages published by a publisher handler. Message
+// buses can use these to filter and route messages without parsing payloads.
+const (
+	AttributeEventType      = "github_event_type"
+	AttributeDeliveryID     = "github_delivery_id"
+	AttributeAction         = "github_action"
+	AttributeInstallationID = "github_installation_id"
+	AttributeRepository     = "github_repository"
+	AttributeOrganization   = "github_organization"
+	AttributeSignature      = "github_signature"
+)
+
+// BusMessage is a webhook delivery published to a MessageBus.
+type BusMessage struct {
+	// Payload is the unmodified payload of the delivery.
+	Payload []byte
+
+	// Attributes describe the delivery. Empty attributes are omitted.
+	Attributes map[string]string
+}
+
+// MessageBus publishes messages to a topic, like an SNS topic, a Pub/Sub
+// topic, or a Kafka topic. Implementations are usually thin adapters around
+// the client library for the bus.
+type MessageBus interface {
+	// Publish sends a message. It must not return until the bus accepts the
+	// message, because GitHub considers the delivery successful when the
+	// handler returns.
+	Publish(ctx context.Context, m BusMessage) error
+}
+
+// PublisherOption configures properties of a publisher handler.
+type PublisherOption func(*publisherHandler)
+
+// WithPublisherSecret signs published payloads with secret and sets the
+// signature in the AttributeSignature attribute, so consumers can verify
+// that messages came from the publisher. Consumers that use
+// SourceMessageFromBus and an EventConsumer verify the signature
+// automatically when the consumer uses the same secret.
+func WithPublisherSecret(secret string) PublisherOption {
+	return func(h *publisherHandler) {
+		h.secret = secret
+	}
+}
+
+type publisherHandler struct {
+	bus        MessageBus
+	eventTypes []string
+	secret     string
+}
+
+// NewPublisherHandler returns an EventHandler that publishes deliveries of
+// the given event types to bus. Use it with an event dispatcher to validate
+// webhooks and fan them out to other internal services. Messages include
+// attributes with the event type, delivery ID, action, installation ID,
+// repository, and organization of each delivery.
+func NewPublisherHandler(bus MessageBus, eventTypes []string, opts ...PublisherOption) EventHandler {
+	h := &publisherHandler{
+		bus:        bus,
+		eventTypes: eventTypes,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *publisherHandler) Handles() []string {
+	return h.eventTypes
+}
+
+func (h *publisherHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	attrs := map[string]string{
+		AttributeEventType:  eventType,
+		AttributeDeliveryID: deliveryID,
+	}
+	if h.secret != "" {
+		attrs[AttributeSignature] = SignPayload(h.secret, payload)
+	}
+
+	if e, err := PeekEnvelopeContext(ctx, payload); err == nil {
+		setAttribute(attrs, AttributeAction, e.Action)
+		setAttribute(attrs, AttributeRepository, e.RepositoryFullName)
+		setAttribute(attrs, AttributeOrganization, e.OrganizationLogin)
+		if e.InstallationID > 0 {
+			attrs[AttributeInstallationID] = strconv.FormatInt(e.InstallationID, 10)
+		}
+	}
+
+	if err := h.bus.Publish(ctx, BusMessage{Payload: payload, Attributes: attrs}); err != nil {
+		return errors.Wrapf(err, "failed to publish %s event", eventType)
+	}
+	return nil
+}
+
+func setAttribute(attrs map[string]string, key, value string) {
+	if value != "" {
+		attrs[key] = value
+	}
+}
+
+// SourceMessageFromBus converts a message published by a publisher handler
+// into a SourceMessage for an EventConsumer. The id identifies the message
+// in the consuming EventSource.
+func SourceMessageFromBus(id string, m BusMessage) SourceMessage {
+	header := http.Header{}
+	header.Set("X-GitHub-Event", m.Attributes[AttributeEventType])
+	header.Set("X-GitHub-Delivery", m.Attributes[AttributeDeliveryID])
+	if signature := m.Attributes[AttributeSignature]; signature != "" {
+		header.Set("X-Hub-Signature-256", signature)
+	}
+	return SourceMessage{
+		ID:      id,
+		Header:  header,
+		Payload: m.Payload,
+	}
+}
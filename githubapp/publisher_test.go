@@ -0,0 +1,83 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testMessageBus struct {
+	messages []BusMessage
+	err      error
+}
+
+func (b *testMessageBus) Publish(ctx context.Context, m BusMessage) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.messages = append(b.messages, m)
+	return nil
+}
+
+func TestPublisherHandler(t *testing.T) {
+	ctx := context.Background()
+	payload := []byte(`{"action":"opened","installation":{"id":12},"repository":{"full_name":"octo/repo"},"organization":{"login":"octo"}}`)
+
+	bus := &testMessageBus{}
+	h := NewPublisherHandler(bus, []string{"pull_request"}, WithPublisherSecret(testHookSecret))
+
+	if err := h.Handle(ctx, "pull_request", "delivery", payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bus.messages) != 1 {
+		t.Fatalf("incorrect number of messages: %d", len(bus.messages))
+	}
+
+	expected := map[string]string{
+		AttributeEventType:      "pull_request",
+		AttributeDeliveryID:     "delivery",
+		AttributeAction:         "opened",
+		AttributeInstallationID: "12",
+		AttributeRepository:     "octo/repo",
+		AttributeOrganization:   "octo",
+		AttributeSignature:      SignPayload(testHookSecret, payload),
+	}
+	if !reflect.DeepEqual(expected, bus.messages[0].Attributes) {
+		t.Errorf("incorrect attributes\nexpected: %v\n  actual: %v", expected, bus.messages[0].Attributes)
+	}
+
+	t.Run("consumer", func(t *testing.T) {
+		handler := &TestEventHandler{Types: []string{"pull_request"}}
+		source := &testEventSource{messages: []SourceMessage{SourceMessageFromBus("1", bus.messages[0])}}
+
+		c := NewEventConsumer(source, []EventHandler{handler}, testHookSecret)
+		if _, err := c.ProcessBatch(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if handler.Count != 1 {
+			t.Errorf("expected consumer to handle published message, but handled %d", handler.Count)
+		}
+	})
+
+	t.Run("publishError", func(t *testing.T) {
+		h := NewPublisherHandler(&testMessageBus{err: errors.New("unavailable")}, []string{"pull_request"})
+		if err := h.Handle(ctx, "pull_request", "delivery", payload); err == nil {
+			t.Fatal("expected error, but got nil")
+		}
+	})
+}
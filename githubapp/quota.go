@@ -0,0 +1,291 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultQuotaMaxWait is the default longest time a QuotaManager waits
+	// for a rate limit to reset before rejecting a reservation.
+	DefaultQuotaMaxWait = time.Minute
+)
+
+// QuotaExceededError is returned when a QuotaManager cannot reserve the
+// requested quota for an installation.
+type QuotaExceededError struct {
+	InstallationID int64
+	Resource       string
+	Requested      int
+	Available      int
+	Reset          time.Time
+}
+
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("installation %d requested %d %s requests, but only %d are available until %s",
+		e.InstallationID, e.Requested, e.Resource, e.Available, e.Reset.Format(time.RFC3339))
+}
+
+// QuotaOption configures properties of a QuotaManager.
+type QuotaOption func(*QuotaManager)
+
+// WithQuotaResource sets the rate limit resource of reservations. The
+// default is RateLimitResourceCore.
+func WithQuotaResource(resource string) QuotaOption {
+	return func(m *QuotaManager) {
+		if resource != "" {
+			m.resource = resource
+		}
+	}
+}
+
+// WithQuotaMinRemaining sets how many requests the manager keeps in reserve
+// for work that does not reserve quota, like webhook handlers that make a
+// single request. The default is 0.
+func WithQuotaMinRemaining(minRemaining int) QuotaOption {
+	return func(m *QuotaManager) {
+		m.minRemaining = max(minRemaining, 0)
+	}
+}
+
+// WithQuotaMaxWait sets the longest time the manager waits for a rate limit
+// to reset when there is not enough quota for a reservation. If the reset is
+// further away, the reservation fails immediately. The default is
+// DefaultQuotaMaxWait. A zero or negative duration disables waiting.
+func WithQuotaMaxWait(maxWait time.Duration) QuotaOption {
+	return func(m *QuotaManager) {
+		m.maxWait = maxWait
+	}
+}
+
+// QuotaManager lets handlers reserve an estimated number of API requests for
+// an installation before starting work that makes many requests, so the work
+// is delayed or rejected up front instead of failing partway through when
+// the rate limit runs out. It uses the rate limits recorded by a
+// RateLimitTracker, which must be added to the client creator, and subtracts
+// the quota held by outstanding reservations.
+//
+// Reservations are held until they are released, even as the requests they
+// cover reduce the remaining quota reported by GitHub, so the manager is
+// conservative while work is in progress. Release reservations as soon as
+// the work is done.
+//
+// A QuotaManager is safe for concurrent use.
+type QuotaManager struct {
+	tracker      *RateLimitTracker
+	resource     string
+	minRemaining int
+	maxWait      time.Duration
+
+	mu       sync.Mutex
+	reserved map[int64]int
+}
+
+// NewQuotaManager creates a QuotaManager that reads rate limits from
+// tracker.
+func NewQuotaManager(tracker *RateLimitTracker, opts ...QuotaOption) *QuotaManager {
+	m := &QuotaManager{
+		tracker:  tracker,
+		resource: RateLimitResourceCore,
+		maxWait:  DefaultQuotaMaxWait,
+		reserved: make(map[int64]int),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Reserve reserves n requests for an installation. If the installation does
+// not have enough remaining quota and its rate limit resets within the
+// maximum wait, Reserve waits for the reset, using the clock from the
+// context. Otherwise, it returns a QuotaExceededError. Installations without
+// a known rate limit always have enough quota.
+//
+// Callers must call Release on the returned reservation when the work is
+// done.
+func (m *QuotaManager) Reserve(ctx context.Context, installationID int64, n int) (*QuotaReservation, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	clock := GetClock(ctx)
+	for waited := false; ; waited = true {
+		limit, err := m.tryReserve(installationID, n, clock.Now())
+		if err == nil {
+			return &QuotaReservation{m: m, installationID: installationID, n: n}, nil
+		}
+
+		delay := err.Reset.Sub(clock.Now())
+		if waited || delay <= 0 || delay > m.maxWait || n > limit.Limit-m.minRemaining {
+			return nil, *err
+		}
+
+		zerolog.Ctx(ctx).Info().Msgf("Waiting %s for the rate limit to reset to reserve %d requests", delay.Round(time.Second), n)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (m *QuotaManager) tryReserve(installationID int64, n int, now time.Time) (RateLimit, *QuotaExceededError) {
+	limit, ok := m.tracker.Get(installationID)[m.resource]
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !ok || limit.Limit == 0 {
+		m.reserved[installationID] += n
+		return limit, nil
+	}
+
+	remaining := limit.Remaining
+	if !now.Before(limit.Reset) {
+		// the limit reset since the last response, so all requests are available
+		remaining = limit.Limit
+	}
+
+	available := remaining - m.minRemaining - m.reserved[installationID]
+	if n > available {
+		return limit, &QuotaExceededError{
+			InstallationID: installationID,
+			Resource:       m.resource,
+			Requested:      n,
+			Available:      max(available, 0),
+			Reset:          limit.Reset,
+		}
+	}
+
+	m.reserved[installationID] += n
+	return limit, nil
+}
+
+// Reserved returns the number of requests held by outstanding reservations
+// for an installation.
+func (m *QuotaManager) Reserved(installationID int64) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reserved[installationID]
+}
+
+func (m *QuotaManager) release(installationID int64, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.reserved[installationID] -= n; m.reserved[installationID] <= 0 {
+		delete(m.reserved, installationID)
+	}
+}
+
+// QuotaReservation is quota held for an installation by a QuotaManager.
+type QuotaReservation struct {
+	m              *QuotaManager
+	installationID int64
+	n              int
+	once           sync.Once
+}
+
+// Release returns the reserved quota to the manager. It is safe to call more
+// than once and on a nil reservation.
+func (r *QuotaReservation) Release() {
+	if r == nil {
+		return
+	}
+	r.once.Do(func() {
+		r.m.release(r.installationID, r.n)
+	})
+}
+
+type quotaManagerKey struct{}
+
+// WithQuotaManager returns a copy of ctx that contains the quota manager.
+func WithQuotaManager(ctx context.Context, m *QuotaManager) context.Context {
+	return context.WithValue(ctx, quotaManagerKey{}, m)
+}
+
+// GetQuotaManager returns the quota manager stored in the context, or nil if
+// the context does not contain one.
+func GetQuotaManager(ctx context.Context) *QuotaManager {
+	m, _ := ctx.Value(quotaManagerKey{}).(*QuotaManager)
+	return m
+}
+
+// ReserveQuota reserves n requests for the installation of the event being
+// handled using the quota manager in the context. Handlers call it before
+// work that makes many requests and release the reservation when the work
+// is done:
+//
+//	r, err := githubapp.ReserveQuota(ctx, 200)
+//	if err != nil {
+//		return err
+//	}
+//	defer r.Release()
+//
+// If the context does not contain a manager or an installation, ReserveQuota
+// returns a nil reservation and no error, so handlers work the same without
+// a manager.
+func ReserveQuota(ctx context.Context, n int) (*QuotaReservation, error) {
+	m := GetQuotaManager(ctx)
+	if m == nil {
+		return nil, nil
+	}
+
+	installationID := contextInstallationID(ctx)
+	if installationID == 0 {
+		return nil, nil
+	}
+	return m.Reserve(ctx, installationID, n)
+}
+
+// WithQuotaReservations adds the quota manager to the context of all of the
+// dispatcher's handlers, so they can call ReserveQuota.
+func WithQuotaReservations(m *QuotaManager) DispatcherOption {
+	return func(d *eventDispatcher) {
+		wrapQuotaHandlers(m, d.handlerMap)
+	}
+}
+
+// WithConsumerQuotaReservations adds the quota manager to the context of all
+// of the consumer's handlers, so they can call ReserveQuota.
+func WithConsumerQuotaReservations(m *QuotaManager) EventConsumerOption {
+	return func(c *EventConsumer) {
+		wrapQuotaHandlers(m, c.handlerMap)
+	}
+}
+
+func wrapQuotaHandlers(m *QuotaManager, handlerMap map[string]EventHandler) {
+	for eventType, h := range handlerMap {
+		handlerMap[eventType] = &quotaReservationHandler{m: m, next: h}
+	}
+}
+
+type quotaReservationHandler struct {
+	m    *QuotaManager
+	next EventHandler
+}
+
+func (h *quotaReservationHandler) Handles() []string {
+	return h.next.Handles()
+}
+
+func (h *quotaReservationHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	return h.next.Handle(WithQuotaManager(ctx, h.m), eventType, deliveryID, payload)
+}
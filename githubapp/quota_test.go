@@ -0,0 +1,146 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestQuotaManager(t *testing.T) {
+	clock := newTestClock()
+	reset := clock.Now().Add(30 * time.Second)
+
+	tracker := NewRateLimitTracker()
+	tracker.update(42, http.Header{
+		"X-Ratelimit-Limit":     {"1000"},
+		"X-Ratelimit-Remaining": {"300"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+	}, clock.Now())
+
+	ctx := WithClock(context.Background(), clock)
+	m := NewQuotaManager(tracker, WithQuotaMinRemaining(100), WithQuotaMaxWait(time.Minute))
+
+	first, err := m.Reserve(ctx, 42, 150)
+	if err != nil {
+		t.Fatalf("unexpected error reserving quota: %v", err)
+	}
+	if m.Reserved(42) != 150 {
+		t.Errorf("incorrect reserved quota: %d", m.Reserved(42))
+	}
+
+	// the limit resets within the maximum wait, so the manager waits
+	second, err := m.Reserve(ctx, 42, 100)
+	if err != nil {
+		t.Fatalf("unexpected error reserving quota: %v", err)
+	}
+	if len(clock.slept) != 1 || clock.slept[0] != 30*time.Second {
+		t.Errorf("expected wait for reset, but slept %v", clock.slept)
+	}
+
+	first.Release()
+	first.Release()
+	second.Release()
+	if m.Reserved(42) != 0 {
+		t.Errorf("expected no reserved quota after release, but got %d", m.Reserved(42))
+	}
+
+	// more than the limit allows can never be reserved
+	_, err = m.Reserve(ctx, 42, 950)
+	var quotaErr QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("expected QuotaExceededError, but got %v", err)
+	}
+	if quotaErr.InstallationID != 42 || quotaErr.Requested != 950 || quotaErr.Resource != RateLimitResourceCore {
+		t.Errorf("incorrect error: %+v", quotaErr)
+	}
+
+	// installations without a known limit always have quota
+	if r, err := m.Reserve(ctx, 7, 10000); err != nil || r == nil {
+		t.Errorf("expected reservation for unknown installation, but got %v", err)
+	}
+}
+
+func TestQuotaManagerRejectsLongWait(t *testing.T) {
+	clock := newTestClock()
+	reset := clock.Now().Add(time.Hour)
+
+	tracker := NewRateLimitTracker()
+	tracker.update(42, http.Header{
+		"X-Ratelimit-Limit":     {"1000"},
+		"X-Ratelimit-Remaining": {"50"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(reset.Unix(), 10)},
+	}, clock.Now())
+	m := NewQuotaManager(tracker)
+
+	_, err := m.Reserve(WithClock(context.Background(), clock), 42, 100)
+	var quotaErr QuotaExceededError
+	if !errors.As(err, &quotaErr) || quotaErr.Available != 50 {
+		t.Fatalf("expected QuotaExceededError with 50 available, but got %v", err)
+	}
+	if len(clock.slept) != 0 {
+		t.Errorf("expected no wait, but slept %v", clock.slept)
+	}
+}
+
+func TestReserveQuota(t *testing.T) {
+	tracker := NewRateLimitTracker()
+	tracker.update(42, http.Header{
+		"X-Ratelimit-Limit":     {"1000"},
+		"X-Ratelimit-Remaining": {"50"},
+		"X-Ratelimit-Reset":     {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)},
+	}, time.Now())
+	m := NewQuotaManager(tracker)
+
+	var reserveErr error
+	h := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			r, err := ReserveQuota(ctx, 100)
+			if err != nil {
+				reserveErr = err
+				return nil
+			}
+			r.Release()
+			return nil
+		},
+	}
+
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithQuotaReservations(m))
+	req, err := NewDeliveryRequest(context.Background(), DefaultWebhookRoute, testHookSecret, Delivery{
+		EventType:  "pull_request",
+		DeliveryID: "quota",
+		Payload:    []byte(`{"action": "opened", "installation": {"id": 42}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating request: %v", err)
+	}
+	d.ServeHTTP(httptest.NewRecorder(), req)
+
+	var quotaErr QuotaExceededError
+	if !errors.As(reserveErr, &quotaErr) {
+		t.Errorf("expected QuotaExceededError in handler, but got %v", reserveErr)
+	}
+
+	if r, err := ReserveQuota(context.Background(), 100); r != nil || err != nil {
+		t.Errorf("expected no reservation without a manager, but got %v, %v", r, err)
+	}
+}
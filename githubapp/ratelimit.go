@@ -0,0 +1,253 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// RateLimitResourceCore is the rate limit resource for most REST API
+	// requests. GitHub uses it when a response does not identify a resource.
+	RateLimitResourceCore = "core"
+
+	// RateLimitResourceSearch is the rate limit resource for search requests.
+	// It has a much smaller quota than the core resource.
+	RateLimitResourceSearch = "search"
+
+	// RateLimitResourceGraphQL is the rate limit resource for GraphQL requests.
+	RateLimitResourceGraphQL = "graphql"
+
+	// RateLimitResourceIntegrationManifest is the rate limit resource for
+	// requests that convert app manifests.
+	RateLimitResourceIntegrationManifest = "integration_manifest"
+)
+
+// RateLimit is the last known rate limit state for a resource.
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Used      int       `json:"used"`
+	Reset     time.Time `json:"reset"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RateLimitTracker records the rate limit state reported by GitHub in
+// response headers, grouped by installation and resource (like "core",
+// "search", or "graphql"). Add the tracker's middleware to a ClientCreator to
+// collect values. Requests not associated with an installation are recorded
+// with installation ID 0.
+//
+// The tracker is also an http.Handler that reports the state of all
+// installations as JSON, which is useful as a debugging endpoint. Callers
+// should protect the endpoint appropriately before exposing it.
+type RateLimitTracker struct {
+	mu     sync.RWMutex
+	limits map[int64]map[string]RateLimit
+}
+
+// NewRateLimitTracker creates an empty RateLimitTracker.
+func NewRateLimitTracker() *RateLimitTracker {
+	return &RateLimitTracker{
+		limits: make(map[int64]map[string]RateLimit),
+	}
+}
+
+// Middleware returns client middleware that records rate limit headers from
+// all responses.
+func (t *RateLimitTracker) Middleware() ClientMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			res, err := next.RoundTrip(r)
+			if res != nil {
+				installationID, _ := r.Context().Value(installationKey).(int64)
+				t.update(installationID, res.Header, GetClock(r.Context()).Now())
+			}
+			return res, err
+		})
+	}
+}
+
+func (t *RateLimitTracker) update(installationID int64, h http.Header, now time.Time) {
+	resource, limit, ok := parseRateLimit(h, now)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resources, ok := t.limits[installationID]
+	if !ok {
+		resources = make(map[string]RateLimit)
+		t.limits[installationID] = resources
+	}
+	resources[resource] = limit
+}
+
+// parseRateLimit returns the resource and rate limit from response headers,
+// updated at now. It returns false if the headers do not contain a rate
+// limit.
+func parseRateLimit(h http.Header, now time.Time) (string, RateLimit, bool) {
+	limit, err := strconv.Atoi(h.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return "", RateLimit{}, false
+	}
+	remaining, _ := strconv.Atoi(h.Get("X-RateLimit-Remaining"))
+	used, _ := strconv.Atoi(h.Get("X-RateLimit-Used"))
+	reset, _ := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64)
+
+	resource := h.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = RateLimitResourceCore
+	}
+
+	return resource, RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Used:      used,
+		Reset:     time.Unix(reset, 0).UTC(),
+		UpdatedAt: now.UTC(),
+	}, true
+}
+
+// Get returns the last known rate limits for an installation, keyed by
+// resource. It returns nil if there is no state for the installation.
+func (t *RateLimitTracker) Get(installationID int64) map[string]RateLimit {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	resources, ok := t.limits[installationID]
+	if !ok {
+		return nil
+	}
+
+	limits := make(map[string]RateLimit, len(resources))
+	for k, v := range resources {
+		limits[k] = v
+	}
+	return limits
+}
+
+// Installations returns the IDs of all installations with known rate limits.
+func (t *RateLimitTracker) Installations() []int64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ids := make([]int64, 0, len(t.limits))
+	for id := range t.limits {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+type rateLimitStatus struct {
+	InstallationID int64                `json:"installation_id"`
+	Resources      map[string]RateLimit `json:"resources"`
+}
+
+// ServeHTTP responds with the rate limits of all installations. If the
+// "installation" query parameter is set, it only includes that installation.
+func (t *RateLimitTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ids := t.Installations()
+	if v := r.URL.Query().Get("installation"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			http.Error(w, "Invalid installation ID", http.StatusBadRequest)
+			return
+		}
+		ids = []int64{id}
+	}
+
+	statuses := make([]rateLimitStatus, 0, len(ids))
+	for _, id := range ids {
+		if limits := t.Get(id); limits != nil {
+			statuses = append(statuses, rateLimitStatus{InstallationID: id, Resources: limits})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(statuses)
+}
+
+type rateLimitsKey struct{}
+
+type contextRateLimits struct {
+	mu     sync.Mutex
+	limits map[string]RateLimit
+}
+
+// InitializeRateLimits prepares the context to record the rate limits
+// reported in responses to requests made with it, so handlers can read them
+// with RateLimits. The event dispatcher, DefaultContextDeriver, and other
+// types that call handlers do this automatically.
+func InitializeRateLimits(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rateLimitsKey{}, &contextRateLimits{
+		limits: make(map[string]RateLimit),
+	})
+}
+
+// RateLimits returns the rate limits reported in the responses to the most
+// recent requests made with the context, keyed by resource. Handlers can use
+// it to defer expensive work when an installation's remaining quota is low.
+//
+// It returns nil if the context was not initialized by InitializeRateLimits
+// or if no requests were made with clients from a ClientCreator. If a handler
+// uses clients for several installations, each resource contains the value
+// from the most recent request for that resource.
+func RateLimits(ctx context.Context) map[string]RateLimit {
+	l, ok := ctx.Value(rateLimitsKey{}).(*contextRateLimits)
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.limits) == 0 {
+		return nil
+	}
+
+	limits := make(map[string]RateLimit, len(l.limits))
+	for k, v := range l.limits {
+		limits[k] = v
+	}
+	return limits
+}
+
+func recordRateLimits() ClientMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			res, err := next.RoundTrip(r)
+			if res != nil {
+				if l, ok := r.Context().Value(rateLimitsKey{}).(*contextRateLimits); ok {
+					if resource, limit, ok := parseRateLimit(res.Header, GetClock(r.Context()).Now()); ok {
+						l.mu.Lock()
+						l.limits[resource] = limit
+						l.mu.Unlock()
+					}
+				}
+			}
+			return res, err
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRateLimitTracker(t *testing.T) {
+	tracker := NewRateLimitTracker()
+
+	var rt http.RoundTripper = roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		res := httptest.NewRecorder()
+		res.Header().Set("X-RateLimit-Limit", "5000")
+		res.Header().Set("X-RateLimit-Remaining", "4990")
+		res.Header().Set("X-RateLimit-Used", "10")
+		res.Header().Set("X-RateLimit-Reset", "1700000000")
+		if strings.HasPrefix(r.URL.Path, "/search") {
+			res.Header().Set("X-RateLimit-Resource", "search")
+		}
+		return res.Result(), nil
+	})
+	rt = setInstallationID(42)(tracker.Middleware()(rt))
+
+	for _, path := range []string{"/repos/test/test", "/search/issues"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil).WithContext(context.Background())
+		if _, err := rt.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error making request: %v", err)
+		}
+	}
+
+	limits := tracker.Get(42)
+	if len(limits) != 2 {
+		t.Fatalf("incorrect number of resources: expected 2, actual %d", len(limits))
+	}
+	if core := limits[RateLimitResourceCore]; core.Remaining != 4990 || core.Limit != 5000 || core.Used != 10 {
+		t.Errorf("incorrect core rate limit: %+v", core)
+	}
+	if search := limits["search"]; search.Reset.Unix() != 1700000000 {
+		t.Errorf("incorrect search reset: %v", search.Reset)
+	}
+
+	res := httptest.NewRecorder()
+	tracker.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/?installation=42", nil))
+	if res.Code != http.StatusOK || !strings.Contains(res.Body.String(), `"installation_id":42`) {
+		t.Errorf("incorrect response: %d: %s", res.Code, res.Body.String())
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "12")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.Header().Set("X-RateLimit-Resource", "core")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	cc, err := NewDefaultCachingClientCreator(Config{V3APIURL: srv.URL + "/"})
+	if err != nil {
+		t.Fatalf("unexpected error creating client creator: %v", err)
+	}
+	client, err := cc.NewTokenClient("token")
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	ctx := InitializeRateLimits(context.Background())
+	if limits := RateLimits(ctx); limits != nil {
+		t.Errorf("expected no rate limits before requests, actual %+v", limits)
+	}
+
+	if _, _, err := client.Repositories.GetByID(ctx, 1); err != nil {
+		t.Fatalf("unexpected error making request: %v", err)
+	}
+
+	core, ok := RateLimits(ctx)[RateLimitResourceCore]
+	if !ok {
+		t.Fatal("expected core rate limit, but it was missing")
+	}
+	if core.Limit != 5000 || core.Remaining != 12 || core.Reset.Unix() != 1700000000 {
+		t.Errorf("incorrect core rate limit: %+v", core)
+	}
+
+	if limits := RateLimits(context.Background()); limits != nil {
+		t.Errorf("expected no rate limits for uninitialized context, actual %+v", limits)
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// Reaction contents supported by GitHub.
+const (
+	ReactionPlusOne  = "+1"
+	ReactionMinusOne = "-1"
+	ReactionLaugh    = "laugh"
+	ReactionConfused = "confused"
+	ReactionHeart    = "heart"
+	ReactionHooray   = "hooray"
+	ReactionRocket   = "rocket"
+	ReactionEyes     = "eyes"
+)
+
+type reactionKind int
+
+const (
+	reactionIssue reactionKind = iota
+	reactionIssueComment
+	reactionPullRequestComment
+	reactionCommitComment
+)
+
+// ReactionTarget identifies an object that supports reactions. Create
+// targets with IssueReactionTarget, IssueCommentReactionTarget,
+// PullRequestCommentReactionTarget, or CommitCommentReactionTarget.
+type ReactionTarget struct {
+	Owner string
+	Repo  string
+
+	kind   reactionKind
+	id     int64
+	number int
+}
+
+// IssueReactionTarget returns a target for an issue or pull request.
+func IssueReactionTarget(owner, repo string, number int) ReactionTarget {
+	return ReactionTarget{Owner: owner, Repo: repo, kind: reactionIssue, number: number}
+}
+
+// IssueCommentReactionTarget returns a target for a comment on an issue or
+// on the conversation of a pull request.
+func IssueCommentReactionTarget(owner, repo string, commentID int64) ReactionTarget {
+	return ReactionTarget{Owner: owner, Repo: repo, kind: reactionIssueComment, id: commentID}
+}
+
+// PullRequestCommentReactionTarget returns a target for a review comment on
+// the diff of a pull request.
+func PullRequestCommentReactionTarget(owner, repo string, commentID int64) ReactionTarget {
+	return ReactionTarget{Owner: owner, Repo: repo, kind: reactionPullRequestComment, id: commentID}
+}
+
+// CommitCommentReactionTarget returns a target for a comment on a commit.
+func CommitCommentReactionTarget(owner, repo string, commentID int64) ReactionTarget {
+	return ReactionTarget{Owner: owner, Repo: repo, kind: reactionCommitComment, id: commentID}
+}
+
+func (t ReactionTarget) String() string {
+	switch t.kind {
+	case reactionIssue:
+		return fmt.Sprintf("%s/%s#%d", t.Owner, t.Repo, t.number)
+	case reactionPullRequestComment:
+		return fmt.Sprintf("%s/%s review comment %d", t.Owner, t.Repo, t.id)
+	case reactionCommitComment:
+		return fmt.Sprintf("%s/%s commit comment %d", t.Owner, t.Repo, t.id)
+	default:
+		return fmt.Sprintf("%s/%s comment %d", t.Owner, t.Repo, t.id)
+	}
+}
+
+// AddReaction adds a reaction with the given content to the target. If the
+// authenticated user already added the same reaction, GitHub returns the
+// existing reaction, so adding a reaction is idempotent.
+func AddReaction(ctx context.Context, client *github.Client, target ReactionTarget, content string) (*github.Reaction, error) {
+	var r *github.Reaction
+	var err error
+
+	switch target.kind {
+	case reactionIssue:
+		r, _, err = client.Reactions.CreateIssueReaction(ctx, target.Owner, target.Repo, target.number, content)
+	case reactionIssueComment:
+		r, _, err = client.Reactions.CreateIssueCommentReaction(ctx, target.Owner, target.Repo, target.id, content)
+	case reactionPullRequestComment:
+		r, _, err = client.Reactions.CreatePullRequestCommentReaction(ctx, target.Owner, target.Repo, target.id, content)
+	case reactionCommitComment:
+		r, _, err = client.Reactions.CreateCommentReaction(ctx, target.Owner, target.Repo, target.id, content)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add %q reaction to %s", content, target)
+	}
+	return r, nil
+}
+
+// RemoveReaction removes the reaction with the given content added by the
+// authenticated user from the target.
+//
+// To find the reaction, RemoveReaction adds it with AddReaction, which
+// returns the existing reaction if there is one, and then deletes it. This
+// takes two requests regardless of the number of reactions on the target and
+// works with installation tokens, which cannot look up the user they belong
+// to. If the reaction does not exist, it is created and then deleted, so it
+// may be briefly visible on the target and the authenticated user needs
+// permission to add reactions.
+func RemoveReaction(ctx context.Context, client *github.Client, target ReactionTarget, content string) error {
+	r, err := AddReaction(ctx, client, target, content)
+	if err != nil {
+		return err
+	}
+	return deleteReaction(ctx, client, target, r.GetID())
+}
+
+func deleteReaction(ctx context.Context, client *github.Client, target ReactionTarget, reactionID int64) error {
+	var err error
+
+	switch target.kind {
+	case reactionIssue:
+		_, err = client.Reactions.DeleteIssueReaction(ctx, target.Owner, target.Repo, target.number, reactionID)
+	case reactionIssueComment:
+		_, err = client.Reactions.DeleteIssueCommentReaction(ctx, target.Owner, target.Repo, target.id, reactionID)
+	case reactionPullRequestComment:
+		_, err = client.Reactions.DeletePullRequestCommentReaction(ctx, target.Owner, target.Repo, target.id, reactionID)
+	case reactionCommitComment:
+		_, err = client.Reactions.DeleteCommentReaction(ctx, target.Owner, target.Repo, target.id, reactionID)
+	}
+	if err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "failed to remove reaction %d from %s", reactionID, target)
+	}
+	return nil
+}
+
+// Acknowledgment is a reaction that shows a user that the application is
+// working on their request. Create one with Acknowledge and call Complete or
+// Fail when the work is done to replace the reaction with the result.
+type Acknowledgment struct {
+	client   *github.Client
+	target   ReactionTarget
+	reaction *github.Reaction
+}
+
+// Acknowledge adds a reaction with the given content, usually ReactionEyes,
+// to the target.
+func Acknowledge(ctx context.Context, client *github.Client, target ReactionTarget, content string) (*Acknowledgment, error) {
+	r, err := AddReaction(ctx, client, target, content)
+	if err != nil {
+		return nil, err
+	}
+	return &Acknowledgment{client: client, target: target, reaction: r}, nil
+}
+
+// Complete replaces the acknowledgment with a ReactionRocket reaction.
+func (a *Acknowledgment) Complete(ctx context.Context) error {
+	return a.Replace(ctx, ReactionRocket)
+}
+
+// Fail replaces the acknowledgment with a ReactionConfused reaction.
+func (a *Acknowledgment) Fail(ctx context.Context) error {
+	return a.Replace(ctx, ReactionConfused)
+}
+
+// Replace removes the acknowledgment and adds a reaction with the given
+// content. If content is empty, it only removes the acknowledgment.
+func (a *Acknowledgment) Replace(ctx context.Context, content string) error {
+	if err := deleteReaction(ctx, a.client, a.target, a.reaction.GetID()); err != nil {
+		return err
+	}
+	if content == "" {
+		return nil
+	}
+
+	r, err := AddReaction(ctx, a.client, a.target, content)
+	if err != nil {
+		return err
+	}
+	a.reaction = r
+	return nil
+}
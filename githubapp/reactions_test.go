@@ -0,0 +1,117 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// reactionServer simulates the reactions API for a single object, returning
+// existing reactions when the same content is added twice.
+type reactionServer struct {
+	nextID    int64
+	reactions map[string]int64
+	calls     []string
+}
+
+func (s *reactionServer) register(mux *http.ServeMux, path string) {
+	mux.HandleFunc("POST "+path, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Content string `json:"content"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		id, ok := s.reactions[body.Content]
+		if !ok {
+			s.nextID++
+			id = s.nextID
+			s.reactions[body.Content] = id
+			s.calls = append(s.calls, "add "+body.Content)
+		}
+		fmt.Fprintf(w, `{"id": %d, "content": %q}`, id, body.Content)
+	})
+	mux.HandleFunc("DELETE "+path+"/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := strconv.ParseInt(r.PathValue("id"), 10, 64)
+		for content, existing := range s.reactions {
+			if existing == id {
+				delete(s.reactions, content)
+				s.calls = append(s.calls, "remove "+content)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+}
+
+func TestReactions(t *testing.T) {
+	tests := map[string]struct {
+		Path   string
+		Target ReactionTarget
+	}{
+		"issue": {
+			Path:   "/repos/octo/repo/issues/3/reactions",
+			Target: IssueReactionTarget("octo", "repo", 3),
+		},
+		"issueComment": {
+			Path:   "/repos/octo/repo/issues/comments/10/reactions",
+			Target: IssueCommentReactionTarget("octo", "repo", 10),
+		},
+		"pullRequestComment": {
+			Path:   "/repos/octo/repo/pulls/comments/10/reactions",
+			Target: PullRequestCommentReactionTarget("octo", "repo", 10),
+		},
+		"commitComment": {
+			Path:   "/repos/octo/repo/comments/10/reactions",
+			Target: CommitCommentReactionTarget("octo", "repo", 10),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			srv := &reactionServer{reactions: make(map[string]int64)}
+			mux := http.NewServeMux()
+			srv.register(mux, test.Path)
+			client := newStaticClientCreator(t, mux).client
+
+			ack, err := Acknowledge(ctx, client, test.Target, ReactionEyes)
+			if err != nil {
+				t.Fatalf("failed to acknowledge: %v", err)
+			}
+			if _, err := AddReaction(ctx, client, test.Target, ReactionEyes); err != nil {
+				t.Fatalf("failed to add duplicate reaction: %v", err)
+			}
+			if err := ack.Complete(ctx); err != nil {
+				t.Fatalf("failed to complete: %v", err)
+			}
+			if err := RemoveReaction(ctx, client, test.Target, ReactionRocket); err != nil {
+				t.Fatalf("failed to remove reaction: %v", err)
+			}
+
+			expected := []string{"add eyes", "remove eyes", "add rocket", "remove rocket"}
+			if !reflect.DeepEqual(expected, srv.calls) {
+				t.Errorf("incorrect calls\nexpected: %v\n  actual: %v", expected, srv.calls)
+			}
+		})
+	}
+}
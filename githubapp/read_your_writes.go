@@ -0,0 +1,115 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type writesKey struct{}
+
+// writeTracker records the resources modified by requests that share a
+// context.
+type writeTracker struct {
+	mu     sync.Mutex
+	scopes map[string]bool
+}
+
+func (w *writeTracker) add(scope string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.scopes[scope] = true
+}
+
+func (w *writeTracker) contains(scope string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.scopes[scope]
+}
+
+// WithReadYourWrites returns a context in which clients with caching enabled
+// do not return cached responses for resources modified earlier with the same
+// context. Without it, a handler that creates a comment and then lists the
+// comments of the issue may get a cached list that does not contain the new
+// comment.
+//
+// After a successful POST, PUT, PATCH, or DELETE request, later GET requests
+// for the same repository, organization, or user revalidate cached responses
+// with GitHub instead of using them. Revalidated responses that did not
+// change do not count against the rate limit. Use the returned context for
+// all requests made by one handler; it has no effect on clients without
+// caching.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(writesKey{}).(*writeTracker); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, writesKey{}, &writeTracker{scopes: make(map[string]bool)})
+}
+
+// readYourWrites is a client middleware that must run before the cache. It
+// records modified resources in the write tracker of the request context and
+// asks the cache to revalidate responses for those resources.
+func readYourWrites() ClientMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			tracker, ok := r.Context().Value(writesKey{}).(*writeTracker)
+			if !ok {
+				return next.RoundTrip(r)
+			}
+
+			scope := resourceScope(r.URL.Path)
+			switch r.Method {
+			case http.MethodGet, http.MethodHead:
+				if scope != "" && tracker.contains(scope) {
+					// max-age=0 makes cached responses stale, so the cache sends a
+					// conditional request instead of returning them
+					r = r.Clone(r.Context())
+					r.Header.Set("Cache-Control", "max-age=0")
+				}
+				return next.RoundTrip(r)
+			}
+
+			res, err := next.RoundTrip(r)
+			if err == nil && scope != "" && res.StatusCode < http.StatusBadRequest {
+				tracker.add(scope)
+			}
+			return res, err
+		})
+	}
+}
+
+// resourceScope returns the part of an API path that identifies the
+// repository, organization, or user that owns a resource. Writes to any
+// resource in a scope invalidate reads of all resources in the same scope,
+// because GitHub often changes related resources, like the comments and
+// timeline of an issue, at the same time.
+func resourceScope(path string) string {
+	path = strings.ToLower(path)
+	path = strings.TrimPrefix(path, "/api/v3")
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) >= 3 && parts[0] == "repos":
+		return strings.Join(parts[:3], "/")
+	case len(parts) >= 2 && (parts[0] == "orgs" || parts[0] == "users"):
+		return strings.Join(parts[:2], "/")
+	case len(parts) >= 1 && parts[0] == "user":
+		return parts[0]
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/gregjones/httpcache"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	tests := map[string]struct {
+		ReadYourWrites bool
+		Comments       int
+	}{
+		"enabled": {
+			ReadYourWrites: true,
+			Comments:       2,
+		},
+		"disabled": {
+			Comments: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var comments, lists int32
+			atomic.StoreInt32(&comments, 1)
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /repos/octo/repo/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&lists, 1)
+
+				n := atomic.LoadInt32(&comments)
+				etag := fmt.Sprintf(`"%d"`, n)
+				w.Header().Set("Cache-Control", "private, max-age=60")
+				w.Header().Set("ETag", etag)
+				if r.Header.Get("If-None-Match") == etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				body := "["
+				for i := int32(0); i < n; i++ {
+					if i > 0 {
+						body += ","
+					}
+					body += fmt.Sprintf(`{"id": %d}`, i+1)
+				}
+				_, _ = w.Write([]byte(body + "]"))
+			})
+			mux.HandleFunc("POST /repos/octo/repo/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&comments, 1)
+				w.WriteHeader(http.StatusCreated)
+				_, _ = fmt.Fprintf(w, `{"id": %d}`, n)
+			})
+
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			cc := NewClientCreator(srv.URL, srv.URL, 1, nil, WithClientCaching(false, func() httpcache.Cache {
+				return httpcache.NewMemoryCache()
+			}))
+			client, err := cc.NewTokenClient("token")
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+
+			ctx := context.Background()
+			if test.ReadYourWrites {
+				ctx = WithReadYourWrites(ctx)
+			}
+
+			list := func() []*github.IssueComment {
+				c, _, err := client.Issues.ListComments(ctx, "octo", "repo", 1, nil)
+				if err != nil {
+					t.Fatalf("unexpected error listing comments: %v", err)
+				}
+				return c
+			}
+
+			list()
+			list()
+			if n := atomic.LoadInt32(&lists); n != 1 {
+				t.Fatalf("expected second list to use the cache, but server received %d requests", n)
+			}
+
+			if _, _, err := client.Issues.CreateComment(ctx, "octo", "repo", 1, &github.IssueComment{Body: github.String("hi")}); err != nil {
+				t.Fatalf("unexpected error creating comment: %v", err)
+			}
+
+			if c := list(); len(c) != test.Comments {
+				t.Errorf("expected %d comments after creating a comment, but got %d", test.Comments, len(c))
+			}
+		})
+	}
+}
+
+func TestResourceScope(t *testing.T) {
+	tests := map[string]string{
+		"/repos/Octo/Repo/issues/1/comments": "repos/octo/repo",
+		"/api/v3/repos/octo/repo/pulls":      "repos/octo/repo",
+		"/orgs/octo/teams":                   "orgs/octo",
+		"/user/installations":                "user",
+		"/app/installations/1/access_tokens": "",
+	}
+
+	for path, expected := range tests {
+		if scope := resourceScope(path); scope != expected {
+			t.Errorf("incorrect scope for %q: expected %q, actual %q", path, expected, scope)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultRelayInterval = 5 * time.Second
+
+	relayPageSize = 100
+)
+
+// Delivery is a single webhook delivery.
+type Delivery struct {
+	EventType  string
+	DeliveryID string
+	Payload    []byte
+
+	// Header contains the headers that describe the delivery, as returned by
+	// DeliveryHeader. It is nil if the source of the delivery does not
+	// provide headers, like events synthesized by a Backfill.
+	Header http.Header
+}
+
+// SignPayload returns the value of the X-Hub-Signature-256 header for a
+// payload signed with secret.
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewDeliveryRequest creates a request that sends a delivery to the webhook
+// endpoint at url. The request has the same headers GitHub sets on webhook
+// requests, including the hook headers in the delivery's Header, and the
+// payload is signed with secret.
+func NewDeliveryRequest(ctx context.Context, url, secret string, d Delivery) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create delivery request")
+	}
+
+	for name, values := range DeliveryHeader(d.Header) {
+		if name != github.SHA1SignatureHeader && name != github.SHA256SignatureHeader {
+			req.Header[name] = values
+		}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", d.EventType)
+	req.Header.Set("X-GitHub-Delivery", d.DeliveryID)
+	if secret != "" {
+		req.Header.Set("X-Hub-Signature-256", SignPayload(secret, d.Payload))
+	}
+	return req, nil
+}
+
+// Relay polls the webhook deliveries of an application and sends them to
+// another endpoint. It is intended for local development, where a running
+// application cannot receive webhooks directly from GitHub. Deliveries are
+// re-signed with a local secret, so the target can use a normal event
+// dispatcher.
+type Relay struct {
+	client   *github.Client
+	target   string
+	secret   string
+	interval time.Duration
+	http     *http.Client
+
+	started bool
+	lastID  int64
+}
+
+// RelayOption configures properties of a relay.
+type RelayOption func(*Relay)
+
+// WithRelayInterval sets how often a relay polls for new deliveries. The
+// default interval is DefaultRelayInterval.
+func WithRelayInterval(interval time.Duration) RelayOption {
+	return func(r *Relay) {
+		if interval > 0 {
+			r.interval = interval
+		}
+	}
+}
+
+// WithRelayHTTPClient sets the client used to send deliveries to the target.
+// By default, the relay uses http.DefaultClient.
+func WithRelayHTTPClient(client *http.Client) RelayOption {
+	return func(r *Relay) {
+		if client != nil {
+			r.http = client
+		}
+	}
+}
+
+// NewRelay creates a Relay that uses appClient, which must authenticate as
+// the application, to list deliveries and sends them to the target URL signed
+// with secret.
+func NewRelay(appClient *github.Client, target, secret string, opts ...RelayOption) *Relay {
+	r := &Relay{
+		client:   appClient,
+		target:   target,
+		secret:   secret,
+		interval: DefaultRelayInterval,
+		http:     http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run polls for deliveries until the context is canceled. Only deliveries
+// that GitHub makes after the first poll are sent to the target. Errors from
+// individual polls are logged and do not stop the relay.
+func (r *Relay) Run(ctx context.Context) error {
+	logger := zerolog.Ctx(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := r.Poll(ctx)
+		switch {
+		case err != nil:
+			logger.Error().Err(err).Msg("Failed to relay webhook deliveries")
+		case n > 0:
+			logger.Info().Msgf("Relayed %d webhook deliveries", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll checks for new deliveries and sends them to the target in the order
+// GitHub delivered them. It returns the number of deliveries sent. The first
+// successful call to Poll records the most recent delivery, if any, and does
+// not send anything. If sending a delivery fails, Poll stops and the next call
+// sends the failed delivery again.
+func (r *Relay) Poll(ctx context.Context) (int, error) {
+	deliveries, _, err := r.client.Apps.ListHookDeliveries(ctx, &github.ListCursorOptions{PerPage: relayPageSize})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to list hook deliveries")
+	}
+
+	var pending []*github.HookDelivery
+	maxID := r.lastID
+	for _, d := range deliveries {
+		if d.GetID() > maxID {
+			maxID = d.GetID()
+		}
+		if r.started && d.GetID() > r.lastID {
+			pending = append(pending, d)
+		}
+	}
+
+	if !r.started {
+		r.started = true
+		r.lastID = maxID
+		return 0, nil
+	}
+	if len(pending) == relayPageSize {
+		zerolog.Ctx(ctx).Warn().Msg("Relay may have skipped deliveries; consider a shorter poll interval")
+	}
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i].GetID() < pending[j].GetID() })
+
+	sent := 0
+	for _, d := range pending {
+		if err := r.send(ctx, d.GetID()); err != nil {
+			return sent, err
+		}
+		r.lastID = d.GetID()
+		sent++
+	}
+	r.lastID = maxID
+	return sent, nil
+}
+
+func (r *Relay) send(ctx context.Context, id int64) error {
+	d, err := LoadGitHubDelivery(ctx, r.client, id)
+	if err != nil {
+		return err
+	}
+
+	req, err := NewDeliveryRequest(ctx, r.target, r.secret, d)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.http.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send delivery %s", d.DeliveryID)
+	}
+	defer closeBody(res.Body)
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.Errorf("target responded with status %d for delivery %s", res.StatusCode, d.DeliveryID)
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str(LogKeyEventType, d.EventType).
+		Str(LogKeyDeliveryID, d.DeliveryID).
+		Int("status", res.StatusCode).
+		Msg("Relayed webhook delivery")
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var listed []int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/hook/deliveries", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// GitHub lists the most recent deliveries first
+		var deliveries []map[string]any
+		for i := len(listed) - 1; i >= 0; i-- {
+			deliveries = append(deliveries, map[string]any{"id": listed[i], "guid": fmt.Sprintf("guid-%d", listed[i]), "event": "push"})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(deliveries)
+	})
+	mux.HandleFunc("GET /app/hook/deliveries/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": %s, "guid": "guid-%s", "event": "push", "request": {"headers": {"X-GitHub-Event": "push"}, "payload": {"ref": "refs/heads/main"}}}`, r.PathValue("id"), r.PathValue("id"))
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	deliver := func(ids ...int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		listed = append(listed, ids...)
+	}
+	reset := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		listed = nil
+	}
+
+	newTarget := func(t *testing.T, status *int) (*httptest.Server, *[]string) {
+		var received []string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			if got, want := r.Header.Get("X-Hub-Signature-256"), SignPayload("local-secret", body); got != want {
+				t.Errorf("expected signature %q, but got %q", want, got)
+			}
+			if *status == http.StatusOK {
+				received = append(received, r.Header.Get("X-GitHub-Delivery"))
+			}
+			w.WriteHeader(*status)
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &received
+	}
+
+	t.Run("emptyFirstPoll", func(t *testing.T) {
+		reset()
+		status := http.StatusOK
+		target, received := newTarget(t, &status)
+		r := NewRelay(cc.client, target.URL, "local-secret")
+
+		if n, err := r.Poll(ctx); err != nil || n != 0 {
+			t.Fatalf("expected first poll to send nothing, but got %d, %v", n, err)
+		}
+
+		deliver(1, 2)
+		if n, err := r.Poll(ctx); err != nil || n != 2 {
+			t.Fatalf("expected 2 deliveries, but got %d, %v", n, err)
+		}
+		if fmt.Sprint(*received) != "[guid-1 guid-2]" {
+			t.Errorf("expected deliveries in order, but got %v", *received)
+		}
+	})
+
+	t.Run("skipsExistingDeliveries", func(t *testing.T) {
+		reset()
+		status := http.StatusOK
+		target, received := newTarget(t, &status)
+		r := NewRelay(cc.client, target.URL, "local-secret")
+
+		deliver(1, 2)
+		if n, err := r.Poll(ctx); err != nil || n != 0 {
+			t.Fatalf("expected first poll to send nothing, but got %d, %v", n, err)
+		}
+
+		deliver(3)
+		if n, err := r.Poll(ctx); err != nil || n != 1 {
+			t.Fatalf("expected 1 delivery, but got %d, %v", n, err)
+		}
+		if n, err := r.Poll(ctx); err != nil || n != 0 {
+			t.Fatalf("expected no new deliveries, but got %d, %v", n, err)
+		}
+		if fmt.Sprint(*received) != "[guid-3]" {
+			t.Errorf("expected only new deliveries, but got %v", *received)
+		}
+	})
+
+	t.Run("targetFailure", func(t *testing.T) {
+		reset()
+		status := http.StatusInternalServerError
+		target, received := newTarget(t, &status)
+		r := NewRelay(cc.client, target.URL, "local-secret")
+
+		if _, err := r.Poll(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		deliver(1, 2)
+		if n, err := r.Poll(ctx); err == nil || n != 0 {
+			t.Fatalf("expected error for failed delivery, but got %d, %v", n, err)
+		}
+
+		status = http.StatusOK
+		if n, err := r.Poll(ctx); err != nil || n != 2 {
+			t.Fatalf("expected failed deliveries to be sent again, but got %d, %v", n, err)
+		}
+		if fmt.Sprint(*received) != "[guid-1 guid-2]" {
+			t.Errorf("expected deliveries in order, but got %v", *received)
+		}
+	})
+}
@@ -0,0 +1,290 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// archiveKeyPattern matches keys returned by ArchivedDelivery.Key.
+var archiveKeyPattern = regexp.MustCompile(`^[0-9]{4}/[0-9]{2}/[0-9]{2}/[A-Za-z0-9%-]+\.json$`)
+
+type replayKey struct{}
+
+// WithReplay returns a context that marks deliveries dispatched with it as
+// replays of earlier deliveries. ReplayDelivery sets this on the requests it
+// sends to a dispatcher.
+func WithReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayKey{}, true)
+}
+
+// IsReplay returns true if the context is for a delivery that an operator
+// replayed with ReplayDelivery. Handlers can use it to skip side effects that
+// should only happen once, like notifications, while repeating the rest of
+// their work. DefaultContextDeriver preserves the flag for asynchronous
+// schedulers.
+func IsReplay(ctx context.Context) bool {
+	replay, _ := ctx.Value(replayKey{}).(bool)
+	return replay
+}
+
+// BlobSource reads blobs written to a BlobSink.
+type BlobSource interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// NewDirectoryBlobSource returns a BlobSource that reads blobs written by a
+// sink from NewDirectoryBlobSink with the same directory.
+func NewDirectoryBlobSource(dir string) BlobSource {
+	return &directoryBlobSink{dir: dir}
+}
+
+// LoadArchivedDelivery reads the delivery stored with key by a dispatcher
+// configured with WithDeliveryArchive. The key is the value returned by
+// ArchivedDelivery.Key, like "2026/01/02/<delivery-id>.json".
+//
+// LoadArchivedDelivery returns an error for keys that are not in this format
+// without reading from the source.
+func LoadArchivedDelivery(ctx context.Context, src BlobSource, key string) (Delivery, error) {
+	if !archiveKeyPattern.MatchString(key) {
+		return Delivery{}, errors.Errorf("invalid archived delivery key %q", key)
+	}
+
+	b, err := src.Get(ctx, key)
+	if err != nil {
+		return Delivery{}, errors.Wrapf(err, "failed to read archived delivery %s", key)
+	}
+
+	var a ArchivedDelivery
+	if err := json.Unmarshal(b, &a); err != nil {
+		return Delivery{}, errors.Wrapf(err, "failed to parse archived delivery %s", key)
+	}
+	return a.Delivery(), nil
+}
+
+// LoadGitHubDelivery reads a delivery that GitHub stores for the application.
+// The ID is GitHub's numeric ID for the delivery, shown in the advanced
+// settings of the app, not the GUID in the X-GitHub-Delivery header. The
+// client must authenticate as the application. GitHub keeps deliveries for a
+// limited time.
+func LoadGitHubDelivery(ctx context.Context, appClient *github.Client, id int64) (Delivery, error) {
+	hd, _, err := appClient.Apps.GetHookDelivery(ctx, id)
+	if err != nil {
+		return Delivery{}, errors.Wrapf(err, "failed to get hook delivery %d", id)
+	}
+
+	var payload []byte
+	header := make(http.Header)
+	if req := hd.GetRequest(); req != nil {
+		if req.RawPayload != nil {
+			payload = *req.RawPayload
+		}
+		for name, value := range req.Headers {
+			header.Set(name, value)
+		}
+	}
+
+	return Delivery{
+		EventType:  hd.GetEvent(),
+		DeliveryID: hd.GetGUID(),
+		Payload:    payload,
+		Header:     DeliveryHeader(header),
+	}, nil
+}
+
+// ReplayError is returned by ReplayDelivery when the dispatcher rejects a
+// replayed delivery or fails to handle it.
+type ReplayError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e ReplayError) Error() string {
+	return fmt.Sprintf("dispatcher responded with status %d: %s", e.StatusCode, strings.TrimSpace(e.Body))
+}
+
+// ReplayDelivery sends a delivery through dispatcher, which is usually the
+// event dispatcher that serves webhooks, so operators can process an event
+// again after fixing a handler. The payload is signed with secret, which
+// must be the dispatcher's webhook secret. Handlers see the original event
+// type, delivery ID, and payload, and IsReplay returns true for their
+// contexts.
+//
+// The event dispatcher does not check the hook allowlist, archive the
+// delivery again, or record delivery latency for replays. With a synchronous
+// scheduler, ReplayDelivery returns a ReplayError if the handler fails. With
+// an asynchronous scheduler, it returns after the dispatch is scheduled and
+// handler errors go to the scheduler's error callback.
+func ReplayDelivery(ctx context.Context, dispatcher http.Handler, secret string, d Delivery) error {
+	ctx = WithReplay(ctx)
+
+	req, err := NewDeliveryRequest(ctx, DefaultWebhookRoute, secret, d)
+	if err != nil {
+		return err
+	}
+
+	w := &replayResponseWriter{header: make(http.Header), status: http.StatusOK}
+	dispatcher.ServeHTTP(w, req)
+
+	if w.status >= http.StatusBadRequest {
+		return ReplayError{StatusCode: w.status, Body: w.body.String()}
+	}
+	return nil
+}
+
+type replayResponseWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *replayResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *replayResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+}
+
+func (w *replayResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(b)
+}
+
+// ReplayHandlerOption configures properties of a replay handler.
+type ReplayHandlerOption func(*replayHandler)
+
+// WithReplayArchive allows the replay handler to load deliveries from an
+// archive by key.
+func WithReplayArchive(src BlobSource) ReplayHandlerOption {
+	return func(h *replayHandler) {
+		h.archive = src
+	}
+}
+
+// WithReplayGitHubDeliveries allows the replay handler to load deliveries
+// stored by GitHub. The client must authenticate as the application.
+func WithReplayGitHubDeliveries(appClient *github.Client) ReplayHandlerOption {
+	return func(h *replayHandler) {
+		h.appClient = appClient
+	}
+}
+
+// ReplayRequest is the body of requests to a replay handler. Set exactly one
+// field.
+type ReplayRequest struct {
+	// ArchiveKey is the key of an archived delivery.
+	ArchiveKey string `json:"archive_key,omitempty"`
+
+	// GitHubDeliveryID is GitHub's numeric ID for a delivery.
+	GitHubDeliveryID int64 `json:"github_delivery_id,omitempty"`
+}
+
+// ReplayResponse is the body of successful responses from a replay handler.
+type ReplayResponse struct {
+	EventType  string `json:"event_type"`
+	DeliveryID string `json:"delivery_id"`
+}
+
+type replayHandler struct {
+	dispatcher http.Handler
+	secret     string
+	archive    BlobSource
+	appClient  *github.Client
+}
+
+// NewReplayHandler returns an http.Handler for operators that loads a
+// delivery and replays it through dispatcher with ReplayDelivery. Requests
+// are POST requests with a JSON ReplayRequest body and successful responses
+// contain a JSON ReplayResponse. Options set where deliveries are loaded
+// from; requests for a source that is not configured fail.
+//
+// The handler can cause any event to be processed again, so only expose it
+// on an internal port or behind authentication.
+func NewReplayHandler(dispatcher http.Handler, secret string, opts ...ReplayHandlerOption) http.Handler {
+	h := &replayHandler{
+		dispatcher: dispatcher,
+		secret:     secret,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *replayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ReplayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid replay request", http.StatusBadRequest)
+		return
+	}
+
+	var d Delivery
+	var err error
+	switch {
+	case req.ArchiveKey != "" && req.GitHubDeliveryID != 0:
+		http.Error(w, "Set only one of archive_key and github_delivery_id", http.StatusBadRequest)
+		return
+	case req.ArchiveKey != "" && !archiveKeyPattern.MatchString(req.ArchiveKey):
+		http.Error(w, "Invalid archive_key", http.StatusBadRequest)
+		return
+	case req.ArchiveKey != "" && h.archive != nil:
+		d, err = LoadArchivedDelivery(ctx, h.archive, req.ArchiveKey)
+	case req.GitHubDeliveryID != 0 && h.appClient != nil:
+		d, err = LoadGitHubDelivery(ctx, h.appClient, req.GitHubDeliveryID)
+	default:
+		http.Error(w, "No configured source for the requested delivery", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to load delivery for replay")
+		http.Error(w, "Failed to load delivery", http.StatusBadGateway)
+		return
+	}
+
+	if err := ReplayDelivery(ctx, h.dispatcher, h.secret, d); err != nil {
+		logger.Error().Err(err).Str(LogKeyEventType, d.EventType).Str(LogKeyDeliveryID, d.DeliveryID).Msg("Failed to replay delivery")
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	logger.Info().Str(LogKeyEventType, d.EventType).Str(LogKeyDeliveryID, d.DeliveryID).Msg("Replayed delivery")
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(ReplayResponse{EventType: d.EventType, DeliveryID: d.DeliveryID})
+}
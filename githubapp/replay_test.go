@@ -0,0 +1,170 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestReplayDelivery(t *testing.T) {
+	var replayed []string
+	h := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			if !IsReplay(ctx) {
+				t.Error("expected replay flag in handler context")
+			}
+			if strings.Contains(string(payload), "fail") {
+				return errors.New("handler failed")
+			}
+			replayed = append(replayed, deliveryID)
+			return nil
+		},
+	}
+
+	dir := t.TempDir()
+	sink := NewDirectoryBlobSink(dir)
+
+	// the allowlist would reject the request if the dispatcher checked it
+	allowlist := NewHookAllowlist(nil)
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithHookAllowlist(allowlist), WithDeliveryArchive(sink))
+
+	archived := ArchivedDelivery{
+		EventType:  "pull_request",
+		DeliveryID: "archived",
+		ReceivedAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Payload:    json.RawMessage(`{"action": "opened"}`),
+	}
+	b, _ := json.Marshal(archived)
+	if err := sink.Put(context.Background(), archived.Key(), b); err != nil {
+		t.Fatalf("unexpected error writing archive: %v", err)
+	}
+
+	delivery, err := LoadArchivedDelivery(context.Background(), NewDirectoryBlobSource(dir), archived.Key())
+	if err != nil {
+		t.Fatalf("unexpected error loading delivery: %v", err)
+	}
+	if err := ReplayDelivery(context.Background(), d, testHookSecret, delivery); err != nil {
+		t.Fatalf("unexpected error replaying delivery: %v", err)
+	}
+	if len(replayed) != 1 || replayed[0] != "archived" {
+		t.Errorf("incorrect replayed deliveries: %v", replayed)
+	}
+
+	err = ReplayDelivery(context.Background(), d, testHookSecret, Delivery{
+		EventType:  "pull_request",
+		DeliveryID: "failing",
+		Payload:    []byte(`{"action": "fail"}`),
+	})
+	var replayErr ReplayError
+	if !errors.As(err, &replayErr) || replayErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected replay error with status 500, but got %v", err)
+	}
+}
+
+func TestLoadArchivedDeliveryInvalidKey(t *testing.T) {
+	src := blobSourceFunc(func(ctx context.Context, key string) ([]byte, error) {
+		t.Errorf("unexpected read of key %q", key)
+		return nil, nil
+	})
+
+	for _, key := range []string{"", "../secret.json", "/2026/01/02/id.json", "2026/01/02/../../id.json", "2026/01/02/id.yml"} {
+		t.Run(key, func(t *testing.T) {
+			if _, err := LoadArchivedDelivery(context.Background(), src, key); err == nil {
+				t.Errorf("expected error loading key %q", key)
+			}
+		})
+	}
+}
+
+func TestReplayHandler(t *testing.T) {
+	var replayed []string
+	h := &TestEventHandler{
+		Types: []string{"issues"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			replayed = append(replayed, deliveryID)
+			return nil
+		},
+	}
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/hook/deliveries/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"id": 12, "guid": "from-github", "event": "issues", "request": {"payload": {"action": "opened"}}}`)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	handler := NewReplayHandler(d, testHookSecret, WithReplayGitHubDeliveries(cc.client), WithReplayArchive(NewDirectoryBlobSource(t.TempDir())))
+
+	tests := map[string]struct {
+		Method string
+		Body   string
+		Status int
+	}{
+		"github": {
+			Method: http.MethodPost,
+			Body:   `{"github_delivery_id": 12}`,
+			Status: http.StatusOK,
+		},
+		"traversalKey": {
+			Method: http.MethodPost,
+			Body:   `{"archive_key": "../../etc/passwd"}`,
+			Status: http.StatusBadRequest,
+		},
+		"absoluteKey": {
+			Method: http.MethodPost,
+			Body:   `{"archive_key": "/2026/01/02/id.json"}`,
+			Status: http.StatusBadRequest,
+		},
+		"missingArchive": {
+			Method: http.MethodPost,
+			Body:   `{"archive_key": "2026/01/02/id.json"}`,
+			Status: http.StatusBadGateway,
+		},
+		"wrongMethod": {
+			Method: http.MethodGet,
+			Status: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(test.Method, "/admin/replay", strings.NewReader(test.Body)))
+			if w.Code != test.Status {
+				t.Errorf("incorrect status: expected %d, actual %d: %s", test.Status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if len(replayed) != 1 || replayed[0] != "from-github" {
+		t.Errorf("incorrect replayed deliveries: %v", replayed)
+	}
+}
+
+type blobSourceFunc func(ctx context.Context, key string) ([]byte, error)
+
+func (f blobSourceFunc) Get(ctx context.Context, key string) ([]byte, error) {
+	return f(ctx, key)
+}
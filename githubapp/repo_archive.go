@@ -0,0 +1,128 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultMaxArchiveSize int64 = 1 << 30
+)
+
+// ErrArchiveTooLarge is returned when a repository archive exceeds the
+// maximum size.
+var ErrArchiveTooLarge = errors.New("archive exceeds maximum size")
+
+// ArchiveOptions configure how a repository archive is downloaded.
+type ArchiveOptions struct {
+	// Format is the archive format, github.Tarball or github.Zipball. If
+	// empty, it defaults to github.Tarball.
+	Format github.ArchiveFormat
+
+	// MaxSize is the maximum size of the archive in bytes. If zero, it
+	// defaults to DefaultMaxArchiveSize.
+	MaxSize int64
+
+	// HTTPClient downloads the archive from the location returned by
+	// GitHub. If nil, it uses http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// DownloadArchive downloads an archive of the repository owner/repo at ref
+// and writes it to w. It returns the number of bytes written. If the archive
+// is larger than the maximum size, it returns ErrArchiveTooLarge after
+// writing the maximum number of bytes.
+//
+// GitHub responds to archive requests with a redirect to a temporary URL
+// that includes its own authorization, so DownloadArchive requests the
+// redirect location with the client and downloads the archive without the
+// client's credentials, which the archive host rejects.
+func DownloadArchive(ctx context.Context, client *github.Client, owner, repo, ref string, w io.Writer, opts ArchiveOptions) (int64, error) {
+	format := opts.Format
+	if format == "" {
+		format = github.Tarball
+	}
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxArchiveSize
+	}
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	link, _, err := client.Repositories.GetArchiveLink(ctx, owner, repo, format, &github.RepositoryContentGetOptions{Ref: ref}, 0)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get archive link for %s/%s@%s", owner, repo, ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.String(), nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create archive request")
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to download archive for %s/%s@%s", owner, repo, ref)
+	}
+	defer closeBody(res.Body)
+
+	if res.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("failed to download archive for %s/%s@%s: unexpected status code %d", owner, repo, ref, res.StatusCode)
+	}
+	if res.ContentLength > maxSize {
+		return 0, ErrArchiveTooLarge
+	}
+
+	n, err := io.Copy(w, io.LimitReader(res.Body, maxSize))
+	if err != nil {
+		return n, errors.Wrapf(err, "failed to download archive for %s/%s@%s", owner, repo, ref)
+	}
+	if n == maxSize {
+		// the archive is too large if any content remains
+		if m, _ := io.ReadFull(res.Body, make([]byte, 1)); m > 0 {
+			return n, ErrArchiveTooLarge
+		}
+	}
+	return n, nil
+}
+
+// DownloadArchiveFile is like DownloadArchive, but writes the archive to a
+// new temporary file in dir and returns its path. If dir is empty, it uses
+// the default directory for temporary files. Callers must remove the file
+// when they no longer need it. If the download fails, the file is removed.
+func DownloadArchiveFile(ctx context.Context, client *github.Client, owner, repo, ref, dir string, opts ArchiveOptions) (string, error) {
+	f, err := os.CreateTemp(dir, "archive-*")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create archive file")
+	}
+
+	_, err = DownloadArchive(ctx, client, owner, repo, ref, f, opts)
+	if cerr := f.Close(); err == nil && cerr != nil {
+		err = errors.Wrap(cerr, "failed to close archive file")
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestDownloadArchive(t *testing.T) {
+	archive := bytes.Repeat([]byte("a"), 100)
+
+	codeload := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("archive request included credentials")
+		}
+		if r.URL.Query().Get("token") != "temporary" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	t.Cleanup(codeload.Close)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/{format}/{ref}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, codeload.URL+"/octo/repo/legacy.tar.gz/"+r.PathValue("ref")+"?token=temporary", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := github.NewClient(&http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("Authorization", "token secret")
+			return http.DefaultTransport.RoundTrip(r)
+		}),
+	})
+	client.BaseURL, _ = url.Parse(srv.URL + "/")
+
+	ctx := context.Background()
+
+	t.Run("writer", func(t *testing.T) {
+		var out bytes.Buffer
+		n, err := DownloadArchive(ctx, client, "octo", "repo", "main", &out, ArchiveOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if n != int64(len(archive)) || !bytes.Equal(out.Bytes(), archive) {
+			t.Errorf("incorrect archive content: %d bytes", n)
+		}
+	})
+
+	t.Run("tooLarge", func(t *testing.T) {
+		var out bytes.Buffer
+		_, err := DownloadArchive(ctx, client, "octo", "repo", "main", &out, ArchiveOptions{MaxSize: 50})
+		if !errors.Is(err, ErrArchiveTooLarge) {
+			t.Fatalf("expected ErrArchiveTooLarge, but got %v", err)
+		}
+		if out.Len() > 50 {
+			t.Errorf("wrote %d bytes, which exceeds the maximum size", out.Len())
+		}
+	})
+
+	t.Run("exactSize", func(t *testing.T) {
+		var out bytes.Buffer
+		if _, err := DownloadArchive(ctx, client, "octo", "repo", "main", &out, ArchiveOptions{MaxSize: 100}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir := t.TempDir()
+		path, err := DownloadArchiveFile(ctx, client, "octo", "repo", "main", dir, ArchiveOptions{Format: github.Zipball})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read archive file: %v", err)
+		}
+		if !bytes.Equal(b, archive) {
+			t.Errorf("incorrect archive content: %d bytes", len(b))
+		}
+
+		_, err = DownloadArchiveFile(ctx, client, "octo", "repo", "main", dir, ArchiveOptions{MaxSize: 10})
+		if !errors.Is(err, ErrArchiveTooLarge) {
+			t.Fatalf("expected ErrArchiveTooLarge, but got %v", err)
+		}
+		if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+			t.Errorf("expected failed download to be removed, but found %d files", len(entries))
+		}
+	})
+}
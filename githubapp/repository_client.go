@@ -0,0 +1,130 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// NewRepositoryTokenSource returns a token source that creates installation
+// tokens restricted to a single repository of the installation. If perms is
+// not nil, tokens are also restricted to those permissions, which must be a
+// subset of the installation's permissions. Tokens are created with
+// application clients from cc and are reused until they expire.
+//
+// The context is used for requests to create tokens and should live as long
+// as the token source is used.
+func NewRepositoryTokenSource(ctx context.Context, cc ClientCreator, installationID int64, repo string, perms *github.InstallationPermissions) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &repositoryTokenSource{
+		ctx:            ctx,
+		cc:             cc,
+		installationID: installationID,
+		repo:           repo,
+		perms:          perms,
+	})
+}
+
+type repositoryTokenSource struct {
+	ctx            context.Context
+	cc             ClientCreator
+	installationID int64
+	repo           string
+	perms          *github.InstallationPermissions
+}
+
+func (ts *repositoryTokenSource) Token() (*oauth2.Token, error) {
+	client, err := ts.cc.NewAppClient()
+	if err != nil {
+		return nil, err
+	}
+
+	token, _, err := client.Apps.CreateInstallationToken(ts.ctx, ts.installationID, &github.InstallationTokenOptions{
+		Repositories: []string{ts.repo},
+		Permissions:  ts.perms,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create token for %q in installation %d", ts.repo, ts.installationID)
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "token",
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+// NewRepositoryClient returns a client that can only access one repository of
+// an installation, using tokens from NewRepositoryTokenSource. Use it in
+// handlers that operate on a single repository to prevent accidental changes
+// to other repositories covered by the installation.
+//
+// The client is created with NewTokenSourceClient, so installation-specific
+// client middleware and the installation ID used by logging and metrics
+// middleware are not applied. If cc implements RepositoryClientCreator, like
+// the creator returned by NewCachingClientCreator, it creates the client
+// instead, which lets it reuse clients and their tokens between calls.
+func NewRepositoryClient(ctx context.Context, cc ClientCreator, installationID int64, repo string, perms *github.InstallationPermissions) (*github.Client, error) {
+	if rc, ok := cc.(RepositoryClientCreator); ok {
+		return rc.NewRepositoryClient(ctx, installationID, repo, perms)
+	}
+	return cc.NewTokenSourceClient(NewRepositoryTokenSource(ctx, cc, installationID, repo, perms))
+}
+
+// RepositoryClientCreator is implemented by ClientCreators that manage
+// clients restricted to a single repository. NewRepositoryClient and
+// NewEventRepositoryClient use it when it is available.
+type RepositoryClientCreator interface {
+	// NewRepositoryClient returns a client restricted to repo and perms, as
+	// described by the NewRepositoryClient function.
+	NewRepositoryClient(ctx context.Context, installationID int64, repo string, perms *github.InstallationPermissions) (*github.Client, error)
+}
+
+// repositoryCacheKey returns the prefix of the cache key for a repository
+// client. Permissions are part of the key because they restrict the token.
+func repositoryCacheKey(repo string, perms *github.InstallationPermissions) (string, error) {
+	b, err := json.Marshal(perms)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode permissions")
+	}
+	return fmt.Sprintf("repo:%s:%s", strings.ToLower(repo), b), nil
+}
+
+// NewEventRepositoryClient returns a client restricted to the repository of
+// the delivery stored in the context, as described by NewRepositoryClient. It
+// returns an error if the context does not contain a delivery or if the
+// delivery has no installation or repository.
+func NewEventRepositoryClient(ctx context.Context, cc ClientCreator, perms *github.InstallationPermissions) (*github.Client, error) {
+	d, ok := GetDelivery(ctx)
+	if !ok {
+		return nil, errors.New("context does not contain a delivery")
+	}
+
+	p, err := ParseCommonPayload(d.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if p.InstallationID() == 0 || p.RepositoryName() == "" {
+		return nil, errors.Errorf("%s event does not have an installation and repository", d.EventType)
+	}
+
+	return NewRepositoryClient(ctx, cc, p.InstallationID(), p.RepositoryName(), perms)
+}
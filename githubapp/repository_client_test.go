@@ -0,0 +1,131 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestNewEventRepositoryClient(t *testing.T) {
+	var tokenRequests []github.InstallationTokenOptions
+	var authorization string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		var opts github.InstallationTokenOptions
+		_ = json.NewDecoder(r.Body).Decode(&opts)
+		tokenRequests = append(tokenRequests, opts)
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "scoped-token", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("GET /repos/octo/repo", func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cc := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t))
+
+	ctx := WithDelivery(context.Background(), Delivery{
+		EventType: "pull_request",
+		Payload:   []byte(`{"installation": {"id": 1}, "repository": {"name": "repo", "owner": {"login": "octo"}}}`),
+	})
+	perms := &github.InstallationPermissions{Contents: github.String("read")}
+
+	client, err := NewEventRepositoryClient(ctx, cc, perms)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Repositories.Get(ctx, "octo", "repo"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(tokenRequests) != 1 {
+		t.Fatalf("expected 1 token request, but got %d", len(tokenRequests))
+	}
+	if r := tokenRequests[0].Repositories; len(r) != 1 || r[0] != "repo" {
+		t.Errorf("incorrect token repositories: %v", r)
+	}
+	if tokenRequests[0].Permissions.GetContents() != "read" {
+		t.Errorf("incorrect token permissions: %+v", tokenRequests[0].Permissions)
+	}
+	if authorization != "token scoped-token" {
+		t.Errorf("incorrect authorization header: %q", authorization)
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		tokenRequests = nil
+
+		cached, err := NewCachingClientCreator(cc, DefaultCachingClientCapacity)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			ctx, cancel := context.WithCancel(ctx)
+			client, err := NewEventRepositoryClient(ctx, cached, perms)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, _, err := client.Repositories.Get(ctx, "octo", "repo"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			cancel()
+		}
+		if len(tokenRequests) != 1 {
+			t.Errorf("expected 1 token request, but got %d", len(tokenRequests))
+		}
+
+		first, _ := NewEventRepositoryClient(ctx, cached, perms)
+		other, _ := NewEventRepositoryClient(ctx, cached, nil)
+		if first == other {
+			t.Error("expected a different client for different permissions")
+		}
+
+		cached.(ClientInvalidator).Invalidate(1)
+		if second, _ := NewEventRepositoryClient(ctx, cached, perms); first == second {
+			t.Error("expected a new client after invalidation")
+		}
+	})
+
+	t.Run("missingRepository", func(t *testing.T) {
+		ctx := WithDelivery(context.Background(), Delivery{
+			EventType: "installation",
+			Payload:   []byte(`{"installation": {"id": 1}}`),
+		})
+		if _, err := NewEventRepositoryClient(ctx, cc, nil); err == nil {
+			t.Error("expected error, but got nil")
+		}
+	})
+
+	t.Run("missingDelivery", func(t *testing.T) {
+		if _, err := NewEventRepositoryClient(context.Background(), cc, nil); err == nil {
+			t.Error("expected error, but got nil")
+		}
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// MaxDispatchEventTypeSize is the longest event type GitHub accepts for
+	// repository_dispatch events.
+	MaxDispatchEventTypeSize = 100
+
+	// MaxDispatchPayloadProperties is the largest number of top-level
+	// properties GitHub accepts in the client payload of repository_dispatch
+	// events.
+	MaxDispatchPayloadProperties = 10
+)
+
+// SendRepositoryDispatch creates a repository_dispatch event in owner/repo
+// with the given event type. The payload is encoded as JSON and must encode
+// to an object with at most MaxDispatchPayloadProperties top-level
+// properties; use a struct to give the payload a type that the receiver can
+// decode with DecodeClientPayload. A nil payload sends an empty object.
+//
+// Other apps installed on the repository and GitHub Actions workflows that
+// run on the "repository_dispatch" event receive the event.
+func SendRepositoryDispatch(ctx context.Context, client *github.Client, owner, repo, eventType string, payload interface{}) error {
+	if eventType == "" || len(eventType) > MaxDispatchEventTypeSize {
+		return errors.Errorf("dispatch event type must have between 1 and %d characters", MaxDispatchEventTypeSize)
+	}
+
+	raw := json.RawMessage("{}")
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to encode client payload")
+		}
+
+		var properties map[string]json.RawMessage
+		if err := json.Unmarshal(b, &properties); err != nil {
+			return errors.New("client payload must encode to a JSON object")
+		}
+		if len(properties) > MaxDispatchPayloadProperties {
+			return errors.Errorf("client payload has %d top-level properties, but GitHub allows at most %d", len(properties), MaxDispatchPayloadProperties)
+		}
+		raw = b
+	}
+
+	_, _, err := client.Repositories.Dispatch(ctx, owner, repo, github.DispatchRequestOptions{
+		EventType:     eventType,
+		ClientPayload: &raw,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to dispatch %q event to %s/%s", eventType, owner, repo)
+	}
+	return nil
+}
+
+// DecodeClientPayload decodes the client payload of a repository_dispatch
+// event into v, which is usually a pointer to the type that the sender passed
+// to SendRepositoryDispatch.
+func DecodeClientPayload(event *github.RepositoryDispatchEvent, v interface{}) error {
+	if len(event.ClientPayload) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(event.ClientPayload, v); err != nil {
+		return errors.Wrapf(err, "failed to decode client payload of %q event", event.GetAction())
+	}
+	return nil
+}
+
+// RepositoryDispatchFunc handles a repository_dispatch event.
+type RepositoryDispatchFunc func(ctx context.Context, event *github.RepositoryDispatchEvent) error
+
+type repositoryDispatchHandler struct {
+	handlers map[string]RepositoryDispatchFunc
+}
+
+// NewRepositoryDispatchHandler returns an EventHandler for repository_dispatch
+// events that calls the function in handlers for the event type set by the
+// sender, which GitHub reports as the action of the event. Before calling the
+// function, it prepares the context with PrepareRepoContext. Events with
+// types that are not in handlers are ignored.
+func NewRepositoryDispatchHandler(handlers map[string]RepositoryDispatchFunc) EventHandler {
+	return &repositoryDispatchHandler{handlers: handlers}
+}
+
+func (h *repositoryDispatchHandler) Handles() []string {
+	return Handles(EventRepositoryDispatch)
+}
+
+func (h *repositoryDispatchHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event github.RepositoryDispatchEvent
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrap(err, "failed to parse repository dispatch event payload")
+	}
+
+	handle, ok := h.handlers[event.GetAction()]
+	if !ok {
+		zerolog.Ctx(ctx).Debug().Msgf("Ignoring repository dispatch event with type %q", event.GetAction())
+		return nil
+	}
+
+	ctx, _ = PrepareRepoContext(ctx, event.GetInstallation().GetID(), event.GetRepo())
+	return handle(ctx, &event)
+}
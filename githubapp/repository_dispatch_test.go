@@ -0,0 +1,130 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+type deployRequest struct {
+	Environment string `json:"environment"`
+	Ref         string `json:"ref"`
+}
+
+func TestSendRepositoryDispatch(t *testing.T) {
+	tests := map[string]struct {
+		EventType string
+		Payload   interface{}
+
+		Err  bool
+		Body string
+	}{
+		"typedPayload": {
+			EventType: "deploy",
+			Payload:   deployRequest{Environment: "prod", Ref: "main"},
+			Body:      `{"event_type":"deploy","client_payload":{"environment":"prod","ref":"main"}}`,
+		},
+		"nilPayload": {
+			EventType: "sync",
+			Body:      `{"event_type":"sync","client_payload":{}}`,
+		},
+		"emptyEventType": {
+			Err: true,
+		},
+		"longEventType": {
+			EventType: strings.Repeat("x", MaxDispatchEventTypeSize+1),
+			Err:       true,
+		},
+		"notAnObject": {
+			EventType: "deploy",
+			Payload:   []string{"prod"},
+			Err:       true,
+		},
+		"tooManyProperties": {
+			EventType: "deploy",
+			Payload: map[string]int{
+				"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6,
+				"g": 7, "h": 8, "i": 9, "j": 10, "k": 11,
+			},
+			Err: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var body string
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /repos/octo/repo/dispatches", func(w http.ResponseWriter, r *http.Request) {
+				var b json.RawMessage
+				_ = json.NewDecoder(r.Body).Decode(&b)
+				body = string(b)
+				w.WriteHeader(http.StatusNoContent)
+			})
+			cc := newStaticClientCreator(t, mux)
+
+			err := SendRepositoryDispatch(context.Background(), cc.client, "octo", "repo", test.EventType, test.Payload)
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if body != test.Body {
+				t.Errorf("incorrect request body:\nexpected: %s\n  actual: %s", test.Body, body)
+			}
+		})
+	}
+}
+
+func TestRepositoryDispatchHandler(t *testing.T) {
+	var req deployRequest
+	h := NewRepositoryDispatchHandler(map[string]RepositoryDispatchFunc{
+		"deploy": func(ctx context.Context, event *github.RepositoryDispatchEvent) error {
+			return DecodeClientPayload(event, &req)
+		},
+	})
+
+	if h.Handles()[0] != "repository_dispatch" {
+		t.Fatalf("incorrect event types: %v", h.Handles())
+	}
+
+	payload := `{
+		"action": "deploy",
+		"branch": "main",
+		"client_payload": {"environment": "prod", "ref": "main"},
+		"repository": {"name": "repo", "owner": {"login": "octo"}},
+		"installation": {"id": 1}
+	}`
+	if err := h.Handle(context.Background(), "repository_dispatch", "id", []byte(payload)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Environment != "prod" || req.Ref != "main" {
+		t.Errorf("incorrect client payload: %+v", req)
+	}
+
+	unknown := `{"action": "unknown", "client_payload": {}}`
+	if err := h.Handle(context.Background(), "repository_dispatch", "id", []byte(unknown)); err != nil {
+		t.Errorf("unexpected error for unknown event type: %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	DefaultRepositoryTrackerCapacity = 4096
+)
+
+// RepositoryTrackerOption configures properties of a repository tracker.
+type RepositoryTrackerOption func(*RepositoryTracker)
+
+// WithRepositoryTrackerCapacity sets the maximum number of renames and
+// transfers remembered by the tracker. When the tracker is full, it forgets
+// the least recently used names. The default is
+// DefaultRepositoryTrackerCapacity.
+func WithRepositoryTrackerCapacity(capacity int) RepositoryTrackerOption {
+	return func(t *RepositoryTracker) {
+		if capacity > 0 {
+			t.capacity = capacity
+		}
+	}
+}
+
+// RepositoryTracker records repository renames and transfers from
+// "repository" events so that long-lived jobs and caches that store
+// repositories by full name can find the current name of a repository.
+// GitHub redirects most API requests for renamed repositories, but not all
+// of them, and values keyed by the old name are otherwise never updated.
+//
+// RepositoryTracker is an EventHandler and must be registered with the
+// dispatcher to receive events. It is safe for concurrent use.
+type RepositoryTracker struct {
+	capacity int
+	names    *lru.Cache
+}
+
+// NewRepositoryTracker creates an empty RepositoryTracker.
+func NewRepositoryTracker(opts ...RepositoryTrackerOption) *RepositoryTracker {
+	t := &RepositoryTracker{
+		capacity: DefaultRepositoryTrackerCapacity,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	// the capacity is always positive, so New never fails
+	t.names, _ = lru.New(t.capacity)
+	return t
+}
+
+func (t *RepositoryTracker) Handles() []string {
+	return Handles(EventRepository)
+}
+
+func (t *RepositoryTracker) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	var event github.RepositoryEvent
+	if err := unmarshalPayload(ctx, payload, &event); err != nil {
+		return errors.Wrap(err, "failed to parse repository event payload")
+	}
+
+	repo := event.GetRepo()
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+
+	oldOwner, oldName := owner, name
+	switch event.GetAction() {
+	case "renamed":
+		oldName = event.GetChanges().GetRepo().GetName().GetFrom()
+	case "transferred":
+		from := event.GetChanges().GetOwner().GetOwnerInfo()
+		if oldOwner = from.GetOrg().GetLogin(); oldOwner == "" {
+			oldOwner = from.GetUser().GetLogin()
+		}
+	default:
+		return nil
+	}
+
+	if oldOwner == "" || oldName == "" {
+		zerolog.Ctx(ctx).Debug().Msgf("Ignoring %q event for %s/%s without the previous name", event.GetAction(), owner, name)
+		return nil
+	}
+
+	zerolog.Ctx(ctx).Debug().Msgf("Repository %s/%s is now %s/%s", oldOwner, oldName, owner, name)
+	t.RecordRename(oldOwner, oldName, owner, name)
+	return nil
+}
+
+// RecordRename records that the repository oldOwner/oldName is now named
+// newOwner/newName. Applications can use it to restore renames from
+// persistent storage or to record renames discovered by other means.
+func (t *RepositoryTracker) RecordRename(oldOwner, oldName, newOwner, newName string) {
+	from := repositoryKey(oldOwner, oldName)
+	to := repositoryKey(newOwner, newName)
+	if from == to {
+		return
+	}
+
+	// the new name belongs to a live repository, even if it was used before
+	t.names.Remove(to)
+	t.names.Add(from, [2]string{newOwner, newName})
+}
+
+// ResolveCurrentName returns the current owner and name of the repository
+// owner/repo, following any number of recorded renames and transfers.
+// Repository names are case-insensitive. If the tracker does not know of a
+// rename, it returns the input values.
+func (t *RepositoryTracker) ResolveCurrentName(owner, repo string) (string, string) {
+	seen := make(map[string]bool)
+
+	key := repositoryKey(owner, repo)
+	for !seen[key] {
+		seen[key] = true
+
+		v, ok := t.names.Get(key)
+		if !ok {
+			break
+		}
+		next := v.([2]string)
+		owner, repo = next[0], next[1]
+		key = repositoryKey(owner, repo)
+	}
+	return owner, repo
+}
+
+func repositoryKey(owner, repo string) string {
+	return strings.ToLower(owner + "/" + repo)
+}
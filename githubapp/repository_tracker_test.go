@@ -0,0 +1,61 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRepositoryTracker(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewRepositoryTracker()
+
+	events := []string{
+		`{"action": "renamed", "changes": {"repository": {"name": {"from": "old"}}}, "repository": {"name": "new", "owner": {"login": "octo"}}}`,
+		`{"action": "transferred", "changes": {"owner": {"from": {"organization": {"login": "octo"}}}}, "repository": {"name": "new", "owner": {"login": "other-org"}}}`,
+		`{"action": "transferred", "changes": {"owner": {"from": {"user": {"login": "mhaypenny"}}}}, "repository": {"name": "personal", "owner": {"login": "octo"}}}`,
+		`{"action": "renamed", "changes": {"repository": {"name": {"from": "a"}}}, "repository": {"name": "b", "owner": {"login": "octo"}}}`,
+		`{"action": "renamed", "changes": {"repository": {"name": {"from": "b"}}}, "repository": {"name": "a", "owner": {"login": "octo"}}}`,
+		`{"action": "edited", "repository": {"name": "edited", "owner": {"login": "octo"}}}`,
+	}
+	for _, payload := range events {
+		if err := tracker.Handle(ctx, "repository", "", []byte(payload)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	tests := map[string]struct {
+		Owner, Repo       string
+		NewOwner, NewRepo string
+	}{
+		"unknown":            {"octo", "unknown", "octo", "unknown"},
+		"renameThenTransfer": {"octo", "old", "other-org", "new"},
+		"intermediateName":   {"octo", "new", "other-org", "new"},
+		"caseInsensitive":    {"Octo", "OLD", "other-org", "new"},
+		"userTransfer":       {"mhaypenny", "personal", "octo", "personal"},
+		"renamedBack":        {"octo", "a", "octo", "a"},
+		"renamedForward":     {"octo", "b", "octo", "a"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			owner, repo := tracker.ResolveCurrentName(test.Owner, test.Repo)
+			if owner != test.NewOwner || repo != test.NewRepo {
+				t.Errorf("incorrect name: expected %s/%s, actual %s/%s", test.NewOwner, test.NewRepo, owner, repo)
+			}
+		})
+	}
+}
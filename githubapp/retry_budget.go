@@ -0,0 +1,134 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+const (
+	DefaultRetryBudget = 3
+)
+
+// RetryBudget limits the total number of retries made for a request by all
+// middleware that retries requests, like retry, circuit breaker, and rate
+// limit middleware. When policies are composed, each one would otherwise
+// retry the attempts of the others and the number of requests multiplies.
+// Middleware that retries must call Acquire before each retry and give up if
+// it returns false.
+//
+// Functions in this package that retry whole operations, like WaitFor,
+// UploadCheckRunAnnotations, and the timeline functions, acquire retries
+// from the budget in their context. Store a budget in the handler's context
+// with WithRetryBudget to limit the retries of these functions and of the
+// requests they make together.
+//
+// A RetryBudget is safe for concurrent use. A nil budget allows unlimited
+// retries.
+type RetryBudget struct {
+	mu        sync.Mutex
+	remaining int
+	used      int
+}
+
+// NewRetryBudget returns a budget that allows retries total retries.
+func NewRetryBudget(retries int) *RetryBudget {
+	return &RetryBudget{remaining: max(retries, 0)}
+}
+
+// Acquire uses one retry from the budget. It returns false if the budget is
+// exhausted, in which case the caller must not retry.
+func (b *RetryBudget) Acquire() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.remaining <= 0 {
+		return false
+	}
+	b.remaining--
+	b.used++
+	return true
+}
+
+// Remaining returns the number of retries left in the budget, or -1 if the
+// budget is nil and allows unlimited retries.
+func (b *RetryBudget) Remaining() int {
+	if b == nil {
+		return -1
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining
+}
+
+// Used returns the number of retries acquired from the budget.
+func (b *RetryBudget) Used() int {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context that stores b. Requests made with the
+// context share the budget, so handlers can limit the retries of all
+// requests they make, not only each request.
+func WithRetryBudget(ctx context.Context, b *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, b)
+}
+
+// GetRetryBudget returns the budget stored in the context, or nil if the
+// context does not contain one. Middleware that retries requests should use
+// the budget from the request context.
+func GetRetryBudget(ctx context.Context) *RetryBudget {
+	b, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return b
+}
+
+// ClientRetryBudget returns middleware that gives each request a budget of
+// retries, shared by all middleware that runs after it. If the request
+// context already contains a budget, for example from WithRetryBudget, the
+// middleware uses that budget instead. Add it before any middleware that
+// retries requests. If retries is not positive, the budget allows
+// DefaultRetryBudget retries.
+//
+// The installation transport, which retries requests once if GitHub rejects a
+// revoked token, also respects the budget. Functions that retry whole
+// operations, like WaitFor, run before the middleware and only use a budget
+// stored in their context with WithRetryBudget.
+func ClientRetryBudget(retries int) ClientMiddleware {
+	if retries <= 0 {
+		retries = DefaultRetryBudget
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if GetRetryBudget(r.Context()) == nil {
+				r = r.WithContext(WithRetryBudget(r.Context(), NewRetryBudget(retries)))
+			}
+			return next.RoundTrip(r)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRetryBudget(t *testing.T) {
+	b := NewRetryBudget(2)
+	for i := 0; i < 2; i++ {
+		if !b.Acquire() {
+			t.Fatalf("expected retry %d to be allowed", i)
+		}
+	}
+	if b.Acquire() {
+		t.Error("expected exhausted budget to reject retry")
+	}
+	if b.Remaining() != 0 || b.Used() != 2 {
+		t.Errorf("incorrect budget state: remaining=%d, used=%d", b.Remaining(), b.Used())
+	}
+
+	var unlimited *RetryBudget
+	if !unlimited.Acquire() || unlimited.Remaining() != -1 {
+		t.Error("nil budget must allow unlimited retries")
+	}
+}
+
+func TestClientRetryBudget(t *testing.T) {
+	// retrying middleware that retries until the budget is exhausted
+	var attempts int
+	retry := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			for {
+				attempts++
+				res, err := next.RoundTrip(r)
+				if !GetRetryBudget(r.Context()).Acquire() {
+					return res, err
+				}
+			}
+		})
+	}
+	base := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return httptest.NewRecorder().Result(), nil
+	})
+
+	tests := map[string]struct {
+		Budget   *RetryBudget
+		Attempts int
+	}{
+		"perRequestBudget": {
+			Attempts: 3,
+		},
+		"contextBudget": {
+			Budget:   NewRetryBudget(1),
+			Attempts: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts = 0
+			rt := ClientRetryBudget(2)(retry(base))
+
+			ctx := context.Background()
+			if test.Budget != nil {
+				ctx = WithRetryBudget(ctx, test.Budget)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			if _, err := rt.RoundTrip(req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if attempts != test.Attempts {
+				t.Errorf("incorrect attempts: expected %d, actual %d", test.Attempts, attempts)
+			}
+		})
+	}
+}
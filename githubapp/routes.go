@@ -0,0 +1,115 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Route is an HTTP handler and the method and path it serves.
+type Route struct {
+	Method  string
+	Pattern string
+	Handler http.Handler
+}
+
+// WebhookRoute returns a route that serves webhook deliveries from GitHub,
+// which are always POST requests, with h. The handler is usually an event
+// dispatcher.
+func WebhookRoute(pattern string, h http.Handler) Route {
+	return Route{
+		Method:  http.MethodPost,
+		Pattern: pattern,
+		Handler: h,
+	}
+}
+
+// ConfigWebhookRoute returns a route that serves webhook deliveries for the
+// app in c with h, at the path returned by c.WebhookPath.
+func ConfigWebhookRoute(c Config, h http.Handler) Route {
+	return WebhookRoute(c.WebhookPath(), h)
+}
+
+// Router registers handlers for a method and path pattern. The router
+// interface from github.com/go-chi/chi satisfies this interface. Use
+// RouterFunc to adapt other frameworks and ServeMuxRouter for the standard
+// library.
+//
+// For example, to adapt an Echo server:
+//
+//	githubapp.RouterFunc(func(method, pattern string, h http.Handler) {
+//		e.Add(method, pattern, echo.WrapHandler(h))
+//	})
+//
+// And to adapt a Gin engine:
+//
+//	githubapp.RouterFunc(func(method, pattern string, h http.Handler) {
+//		engine.Handle(method, pattern, gin.WrapH(h))
+//	})
+type Router interface {
+	Method(method, pattern string, h http.Handler)
+}
+
+// RouterFunc is a function that implements the Router interface.
+type RouterFunc func(method, pattern string, h http.Handler)
+
+func (f RouterFunc) Method(method, pattern string, h http.Handler) {
+	f(method, pattern, h)
+}
+
+// ServeMuxRouter returns a Router that registers handlers with mux using
+// method patterns, like "POST /api/github/hook".
+func ServeMuxRouter(mux *http.ServeMux) Router {
+	return RouterFunc(func(method, pattern string, h http.Handler) {
+		mux.Handle(method+" "+pattern, h)
+	})
+}
+
+// RegisterWebhookRoutes registers webhook routes for one or more apps with r,
+// usually created by ConfigWebhookRoute for each app. Unlike RegisterRoutes,
+// it returns an error without registering any routes if a route has no
+// handler, has a pattern that does not start with "/", or has the same method
+// and pattern as another route, which happens when several apps share a
+// server but not all of them configure a webhook route.
+func RegisterWebhookRoutes(r Router, routes ...Route) error {
+	seen := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		if route.Handler == nil {
+			return errors.Errorf("webhook route %q has no handler", route.Pattern)
+		}
+		if !strings.HasPrefix(route.Pattern, "/") {
+			return errors.Errorf("webhook route %q must start with \"/\"", route.Pattern)
+		}
+
+		key := route.Method + " " + route.Pattern
+		if seen[key] {
+			return errors.Errorf("multiple webhook handlers for %q", key)
+		}
+		seen[key] = true
+	}
+
+	RegisterRoutes(r, routes...)
+	return nil
+}
+
+// RegisterRoutes registers each route with r.
+func RegisterRoutes(r Router, routes ...Route) {
+	for _, route := range routes {
+		r.Method(route.Method, route.Pattern, route.Handler)
+	}
+}
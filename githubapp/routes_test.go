@@ -0,0 +1,125 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRegisterRoutes(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("routerFunc", func(t *testing.T) {
+		var registered []string
+		r := RouterFunc(func(method, pattern string, h http.Handler) {
+			registered = append(registered, method+" "+pattern)
+		})
+
+		RegisterRoutes(r,
+			WebhookRoute(DefaultWebhookRoute, ok),
+			Route{Method: http.MethodGet, Pattern: "/api/github/auth", Handler: ok},
+		)
+
+		expected := []string{"POST /api/github/hook", "GET /api/github/auth"}
+		if len(registered) != len(expected) {
+			t.Fatalf("expected %d routes, but got %d", len(expected), len(registered))
+		}
+		for i := range expected {
+			if registered[i] != expected[i] {
+				t.Errorf("incorrect route %d: expected %q, actual %q", i, expected[i], registered[i])
+			}
+		}
+	})
+
+	t.Run("serveMux", func(t *testing.T) {
+		mux := http.NewServeMux()
+		RegisterRoutes(ServeMuxRouter(mux), WebhookRoute(DefaultWebhookRoute, ok))
+
+		tests := map[string]int{
+			http.MethodPost: http.StatusNoContent,
+			http.MethodGet:  http.StatusMethodNotAllowed,
+		}
+		for method, status := range tests {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(method, DefaultWebhookRoute, nil))
+			if w.Code != status {
+				t.Errorf("incorrect status for %s: expected %d, actual %d", method, status, w.Code)
+			}
+		}
+	})
+}
+
+func TestRegisterWebhookRoutes(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	var v1, v2 Config
+	v2.App.WebhookRoute = "/api/github/hook/v2"
+
+	tests := map[string]struct {
+		Routes     []Route
+		Registered []string
+		Err        bool
+	}{
+		"perApp": {
+			Routes:     []Route{ConfigWebhookRoute(v1, ok), ConfigWebhookRoute(v2, ok)},
+			Registered: []string{"POST /api/github/hook", "POST /api/github/hook/v2"},
+		},
+		"duplicate": {
+			Routes: []Route{ConfigWebhookRoute(v1, ok), WebhookRoute(DefaultWebhookRoute, ok)},
+			Err:    true,
+		},
+		"relative": {
+			Routes: []Route{WebhookRoute("api/github/hook", ok)},
+			Err:    true,
+		},
+		"noHandler": {
+			Routes: []Route{ConfigWebhookRoute(v2, nil)},
+			Err:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var registered []string
+			r := RouterFunc(func(method, pattern string, h http.Handler) {
+				registered = append(registered, method+" "+pattern)
+			})
+
+			err := RegisterWebhookRoutes(r, test.Routes...)
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				if len(registered) > 0 {
+					t.Errorf("expected no routes after an error, but got %v", registered)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if fmt.Sprint(registered) != fmt.Sprint(test.Registered) {
+				t.Errorf("incorrect routes: expected %v, actual %v", test.Registered, registered)
+			}
+		})
+	}
+}
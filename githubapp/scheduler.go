@@ -16,6 +16,11 @@ package githubapp
 
 import (
 	"context"
+	"net/http"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -31,6 +36,11 @@ const (
 	MetricsKeyDroppedEvents = "github.event.dropped"
 )
 
+const (
+	// dispatchTaskType is the runtime/trace task type for handler execution
+	dispatchTaskType = "github_event"
+)
+
 const (
 	// values from metrics.NewTimer, which match those used by UNIX load averages
 	histogramReservoirSize = 1028
@@ -39,6 +49,10 @@ const (
 
 var (
 	ErrCapacityExceeded = errors.New("scheduler: capacity exceeded")
+
+	// ErrSchedulerDraining is returned by asynchronous schedulers for
+	// dispatches scheduled after a call to Drain.
+	ErrSchedulerDraining = errors.New("scheduler: draining")
 )
 
 // Dispatch is a webhook payload and the handler that handles it.
@@ -48,11 +62,44 @@ type Dispatch struct {
 	EventType  string
 	DeliveryID string
 	Payload    []byte
+
+	// Header contains the headers that describe the delivery, as returned by
+	// DeliveryHeader, or nil if they are not available.
+	Header http.Header
 }
 
-// Execute calls the Dispatch's handler with the stored arguments.
-func (d Dispatch) Execute(ctx context.Context) error {
-	return d.Handler.Handle(ctx, d.EventType, d.DeliveryID, d.Payload)
+// Execute calls the Dispatch's handler with the stored arguments. The
+// context passed to the handler contains the delivery, as returned by
+// GetDelivery.
+//
+// The handler runs with pprof labels for the event type and installation ID,
+// so CPU profiles attribute work to specific events, and in a runtime/trace
+// task, so execution traces group the work done for each event.
+func (d Dispatch) Execute(ctx context.Context) (err error) {
+	if dd, ok := GetDelivery(ctx); !ok || dd.DeliveryID != d.DeliveryID || dd.EventType != d.EventType {
+		ctx = WithDelivery(ctx, Delivery{
+			EventType:  d.EventType,
+			DeliveryID: d.DeliveryID,
+			Payload:    d.Payload,
+			Header:     d.Header,
+		})
+	}
+
+	ctx, task := trace.NewTask(ctx, dispatchTaskType)
+	defer task.End()
+
+	trace.Log(ctx, LogKeyEventType, d.EventType)
+	trace.Log(ctx, LogKeyDeliveryID, d.DeliveryID)
+
+	labels := []string{LogKeyEventType, d.EventType}
+	if e, peekErr := PeekEnvelopeContext(ctx, d.Payload); peekErr == nil && e.InstallationID > 0 {
+		labels = append(labels, LogKeyInstallationID, strconv.FormatInt(e.InstallationID, 10))
+	}
+
+	pprof.Do(ctx, pprof.Labels(labels...), func(ctx context.Context) {
+		err = d.Handler.Handle(ctx, d.EventType, d.DeliveryID, d.Payload)
+	})
+	return err
 }
 
 // AsyncErrorCallback is called by an asynchronous scheduler when an event
@@ -81,18 +128,56 @@ func MetricsAsyncErrorCallback(reg metrics.Registry) AsyncErrorCallback {
 // The new context must be based on context.Background(), not the input.
 type ContextDeriver func(context.Context) context.Context
 
-// DefaultContextDeriver copies the logger from the request's context to a new
-// context.
+// DefaultContextDeriver copies the logger, the replay flag, the JSON decoder,
+// and the clock from the request's context to a new context and initializes
+// the new context for RateLimits.
 func DefaultContextDeriver(ctx context.Context) context.Context {
 	newCtx := context.Background()
 
-	// this value is always unused by async schedulers, but is set for
-	// compatibility with existing handlers that call SetResponder
-	newCtx = InitializeResponder(newCtx)
+	// the response is sent before async handlers run, but handlers that call
+	// SetResponder must not panic, so mark responders as unavailable
+	newCtx = InitializeAsyncResponder(newCtx)
+	newCtx = InitializeRateLimits(newCtx)
+	if IsReplay(ctx) {
+		newCtx = WithReplay(newCtx)
+	}
+	if unmarshal := contextJSONUnmarshal(ctx); unmarshal != nil {
+		newCtx = WithJSONUnmarshal(newCtx, unmarshal)
+	}
+	if version, ok := ctx.Value(serverVersionKey{}).(string); ok {
+		newCtx = WithServerVersion(newCtx, version)
+	}
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		newCtx = WithClock(newCtx, clock)
+	}
 
 	return zerolog.Ctx(ctx).WithContext(newCtx)
 }
 
+// DispatchTracer starts a trace span for a dispatch accepted by an
+// asynchronous scheduler. It is called with the derived context before the
+// dispatch is queued and returns the context passed to the handler. Use a
+// ContextDeriver that copies the span from the request context to link the
+// new span to the request that delivered the event.
+//
+// This allows integration with tracing libraries like OpenTelemetry without
+// adding a dependency to this package.
+type DispatchTracer func(ctx context.Context, d Dispatch) (context.Context, DispatchSpan)
+
+// DispatchSpan is a trace span that covers the queue wait and execution of a
+// dispatch.
+type DispatchSpan interface {
+	// Executing is called when the scheduler starts the handler, which marks
+	// the end of the time the dispatch spent waiting in a queue.
+	Executing()
+
+	// End is called when the handler returns. The error is the error
+	// returned by the handler, a HandlerPanicError if the handler panicked,
+	// or nil. If the scheduler drops the dispatch, End is called with
+	// ErrCapacityExceeded and Executing is never called.
+	End(err error)
+}
+
 // Scheduler is a strategy for executing event handlers.
 //
 // The Schedule method takes a Dispatch and executes it by calling the handler
@@ -107,6 +192,52 @@ type Scheduler interface {
 	Schedule(ctx context.Context, d Dispatch) error
 }
 
+// SchedulerStats describes the current load of a scheduler.
+type SchedulerStats struct {
+	// QueueLength is the number of dispatches waiting for a worker and
+	// QueueCapacity is the maximum number of waiting dispatches.
+	QueueLength   int
+	QueueCapacity int
+
+	// ActiveWorkers is the number of workers executing handlers and Workers
+	// is the total number of workers.
+	ActiveWorkers int
+	Workers       int
+}
+
+// Saturation returns the fraction of the scheduler's capacity in use, from 0
+// to 1. The capacity includes both workers and queue slots. A scheduler with
+// a saturation of 1 drops new dispatches.
+func (s SchedulerStats) Saturation() float64 {
+	total := s.QueueCapacity + s.Workers
+	if total <= 0 {
+		return 0
+	}
+	return min(float64(s.QueueLength+s.ActiveWorkers)/float64(total), 1)
+}
+
+// StatsScheduler is implemented by schedulers with limited capacity that can
+// report their current load, like the scheduler returned by
+// QueueAsyncScheduler.
+type StatsScheduler interface {
+	Scheduler
+	Stats() SchedulerStats
+}
+
+// DrainScheduler is implemented by asynchronous schedulers that can stop
+// accepting dispatches and wait for accepted dispatches to finish, like the
+// schedulers returned by AsyncScheduler, BoundedAsyncScheduler, and
+// QueueAsyncScheduler.
+type DrainScheduler interface {
+	Scheduler
+
+	// Drain stops the scheduler from accepting new dispatches and waits
+	// until all accepted dispatches, including queued dispatches, finish or
+	// the context is canceled. After Drain is called, Schedule returns
+	// ErrSchedulerDraining. Drain may be called more than once.
+	Drain(ctx context.Context) error
+}
+
 // SchedulerOption configures properties of a scheduler.
 type SchedulerOption func(*scheduler)
 
@@ -130,6 +261,15 @@ func WithContextDeriver(deriver ContextDeriver) SchedulerOption {
 	}
 }
 
+// WithDispatchTracer sets a tracer that starts a span for each dispatch
+// accepted by an asynchronous scheduler. The span covers the time the
+// dispatch waits for a worker and the execution of the handler.
+func WithDispatchTracer(tracer DispatchTracer) SchedulerOption {
+	return func(s *scheduler) {
+		s.tracer = tracer
+	}
+}
+
 // WithSchedulingMetrics enables metrics reporting for schedulers.
 func WithSchedulingMetrics(r metrics.Registry) SchedulerOption {
 	return func(s *scheduler) {
@@ -143,44 +283,127 @@ func WithSchedulingMetrics(r metrics.Registry) SchedulerOption {
 		sample := metrics.NewExpDecaySample(histogramReservoirSize, histogramAlpha)
 		s.eventAge = metrics.NewRegisteredHistogram(MetricsKeyEventAge, r, sample)
 		s.dropped = metrics.NewRegisteredCounter(MetricsKeyDroppedEvents, r)
+		s.registry = r
+	}
+}
+
+// WithSchedulingInstallationTags also counts dropped events with metrics
+// tagged with the event type and with the installation as selected by tags,
+// like "github.event.dropped[event:push,installation:123]". It requires
+// WithSchedulingMetrics.
+func WithSchedulingInstallationTags(tags *InstallationTags) SchedulerOption {
+	return func(s *scheduler) {
+		s.installationTags = tags
 	}
 }
 
 type queueDispatch struct {
-	ctx context.Context
-	t   time.Time
-	d   Dispatch
+	ctx  context.Context
+	t    time.Time
+	d    Dispatch
+	span DispatchSpan
+
+	// installationID is only set when the scheduler has quotas
+	installationID int64
+
+	// spillKey is the key of the payload in the spill store, if the payload
+	// was spilled
+	spillKey string
 }
 
 // core functionality and options for (async) schedulers
 type scheduler struct {
 	onError AsyncErrorCallback
 	deriver ContextDeriver
+	tracer  DispatchTracer
 
 	activeWorkers int64
 	queue         chan queueDispatch
 
+	// mu protects draining and guarantees that no dispatches are accepted
+	// or queued after Drain starts waiting for pending dispatches
+	mu       sync.RWMutex
+	draining bool
+	pending  sync.WaitGroup
+
 	eventAge metrics.Histogram
 	dropped  metrics.Counter
+	registry metrics.Registry
+
+	installationTags *InstallationTags
+
+	deleted     *deletedInstallations
+	quotas      *installationQuotas
+	eventLimits *eventTypeLimits
+	spill       *payloadSpill
+}
+
+func (s *scheduler) safeExecute(ctx context.Context, d Dispatch, span DispatchSpan) {
+	if err := s.execute(ctx, d, span); err != nil && s.onError != nil {
+		s.onError(ctx, d, err)
+	}
 }
 
-func (s *scheduler) safeExecute(ctx context.Context, d Dispatch) {
-	var err error
+// execute executes d, recovering panics, and returns the error to report, if
+// any. Errors for deleted installations are not reported when the scheduler
+// drops their dispatches.
+func (s *scheduler) execute(ctx context.Context, d Dispatch, span DispatchSpan) (err error) {
 	defer func() {
 		atomic.AddInt64(&s.activeWorkers, -1)
 		if r := recover(); r != nil {
-			err = HandlerPanicError{
-				value: r,
-				stack: getStack(1),
-			}
+			err = recoveredPanic(ctx, r)
+		}
+		if span != nil {
+			span.End(err)
 		}
-		if err != nil && s.onError != nil {
-			s.onError(ctx, d, err)
+		if s.deleted.handleError(ctx, err, GetClock(ctx).Now()) {
+			err = nil
 		}
 	}()
 
 	atomic.AddInt64(&s.activeWorkers, 1)
-	err = d.Execute(ctx)
+	if span != nil {
+		span.Executing()
+	}
+	if s.deleted.skip(ctx, d, GetClock(ctx).Now()) {
+		return nil
+	}
+	return d.Execute(ctx)
+}
+
+func (s *scheduler) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.draining {
+		s.draining = true
+		if s.queue != nil {
+			close(s.queue)
+		}
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// countDropped records a dispatch dropped because of limited capacity.
+func (s *scheduler) countDropped(ctx context.Context, d Dispatch) {
+	if s.dropped != nil {
+		s.dropped.Inc(1)
+	}
+	if s.registry != nil && s.installationTags != nil {
+		key := MetricsKeyDroppedEvents + eventMetricTags(ctx, s.installationTags, d.EventType, d.Payload)
+		metrics.GetOrRegisterCounter(key, s.registry).Inc(1)
+	}
 }
 
 func (s *scheduler) derive(ctx context.Context) context.Context {
@@ -190,6 +413,13 @@ func (s *scheduler) derive(ctx context.Context) context.Context {
 	return s.deriver(ctx)
 }
 
+func (s *scheduler) startSpan(ctx context.Context, d Dispatch) (context.Context, DispatchSpan) {
+	if s.tracer == nil {
+		return ctx, nil
+	}
+	return s.tracer(ctx, d)
+}
+
 // DefaultScheduler returns a scheduler that executes handlers in the go
 // routine of the caller and returns any error.
 func DefaultScheduler() Scheduler {
@@ -203,7 +433,8 @@ func (s *defaultScheduler) Schedule(ctx context.Context, d Dispatch) error {
 }
 
 // AsyncScheduler returns a scheduler that executes handlers in new goroutines.
-// Goroutines are not reused and there is no limit on the number created.
+// Goroutines are not reused and there is no limit on the number created. Use
+// BoundedAsyncScheduler to limit the number of concurrent handlers.
 func AsyncScheduler(opts ...SchedulerOption) Scheduler {
 	s := &asyncScheduler{
 		scheduler: scheduler{
@@ -222,7 +453,20 @@ type asyncScheduler struct {
 }
 
 func (s *asyncScheduler) Schedule(ctx context.Context, d Dispatch) error {
-	go s.safeExecute(s.derive(ctx), d)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.draining {
+		return ErrSchedulerDraining
+	}
+
+	ctx, span := s.startSpan(s.derive(ctx), d)
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		s.safeExecute(ctx, d, span)
+	}()
 	return nil
 }
 
@@ -243,18 +487,19 @@ func QueueAsyncScheduler(queueSize int, workers int, opts ...SchedulerOption) Sc
 			onError: DefaultAsyncErrorCallback,
 			queue:   make(chan queueDispatch, queueSize),
 		},
+		workers: workers,
 	}
 	for _, opt := range opts {
 		opt(&s.scheduler)
 	}
+	if s.quotas != nil {
+		s.quotas.init(queueSize, workers)
+	}
 
 	for i := 0; i < workers; i++ {
 		go func() {
 			for d := range s.queue {
-				if s.eventAge != nil {
-					s.eventAge.Update(time.Since(d.t).Milliseconds())
-				}
-				s.safeExecute(d.ctx, d.d)
+				s.run(d)
 			}
 		}()
 	}
@@ -264,16 +509,99 @@ func QueueAsyncScheduler(queueSize int, workers int, opts ...SchedulerOption) Sc
 
 type queueScheduler struct {
 	scheduler
+	workers int
+}
+
+// run executes a dispatch from the queue and then any dispatches for the
+// same installation that waited for it to finish.
+func (s *queueScheduler) run(d queueDispatch) {
+	if !s.quotas.start(d) {
+		return
+	}
+
+	for {
+		if s.eventAge != nil {
+			s.eventAge.Update(GetClock(d.ctx).Now().Sub(d.t).Milliseconds())
+		}
+		s.eventLimits.release(d.d.EventType)
+		if err := s.spill.load(&d); err != nil {
+			s.failLoad(d, err)
+		} else {
+			s.safeExecute(d.ctx, d.d, d.span)
+		}
+		s.spill.discard(d)
+		s.pending.Done()
+
+		next, ok := s.quotas.finish(d.installationID)
+		if !ok {
+			return
+		}
+		d = next
+	}
+}
+
+func (s *queueScheduler) Stats() SchedulerStats {
+	return SchedulerStats{
+		QueueLength:   len(s.queue) + s.quotas.waitingCount(),
+		QueueCapacity: cap(s.queue),
+		ActiveWorkers: int(atomic.LoadInt64(&s.activeWorkers)),
+		Workers:       s.workers,
+	}
 }
 
 func (s *queueScheduler) Schedule(ctx context.Context, d Dispatch) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.draining {
+		return ErrSchedulerDraining
+	}
+
+	ctx, span := s.startSpan(s.derive(ctx), d)
+
+	qd := queueDispatch{ctx: ctx, t: GetClock(ctx).Now(), d: d, span: span}
+	if s.quotas != nil {
+		if e, err := PeekEnvelopeContext(ctx, d.Payload); err == nil {
+			qd.installationID = e.InstallationID
+		}
+	}
+	if !s.quotas.admit(qd.installationID) {
+		return s.drop(ctx, d, span)
+	}
+	if !s.eventLimits.admit(d.EventType) {
+		s.quotas.cancel(qd.installationID)
+		return s.drop(ctx, d, span)
+	}
+
+	s.spill.put(&qd, s.registry)
+
+	s.pending.Add(1)
 	select {
-	case s.queue <- queueDispatch{ctx: s.derive(ctx), t: time.Now(), d: d}:
+	case s.queue <- qd:
 	default:
-		if s.dropped != nil {
-			s.dropped.Inc(1)
-		}
-		return ErrCapacityExceeded
+		s.pending.Done()
+		s.quotas.cancel(qd.installationID)
+		s.eventLimits.release(d.EventType)
+		s.spill.discard(qd)
+		return s.drop(ctx, d, span)
 	}
 	return nil
 }
+
+// failLoad reports a dispatch whose spilled payload could not be loaded.
+func (s *queueScheduler) failLoad(d queueDispatch, err error) {
+	if d.span != nil {
+		d.span.End(err)
+	}
+	if s.onError != nil {
+		s.onError(d.ctx, d.d, err)
+	}
+}
+
+func (s *queueScheduler) drop(ctx context.Context, d Dispatch, span DispatchSpan) error {
+	s.countDropped(ctx, d)
+	if span != nil {
+		span.End(ErrCapacityExceeded)
+	}
+	return ErrCapacityExceeded
+}
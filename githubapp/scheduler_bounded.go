@@ -0,0 +1,191 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// OverflowPolicy decides what a bounded scheduler does with a dispatch when
+// the maximum number of handlers are already executing.
+type OverflowPolicy int
+
+const (
+	// OverflowReject drops the dispatch and returns ErrCapacityExceeded, like
+	// a full QueueAsyncScheduler.
+	OverflowReject OverflowPolicy = iota
+
+	// OverflowWait waits for a handler to finish before starting the
+	// dispatch. The dispatcher does not respond to GitHub while waiting. If
+	// the request context is canceled first, the dispatch is dropped and
+	// Schedule returns ErrCapacityExceeded.
+	OverflowWait
+
+	// OverflowRunSync executes the dispatch in the caller's goroutine, like
+	// DefaultScheduler, and returns the handler's error. This slows the
+	// response to GitHub instead of dropping events. The dispatch still
+	// recovers panics, counts as in flight, and is traced and skipped for
+	// deleted installations like asynchronous dispatches.
+	OverflowRunSync
+)
+
+var overflowPolicyNames = map[OverflowPolicy]string{
+	OverflowReject:  "reject",
+	OverflowWait:    "wait",
+	OverflowRunSync: "run_sync",
+}
+
+func (p OverflowPolicy) String() string {
+	if name, ok := overflowPolicyNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+}
+
+// MarshalText returns the name of the policy, like "reject", for use in
+// configuration files.
+func (p OverflowPolicy) MarshalText() ([]byte, error) {
+	if _, ok := overflowPolicyNames[p]; !ok {
+		return nil, errors.Errorf("unknown overflow policy %d", int(p))
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText sets the policy from its name: "reject", "wait", or
+// "run_sync".
+func (p *OverflowPolicy) UnmarshalText(b []byte) error {
+	for policy, name := range overflowPolicyNames {
+		if name == string(b) {
+			*p = policy
+			return nil
+		}
+	}
+	return errors.Errorf("unknown overflow policy %q", b)
+}
+
+// InFlightScheduler is implemented by asynchronous schedulers that report how
+// many handlers are executing, like the schedulers returned by
+// AsyncScheduler, BoundedAsyncScheduler, and QueueAsyncScheduler.
+type InFlightScheduler interface {
+	Scheduler
+	InFlight() int
+}
+
+func (s *scheduler) InFlight() int {
+	return int(atomic.LoadInt64(&s.activeWorkers))
+}
+
+// BoundedAsyncScheduler returns a scheduler that executes handlers in new
+// goroutines, like AsyncScheduler, but with at most maxConcurrent handlers
+// executing at once. When all slots are in use, the overflow policy decides
+// what happens to new dispatches. It is a middle ground between the
+// unbounded AsyncScheduler and the fixed workers and queue of
+// QueueAsyncScheduler.
+//
+// The scheduler implements StatsScheduler, reporting maxConcurrent as its
+// workers and no queue, so it works with WithLoadShedding.
+func BoundedAsyncScheduler(maxConcurrent int, policy OverflowPolicy, opts ...SchedulerOption) Scheduler {
+	if maxConcurrent < 1 {
+		panic("BoundedAsyncScheduler: max concurrent executions must be positive")
+	}
+
+	s := &boundedScheduler{
+		scheduler: scheduler{
+			deriver: DefaultContextDeriver,
+			onError: DefaultAsyncErrorCallback,
+		},
+		slots:  make(chan struct{}, maxConcurrent),
+		policy: policy,
+	}
+	for _, opt := range opts {
+		opt(&s.scheduler)
+	}
+	return s
+}
+
+type boundedScheduler struct {
+	scheduler
+	slots  chan struct{}
+	policy OverflowPolicy
+}
+
+func (s *boundedScheduler) Schedule(ctx context.Context, d Dispatch) error {
+	if !s.begin() {
+		return ErrSchedulerDraining
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		switch s.policy {
+		case OverflowWait:
+			// the dispatch is pending, so Drain waits for it, but the lock is
+			// not held while waiting, so Drain and other dispatches can proceed
+			select {
+			case s.slots <- struct{}{}:
+			case <-ctx.Done():
+				s.pending.Done()
+				return s.drop(ctx, d)
+			}
+		case OverflowRunSync:
+			defer s.pending.Done()
+			ctx, span := s.startSpan(ctx, d)
+			return s.execute(ctx, d, span)
+		default:
+			s.pending.Done()
+			return s.drop(ctx, d)
+		}
+	}
+
+	ctx, span := s.startSpan(s.derive(ctx), d)
+
+	go func() {
+		defer func() {
+			<-s.slots
+			s.pending.Done()
+		}()
+		s.safeExecute(ctx, d, span)
+	}()
+	return nil
+}
+
+// begin adds a pending dispatch and returns true if the scheduler is not
+// draining.
+func (s *boundedScheduler) begin() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.draining {
+		return false
+	}
+	s.pending.Add(1)
+	return true
+}
+
+func (s *boundedScheduler) drop(ctx context.Context, d Dispatch) error {
+	s.countDropped(ctx, d)
+	return ErrCapacityExceeded
+}
+
+func (s *boundedScheduler) Stats() SchedulerStats {
+	return SchedulerStats{
+		ActiveWorkers: len(s.slots),
+		Workers:       cap(s.slots),
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestBoundedAsyncScheduler(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	// fill schedules a blocked dispatch that occupies the only slot of s
+	fill := func(t *testing.T, s Scheduler) *AsyncHandler {
+		h := &AsyncHandler{Block: make(chan struct{}), Called: make(chan bool, 2)}
+		if err := s.Schedule(context.Background(), Dispatch{Handler: h}); err != nil {
+			t.Fatalf("unexpected error scheduling dispatch: %v", err)
+		}
+		return h
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		s := BoundedAsyncScheduler(1, OverflowReject)
+		h := fill(t, s)
+		defer close(h.Block)
+
+		err := s.Schedule(context.Background(), Dispatch{Handler: h})
+		if !errors.Is(err, ErrCapacityExceeded) {
+			t.Fatalf("expected ErrCapacityExceeded, but got %v", err)
+		}
+
+		stats := s.(StatsScheduler).Stats()
+		if stats.ActiveWorkers != 1 || stats.Workers != 1 {
+			t.Errorf("incorrect stats: %+v", stats)
+		}
+	})
+
+	t.Run("waitCanceled", func(t *testing.T) {
+		s := BoundedAsyncScheduler(1, OverflowWait)
+		h := fill(t, s)
+		defer close(h.Block)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := s.Schedule(ctx, Dispatch{Handler: h})
+		if !errors.Is(err, ErrCapacityExceeded) {
+			t.Fatalf("expected ErrCapacityExceeded, but got %v", err)
+		}
+	})
+
+	t.Run("waitForSlot", func(t *testing.T) {
+		s := BoundedAsyncScheduler(1, OverflowWait)
+		h := fill(t, s)
+
+		time.AfterFunc(10*time.Millisecond, func() { close(h.Block) })
+		if err := s.Schedule(context.Background(), Dispatch{Handler: h}); err != nil {
+			t.Fatalf("unexpected error scheduling dispatch: %v", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			select {
+			case <-h.Called:
+			case <-time.After(timeout):
+				t.Fatalf("handler was not called twice after %v", timeout)
+			}
+		}
+	})
+
+	t.Run("runSync", func(t *testing.T) {
+		s := BoundedAsyncScheduler(1, OverflowRunSync)
+		h := fill(t, s)
+		defer close(h.Block)
+
+		inline := &AsyncHandler{Called: make(chan bool, 1), Error: errors.New("handler error")}
+		if err := s.Schedule(context.Background(), Dispatch{Handler: inline}); err == nil {
+			t.Fatal("expected handler error from synchronous execution, but got nil")
+		}
+		if len(inline.Called) != 1 {
+			t.Error("handler was not called synchronously")
+		}
+
+		deadline := time.Now().Add(timeout)
+		for s.(InFlightScheduler).InFlight() != 1 {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected 1 handler in flight, but got %d", s.(InFlightScheduler).InFlight())
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		var inFlight int
+		panics := &TestEventHandler{
+			Types: []string{"ping"},
+			Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+				inFlight = s.(InFlightScheduler).InFlight()
+				panic("handler panic")
+			},
+		}
+		if err := s.Schedule(context.Background(), Dispatch{Handler: panics}); err == nil {
+			t.Fatal("expected error for recovered panic, but got nil")
+		}
+		if inFlight != 2 {
+			t.Errorf("expected synchronous dispatch to count as in flight, but got %d", inFlight)
+		}
+	})
+
+	t.Run("waitDoesNotBlockDrain", func(t *testing.T) {
+		s := BoundedAsyncScheduler(1, OverflowWait)
+		h := fill(t, s)
+
+		waiting := make(chan error, 1)
+		go func() { waiting <- s.Schedule(context.Background(), Dispatch{Handler: h}) }()
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := s.(DrainScheduler).Drain(ctx); err == nil {
+			t.Fatal("expected drain to time out while handlers are blocked, but got nil")
+		}
+		if err := s.Schedule(context.Background(), Dispatch{Handler: h}); !errors.Is(err, ErrSchedulerDraining) {
+			t.Fatalf("expected ErrSchedulerDraining, but got %v", err)
+		}
+
+		close(h.Block)
+		if err := <-waiting; err != nil {
+			t.Fatalf("unexpected error from waiting dispatch: %v", err)
+		}
+		if err := s.(DrainScheduler).Drain(context.Background()); err != nil {
+			t.Fatalf("unexpected error draining scheduler: %v", err)
+		}
+	})
+
+	t.Run("inFlight", func(t *testing.T) {
+		s := BoundedAsyncScheduler(2, OverflowReject)
+		h := fill(t, s)
+
+		deadline := time.Now().Add(timeout)
+		for s.(InFlightScheduler).InFlight() != 1 {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected 1 handler in flight, but got %d", s.(InFlightScheduler).InFlight())
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		close(h.Block)
+		if err := s.(DrainScheduler).Drain(context.Background()); err != nil {
+			t.Fatalf("unexpected error draining scheduler: %v", err)
+		}
+		if n := s.(InFlightScheduler).InFlight(); n != 0 {
+			t.Errorf("expected no handlers in flight after drain, but got %d", n)
+		}
+	})
+}
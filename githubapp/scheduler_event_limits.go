@@ -0,0 +1,95 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"sync"
+)
+
+// WithEventTypeQueueLimits limits the number of dispatches for each event
+// type that a QueueAsyncScheduler holds before their handlers start, so a
+// flood of one type, like "status" events from a busy CI system, cannot fill
+// the queue and starve other types, like "pull_request". The scheduler
+// rejects new dispatches for a type with ErrCapacityExceeded when this many
+// are waiting. Event types without a positive limit are only limited by the
+// size of the queue.
+//
+// Dispatches waiting for their installation's handlers to finish because of
+// WithInstallationQuotas count against the limit of their type.
+//
+// This option has no effect on other schedulers.
+func WithEventTypeQueueLimits(limits map[string]int) SchedulerOption {
+	return func(s *scheduler) {
+		l := &eventTypeLimits{
+			limits: make(map[string]int, len(limits)),
+			queued: make(map[string]int),
+		}
+		for eventType, limit := range limits {
+			if limit > 0 {
+				l.limits[eventType] = limit
+			}
+		}
+		s.eventLimits = l
+	}
+}
+
+type eventTypeLimits struct {
+	limits map[string]int
+
+	mu     sync.Mutex
+	queued map[string]int
+}
+
+// admit reserves a queue slot for a dispatch of the event type and returns
+// false if the type has no slots left.
+func (l *eventTypeLimits) admit(eventType string) bool {
+	if l == nil {
+		return true
+	}
+
+	limit, ok := l.limits[eventType]
+	if !ok {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.queued[eventType] >= limit {
+		return false
+	}
+	l.queued[eventType]++
+	return true
+}
+
+// release frees the slot reserved by admit when the handler starts or when
+// the dispatch is not queued.
+func (l *eventTypeLimits) release(eventType string) {
+	if l == nil {
+		return
+	}
+	if _, ok := l.limits[eventType]; !ok {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.queued[eventType] <= 1 {
+		delete(l.queued, eventType)
+	} else {
+		l.queued[eventType]--
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEventTypeQueueLimits(t *testing.T) {
+	h := &AsyncHandler{Block: make(chan struct{}), Called: make(chan bool, 10)}
+	s := QueueAsyncScheduler(10, 1, WithEventTypeQueueLimits(map[string]int{"status": 2}))
+
+	schedule := func(eventType string) error {
+		return s.Schedule(context.Background(), Dispatch{Handler: h, EventType: eventType})
+	}
+
+	// occupy the worker so later dispatches stay in the queue
+	if err := schedule("pull_request"); err != nil {
+		t.Fatalf("unexpected error scheduling dispatch: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		if err := schedule("status"); err != nil {
+			t.Fatalf("unexpected error scheduling status dispatch: %v", err)
+		}
+	}
+	if err := schedule("status"); err != ErrCapacityExceeded {
+		t.Errorf("expected ErrCapacityExceeded, but got: %v", err)
+	}
+	if err := schedule("pull_request"); err != nil {
+		t.Errorf("unexpected error scheduling dispatch for another type: %v", err)
+	}
+
+	close(h.Block)
+	if err := s.(DrainScheduler).Drain(context.Background()); err != nil {
+		t.Fatalf("unexpected error draining scheduler: %v", err)
+	}
+	if n := len(h.Called); n != 4 {
+		t.Errorf("expected 4 handled dispatches, but got %d", n)
+	}
+
+	l := s.(*queueScheduler).eventLimits
+	if n := l.queued["status"]; n != 0 {
+		t.Errorf("expected no queued status dispatches after drain, but got %d", n)
+	}
+}
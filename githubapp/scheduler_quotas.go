@@ -0,0 +1,321 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"sync"
+)
+
+// InstallationQuota limits the dispatches a queue scheduler holds for one
+// installation and sets the capacity reserved for it. Zero values are
+// unlimited or, for reservations, reserve nothing.
+type InstallationQuota struct {
+	// MaxQueued is the maximum number of dispatches for the installation
+	// that wait for a worker. The scheduler rejects new dispatches for the
+	// installation with ErrCapacityExceeded when this many are waiting.
+	MaxQueued int `yaml:"max_queued" json:"maxQueued"`
+
+	// MaxConcurrent is the maximum number of handlers for the installation
+	// that run at the same time. Other dispatches for the installation wait,
+	// without occupying a worker, until a running handler finishes.
+	MaxConcurrent int `yaml:"max_concurrent" json:"maxConcurrent"`
+
+	// ReservedQueued is the number of queue slots reserved for the
+	// installation. Other installations cannot use these slots, so the
+	// installation can queue this many dispatches even if the rest of the
+	// queue is full. It is only used in overrides.
+	ReservedQueued int `yaml:"reserved_queued" json:"reservedQueued"`
+
+	// ReservedConcurrent is the number of workers reserved for the
+	// installation. Other installations cannot use these workers, so this
+	// many handlers for the installation can start even if all other
+	// workers are busy. It is only used in overrides.
+	ReservedConcurrent int `yaml:"reserved_concurrent" json:"reservedConcurrent"`
+
+	// Weight is the share of the unreserved workers that the installation
+	// receives, relative to the weights of other installations, when
+	// dispatches for several installations wait for a worker. If it is zero,
+	// the weight is 1.
+	Weight int `yaml:"weight" json:"weight"`
+}
+
+// WithInstallationQuotas limits the share of a QueueAsyncScheduler that
+// each installation can use, so a busy installation cannot fill the queue or
+// occupy all workers while other installations wait. The defaults apply to
+// all installations without an entry in overrides. Use overrides to give
+// critical or paying installations more capacity, or to restrict known noisy
+// installations. Dispatches without an installation are not limited, but
+// only use unreserved capacity.
+//
+// Overrides can reserve queue slots and workers for an installation, which
+// guarantees it capacity that other installations cannot use, and can set a
+// weight, which gives the installation a larger share of the unreserved
+// workers when they are contended. When a worker finishes a handler, it
+// starts the waiting dispatch of the installation that uses the fewest
+// workers relative to its weight. The reservations of all overrides must
+// leave at least one unreserved worker; QueueAsyncScheduler panics if they
+// do not, or if they exceed the queue size.
+//
+// If MaxConcurrent is set and MaxQueued is not, MaxQueued is the larger of
+// the queue size and the number of workers, which bounds the dispatches
+// waiting for their installation's handlers to finish.
+//
+// This option has no effect on other schedulers.
+func WithInstallationQuotas(defaults InstallationQuota, overrides map[int64]InstallationQuota) SchedulerOption {
+	return func(s *scheduler) {
+		s.quotas = &installationQuotas{
+			defaults:  defaults,
+			overrides: overrides,
+			queued:    make(map[int64]int),
+			running:   make(map[int64]int),
+			waiting:   make(map[int64][]queueDispatch),
+		}
+	}
+}
+
+type installationQuotas struct {
+	defaults  InstallationQuota
+	overrides map[int64]InstallationQuota
+
+	// maxWaiting is the implicit MaxQueued for quotas that only set
+	// MaxConcurrent
+	maxWaiting int
+
+	// sharedQueue and sharedWorkers are the unreserved queue slots and
+	// workers; reserved is true if any override reserves queue slots
+	sharedQueue   int
+	sharedWorkers int
+	reserved      bool
+
+	mu sync.Mutex
+
+	// queued counts dispatches in the queue or in waiting; running counts
+	// running handlers. The shared counts are the dispatches that use
+	// unreserved capacity because their installation used its reservation.
+	queued        map[int64]int
+	running       map[int64]int
+	sharedQueued  int
+	sharedRunning int
+
+	// waiting holds dispatches taken from the queue while their installation
+	// was at its concurrency limit or all unreserved workers were busy
+	waiting      map[int64][]queueDispatch
+	waitingTotal int
+}
+
+// init sets the limits that depend on the size of the scheduler. It panics
+// if the reservations exceed the scheduler's capacity.
+func (q *installationQuotas) init(queueSize, workers int) {
+	q.maxWaiting = max(queueSize, workers)
+
+	var reservedQueued, reservedWorkers int
+	for _, quota := range q.overrides {
+		reservedQueued += quota.ReservedQueued
+		reservedWorkers += quota.ReservedConcurrent
+	}
+	if reservedQueued > queueSize {
+		panic("QueueAsyncScheduler: reserved queue slots exceed the queue size")
+	}
+	if reservedWorkers >= workers {
+		panic("QueueAsyncScheduler: reserved workers must leave at least one unreserved worker")
+	}
+
+	q.sharedQueue = queueSize - reservedQueued
+	q.sharedWorkers = workers - reservedWorkers
+	q.reserved = reservedQueued > 0
+}
+
+func (q *installationQuotas) quota(id int64) InstallationQuota {
+	if id == 0 {
+		return InstallationQuota{}
+	}
+
+	quota, ok := q.overrides[id]
+	if !ok {
+		quota = q.defaults
+		quota.ReservedQueued = 0
+		quota.ReservedConcurrent = 0
+	}
+	if quota.MaxQueued == 0 && quota.MaxConcurrent > 0 {
+		quota.MaxQueued = q.maxWaiting
+	}
+	if quota.Weight <= 0 {
+		quota.Weight = 1
+	}
+	return quota
+}
+
+// admit reserves a queue slot for a dispatch for the installation and
+// returns false if the installation has no slots left.
+func (q *installationQuotas) admit(id int64) bool {
+	if q == nil {
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	quota := q.quota(id)
+	if limit := quota.MaxQueued; limit > 0 && q.queued[id] >= limit {
+		return false
+	}
+	if q.reserved && q.queued[id] >= quota.ReservedQueued && q.sharedQueued >= q.sharedQueue {
+		return false
+	}
+	q.addQueued(id, quota, 1)
+	return true
+}
+
+// cancel releases a slot reserved by admit for a dispatch that was not
+// queued.
+func (q *installationQuotas) cancel(id int64) {
+	if q == nil {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.addQueued(id, q.quota(id), -1)
+}
+
+// start is called when a worker takes a dispatch from the queue. It returns
+// false if the installation is at its concurrency limit or all workers it
+// can use are busy, in which case the dispatch waits and runs when a handler
+// finishes.
+func (q *installationQuotas) start(d queueDispatch) bool {
+	if q == nil {
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	id := d.installationID
+	quota := q.quota(id)
+	if !q.canStart(id, quota) {
+		q.waiting[id] = append(q.waiting[id], d)
+		q.waitingTotal++
+		return false
+	}
+	q.addQueued(id, quota, -1)
+	q.addRunning(id, quota, 1)
+	return true
+}
+
+// finish is called when a handler for the installation returns. If a
+// waiting dispatch can start in its place, it returns the dispatch, which
+// the caller must run in place of the finished one.
+func (q *installationQuotas) finish(id int64) (queueDispatch, bool) {
+	if q == nil {
+		return queueDispatch{}, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.addRunning(id, q.quota(id), -1)
+
+	next, ok := q.nextWaiting()
+	if !ok {
+		return queueDispatch{}, false
+	}
+
+	waiting := q.waiting[next]
+	d := waiting[0]
+	if len(waiting) == 1 {
+		delete(q.waiting, next)
+	} else {
+		q.waiting[next] = waiting[1:]
+	}
+	q.waitingTotal--
+
+	quota := q.quota(next)
+	q.addQueued(next, quota, -1)
+	q.addRunning(next, quota, 1)
+	return d, true
+}
+
+// nextWaiting returns the installation whose waiting dispatch starts next.
+// Of the installations that can start a dispatch, it selects the one that
+// runs the fewest handlers relative to its weight and then the one whose
+// dispatch was queued first.
+func (q *installationQuotas) nextWaiting() (int64, bool) {
+	var next int64
+	var nextQuota InstallationQuota
+	found := false
+
+	for id, waiting := range q.waiting {
+		quota := q.quota(id)
+		if !q.canStart(id, quota) {
+			continue
+		}
+		if found {
+			// compare running/weight without division
+			share, nextShare := q.running[id]*nextQuota.Weight, q.running[next]*quota.Weight
+			if share > nextShare || (share == nextShare && !waiting[0].t.Before(q.waiting[next][0].t)) {
+				continue
+			}
+		}
+		next, nextQuota, found = id, quota, true
+	}
+	return next, found
+}
+
+// canStart returns true if a handler for the installation can start without
+// exceeding its concurrency limit or the unreserved workers.
+func (q *installationQuotas) canStart(id int64, quota InstallationQuota) bool {
+	if limit := quota.MaxConcurrent; limit > 0 && q.running[id] >= limit {
+		return false
+	}
+	return q.running[id] < quota.ReservedConcurrent || q.sharedRunning < q.sharedWorkers
+}
+
+// waitingCount returns the number of dispatches waiting for their
+// installation's handlers to finish.
+func (q *installationQuotas) waitingCount() int {
+	if q == nil {
+		return 0
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.waitingTotal
+}
+
+// addQueued and addRunning change the count for an installation by delta,
+// which is 1 or -1, and update the shared count if the change is beyond the
+// installation's reservation.
+func (q *installationQuotas) addQueued(id int64, quota InstallationQuota, delta int) {
+	q.sharedQueued += sharedDelta(q.queued, id, quota.ReservedQueued, delta)
+}
+
+func (q *installationQuotas) addRunning(id int64, quota InstallationQuota, delta int) {
+	q.sharedRunning += sharedDelta(q.running, id, quota.ReservedConcurrent, delta)
+}
+
+func sharedDelta(counts map[int64]int, id int64, reserved int, delta int) int {
+	before := counts[id]
+	after := max(before+delta, 0)
+	if after == 0 {
+		delete(counts, id)
+	} else {
+		counts[id] = after
+	}
+
+	if max(before, after) > reserved {
+		return after - before
+	}
+	return 0
+}
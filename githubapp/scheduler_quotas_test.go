@@ -0,0 +1,233 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+type quotaHandler struct {
+	block chan struct{}
+
+	mu      sync.Mutex
+	running map[int64]int
+	peak    map[int64]int
+	handled int
+}
+
+func (h *quotaHandler) Handles() []string { return []string{"push"} }
+
+func (h *quotaHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	e, _ := PeekEnvelope(payload)
+
+	h.mu.Lock()
+	h.running[e.InstallationID]++
+	h.peak[e.InstallationID] = max(h.peak[e.InstallationID], h.running[e.InstallationID])
+	h.mu.Unlock()
+
+	<-h.block
+
+	h.mu.Lock()
+	h.running[e.InstallationID]--
+	h.handled++
+	h.mu.Unlock()
+	return nil
+}
+
+func quotaDispatch(h EventHandler, installationID int64) Dispatch {
+	return Dispatch{
+		Handler:   h,
+		EventType: "push",
+		Payload:   []byte(fmt.Sprintf(`{"installation": {"id": %d}}`, installationID)),
+	}
+}
+
+func TestInstallationQuotas(t *testing.T) {
+	t.Run("maxConcurrent", func(t *testing.T) {
+		h := &quotaHandler{block: make(chan struct{}), running: make(map[int64]int), peak: make(map[int64]int)}
+		s := QueueAsyncScheduler(10, 4, WithInstallationQuotas(
+			InstallationQuota{MaxConcurrent: 1},
+			map[int64]InstallationQuota{2: {MaxConcurrent: 2}},
+		))
+
+		for _, id := range []int64{1, 1, 1, 2, 2, 2} {
+			if err := s.Schedule(context.Background(), quotaDispatch(h, id)); err != nil {
+				t.Fatalf("unexpected error scheduling dispatch: %v", err)
+			}
+		}
+
+		// wait for the workers to take the dispatches from the queue
+		time.Sleep(50 * time.Millisecond)
+		if stats := s.(StatsScheduler).Stats(); stats.ActiveWorkers != 3 || stats.QueueLength != 3 {
+			t.Errorf("incorrect stats with blocked handlers: %+v", stats)
+		}
+
+		close(h.block)
+		if err := s.(DrainScheduler).Drain(context.Background()); err != nil {
+			t.Fatalf("unexpected error draining scheduler: %v", err)
+		}
+
+		if h.handled != 6 {
+			t.Errorf("expected 6 handled dispatches, but got %d", h.handled)
+		}
+		if h.peak[1] != 1 || h.peak[2] != 2 {
+			t.Errorf("incorrect peak concurrency: %v", h.peak)
+		}
+	})
+
+	t.Run("maxQueued", func(t *testing.T) {
+		h := &quotaHandler{block: make(chan struct{}), running: make(map[int64]int), peak: make(map[int64]int)}
+		defer close(h.block)
+
+		s := QueueAsyncScheduler(10, 1, WithInstallationQuotas(InstallationQuota{MaxQueued: 2}, nil))
+
+		// the first dispatch leaves the queue when the worker starts it
+		if err := s.Schedule(context.Background(), quotaDispatch(h, 1)); err != nil {
+			t.Fatalf("unexpected error scheduling dispatch: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		for i := 0; i < 2; i++ {
+			if err := s.Schedule(context.Background(), quotaDispatch(h, 1)); err != nil {
+				t.Fatalf("unexpected error scheduling dispatch: %v", err)
+			}
+		}
+		if err := s.Schedule(context.Background(), quotaDispatch(h, 1)); err != ErrCapacityExceeded {
+			t.Errorf("expected ErrCapacityExceeded, but got: %v", err)
+		}
+		if err := s.Schedule(context.Background(), quotaDispatch(h, 2)); err != nil {
+			t.Errorf("unexpected error scheduling dispatch for another installation: %v", err)
+		}
+	})
+
+	t.Run("reservedQueued", func(t *testing.T) {
+		h := &quotaHandler{block: make(chan struct{}), running: make(map[int64]int), peak: make(map[int64]int)}
+		defer close(h.block)
+
+		s := QueueAsyncScheduler(3, 1, WithInstallationQuotas(InstallationQuota{}, map[int64]InstallationQuota{
+			2: {ReservedQueued: 1},
+		}))
+
+		if err := s.Schedule(context.Background(), quotaDispatch(h, 1)); err != nil {
+			t.Fatalf("unexpected error scheduling dispatch: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		// the queue has 3 slots and 1 is reserved for installation 2
+		for i := 0; i < 2; i++ {
+			if err := s.Schedule(context.Background(), quotaDispatch(h, 1)); err != nil {
+				t.Fatalf("unexpected error scheduling dispatch: %v", err)
+			}
+		}
+		if err := s.Schedule(context.Background(), quotaDispatch(h, 1)); err != ErrCapacityExceeded {
+			t.Errorf("expected ErrCapacityExceeded, but got: %v", err)
+		}
+		if err := s.Schedule(context.Background(), quotaDispatch(h, 2)); err != nil {
+			t.Errorf("unexpected error scheduling dispatch with reserved capacity: %v", err)
+		}
+	})
+
+	t.Run("reservedConcurrent", func(t *testing.T) {
+		h := &quotaHandler{block: make(chan struct{}), running: make(map[int64]int), peak: make(map[int64]int)}
+		s := QueueAsyncScheduler(10, 3, WithInstallationQuotas(InstallationQuota{}, map[int64]InstallationQuota{
+			2: {ReservedConcurrent: 1},
+		}))
+
+		for _, id := range []int64{1, 1, 1, 1, 2} {
+			if err := s.Schedule(context.Background(), quotaDispatch(h, id)); err != nil {
+				t.Fatalf("unexpected error scheduling dispatch: %v", err)
+			}
+		}
+
+		// wait for the workers to take the dispatches from the queue
+		time.Sleep(50 * time.Millisecond)
+		h.mu.Lock()
+		running := fmt.Sprint(h.running)
+		h.mu.Unlock()
+		if running != "map[1:2 2:1]" {
+			t.Errorf("expected reserved worker to run installation 2, but running handlers are %s", running)
+		}
+
+		close(h.block)
+		if err := s.(DrainScheduler).Drain(context.Background()); err != nil {
+			t.Fatalf("unexpected error draining scheduler: %v", err)
+		}
+		if h.handled != 5 {
+			t.Errorf("expected 5 handled dispatches, but got %d", h.handled)
+		}
+		if h.peak[1] != 2 {
+			t.Errorf("expected installation 1 to use only unreserved workers, but peak was %d", h.peak[1])
+		}
+	})
+
+	t.Run("invalidReservations", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic when reservations leave no unreserved workers")
+			}
+		}()
+		QueueAsyncScheduler(10, 2, WithInstallationQuotas(InstallationQuota{}, map[int64]InstallationQuota{
+			2: {ReservedConcurrent: 1},
+			3: {ReservedConcurrent: 1},
+		}))
+	})
+}
+
+func TestInstallationQuotasWeight(t *testing.T) {
+	tests := map[string]struct {
+		Weight int
+		Next   int64
+	}{
+		"equalWeights": {Weight: 0, Next: 1},
+		"weighted":     {Weight: 3, Next: 2},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var s scheduler
+			WithInstallationQuotas(InstallationQuota{}, map[int64]InstallationQuota{
+				2: {Weight: test.Weight},
+			})(&s)
+			q := s.quotas
+			q.init(10, 3)
+
+			start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			for i, id := range []int64{1, 1, 2, 1, 2} {
+				d := queueDispatch{t: start.Add(time.Duration(i) * time.Second), installationID: id}
+				if !q.admit(id) {
+					t.Fatalf("dispatch %d was not admitted", i)
+				}
+				// the first three dispatches use all workers, the others wait
+				if started := q.start(d); started != (i < 3) {
+					t.Fatalf("incorrect start result for dispatch %d: %t", i, started)
+				}
+			}
+
+			// both installations now run one handler and have a waiting
+			// dispatch; installation 1's dispatch was queued first
+			next, ok := q.finish(1)
+			if !ok {
+				t.Fatal("expected a waiting dispatch to start")
+			}
+			if next.installationID != test.Next {
+				t.Errorf("expected installation %d to start next, but got %d", test.Next, next.installationID)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// MetricsKeySpilledPayloads counts the payloads written to the blob store
+	// by WithPayloadSpilling when WithSchedulingMetrics is set.
+	MetricsKeySpilledPayloads = "github.event.spilled"
+)
+
+// BlobStore stores blobs that are read and then deleted, like payloads
+// spilled by a scheduler.
+type BlobStore interface {
+	BlobSink
+	BlobSource
+	Delete(ctx context.Context, key string) error
+}
+
+// NewDirectoryBlobStore returns a BlobStore that stores blobs as files in
+// dir, creating subdirectories as needed.
+func NewDirectoryBlobStore(dir string) BlobStore {
+	return &directoryBlobSink{dir: dir}
+}
+
+// WithPayloadSpilling makes a QueueAsyncScheduler write payloads larger than
+// threshold bytes to store while their dispatches wait in the queue, and
+// read them back when a worker executes the handler. This keeps the memory
+// used by the queue bounded when a burst of large events, like "push" events
+// with many commits, arrives faster than handlers run. Spilled payloads are
+// deleted after the handler returns or if the dispatch is dropped.
+//
+// If writing a payload fails, the dispatch keeps its payload in memory. If
+// reading it fails, the handler does not run and the error is reported to
+// the error callback. When WithSchedulingMetrics is set, the scheduler counts
+// spilled payloads in the "github.event.spilled" metric.
+//
+// Context derivers that copy the delivery from the request context keep the
+// payload in memory and defeat spilling; DefaultContextDeriver does not.
+// This option has no effect on other schedulers.
+func WithPayloadSpilling(store BlobStore, threshold int) SchedulerOption {
+	return func(s *scheduler) {
+		if store != nil {
+			s.spill = &payloadSpill{store: store, threshold: threshold}
+		}
+	}
+}
+
+type payloadSpill struct {
+	store     BlobStore
+	threshold int
+	seq       atomic.Uint64
+}
+
+// put writes the payload of a queued dispatch to the store if it is larger
+// than the threshold.
+func (p *payloadSpill) put(qd *queueDispatch, registry metrics.Registry) {
+	if p == nil || len(qd.d.Payload) <= p.threshold {
+		return
+	}
+
+	key := fmt.Sprintf("spill/%d-%s", p.seq.Add(1), url.PathEscape(qd.d.DeliveryID))
+	if err := p.store.Put(qd.ctx, key, qd.d.Payload); err != nil {
+		zerolog.Ctx(qd.ctx).Warn().Err(err).Msg("Failed to spill payload, keeping it in memory")
+		return
+	}
+	if registry != nil {
+		metrics.GetOrRegisterCounter(MetricsKeySpilledPayloads, registry).Inc(1)
+	}
+
+	qd.spillKey = key
+	qd.d.Payload = nil
+}
+
+// load reads the payload of a queued dispatch if it was spilled.
+func (p *payloadSpill) load(qd *queueDispatch) error {
+	if p == nil || qd.spillKey == "" {
+		return nil
+	}
+
+	payload, err := p.store.Get(qd.ctx, qd.spillKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load spilled payload of %s event %s", qd.d.EventType, qd.d.DeliveryID)
+	}
+	qd.d.Payload = payload
+	return nil
+}
+
+// discard deletes the payload of a queued dispatch if it was spilled.
+func (p *payloadSpill) discard(qd queueDispatch) {
+	if p == nil || qd.spillKey == "" {
+		return
+	}
+	if err := p.store.Delete(context.WithoutCancel(qd.ctx), qd.spillKey); err != nil {
+		zerolog.Ctx(qd.ctx).Warn().Err(err).Msg("Failed to delete spilled payload")
+	}
+}
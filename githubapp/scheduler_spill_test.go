@@ -0,0 +1,121 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rcrowley/go-metrics"
+)
+
+type failingBlobStore struct {
+	BlobStore
+}
+
+func (s failingBlobStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, errors.New("blob store unavailable")
+}
+
+func TestPayloadSpilling(t *testing.T) {
+	dir := t.TempDir()
+	block := make(chan struct{})
+
+	var mu sync.Mutex
+	var handled []string
+	h := &TestEventHandler{
+		Types: []string{"push"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			<-block
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, string(payload))
+			return nil
+		},
+	}
+
+	registry := metrics.NewRegistry()
+	s := QueueAsyncScheduler(10, 1, WithPayloadSpilling(NewDirectoryBlobStore(dir), 16), WithSchedulingMetrics(registry))
+
+	large := `{"commits": [` + strings.Repeat(`{"id": "a"},`, 10) + `{"id": "b"}]}`
+	for _, payload := range []string{`{}`, large, `{"small": true}`} {
+		d := Dispatch{Handler: h, EventType: "push", DeliveryID: "delivery", Payload: []byte(payload)}
+		if err := s.Schedule(context.Background(), d); err != nil {
+			t.Fatalf("unexpected error scheduling dispatch: %v", err)
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	spilled, _ := os.ReadDir(dir + "/spill")
+	if len(spilled) != 1 {
+		t.Fatalf("expected 1 spilled payload while queued, but got %d", len(spilled))
+	}
+	if n := registry.Get(MetricsKeySpilledPayloads).(metrics.Counter).Count(); n != 1 {
+		t.Errorf("expected spilled payload count of 1, but got %d", n)
+	}
+
+	close(block)
+	if err := s.(DrainScheduler).Drain(context.Background()); err != nil {
+		t.Fatalf("unexpected error draining scheduler: %v", err)
+	}
+
+	if len(handled) != 3 || handled[1] != large {
+		t.Errorf("incorrect handled payloads: %v", handled)
+	}
+	if spilled, _ := os.ReadDir(dir + "/spill"); len(spilled) != 0 {
+		t.Errorf("expected spilled payloads to be deleted, but found %d", len(spilled))
+	}
+}
+
+func TestPayloadSpillingLoadError(t *testing.T) {
+	dir := t.TempDir()
+	store := failingBlobStore{BlobStore: NewDirectoryBlobStore(dir)}
+
+	h := &AsyncHandler{Called: make(chan bool, 10)}
+	errs := make(chan error, 10)
+	s := QueueAsyncScheduler(10, 1,
+		WithPayloadSpilling(store, 0),
+		WithAsyncErrorCallback(func(ctx context.Context, d Dispatch, err error) {
+			errs <- err
+		}),
+	)
+
+	if err := s.Schedule(context.Background(), Dispatch{Handler: h, EventType: "push", DeliveryID: "lost", Payload: []byte(`{}`)}); err != nil {
+		t.Fatalf("unexpected error scheduling dispatch: %v", err)
+	}
+	if err := s.(DrainScheduler).Drain(context.Background()); err != nil {
+		t.Fatalf("unexpected error draining scheduler: %v", err)
+	}
+
+	if len(h.Called) != 0 {
+		t.Error("handler ran without its payload")
+	}
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "spilled payload") {
+			t.Errorf("incorrect error: %v", err)
+		}
+	default:
+		t.Error("expected error callback for payload that failed to load")
+	}
+	if spilled, _ := os.ReadDir(dir + "/spill"); len(spilled) != 0 {
+		t.Errorf("expected spilled payload to be deleted, but found %d", len(spilled))
+	}
+}
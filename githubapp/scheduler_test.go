@@ -16,6 +16,8 @@ package githubapp
 
 import (
 	"context"
+	"reflect"
+	"runtime/pprof"
 	"testing"
 	"time"
 
@@ -154,4 +156,87 @@ func TestQueueAsyncScheduler(t *testing.T) {
 			t.Fatalf("expected ErrCapacityExceeded, but got: %v", err)
 		}
 	})
+	t.Run("tracer", func(t *testing.T) {
+		span := &testSpan{ended: make(chan error, 1)}
+		tracer := func(ctx context.Context, d Dispatch) (context.Context, DispatchSpan) {
+			return context.WithValue(ctx, testSpanKey{}, span), span
+		}
+
+		errc := make(chan error, 1)
+		cb := func(ctx context.Context, d Dispatch, err error) {
+			if ctx.Value(testSpanKey{}) != span {
+				err = errors.New("handler context does not contain the span")
+			}
+			errc <- err
+		}
+
+		s := QueueAsyncScheduler(1, 1, WithDispatchTracer(tracer), WithAsyncErrorCallback(cb))
+		h := AsyncHandler{Called: make(chan bool, 1), Error: errors.New("handler error")}
+
+		if err := s.Schedule(context.Background(), Dispatch{
+			Handler: &h,
+		}); err != nil {
+			t.Fatalf("unexpected error scheduling dispatch: %v", err)
+		}
+
+		var spanErr error
+		select {
+		case spanErr = <-span.ended:
+		case <-time.After(timeout):
+			t.Fatalf("span did not end after %v", timeout)
+		}
+
+		if !span.executing {
+			t.Error("span was not marked as executing")
+		}
+		if spanErr != h.Error {
+			t.Errorf("incorrect span error: %v", spanErr)
+		}
+		if err := <-errc; err != h.Error {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	executing bool
+	ended     chan error
+}
+
+func (s *testSpan) Executing()    { s.executing = true }
+func (s *testSpan) End(err error) { s.ended <- err }
+
+func TestDispatchExecuteLabels(t *testing.T) {
+	var labels map[string]string
+	h := &TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			labels = make(map[string]string)
+			pprof.ForLabels(ctx, func(key, value string) bool {
+				labels[key] = value
+				return true
+			})
+			return nil
+		},
+	}
+
+	d := Dispatch{
+		Handler:    h,
+		EventType:  "pull_request",
+		DeliveryID: "delivery-id",
+		Payload:    []byte(`{"action": "opened", "installation": {"id": 123}}`),
+	}
+	if err := d.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		LogKeyEventType:      "pull_request",
+		LogKeyInstallationID: "123",
+	}
+	if !reflect.DeepEqual(expected, labels) {
+		t.Errorf("incorrect labels\nexpected: %v\n  actual: %v", expected, labels)
+	}
 }
@@ -0,0 +1,276 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxSearchResults is the largest number of results GitHub returns for a
+	// search query, regardless of the total number of matches.
+	MaxSearchResults = 1000
+
+	// DefaultSearchInterval is the default time between requests made with
+	// a SearchThrottle. The search API allows 30 requests per minute for
+	// authenticated clients.
+	DefaultSearchInterval = 2 * time.Second
+
+	// DefaultCodeSearchInterval is the time between code search requests
+	// when SearchCode is called without a throttle. The code search API
+	// allows 10 requests per minute.
+	DefaultCodeSearchInterval = 6 * time.Second
+
+	searchPageSize = 100
+)
+
+var (
+	// defaultSearchThrottle and defaultCodeSearchThrottle space requests
+	// made by the search helpers when the options do not set a throttle
+	defaultSearchThrottle     = NewSearchThrottle(DefaultSearchInterval)
+	defaultCodeSearchThrottle = NewSearchThrottle(DefaultCodeSearchInterval)
+)
+
+// SearchThrottle spaces search requests to stay within the search rate
+// limit, which is much smaller than the core rate limit. GitHub also applies
+// secondary rate limits to bursts of search requests, so applications should
+// share a single throttle between all handlers that use the search helpers.
+// A throttle is safe for concurrent use.
+//
+// When a response reports that the search rate limit is exhausted, the
+// throttle delays all requests until the limit resets.
+type SearchThrottle struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewSearchThrottle creates a throttle that allows one request every
+// interval. If interval is not positive, it uses DefaultSearchInterval.
+func NewSearchThrottle(interval time.Duration) *SearchThrottle {
+	if interval <= 0 {
+		interval = DefaultSearchInterval
+	}
+	return &SearchThrottle{interval: interval}
+}
+
+// Wait blocks until the throttle allows another request or the context is
+// canceled. A nil throttle never blocks.
+func (t *SearchThrottle) Wait(ctx context.Context) error {
+	if t == nil {
+		return ctx.Err()
+	}
+
+	now := GetClock(ctx).Now()
+
+	t.mu.Lock()
+	at := now
+	if at.Before(t.next) {
+		at = t.next
+	}
+	t.next = at.Add(t.interval)
+	t.mu.Unlock()
+
+	return sleepContext(ctx, at.Sub(now))
+}
+
+func (t *SearchThrottle) update(res *github.Response) {
+	if t == nil || res == nil || res.Rate.Limit == 0 || res.Rate.Remaining > 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if reset := res.Rate.Reset.Time; reset.After(t.next) {
+		t.next = reset
+	}
+}
+
+// SearchOptions configures the search helpers.
+type SearchOptions struct {
+	// Sort and Order set the sort order of results. If empty, results are
+	// sorted by best match.
+	Sort  string
+	Order string
+
+	// MaxResults is the largest number of results to return. If it is zero
+	// or larger than MaxSearchResults, the helpers return up to
+	// MaxSearchResults.
+	MaxResults int
+
+	// Throttle delays requests to stay within the search rate limit. If
+	// nil, the helpers share a default throttle for each process that
+	// allows one request every DefaultSearchInterval, or every
+	// DefaultCodeSearchInterval for SearchCode.
+	Throttle *SearchThrottle
+}
+
+// SearchMetadata describes the results of a search.
+type SearchMetadata struct {
+	// Total is the number of matches reported by GitHub. It may be larger
+	// than the number of returned results.
+	Total int
+
+	// Incomplete is true if GitHub reported that the search timed out before
+	// finding all matches. Incomplete results may be missing matches.
+	Incomplete bool
+}
+
+// IssueSearchResult contains the issues and pull requests returned by
+// SearchIssues.
+type IssueSearchResult struct {
+	SearchMetadata
+	Issues []*github.Issue
+}
+
+// CodeSearchResult contains the files returned by SearchCode.
+type CodeSearchResult struct {
+	SearchMetadata
+	Code []*github.CodeResult
+}
+
+// CommitSearchResult contains the commits returned by SearchCommits.
+type CommitSearchResult struct {
+	SearchMetadata
+	Commits []*github.CommitResult
+}
+
+// SearchIssues returns the issues and pull requests matching query, reading
+// all pages up to the maximum number of results in opts. Handlers should use
+// search sparingly: bursts of search requests are a common cause of
+// secondary rate limits. Requests are spaced by the throttle in opts or a
+// default throttle.
+func SearchIssues(ctx context.Context, client *github.Client, query string, opts *SearchOptions) (*IssueSearchResult, error) {
+	var result IssueSearchResult
+	limit, err := search(ctx, opts, defaultSearchThrottle, func(searchOpts *github.SearchOptions) (int, *github.Response, error) {
+		page, res, err := client.Search.Issues(ctx, query, searchOpts)
+		if err != nil {
+			return 0, res, err
+		}
+		result.Issues = append(result.Issues, page.Issues...)
+		result.update(page.GetTotal(), page.GetIncompleteResults())
+		return len(page.Issues), res, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search issues for %q", query)
+	}
+	if len(result.Issues) > limit {
+		result.Issues = result.Issues[:limit]
+	}
+	return &result, nil
+}
+
+// SearchCode returns the files matching query, reading all pages up to the
+// maximum number of results in opts. Code search has a smaller rate limit
+// than other searches; see SearchIssues for recommendations.
+func SearchCode(ctx context.Context, client *github.Client, query string, opts *SearchOptions) (*CodeSearchResult, error) {
+	var result CodeSearchResult
+	limit, err := search(ctx, opts, defaultCodeSearchThrottle, func(searchOpts *github.SearchOptions) (int, *github.Response, error) {
+		page, res, err := client.Search.Code(ctx, query, searchOpts)
+		if err != nil {
+			return 0, res, err
+		}
+		result.Code = append(result.Code, page.CodeResults...)
+		result.update(page.GetTotal(), page.GetIncompleteResults())
+		return len(page.CodeResults), res, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search code for %q", query)
+	}
+	if len(result.Code) > limit {
+		result.Code = result.Code[:limit]
+	}
+	return &result, nil
+}
+
+// SearchCommits returns the commits matching query, reading all pages up to
+// the maximum number of results in opts. See SearchIssues for
+// recommendations.
+func SearchCommits(ctx context.Context, client *github.Client, query string, opts *SearchOptions) (*CommitSearchResult, error) {
+	var result CommitSearchResult
+	limit, err := search(ctx, opts, defaultSearchThrottle, func(searchOpts *github.SearchOptions) (int, *github.Response, error) {
+		page, res, err := client.Search.Commits(ctx, query, searchOpts)
+		if err != nil {
+			return 0, res, err
+		}
+		result.Commits = append(result.Commits, page.Commits...)
+		result.update(page.GetTotal(), page.GetIncompleteResults())
+		return len(page.Commits), res, nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to search commits for %q", query)
+	}
+	if len(result.Commits) > limit {
+		result.Commits = result.Commits[:limit]
+	}
+	return &result, nil
+}
+
+func (m *SearchMetadata) update(total int, incomplete bool) {
+	m.Total = total
+	m.Incomplete = m.Incomplete || incomplete
+}
+
+// search reads pages with fetch until there are no more pages or it reads
+// the maximum number of results. fetch returns the number of results in the
+// page. search returns the maximum number of results, which callers use to
+// trim the last page. Requests wait for defaultThrottle if opts does not set
+// a throttle.
+func search(ctx context.Context, opts *SearchOptions, defaultThrottle *SearchThrottle, fetch func(*github.SearchOptions) (int, *github.Response, error)) (int, error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	throttle := opts.Throttle
+	if throttle == nil {
+		throttle = defaultThrottle
+	}
+
+	maxResults := opts.MaxResults
+	if maxResults <= 0 || maxResults > MaxSearchResults {
+		maxResults = MaxSearchResults
+	}
+
+	searchOpts := &github.SearchOptions{
+		Sort:        opts.Sort,
+		Order:       opts.Order,
+		ListOptions: github.ListOptions{PerPage: min(maxResults, searchPageSize)},
+	}
+
+	count := 0
+	for {
+		if err := throttle.Wait(ctx); err != nil {
+			return maxResults, err
+		}
+
+		n, res, err := fetch(searchOpts)
+		throttle.update(res)
+		if err != nil {
+			return maxResults, ClassifyError(err)
+		}
+
+		count += n
+		if n == 0 || count >= maxResults || res.NextPage == 0 {
+			return maxResults, nil
+		}
+		searchOpts.Page = res.NextPage
+	}
+}
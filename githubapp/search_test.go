@@ -0,0 +1,152 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestSearchIssues(t *testing.T) {
+	var requests []string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search/issues", func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RawQuery)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+
+		// three pages of results, the last of which is incomplete
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, page+1))
+		}
+
+		var items []string
+		for i := 0; i < perPage; i++ {
+			items = append(items, fmt.Sprintf(`{"number": %d}`, (page-1)*perPage+i+1))
+		}
+		fmt.Fprintf(w, `{"total_count": %d, "incomplete_results": %t, "items": [%s]}`, 3*perPage, page == 3, strings.Join(items, ","))
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	tests := map[string]struct {
+		Options    *SearchOptions
+		Results    int
+		Requests   int
+		Incomplete bool
+	}{
+		"allPages": {
+			Options:    &SearchOptions{},
+			Results:    300,
+			Requests:   3,
+			Incomplete: true,
+		},
+		"maxResults": {
+			Options:  &SearchOptions{MaxResults: 15},
+			Results:  15,
+			Requests: 1,
+		},
+		"trimmedPage": {
+			Options:  &SearchOptions{MaxResults: 150, Sort: "created", Order: "asc"},
+			Results:  150,
+			Requests: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			requests = nil
+
+			// the test clock skips the delays of the default throttle
+			ctx := WithClock(context.Background(), newTestClock())
+			result, err := SearchIssues(ctx, cc.client, "is:open label:bug", test.Options)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(result.Issues) != test.Results {
+				t.Errorf("incorrect number of results: expected %d, actual %d", test.Results, len(result.Issues))
+			}
+			if len(requests) != test.Requests {
+				t.Errorf("incorrect number of requests: expected %d, actual %d: %q", test.Requests, len(requests), requests)
+			}
+			if result.Incomplete != test.Incomplete {
+				t.Errorf("incorrect incomplete flag: expected %t, actual %t", test.Incomplete, result.Incomplete)
+			}
+			if test.Options.Sort != "" && !strings.Contains(requests[0], "sort="+test.Options.Sort) {
+				t.Errorf("request did not include sort order: %s", requests[0])
+			}
+		})
+	}
+}
+
+func TestSearchThrottle(t *testing.T) {
+	t.Run("spacesRequests", func(t *testing.T) {
+		throttle := NewSearchThrottle(20 * time.Millisecond)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			if err := throttle.Wait(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+			t.Errorf("throttle did not delay requests: elapsed %s", elapsed)
+		}
+	})
+
+	t.Run("waitsForReset", func(t *testing.T) {
+		throttle := NewSearchThrottle(time.Millisecond)
+		throttle.update(&github.Response{Rate: github.Rate{
+			Limit:     30,
+			Remaining: 0,
+			Reset:     github.Timestamp{Time: time.Now().Add(time.Hour)},
+		}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := throttle.Wait(ctx); err == nil {
+			t.Fatal("expected error waiting for reset, but got nil")
+		}
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		if defaultSearchThrottle.interval != 2*time.Second {
+			t.Errorf("incorrect default search interval: %s", defaultSearchThrottle.interval)
+		}
+		if defaultCodeSearchThrottle.interval != 6*time.Second {
+			t.Errorf("incorrect default code search interval: %s", defaultCodeSearchThrottle.interval)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var throttle *SearchThrottle
+		if err := throttle.Wait(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
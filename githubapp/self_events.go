@@ -0,0 +1,106 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// EventFilter decides if a dispatcher should handle an event. It returns
+// false for events that the dispatcher should ignore.
+type EventFilter func(ctx context.Context, eventType string, payload []byte) bool
+
+// WithEventFilter sets a filter that the dispatcher checks for every valid
+// event before scheduling it. The dispatcher responds to ignored events as if
+// no handler exists for them.
+func WithEventFilter(filter EventFilter) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.filter = filter
+	}
+}
+
+type appObject struct {
+	ID int64 `json:"id"`
+}
+
+type appOwnedObject struct {
+	App                   *appObject `json:"app"`
+	PerformedViaGitHubApp *appObject `json:"performed_via_github_app"`
+}
+
+// IsSelfEvent returns true if the application with the given ID and slug
+// caused an event. This is true when the sender of the event is the
+// application's bot user or when the event reports that the application
+// created the check run, check suite, comment, issue, or review in the event.
+// Handlers that react to events by creating similar events should ignore self
+// events to avoid infinite loops.
+//
+// Check events requesting that the application take action, like the
+// "rerequested" and "requested_action" actions, are never self events, even
+// though they refer to check runs owned by the application.
+func IsSelfEvent(appID int64, slug string, payload []byte) bool {
+	var event struct {
+		Action string `json:"action"`
+		Sender struct {
+			Login string `json:"login"`
+		} `json:"sender"`
+
+		CheckRun   *appOwnedObject `json:"check_run"`
+		CheckSuite *appOwnedObject `json:"check_suite"`
+		Comment    *appOwnedObject `json:"comment"`
+		Issue      *appOwnedObject `json:"issue"`
+		Review     *appOwnedObject `json:"review"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return false
+	}
+
+	if slug != "" && strings.EqualFold(event.Sender.Login, BotLogin(slug)) {
+		return true
+	}
+
+	switch event.Action {
+	case "requested", "rerequested", "requested_action":
+	default:
+		for _, obj := range []*appOwnedObject{event.CheckRun, event.CheckSuite} {
+			if obj != nil && obj.App != nil && obj.App.ID == appID {
+				return true
+			}
+		}
+	}
+
+	for _, obj := range []*appOwnedObject{event.Comment, event.Issue, event.Review} {
+		if obj != nil && obj.PerformedViaGitHubApp != nil && obj.PerformedViaGitHubApp.ID == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// SelfEventFilter returns an EventFilter that ignores events caused by the
+// application with the given ID and slug. See IsSelfEvent for details.
+func SelfEventFilter(appID int64, slug string) EventFilter {
+	return func(ctx context.Context, eventType string, payload []byte) bool {
+		if IsSelfEvent(appID, slug, payload) {
+			zerolog.Ctx(ctx).Debug().Msg("Ignoring event caused by this app")
+			return false
+		}
+		return true
+	}
+}
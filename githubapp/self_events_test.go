@@ -0,0 +1,92 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsSelfEvent(t *testing.T) {
+	tests := map[string]struct {
+		Payload  string
+		Expected bool
+	}{
+		"botSender": {
+			Payload:  `{"action":"created","sender":{"login":"my-app[bot]"}}`,
+			Expected: true,
+		},
+		"otherBotSender": {
+			Payload:  `{"action":"created","sender":{"login":"other-app[bot]"}}`,
+			Expected: false,
+		},
+		"userSender": {
+			Payload:  `{"action":"created","sender":{"login":"my-app"}}`,
+			Expected: false,
+		},
+		"ownCheckRunCompleted": {
+			Payload:  `{"action":"completed","check_run":{"app":{"id":42}},"sender":{"login":"someone"}}`,
+			Expected: true,
+		},
+		"ownCheckRunRerequested": {
+			Payload:  `{"action":"rerequested","check_run":{"app":{"id":42}},"sender":{"login":"someone"}}`,
+			Expected: false,
+		},
+		"otherCheckRun": {
+			Payload:  `{"action":"completed","check_run":{"app":{"id":7}},"sender":{"login":"someone"}}`,
+			Expected: false,
+		},
+		"commentViaApp": {
+			Payload:  `{"action":"created","comment":{"performed_via_github_app":{"id":42}},"sender":{"login":"someone"}}`,
+			Expected: true,
+		},
+		"commentViaOtherApp": {
+			Payload:  `{"action":"created","comment":{"performed_via_github_app":{"id":7}},"sender":{"login":"someone"}}`,
+			Expected: false,
+		},
+		"invalidPayload": {
+			Payload:  `{`,
+			Expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if actual := IsSelfEvent(42, "my-app", []byte(test.Payload)); actual != test.Expected {
+				t.Errorf("incorrect result: expected %t, actual %t", test.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestDispatcherEventFilter(t *testing.T) {
+	handler := &TestEventHandler{Types: []string{"pull_request"}}
+	filter := func(ctx context.Context, eventType string, payload []byte) bool {
+		return false
+	}
+
+	d := NewEventDispatcher([]EventHandler{handler}, testHookSecret, WithEventFilter(filter))
+
+	w := httptest.NewRecorder()
+	d.ServeHTTP(w, newHookRequest("pull_request", "filtered", true))
+
+	if w.Code != 202 {
+		t.Errorf("incorrect response code: expected 202, actual %d", w.Code)
+	}
+	if handler.Count != 0 {
+		t.Errorf("filtered event was handled")
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// SelfTestStatus is the outcome of a self-test step.
+type SelfTestStatus string
+
+const (
+	SelfTestPassed  SelfTestStatus = "ok"
+	SelfTestWarning SelfTestStatus = "warn"
+	SelfTestFailed  SelfTestStatus = "fail"
+	SelfTestSkipped SelfTestStatus = "skip"
+)
+
+// SelfTestStep is the result of a single self-test step.
+type SelfTestStep struct {
+	Name   string
+	Status SelfTestStatus
+
+	// Detail describes the result of the step, like the application found
+	// or the reason the step failed.
+	Detail string
+}
+
+// SelfTestReport is the result of SelfTest.
+type SelfTestReport struct {
+	Steps []SelfTestStep
+}
+
+// Err returns an error describing the failed steps, or nil if no steps
+// failed.
+func (r *SelfTestReport) Err() error {
+	var failed []string
+	for _, s := range r.Steps {
+		if s.Status == SelfTestFailed {
+			failed = append(failed, fmt.Sprintf("%s: %s", s.Name, s.Detail))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return errors.Errorf("self-test failed: %s", strings.Join(failed, "; "))
+}
+
+// WriteTo writes a human-readable version of the report to w.
+func (r *SelfTestReport) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, s := range r.Steps {
+		n, err := fmt.Fprintf(w, "[%-4s] %-20s %s\n", s.Status, s.Name, s.Detail)
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (r *SelfTestReport) add(name string, status SelfTestStatus, format string, args ...interface{}) bool {
+	r.Steps = append(r.Steps, SelfTestStep{
+		Name:   name,
+		Status: status,
+		Detail: fmt.Sprintf(format, args...),
+	})
+	return status != SelfTestFailed
+}
+
+func (r *SelfTestReport) skip(names ...string) {
+	for _, name := range names {
+		r.add(name, SelfTestSkipped, "a previous step failed")
+	}
+}
+
+// SelfTest checks that the application described by c can authenticate with
+// GitHub. It validates the configuration and private key, creates an
+// application JWT, gets the application, lists one installation, creates an
+// installation token for it, and checks that a webhook secret is set. Steps
+// that depend on a failed step are skipped.
+//
+// Run SelfTest at startup or from a command line flag to diagnose problems
+// with new or changed configuration. The options configure the clients used
+// for the test.
+func SelfTest(ctx context.Context, c Config, opts ...ClientOption) *SelfTestReport {
+	r := &SelfTestReport{}
+
+	switch {
+	case !selfTestConfig(r, c):
+		r.skip("private key", "jwt", "app", "installations", "installation token")
+	case !selfTestKey(r, c):
+		r.skip("jwt", "app", "installations", "installation token")
+	case !selfTestJWT(r, c):
+		r.skip("app", "installations", "installation token")
+	default:
+		selfTestAPI(ctx, r, c, opts)
+	}
+
+	if c.App.WebhookSecret == "" {
+		r.add("webhook secret", SelfTestWarning, "not set; webhook signatures will not be validated")
+	} else {
+		r.add("webhook secret", SelfTestPassed, "set")
+	}
+
+	return r
+}
+
+func selfTestConfig(r *SelfTestReport, c Config) bool {
+	var missing []string
+	if c.V3APIURL == "" {
+		missing = append(missing, "v3_api_url")
+	}
+	if c.App.IntegrationID <= 0 {
+		missing = append(missing, "app.integration_id")
+	}
+	if c.App.PrivateKey == "" {
+		missing = append(missing, "app.private_key")
+	}
+	if len(missing) > 0 {
+		return r.add("configuration", SelfTestFailed, "missing values: %s", strings.Join(missing, ", "))
+	}
+	return r.add("configuration", SelfTestPassed, "app ID %d, API %s", c.App.IntegrationID, c.V3APIURL)
+}
+
+func selfTestKey(r *SelfTestReport, c Config) bool {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(c.App.PrivateKey))
+	if err != nil {
+		return r.add("private key", SelfTestFailed, "%v", err)
+	}
+	fingerprint, _ := PrivateKeyFingerprint([]byte(c.App.PrivateKey))
+	return r.add("private key", SelfTestPassed, "%d-bit RSA key, fingerprint %s", key.N.BitLen(), fingerprint)
+}
+
+func selfTestJWT(r *SelfTestReport, c Config) bool {
+	if _, err := NewAppJWT(c.App.IntegrationID, []byte(c.App.PrivateKey), MaxAppJWTExpiration); err != nil {
+		return r.add("jwt", SelfTestFailed, "%v", err)
+	}
+	return r.add("jwt", SelfTestPassed, "signed")
+}
+
+func selfTestAPI(ctx context.Context, r *SelfTestReport, c Config, opts []ClientOption) {
+	cc := NewClientCreatorFromConfig(c, opts...)
+
+	client, err := cc.NewAppClient()
+	if err != nil {
+		r.add("app", SelfTestFailed, "failed to create app client: %v", err)
+		r.skip("installations", "installation token")
+		return
+	}
+
+	app, _, err := client.Apps.Get(ctx, "")
+	if err != nil {
+		r.add("app", SelfTestFailed, "%v", selfTestError(err))
+		r.skip("installations", "installation token")
+		return
+	}
+	if app.GetID() != c.App.IntegrationID {
+		r.add("app", SelfTestFailed, "GitHub returned app ID %d, but the configuration has %d", app.GetID(), c.App.IntegrationID)
+		r.skip("installations", "installation token")
+		return
+	}
+	r.add("app", SelfTestPassed, "%s (ID %d)", app.GetSlug(), app.GetID())
+
+	installations, _, err := client.Apps.ListInstallations(ctx, &github.ListOptions{PerPage: 1})
+	switch {
+	case err != nil:
+		r.add("installations", SelfTestFailed, "%v", selfTestError(err))
+		r.skip("installation token")
+		return
+	case len(installations) == 0:
+		r.add("installations", SelfTestWarning, "the app is not installed on any accounts")
+		r.add("installation token", SelfTestSkipped, "no installations")
+		return
+	}
+
+	installation := installations[0]
+	r.add("installations", SelfTestPassed, "found installation %d for %s", installation.GetID(), installation.GetAccount().GetLogin())
+
+	token, _, err := client.Apps.CreateInstallationToken(ctx, installation.GetID(), nil)
+	if err != nil {
+		r.add("installation token", SelfTestFailed, "%v", selfTestError(err))
+		return
+	}
+	r.add("installation token", SelfTestPassed, "expires at %s", token.GetExpiresAt().Format(time.RFC3339))
+}
+
+// selfTestError removes the request URL from go-github errors, which is
+// noise in a report
+func selfTestError(err error) string {
+	var resErr *github.ErrorResponse
+	if errors.As(err, &resErr) && resErr.Response != nil {
+		return fmt.Sprintf("%d %s", resErr.Response.StatusCode, resErr.Message)
+	}
+	return err.Error()
+}
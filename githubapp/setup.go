@@ -0,0 +1,308 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// SetupActionInstall is the setup action after a user installs the app.
+	SetupActionInstall = "install"
+
+	// SetupActionUpdate is the setup action after a user changes the
+	// repositories or permissions of an existing installation.
+	SetupActionUpdate = "update"
+
+	// SetupActionRequest is the setup action after a user without permission
+	// to install the app requests an installation from an owner. There is no
+	// installation until an owner approves the request.
+	SetupActionRequest = "request"
+)
+
+var (
+	ErrInvalidSetupState = errors.New("invalid setup state")
+
+	// ErrSetupInstallationNotAccessible is returned when the user who made a
+	// setup request cannot access the installation in the request.
+	ErrSetupInstallationNotAccessible = errors.New("installation is not accessible to the user")
+)
+
+// Setup contains the parameters of a request to the application's setup URL.
+type Setup struct {
+	// InstallationID is the ID of the new or updated installation. It is 0
+	// for SetupActionRequest.
+	InstallationID int64
+
+	// Action is one of the SetupAction constants.
+	Action string
+
+	// State is the state value from the installation URL, if any.
+	State string
+
+	// Installation is the installation loaded from GitHub. It is nil for
+	// SetupActionRequest.
+	Installation *github.Installation
+}
+
+// SetupError is returned when a setup request has invalid parameters.
+type SetupError string
+
+func (err SetupError) Error() string {
+	return "invalid setup request: " + string(err)
+}
+
+// SetupStateVerifier verifies the state parameter of a setup request.
+type SetupStateVerifier interface {
+	// VerifyState checks that the state associated with the request matches
+	// the given state.
+	VerifyState(r *http.Request, state string) (bool, error)
+}
+
+// SetupProvisioner is called for each valid setup request before the setup
+// callback. Applications can use it to create or update records for the
+// installation's owner. If it returns an error, the handler calls the error
+// callback instead of the setup callback.
+type SetupProvisioner func(ctx context.Context, setup *Setup) error
+
+// SetupUserClientFunc returns a client authenticated as the user who made a
+// setup request, like a client from ClientCreator.NewTokenClient that uses
+// the token saved when the user logged in with the oauth2 package.
+type SetupUserClientFunc func(r *http.Request) (*github.Client, error)
+
+// SetupCallback sends the response after a successful setup request.
+type SetupCallback func(w http.ResponseWriter, r *http.Request, setup *Setup)
+
+// SetupOption configures properties of a setup handler.
+type SetupOption func(*setupHandler)
+
+// WithSetupStateVerifier sets the verifier for the state parameter. Requests
+// without a valid state fail with ErrInvalidSetupState. The state confirms
+// that the user started the installation from the application, but it is
+// not bound to the installation_id parameter, so it does not replace
+// WithSetupUserInstallations.
+func WithSetupStateVerifier(verifier SetupStateVerifier) SetupOption {
+	return func(h *setupHandler) {
+		h.verifier = verifier
+	}
+}
+
+// WithSetupUserInstallations confirms that the user who made a setup request
+// can access the installation in the request, by listing the user's
+// installations with a client from newClient. Requests for other
+// installations fail with ErrSetupInstallationNotAccessible. This prevents
+// users from claiming installations they do not own by changing the
+// installation_id parameter.
+func WithSetupUserInstallations(newClient SetupUserClientFunc) SetupOption {
+	return func(h *setupHandler) {
+		h.userClient = newClient
+	}
+}
+
+// WithSetupProvisioner sets the provisioner for the setup handler.
+func WithSetupProvisioner(provision SetupProvisioner) SetupOption {
+	return func(h *setupHandler) {
+		h.provision = provision
+	}
+}
+
+// OnSetup sets the callback for successful setup requests.
+func OnSetup(onSetup SetupCallback) SetupOption {
+	return func(h *setupHandler) {
+		if onSetup != nil {
+			h.onSetup = onSetup
+		}
+	}
+}
+
+// OnSetupError sets the callback for failed setup requests.
+func OnSetupError(onError ErrorCallback) SetupOption {
+	return func(h *setupHandler) {
+		if onError != nil {
+			h.onError = onError
+		}
+	}
+}
+
+type setupHandler struct {
+	cc         ClientCreator
+	verifier   SetupStateVerifier
+	userClient SetupUserClientFunc
+	provision  SetupProvisioner
+	onSetup    SetupCallback
+	onError    ErrorCallback
+}
+
+// NewSetupHandler returns an http.Handler for the application's setup URL,
+// where GitHub redirects users after they install or update the app. Because
+// users control the query parameters, the handler loads the installation
+// using an application client from cc to confirm that it exists and belongs
+// to the application.
+//
+// An installation belonging to the application does not mean it belongs to
+// the user who made the request, so NewSetupHandler returns an error unless
+// WithSetupUserInstallations is set. A valid state is not enough: a user can
+// start their own installation to get a valid state and then change the
+// installation_id parameter to another account's installation.
+func NewSetupHandler(cc ClientCreator, opts ...SetupOption) (http.Handler, error) {
+	h := &setupHandler{
+		cc:      cc,
+		onSetup: DefaultSetupCallback,
+		onError: DefaultSetupErrorCallback,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	if h.userClient == nil {
+		return nil, errors.New("setup handler requires a user installation check")
+	}
+	return h, nil
+}
+
+// DefaultSetupCallback responds with a plain text success message.
+func DefaultSetupCallback(w http.ResponseWriter, r *http.Request, setup *Setup) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if setup.Action == SetupActionRequest {
+		_, _ = w.Write([]byte("Installation requested\n"))
+	} else {
+		_, _ = w.Write([]byte("Installation complete\n"))
+	}
+}
+
+// DefaultSetupErrorCallback logs errors and responds with an appropriate
+// status code.
+func DefaultSetupErrorCallback(w http.ResponseWriter, r *http.Request, err error) {
+	var setupErr SetupError
+	var notFoundErr *NotFoundError
+	switch {
+	case errors.Is(err, ErrInvalidSetupState):
+		http.Error(w, "Invalid setup state", http.StatusBadRequest)
+	case errors.Is(err, ErrSetupInstallationNotAccessible):
+		http.Error(w, "Installation not found", http.StatusNotFound)
+	case errors.As(err, &setupErr):
+		http.Error(w, setupErr.Error(), http.StatusBadRequest)
+	case errors.As(ClassifyError(err), &notFoundErr):
+		http.Error(w, "Installation not found", http.StatusNotFound)
+	default:
+		zerolog.Ctx(r.Context()).Error().Err(err).Msg("Unexpected error handling app setup")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+func (h *setupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	setup, err := h.parseSetup(r)
+	if err != nil {
+		h.onError(w, r, err)
+		return
+	}
+
+	ctx := r.Context()
+	if setup.InstallationID > 0 {
+		client, err := h.cc.NewAppClient()
+		if err != nil {
+			h.onError(w, r, err)
+			return
+		}
+
+		installation, _, err := client.Apps.GetInstallation(ctx, setup.InstallationID)
+		if err != nil {
+			h.onError(w, r, errors.Wrapf(err, "failed to get installation %d", setup.InstallationID))
+			return
+		}
+		setup.Installation = installation
+
+		if err := h.checkUserInstallation(r, setup.InstallationID); err != nil {
+			h.onError(w, r, err)
+			return
+		}
+
+		ctx = zerolog.Ctx(ctx).With().Int64(LogKeyInstallationID, setup.InstallationID).Logger().WithContext(ctx)
+		r = r.WithContext(ctx)
+	}
+
+	if h.provision != nil {
+		if err := h.provision(ctx, setup); err != nil {
+			h.onError(w, r, err)
+			return
+		}
+	}
+	h.onSetup(w, r, setup)
+}
+
+// checkUserInstallation returns ErrSetupInstallationNotAccessible if the
+// user who made the request cannot access the installation.
+func (h *setupHandler) checkUserInstallation(r *http.Request, id int64) error {
+	client, err := h.userClient(r)
+	if err != nil {
+		return errors.Wrap(err, "failed to create user client")
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		installations, res, err := client.Apps.ListUserInstallations(r.Context(), opts)
+		if err != nil {
+			return errors.Wrap(err, "failed to list user installations")
+		}
+		for _, installation := range installations {
+			if installation.GetID() == id {
+				return nil
+			}
+		}
+		if res.NextPage == 0 {
+			return ErrSetupInstallationNotAccessible
+		}
+		opts.Page = res.NextPage
+	}
+}
+
+func (h *setupHandler) parseSetup(r *http.Request) (*Setup, error) {
+	q := r.URL.Query()
+
+	setup := &Setup{
+		Action: q.Get("setup_action"),
+		State:  q.Get("state"),
+	}
+
+	switch setup.Action {
+	case SetupActionInstall, SetupActionUpdate:
+		id, err := strconv.ParseInt(q.Get("installation_id"), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, SetupError("missing or invalid installation_id")
+		}
+		setup.InstallationID = id
+	case SetupActionRequest:
+	default:
+		return nil, SetupError("unknown setup_action")
+	}
+
+	if h.verifier != nil {
+		valid, err := h.verifier.VerifyState(r, setup.State)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, ErrInvalidSetupState
+		}
+	}
+	return setup, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+type staticStateVerifier string
+
+func (v staticStateVerifier) VerifyState(r *http.Request, state string) (bool, error) {
+	return string(v) == state, nil
+}
+
+func TestSetupHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /app/installations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		login, ok := map[string]string{"123": "octo", "789": "other"}[r.PathValue("id")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": %s, "account": {"login": %q}}`, r.PathValue("id"), login)
+	})
+	mux.HandleFunc("GET /user/installations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"total_count": 1, "installations": [{"id": 123}]}`)
+	})
+	cc := newStaticClientCreator(t, mux)
+	userInstallations := WithSetupUserInstallations(func(r *http.Request) (*github.Client, error) {
+		return cc.client, nil
+	})
+
+	tests := map[string]struct {
+		Query     string
+		Options   []SetupOption
+		Status    int
+		Provision string
+	}{
+		"install": {
+			Query:     "installation_id=123&setup_action=install",
+			Options:   []SetupOption{userInstallations},
+			Status:    http.StatusOK,
+			Provision: "install:123:octo",
+		},
+		"otherUserInstallation": {
+			Query:   "installation_id=789&setup_action=install",
+			Options: []SetupOption{userInstallations},
+			Status:  http.StatusNotFound,
+		},
+		"request": {
+			Query:     "setup_action=request",
+			Options:   []SetupOption{userInstallations},
+			Status:    http.StatusOK,
+			Provision: "request:0:",
+		},
+		"validState": {
+			Query:     "installation_id=123&setup_action=update&state=abc",
+			Options:   []SetupOption{userInstallations, WithSetupStateVerifier(staticStateVerifier("abc"))},
+			Status:    http.StatusOK,
+			Provision: "update:123:octo",
+		},
+		"invalidState": {
+			Query:   "installation_id=123&setup_action=install&state=xyz",
+			Options: []SetupOption{userInstallations, WithSetupStateVerifier(staticStateVerifier("abc"))},
+			Status:  http.StatusBadRequest,
+		},
+		"validStateOtherUserInstallation": {
+			Query:   "installation_id=789&setup_action=install&state=abc",
+			Options: []SetupOption{userInstallations, WithSetupStateVerifier(staticStateVerifier("abc"))},
+			Status:  http.StatusNotFound,
+		},
+		"missingInstallation": {
+			Query:   "setup_action=install",
+			Options: []SetupOption{userInstallations},
+			Status:  http.StatusBadRequest,
+		},
+		"unknownAction": {
+			Query:   "installation_id=123&setup_action=delete",
+			Options: []SetupOption{userInstallations},
+			Status:  http.StatusBadRequest,
+		},
+		"unknownInstallation": {
+			Query:   "installation_id=456&setup_action=install",
+			Options: []SetupOption{userInstallations},
+			Status:  http.StatusNotFound,
+		},
+		"provisionError": {
+			Query: "installation_id=123&setup_action=install",
+			Options: []SetupOption{userInstallations, WithSetupProvisioner(func(ctx context.Context, setup *Setup) error {
+				return errors.New("database unavailable")
+			})},
+			Status: http.StatusInternalServerError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var provisioned string
+			opts := append([]SetupOption{WithSetupProvisioner(func(ctx context.Context, setup *Setup) error {
+				provisioned = fmt.Sprintf("%s:%d:%s", setup.Action, setup.InstallationID, setup.Installation.GetAccount().GetLogin())
+				return nil
+			})}, test.Options...)
+
+			h, err := NewSetupHandler(cc, opts...)
+			if err != nil {
+				t.Fatalf("unexpected error creating handler: %v", err)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/setup?"+test.Query, nil))
+
+			if w.Code != test.Status {
+				t.Errorf("expected status %d, but got %d: %s", test.Status, w.Code, w.Body.String())
+			}
+			if provisioned != test.Provision {
+				t.Errorf("incorrect provisioning: expected %q, actual %q", test.Provision, provisioned)
+			}
+		})
+	}
+}
+
+func TestSetupHandlerRequiresVerification(t *testing.T) {
+	cc := newStaticClientCreator(t, http.NewServeMux())
+	if _, err := NewSetupHandler(cc); err == nil {
+		t.Fatal("expected error creating handler without verification, but got nil")
+	}
+	if _, err := NewSetupHandler(cc, WithSetupStateVerifier(staticStateVerifier("abc"))); err == nil {
+		t.Fatal("expected error creating handler with only a state verifier, but got nil")
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// GracefulShutdown stops webhook ingestion and then event processing, in
+// that order, so events accepted from GitHub are not lost during deploys.
+//
+// First, it calls Shutdown on server, which stops accepting connections and
+// waits for in-flight requests, including synchronous handlers and calls to
+// Schedule, to finish. Then it drains each scheduler that implements
+// DrainScheduler, waiting for queued and running handlers. Other schedulers,
+// like DefaultScheduler, have no work outside of requests and are skipped.
+// If server is nil, GracefulShutdown only drains the schedulers.
+//
+// The context limits the total time spent shutting down. If it expires,
+// GracefulShutdown still stops the remaining schedulers from accepting new
+// dispatches and returns the first error. Handlers that are running when it
+// returns are not canceled.
+//
+// With go-baseapp, pass the server returned by HTTPServer().
+func GracefulShutdown(ctx context.Context, server *http.Server, schedulers ...Scheduler) error {
+	logger := zerolog.Ctx(ctx)
+
+	var firstErr error
+	if server != nil {
+		logger.Info().Msg("Stopping webhook server")
+		if err := server.Shutdown(ctx); err != nil {
+			firstErr = errors.Wrap(err, "failed to shut down server")
+		}
+	}
+
+	for _, s := range schedulers {
+		ds, ok := s.(DrainScheduler)
+		if !ok {
+			continue
+		}
+
+		logger.Info().Msg("Draining event scheduler")
+		if err := ds.Drain(ctx); err != nil && firstErr == nil {
+			firstErr = errors.Wrap(err, "failed to drain scheduler")
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	t.Run("drainsQueuedEvents", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("failed to listen: %v", err)
+		}
+
+		server := &http.Server{Handler: http.NotFoundHandler()}
+		served := make(chan error, 1)
+		go func() { served <- server.Serve(ln) }()
+
+		s := QueueAsyncScheduler(2, 1)
+		h := AsyncHandler{Block: make(chan struct{}), Called: make(chan bool, 2)}
+		for i := 0; i < 2; i++ {
+			if err := s.Schedule(context.Background(), Dispatch{Handler: &h}); err != nil {
+				t.Fatalf("unexpected error scheduling dispatch: %v", err)
+			}
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- GracefulShutdown(context.Background(), server, DefaultScheduler(), s) }()
+
+		select {
+		case err := <-done:
+			t.Fatalf("shutdown returned before handlers finished: %v", err)
+		case <-time.After(timeout):
+		}
+
+		close(h.Block)
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("unexpected error shutting down: %v", err)
+			}
+		case <-time.After(timeout):
+			t.Fatalf("shutdown did not return after %v", timeout)
+		}
+
+		if len(h.Called) != 2 {
+			t.Errorf("expected 2 handled events, but got %d", len(h.Called))
+		}
+		if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+			t.Errorf("expected server to close, but got: %v", err)
+		}
+		if err := s.Schedule(context.Background(), Dispatch{Handler: &h}); err != ErrSchedulerDraining {
+			t.Errorf("expected ErrSchedulerDraining, but got: %v", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		s := AsyncScheduler()
+		h := AsyncHandler{Block: make(chan struct{}), Called: make(chan bool, 1)}
+		defer close(h.Block)
+
+		if err := s.Schedule(context.Background(), Dispatch{Handler: &h}); err != nil {
+			t.Fatalf("unexpected error scheduling dispatch: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		if err := GracefulShutdown(ctx, nil, s); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, but got: %v", err)
+		}
+		if err := s.Schedule(context.Background(), Dispatch{Handler: &h}); err != ErrSchedulerDraining {
+			t.Errorf("expected ErrSchedulerDraining, but got: %v", err)
+		}
+	})
+}
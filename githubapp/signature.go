@@ -0,0 +1,218 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// VerifySignatureOption configures how VerifySignature checks signatures.
+type VerifySignatureOption func(*verifySignatureConfig)
+
+// WithSHA1SignatureFallback makes VerifySignature use the SHA-1 signature in
+// the X-Hub-Signature header when the X-Hub-Signature-256 header is not set.
+// GitHub sends both headers, so only enable this for senders that cannot
+// produce SHA-256 signatures, like some older GitHub Enterprise Server
+// versions.
+func WithSHA1SignatureFallback() VerifySignatureOption {
+	return func(c *verifySignatureConfig) {
+		c.allowSHA1 = true
+	}
+}
+
+type verifySignatureConfig struct {
+	allowSHA1 bool
+}
+
+// VerifySignature checks that body was signed with secret by GitHub. It uses
+// the SHA-256 signature in the X-Hub-Signature-256 header. The body is the
+// unmodified body of the webhook request, regardless of its content type.
+// VerifySignature returns an error if the secret is empty, so a missing
+// secret never disables verification.
+func VerifySignature(secret string, body []byte, header http.Header, opts ...VerifySignatureOption) error {
+	var config verifySignatureConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if secret == "" {
+		return errors.New("webhook secret is not set")
+	}
+
+	alg, signature := "sha256", header.Get(github.SHA256SignatureHeader)
+	if signature == "" && config.allowSHA1 {
+		alg, signature = "sha1", header.Get(github.SHA1SignatureHeader)
+	}
+	if signature != "" && !strings.HasPrefix(signature, alg+"=") {
+		return errors.Errorf("invalid signature format: %q", signature)
+	}
+
+	mac, expected, err := parseSignature(signature, []byte(secret))
+	if err != nil {
+		return err
+	}
+	mac.Write(body)
+	if !hmac.Equal(expected, mac.Sum(nil)) {
+		return errors.New("payload signature check failed")
+	}
+	return nil
+}
+
+// ResignPayload verifies that body was signed with secret by GitHub and then
+// replaces the signature headers in header with a signature of body with
+// newSecret. Use it when forwarding deliveries to internal services, so
+// they can verify deliveries with the same code as for GitHub, like an event
+// dispatcher, without knowing the GitHub webhook secret. The header is not
+// modified if verification fails or if newSecret is empty.
+func ResignPayload(secret, newSecret string, body []byte, header http.Header) error {
+	if newSecret == "" {
+		return errors.New("re-signing secret is not set")
+	}
+	if err := VerifySignature(secret, body, header); err != nil {
+		return err
+	}
+	header.Del(github.SHA1SignatureHeader)
+	header.Set(github.SHA256SignatureHeader, SignPayload(newSecret, body))
+	return nil
+}
+
+// SignatureMiddlewareOption configures properties of a signature middleware.
+type SignatureMiddlewareOption func(*signatureMiddleware)
+
+// WithSignatureErrorCallback sets the callback for requests that fail
+// verification. The error is always a ValidationError. The default is
+// DefaultErrorCallback, which responds with status 400.
+func WithSignatureErrorCallback(onError ErrorCallback) SignatureMiddlewareOption {
+	return func(m *signatureMiddleware) {
+		if onError != nil {
+			m.onError = onError
+		}
+	}
+}
+
+// WithSignatureMaxPayloadSize sets the largest request body, in bytes, that
+// the middleware accepts. The default is DefaultMaxPayloadSize.
+func WithSignatureMaxPayloadSize(size int64) SignatureMiddlewareOption {
+	return func(m *signatureMiddleware) {
+		if size > 0 {
+			m.maxPayloadSize = size
+		}
+	}
+}
+
+// WithSignatureSHA1Fallback makes the middleware accept requests that only
+// have a SHA-1 signature, as with WithSHA1SignatureFallback.
+func WithSignatureSHA1Fallback() SignatureMiddlewareOption {
+	return func(m *signatureMiddleware) {
+		m.verifyOpts = append(m.verifyOpts, WithSHA1SignatureFallback())
+	}
+}
+
+// WithSignatureResigning makes the middleware re-sign valid requests with
+// secret before passing them to the next handler, as with ResignPayload. Use
+// it in gateways that forward deliveries to services that have their own
+// secret. If secret is empty, the middleware rejects all requests instead of
+// forwarding them with a signature anyone could create.
+func WithSignatureResigning(secret string) SignatureMiddlewareOption {
+	return func(m *signatureMiddleware) {
+		m.resign = true
+		m.resignSecret = secret
+	}
+}
+
+type signatureMiddleware struct {
+	secret         string
+	resign         bool
+	resignSecret   string
+	onError        ErrorCallback
+	maxPayloadSize int64
+	verifyOpts     []VerifySignatureOption
+}
+
+// SignatureMiddleware returns middleware that rejects webhook requests that
+// are not signed with secret. Requests with valid signatures are passed to
+// the next handler with an unmodified body, so the middleware can protect
+// gateways and proxies that forward GitHub traffic to other services. By
+// default, the original signature is also forwarded. If secret is empty, the
+// middleware rejects all requests.
+//
+// The middleware reads the entire body into memory. Event dispatchers verify
+// signatures themselves and do not need this middleware.
+func SignatureMiddleware(secret string, opts ...SignatureMiddlewareOption) func(http.Handler) http.Handler {
+	m := &signatureMiddleware{
+		secret:         secret,
+		onError:        DefaultErrorCallback,
+		maxPayloadSize: DefaultMaxPayloadSize,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := m.verify(r)
+			if err != nil {
+				m.onError(w, r, ValidationError{
+					EventType:  r.Header.Get("X-GitHub-Event"),
+					DeliveryID: r.Header.Get("X-GitHub-Delivery"),
+					Cause:      err,
+				})
+				return
+			}
+
+			if m.resign {
+				r.Header.Del(github.SHA1SignatureHeader)
+				r.Header.Set(github.SHA256SignatureHeader, SignPayload(m.resignSecret, body))
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (m *signatureMiddleware) verify(r *http.Request) ([]byte, error) {
+	if m.resign && m.resignSecret == "" {
+		return nil, errors.New("re-signing secret is not set")
+	}
+	if r.ContentLength > m.maxPayloadSize {
+		return nil, errors.Errorf("payload size %d exceeds the maximum of %d bytes", r.ContentLength, m.maxPayloadSize)
+	}
+
+	var buf bytes.Buffer
+	if r.ContentLength > 0 {
+		buf.Grow(int(r.ContentLength))
+	}
+	if _, err := io.Copy(&buf, io.LimitReader(r.Body, m.maxPayloadSize+1)); err != nil {
+		return nil, errors.Wrap(err, "failed to read payload")
+	}
+	if int64(buf.Len()) > m.maxPayloadSize {
+		return nil, errors.Errorf("payload exceeds the maximum of %d bytes", m.maxPayloadSize)
+	}
+
+	if err := VerifySignature(m.secret, buf.Bytes(), r.Header, m.verifyOpts...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
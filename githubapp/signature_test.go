@@ -0,0 +1,259 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+
+	sha1Signature := func() string {
+		mac := hmac.New(sha1.New, []byte(testHookSecret))
+		mac.Write(payload)
+		return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+	}()
+
+	tests := map[string]struct {
+		Header http.Header
+		Opts   []VerifySignatureOption
+		Err    bool
+	}{
+		"sha256": {
+			Header: http.Header{"X-Hub-Signature-256": {SignPayload(testHookSecret, payload)}},
+		},
+		"sha1": {
+			Header: http.Header{"X-Hub-Signature": {sha1Signature}},
+			Err:    true,
+		},
+		"sha1Fallback": {
+			Header: http.Header{"X-Hub-Signature": {sha1Signature}},
+			Opts:   []VerifySignatureOption{WithSHA1SignatureFallback()},
+		},
+		"sha1InSHA256Header": {
+			Header: http.Header{"X-Hub-Signature-256": {sha1Signature}},
+			Opts:   []VerifySignatureOption{WithSHA1SignatureFallback()},
+			Err:    true,
+		},
+
+		"wrongSecret": {
+			Header: http.Header{"X-Hub-Signature-256": {SignPayload("wrong", payload)}},
+			Err:    true,
+		},
+		"missing": {
+			Header: http.Header{},
+			Err:    true,
+		},
+		"malformed": {
+			Header: http.Header{"X-Hub-Signature-256": {"sha256:abc"}},
+			Err:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifySignature(testHookSecret, payload, test.Header, test.Opts...)
+			if test.Err && err == nil {
+				t.Fatal("expected error, but got nil")
+			}
+			if !test.Err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	t.Run("emptySecret", func(t *testing.T) {
+		header := http.Header{"X-Hub-Signature-256": {SignPayload("", payload)}}
+		if err := VerifySignature("", payload, header); err == nil {
+			t.Fatal("expected error, but got nil")
+		}
+	})
+
+	t.Run("middlewareSHA1Fallback", func(t *testing.T) {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		for fallback, status := range map[bool]int{false: http.StatusBadRequest, true: http.StatusAccepted} {
+			var opts []SignatureMiddlewareOption
+			if fallback {
+				opts = append(opts, WithSignatureSHA1Fallback())
+			}
+			r := httptest.NewRequest(http.MethodPost, DefaultWebhookRoute, strings.NewReader(string(payload)))
+			r.Header.Set("X-Hub-Signature", sha1Signature)
+
+			w := httptest.NewRecorder()
+			SignatureMiddleware(testHookSecret, opts...)(next).ServeHTTP(w, r)
+			if w.Code != status {
+				t.Errorf("incorrect status with fallback %t: expected %d, actual %d", fallback, status, w.Code)
+			}
+		}
+	})
+}
+
+func TestSignatureMiddleware(t *testing.T) {
+	payload := `{"action":"opened"}`
+
+	var forwarded string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		forwarded = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	tests := map[string]struct {
+		Signature string
+		MaxSize   int64
+		Status    int
+	}{
+		"valid": {
+			Signature: SignPayload(testHookSecret, []byte(payload)),
+			Status:    http.StatusAccepted,
+		},
+		"invalid": {
+			Signature: SignPayload("wrong", []byte(payload)),
+			Status:    http.StatusBadRequest,
+		},
+		"missing": {
+			Status: http.StatusBadRequest,
+		},
+		"tooLarge": {
+			Signature: SignPayload(testHookSecret, []byte(payload)),
+			MaxSize:   8,
+			Status:    http.StatusBadRequest,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			forwarded = ""
+			h := SignatureMiddleware(testHookSecret, WithSignatureMaxPayloadSize(test.MaxSize))(next)
+
+			r := httptest.NewRequest(http.MethodPost, DefaultWebhookRoute, strings.NewReader(payload))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("X-GitHub-Event", "pull_request")
+			if test.Signature != "" {
+				r.Header.Set("X-Hub-Signature-256", test.Signature)
+			}
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != test.Status {
+				t.Fatalf("incorrect status: expected %d, actual %d", test.Status, w.Code)
+			}
+			if test.Status == http.StatusAccepted && forwarded != payload {
+				t.Errorf("incorrect forwarded body: expected %q, actual %q", payload, forwarded)
+			}
+			if test.Status != http.StatusAccepted && forwarded != "" {
+				t.Errorf("request was forwarded after failing verification")
+			}
+		})
+	}
+}
+
+func TestResignPayload(t *testing.T) {
+	payload := []byte(`{"action":"opened"}`)
+
+	header := http.Header{
+		"X-Hub-Signature-256": {SignPayload(testHookSecret, payload)},
+		"X-Hub-Signature":     {"sha1=ignored"},
+	}
+	if err := ResignPayload(testHookSecret, "internal", payload, header); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := VerifySignature("internal", payload, header); err != nil {
+		t.Errorf("re-signed payload failed verification: %v", err)
+	}
+	if header.Get("X-Hub-Signature") != "" {
+		t.Errorf("expected SHA-1 signature to be removed, but got %q", header.Get("X-Hub-Signature"))
+	}
+
+	header = http.Header{"X-Hub-Signature-256": {SignPayload("wrong", payload)}}
+	if err := ResignPayload(testHookSecret, "internal", payload, header); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+	if header.Get("X-Hub-Signature-256") != SignPayload("wrong", payload) {
+		t.Errorf("header was modified after failing verification")
+	}
+
+	header = http.Header{"X-Hub-Signature-256": {SignPayload(testHookSecret, payload)}}
+	if err := ResignPayload(testHookSecret, "", payload, header); err == nil {
+		t.Fatal("expected error for empty secret, but got nil")
+	}
+	if header.Get("X-Hub-Signature-256") != SignPayload(testHookSecret, payload) {
+		t.Errorf("header was modified with an empty secret")
+	}
+}
+
+func TestSignatureMiddlewareResigning(t *testing.T) {
+	payload := `{"action":"opened"}`
+
+	var handled []string
+	downstream := NewEventDispatcher([]EventHandler{&TestEventHandler{
+		Types: []string{"pull_request"},
+		Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+			handled = append(handled, deliveryID)
+			return nil
+		},
+	}}, "internal")
+
+	h := SignatureMiddleware(testHookSecret, WithSignatureResigning("internal"))(downstream)
+
+	r := httptest.NewRequest(http.MethodPost, DefaultWebhookRoute, strings.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-GitHub-Event", "pull_request")
+	r.Header.Set("X-GitHub-Delivery", "forwarded")
+	r.Header.Set("X-Hub-Signature-256", SignPayload(testHookSecret, []byte(payload)))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("incorrect status: expected %d, actual %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if len(handled) != 1 || handled[0] != "forwarded" {
+		t.Errorf("incorrect handled deliveries: %v", handled)
+	}
+
+	t.Run("emptySecret", func(t *testing.T) {
+		var forwarded bool
+		h := SignatureMiddleware(testHookSecret, WithSignatureResigning(""))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			forwarded = true
+		}))
+
+		r := httptest.NewRequest(http.MethodPost, DefaultWebhookRoute, strings.NewReader(payload))
+		r.Header.Set("X-Hub-Signature-256", SignPayload(testHookSecret, []byte(payload)))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("incorrect status: expected %d, actual %d", http.StatusBadRequest, w.Code)
+		}
+		if forwarded {
+			t.Error("request was forwarded without a re-signing secret")
+		}
+	})
+}
@@ -0,0 +1,214 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Tenant contains the settings, quotas, and feature flags of an
+// installation. Multi-tenant applications use it to customize how handlers
+// process events for each installation. The methods of a nil Tenant return
+// zero values, so handlers can use the result of GetTenant directly.
+type Tenant struct {
+	InstallationID int64 `json:"installation_id"`
+
+	Settings map[string]string `json:"settings,omitempty"`
+	Features map[string]bool   `json:"features,omitempty"`
+	Quotas   map[string]int64  `json:"quotas,omitempty"`
+}
+
+// Setting returns the value of a setting or the empty string if it is not
+// set.
+func (t *Tenant) Setting(name string) string {
+	if t == nil {
+		return ""
+	}
+	return t.Settings[name]
+}
+
+// Enabled returns true if a feature flag is enabled.
+func (t *Tenant) Enabled(feature string) bool {
+	if t == nil {
+		return false
+	}
+	return t.Features[feature]
+}
+
+// Quota returns the value of a quota and true, or false if it is not set.
+func (t *Tenant) Quota(name string) (int64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	q, ok := t.Quotas[name]
+	return q, ok
+}
+
+func (t *Tenant) clone() *Tenant {
+	if t == nil {
+		return nil
+	}
+
+	c := &Tenant{InstallationID: t.InstallationID}
+	if t.Settings != nil {
+		c.Settings = make(map[string]string, len(t.Settings))
+		for k, v := range t.Settings {
+			c.Settings[k] = v
+		}
+	}
+	if t.Features != nil {
+		c.Features = make(map[string]bool, len(t.Features))
+		for k, v := range t.Features {
+			c.Features[k] = v
+		}
+	}
+	if t.Quotas != nil {
+		c.Quotas = make(map[string]int64, len(t.Quotas))
+		for k, v := range t.Quotas {
+			c.Quotas[k] = v
+		}
+	}
+	return c
+}
+
+// TenantStore stores Tenants by installation ID. Implement it to keep tenant
+// configuration in a database or configuration service.
+type TenantStore interface {
+	// Get returns the tenant for an installation. It returns nil and a nil
+	// error if the installation has no tenant.
+	Get(ctx context.Context, installationID int64) (*Tenant, error)
+
+	// Put creates or replaces the tenant for t.InstallationID.
+	Put(ctx context.Context, t *Tenant) error
+
+	// Delete removes the tenant for an installation. It does nothing if the
+	// installation has no tenant.
+	Delete(ctx context.Context, installationID int64) error
+}
+
+// MemoryTenantStore is a TenantStore that keeps tenants in memory. It is
+// useful for tests and for applications that load tenants from static
+// configuration at startup.
+type MemoryTenantStore struct {
+	mu      sync.RWMutex
+	tenants map[int64]*Tenant
+}
+
+// NewMemoryTenantStore creates a MemoryTenantStore containing tenants.
+func NewMemoryTenantStore(tenants ...*Tenant) *MemoryTenantStore {
+	s := &MemoryTenantStore{tenants: make(map[int64]*Tenant)}
+	for _, t := range tenants {
+		s.tenants[t.InstallationID] = t.clone()
+	}
+	return s
+}
+
+func (s *MemoryTenantStore) Get(ctx context.Context, installationID int64) (*Tenant, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tenants[installationID].clone(), nil
+}
+
+func (s *MemoryTenantStore) Put(ctx context.Context, t *Tenant) error {
+	if t == nil {
+		return errors.New("tenant must not be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenants[t.InstallationID] = t.clone()
+	return nil
+}
+
+func (s *MemoryTenantStore) Delete(ctx context.Context, installationID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tenants, installationID)
+	return nil
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx that contains the tenant.
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, t)
+}
+
+// GetTenant returns the tenant stored in the context by WithTenant or a
+// handler created by TenantHandler. It returns nil if the context does not
+// contain a tenant.
+func GetTenant(ctx context.Context) *Tenant {
+	t, _ := ctx.Value(tenantKey{}).(*Tenant)
+	return t
+}
+
+// TenantHandler returns an EventHandler that loads the tenant for the
+// installation in each event from store and adds it to the context before
+// calling next. Handlers read the tenant with GetTenant. Events without an
+// installation and installations without a tenant are handled with no tenant
+// in the context. If the store returns an error, the event is not handled.
+//
+// The tenant is loaded when the handler runs, not when the event is
+// received, so handlers see the current tenant with asynchronous
+// schedulers.
+func TenantHandler(store TenantStore, next EventHandler) EventHandler {
+	return &tenantHandler{store: store, next: next}
+}
+
+// WithTenantStore wraps all of the dispatcher's handlers with TenantHandler.
+func WithTenantStore(store TenantStore) DispatcherOption {
+	return func(d *eventDispatcher) {
+		wrapTenantHandlers(store, d.handlerMap)
+	}
+}
+
+// WithConsumerTenantStore wraps all of the consumer's handlers with
+// TenantHandler.
+func WithConsumerTenantStore(store TenantStore) EventConsumerOption {
+	return func(c *EventConsumer) {
+		wrapTenantHandlers(store, c.handlerMap)
+	}
+}
+
+func wrapTenantHandlers(store TenantStore, handlerMap map[string]EventHandler) {
+	for eventType, h := range handlerMap {
+		handlerMap[eventType] = TenantHandler(store, h)
+	}
+}
+
+type tenantHandler struct {
+	store TenantStore
+	next  EventHandler
+}
+
+func (h *tenantHandler) Handles() []string {
+	return h.next.Handles()
+}
+
+func (h *tenantHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	if e, err := PeekEnvelopeContext(ctx, payload); err == nil && e.InstallationID > 0 {
+		t, err := h.store.Get(ctx, e.InstallationID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to load tenant for installation %d", e.InstallationID)
+		}
+		if t != nil {
+			ctx = WithTenant(ctx, t)
+		}
+	}
+	return h.next.Handle(ctx, eventType, deliveryID, payload)
+}
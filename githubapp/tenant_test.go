@@ -0,0 +1,147 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+type errorTenantStore struct {
+	MemoryTenantStore
+}
+
+func (s *errorTenantStore) Get(ctx context.Context, installationID int64) (*Tenant, error) {
+	return nil, errors.New("store unavailable")
+}
+
+func TestMemoryTenantStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryTenantStore(&Tenant{InstallationID: 1, Features: map[string]bool{"beta": true}})
+
+	tenant, err := store.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tenant.Enabled("beta") {
+		t.Errorf("expected feature to be enabled: %+v", tenant)
+	}
+
+	// modifying a returned tenant does not modify the store
+	tenant.Features["beta"] = false
+	if tenant, _ := store.Get(ctx, 1); !tenant.Enabled("beta") {
+		t.Errorf("store was modified through a returned tenant: %+v", tenant)
+	}
+
+	if err := store.Put(ctx, &Tenant{InstallationID: 2, Quotas: map[string]int64{"reviews": 10}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tenant, _ = store.Get(ctx, 2)
+	if q, ok := tenant.Quota("reviews"); !ok || q != 10 {
+		t.Errorf("incorrect quota: %d, %t", q, ok)
+	}
+
+	if err := store.Delete(ctx, 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tenant, _ := store.Get(ctx, 2); tenant != nil {
+		t.Errorf("expected deleted tenant to be nil, but got %+v", tenant)
+	}
+}
+
+func TestNilTenant(t *testing.T) {
+	var tenant *Tenant
+	if tenant.Setting("mode") != "" || tenant.Enabled("beta") {
+		t.Error("nil tenant returned non-zero values")
+	}
+	if _, ok := tenant.Quota("reviews"); ok {
+		t.Error("nil tenant returned a quota")
+	}
+}
+
+func TestTenantHandler(t *testing.T) {
+	store := NewMemoryTenantStore(&Tenant{InstallationID: 1, Settings: map[string]string{"mode": "strict"}})
+
+	tests := map[string]struct {
+		Store   TenantStore
+		Payload string
+		Mode    string
+		Err     bool
+	}{
+		"tenant": {
+			Store:   store,
+			Payload: `{"action": "opened", "installation": {"id": 1}}`,
+			Mode:    "strict",
+		},
+		"unknownInstallation": {
+			Store:   store,
+			Payload: `{"action": "opened", "installation": {"id": 2}}`,
+		},
+		"noInstallation": {
+			Store:   store,
+			Payload: `{"action": "opened"}`,
+		},
+		"storeError": {
+			Store:   &errorTenantStore{},
+			Payload: `{"action": "opened", "installation": {"id": 1}}`,
+			Err:     true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mode string
+			next := &TestEventHandler{
+				Types: []string{"issues"},
+				Fn: func(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+					mode = GetTenant(ctx).Setting("mode")
+					return nil
+				},
+			}
+
+			h := TenantHandler(test.Store, next)
+			err := h.Handle(context.Background(), "issues", "delivery-id", []byte(test.Payload))
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("expected error, but got nil")
+				}
+				if next.Count != 0 {
+					t.Error("handler was called after store error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mode != test.Mode {
+				t.Errorf("incorrect tenant setting: expected %q, actual %q", test.Mode, mode)
+			}
+		})
+	}
+}
+
+func TestWithTenantStore(t *testing.T) {
+	h := &TestEventHandler{Types: []string{"issues", "pull_request"}}
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithTenantStore(NewMemoryTenantStore())).(*eventDispatcher)
+
+	for eventType, handler := range d.handlerMap {
+		if _, ok := handler.(*tenantHandler); !ok {
+			t.Errorf("handler for %s is not a tenant handler: %T", eventType, handler)
+		}
+	}
+}
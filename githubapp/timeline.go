@@ -0,0 +1,276 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+const (
+	timelinePageSize = 100
+)
+
+// TimelineItemType identifies the kind of a TimelineItem.
+type TimelineItemType string
+
+const (
+	TimelineComment       TimelineItemType = "comment"
+	TimelineEvent         TimelineItemType = "event"
+	TimelineCommit        TimelineItemType = "commit"
+	TimelineReview        TimelineItemType = "review"
+	TimelineReviewComment TimelineItemType = "review_comment"
+)
+
+// TimelineItem is an entry in the timeline of an issue or pull request.
+// Exactly one of the value fields is set, depending on the type.
+type TimelineItem struct {
+	Type TimelineItemType
+
+	// Time is when the item happened. For commits, this is the committer
+	// date, which may be earlier than when the commit was pushed.
+	Time time.Time
+
+	// Actor is the login of the user who created the item. For commits
+	// without an associated GitHub user, it is the name of the author.
+	Actor string
+
+	Comment       *github.IssueComment
+	Event         *github.IssueEvent
+	Commit        *github.RepositoryCommit
+	Review        *github.PullRequestReview
+	ReviewComment *github.PullRequestComment
+}
+
+// TimelineOptions configures the timeline helpers.
+type TimelineOptions struct {
+	// Types selects the types of items to return. If empty, the helpers
+	// return all types.
+	Types []TimelineItemType
+
+	// MaxRateLimitWait is the longest time the helpers wait for a rate limit
+	// to reset before retrying a request. If a request fails because of a
+	// rate limit and GitHub asks the client to wait longer, if this is zero,
+	// or if the RetryBudget of the context is exhausted, the helpers return
+	// the error.
+	MaxRateLimitWait time.Duration
+}
+
+func (opts *TimelineOptions) includes(t TimelineItemType) bool {
+	if opts == nil || len(opts.Types) == 0 {
+		return true
+	}
+	for _, included := range opts.Types {
+		if included == t {
+			return true
+		}
+	}
+	return false
+}
+
+// TimelineFunc is called with each item in a timeline. If it returns an
+// error, the helpers stop reading the timeline and return the error.
+type TimelineFunc func(item TimelineItem) error
+
+// StreamIssueTimeline calls fn with the comments and events of an issue in
+// the order they happened. It reads pages from each endpoint as needed, so
+// it does not hold the entire timeline in memory.
+func StreamIssueTimeline(ctx context.Context, client *github.Client, owner, repo string, number int, opts *TimelineOptions, fn TimelineFunc) error {
+	sources := issueTimelineSources(client, owner, repo, number, opts)
+	return errors.Wrapf(streamTimeline(ctx, sources, opts, fn), "failed to read timeline of %s/%s#%d", owner, repo, number)
+}
+
+// StreamPullRequestTimeline calls fn with the comments, events, commits,
+// reviews, and review comments of a pull request in the order they happened.
+// GitHub serves each type of item from a different endpoint; this function
+// reads pages from each endpoint as needed and merges them by time. Pending
+// reviews, which are only visible to their author, are not included.
+//
+// GitHub lists the commits of a pull request in ancestry order, which does
+// not match time order after a rebase, so this function reads all commits
+// before returning the first one and sorts them by time. GitHub returns at
+// most 250 commits for a pull request; later commits are not included.
+func StreamPullRequestTimeline(ctx context.Context, client *github.Client, owner, repo string, number int, opts *TimelineOptions, fn TimelineFunc) error {
+	sources := issueTimelineSources(client, owner, repo, number, opts)
+	if opts.includes(TimelineCommit) {
+		sources = append(sources, &timelineSource{sorted: true, fetch: func(ctx context.Context, page int) ([]TimelineItem, *github.Response, error) {
+			commits, res, err := client.PullRequests.ListCommits(ctx, owner, repo, number, &github.ListOptions{Page: page, PerPage: timelinePageSize})
+			items := make([]TimelineItem, 0, len(commits))
+			for _, c := range commits {
+				items = append(items, commitTimelineItem(c))
+			}
+			return items, res, err
+		}})
+	}
+	if opts.includes(TimelineReview) {
+		sources = append(sources, &timelineSource{fetch: func(ctx context.Context, page int) ([]TimelineItem, *github.Response, error) {
+			reviews, res, err := client.PullRequests.ListReviews(ctx, owner, repo, number, &github.ListOptions{Page: page, PerPage: timelinePageSize})
+			items := make([]TimelineItem, 0, len(reviews))
+			for _, r := range reviews {
+				if r.GetState() == "PENDING" {
+					continue
+				}
+				items = append(items, TimelineItem{Type: TimelineReview, Time: r.GetSubmittedAt().Time, Actor: r.GetUser().GetLogin(), Review: r})
+			}
+			return items, res, err
+		}})
+	}
+	if opts.includes(TimelineReviewComment) {
+		sources = append(sources, &timelineSource{fetch: func(ctx context.Context, page int) ([]TimelineItem, *github.Response, error) {
+			comments, res, err := client.PullRequests.ListComments(ctx, owner, repo, number, &github.PullRequestListCommentsOptions{
+				Sort:        "created",
+				Direction:   "asc",
+				ListOptions: github.ListOptions{Page: page, PerPage: timelinePageSize},
+			})
+			items := make([]TimelineItem, 0, len(comments))
+			for _, c := range comments {
+				items = append(items, TimelineItem{Type: TimelineReviewComment, Time: c.GetCreatedAt().Time, Actor: c.GetUser().GetLogin(), ReviewComment: c})
+			}
+			return items, res, err
+		}})
+	}
+	return errors.Wrapf(streamTimeline(ctx, sources, opts, fn), "failed to read timeline of %s/%s#%d", owner, repo, number)
+}
+
+func issueTimelineSources(client *github.Client, owner, repo string, number int, opts *TimelineOptions) []*timelineSource {
+	var sources []*timelineSource
+	if opts.includes(TimelineComment) {
+		sources = append(sources, &timelineSource{fetch: func(ctx context.Context, page int) ([]TimelineItem, *github.Response, error) {
+			comments, res, err := client.Issues.ListComments(ctx, owner, repo, number, &github.IssueListCommentsOptions{
+				Sort:        github.String("created"),
+				Direction:   github.String("asc"),
+				ListOptions: github.ListOptions{Page: page, PerPage: timelinePageSize},
+			})
+			items := make([]TimelineItem, 0, len(comments))
+			for _, c := range comments {
+				items = append(items, TimelineItem{Type: TimelineComment, Time: c.GetCreatedAt().Time, Actor: c.GetUser().GetLogin(), Comment: c})
+			}
+			return items, res, err
+		}})
+	}
+	if opts.includes(TimelineEvent) {
+		sources = append(sources, &timelineSource{fetch: func(ctx context.Context, page int) ([]TimelineItem, *github.Response, error) {
+			events, res, err := client.Issues.ListIssueEvents(ctx, owner, repo, number, &github.ListOptions{Page: page, PerPage: timelinePageSize})
+			items := make([]TimelineItem, 0, len(events))
+			for _, e := range events {
+				items = append(items, TimelineItem{Type: TimelineEvent, Time: e.GetCreatedAt().Time, Actor: e.GetActor().GetLogin(), Event: e})
+			}
+			return items, res, err
+		}})
+	}
+	return sources
+}
+
+func commitTimelineItem(c *github.RepositoryCommit) TimelineItem {
+	item := TimelineItem{
+		Type:   TimelineCommit,
+		Time:   c.GetCommit().GetCommitter().GetDate().Time,
+		Actor:  c.GetAuthor().GetLogin(),
+		Commit: c,
+	}
+	if item.Time.IsZero() {
+		item.Time = c.GetCommit().GetAuthor().GetDate().Time
+	}
+	if item.Actor == "" {
+		item.Actor = c.GetCommit().GetAuthor().GetName()
+	}
+	return item
+}
+
+// timelineSource reads the pages of a single endpoint. GitHub returns the
+// items of most endpoints in chronological order; if sorted is true, the
+// source reads all pages and sorts the items by time instead.
+type timelineSource struct {
+	fetch  func(ctx context.Context, page int) ([]TimelineItem, *github.Response, error)
+	sorted bool
+
+	items []TimelineItem
+	page  int
+	done  bool
+}
+
+// fill reads pages until the source has a buffered item or no pages remain.
+// Sorted sources read all pages the first time they are filled.
+func (s *timelineSource) fill(ctx context.Context, opts *TimelineOptions) error {
+	for (len(s.items) == 0 || s.sorted) && !s.done {
+		items, res, err := s.fetchPage(ctx, opts)
+		if err != nil {
+			return err
+		}
+		s.items = append(s.items, items...)
+		if res.NextPage == 0 {
+			s.done = true
+			if s.sorted {
+				slices.SortStableFunc(s.items, func(a, b TimelineItem) int {
+					return a.Time.Compare(b.Time)
+				})
+			}
+		}
+		s.page = res.NextPage
+	}
+	return nil
+}
+
+func (s *timelineSource) fetchPage(ctx context.Context, opts *TimelineOptions) ([]TimelineItem, *github.Response, error) {
+	var maxWait time.Duration
+	if opts != nil {
+		maxWait = opts.MaxRateLimitWait
+	}
+
+	for {
+		items, res, err := s.fetch(ctx, s.page)
+		if err == nil {
+			return items, res, nil
+		}
+
+		err = ClassifyError(err)
+		delay, ok := rateLimitDelay(err, GetClock(ctx).Now())
+		if !ok || delay > maxWait || !GetRetryBudget(ctx).Acquire() {
+			return nil, res, err
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, res, err
+		}
+	}
+}
+
+// streamTimeline merges the items of the sources by time. Items with the
+// same time are returned in the order of the sources.
+func streamTimeline(ctx context.Context, sources []*timelineSource, opts *TimelineOptions, fn TimelineFunc) error {
+	for {
+		var next *timelineSource
+		for _, s := range sources {
+			if err := s.fill(ctx, opts); err != nil {
+				return err
+			}
+			if len(s.items) > 0 && (next == nil || s.items[0].Time.Before(next.items[0].Time)) {
+				next = s
+			}
+		}
+		if next == nil {
+			return nil
+		}
+
+		item := next.items[0]
+		next.items = next.items[1:]
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
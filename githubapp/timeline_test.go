@@ -0,0 +1,159 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestStreamPullRequestTimeline(t *testing.T) {
+	var rateLimitReset time.Time
+	var rateLimited bool
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		// two pages of comments
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+			fmt.Fprint(w, `[{"id": 1, "created_at": "2026-01-01T00:01:00Z", "user": {"login": "alice"}}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id": 2, "created_at": "2026-01-01T00:05:00Z", "user": {"login": "bob"}}]`)
+	})
+	mux.HandleFunc("GET /repos/octo/repo/issues/1/events", func(w http.ResponseWriter, r *http.Request) {
+		if rateLimited {
+			rateLimited = false
+			w.Header().Set("X-RateLimit-Limit", "5000")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(rateLimitReset.Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+			return
+		}
+		fmt.Fprint(w, `[{"id": 3, "event": "labeled", "created_at": "2026-01-01T00:02:00Z", "actor": {"login": "carol"}}]`)
+	})
+	mux.HandleFunc("GET /repos/octo/repo/pulls/1/commits", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"sha": "a", "author": {"login": "alice"}, "commit": {"committer": {"date": "2026-01-01T00:00:00Z"}}},
+			{"sha": "b", "commit": {"author": {"name": "Dave", "date": "2026-01-01T00:03:00Z"}}},
+			{"sha": "c", "author": {"login": "frank"}, "commit": {"committer": {"date": "2026-01-01T00:00:30Z"}}}
+		]`)
+	})
+	mux.HandleFunc("GET /repos/octo/repo/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"id": 4, "state": "APPROVED", "submitted_at": "2026-01-01T00:04:00Z", "user": {"login": "bob"}},
+			{"id": 5, "state": "PENDING", "user": {"login": "erin"}}
+		]`)
+	})
+	mux.HandleFunc("GET /repos/octo/repo/pulls/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": 6, "created_at": "2026-01-01T00:04:00Z", "user": {"login": "bob"}}]`)
+	})
+
+	tests := map[string]struct {
+		Options        *TimelineOptions
+		RateLimitReset time.Duration
+		Expected       []string
+		Err            bool
+	}{
+		"all": {
+			Expected: []string{"commit:alice", "commit:frank", "comment:alice", "event:carol", "commit:Dave", "review:bob", "review_comment:bob", "comment:bob"},
+		},
+		"types": {
+			Options:  &TimelineOptions{Types: []TimelineItemType{TimelineCommit, TimelineReview}},
+			Expected: []string{"commit:alice", "commit:frank", "commit:Dave", "review:bob"},
+		},
+		"rateLimitRetry": {
+			Options:        &TimelineOptions{Types: []TimelineItemType{TimelineEvent}, MaxRateLimitWait: time.Second},
+			RateLimitReset: -time.Second,
+			Expected:       []string{"event:carol"},
+		},
+		"rateLimitError": {
+			Options:        &TimelineOptions{Types: []TimelineItemType{TimelineEvent}, MaxRateLimitWait: time.Second},
+			RateLimitReset: time.Hour,
+			Err:            true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rateLimited = test.RateLimitReset != 0
+			rateLimitReset = time.Now().Add(test.RateLimitReset)
+
+			// clients remember rate limits, so each test needs a new client
+			cc := newStaticClientCreator(t, mux)
+
+			var actual []string
+			err := StreamPullRequestTimeline(context.Background(), cc.client, "octo", "repo", 1, test.Options, func(item TimelineItem) error {
+				actual = append(actual, fmt.Sprintf("%s:%s", item.Type, item.Actor))
+				return nil
+			})
+
+			if test.Err {
+				var rateErr *github.RateLimitError
+				if !errors.As(err, &rateErr) {
+					t.Fatalf("expected rate limit error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(actual, ",") != strings.Join(test.Expected, ",") {
+				t.Errorf("incorrect timeline:\nexpected: %v\n  actual: %v", test.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestStreamIssueTimelineStop(t *testing.T) {
+	requests := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next"`, r.URL.Path, requests+1))
+		fmt.Fprintf(w, `[{"id": %d, "created_at": "2026-01-01T00:00:00Z"}]`, requests)
+	})
+	mux.HandleFunc("GET /repos/octo/repo/issues/1/events", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[]`)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	stop := errors.New("stop")
+	count := 0
+	err := StreamIssueTimeline(context.Background(), cc.client, "octo", "repo", 1, nil, func(item TimelineItem) error {
+		count++
+		if count == 2 {
+			return stop
+		}
+		return nil
+	})
+
+	if errors.Cause(err) != stop {
+		t.Fatalf("expected stop error, but got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("incorrect number of requests: expected 2, actual %d", requests)
+	}
+}
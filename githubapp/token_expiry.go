@@ -0,0 +1,89 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+// installationTokenTransport is the outermost transport of installation
+// clients. It does not modify requests, but allows finding the installation
+// transport of a client.
+type installationTokenTransport struct {
+	next         http.RoundTripper
+	installation *installationResult
+}
+
+func (t *installationTokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(r)
+}
+
+// TokenExpiry returns the time when the installation token used by client
+// expires. If the client does not have a token yet or the token expires in
+// less than a minute, the client creates a new token first. Long-running
+// operations that use the token outside of the client, like cloning a large
+// repository, can use this to decide if they need a new token with
+// RefreshToken before starting.
+//
+// The client must be an installation client created by NewClientCreator or a
+// caching client creator that wraps one.
+func TokenExpiry(ctx context.Context, client *github.Client) (time.Time, error) {
+	rt, err := installationTransport(client)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return rt.expiry(ctx)
+}
+
+// RefreshToken replaces the installation token used by client with a new
+// token and returns when the new token expires. Requests made with the client
+// after RefreshToken returns use the new token. The client must be an
+// installation client, as described by TokenExpiry.
+func RefreshToken(ctx context.Context, client *github.Client) (time.Time, error) {
+	rt, err := installationTransport(client)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := rt.invalidate(rt.current()); err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to replace installation token")
+	}
+	return rt.expiry(ctx)
+}
+
+func installationTransport(client *github.Client) (*refreshingTransport, error) {
+	t, ok := client.Client().Transport.(*installationTokenTransport)
+	if !ok || t.installation.transport == nil {
+		return nil, errors.New("client is not an installation client")
+	}
+	return t.installation.transport, nil
+}
+
+func (t *refreshingTransport) expiry(ctx context.Context) (time.Time, error) {
+	itr := t.current()
+	if _, err := itr.Token(ctx); err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get installation token")
+	}
+
+	expiresAt, _, err := itr.Expiry()
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to get installation token expiry")
+	}
+	return expiresAt, nil
+}
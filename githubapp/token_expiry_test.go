@@ -0,0 +1,82 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenExpiry(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now().Truncate(time.Second)
+
+	var tokens int
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, tokens, start.Add(time.Duration(tokens)*time.Hour).Format(time.RFC3339))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	delegate := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t))
+	cc, err := NewCachingClientCreator(delegate, DefaultCachingClientCapacity)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client, err := cc.NewInstallationClient(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expiry, err := TokenExpiry(ctx, client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !expiry.Equal(start.Add(time.Hour)) {
+		t.Errorf("incorrect expiry: expected %s, actual %s", start.Add(time.Hour), expiry)
+	}
+
+	// the existing token is reused
+	if _, err := TokenExpiry(ctx, client); err != nil || tokens != 1 {
+		t.Errorf("expected existing token to be reused, but %d tokens were created (err: %v)", tokens, err)
+	}
+
+	expiry, err = RefreshToken(ctx, client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !expiry.Equal(start.Add(2*time.Hour)) || tokens != 2 {
+		t.Errorf("incorrect refreshed expiry: %s (%d tokens)", expiry, tokens)
+	}
+
+	t.Run("appClient", func(t *testing.T) {
+		client, err := cc.NewAppClient()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := TokenExpiry(ctx, client); err == nil {
+			t.Error("expected error for app client, but got nil")
+		}
+	})
+}
@@ -0,0 +1,197 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+// TokenRequest is a request for an installation token sent to a token
+// handler. Owner is required; Repositories and Permissions optionally restrict
+// the scope of the token.
+type TokenRequest struct {
+	Owner        string                          `json:"owner"`
+	Repositories []string                        `json:"repositories,omitempty"`
+	Permissions  *github.InstallationPermissions `json:"permissions,omitempty"`
+}
+
+// TokenResponse is the response sent by a token handler after successfully
+// creating an installation token.
+type TokenResponse struct {
+	Token       string                          `json:"token"`
+	ExpiresAt   time.Time                       `json:"expires_at"`
+	Permissions *github.InstallationPermissions `json:"permissions,omitempty"`
+}
+
+// TokenAuthorizer authenticates the caller of a token handler and decides if
+// the caller may receive a token for the request. It returns an identifier
+// for the caller that is used for auditing or an error if the caller is not
+// authorized. Authorizers may modify the request to further restrict the
+// scope of the created token.
+type TokenAuthorizer func(r *http.Request, req *TokenRequest) (caller string, err error)
+
+// TokenAuditCallback is called after a token handler processes an authorized
+// request. If token creation failed, token is nil and err is non-nil.
+type TokenAuditCallback func(ctx context.Context, caller string, req TokenRequest, token *github.InstallationToken, err error)
+
+// TokenHandlerOption configures properties of a token handler.
+type TokenHandlerOption func(*tokenHandler)
+
+// WithTokenAuditCallback sets the audit callback for a token handler. If not
+// set, the handler uses DefaultTokenAuditCallback.
+func WithTokenAuditCallback(onAudit TokenAuditCallback) TokenHandlerOption {
+	return func(h *tokenHandler) {
+		if onAudit != nil {
+			h.onAudit = onAudit
+		}
+	}
+}
+
+// WithTokenInstallationsService sets the service used to find the
+// installation for a requested owner. By default, the handler queries GitHub
+// for every request using an application client.
+func WithTokenInstallationsService(installs InstallationsService) TokenHandlerOption {
+	return func(h *tokenHandler) {
+		h.installs = installs
+	}
+}
+
+// DefaultTokenAuditCallback logs the caller, the owner, and the scope of every
+// token request.
+func DefaultTokenAuditCallback(ctx context.Context, caller string, req TokenRequest, token *github.InstallationToken, err error) {
+	logger := zerolog.Ctx(ctx)
+
+	var evt *zerolog.Event
+	if err != nil {
+		evt = logger.Warn().Err(err)
+	} else {
+		evt = logger.Info().Time("expires_at", token.GetExpiresAt().Time)
+	}
+
+	evt.Str("caller", caller).
+		Str("owner", req.Owner).
+		Strs("repositories", req.Repositories).
+		Interface("permissions", req.Permissions).
+		Msg("github_token_request")
+}
+
+type tokenHandler struct {
+	cc        ClientCreator
+	authorize TokenAuthorizer
+	installs  InstallationsService
+	onAudit   TokenAuditCallback
+}
+
+// NewTokenHandler returns an http.Handler that creates and returns installation
+// tokens for authorized callers. This is useful when processes that do not
+// have access to the application's private key, like sidecars or CI jobs, need
+// to make requests as the application.
+//
+// The handler accepts POST requests with a JSON-encoded TokenRequest body and
+// responds with a JSON-encoded TokenResponse. Every request is passed to the
+// authorizer, which must authenticate the caller; there is no default
+// authentication. The handler responds with 403 Forbidden if the authorizer
+// returns an error.
+func NewTokenHandler(cc ClientCreator, authorize TokenAuthorizer, opts ...TokenHandlerOption) http.Handler {
+	h := &tokenHandler{
+		cc:        cc,
+		authorize: authorize,
+		onAudit:   DefaultTokenAuditCallback,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *tokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := zerolog.Ctx(ctx)
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req TokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Owner == "" {
+		http.Error(w, "Invalid token request", http.StatusBadRequest)
+		return
+	}
+
+	caller, err := h.authorize(r, &req)
+	if err != nil {
+		logger.Warn().Err(err).Str("owner", req.Owner).Msg("Rejected unauthorized token request")
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	token, err := h.createToken(ctx, req)
+	h.onAudit(ctx, caller, req, token, err)
+
+	if err != nil {
+		if _, ok := errors.Cause(err).(InstallationNotFound); ok {
+			http.Error(w, "No installation found for owner", http.StatusNotFound)
+			return
+		}
+		logger.Error().Err(err).Msg("Unexpected error creating installation token")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(TokenResponse{
+		Token:       token.GetToken(),
+		ExpiresAt:   token.GetExpiresAt().Time,
+		Permissions: token.GetPermissions(),
+	})
+}
+
+func (h *tokenHandler) createToken(ctx context.Context, req TokenRequest) (*github.InstallationToken, error) {
+	client, err := h.cc.NewAppClient()
+	if err != nil {
+		return nil, err
+	}
+
+	installs := h.installs
+	if installs == nil {
+		installs = NewInstallationsService(client)
+	}
+
+	install, err := installs.GetByOwner(ctx, req.Owner)
+	if err != nil {
+		return nil, err
+	}
+
+	token, _, err := client.Apps.CreateInstallationToken(ctx, install.ID, &github.InstallationTokenOptions{
+		Repositories: req.Repositories,
+		Permissions:  req.Permissions,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create token for installation %d", install.ID)
+	}
+	return token, nil
+}
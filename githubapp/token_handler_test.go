@@ -0,0 +1,182 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestTokenHandler(t *testing.T) {
+	var created github.InstallationTokenOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /orgs/{org}/installation", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("org") != "octo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 123, "account": {"login": "octo"}}`)
+	})
+	mux.HandleFunc("GET /users/{user}/installation", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("POST /app/installations/123/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		created = github.InstallationTokenOptions{}
+		if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+			t.Errorf("failed to decode token options: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"token": "ghs_token", "expires_at": "2026-01-01T01:00:00Z", "permissions": {"contents": "read"}}`)
+	})
+	cc := newStaticClientCreator(t, mux)
+
+	authorize := func(r *http.Request, req *TokenRequest) (string, error) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer ci":
+			return "ci", nil
+		case "Bearer restricted":
+			req.Repositories = []string{"app"}
+			req.Permissions = &github.InstallationPermissions{Contents: github.String("read")}
+			return "restricted", nil
+		}
+		return "", errors.New("unknown caller")
+	}
+
+	tests := map[string]struct {
+		Method string
+		Auth   string
+		Body   string
+
+		Status       int
+		Token        string
+		Repositories []string
+		Permissions  *github.InstallationPermissions
+		Audit        string
+	}{
+		"issue": {
+			Auth:   "ci",
+			Body:   `{"owner": "octo"}`,
+			Status: http.StatusOK,
+			Token:  "ghs_token",
+			Audit:  "ci:octo:<nil>",
+		},
+		"scopedByCaller": {
+			Auth:         "ci",
+			Body:         `{"owner": "octo", "repositories": ["app", "lib"], "permissions": {"issues": "write"}}`,
+			Status:       http.StatusOK,
+			Token:        "ghs_token",
+			Repositories: []string{"app", "lib"},
+			Permissions:  &github.InstallationPermissions{Issues: github.String("write")},
+			Audit:        "ci:octo:<nil>",
+		},
+		"scopedByAuthorizer": {
+			Auth:         "restricted",
+			Body:         `{"owner": "octo", "repositories": ["app", "lib"]}`,
+			Status:       http.StatusOK,
+			Token:        "ghs_token",
+			Repositories: []string{"app"},
+			Permissions:  &github.InstallationPermissions{Contents: github.String("read")},
+			Audit:        "restricted:octo:<nil>",
+		},
+		"unauthorized": {
+			Auth:   "unknown",
+			Body:   `{"owner": "octo"}`,
+			Status: http.StatusForbidden,
+		},
+		"invalidBody": {
+			Auth:   "ci",
+			Body:   `{"owner": `,
+			Status: http.StatusBadRequest,
+		},
+		"missingOwner": {
+			Auth:   "ci",
+			Body:   `{}`,
+			Status: http.StatusBadRequest,
+		},
+		"wrongMethod": {
+			Method: http.MethodGet,
+			Auth:   "ci",
+			Status: http.StatusMethodNotAllowed,
+		},
+		"unknownOwner": {
+			Auth:   "ci",
+			Body:   `{"owner": "other"}`,
+			Status: http.StatusNotFound,
+			Audit:  `ci:other:no installation found for "other"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			created = github.InstallationTokenOptions{}
+
+			var audit string
+			h := NewTokenHandler(cc, authorize, WithTokenAuditCallback(func(ctx context.Context, caller string, req TokenRequest, token *github.InstallationToken, err error) {
+				audit = fmt.Sprintf("%s:%s:%v", caller, req.Owner, err)
+			}))
+
+			method := test.Method
+			if method == "" {
+				method = http.MethodPost
+			}
+			r := httptest.NewRequest(method, "/api/github/token", strings.NewReader(test.Body))
+			r.Header.Set("Authorization", "Bearer "+test.Auth)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != test.Status {
+				t.Fatalf("expected status %d, but got %d: %s", test.Status, w.Code, w.Body.String())
+			}
+			if audit != test.Audit {
+				t.Errorf("expected audit %q, but got %q", test.Audit, audit)
+			}
+			if test.Status != http.StatusOK {
+				return
+			}
+
+			if cc := w.Header().Get("Cache-Control"); cc != "no-store" {
+				t.Errorf("expected Cache-Control no-store, but got %q", cc)
+			}
+
+			var res TokenResponse
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if res.Token != test.Token {
+				t.Errorf("expected token %q, but got %q", test.Token, res.Token)
+			}
+			if res.ExpiresAt.IsZero() {
+				t.Error("expected token expiry in response")
+			}
+			if !reflect.DeepEqual(created.Repositories, test.Repositories) {
+				t.Errorf("expected repositories %v, but got %v", test.Repositories, created.Repositories)
+			}
+			if !reflect.DeepEqual(created.Permissions, test.Permissions) {
+				t.Errorf("expected permissions %v, but got %v", test.Permissions, created.Permissions)
+			}
+		})
+	}
+}
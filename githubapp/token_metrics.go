@@ -0,0 +1,92 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	MetricsKeyAppJWTSigned  = "github.auth.jwt.signed"
+	MetricsKeyAppJWTLatency = "github.auth.jwt.latency"
+	MetricsKeyAppJWTErrors  = "github.auth.jwt.errors"
+
+	MetricsKeyTokenMints     = "github.auth.token.mints"
+	MetricsKeyTokenLatency   = "github.auth.token.latency"
+	MetricsKeyTokenCacheHits = "github.auth.token.cache_hits"
+	MetricsKeyTokenErrors    = "github.auth.token.errors"
+)
+
+// metricsSigner records the number and latency of signed application JWTs.
+type metricsSigner struct {
+	signer   ghinstallation.Signer
+	registry metrics.Registry
+}
+
+func (s *metricsSigner) Sign(claims jwt.Claims) (string, error) {
+	start := time.Now()
+	token, err := s.signer.Sign(claims)
+	if err != nil {
+		metrics.GetOrRegisterCounter(MetricsKeyAppJWTErrors, s.registry).Inc(1)
+		return token, err
+	}
+	metrics.GetOrRegisterCounter(MetricsKeyAppJWTSigned, s.registry).Inc(1)
+	metrics.GetOrRegisterTimer(MetricsKeyAppJWTLatency, s.registry).UpdateSince(start)
+	return token, nil
+}
+
+// tokenMetrics creates middleware that records requests that create
+// installation tokens. It must be beneath the transport that mints tokens.
+func tokenMetrics(registry metrics.Registry) ClientMiddleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/access_tokens") {
+				return next.RoundTrip(r)
+			}
+
+			start := time.Now()
+			res, err := next.RoundTrip(r)
+
+			switch {
+			case err != nil:
+				metrics.GetOrRegisterCounter(MetricsKeyTokenErrors+"[status:error]", registry).Inc(1)
+			case res.StatusCode/100 != 2:
+				metrics.GetOrRegisterCounter(fmt.Sprintf("%s[status:%d]", MetricsKeyTokenErrors, res.StatusCode), registry).Inc(1)
+			default:
+				metrics.GetOrRegisterCounter(MetricsKeyTokenMints, registry).Inc(1)
+				metrics.GetOrRegisterTimer(MetricsKeyTokenLatency, registry).UpdateSince(start)
+			}
+			return res, err
+		})
+	}
+}
+
+// recordTokenUse increments the cache hit counter if itr has a token that it
+// will use at now for the next request without minting a new one.
+func recordTokenUse(registry metrics.Registry, itr *ghinstallation.Transport, now time.Time) {
+	if registry == nil {
+		return
+	}
+	if _, refreshAt, err := itr.Expiry(); err == nil && now.Before(refreshAt) {
+		metrics.GetOrRegisterCounter(MetricsKeyTokenCacheHits, registry).Inc(1)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestTokenMetrics(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /app/installations/1/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-1", "expires_at": %q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("POST /app/installations/2/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("GET /repos/octo/repo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	registry := metrics.NewRegistry()
+	cc := NewClientCreator(srv.URL+"/", srv.URL+"/graphql", 1, newTestPrivateKey(t), WithTokenMetrics(registry))
+
+	client, err := cc.NewInstallationClient(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := client.Repositories.Get(context.Background(), "octo", "repo"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	failing, err := cc.NewInstallationClient(2)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+	if _, _, err := failing.Repositories.Get(context.Background(), "octo", "repo"); err == nil {
+		t.Fatal("expected error, but got nil")
+	}
+
+	counters := map[string]int64{
+		MetricsKeyAppJWTSigned:                 1,
+		MetricsKeyTokenMints:                   1,
+		MetricsKeyTokenCacheHits:               1,
+		MetricsKeyTokenErrors + "[status:404]": 1,
+	}
+	for key, expected := range counters {
+		c, ok := registry.Get(key).(metrics.Counter)
+		if !ok {
+			t.Errorf("missing counter %s", key)
+			continue
+		}
+		if c.Count() != expected {
+			t.Errorf("incorrect value for %s: expected %d, actual %d", key, expected, c.Count())
+		}
+	}
+
+	if timer, ok := registry.Get(MetricsKeyTokenLatency).(metrics.Timer); !ok || timer.Count() != 1 {
+		t.Errorf("incorrect token latency timer: %v", registry.Get(MetricsKeyTokenLatency))
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/rs/zerolog"
+)
+
+const (
+	MetricsKeyUnhandledEvent = "github.handler.unhandled"
+)
+
+// WithUnhandledEventStatus sets the status code of responses to events that
+// have no handler, like http.StatusNoContent or http.StatusNotFound. These
+// responses do not use the response callback. By default, the response
+// callback is called for these events and DefaultResponseCallback responds
+// with 202 Accepted. Ping events are always passed to the response callback.
+func WithUnhandledEventStatus(status int) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.unhandled.status = status
+	}
+}
+
+// WithUnhandledEventMetrics counts events that have no handler in the
+// registry, tagged with the event type. Use it to notice when GitHub sends
+// event types the application does not handle, for example after changing
+// the app's event subscriptions.
+func WithUnhandledEventMetrics(r metrics.Registry) DispatcherOption {
+	return func(d *eventDispatcher) {
+		d.unhandled.registry = r
+	}
+}
+
+type unhandledEvents struct {
+	status   int
+	registry metrics.Registry
+
+	// seen tracks the event types that were logged, so each type is logged
+	// once instead of for every delivery
+	seen sync.Map
+}
+
+func (u *unhandledEvents) record(ctx context.Context, eventType string) {
+	if _, loaded := u.seen.LoadOrStore(eventType, true); !loaded {
+		zerolog.Ctx(ctx).Info().Msgf("Received %s event, but no handler is registered for this event type", eventType)
+	}
+	if u.registry != nil {
+		key := fmt.Sprintf("%s[event:%s]", MetricsKeyUnhandledEvent, eventType)
+		metrics.GetOrRegisterCounter(key, u.registry).Inc(1)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+func TestUnhandledEventMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	h := &TestEventHandler{Types: []string{"pull_request"}}
+	d := NewEventDispatcher([]EventHandler{h}, testHookSecret, WithUnhandledEventMetrics(registry))
+
+	for _, event := range []string{"pull_request", "issues", "issues", "label", "ping"} {
+		d.ServeHTTP(httptest.NewRecorder(), newHookRequest(event, "delivery-id", true))
+	}
+
+	expected := map[string]int64{
+		"github.handler.unhandled[event:issues]": 2,
+		"github.handler.unhandled[event:label]":  1,
+	}
+
+	actual := make(map[string]int64)
+	registry.Each(func(name string, m interface{}) {
+		actual[name] = m.(metrics.Counter).Count()
+	})
+
+	if len(actual) != len(expected) {
+		t.Errorf("incorrect metrics: expected %v, actual %v", expected, actual)
+	}
+	for name, count := range expected {
+		if actual[name] != count {
+			t.Errorf("incorrect count for %s: expected %d, actual %d", name, count, actual[name])
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrWaitTimeout is returned by WaitFor when the condition is not met
+	// before the backoff timeout expires.
+	ErrWaitTimeout = errors.New("condition was not met before the timeout")
+)
+
+// DefaultBackoff is the backoff used by WaitFor for fields that are not set.
+var DefaultBackoff = Backoff{
+	InitialDelay: 500 * time.Millisecond,
+	MaxDelay:     10 * time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+	Timeout:      2 * time.Minute,
+}
+
+// Backoff configures the delays between checks in WaitFor. Fields with zero
+// values use the value from DefaultBackoff.
+type Backoff struct {
+	// InitialDelay is the delay after the first check.
+	InitialDelay time.Duration
+
+	// MaxDelay is the longest delay between checks, not including delays
+	// requested by GitHub because of rate limits.
+	MaxDelay time.Duration
+
+	// Multiplier is the factor applied to the delay after each check.
+	Multiplier float64
+
+	// Jitter is the fraction by which each delay is randomly increased or
+	// decreased, so that handlers waiting for the same resource do not check
+	// it at the same time. Set a negative value to disable jitter.
+	Jitter float64
+
+	// Timeout is the longest time WaitFor waits for the condition.
+	Timeout time.Duration
+}
+
+func (b Backoff) withDefaults() Backoff {
+	if b.InitialDelay <= 0 {
+		b.InitialDelay = DefaultBackoff.InitialDelay
+	}
+	if b.MaxDelay <= 0 {
+		b.MaxDelay = DefaultBackoff.MaxDelay
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = DefaultBackoff.Multiplier
+	}
+	if b.Jitter == 0 {
+		b.Jitter = DefaultBackoff.Jitter
+	}
+	if b.Timeout <= 0 {
+		b.Timeout = DefaultBackoff.Timeout
+	}
+	return b
+}
+
+func (b Backoff) jitter(d time.Duration) time.Duration {
+	if b.Jitter <= 0 {
+		return d
+	}
+	return time.Duration(float64(d) * (1 + b.Jitter*(2*rand.Float64()-1)))
+}
+
+// delay returns the jittered delay before the retry that follows the given
+// attempt, starting from 1.
+func (b Backoff) delay(attempt int) time.Duration {
+	d := b.InitialDelay
+	for i := 1; i < attempt && d < b.MaxDelay; i++ {
+		d = time.Duration(float64(d) * b.Multiplier)
+	}
+	return b.jitter(min(d, b.MaxDelay))
+}
+
+// WaitCondition reports whether a resource has reached the state a handler
+// is waiting for.
+type WaitCondition func(ctx context.Context) (bool, error)
+
+// WaitFor calls cond until it returns true, with increasing delays between
+// calls. Many GitHub resources are eventually consistent: check runs may not
+// be listed immediately after a push, and a new pull request's refs may not
+// be visible right after the pull_request event. Use WaitFor in place of
+// polling loops in handlers that depend on these resources.
+//
+// If cond returns a not found error, WaitFor treats the resource as not yet
+// visible and checks again. If cond returns a rate limit error, WaitFor
+// waits until GitHub allows more requests before checking again, or returns
+// the error if that is after the timeout. WaitFor also waits for the reset of
+// the core rate limit recorded in the context by RateLimits, if no requests
+// remain. Checking again after an error uses a retry from the RetryBudget of
+// the context, and WaitFor returns the error if the budget is exhausted. All
+// other errors are returned immediately.
+//
+// WaitFor returns ErrWaitTimeout if the condition is not met before the
+// timeout and the context error if the context is canceled.
+func WaitFor(ctx context.Context, cond WaitCondition, backoff Backoff) error {
+	b := backoff.withDefaults()
+	clock := GetClock(ctx)
+	deadline := clock.Now().Add(b.Timeout)
+	delay := b.InitialDelay
+
+	for attempts := 1; ; attempts++ {
+		done, err := cond(ctx)
+		if err == nil && done {
+			return nil
+		}
+
+		wait := b.jitter(delay)
+		if err != nil {
+			retryAfter, ok := waitRetryDelay(err, clock.Now())
+			if !ok || !GetRetryBudget(ctx).Acquire() {
+				return err
+			}
+			if retryAfter > wait {
+				if clock.Now().Add(retryAfter).After(deadline) {
+					return err
+				}
+				wait = retryAfter
+			}
+		}
+		now := clock.Now()
+		if reset := coreRateLimitReset(ctx); reset.Sub(now) > wait {
+			wait = reset.Sub(now)
+		}
+
+		if now.Add(wait).After(deadline) {
+			return errors.Wrapf(ErrWaitTimeout, "after %d attempts", attempts)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+
+		delay = min(time.Duration(float64(delay)*b.Multiplier), b.MaxDelay)
+	}
+}
+
+// waitRetryDelay returns the minimum delay before checking a condition that
+// failed with err again and false if the error is not retryable.
+func waitRetryDelay(err error, now time.Time) (time.Duration, bool) {
+	err = ClassifyError(err)
+
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		return 0, true
+	}
+	return rateLimitDelay(err, now)
+}
+
+// rateLimitDelay returns how long to wait before retrying a request that
+// failed with err and false if the error is not caused by a rate limit. The
+// error must be classified with ClassifyError.
+func rateLimitDelay(err error, now time.Time) (time.Duration, bool) {
+	var secondary *SecondaryRateLimitError
+	if errors.As(err, &secondary) {
+		if secondary.RetryAfter > 0 {
+			return secondary.RetryAfter, true
+		}
+		// GitHub recommends waiting at least a minute if it does not say how long
+		return time.Minute, true
+	}
+
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		return rateErr.Rate.Reset.Time.Sub(now), true
+	}
+
+	return 0, false
+}
+
+// coreRateLimitReset returns the reset time of the core rate limit recorded
+// in the context if no requests remain, or the zero time otherwise.
+func coreRateLimitReset(ctx context.Context) time.Time {
+	core, ok := RateLimits(ctx)[RateLimitResourceCore]
+	if !ok || core.Limit == 0 || core.Remaining > 0 {
+		return time.Time{}
+	}
+	return core.Reset
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+)
+
+func TestWaitFor(t *testing.T) {
+	backoff := Backoff{
+		InitialDelay: time.Millisecond,
+		MaxDelay:     5 * time.Millisecond,
+		Jitter:       -1,
+		Timeout:      500 * time.Millisecond,
+	}
+
+	notReady := errors.New("not ready")
+	notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	serverError := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusInternalServerError}}
+
+	retryAfter := 10 * time.Millisecond
+	secondary := &github.AbuseRateLimitError{
+		Response:   &http.Response{StatusCode: http.StatusForbidden},
+		RetryAfter: &retryAfter,
+	}
+
+	tests := map[string]struct {
+		Results  []error
+		Backoff  Backoff
+		Budget   *RetryBudget
+		Err      error
+		Attempts int
+	}{
+		"immediate": {
+			Results:  []error{nil},
+			Attempts: 1,
+		},
+		"eventual": {
+			Results:  []error{notReady, notReady, nil},
+			Attempts: 3,
+		},
+		"notFound": {
+			Results:  []error{notFound, notFound, nil},
+			Attempts: 3,
+		},
+		"secondaryRateLimit": {
+			Results:  []error{secondary, nil},
+			Attempts: 2,
+		},
+		"primaryRateLimitAfterTimeout": {
+			Results: []error{&github.RateLimitError{
+				Rate:     github.Rate{Reset: github.Timestamp{Time: time.Now().Add(time.Hour)}},
+				Response: &http.Response{StatusCode: http.StatusForbidden},
+			}},
+			Err:      &github.RateLimitError{},
+			Attempts: 1,
+		},
+		"otherError": {
+			Results:  []error{notFound, serverError},
+			Err:      serverError,
+			Attempts: 2,
+		},
+		"exhaustedBudget": {
+			Results:  []error{notFound, notFound, nil},
+			Budget:   NewRetryBudget(1),
+			Err:      notFound,
+			Attempts: 2,
+		},
+		"pollingIgnoresBudget": {
+			Results:  []error{notReady, notReady, nil},
+			Budget:   NewRetryBudget(0),
+			Attempts: 3,
+		},
+		"timeout": {
+			Backoff: Backoff{
+				InitialDelay: 10 * time.Millisecond,
+				Jitter:       -1,
+				Timeout:      25 * time.Millisecond,
+			},
+			Err:      ErrWaitTimeout,
+			Attempts: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			cond := func(ctx context.Context) (bool, error) {
+				attempts++
+				if len(test.Results) == 0 {
+					return false, nil
+				}
+
+				err := test.Results[0]
+				if len(test.Results) > 1 {
+					test.Results = test.Results[1:]
+				}
+				switch {
+				case err == nil:
+					return true, nil
+				case err == notReady:
+					return false, nil
+				}
+				return false, err
+			}
+
+			b := backoff
+			if test.Backoff != (Backoff{}) {
+				b = test.Backoff
+			}
+
+			ctx := context.Background()
+			if test.Budget != nil {
+				ctx = WithRetryBudget(ctx, test.Budget)
+			}
+
+			err := WaitFor(ctx, cond, b)
+			switch target := test.Err.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			case *github.RateLimitError:
+				if !errors.As(err, &target) {
+					t.Fatalf("expected rate limit error, but got %v", err)
+				}
+			default:
+				if errors.Cause(err) != test.Err {
+					t.Fatalf("expected error %v, but got %v", test.Err, err)
+				}
+			}
+
+			if attempts != test.Attempts {
+				t.Errorf("expected %d attempts, but got %d", test.Attempts, attempts)
+			}
+		})
+	}
+}
+
+func TestWaitForCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	err := WaitFor(ctx, func(ctx context.Context) (bool, error) {
+		attempts++
+		cancel()
+		return false, nil
+	}, Backoff{InitialDelay: time.Minute, Timeout: time.Hour})
+
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, but got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, but got %d", attempts)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+)
+
+const (
+	WebhookContentTypeJSON = "json"
+	WebhookContentTypeForm = "form"
+)
+
+// WebhookSettings are the desired settings of a webhook.
+type WebhookSettings struct {
+	// URL is the address that receives webhook deliveries.
+	URL string
+
+	// Secret is the secret used to sign deliveries. GitHub never returns
+	// secrets, so changes that only modify the secret are not detected.
+	// Use Force to update a webhook after rotating the secret.
+	Secret string
+
+	// ContentType is the format of deliveries, either "json" or "form". If
+	// empty, it defaults to "json".
+	ContentType string
+
+	// InsecureSSL disables certificate verification for deliveries.
+	InsecureSSL bool
+
+	// Force updates the webhook even if the visible settings match.
+	Force bool
+}
+
+// WebhookSettingsFromConfig returns settings that deliver JSON payloads to
+// url, signed with the webhook secret in the configuration.
+func WebhookSettingsFromConfig(c Config, url string) WebhookSettings {
+	return WebhookSettings{
+		URL:         url,
+		Secret:      c.App.WebhookSecret,
+		ContentType: WebhookContentTypeJSON,
+	}
+}
+
+func (s WebhookSettings) hookConfig() *github.HookConfig {
+	contentType := s.ContentType
+	if contentType == "" {
+		contentType = WebhookContentTypeJSON
+	}
+
+	insecureSSL := "0"
+	if s.InsecureSSL {
+		insecureSSL = "1"
+	}
+
+	hc := &github.HookConfig{
+		URL:         &s.URL,
+		ContentType: &contentType,
+		InsecureSSL: &insecureSSL,
+	}
+	if s.Secret != "" {
+		hc.Secret = &s.Secret
+	}
+	return hc
+}
+
+// matches returns true if the visible settings of the existing configuration
+// match the desired configuration.
+func (s WebhookSettings) matches(existing *github.HookConfig) bool {
+	if s.Force || existing == nil {
+		return false
+	}
+
+	desired := s.hookConfig()
+	if existing.GetURL() != desired.GetURL() ||
+		existing.GetContentType() != desired.GetContentType() ||
+		existing.GetInsecureSSL() != desired.GetInsecureSSL() {
+		return false
+	}
+
+	// GitHub obfuscates secrets, but does not return a value if none is set
+	return (existing.GetSecret() != "") == (desired.GetSecret() != "")
+}
+
+// ConfigureAppWebhook updates the webhook configuration of the application
+// to match the settings. It returns true if the configuration was changed
+// and false if it already matched. Infrastructure-as-code deployments can
+// call it at startup to converge the webhook with the same Config used by
+// the server.
+func ConfigureAppWebhook(ctx context.Context, cc ClientCreator, settings WebhookSettings) (bool, error) {
+	client, err := cc.NewAppClient()
+	if err != nil {
+		return false, err
+	}
+
+	existing, _, err := client.Apps.GetHookConfig(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get app webhook configuration")
+	}
+	if settings.matches(existing) {
+		return false, nil
+	}
+
+	if _, _, err := client.Apps.UpdateHookConfig(ctx, settings.hookConfig()); err != nil {
+		return false, errors.Wrap(err, "failed to update app webhook configuration")
+	}
+
+	zerolog.Ctx(ctx).Info().Msgf("Updated app webhook configuration for %s", settings.URL)
+	return true, nil
+}
+
+// ConfigureRepositoryWebhook creates or updates the webhook in a repository
+// with the same URL as the settings so that it is active and delivers the
+// given events. It returns true if the webhook was created or changed.
+// Applications only need repository webhooks for events that are not
+// delivered to GitHub Apps.
+func ConfigureRepositoryWebhook(ctx context.Context, client *github.Client, owner, repo string, settings WebhookSettings, events ...string) (bool, error) {
+	return configureWebhook(ctx, settings, events, hooksAPI{
+		list: func(opts *github.ListOptions) ([]*github.Hook, *github.Response, error) {
+			return client.Repositories.ListHooks(ctx, owner, repo, opts)
+		},
+		create: func(hook *github.Hook) error {
+			_, _, err := client.Repositories.CreateHook(ctx, owner, repo, hook)
+			return err
+		},
+		edit: func(id int64, hook *github.Hook) error {
+			_, _, err := client.Repositories.EditHook(ctx, owner, repo, id, hook)
+			return err
+		},
+		target: owner + "/" + repo,
+	})
+}
+
+// ConfigureOrganizationWebhook creates or updates the webhook in an
+// organization with the same URL as the settings so that it is active and
+// delivers the given events. It returns true if the webhook was created or
+// changed.
+func ConfigureOrganizationWebhook(ctx context.Context, client *github.Client, org string, settings WebhookSettings, events ...string) (bool, error) {
+	return configureWebhook(ctx, settings, events, hooksAPI{
+		list: func(opts *github.ListOptions) ([]*github.Hook, *github.Response, error) {
+			return client.Organizations.ListHooks(ctx, org, opts)
+		},
+		create: func(hook *github.Hook) error {
+			_, _, err := client.Organizations.CreateHook(ctx, org, hook)
+			return err
+		},
+		edit: func(id int64, hook *github.Hook) error {
+			_, _, err := client.Organizations.EditHook(ctx, org, id, hook)
+			return err
+		},
+		target: org,
+	})
+}
+
+// hooksAPI abstracts the differences between repository and organization
+// webhooks.
+type hooksAPI struct {
+	list   func(opts *github.ListOptions) ([]*github.Hook, *github.Response, error)
+	create func(hook *github.Hook) error
+	edit   func(id int64, hook *github.Hook) error
+	target string
+}
+
+func configureWebhook(ctx context.Context, settings WebhookSettings, events []string, api hooksAPI) (bool, error) {
+	existing, err := findWebhook(api, settings.URL)
+	if err != nil {
+		return false, err
+	}
+
+	events = slices.Clone(events)
+	slices.Sort(events)
+
+	hook := &github.Hook{
+		Config: settings.hookConfig(),
+		Events: events,
+		Active: github.Bool(true),
+	}
+
+	logger := zerolog.Ctx(ctx)
+	if existing == nil {
+		if err := api.create(hook); err != nil {
+			return false, errors.Wrapf(err, "failed to create webhook for %s", api.target)
+		}
+		logger.Info().Msgf("Created webhook for %s", api.target)
+		return true, nil
+	}
+
+	existingEvents := slices.Clone(existing.Events)
+	slices.Sort(existingEvents)
+
+	if existing.GetActive() && slices.Equal(existingEvents, events) && settings.matches(existing.Config) {
+		return false, nil
+	}
+
+	if err := api.edit(existing.GetID(), hook); err != nil {
+		return false, errors.Wrapf(err, "failed to update webhook %d for %s", existing.GetID(), api.target)
+	}
+	logger.Info().Msgf("Updated webhook %d for %s", existing.GetID(), api.target)
+	return true, nil
+}
+
+func findWebhook(api hooksAPI, url string) (*github.Hook, error) {
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		hooks, res, err := api.list(opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list webhooks for %s", api.target)
+		}
+		for _, hook := range hooks {
+			if hook.GetConfig().GetURL() == url {
+				return hook, nil
+			}
+		}
+		if res.NextPage == 0 {
+			return nil, nil
+		}
+		opts.Page = res.NextPage
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestConfigureAppWebhook(t *testing.T) {
+	tests := map[string]struct {
+		Existing string
+		Settings WebhookSettings
+		Updated  bool
+	}{
+		"matching": {
+			Existing: `{"url": "https://example.com/hook", "content_type": "json", "insecure_ssl": "0", "secret": "********"}`,
+			Settings: WebhookSettings{URL: "https://example.com/hook", Secret: "secret"},
+		},
+		"differentURL": {
+			Existing: `{"url": "https://old.example.com/hook", "content_type": "json", "insecure_ssl": "0", "secret": "********"}`,
+			Settings: WebhookSettings{URL: "https://example.com/hook", Secret: "secret"},
+			Updated:  true,
+		},
+		"differentContentType": {
+			Existing: `{"url": "https://example.com/hook", "content_type": "form", "insecure_ssl": "0", "secret": "********"}`,
+			Settings: WebhookSettings{URL: "https://example.com/hook", Secret: "secret"},
+			Updated:  true,
+		},
+		"missingSecret": {
+			Existing: `{"url": "https://example.com/hook", "content_type": "json", "insecure_ssl": "0"}`,
+			Settings: WebhookSettings{URL: "https://example.com/hook", Secret: "secret"},
+			Updated:  true,
+		},
+		"force": {
+			Existing: `{"url": "https://example.com/hook", "content_type": "json", "insecure_ssl": "0", "secret": "********"}`,
+			Settings: WebhookSettings{URL: "https://example.com/hook", Secret: "secret", Force: true},
+			Updated:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var patched *github.HookConfig
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /app/hook/config", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(test.Existing))
+			})
+			mux.HandleFunc("PATCH /app/hook/config", func(w http.ResponseWriter, r *http.Request) {
+				patched = new(github.HookConfig)
+				if err := json.NewDecoder(r.Body).Decode(patched); err != nil {
+					t.Errorf("invalid request body: %v", err)
+				}
+				_, _ = w.Write([]byte(`{}`))
+			})
+
+			updated, err := ConfigureAppWebhook(context.Background(), newStaticClientCreator(t, mux), test.Settings)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated != test.Updated {
+				t.Errorf("incorrect update result: expected %t, actual %t", test.Updated, updated)
+			}
+			if updated {
+				if patched == nil {
+					t.Fatal("expected configuration to be updated, but it was not")
+				}
+				if patched.GetURL() != test.Settings.URL || patched.GetContentType() != "json" || patched.GetSecret() != test.Settings.Secret {
+					t.Errorf("incorrect configuration: %+v", patched)
+				}
+			} else if patched != nil {
+				t.Errorf("expected no update, but got %+v", patched)
+			}
+		})
+	}
+}
+
+func TestConfigureRepositoryWebhook(t *testing.T) {
+	ctx := context.Background()
+	settings := WebhookSettings{URL: "https://example.com/hook", Secret: "secret"}
+
+	var hooks []*github.Hook
+	var created, edited int
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/octo/repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(hooks)
+	})
+	mux.HandleFunc("POST /repos/octo/repo/hooks", func(w http.ResponseWriter, r *http.Request) {
+		created++
+		hook := new(github.Hook)
+		_ = json.NewDecoder(r.Body).Decode(hook)
+		hook.ID = github.Int64(1)
+		hook.Config.Secret = github.String("********")
+		hooks = append(hooks, hook)
+		_ = json.NewEncoder(w).Encode(hook)
+	})
+	mux.HandleFunc("PATCH /repos/octo/repo/hooks/1", func(w http.ResponseWriter, r *http.Request) {
+		edited++
+		hook := new(github.Hook)
+		_ = json.NewDecoder(r.Body).Decode(hook)
+		hooks[0].Events = hook.Events
+		_ = json.NewEncoder(w).Encode(hooks[0])
+	})
+	client := newStaticClientCreator(t, mux).client
+
+	changed, err := ConfigureRepositoryWebhook(ctx, client, "octo", "repo", settings, "push", "fork")
+	if err != nil || !changed {
+		t.Fatalf("expected webhook to be created, but got %t, %v", changed, err)
+	}
+
+	changed, err = ConfigureRepositoryWebhook(ctx, client, "octo", "repo", settings, "fork", "push")
+	if err != nil || changed {
+		t.Fatalf("expected no changes, but got %t, %v", changed, err)
+	}
+
+	changed, err = ConfigureRepositoryWebhook(ctx, client, "octo", "repo", settings, "push")
+	if err != nil || !changed {
+		t.Fatalf("expected webhook to be updated, but got %t, %v", changed, err)
+	}
+
+	if created != 1 || edited != 1 {
+		t.Errorf("incorrect calls: %d creates, %d edits", created, edited)
+	}
+}
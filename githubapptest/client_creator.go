@@ -0,0 +1,194 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// Request is a request received by a FakeClientCreator's server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// FakeClientCreator is a githubapp.ClientCreator whose clients send requests
+// to an in-process server instead of GitHub. The server responds using
+// programmable routes and records all requests so that tests can make
+// assertions about the calls made by a handler.
+//
+// Clients set an Authorization header identifying the type of client that
+// made the request: "app" for application clients, "installation <id>" for
+// installation clients, and "token <token>" for token clients.
+type FakeClientCreator struct {
+	Server *httptest.Server
+
+	mu       sync.Mutex
+	requests []Request
+}
+
+var _ githubapp.ClientCreator = &FakeClientCreator{}
+
+// NewFakeClientCreator starts a server that responds to requests using routes
+// and returns a FakeClientCreator that creates clients for the server. Route
+// keys are http.ServeMux patterns, like "GET /repos/{owner}/{repo}/pulls/{number}".
+// V4 (GraphQL) requests are sent to "POST /graphql". Requests that do not
+// match a route receive a 404 Not Found response.
+//
+// Callers must call Close when finished to stop the server.
+func NewFakeClientCreator(routes map[string]http.Handler) *FakeClientCreator {
+	mux := http.NewServeMux()
+	for pattern, h := range routes {
+		mux.Handle(pattern, h)
+	}
+
+	f := &FakeClientCreator{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		f.mu.Lock()
+		f.requests = append(f.requests, Request{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		f.mu.Unlock()
+
+		mux.ServeHTTP(w, r)
+	}))
+	return f
+}
+
+// JSONResponse returns a handler that responds with the given status and the
+// JSON encoding of v.
+func JSONResponse(status int, v interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	})
+}
+
+// Close stops the server.
+func (f *FakeClientCreator) Close() {
+	f.Server.Close()
+}
+
+// Requests returns all requests received by the server in the order they
+// were received.
+func (f *FakeClientCreator) Requests() []Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Request(nil), f.requests...)
+}
+
+// Reset clears all recorded requests.
+func (f *FakeClientCreator) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = nil
+}
+
+func (f *FakeClientCreator) NewAppClient() (*github.Client, error) {
+	return f.newClient("app")
+}
+
+func (f *FakeClientCreator) NewAppV4Client() (*githubv4.Client, error) {
+	return f.newV4Client("app")
+}
+
+func (f *FakeClientCreator) NewInstallationClient(installationID int64) (*github.Client, error) {
+	return f.newClient(fmt.Sprintf("installation %d", installationID))
+}
+
+func (f *FakeClientCreator) NewInstallationV4Client(installationID int64) (*githubv4.Client, error) {
+	return f.newV4Client(fmt.Sprintf("installation %d", installationID))
+}
+
+func (f *FakeClientCreator) NewTokenSourceClient(ts oauth2.TokenSource) (*github.Client, error) {
+	token, err := ts.Token()
+	if err != nil {
+		return nil, err
+	}
+	return f.NewTokenClient(token.AccessToken)
+}
+
+func (f *FakeClientCreator) NewTokenSourceV4Client(ts oauth2.TokenSource) (*githubv4.Client, error) {
+	token, err := ts.Token()
+	if err != nil {
+		return nil, err
+	}
+	return f.NewTokenV4Client(token.AccessToken)
+}
+
+func (f *FakeClientCreator) NewTokenClient(token string) (*github.Client, error) {
+	return f.newClient("token " + token)
+}
+
+func (f *FakeClientCreator) NewTokenV4Client(token string) (*githubv4.Client, error) {
+	return f.newV4Client("token " + token)
+}
+
+func (f *FakeClientCreator) newHTTPClient(auth string) *http.Client {
+	next := f.Server.Client().Transport
+	return &http.Client{
+		Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r = r.Clone(r.Context())
+			r.Header.Set("Authorization", auth)
+			return next.RoundTrip(r)
+		}),
+	}
+}
+
+func (f *FakeClientCreator) newClient(auth string) (*github.Client, error) {
+	baseURL, err := url.Parse(f.Server.URL + "/")
+	if err != nil {
+		return nil, err
+	}
+
+	client := github.NewClient(f.newHTTPClient(auth))
+	client.BaseURL = baseURL
+	client.UploadURL = baseURL
+	return client, nil
+}
+
+func (f *FakeClientCreator) newV4Client(auth string) (*githubv4.Client, error) {
+	return githubv4.NewEnterpriseClient(f.Server.URL+"/graphql", f.newHTTPClient(auth)), nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return fn(r)
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+)
+
+func TestFakeClientCreator(t *testing.T) {
+	cc := NewFakeClientCreator(map[string]http.Handler{
+		"GET /repos/{owner}/{repo}/pulls/{number}": JSONResponse(http.StatusOK, &github.PullRequest{
+			Number: github.Int(1),
+			Title:  github.String("Update the README"),
+		}),
+	})
+	defer cc.Close()
+
+	client, err := cc.NewInstallationClient(42)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	pr, _, err := client.PullRequests.Get(context.Background(), "test", "repo", 1)
+	if err != nil {
+		t.Fatalf("unexpected error getting pull request: %v", err)
+	}
+	if pr.GetTitle() != "Update the README" {
+		t.Errorf("incorrect title: %q", pr.GetTitle())
+	}
+
+	_, _, err = client.Issues.Get(context.Background(), "test", "repo", 1)
+	if err == nil {
+		t.Errorf("expected error for unregistered route, but got nil")
+	}
+
+	reqs := cc.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("incorrect number of requests: expected 2, actual %d", len(reqs))
+	}
+	if reqs[0].Path != "/repos/test/repo/pulls/1" {
+		t.Errorf("incorrect path: %q", reqs[0].Path)
+	}
+	if auth := reqs[0].Header.Get("Authorization"); auth != "installation 42" {
+		t.Errorf("incorrect authorization: %q", auth)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/palantir/go-githubapp/githubapp"
+)
+
+// FakeClock is a githubapp.Clock that only moves when the test advances it.
+// Store it in a context with githubapp.WithClock to test installation token
+// expiry, backoff, and other waits without sleeping. A FakeClock is safe for
+// concurrent use.
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	c     chan time.Time
+}
+
+var _ githubapp.Clock = &FakeClock{}
+
+// NewFakeClock creates a FakeClock that starts at now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer returns a timer that fires once the clock advances by at least d.
+// If d is not positive, the timer fires immediately.
+func (c *FakeClock) NewTimer(d time.Duration) githubapp.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, at: c.now.Add(d), c: make(chan time.Time, 1)}
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward by d and fires all timers whose duration
+// elapsed.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(c.now.Add(d))
+}
+
+// Set moves the clock to t and fires all timers whose duration elapsed.
+// Setting a time before the current time does not fire any timers.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(t)
+}
+
+// Waiters returns the number of timers that have not fired or been stopped.
+// Tests can poll it to know when code under test has started waiting before
+// advancing the clock.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+func (c *FakeClock) setLocked(t time.Time) {
+	c.now = t
+
+	waiting := c.timers[:0]
+	for _, timer := range c.timers {
+		if t.Before(timer.at) {
+			waiting = append(waiting, timer)
+			continue
+		}
+		timer.c <- t
+	}
+	clear(c.timers[len(waiting):])
+	c.timers = waiting
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, timer := range c.timers {
+		if timer == t {
+			c.timers = slices.Delete(c.timers, i, i+1)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/palantir/go-githubapp/githubapp"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	short := c.NewTimer(time.Second)
+	long := c.NewTimer(time.Minute)
+	stopped := c.NewTimer(time.Minute)
+	if c.Waiters() != 3 {
+		t.Fatalf("expected 3 waiters, got %d", c.Waiters())
+	}
+	if !stopped.Stop() {
+		t.Error("expected stopping a pending timer to return true")
+	}
+	if stopped.Stop() {
+		t.Error("expected stopping a stopped timer to return false")
+	}
+	if c.Waiters() != 2 {
+		t.Fatalf("expected 2 waiters after stop, got %d", c.Waiters())
+	}
+
+	c.Advance(2 * time.Second)
+	select {
+	case at := <-short.C():
+		if !at.Equal(start.Add(2 * time.Second)) {
+			t.Errorf("incorrect time from short waiter: %v", at)
+		}
+	default:
+		t.Fatal("short waiter did not fire")
+	}
+	select {
+	case <-long.C():
+		t.Fatal("long waiter fired early")
+	default:
+	}
+
+	c.Set(start.Add(time.Hour))
+	select {
+	case <-long.C():
+	default:
+		t.Fatal("long waiter did not fire")
+	}
+	select {
+	case <-stopped.C():
+		t.Fatal("stopped waiter fired")
+	default:
+	}
+	if c.Waiters() != 0 {
+		t.Errorf("expected no waiters, got %d", c.Waiters())
+	}
+}
+
+func TestFakeClockCanceledWait(t *testing.T) {
+	c := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(githubapp.WithClock(context.Background(), c))
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- githubapp.WaitFor(ctx, func(ctx context.Context) (bool, error) {
+			return false, nil
+		}, githubapp.Backoff{Timeout: time.Hour, Jitter: -1})
+	}()
+
+	for c.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+	if c.Waiters() != 0 {
+		t.Errorf("expected canceled wait to stop its timer, got %d waiters", c.Waiters())
+	}
+}
+
+func TestFakeClockWaitFor(t *testing.T) {
+	c := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	ctx := githubapp.WithClock(context.Background(), c)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- githubapp.WaitFor(ctx, func(ctx context.Context) (bool, error) {
+			return false, nil
+		}, githubapp.Backoff{Timeout: time.Minute, Jitter: -1})
+	}()
+
+	// advance the clock each time WaitFor starts waiting until it times out
+	for {
+		select {
+		case err := <-errc:
+			if !errors.Is(err, githubapp.ErrWaitTimeout) {
+				t.Fatalf("expected timeout error, got %v", err)
+			}
+			return
+		default:
+		}
+		if c.Waiters() > 0 {
+			c.Advance(10 * time.Second)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
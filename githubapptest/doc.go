@@ -0,0 +1,20 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package githubapptest provides utilities for testing GitHub applications
+// built with the githubapp package. It can create signed webhook requests
+// from canned payloads and run them through an event dispatcher, create
+// clients that send requests to a fake server, and record and replay real
+// interactions with GitHub.
+package githubapptest
@@ -0,0 +1,160 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/palantir/go-githubapp/githubapp"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+var deliveryCount int64
+
+// Event is a webhook event that can be sent to an event dispatcher.
+type Event struct {
+	Type       string
+	DeliveryID string
+	Payload    []byte
+}
+
+// EventOption modifies the payload of an event created from a fixture.
+type EventOption func(payload map[string]interface{})
+
+// WithAction sets the "action" field of the payload.
+func WithAction(action string) EventOption {
+	return WithField("action", action)
+}
+
+// WithRepository sets the owner and name of the repository in the payload.
+func WithRepository(owner, name string) EventOption {
+	return func(payload map[string]interface{}) {
+		setField(payload, "repository.name", name)
+		setField(payload, "repository.full_name", owner+"/"+name)
+		setField(payload, "repository.owner.login", owner)
+		if _, ok := payload["organization"]; ok {
+			setField(payload, "organization.login", owner)
+		}
+	}
+}
+
+// WithInstallationID sets the ID of the installation in the payload.
+func WithInstallationID(id int64) EventOption {
+	return WithField("installation.id", id)
+}
+
+// WithSender sets the login of the user that triggered the event.
+func WithSender(login string) EventOption {
+	return WithField("sender.login", login)
+}
+
+// WithField sets an arbitrary field in the payload. The path is a sequence of
+// object keys separated by periods, like "pull_request.head.sha". Objects are
+// created as needed.
+func WithField(path string, value interface{}) EventOption {
+	return func(payload map[string]interface{}) {
+		setField(payload, path, value)
+	}
+}
+
+// EventTypes returns the event types that have fixtures.
+func EventTypes() []string {
+	entries, err := fixtures.ReadDir("fixtures")
+	if err != nil {
+		panic(err)
+	}
+
+	var types []string
+	for _, e := range entries {
+		types = append(types, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewEvent creates an event of the given type from a canned payload, applying
+// any options to modify the payload. It panics if there is no fixture for
+// the event type; see EventTypes for the supported types.
+func NewEvent(eventType string, opts ...EventOption) Event {
+	b, err := fixtures.ReadFile("fixtures/" + eventType + ".json")
+	if err != nil {
+		panic(fmt.Sprintf("githubapptest: no fixture for event type %q", eventType))
+	}
+
+	var payload map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		panic(fmt.Sprintf("githubapptest: invalid fixture for event type %q: %v", eventType, err))
+	}
+
+	for _, opt := range opts {
+		opt(payload)
+	}
+
+	b, err = json.Marshal(payload)
+	if err != nil {
+		panic(fmt.Sprintf("githubapptest: failed to marshal payload: %v", err))
+	}
+
+	return Event{
+		Type:       eventType,
+		DeliveryID: fmt.Sprintf("githubapptest-%d", atomic.AddInt64(&deliveryCount, 1)),
+		Payload:    b,
+	}
+}
+
+// NewRequest creates a webhook request for the event, signed with secret.
+func NewRequest(e Event, secret string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, githubapp.DefaultWebhookRoute, bytes.NewReader(e.Payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", e.Type)
+	req.Header.Set("X-GitHub-Delivery", e.DeliveryID)
+	req.Header.Set("X-Hub-Signature-256", githubapp.SignPayload(secret, e.Payload))
+	return req
+}
+
+// Send sends the event to handler, usually an event dispatcher, as a
+// webhook request signed with secret and returns the recorded response.
+func Send(handler http.Handler, e Event, secret string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, NewRequest(e, secret))
+	return res
+}
+
+func setField(payload map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+
+	obj := payload
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := obj[k].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			obj[k] = next
+		}
+		obj = next
+	}
+	obj[keys[len(keys)-1]] = value
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+)
+
+const testSecret = "secrethooksecret"
+
+type testHandler struct {
+	events []interface{}
+}
+
+func (h *testHandler) Handles() []string {
+	return EventTypes()
+}
+
+func (h *testHandler) Handle(ctx context.Context, eventType, deliveryID string, payload []byte) error {
+	event, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		return err
+	}
+	h.events = append(h.events, event)
+	return nil
+}
+
+func TestSend(t *testing.T) {
+	for _, eventType := range EventTypes() {
+		t.Run(eventType, func(t *testing.T) {
+			h := &testHandler{}
+			d := githubapp.NewEventDispatcher([]githubapp.EventHandler{h}, testSecret)
+
+			res := Send(d, NewEvent(eventType), testSecret)
+			if res.Code != http.StatusOK {
+				t.Fatalf("incorrect response code: expected %d, actual %d: %s", http.StatusOK, res.Code, res.Body.String())
+			}
+			if len(h.events) != 1 {
+				t.Fatalf("incorrect number of handled events: expected 1, actual %d", len(h.events))
+			}
+		})
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	e := NewEvent("pull_request",
+		WithAction("closed"),
+		WithRepository("test", "repo"),
+		WithInstallationID(42),
+		WithField("pull_request.number", 128),
+	)
+
+	var event github.PullRequestEvent
+	if err := json.Unmarshal(e.Payload, &event); err != nil {
+		t.Fatalf("unexpected error parsing payload: %v", err)
+	}
+
+	if event.GetAction() != "closed" {
+		t.Errorf("incorrect action: %q", event.GetAction())
+	}
+	if event.GetRepo().GetFullName() != "test/repo" || event.GetRepo().GetOwner().GetLogin() != "test" {
+		t.Errorf("incorrect repository: %q", event.GetRepo().GetFullName())
+	}
+	if event.GetInstallation().GetID() != 42 {
+		t.Errorf("incorrect installation ID: %d", event.GetInstallation().GetID())
+	}
+	if event.GetPullRequest().GetNumber() != 128 {
+		t.Errorf("incorrect pull request number: %d", event.GetPullRequest().GetNumber())
+	}
+}
+
+func TestFixturesMatchPayloadSchemas(t *testing.T) {
+	v := githubapp.NewPayloadValidator()
+	for _, eventType := range EventTypes() {
+		t.Run(eventType, func(t *testing.T) {
+			if err := v.Validate(eventType, NewEvent(eventType).Payload); err != nil {
+				t.Errorf("fixture does not match bundled schema: %v", err)
+			}
+		})
+	}
+}
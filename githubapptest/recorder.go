@@ -0,0 +1,289 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+	"gopkg.in/yaml.v2"
+)
+
+// RecorderMode determines if a Recorder records or replays interactions.
+type RecorderMode int
+
+const (
+	// ModeReplay responds to requests using recorded interactions and never
+	// sends requests to GitHub.
+	ModeReplay RecorderMode = iota
+
+	// ModeRecord sends requests to GitHub and records the interactions.
+	ModeRecord
+)
+
+var (
+	tokenPattern    = regexp.MustCompile(`\b(ghs|ghp|gho|ghu|ghr)_[A-Za-z0-9]+|\bgithub_pat_[A-Za-z0-9_]+`)
+	accessTokenPath = regexp.MustCompile(`/app/installations/\d+/access_tokens$`)
+
+	recordedHeaders = []string{"Content-Type", "Link", "Location", "ETag", "Last-Modified"}
+)
+
+const redactedToken = "REDACTED"
+
+// Interaction is a recorded request and response.
+type Interaction struct {
+	Method      string            `yaml:"method"`
+	URL         string            `yaml:"url"`
+	RequestBody string            `yaml:"request_body,omitempty"`
+	Status      int               `yaml:"status"`
+	Headers     map[string]string `yaml:"headers,omitempty"`
+	Body        string            `yaml:"body"`
+
+	used bool
+}
+
+// Recorder is an http.RoundTripper that records interactions with GitHub to a
+// file and replays them in later test runs. Recorded interactions do not
+// include authentication headers or requests that create installation
+// tokens, and any token values in request or response bodies are redacted.
+//
+// In replay mode, requests are matched to the first unused interaction with
+// the same method, path, query, and body.
+type Recorder struct {
+	path string
+	mode RecorderMode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []*Interaction
+}
+
+// NewRecorder creates a Recorder that saves interactions to path. In replay
+// mode, it loads existing interactions from the file. In record mode, it
+// sends requests to GitHub using http.DefaultTransport.
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{
+		path: path,
+		mode: mode,
+		next: http.DefaultTransport,
+	}
+
+	if mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read interactions: %s", path)
+		}
+		if err := yaml.Unmarshal(b, &r.interactions); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse interactions: %s", path)
+		}
+	}
+
+	return r, nil
+}
+
+// RoundTrip records or replays a request, depending on the mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reqURL := req.URL.RequestURI()
+	reqBody := redact(string(body))
+	for _, in := range r.interactions {
+		if in.used || in.Method != req.Method || in.URL != reqURL || in.RequestBody != reqBody {
+			continue
+		}
+		in.used = true
+
+		header := make(http.Header)
+		for k, v := range in.Headers {
+			header.Set(k, v)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode:    in.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(in.Body)),
+			ContentLength: int64(len(in.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, errors.Errorf("no recorded interaction for \"%s %s\"", req.Method, reqURL)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil || accessTokenPath.MatchString(req.URL.Path) {
+		return res, err
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	in := &Interaction{
+		Method:      req.Method,
+		URL:         req.URL.RequestURI(),
+		RequestBody: redact(string(body)),
+		Status:      res.StatusCode,
+		Headers:     make(map[string]string),
+		Body:        redact(string(resBody)),
+	}
+	for _, h := range recordedHeaders {
+		if v := res.Header.Get(h); v != "" {
+			in.Headers[h] = v
+		}
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+
+	return res, nil
+}
+
+// Save writes recorded interactions to the file. It does nothing in replay
+// mode.
+func (r *Recorder) Save() error {
+	if r.mode == ModeReplay {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := yaml.Marshal(r.interactions)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal interactions")
+	}
+	if err := os.WriteFile(r.path, b, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write interactions: %s", r.path)
+	}
+	return nil
+}
+
+// NewRecordingClientCreator returns a ClientCreator for the application in c
+// that sends all requests through the recorder. In record mode, it is a
+// standard ClientCreator that authenticates with the private key in c. In
+// replay mode, created clients use the same base URLs but do not
+// authenticate, so tests do not need real credentials.
+func NewRecordingClientCreator(rec *Recorder, c githubapp.Config, opts ...githubapp.ClientOption) githubapp.ClientCreator {
+	v3BaseURL := c.V3APIURL
+	if v3BaseURL == "" {
+		v3BaseURL = "https://api.github.com/"
+	}
+	v4BaseURL := c.V4APIURL
+	if v4BaseURL == "" {
+		v4BaseURL = "https://api.github.com/graphql"
+	}
+
+	if rec.mode == ModeRecord {
+		opts = append(opts, githubapp.WithTransport(rec))
+		return githubapp.NewClientCreator(v3BaseURL, v4BaseURL, c.App.IntegrationID, []byte(c.App.PrivateKey), opts...)
+	}
+
+	return &replayClientCreator{
+		v3BaseURL: strings.TrimSuffix(v3BaseURL, "/") + "/",
+		v4BaseURL: strings.TrimSuffix(v4BaseURL, "/"),
+		client:    &http.Client{Transport: rec},
+	}
+}
+
+type replayClientCreator struct {
+	v3BaseURL string
+	v4BaseURL string
+	client    *http.Client
+}
+
+func (c *replayClientCreator) newClient() (*github.Client, error) {
+	baseURL, err := url.Parse(c.v3BaseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse base URL: %q", c.v3BaseURL)
+	}
+
+	client := github.NewClient(c.client)
+	client.BaseURL = baseURL
+	return client, nil
+}
+
+func (c *replayClientCreator) newV4Client() (*githubv4.Client, error) {
+	return githubv4.NewEnterpriseClient(c.v4BaseURL, c.client), nil
+}
+
+func (c *replayClientCreator) NewAppClient() (*github.Client, error) { return c.newClient() }
+
+func (c *replayClientCreator) NewAppV4Client() (*githubv4.Client, error) { return c.newV4Client() }
+
+func (c *replayClientCreator) NewInstallationClient(int64) (*github.Client, error) {
+	return c.newClient()
+}
+
+func (c *replayClientCreator) NewInstallationV4Client(int64) (*githubv4.Client, error) {
+	return c.newV4Client()
+}
+
+func (c *replayClientCreator) NewTokenSourceClient(oauth2.TokenSource) (*github.Client, error) {
+	return c.newClient()
+}
+
+func (c *replayClientCreator) NewTokenSourceV4Client(oauth2.TokenSource) (*githubv4.Client, error) {
+	return c.newV4Client()
+}
+
+func (c *replayClientCreator) NewTokenClient(string) (*github.Client, error) { return c.newClient() }
+
+func (c *replayClientCreator) NewTokenV4Client(string) (*githubv4.Client, error) {
+	return c.newV4Client()
+}
+
+func redact(s string) string {
+	return tokenPattern.ReplaceAllString(s, redactedToken)
+}
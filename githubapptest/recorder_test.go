@@ -0,0 +1,76 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githubapptest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"path":"`+r.URL.Path+`","token":"ghs_abcdef123456"}`)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "interactions.yml")
+
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("unexpected error creating recorder: %v", err)
+	}
+	recorded := get(t, &http.Client{Transport: rec}, server.URL+"/repos/test/repo")
+	if err := rec.Save(); err != nil {
+		t.Fatalf("unexpected error saving interactions: %v", err)
+	}
+
+	if !strings.Contains(recorded, "ghs_abcdef123456") {
+		t.Errorf("recorded response was modified: %s", recorded)
+	}
+
+	rep, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("unexpected error creating replay recorder: %v", err)
+	}
+	replayed := get(t, &http.Client{Transport: rep}, "https://api.github.com/repos/test/repo")
+
+	expected := `{"path":"/repos/test/repo","token":"REDACTED"}`
+	if replayed != expected {
+		t.Errorf("incorrect replayed body\nexpected: %s\n  actual: %s", expected, replayed)
+	}
+
+	if _, err := (&http.Client{Transport: rep}).Get("https://api.github.com/repos/test/repo"); err == nil {
+		t.Errorf("expected error replaying used interaction, but got nil")
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	res, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("unexpected error making request: %v", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %v", err)
+	}
+	return string(b)
+}
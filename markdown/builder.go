@@ -0,0 +1,169 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Builder builds a markdown document from blocks, like paragraphs, tables,
+// and code blocks. Blocks are separated by blank lines. The zero value is an
+// empty document ready to use.
+type Builder struct {
+	b strings.Builder
+}
+
+// String returns the document.
+func (b *Builder) String() string {
+	return b.b.String()
+}
+
+// Len returns the length of the document in bytes.
+func (b *Builder) Len() int {
+	return b.b.Len()
+}
+
+func (b *Builder) block() {
+	if b.b.Len() > 0 {
+		b.b.WriteString("\n")
+	}
+}
+
+// Heading adds a heading of the given level, from 1 to 6.
+func (b *Builder) Heading(level int, text string) *Builder {
+	level = max(1, min(level, 6))
+
+	b.block()
+	fmt.Fprintf(&b.b, "%s %s\n", strings.Repeat("#", level), singleLine(text))
+	return b
+}
+
+// Paragraph adds a paragraph of text. The text is not escaped, so it may
+// contain inline markdown.
+func (b *Builder) Paragraph(text string) *Builder {
+	b.block()
+	b.b.WriteString(strings.TrimRight(text, "\n"))
+	b.b.WriteString("\n")
+	return b
+}
+
+// Paragraphf adds a paragraph of formatted text.
+func (b *Builder) Paragraphf(format string, args ...interface{}) *Builder {
+	return b.Paragraph(fmt.Sprintf(format, args...))
+}
+
+// List adds a bulleted list with one item for each value.
+func (b *Builder) List(items ...string) *Builder {
+	if len(items) == 0 {
+		return b
+	}
+
+	b.block()
+	for _, item := range items {
+		fmt.Fprintf(&b.b, "- %s\n", singleLine(item))
+	}
+	return b
+}
+
+// CodeBlock adds a fenced code block with optional language for syntax
+// highlighting. The fence is longer than any sequence of backticks in code,
+// so the content cannot end the block early.
+func (b *Builder) CodeBlock(language, code string) *Builder {
+	fence := strings.Repeat("`", max(3, longestRun(code, '`')+1))
+
+	b.block()
+	fmt.Fprintf(&b.b, "%s%s\n", fence, singleLine(language))
+	b.b.WriteString(code)
+	if !strings.HasSuffix(code, "\n") {
+		b.b.WriteString("\n")
+	}
+	b.b.WriteString(fence)
+	b.b.WriteString("\n")
+	return b
+}
+
+// Table adds a table with the given header and rows. Cells are escaped so
+// that pipes and newlines do not break the table. Rows with fewer cells than
+// the header are padded with empty cells.
+func (b *Builder) Table(header []string, rows [][]string) *Builder {
+	if len(header) == 0 {
+		return b
+	}
+
+	b.block()
+	b.tableRow(header, len(header))
+
+	b.b.WriteString("|")
+	for range header {
+		b.b.WriteString(" --- |")
+	}
+	b.b.WriteString("\n")
+
+	for _, row := range rows {
+		b.tableRow(row, len(header))
+	}
+	return b
+}
+
+func (b *Builder) tableRow(cells []string, n int) {
+	b.b.WriteString("|")
+	for i := 0; i < n; i++ {
+		var cell string
+		if i < len(cells) {
+			cell = EscapeTableCell(cells[i])
+		}
+		fmt.Fprintf(&b.b, " %s |", cell)
+	}
+	b.b.WriteString("\n")
+}
+
+// Details adds a collapsible section with the given summary. The content of
+// the section is built by calling fn with a new Builder.
+func (b *Builder) Details(summary string, fn func(*Builder)) *Builder {
+	var inner Builder
+	fn(&inner)
+
+	b.block()
+	fmt.Fprintf(&b.b, "<details>\n<summary>%s</summary>\n\n", html.EscapeString(singleLine(summary)))
+	b.b.WriteString(inner.String())
+	b.b.WriteString("\n</details>\n")
+	return b
+}
+
+// EscapeTableCell escapes text for use in a table cell.
+func EscapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func longestRun(s string, c byte) int {
+	var longest, current int
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	return longest
+}
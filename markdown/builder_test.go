@@ -0,0 +1,60 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	var b Builder
+	b.Heading(2, "Test Results").
+		Paragraphf("**%d** tests failed", 2).
+		Table([]string{"Test", "Error"}, [][]string{
+			{"TestA", "expected a|b"},
+			{"TestB", "line 1\nline 2"},
+			{"TestC"},
+		}).
+		Details("Logs <full>", func(b *Builder) {
+			b.CodeBlock("text", "output with ``` inside")
+		}).
+		List("first", "second")
+
+	expected := "## Test Results\n" +
+		"\n" +
+		"**2** tests failed\n" +
+		"\n" +
+		"| Test | Error |\n" +
+		"| --- | --- |\n" +
+		"| TestA | expected a\\|b |\n" +
+		"| TestB | line 1<br>line 2 |\n" +
+		"| TestC |  |\n" +
+		"\n" +
+		"<details>\n" +
+		"<summary>Logs &lt;full&gt;</summary>\n" +
+		"\n" +
+		"````text\n" +
+		"output with ``` inside\n" +
+		"````\n" +
+		"\n" +
+		"</details>\n" +
+		"\n" +
+		"- first\n" +
+		"- second\n"
+
+	if actual := b.String(); actual != expected {
+		t.Errorf("incorrect markdown\nexpected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package markdown builds GitHub-flavored markdown for application output,
+// like comments and check run summaries. It escapes values inserted into
+// tables and code blocks and truncates output to the length limits of the
+// GitHub API without leaving code blocks or collapsible sections open.
+// Templates render output from structured data and split long output into
+// pages that each fit in a single comment.
+package markdown
@@ -0,0 +1,234 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PageFooter returns the footer added to each page of paginated output.
+type PageFooter func(page, total int) string
+
+// DefaultPageFooter identifies the part of the output on each page.
+func DefaultPageFooter(page, total int) string {
+	return fmt.Sprintf("\n\n_Part %d of %d_\n", page, total)
+}
+
+// maxFooterPages is the page count used to reserve space for footers before
+// the actual number of pages is known.
+const maxFooterPages = 9999
+
+// Paginate splits markdown into pages of at most limit bytes, including the
+// footer, which is added to every page if there is more than one. It splits
+// between blocks when possible. Blocks longer than a page are split between
+// lines, closing any code blocks and collapsible sections at the end of a
+// page and reopening them at the start of the next page. The markdown that
+// closes and reopens blocks counts against the limit; if it would fill more
+// than half of a page, sections are reopened without their summaries. If
+// footer is nil, it uses DefaultPageFooter.
+func Paginate(markdown string, limit int, footer PageFooter) []string {
+	if len(markdown) <= limit {
+		return []string{markdown}
+	}
+	if footer == nil {
+		footer = DefaultPageFooter
+	}
+
+	budget := limit - len(footer(maxFooterPages, maxFooterPages))
+
+	var pages []string
+	var page strings.Builder
+	flush := func() {
+		if page.Len() > 0 {
+			pages = append(pages, strings.TrimRight(page.String(), "\n")+"\n")
+			page.Reset()
+		}
+	}
+
+	for _, block := range splitBlocks(markdown) {
+		sep := ""
+		if page.Len() > 0 {
+			sep = "\n"
+		}
+		if page.Len()+len(sep)+len(block) <= budget {
+			page.WriteString(sep)
+			page.WriteString(block)
+			continue
+		}
+
+		flush()
+		if len(block) <= budget {
+			page.WriteString(block)
+			continue
+		}
+		for _, part := range splitLines(block, budget) {
+			page.WriteString(part)
+			flush()
+		}
+	}
+	flush()
+
+	if len(pages) > 1 {
+		for i := range pages {
+			pages[i] = strings.TrimRight(pages[i], "\n") + footer(i+1, len(pages))
+		}
+	}
+	return pages
+}
+
+// blockState tracks the code block and collapsible sections that are open
+// at a point in a document.
+type blockState struct {
+	fence     string
+	fenceLine string
+	details   []string
+}
+
+func (s *blockState) open() bool {
+	return s.fence != "" || len(s.details) > 0
+}
+
+func (s *blockState) update(line string) {
+	trimmed := strings.TrimSpace(line)
+	if s.fence != "" {
+		if strings.HasPrefix(trimmed, s.fence) && strings.Trim(trimmed, "`") == "" {
+			s.fence, s.fenceLine = "", ""
+		}
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(trimmed, "```"):
+		s.fence = trimmed[:longestRun(trimmed, '`')]
+		s.fenceLine = trimmed
+	case trimmed == "</details>":
+		if len(s.details) > 0 {
+			s.details = s.details[:len(s.details)-1]
+		}
+	case strings.HasPrefix(trimmed, "<details"):
+		s.details = append(s.details, trimmed)
+	}
+}
+
+// closing returns the markdown that closes all open blocks.
+func (s *blockState) closing() string {
+	var b strings.Builder
+	if s.fence != "" {
+		b.WriteString(s.fence)
+		b.WriteString("\n")
+	}
+	for range s.details {
+		b.WriteString("\n</details>\n")
+	}
+	return b.String()
+}
+
+// reopening returns the markdown that reopens all open blocks. If compact is
+// true, it reopens collapsible sections without attributes or summaries.
+func (s *blockState) reopening(compact bool) string {
+	var b strings.Builder
+	for _, d := range s.details {
+		if compact {
+			b.WriteString("<details>\n\n")
+			continue
+		}
+		b.WriteString(d)
+		b.WriteString("\n<summary>(continued)</summary>\n\n")
+	}
+	if s.fence != "" {
+		b.WriteString(s.fenceLine)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// splitBlocks splits markdown at blank lines that are not inside a code
+// block or collapsible section.
+func splitBlocks(markdown string) []string {
+	var blocks []string
+	var block strings.Builder
+	var state blockState
+
+	for _, line := range strings.SplitAfter(markdown, "\n") {
+		if strings.TrimSpace(line) == "" && !state.open() {
+			if block.Len() > 0 {
+				blocks = append(blocks, block.String())
+				block.Reset()
+			}
+			continue
+		}
+		block.WriteString(line)
+		state.update(line)
+	}
+	if block.Len() > 0 {
+		blocks = append(blocks, block.String())
+	}
+	return blocks
+}
+
+// splitLines splits a block into parts of at most limit bytes between lines.
+// Lines that are longer than a page are truncated. Each part includes the
+// markdown that closes and reopens the blocks open at its ends.
+func splitLines(block string, limit int) []string {
+	var parts []string
+	var part strings.Builder
+	var state blockState
+
+	// hasContent is false while part only contains the markdown that reopens
+	// blocks, so a part is never closed before it includes a line
+	hasContent := false
+
+	for _, line := range strings.SplitAfter(block, "\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+
+		next := state
+		next.details = append([]string(nil), state.details...)
+		next.update(line)
+
+		if hasContent && part.Len()+len(line)+len(next.closing()) > limit {
+			part.WriteString(state.closing())
+			parts = append(parts, part.String())
+			part.Reset()
+
+			// the markdown to reopen and close blocks on the next part counts
+			// against its limit, so if it leaves less than half of the part
+			// for content, reopen sections without their summaries
+			reopening := state.reopening(false)
+			if len(reopening)+len(state.closing()) > limit/2 {
+				reopening = state.reopening(true)
+			}
+			part.WriteString(reopening)
+			hasContent = false
+		}
+
+		if room := limit - part.Len() - len(next.closing()); len(line) > room {
+			line = Truncate(strings.TrimRight(line, "\n"), max(room-1, 0), "") + "\n"
+		}
+		part.WriteString(line)
+		hasContent = true
+		state = next
+	}
+	if part.Len() > 0 {
+		part.WriteString(state.closing())
+		parts = append(parts, part.String())
+	}
+	return parts
+}
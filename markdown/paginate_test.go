@@ -0,0 +1,75 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	var nested strings.Builder
+	nested.WriteString("<details><summary>Build output for the linux job</summary>\n\n")
+	nested.WriteString("<details><summary>Test output for the integration suite</summary>\n\n```go\n")
+	for i := 0; i < 100; i++ {
+		nested.WriteString("ok  github.com/palantir/go-githubapp/githubapp\n")
+	}
+	nested.WriteString("```\n</details>\n</details>\n")
+
+	tests := map[string]struct {
+		Input string
+		Limit int
+		Pages int
+	}{
+		"short": {
+			Input: "hello\n",
+			Limit: 100,
+			Pages: 1,
+		},
+		"blocks": {
+			Input: strings.Repeat("paragraph of text\n\n", 20),
+			Limit: 100,
+			Pages: 5,
+		},
+		"nestedBlocks": {
+			Input: nested.String(),
+			Limit: 500,
+		},
+		"nestedBlocksSmallLimit": {
+			Input: nested.String(),
+			Limit: 150,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			pages := Paginate(test.Input, test.Limit, nil)
+			if test.Pages > 0 && len(pages) != test.Pages {
+				t.Errorf("incorrect number of pages: expected %d, actual %d", test.Pages, len(pages))
+			}
+			for i, p := range pages {
+				if len(p) > test.Limit {
+					t.Errorf("page %d has %d bytes, more than the limit of %d:\n%s", i+1, len(p), test.Limit, p)
+				}
+				if strings.Count(p, "<details") != strings.Count(p, "</details>") {
+					t.Errorf("page %d has unbalanced collapsible sections:\n%s", i+1, p)
+				}
+				if strings.Count(p, "```")%2 != 0 {
+					t.Errorf("page %d has an unclosed code block:\n%s", i+1, p)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+// TemplateOption configures properties of a template.
+type TemplateOption func(*Template)
+
+// WithTemplateFuncs adds functions to the template. Functions with the same
+// names as the default functions replace them.
+func WithTemplateFuncs(funcs template.FuncMap) TemplateOption {
+	return func(t *Template) {
+		for name, fn := range funcs {
+			t.funcs[name] = fn
+		}
+	}
+}
+
+// WithTruncationNotice sets the notice appended to truncated output. The
+// default is DefaultTruncationNotice.
+func WithTruncationNotice(notice string) TemplateOption {
+	return func(t *Template) {
+		t.notice = notice
+	}
+}
+
+// WithPageFooter sets the footer added to each page of paginated output.
+// The default is DefaultPageFooter.
+func WithPageFooter(footer PageFooter) TemplateOption {
+	return func(t *Template) {
+		if footer != nil {
+			t.footer = footer
+		}
+	}
+}
+
+// Template renders comment and check run bodies from Go templates and
+// structured data. In addition to the standard template functions, templates
+// can use the following functions:
+//
+//	cell     escapes a value for use in a table cell
+//	code     formats a value as a code block: {{code "go" .Source}}
+//	html     escapes a value for use in HTML, like a <summary> element
+//	inline   collapses whitespace so a value fits on one line
+//	join     joins a list of strings: {{join .Names ", "}}
+//
+// A Template is safe for concurrent use.
+type Template struct {
+	tmpl   *template.Template
+	funcs  template.FuncMap
+	notice string
+	footer PageFooter
+}
+
+// NewTemplate parses text as a template with the given name.
+func NewTemplate(name, text string, opts ...TemplateOption) (*Template, error) {
+	t := &Template{
+		funcs: template.FuncMap{
+			"cell":   EscapeTableCell,
+			"code":   codeBlock,
+			"html":   html.EscapeString,
+			"inline": singleLine,
+			"join":   strings.Join,
+		},
+		notice: DefaultTruncationNotice,
+		footer: DefaultPageFooter,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	tmpl, err := template.New(name).Funcs(t.funcs).Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse template %q", name)
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Render executes the template with data and returns the full output.
+func (t *Template) Render(data interface{}) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", errors.Wrapf(err, "failed to render template %q", t.tmpl.Name())
+	}
+	return b.String(), nil
+}
+
+// RenderTruncated executes the template with data and truncates the output
+// to limit bytes. Use it for output that must fit in a single value, like
+// the summary of a check run.
+func (t *Template) RenderTruncated(data interface{}, limit int) (string, error) {
+	s, err := t.Render(data)
+	if err != nil {
+		return "", err
+	}
+	return Truncate(s, limit, t.notice), nil
+}
+
+// RenderPages executes the template with data and splits the output into
+// pages of at most limit bytes. Use it for output that can span multiple
+// values, like a report posted as several comments.
+func (t *Template) RenderPages(data interface{}, limit int) ([]string, error) {
+	s, err := t.Render(data)
+	if err != nil {
+		return nil, err
+	}
+	return Paginate(s, limit, t.footer), nil
+}
+
+func codeBlock(language string, code interface{}) string {
+	var b Builder
+	b.CodeBlock(language, toString(code))
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
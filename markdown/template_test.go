@@ -0,0 +1,136 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testReport = `## Failed Tests
+
+| Test | Error |
+| --- | --- |
+{{range .Failures}}| {{cell .Name}} | {{cell .Error}} |
+{{end}}
+<details>
+<summary>{{html .Title}}</summary>
+
+{{code "text" .Logs}}
+
+</details>
+`
+
+type testFailure struct {
+	Name, Error string
+}
+
+func TestTemplateRender(t *testing.T) {
+	tmpl, err := NewTemplate("report", testReport)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := tmpl.Render(map[string]interface{}{
+		"Failures": []testFailure{{"TestA", "a|b"}},
+		"Title":    "Logs <all>",
+		"Logs":     "ok\n",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "## Failed Tests\n\n| Test | Error |\n| --- | --- |\n| TestA | a\\|b |\n\n" +
+		"<details>\n<summary>Logs &lt;all&gt;</summary>\n\n```text\nok\n```\n\n</details>\n"
+	if out != expected {
+		t.Errorf("incorrect output\nexpected: %q\n  actual: %q", expected, out)
+	}
+}
+
+func TestTemplateRenderPages(t *testing.T) {
+	tmpl, err := NewTemplate("report", testReport)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var failures []testFailure
+	for i := 0; i < 50; i++ {
+		failures = append(failures, testFailure{fmt.Sprintf("Test%02d", i), "failed"})
+	}
+	var logs strings.Builder
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&logs, "log line %02d\n", i)
+	}
+
+	const limit = 500
+	pages, err := tmpl.RenderPages(map[string]interface{}{
+		"Failures": failures,
+		"Title":    "Logs",
+		"Logs":     logs.String(),
+	}, limit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(pages) < 3 {
+		t.Fatalf("expected multiple pages, but got %d", len(pages))
+	}
+
+	var combined strings.Builder
+	for i, page := range pages {
+		if len(page) > limit {
+			t.Errorf("page %d is %d bytes, which exceeds the limit of %d", i+1, len(page), limit)
+		}
+		if !strings.HasSuffix(page, fmt.Sprintf("_Part %d of %d_\n", i+1, len(pages))) {
+			t.Errorf("page %d has incorrect footer: %q", i+1, page)
+		}
+		if strings.Count(page, "```") != 2*strings.Count(page, "```text") {
+			t.Errorf("page %d has unbalanced code blocks: %q", i+1, page)
+		}
+		if strings.Count(page, "<details>") != strings.Count(page, "</details>") {
+			t.Errorf("page %d has unbalanced details: %q", i+1, page)
+		}
+		combined.WriteString(page)
+	}
+
+	for _, f := range failures {
+		if !strings.Contains(combined.String(), "| "+f.Name+" |") {
+			t.Errorf("pages are missing failure %s", f.Name)
+		}
+	}
+	for i := 0; i < 50; i++ {
+		if line := fmt.Sprintf("log line %02d\n", i); !strings.Contains(combined.String(), line) {
+			t.Errorf("pages are missing %q", line)
+		}
+	}
+}
+
+func TestTemplateRenderTruncated(t *testing.T) {
+	tmpl, err := NewTemplate("logs", "{{code \"\" .}}", WithTruncationNotice("[cut]"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	out, err := tmpl.RenderTruncated(strings.Repeat("line\n", 100), 50)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "```\n" + strings.Repeat("line\n", 7) + "```\n[cut]"
+	if out != expected {
+		t.Errorf("incorrect output\nexpected: %q\n  actual: %q", expected, out)
+	}
+}
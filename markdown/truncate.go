@@ -0,0 +1,92 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Length limits of the GitHub API. GitHub counts characters, but the limits
+// here are applied to bytes, which is never more permissive.
+const (
+	MaxCommentLength         = 65536
+	MaxCheckRunSummaryLength = 65535
+	MaxCheckRunTextLength    = 65535
+)
+
+// DefaultTruncationNotice is appended to truncated output.
+const DefaultTruncationNotice = "\n\n_Output truncated._\n"
+
+// Truncate shortens markdown to at most limit bytes, including notice, which
+// is appended if the markdown is truncated. It cuts at a line break when
+// possible, never splits a UTF-8 character, and closes any code blocks and
+// collapsible sections left open by the cut. If the markdown is shorter than
+// the limit, it is returned unchanged.
+func Truncate(markdown string, limit int, notice string) string {
+	if len(markdown) <= limit {
+		return markdown
+	}
+
+	// reserve space for the notice and anything needed to close open blocks
+	budget := limit - len(notice)
+	for n := budget; n > 0; {
+		cut := cutPoint(markdown, n)
+		closing := closeBlocks(markdown[:cut])
+		if cut+len(closing) <= budget {
+			return markdown[:cut] + closing + notice
+		}
+		n = cut - 1
+	}
+	return notice[:min(len(notice), max(limit, 0))]
+}
+
+// TruncateComment truncates markdown to the length limit of comments.
+func TruncateComment(markdown string) string {
+	return Truncate(markdown, MaxCommentLength, DefaultTruncationNotice)
+}
+
+// TruncateCheckRunSummary truncates markdown to the length limit of check
+// run summaries.
+func TruncateCheckRunSummary(markdown string) string {
+	return Truncate(markdown, MaxCheckRunSummaryLength, DefaultTruncationNotice)
+}
+
+// cutPoint returns the end of the longest prefix of s that is at most n
+// bytes, preferring to end after a line break in the second half.
+func cutPoint(s string, n int) int {
+	if i := strings.LastIndexByte(s[:n], '\n'); i >= n/2 {
+		return i + 1
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return n
+}
+
+// closeBlocks returns the markdown needed to close any code blocks and
+// collapsible sections that are open at the end of s.
+func closeBlocks(s string) string {
+	var state blockState
+	for _, line := range strings.Split(s, "\n") {
+		state.update(line)
+	}
+
+	closing := state.closing()
+	if state.fence != "" && !strings.HasSuffix(s, "\n") {
+		closing = "\n" + closing
+	}
+	return closing
+}
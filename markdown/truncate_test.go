@@ -0,0 +1,75 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := map[string]struct {
+		Input    string
+		Limit    int
+		Expected string
+	}{
+		"short": {
+			Input:    "hello\n",
+			Limit:    10,
+			Expected: "hello\n",
+		},
+		"lineBreak": {
+			Input:    "line one\nline two\nline three\n",
+			Limit:    24,
+			Expected: "line one\nline two\n[cut]",
+		},
+		"openCodeBlock": {
+			Input:    "intro\n```go\nfunc a() {}\nfunc b() {}\nfunc c() {}\n```\n",
+			Limit:    40,
+			Expected: "intro\n```go\nfunc a() {}\n```\n[cut]",
+		},
+		"openDetails": {
+			Input:    "<details>\n<summary>Logs</summary>\n\nline 1\nline 2\nline 3\nline 4\n</details>\n",
+			Limit:    60,
+			Expected: "<details>\n<summary>Logs</summary>\n\nline 1\n\n</details>\n[cut]",
+		},
+		"noLineBreak": {
+			Input:    strings.Repeat("é", 20),
+			Limit:    16,
+			Expected: strings.Repeat("é", 5) + "[cut]",
+		},
+		"tinyLimit": {
+			Input:    "hello world",
+			Limit:    3,
+			Expected: "[cu",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual := Truncate(test.Input, test.Limit, "[cut]")
+			if actual != test.Expected {
+				t.Errorf("incorrect output\nexpected: %q\n  actual: %q", test.Expected, actual)
+			}
+			if len(actual) > test.Limit {
+				t.Errorf("output is %d bytes, which exceeds the limit of %d", len(actual), test.Limit)
+			}
+			if !utf8.ValidString(actual) {
+				t.Errorf("output is not valid UTF-8: %q", actual)
+			}
+		})
+	}
+}
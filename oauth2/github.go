@@ -15,6 +15,7 @@
 package oauth2
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/palantir/go-githubapp/githubapp"
@@ -37,6 +38,16 @@ func GetConfig(c githubapp.Config, scopes []string) *oauth2.Config {
 	}
 }
 
+// Route returns a route that serves the OAuth2 flow with h. Both the initial
+// request and the redirect from GitHub are GET requests to the same path.
+func Route(pattern string, h http.Handler) githubapp.Route {
+	return githubapp.Route{
+		Method:  http.MethodGet,
+		Pattern: pattern,
+		Handler: h,
+	}
+}
+
 func joinURL(base, path string) string {
 	return strings.TrimSuffix(base, "/") + path
 }
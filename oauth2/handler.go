@@ -15,6 +15,7 @@
 package oauth2
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -58,6 +59,9 @@ type handler struct {
 
 	forceTLS bool
 	store    StateStore
+	client   *http.Client
+
+	membership *membershipChecker
 }
 
 // NewHandler returns an http.Hander that implements the 3-leg OAuth2 flow on a
@@ -83,6 +87,10 @@ func DefaultErrorCallback(w http.ResponseWriter, r *http.Request, err error) {
 		http.Error(w, "invalid state parameter", http.StatusBadRequest)
 		return
 	}
+	if err == ErrNotMember {
+		http.Error(w, "user is not a member of an allowed organization or team", http.StatusForbidden)
+		return
+	}
 	if _, ok := err.(LoginError); ok {
 		http.Error(w, fmt.Sprintf("oauth2 error: %v", err.Error()), http.StatusBadRequest)
 		return
@@ -125,6 +133,16 @@ func OnLogin(c LoginCallback) Param {
 	}
 }
 
+// WithHTTPClient sets the HTTP client used to exchange codes for tokens and
+// to refresh tokens. The client in the Login passed to the login callback
+// also uses its transport. Use this to configure proxies, timeouts, or
+// custom certificate authorities. By default, http.DefaultClient is used.
+func WithHTTPClient(client *http.Client) Param {
+	return func(h *handler) {
+		h.client = client
+	}
+}
+
 // WithRedirectURL sets a static redirect URL. By default, the redirect URL is
 // generated using the request path, the Host header, and the ForceTLS option.
 func WithRedirectURL(uri string) Param {
@@ -171,18 +189,36 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tok, err := conf.Exchange(r.Context(), r.FormValue(queryCode))
+	ctx := h.clientContext(r.Context())
+
+	tok, err := conf.Exchange(ctx, r.FormValue(queryCode))
 	if err != nil {
 		h.onError(w, r, err)
 		return
 	}
 
+	if h.membership != nil {
+		if err := h.membership.check(ctx, tok); err != nil {
+			h.onError(w, r, err)
+			return
+		}
+	}
+
 	h.onLogin(w, r, &Login{
 		Token:  tok,
-		Client: conf.Client(r.Context(), tok),
+		Client: conf.Client(ctx, tok),
 	})
 }
 
+// clientContext returns a context that configures the oauth2 package to use
+// the handler's HTTP client, if set
+func (h *handler) clientContext(ctx context.Context) context.Context {
+	if h.client == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, h.client)
+}
+
 func isInitial(r *http.Request) bool {
 	return r.FormValue(queryCode) == ""
 }
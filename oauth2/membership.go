@@ -0,0 +1,141 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v66/github"
+	"github.com/palantir/go-githubapp/githubapp"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrNotMember = errors.New("oauth2: user is not a member of an allowed organization or team")
+)
+
+// Membership identifies an organization or a team in an organization.
+type Membership struct {
+	Org string
+
+	// Team is the slug of a team in Org. If empty, all members of Org match.
+	Team string
+}
+
+func (m Membership) String() string {
+	if m.Team == "" {
+		return m.Org
+	}
+	return m.Org + "/" + m.Team
+}
+
+// RequireMembership only allows users who are members of at least one of the
+// allowed organizations or teams to log in. Other users are passed to the
+// error callback with ErrNotMember and the login callback is not called. If
+// membership in some organizations cannot be checked, for example because the
+// app is not installed there, the other organizations are still checked; if
+// the user is not a member of any of them, the error callback receives the
+// errors instead of ErrNotMember.
+//
+// Membership is checked with installation clients from cc, so the app must
+// be installed in each organization with permission to read members. The
+// user's login is read with a client from cc that uses the user's token.
+func RequireMembership(cc githubapp.ClientCreator, allowed ...Membership) Param {
+	return func(h *handler) {
+		h.membership = &membershipChecker{
+			cc:      cc,
+			allowed: allowed,
+		}
+	}
+}
+
+type membershipChecker struct {
+	cc      githubapp.ClientCreator
+	allowed []Membership
+}
+
+func (c *membershipChecker) check(ctx context.Context, tok *oauth2.Token) error {
+	userClient, err := c.cc.NewTokenSourceClient(oauth2.StaticTokenSource(tok))
+	if err != nil {
+		return fmt.Errorf("failed to create user client: %w", err)
+	}
+
+	user, _, err := userClient.Users.Get(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to get authenticated user: %w", err)
+	}
+
+	appClient, err := c.cc.NewAppClient()
+	if err != nil {
+		return fmt.Errorf("failed to create app client: %w", err)
+	}
+	installations := githubapp.NewInstallationsService(appClient)
+
+	// an error for one organization, like an organization where the app is
+	// not installed, must not prevent members of other organizations from
+	// logging in
+	var errs []error
+	for _, m := range c.allowed {
+		isMember, err := c.checkOne(ctx, installations, m, user.GetLogin())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if isMember {
+			return nil
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return ErrNotMember
+}
+
+func (c *membershipChecker) checkOne(ctx context.Context, installations githubapp.InstallationsService, m Membership, login string) (bool, error) {
+	installation, err := installations.GetByOwner(ctx, m.Org)
+	if err != nil {
+		return false, fmt.Errorf("failed to get installation for %s: %w", m.Org, err)
+	}
+
+	client, err := c.cc.NewInstallationClient(installation.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to create client for %s: %w", m.Org, err)
+	}
+
+	isMember, err := isMember(ctx, client, m, login)
+	if err != nil {
+		return false, fmt.Errorf("failed to check membership in %s: %w", m, err)
+	}
+	return isMember, nil
+}
+
+func isMember(ctx context.Context, client *github.Client, m Membership, login string) (bool, error) {
+	if m.Team == "" {
+		isMember, _, err := client.Organizations.IsMember(ctx, m.Org, login)
+		return isMember, err
+	}
+
+	membership, res, err := client.Teams.GetTeamMembershipBySlug(ctx, m.Org, m.Team, login)
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return membership.GetState() == "active", nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauth2
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/palantir/go-githubapp/githubapptest"
+	"golang.org/x/oauth2"
+)
+
+func TestMembershipChecker(t *testing.T) {
+	cc := githubapptest.NewFakeClientCreator(map[string]http.Handler{
+		"GET /user":                    githubapptest.JSONResponse(http.StatusOK, map[string]string{"login": "octocat"}),
+		"GET /orgs/{org}/installation": githubapptest.JSONResponse(http.StatusOK, map[string]int64{"id": 1}),
+		"GET /orgs/uninstalled/installation": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+		"GET /users/uninstalled/installation": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+		"GET /orgs/members/members/octocat": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+		"GET /orgs/teams/teams/active/memberships/octocat":  githubapptest.JSONResponse(http.StatusOK, map[string]string{"state": "active"}),
+		"GET /orgs/teams/teams/pending/memberships/octocat": githubapptest.JSONResponse(http.StatusOK, map[string]string{"state": "pending"}),
+	})
+	defer cc.Close()
+
+	tests := map[string]struct {
+		Allowed []Membership
+		Error   error
+		Failed  bool
+	}{
+		"orgMember": {
+			Allowed: []Membership{{Org: "members"}},
+		},
+		"notOrgMember": {
+			Allowed: []Membership{{Org: "others"}},
+			Error:   ErrNotMember,
+		},
+		"teamMember": {
+			Allowed: []Membership{{Org: "teams", Team: "active"}},
+		},
+		"pendingTeamMember": {
+			Allowed: []Membership{{Org: "teams", Team: "pending"}},
+			Error:   ErrNotMember,
+		},
+		"notTeamMember": {
+			Allowed: []Membership{{Org: "teams", Team: "other"}},
+			Error:   ErrNotMember,
+		},
+		"anyAllowed": {
+			Allowed: []Membership{{Org: "others"}, {Org: "teams", Team: "active"}},
+		},
+		"uninstalledOrgSkipped": {
+			Allowed: []Membership{{Org: "uninstalled"}, {Org: "members"}},
+		},
+		"uninstalledOrgOnly": {
+			Allowed: []Membership{{Org: "uninstalled"}, {Org: "others"}},
+			Failed:  true,
+		},
+	}
+
+	tok := &oauth2.Token{AccessToken: "token"}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &membershipChecker{cc: cc, allowed: test.Allowed}
+
+			err := c.check(context.Background(), tok)
+			if test.Failed {
+				if err == nil || errors.Is(err, ErrNotMember) {
+					t.Errorf("expected membership check error, actual %v", err)
+				}
+				return
+			}
+			if err != test.Error {
+				t.Errorf("incorrect error: expected %v, actual %v", test.Error, err)
+			}
+		})
+	}
+}
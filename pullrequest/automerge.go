@@ -0,0 +1,217 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullrequest
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+// GetNodeID returns the GraphQL node ID of a pull request, which is required
+// by most mutations.
+func GetNodeID(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) (githubv4.ID, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				ID githubv4.ID
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	vars := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+	}
+	if err := v4client.Query(ctx, &q, vars); err != nil {
+		return nil, errors.Wrap(err, "failed to get pull request ID")
+	}
+	return q.Repository.PullRequest.ID, nil
+}
+
+// EnableAutoMerge enables auto-merge for a pull request using the given merge
+// method. If expectedHeadSHA is not empty, GitHub only enables auto-merge if
+// it matches the current head of the pull request.
+func EnableAutoMerge(ctx context.Context, v4client *githubv4.Client, owner, name string, number int, method githubv4.PullRequestMergeMethod, expectedHeadSHA string) error {
+	id, err := GetNodeID(ctx, v4client, owner, name, number)
+	if err != nil {
+		return err
+	}
+
+	input := githubv4.EnablePullRequestAutoMergeInput{
+		PullRequestID: id,
+		MergeMethod:   &method,
+	}
+	if expectedHeadSHA != "" {
+		oid := githubv4.GitObjectID(expectedHeadSHA)
+		input.ExpectedHeadOid = &oid
+	}
+
+	var m struct {
+		EnablePullRequestAutoMerge struct {
+			ClientMutationID string
+		} `graphql:"enablePullRequestAutoMerge(input: $input)"`
+	}
+	if err := v4client.Mutate(ctx, &m, input, nil); err != nil {
+		return errors.Wrap(err, "failed to enable auto-merge")
+	}
+	return nil
+}
+
+// DisableAutoMerge disables auto-merge for a pull request. It succeeds if
+// auto-merge is not enabled.
+func DisableAutoMerge(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) error {
+	id, err := GetNodeID(ctx, v4client, owner, name, number)
+	if err != nil {
+		return err
+	}
+
+	var m struct {
+		DisablePullRequestAutoMerge struct {
+			ClientMutationID string
+		} `graphql:"disablePullRequestAutoMerge(input: $input)"`
+	}
+	input := githubv4.DisablePullRequestAutoMergeInput{PullRequestID: id}
+	if err := v4client.Mutate(ctx, &m, input, nil); err != nil {
+		return errors.Wrap(err, "failed to disable auto-merge")
+	}
+	return nil
+}
+
+// Enqueue adds a pull request to the merge queue of its base branch. If
+// expectedHeadSHA is not empty, GitHub only adds the pull request if it
+// matches the current head of the pull request. The base branch must require
+// a merge queue; otherwise, use EnableAutoMerge.
+func Enqueue(ctx context.Context, v4client *githubv4.Client, owner, name string, number int, expectedHeadSHA string) error {
+	id, err := GetNodeID(ctx, v4client, owner, name, number)
+	if err != nil {
+		return err
+	}
+
+	input := githubv4.EnqueuePullRequestInput{PullRequestID: id}
+	if expectedHeadSHA != "" {
+		oid := githubv4.GitObjectID(expectedHeadSHA)
+		input.ExpectedHeadOid = &oid
+	}
+
+	var m struct {
+		EnqueuePullRequest struct {
+			ClientMutationID string
+		} `graphql:"enqueuePullRequest(input: $input)"`
+	}
+	if err := v4client.Mutate(ctx, &m, input, nil); err != nil {
+		return errors.Wrap(err, "failed to add pull request to merge queue")
+	}
+	return nil
+}
+
+// Dequeue removes a pull request from the merge queue of its base branch.
+func Dequeue(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) error {
+	id, err := GetNodeID(ctx, v4client, owner, name, number)
+	if err != nil {
+		return err
+	}
+
+	var m struct {
+		DequeuePullRequest struct {
+			ClientMutationID string
+		} `graphql:"dequeuePullRequest(input: $input)"`
+	}
+	input := githubv4.DequeuePullRequestInput{ID: id}
+	if err := v4client.Mutate(ctx, &m, input, nil); err != nil {
+		return errors.Wrap(err, "failed to remove pull request from merge queue")
+	}
+	return nil
+}
+
+// MergeState describes whether a pull request is scheduled to merge.
+type MergeState struct {
+	// Status is GitHub's summary of whether the pull request can merge.
+	Status githubv4.MergeStateStatus
+
+	// AutoMerge is set if auto-merge is enabled for the pull request.
+	AutoMerge *AutoMerge
+
+	// QueueEntry is set if the pull request is in a merge queue.
+	QueueEntry *MergeQueueEntry
+}
+
+// AutoMerge describes the auto-merge settings of a pull request.
+type AutoMerge struct {
+	Method    githubv4.PullRequestMergeMethod
+	EnabledBy string
+	EnabledAt time.Time
+}
+
+// MergeQueueEntry describes the position of a pull request in a merge queue.
+type MergeQueueEntry struct {
+	State githubv4.MergeQueueEntryState
+
+	// Position is the zero-based position of the pull request in the queue.
+	Position   int
+	EnqueuedAt time.Time
+}
+
+// GetMergeState returns the auto-merge and merge queue state of a pull
+// request.
+func GetMergeState(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) (*MergeState, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				MergeStateStatus githubv4.MergeStateStatus
+				AutoMergeRequest *struct {
+					MergeMethod githubv4.PullRequestMergeMethod
+					EnabledBy   actor
+					EnabledAt   time.Time
+				}
+				MergeQueueEntry *struct {
+					State      githubv4.MergeQueueEntryState
+					Position   int
+					EnqueuedAt time.Time
+				}
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	vars := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+	}
+	if err := v4client.Query(ctx, &q, vars); err != nil {
+		return nil, errors.Wrap(err, "failed to get pull request merge state")
+	}
+
+	pr := q.Repository.PullRequest
+	state := &MergeState{Status: pr.MergeStateStatus}
+	if r := pr.AutoMergeRequest; r != nil {
+		state.AutoMerge = &AutoMerge{
+			Method:    r.MergeMethod,
+			EnabledBy: r.EnabledBy.Login,
+			EnabledAt: r.EnabledAt,
+		}
+	}
+	if e := pr.MergeQueueEntry; e != nil {
+		state.QueueEntry = &MergeQueueEntry{
+			State:      e.State,
+			Position:   e.Position,
+			EnqueuedAt: e.EnqueuedAt,
+		}
+	}
+	return state, nil
+}
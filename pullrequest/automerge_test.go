@@ -0,0 +1,87 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullrequest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/palantir/go-githubapp/githubapptest"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestGetMergeState(t *testing.T) {
+	tests := map[string]struct {
+		Response string
+		Expected func(t *testing.T, state *MergeState)
+	}{
+		"queued": {
+			Response: `{"data":{"repository":{"pullRequest":{
+				"mergeStateStatus":"CLEAN",
+				"autoMergeRequest":null,
+				"mergeQueueEntry":{"state":"AWAITING_CHECKS","position":2,"enqueuedAt":"2026-01-02T03:04:05Z"}}}}}`,
+			Expected: func(t *testing.T, state *MergeState) {
+				if state.AutoMerge != nil {
+					t.Errorf("expected auto-merge to be disabled, but got %+v", state.AutoMerge)
+				}
+				if e := state.QueueEntry; e == nil || e.State != githubv4.MergeQueueEntryStateAwaitingChecks || e.Position != 2 {
+					t.Errorf("incorrect queue entry: %+v", e)
+				}
+			},
+		},
+		"autoMerge": {
+			Response: `{"data":{"repository":{"pullRequest":{
+				"mergeStateStatus":"BLOCKED",
+				"autoMergeRequest":{"mergeMethod":"SQUASH","enabledBy":{"login":"octocat"},"enabledAt":"2026-01-02T03:04:05Z"},
+				"mergeQueueEntry":null}}}}`,
+			Expected: func(t *testing.T, state *MergeState) {
+				if state.Status != githubv4.MergeStateStatusBlocked {
+					t.Errorf("incorrect status: %s", state.Status)
+				}
+				if a := state.AutoMerge; a == nil || a.Method != githubv4.PullRequestMergeMethodSquash || a.EnabledBy != "octocat" {
+					t.Errorf("incorrect auto-merge: %+v", a)
+				}
+				if state.QueueEntry != nil {
+					t.Errorf("expected no queue entry, but got %+v", state.QueueEntry)
+				}
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cc := githubapptest.NewFakeClientCreator(map[string]http.Handler{
+				"POST /graphql": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = io.WriteString(w, test.Response)
+				}),
+			})
+			defer cc.Close()
+
+			client, err := cc.NewInstallationV4Client(1)
+			if err != nil {
+				t.Fatalf("unexpected error creating client: %v", err)
+			}
+
+			state, err := GetMergeState(context.Background(), client, "test", "repo", 1)
+			if err != nil {
+				t.Fatalf("unexpected error getting merge state: %v", err)
+			}
+			test.Expected(t, state)
+		})
+	}
+}
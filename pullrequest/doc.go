@@ -0,0 +1,19 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pullrequest provides prebuilt GitHub v4 (GraphQL) queries and
+// mutations for pull request data that applications commonly need, like
+// changed files, reviews, review threads, and status check results. The
+// functions handle pagination and return simplified types.
+package pullrequest
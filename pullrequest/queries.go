@@ -0,0 +1,323 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullrequest
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shurcooL/githubv4"
+)
+
+const (
+	// pageSize is the number of nodes requested per page, which is the
+	// maximum GitHub allows
+	pageSize = 100
+)
+
+type pageInfo struct {
+	EndCursor   githubv4.String
+	HasNextPage bool
+}
+
+type actor struct {
+	Login string
+}
+
+// File is a file changed by a pull request.
+type File struct {
+	Path       string
+	Additions  int
+	Deletions  int
+	ChangeType githubv4.PatchStatus
+}
+
+// Review is a review of a pull request.
+type Review struct {
+	ID          string
+	Author      string
+	State       githubv4.PullRequestReviewState
+	Body        string
+	CommitSHA   string
+	SubmittedAt time.Time
+}
+
+// ReviewThread is a thread of review comments on a pull request.
+type ReviewThread struct {
+	ID         string
+	Path       string
+	Line       int
+	IsResolved bool
+	IsOutdated bool
+	Comments   []ReviewComment
+}
+
+// ReviewComment is a comment in a review thread.
+type ReviewComment struct {
+	ID        string
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// StatusRollup is the combined status of the checks and commit statuses on
+// the head commit of a pull request.
+type StatusRollup struct {
+	CommitSHA string
+
+	// State is the combined state of all contexts. It is empty if the commit
+	// has no checks or statuses.
+	State    githubv4.StatusState
+	Contexts []StatusContext
+}
+
+// StatusContext is a single check run or commit status. Check runs have a
+// Status and Conclusion; commit statuses have a State.
+type StatusContext struct {
+	Name       string
+	IsCheckRun bool
+	Status     githubv4.CheckStatusState
+	Conclusion githubv4.CheckConclusionState
+	State      githubv4.StatusState
+	URL        string
+}
+
+func baseVars(owner, name string, number int) map[string]interface{} {
+	return map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(name),
+		"number": githubv4.Int(number),
+		"limit":  githubv4.Int(pageSize),
+		"cursor": (*githubv4.String)(nil),
+	}
+}
+
+// ListFiles returns all files changed by a pull request.
+func ListFiles(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) ([]File, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				Files struct {
+					PageInfo pageInfo
+					Nodes    []File
+				} `graphql:"files(first: $limit, after: $cursor)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	var files []File
+	vars := baseVars(owner, name, number)
+	for {
+		if err := v4client.Query(ctx, &q, vars); err != nil {
+			return nil, errors.Wrap(err, "failed to list pull request files")
+		}
+
+		conn := q.Repository.PullRequest.Files
+		files = append(files, conn.Nodes...)
+		if !conn.PageInfo.HasNextPage {
+			return files, nil
+		}
+		vars["cursor"] = githubv4.NewString(conn.PageInfo.EndCursor)
+	}
+}
+
+// ListReviews returns all reviews of a pull request.
+func ListReviews(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) ([]Review, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				Reviews struct {
+					PageInfo pageInfo
+					Nodes    []struct {
+						ID          string
+						Author      actor
+						State       githubv4.PullRequestReviewState
+						Body        string
+						Commit      struct{ OID string }
+						SubmittedAt time.Time
+					}
+				} `graphql:"reviews(first: $limit, after: $cursor)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	var reviews []Review
+	vars := baseVars(owner, name, number)
+	for {
+		if err := v4client.Query(ctx, &q, vars); err != nil {
+			return nil, errors.Wrap(err, "failed to list pull request reviews")
+		}
+
+		conn := q.Repository.PullRequest.Reviews
+		for _, r := range conn.Nodes {
+			reviews = append(reviews, Review{
+				ID:          r.ID,
+				Author:      r.Author.Login,
+				State:       r.State,
+				Body:        r.Body,
+				CommitSHA:   r.Commit.OID,
+				SubmittedAt: r.SubmittedAt,
+			})
+		}
+		if !conn.PageInfo.HasNextPage {
+			return reviews, nil
+		}
+		vars["cursor"] = githubv4.NewString(conn.PageInfo.EndCursor)
+	}
+}
+
+// ListReviewThreads returns all review threads of a pull request. For each
+// thread, it returns at most the first 100 comments.
+func ListReviewThreads(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) ([]ReviewThread, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					PageInfo pageInfo
+					Nodes    []struct {
+						ID         string
+						Path       string
+						Line       int
+						IsResolved bool
+						IsOutdated bool
+						Comments   struct {
+							Nodes []struct {
+								ID        string
+								Author    actor
+								Body      string
+								CreatedAt time.Time
+							}
+						} `graphql:"comments(first: $limit)"`
+					}
+				} `graphql:"reviewThreads(first: $limit, after: $cursor)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	var threads []ReviewThread
+	vars := baseVars(owner, name, number)
+	for {
+		if err := v4client.Query(ctx, &q, vars); err != nil {
+			return nil, errors.Wrap(err, "failed to list pull request review threads")
+		}
+
+		conn := q.Repository.PullRequest.ReviewThreads
+		for _, t := range conn.Nodes {
+			thread := ReviewThread{
+				ID:         t.ID,
+				Path:       t.Path,
+				Line:       t.Line,
+				IsResolved: t.IsResolved,
+				IsOutdated: t.IsOutdated,
+			}
+			for _, c := range t.Comments.Nodes {
+				thread.Comments = append(thread.Comments, ReviewComment{
+					ID:        c.ID,
+					Author:    c.Author.Login,
+					Body:      c.Body,
+					CreatedAt: c.CreatedAt,
+				})
+			}
+			threads = append(threads, thread)
+		}
+		if !conn.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		vars["cursor"] = githubv4.NewString(conn.PageInfo.EndCursor)
+	}
+}
+
+// GetStatusRollup returns the combined status of the head commit of a pull
+// request, including all check runs and commit statuses.
+func GetStatusRollup(ctx context.Context, v4client *githubv4.Client, owner, name string, number int) (*StatusRollup, error) {
+	var q struct {
+		Repository struct {
+			PullRequest struct {
+				Commits struct {
+					Nodes []struct {
+						Commit struct {
+							OID               string
+							StatusCheckRollup *struct {
+								State    githubv4.StatusState
+								Contexts struct {
+									PageInfo pageInfo
+									Nodes    []struct {
+										Typename string `graphql:"__typename"`
+										CheckRun struct {
+											Name       string
+											Status     githubv4.CheckStatusState
+											Conclusion githubv4.CheckConclusionState
+											DetailsURL string `graphql:"detailsUrl"`
+										} `graphql:"... on CheckRun"`
+										StatusContext struct {
+											Context   string
+											State     githubv4.StatusState
+											TargetURL string `graphql:"targetUrl"`
+										} `graphql:"... on StatusContext"`
+									}
+								} `graphql:"contexts(first: $limit, after: $cursor)"`
+							}
+						}
+					}
+				} `graphql:"commits(last: 1)"`
+			} `graphql:"pullRequest(number: $number)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+
+	rollup := &StatusRollup{}
+	vars := baseVars(owner, name, number)
+	for {
+		if err := v4client.Query(ctx, &q, vars); err != nil {
+			return nil, errors.Wrap(err, "failed to get pull request status rollup")
+		}
+
+		commits := q.Repository.PullRequest.Commits.Nodes
+		if len(commits) == 0 {
+			return rollup, nil
+		}
+
+		commit := commits[0].Commit
+		rollup.CommitSHA = commit.OID
+		if commit.StatusCheckRollup == nil {
+			return rollup, nil
+		}
+		rollup.State = commit.StatusCheckRollup.State
+
+		conn := commit.StatusCheckRollup.Contexts
+		for _, n := range conn.Nodes {
+			switch n.Typename {
+			case "CheckRun":
+				rollup.Contexts = append(rollup.Contexts, StatusContext{
+					Name:       n.CheckRun.Name,
+					IsCheckRun: true,
+					Status:     n.CheckRun.Status,
+					Conclusion: n.CheckRun.Conclusion,
+					URL:        n.CheckRun.DetailsURL,
+				})
+			case "StatusContext":
+				rollup.Contexts = append(rollup.Contexts, StatusContext{
+					Name:  n.StatusContext.Context,
+					State: n.StatusContext.State,
+					URL:   n.StatusContext.TargetURL,
+				})
+			}
+		}
+		if !conn.PageInfo.HasNextPage {
+			return rollup, nil
+		}
+		vars["cursor"] = githubv4.NewString(conn.PageInfo.EndCursor)
+	}
+}
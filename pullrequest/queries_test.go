@@ -0,0 +1,76 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/palantir/go-githubapp/githubapptest"
+)
+
+func TestListFiles(t *testing.T) {
+	pages := map[string]string{
+		"": `{"data":{"repository":{"pullRequest":{"files":{
+			"pageInfo":{"endCursor":"page2","hasNextPage":true},
+			"nodes":[{"path":"README.md","additions":1,"deletions":0,"changeType":"MODIFIED"}]}}}}}`,
+		"page2": `{"data":{"repository":{"pullRequest":{"files":{
+			"pageInfo":{"endCursor":"","hasNextPage":false},
+			"nodes":[{"path":"main.go","additions":0,"deletions":10,"changeType":"DELETED"}]}}}}}`,
+	}
+
+	cc := githubapptest.NewFakeClientCreator(map[string]http.Handler{
+		"POST /graphql": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Variables struct {
+					Cursor *string `json:"cursor"`
+				} `json:"variables"`
+			}
+			b, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(b, &req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			cursor := ""
+			if req.Variables.Cursor != nil {
+				cursor = *req.Variables.Cursor
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, pages[cursor])
+		}),
+	})
+	defer cc.Close()
+
+	client, err := cc.NewInstallationV4Client(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %v", err)
+	}
+
+	files, err := ListFiles(context.Background(), client, "test", "repo", 1)
+	if err != nil {
+		t.Fatalf("unexpected error listing files: %v", err)
+	}
+
+	if len(files) != 2 {
+		t.Fatalf("incorrect number of files: expected 2, actual %d", len(files))
+	}
+	if files[0].Path != "README.md" || files[1].Path != "main.go" || files[1].Deletions != 10 {
+		t.Errorf("incorrect files: %+v", files)
+	}
+}